@@ -8,7 +8,7 @@ param(
 )
 
 $BinaryName = "gem-server"
-$CmdPath = "cmd/server/main.go"
+$CmdPath = "./cmd/server"
 $BuildDir = "bin"
 $CoverageDir = "coverage"
 
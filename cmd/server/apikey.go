@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/persistence/mysql"
+)
+
+// runAPIKeyCommand apikeyサブコマンド
+// 管理APIキーを発行・失効させる。キーの値は発行時に一度だけ表示し、ハッシュのみを保存する
+//
+//	gem-server apikey create -name game-server -scopes currency:grant,currency:consume
+//	gem-server apikey create -name game-server -scopes currency:grant -file   # ADMIN_API_KEYS_FILEに追記するエントリを出力
+//	gem-server apikey revoke -name game-server
+func runAPIKeyCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: apikey create|revoke [flags]")
+	}
+
+	switch args[0] {
+	case "create":
+		return runAPIKeyCreate(args[1:])
+	case "revoke":
+		return runAPIKeyRevoke(args[1:])
+	default:
+		return fmt.Errorf("unknown apikey command: %s", args[0])
+	}
+}
+
+// runAPIKeyCreate 管理APIキーを発行する
+func runAPIKeyCreate(args []string) error {
+	fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	name := fs.String("name", "", "キーの名前（トランザクションのリクエスト元として記録される、必須）")
+	scopesStr := fs.String("scopes", "", "許可するスコープ（カンマ区切り、*はすべての操作）")
+	toFile := fs.Bool("file", false, "MySQLに保存せず、ADMIN_API_KEYS_FILEに記載するエントリを出力する")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	scopes, err := api_key.ParseScopes(*scopesStr)
+	if err != nil {
+		return err
+	}
+	if len(scopes) == 0 {
+		return errors.New("-scopes is required")
+	}
+	value, err := api_key.GenerateKeyValue()
+	if err != nil {
+		return err
+	}
+	key, err := api_key.NewAPIKey(*name, api_key.HashKey(value), scopes)
+	if err != nil {
+		return err
+	}
+
+	if *toFile {
+		scopeValues := make([]string, len(key.Scopes()))
+		for i, scope := range key.Scopes() {
+			scopeValues[i] = scope.String()
+		}
+		entry, err := json.Marshal(struct {
+			Name    string   `json:"name"`
+			KeyHash string   `json:"key_hash"`
+			Scopes  []string `json:"scopes"`
+		}{key.Name(), key.KeyHash(), scopeValues})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "add the following entry to ADMIN_API_KEYS_FILE:\n%s\n", entry)
+	} else {
+		repo, closeDB, err := openAPIKeyRepository()
+		if err != nil {
+			return err
+		}
+		defer closeDB()
+
+		if err := repo.Save(context.Background(), key); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "created api key %s (%s)\n", key.Name(), api_key.FormatScopes(key.Scopes()))
+	}
+
+	// キーの値は標準出力に一度だけ出力する（再表示はできない）
+	fmt.Println(value)
+	return nil
+}
+
+// runAPIKeyRevoke MySQLに保存した管理APIキーを失効させる
+func runAPIKeyRevoke(args []string) error {
+	fs := flag.NewFlagSet("apikey revoke", flag.ContinueOnError)
+	name := fs.String("name", "", "失効させるキーの名前（必須）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	repo, closeDB, err := openAPIKeyRepository()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	if err := repo.Revoke(context.Background(), *name, time.Now()); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "revoked api key %s\n", *name)
+	return nil
+}
+
+// openAPIKeyRepository 設定を読み込み、MySQLの管理APIキーリポジトリを作成する
+func openAPIKeyRepository() (*mysql.APIKeyRepository, func(), error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	db, err := mysql.NewDB(&cfg.Database)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return mysql.NewAPIKeyRepository(db), func() { db.Close() }, nil
+}
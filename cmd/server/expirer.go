@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redemptionapp "gem-server/internal/application/code_redemption"
+	currencyapp "gem-server/internal/application/currency"
+	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/redemption_code"
+	"gem-server/internal/infrastructure/config"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+)
+
+// runCurrencyExpirer 有効期限切れの通貨ロットを定期的に失効させる
+// 他のインスタンスが実行中の回はスキップする。ctxがキャンセルされるまでブロックする
+func runCurrencyExpirer(ctx context.Context, cfg *config.CurrencyExpiryConfig, currencyAppService *currencyapp.CurrencyApplicationService, logger *otelinfra.Logger) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "Currency expirer started", map[string]interface{}{
+		"interval":   cfg.Interval.String(),
+		"batch_size": cfg.BatchSize,
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "Currency expirer stopped", nil)
+			return
+		case <-ticker.C:
+			// エラーはExpireLots内でログ出力済みのため、次回の実行で再試行する
+			_, err := currencyAppService.ExpireLots(ctx, &currencyapp.ExpireLotsRequest{
+				Limit: cfg.BatchSize,
+			})
+			if errors.Is(err, currency.ErrExpirySweepInProgress) {
+				logger.Debug(ctx, "Lot expiry sweep is running on another instance", nil)
+			}
+		}
+	}
+}
+
+// runCodeExpirer 有効期限を過ぎた引き換えコードを定期的に失効させる
+// 他のインスタンスが実行中の回はスキップする。ctxがキャンセルされるまでブロックする
+func runCodeExpirer(ctx context.Context, cfg *config.CodeExpiryConfig, redemptionAppService *redemptionapp.CodeRedemptionApplicationService, logger *otelinfra.Logger) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	logger.Info(ctx, "Code expirer started", map[string]interface{}{
+		"interval":   cfg.Interval.String(),
+		"batch_size": cfg.BatchSize,
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "Code expirer stopped", nil)
+			return
+		case <-ticker.C:
+			// エラーはExpireCodes内でログ出力済みのため、次回の実行で再試行する
+			_, err := redemptionAppService.ExpireCodes(ctx, &redemptionapp.ExpireCodesRequest{
+				Limit: cfg.BatchSize,
+			})
+			if errors.Is(err, redemption_code.ErrExpirySweepInProgress) {
+				logger.Debug(ctx, "Code expiry sweep is running on another instance", nil)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	historyapp "gem-server/internal/application/history"
+	"gem-server/internal/infrastructure/config"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/persistence/mysql"
+)
+
+// runExportCommand exportサブコマンド
+// トランザクションを期間・通貨タイプ・トランザクションタイプで絞り込み、CSVまたはNDJSONで出力する
+//
+//	gem-server export -from 2025-01-01T00:00:00Z -to 2025-02-01T00:00:00Z -currency-type paid -output paid-2025-01.csv
+func runExportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "csv", "出力形式（csv/ndjson）")
+	fromStr := fs.String("from", "", "この日時以降に作成されたものを出力（RFC3339、必須）")
+	toStr := fs.String("to", "", "この日時より前に作成されたものを出力（RFC3339、必須）")
+	currencyType := fs.String("currency-type", "", "通貨タイプでフィルタ（paid/free）")
+	transactionType := fs.String("transaction-type", "", "トランザクションタイプでフィルタ")
+	output := fs.String("output", "", "出力先ファイル（未指定の場合は標準出力）")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	exportFormat, err := historyapp.ParseExportFormat(*format)
+	if err != nil {
+		return err
+	}
+	if *fromStr == "" || *toStr == "" {
+		return errors.New("-from and -to are required")
+	}
+	from, err := time.Parse(time.RFC3339, *fromStr)
+	if err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	to, err := time.Parse(time.RFC3339, *toStr)
+	if err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// ログは標準エラー出力に書き込まれるため、標準出力へのエクスポートと混ざらない
+	logger := otelinfra.NewLogger(otelinfra.Tracer("gem-server"))
+	metrics, err := otelinfra.NewMetrics("gem-server")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics: %w", err)
+	}
+
+	db, err := mysql.NewDB(&cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	historyAppService := historyapp.NewHistoryApplicationService(
+		mysql.NewTransactionRepository(db),
+		logger,
+		metrics,
+	)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	resp, err := historyAppService.ExportTransactions(ctx, &historyapp.ExportTransactionsRequest{
+		Format:          exportFormat,
+		CurrencyType:    *currencyType,
+		TransactionType: *transactionType,
+		From:            &from,
+		To:              &to,
+	}, w)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d transactions\n", resp.Count)
+	return nil
+}
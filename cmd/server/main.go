@@ -15,12 +15,23 @@ import (
 	currencyapp "gem-server/internal/application/currency"
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/service"
+	"gem-server/internal/infrastructure/apikey"
+	"gem-server/internal/infrastructure/cache"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/denylist"
+	"gem-server/internal/infrastructure/jwtkeys"
+	"gem-server/internal/infrastructure/lockout"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/oidc"
 	"gem-server/internal/infrastructure/persistence/mysql"
+	"gem-server/internal/infrastructure/ratelimit"
 	grpcserver "gem-server/internal/presentation/grpc"
 	"gem-server/internal/presentation/rest"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // @title Gem Server API
@@ -34,6 +45,20 @@ import (
 // @name Authorization
 // @description JWT認証トークン。形式: "Bearer {token}"
 func main() {
+	// サブコマンド
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExportCommand(os.Args[2:]); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "apikey" {
+		if err := runAPIKeyCommand(os.Args[2:]); err != nil {
+			log.Fatalf("API key command failed: %v", err)
+		}
+		return
+	}
+
 	// 設定の読み込み
 	cfg, err := config.Load()
 	if err != nil {
@@ -80,26 +105,86 @@ func main() {
 	}
 	defer db.Close()
 
+	// Redis接続の初期化（有効な場合のみ）
+	var redisClient *redis.Client
+	if cfg.Redis.Enabled {
+		redisClient, err = cache.NewRedisClient(&cfg.Redis)
+		if err != nil {
+			log.Fatalf("Failed to connect to redis: %v", err)
+		}
+		defer redisClient.Close()
+	}
+
 	// リポジトリの初期化
-	currencyRepo := mysql.NewCurrencyRepository(db)
+	var currencyRepo currency.CurrencyRepository = mysql.NewCurrencyRepository(db)
+	if redisClient != nil {
+		currencyRepo = cache.NewCurrencyRepository(currencyRepo, redisClient, cfg.Redis.CacheTTL)
+	}
 	transactionRepo := mysql.NewTransactionRepository(db)
+	lotRepo := mysql.NewLotRepository(db)
 	paymentRequestRepo := mysql.NewPaymentRequestRepository(db)
 	redemptionCodeRepo := mysql.NewRedemptionCodeRepository(db)
+	refreshTokenRepo := mysql.NewRefreshTokenRepository(db)
+	apiKeyRepo := mysql.NewAPIKeyRepository(db)
 
 	// トランザクションマネージャーの初期化
 	txManager := mysql.NewTransactionManager(db)
 
 	// ドメインサービスの初期化
 	currencyService := service.NewCurrencyService(currencyRepo)
+	idGenerator := idgen.NewUUIDv7Generator()
+	transferPolicy, err := currency.NewTransferPolicyFromStrings(cfg.CurrencyTransfer.TransferableTypes)
+	if err != nil {
+		log.Fatalf("Invalid CURRENCY_TRANSFERABLE_TYPES: %v", err)
+	}
+
+	// 引き換え失敗によるロックアウト（Redisが有効な場合はインスタンス間で共有する）
+	failureTracker := lockout.NewFailureTracker(&cfg.RedemptionLockout, redisClient)
+
+	// 期限切れコードの一括失効は複数インスタンスのうち1つだけが実行する
+	codeExpiryLock := mysql.NewAdvisoryLock(db, "gem-server:redemption_code_expiry")
+
+	// 有効期限切れロットの一括失効も同様に1つのインスタンスだけが実行する
+	currencyExpiryLock := mysql.NewAdvisoryLock(db, "gem-server:currency_lot_expiry")
+
+	// 失効させたアクセストークン（Redisが有効な場合はインスタンス間で共有する）
+	tokenDenylist := denylist.NewDenylist(redisClient)
+
+	// アクセストークンの署名鍵（署名鍵が未設定の場合はJWT_SECRETによるHS256）
+	keyring, err := jwtkeys.NewKeyring(&cfg.JWT)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// 外部のOIDCプロバイダーが発行したIDトークンの検証（無効な場合はnil）
+	oidcVerifier := oidc.NewVerifier(&cfg.OIDC)
+
+	// 管理APIキー（ADMIN_API_KEY、定義ファイル、MySQLに登録したキー）
+	apiKeyAuthenticator, err := apikey.NewAuthenticator(&cfg.AdminAPI, apiKeyRepo)
+	if err != nil {
+		log.Fatalf("Failed to load admin API keys: %v", err)
+	}
 
 	// アプリケーションサービスの初期化
-	authAppService := authapp.NewAuthApplicationService(&cfg.JWT, logger)
+	authAppService := authapp.NewAuthApplicationService(
+		&cfg.JWT,
+		keyring,
+		refreshTokenRepo,
+		txManager,
+		tokenDenylist,
+		idGenerator,
+		logger,
+	)
 
 	currencyAppService := currencyapp.NewCurrencyApplicationService(
 		currencyRepo,
 		transactionRepo,
+		lotRepo,
 		txManager,
+		idGenerator,
+		transferPolicy,
 		currencyService,
+		currencyExpiryLock,
 		logger,
 		metrics,
 	)
@@ -107,8 +192,10 @@ func main() {
 	paymentAppService := paymentapp.NewPaymentApplicationService(
 		currencyRepo,
 		transactionRepo,
+		lotRepo,
 		paymentRequestRepo,
 		txManager,
+		idGenerator,
 		logger,
 		metrics,
 	)
@@ -118,6 +205,9 @@ func main() {
 		transactionRepo,
 		redemptionCodeRepo,
 		txManager,
+		idGenerator,
+		failureTracker,
+		codeExpiryLock,
 		logger,
 		metrics,
 	)
@@ -128,11 +218,19 @@ func main() {
 		metrics,
 	)
 
+	// レート制限の初期化（Redisが有効な場合はインスタンス間で共有する）
+	limiters := ratelimit.NewLimiters(&cfg.RateLimit, redisClient)
+
 	// REST APIルーターの初期化
 	router, err := rest.NewRouter(
 		cfg,
 		logger,
 		metrics,
+		limiters,
+		keyring,
+		oidcVerifier,
+		tokenDenylist,
+		apiKeyAuthenticator,
 		authAppService,
 		currencyAppService,
 		paymentAppService,
@@ -147,6 +245,8 @@ func main() {
 	grpcSrv, err := grpcserver.NewServer(
 		cfg,
 		logger,
+		limiters,
+		apiKeyAuthenticator,
 		currencyAppService,
 		paymentAppService,
 		redemptionAppService,
@@ -159,6 +259,34 @@ func main() {
 	// サーバーアドレスの設定
 	address := fmt.Sprintf(":%d", cfg.Server.Port)
 
+	// 有効期限付き通貨の失効ジョブを起動
+	expirerCtx, stopExpirer := context.WithCancel(context.Background())
+	expirerDone := make(chan struct{})
+	go func() {
+		defer close(expirerDone)
+		if cfg.CurrencyExpiry.Enabled {
+			runCurrencyExpirer(expirerCtx, &cfg.CurrencyExpiry, currencyAppService, logger)
+		}
+	}()
+
+	// 有効期限切れの引き換えコードの失効ジョブを起動
+	codeExpirerDone := make(chan struct{})
+	go func() {
+		defer close(codeExpirerDone)
+		if cfg.CodeExpiry.Enabled {
+			runCodeExpirer(expirerCtx, &cfg.CodeExpiry, redemptionAppService, logger)
+		}
+	}()
+
+	// JWTの署名鍵の再読み込みジョブを起動
+	keyReloaderDone := make(chan struct{})
+	go func() {
+		defer close(keyReloaderDone)
+		if keyring.UsesSigningKeys() && cfg.JWT.KeyReloadInterval > 0 {
+			runSigningKeyReloader(expirerCtx, keyring, cfg.JWT.KeyReloadInterval, logger)
+		}
+	}()
+
 	// グレースフルシャットダウンの設定
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -183,6 +311,12 @@ func main() {
 	<-quit
 	log.Println("Shutting down servers...")
 
+	// 失効ジョブと署名鍵の再読み込みジョブの停止
+	stopExpirer()
+	<-expirerDone
+	<-codeExpirerDone
+	<-keyReloaderDone
+
 	// グレースフルシャットダウン
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
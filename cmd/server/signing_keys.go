@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"gem-server/internal/infrastructure/jwtkeys"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+)
+
+// runSigningKeyReloader JWTの署名鍵を定期的に読み込み直す
+// 鍵ファイルの追加・削除によるローテーションを再起動せずに反映する。ctxがキャンセルされるまでブロックする
+func runSigningKeyReloader(ctx context.Context, keyring *jwtkeys.Keyring, interval time.Duration, logger *otelinfra.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	signingKeyID := keyring.SigningKeyID()
+	logger.Info(ctx, "Signing key reloader started", map[string]interface{}{
+		"interval": interval.String(),
+		"kid":      signingKeyID,
+	})
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info(context.Background(), "Signing key reloader stopped", nil)
+			return
+		case <-ticker.C:
+			// 読み込みに失敗した場合は現在の鍵を使い続け、次回の実行で再試行する
+			if err := keyring.Reload(); err != nil {
+				logger.Error(ctx, "Failed to reload signing keys", err, nil)
+			}
+			// 鍵の追加から使用開始までの待機時間が経過すると、再読み込みしなくても署名鍵が切り替わる
+			if kid := keyring.SigningKeyID(); kid != signingKeyID {
+				logger.Info(ctx, "Signing key rotated", map[string]interface{}{
+					"previous_kid": signingKeyID,
+					"kid":          kid,
+				})
+				signingKeyID = kid
+			}
+		}
+	}
+}
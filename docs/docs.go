@@ -15,9 +15,135 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/code_batches": {
+            "post": {
+                "description": "パターンからランダムな引き換えコードを一括生成します。生成したコードは同じ設定とバッチIDを持ちます。alphabetを省略した場合は読み間違えやすい文字（0/O、1/I/L）を除いた英大文字と数字を使用します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "引き換えコードを一括生成（管理API）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "引き換えコード一括生成リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GenerateCodesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "引き換えコード一括生成成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GenerateCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "バッチIDが使用済み",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/code_batches/{batch_id}/export": {
+            "get": {
+                "description": "バッチIDで一括生成した引き換えコードを、使用回数とステータスを含めてCSVで出力します",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "一括生成した引き換えコードをCSVでダウンロード（管理API）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "influencer_2024_spring",
+                        "description": "バッチID",
+                        "name": "batch_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "エクスポート成功",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "バッチが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/codes": {
             "get": {
-                "description": "引き換えコードの一覧を取得します（ページネーション・フィルタリング対応）",
+                "description": "引き換えコードの一覧を取得します（ページネーション・フィルタリング対応）。totalは検索条件に一致する総件数です",
                 "consumes": [
                     "application/json"
                 ],
@@ -69,6 +195,59 @@ const docTemplate = `{
                         "name": "code_type",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "paid",
+                            "free"
+                        ],
+                        "type": "string",
+                        "example": "free",
+                        "description": "通貨タイプフィルタ（報酬にこの通貨を含むコード）",
+                        "name": "currency_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "spring_2024",
+                        "description": "キャンペーンIDフィルタ（campaign_idまたはmetadataのcampaign_id）",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "INF-",
+                        "description": "コードの前方一致検索",
+                        "name": "code_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-04-01T00:00:00Z",
+                        "description": "有効期間がこの日時以降と重なるコード（RFC3339）",
+                        "name": "valid_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2024-04-30T23:59:59Z",
+                        "description": "有効期間がこの日時以前と重なるコード（RFC3339）",
+                        "name": "valid_until",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at_desc",
+                            "created_at_asc",
+                            "code_asc",
+                            "valid_until_asc",
+                            "valid_until_desc"
+                        ],
+                        "type": "string",
+                        "default": "created_at_desc",
+                        "description": "並び順",
+                        "name": "sort",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "APIキー",
@@ -95,6 +274,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             },
@@ -147,6 +332,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "409": {
                         "description": "コードが既に存在",
                         "schema": {
@@ -156,9 +347,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/codes/{code}": {
-            "get": {
-                "description": "指定された引き換えコードの詳細を取得します",
+        "/admin/codes/expire_sweep": {
+            "post": {
+                "description": "有効期限を過ぎたactiveなコードをexpiredに変更します。定期ジョブと同じ処理を即時に実行し、失効は管理APIキーの名前とともに変更履歴に記録されます。リクエストボディは省略できます",
                 "consumes": [
                     "application/json"
                 ],
@@ -168,29 +359,29 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "引き換えコードを取得（管理API）",
+                "summary": "期限切れコードを一括失効（管理API）",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "example": "PROMO2024",
-                        "description": "引き換えコード",
-                        "name": "code",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "APIキー",
                         "name": "X-API-Key",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "description": "期限切れコードの一括失効リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ExpireCodesRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "引き換えコード取得成功",
+                        "description": "一括失効成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.GetCodeResponse"
+                            "$ref": "#/definitions/handler.ExpireCodesResponse"
                         }
                     },
                     "400": {
@@ -205,16 +396,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "コードが見つからない",
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "他のインスタンスで一括失効を実行中",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "引き換えコードを削除します（使用済みコードは削除不可）",
+            }
+        },
+        "/admin/codes/{code}": {
+            "get": {
+                "description": "指定された引き換えコードの詳細を取得します",
                 "consumes": [
                     "application/json"
                 ],
@@ -224,7 +423,7 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "引き換えコードを削除（管理API）",
+                "summary": "引き換えコードを取得（管理API）",
                 "parameters": [
                     {
                         "type": "string",
@@ -244,9 +443,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "引き換えコード削除成功",
+                        "description": "引き換えコード取得成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.DeleteCodeResponse"
+                            "$ref": "#/definitions/handler.GetCodeResponse"
                         }
                     },
                     "400": {
@@ -261,24 +460,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "コードが見つからない",
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "コードが使用済みのため削除不可",
+                    "404": {
+                        "description": "コードが見つからない",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/admin/users/{user_id}/balance": {
-            "get": {
-                "description": "指定されたユーザーの通貨残高を取得します",
+            },
+            "delete": {
+                "description": "引き換えコードを削除します（使用済みコードは削除不可）",
                 "consumes": [
                     "application/json"
                 ],
@@ -288,13 +485,13 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "残高を取得（管理API）",
+                "summary": "引き換えコードを削除（管理API）",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "user123",
-                        "description": "ユーザーID",
-                        "name": "user_id",
+                        "example": "PROMO2024",
+                        "description": "引き換えコード",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
@@ -308,9 +505,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "残高取得成功",
+                        "description": "引き換えコード削除成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.BalanceResponse"
+                            "$ref": "#/definitions/handler.DeleteCodeResponse"
                         }
                     },
                     "400": {
@@ -324,13 +521,29 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "コードが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "コードが使用済みのため削除不可",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/admin/users/{user_id}/consume": {
-            "post": {
-                "description": "指定されたユーザーの通貨を消費します。優先順位制御も可能です",
+            },
+            "patch": {
+                "description": "引き換えコードの無効化・再有効化、有効期限の延長、最大使用回数の引き上げを行います。変更内容は管理APIキーの名前とともに変更履歴に記録されます",
                 "consumes": [
                     "application/json"
                 ],
@@ -340,13 +553,13 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "通貨を消費（管理API）",
+                "summary": "引き換えコードを更新（管理API）",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "user123",
-                        "description": "ユーザーID",
-                        "name": "user_id",
+                        "example": "PROMO2024",
+                        "description": "引き換えコード",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
@@ -358,24 +571,24 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "通貨消費リクエスト",
+                        "description": "引き換えコード更新リクエスト",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ConsumeRequest"
+                            "$ref": "#/definitions/handler.UpdateCodeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "通貨消費成功",
+                        "description": "引き換えコード更新成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.ConsumeResponse"
+                            "$ref": "#/definitions/handler.UpdateCodeResponse"
                         }
                     },
                     "400": {
-                        "description": "不正なリクエスト",
+                        "description": "不正なリクエスト、または許可されていない変更",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -386,18 +599,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "残高不足",
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
-                    }
-                }
-            }
+                    },
+                    "404": {
+                        "description": "コードが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
         },
-        "/admin/users/{user_id}/grant": {
-            "post": {
-                "description": "指定されたユーザーに無償通貨を付与します",
+        "/admin/codes/{code}/redemptions": {
+            "get": {
+                "description": "指定された引き換えコードを引き換えたユーザーと日時、付与トランザクションを新しい順に取得します",
                 "consumes": [
                     "application/json"
                 ],
@@ -407,38 +626,45 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "通貨を付与（管理API）",
+                "summary": "引き換えコードの引き換え履歴を取得（管理API）",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "user123",
-                        "description": "ユーザーID",
-                        "name": "user_id",
+                        "example": "PROMO2024",
+                        "description": "引き換えコード",
+                        "name": "code",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "example": 50,
+                        "description": "取得件数（最大: 100）",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "example": 0,
+                        "description": "オフセット",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "APIキー",
                         "name": "X-API-Key",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "通貨付与リクエスト",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.GrantRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "通貨付与成功",
+                        "description": "引き換え履歴取得成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.GrantResponse"
+                            "$ref": "#/definitions/handler.ListRedemptionsResponse"
                         }
                     },
                     "400": {
@@ -452,13 +678,25 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "コードが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/admin/users/{user_id}/issue_token": {
-            "post": {
-                "description": "ユーザーIDを元にJWT認証トークンを生成します",
+        "/admin/codes/{code}/stats": {
+            "get": {
+                "description": "指定された引き換えコードの引き換え回数・ユーザー数・付与した通貨の合計・日ごとの引き換え回数を取得します",
                 "consumes": [
                     "application/json"
                 ],
@@ -468,21 +706,29 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "認証トークンを生成",
+                "summary": "引き換えコードの利用統計を取得（管理API）",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ユーザーID",
-                        "name": "user_id",
+                        "example": "PROMO2024",
+                        "description": "引き換えコード",
+                        "name": "code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "トークン生成成功",
+                        "description": "利用統計取得成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.GenerateTokenResponse"
+                            "$ref": "#/definitions/handler.CodeStatsResponse"
                         }
                     },
                     "400": {
@@ -490,13 +736,31 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "コードが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/admin/users/{user_id}/transactions": {
-            "get": {
-                "description": "指定されたユーザーのトランザクション履歴を取得します。ページネーションとフィルタリングに対応しています",
+        "/admin/tokens/revoke": {
+            "post": {
+                "description": "アクセストークンまたはリフレッシュトークンを失効させます。アクセストークンは有効期限まで拒否され、リフレッシュトークンは同じ系列のリフレッシュトークンがすべて失効します",
                 "consumes": [
                     "application/json"
                 ],
@@ -506,16 +770,65 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "トランザクション履歴を取得（管理API）",
+                "summary": "認証トークンを失効（管理API）",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "user123",
-                        "description": "ユーザーID",
-                        "name": "user_id",
-                        "in": "path",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
                         "required": true
                     },
+                    {
+                        "description": "トークン失効リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RevokeTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "トークン失効成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RevokeTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト、またはトークンが不正",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/transactions/export": {
+            "get": {
+                "description": "全ユーザーのトランザクションを期間・通貨タイプ・トランザクションタイプで絞り込み、CSVまたはNDJSONでストリーミング出力します。メタデータは\"親.子\"形式のキーに展開されます",
+                "produces": [
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "トランザクションをエクスポート（管理API）",
+                "parameters": [
                     {
                         "type": "string",
                         "description": "APIキー",
@@ -524,20 +837,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 50,
-                        "example": 50,
-                        "description": "取得件数（デフォルト: 50, 最大: 100)",
-                        "name": "limit",
+                        "type": "string",
+                        "example": "csv",
+                        "description": "出力形式（csv/ndjson、デフォルト: csv）",
+                        "name": "format",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "example": 0,
-                        "description": "オフセット（デフォルト: 0)",
-                        "name": "offset",
-                        "in": "query"
+                        "type": "string",
+                        "example": "2025-01-01T00:00:00Z",
+                        "description": "この日時以降に作成されたものを出力（RFC3339）",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-02-01T00:00:00Z",
+                        "description": "この日時より前に作成されたものを出力（RFC3339）",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -549,16 +869,16 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "consume",
-                        "description": "トランザクションタイプでフィルタ（grant/consume/payment/redemption）",
+                        "description": "トランザクションタイプでフィルタ（grant/consume/refund/expire/compensate/transfer_out/transfer_in）",
                         "name": "transaction_type",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "履歴取得成功",
+                        "description": "エクスポート成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.TransactionHistoryResponse"
+                            "type": "string"
                         }
                     },
                     "400": {
@@ -572,18 +892,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/codes/redeem": {
+        "/admin/transactions/{transaction_id}/refund": {
             "post": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "引き換えコードを使用して通貨を付与します",
+                "description": "消費トランザクションの金額を元の通貨タイプに返金します。優先順位制御で分割された消費はベースIDを指定すると一括で返金されます",
                 "consumes": [
                     "application/json"
                 ],
@@ -591,25 +912,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "redemption"
+                    "admin"
                 ],
-                "summary": "コードを引き換え",
+                "summary": "消費トランザクションを返金（管理API）",
                 "parameters": [
                     {
-                        "description": "コード引き換えリクエスト",
+                        "type": "string",
+                        "example": "txn_456",
+                        "description": "返金対象のトランザクションID",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "返金リクエスト",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RedeemCodeRequest"
+                            "$ref": "#/definitions/handler.RefundRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "コード引き換え成功",
+                        "description": "返金成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.RedeemCodeResponse"
+                            "$ref": "#/definitions/handler.RefundResponse"
                         }
                     },
                     "400": {
@@ -618,14 +953,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "403": {
+                    "401": {
                         "description": "認証エラー",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "コードが見つからない",
+                        "description": "トランザクションが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "返金済み",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -633,14 +980,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/me/balance": {
+        "/admin/users/{user_id}/balance": {
             "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "自分の通貨残高を取得します",
+                "description": "指定されたユーザーの通貨残高を取得します",
                 "consumes": [
                     "application/json"
                 ],
@@ -648,9 +990,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "currency"
+                    "admin"
+                ],
+                "summary": "残高を取得（管理API）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "残高を取得",
                 "responses": {
                     "200": {
                         "description": "残高取得成功",
@@ -664,23 +1023,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "403": {
+                    "401": {
                         "description": "認証エラー",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/me/transactions": {
-            "get": {
-                "security": [
-                    {
-                        "Bearer": []
-                    }
-                ],
-                "description": "自分のトランザクション履歴を取得します。ページネーションとフィルタリングに対応しています",
+        "/admin/users/{user_id}/compensate": {
+            "post": {
+                "description": "符号付きの金額で残高を調整します。正の値で補填、負の値で回収し、回収時はマイナス残高を許可します。reasonは必須です",
                 "consumes": [
                     "application/json"
                 ],
@@ -688,46 +1048,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "history"
+                    "admin"
                 ],
-                "summary": "トランザクション履歴を取得",
+                "summary": "通貨を補填・回収（管理API）",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 50,
-                        "example": 50,
-                        "description": "取得件数（デフォルト: 50, 最大: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "example": 0,
-                        "description": "オフセット（デフォルト: 0)",
-                        "name": "offset",
-                        "in": "query"
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "example": "paid",
-                        "description": "通貨タイプでフィルタ（paid/free）",
-                        "name": "currency_type",
-                        "in": "query"
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "type": "string",
-                        "example": "consume",
-                        "description": "トランザクションタイプでフィルタ（grant/consume/payment/redemption）",
-                        "name": "transaction_type",
-                        "in": "query"
+                        "description": "補填リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CompensateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "履歴取得成功",
+                        "description": "調整成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.TransactionHistoryResponse"
+                            "$ref": "#/definitions/handler.CompensateResponse"
                         }
                     },
                     "400": {
@@ -741,18 +1095,98 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/payment/process": {
+        "/admin/users/{user_id}/consume": {
             "post": {
-                "security": [
+                "description": "指定されたユーザーの通貨を消費します。優先順位制御も可能です",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "通貨を消費（管理API）",
+                "parameters": [
                     {
-                        "Bearer": []
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "冪等性キー（同じキーでの再送は初回の結果を返す）",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "通貨消費リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConsumeRequest"
+                        }
                     }
                 ],
-                "description": "決済リクエストを処理し、通貨を消費します",
+                "responses": {
+                    "200": {
+                        "description": "通貨消費成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ConsumeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "残高不足、または冪等性キーが異なるリクエスト内容で使用済み",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/grant": {
+            "post": {
+                "description": "指定されたユーザーに通貨を付与します。expires_atを指定すると有効期限付きの無償通貨として付与します",
                 "consumes": [
                     "application/json"
                 ],
@@ -760,25 +1194,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "admin"
                 ],
-                "summary": "決済を処理",
+                "summary": "通貨を付与（管理API）",
                 "parameters": [
                     {
-                        "description": "決済処理リクエスト",
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "冪等性キー（同じキーでの再送は初回の結果を返す）",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "通貨付与リクエスト",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ProcessPaymentRequest"
+                            "$ref": "#/definitions/handler.GrantRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "決済処理成功",
+                        "description": "通貨付与成功",
                         "schema": {
-                            "$ref": "#/definitions/handler.ProcessPaymentResponse"
+                            "$ref": "#/definitions/handler.GrantResponse"
                         }
                     },
                     "400": {
@@ -787,58 +1242,1144 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "403": {
+                    "401": {
                         "description": "認証エラー",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "残高不足",
+                        "description": "冪等性キーが異なるリクエスト内容で使用済み",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/issue_token": {
+            "post": {
+                "description": "ユーザーIDを元にJWT認証トークン（アクセストークン）とリフレッシュトークンを生成します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "認証トークンを生成",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "トークン生成成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GenerateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.BalanceItem": {
-            "description": "残高アイテム",
+        },
+        "/admin/users/{user_id}/redemption_lockout": {
+            "delete": {
+                "description": "ユーザーの引き換え失敗の記録を消去し、ロックアウトを解除します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "引き換えロックアウトを解除（管理API）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ロックアウト解除成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ClearRedemptionLockoutResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/revoke_tokens": {
+            "post": {
+                "description": "ユーザーのリフレッシュトークンと、発行済みのアクセストークンをすべて失効させます",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "ユーザーの認証トークンを一括失効（管理API）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "トークン一括失効成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RevokeUserTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/transactions": {
+            "get": {
+                "description": "指定されたユーザーのトランザクション履歴を取得します。ページネーションとフィルタリングに対応しています",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "トランザクション履歴を取得（管理API）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "user123",
+                        "description": "ユーザーID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "APIキー",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "example": 50,
+                        "description": "取得件数（デフォルト: 50, 最大: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "example": 0,
+                        "description": "オフセット（デフォルト: 0)。cursor指定時は無視されます",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "前のレスポンスのnext_cursor。指定するとその続きから取得します",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "paid",
+                        "description": "通貨タイプでフィルタ（paid/free）",
+                        "name": "currency_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "consume",
+                        "description": "トランザクションタイプでフィルタ（grant/consume/refund/expire/compensate/transfer_out/transfer_in）",
+                        "name": "transaction_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "completed",
+                        "description": "ステータスでフィルタ（pending/completed/failed）",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "game-server",
+                        "description": "リクエスト元でフィルタ",
+                        "name": "requester",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01-01T00:00:00Z",
+                        "description": "この日時以降に作成されたものに絞り込み（RFC3339）",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-02-01T00:00:00Z",
+                        "description": "この日時より前に作成されたものに絞り込み（RFC3339）",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "履歴取得成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TransactionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "APIキーに必要なスコープがない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "リフレッシュトークンを使用して新しいアクセストークンとリフレッシュトークンを発行します。使用したリフレッシュトークンは無効になり、再度使用された場合は同じ系列のリフレッシュトークンがすべて失効します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "認証トークンを更新",
+                "parameters": [
+                    {
+                        "description": "トークン更新リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "トークン更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GenerateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "リフレッシュトークンが無効、期限切れ、または失効済み",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/codes/redeem": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "引き換えコードを使用して通貨を付与します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemption"
+                ],
+                "summary": "コードを引き換え",
+                "parameters": [
+                    {
+                        "description": "コード引き換えリクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RedeemCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "コード引き換え成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.RedeemCodeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "認証エラー、または引き換え条件を満たしていない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "コードが見つからない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "引き換えの失敗が多すぎるためロックアウト中",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/balance": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "自分の通貨残高と、有効期限付き無償通貨の失効予定を取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "残高を取得",
+                "responses": {
+                    "200": {
+                        "description": "残高取得成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.BalanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/redemptions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "自分がこれまでに引き換えたコードの履歴を新しい順に取得します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "redemption"
+                ],
+                "summary": "引き換え履歴を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "example": 50,
+                        "description": "取得件数（最大: 100）",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "example": 0,
+                        "description": "オフセット",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "引き換え履歴取得成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ListRedemptionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "自分のトランザクション履歴を取得します。ページネーションとフィルタリングに対応しています",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "history"
+                ],
+                "summary": "トランザクション履歴を取得",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "example": 50,
+                        "description": "取得件数（デフォルト: 50, 最大: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "example": 0,
+                        "description": "オフセット（デフォルト: 0)。cursor指定時は無視されます",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "前のレスポンスのnext_cursor。指定するとその続きから取得します",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "paid",
+                        "description": "通貨タイプでフィルタ（paid/free）",
+                        "name": "currency_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "consume",
+                        "description": "トランザクションタイプでフィルタ（grant/consume/refund/expire/compensate/transfer_out/transfer_in）",
+                        "name": "transaction_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "completed",
+                        "description": "ステータスでフィルタ（pending/completed/failed）",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "game-server",
+                        "description": "リクエスト元でフィルタ",
+                        "name": "requester",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-01-01T00:00:00Z",
+                        "description": "この日時以降に作成されたものに絞り込み（RFC3339）",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2025-02-01T00:00:00Z",
+                        "description": "この日時より前に作成されたものに絞り込み（RFC3339）",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "履歴取得成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TransactionHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/transfers": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "自分の通貨を他のユーザーに譲渡します。送信側の消費と受信側の付与は同一トランザクションで行われます。譲渡できる通貨タイプはサーバー設定で決まります（通常は無償通貨のみ）",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "currency"
+                ],
+                "summary": "通貨を他のユーザーに譲渡",
+                "parameters": [
+                    {
+                        "description": "通貨譲渡リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TransferRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "譲渡成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト、譲渡できない通貨タイプ、または自分自身への譲渡",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "譲渡先のユーザーが存在しない",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "残高不足",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment/process": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "決済リクエストを処理し、通貨を消費します",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "決済を処理",
+                "parameters": [
+                    {
+                        "description": "決済処理リクエスト",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProcessPaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "決済処理成功",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ProcessPaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "不正なリクエスト",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "認証エラー",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "残高不足",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.BalanceItem": {
+            "description": "残高アイテム",
+            "type": "object",
+            "properties": {
+                "free": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "paid": {
+                    "type": "string",
+                    "example": "1000"
+                }
+            }
+        },
+        "handler.BalanceResponse": {
+            "description": "残高レスポンス",
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "$ref": "#/definitions/handler.BalanceItem"
+                },
+                "expirations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ExpirationItem"
+                    }
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "handler.ClearRedemptionLockoutResponse": {
+            "description": "引き換えロックアウト解除レスポンス",
+            "type": "object",
+            "properties": {
+                "cleared_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "handler.CodeChangeItem": {
+            "description": "変更されたフィールドと変更前後の値",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "max_uses"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "200"
+                }
+            }
+        },
+        "handler.CodeItem": {
+            "description": "引き換えコードアイテム",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "1000"
+                },
+                "batch_id": {
+                    "type": "string",
+                    "example": "influencer_2024_spring"
+                },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "spring_2024"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "PROMO2024"
+                },
+                "code_type": {
+                    "type": "string",
+                    "example": "promotion"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "example": "free"
+                },
+                "current_uses": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "eligibility": {
+                    "$ref": "#/definitions/handler.EligibilityItem"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                }
+            }
+        },
+        "handler.CodeStatsResponse": {
+            "description": "引き換えコードの利用統計レスポンス",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "PROMO2024"
+                },
+                "current_uses": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "daily": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.DailyRedemptionItem"
+                    }
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "redemptions": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "total_granted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
+                },
+                "unique_users": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "handler.CompensateRequest": {
+            "description": "補填（調整）リクエスト。amountが正の値で付与、負の値で回収（マイナス残高を許可）",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "-100"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "free"
+                    ],
+                    "example": "paid"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "不正付与の回収"
+                }
+            }
+        },
+        "handler.CompensateResponse": {
+            "description": "補填（調整）レスポンス",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "-100"
+                },
+                "balance_after": {
+                    "type": "string",
+                    "example": "-50"
+                },
+                "balance_before": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "example": "paid"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "transaction_id": {
+                    "type": "string",
+                    "example": "txn_789"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
+                }
+            }
+        },
+        "handler.ConsumeRequest": {
+            "description": "通貨消費リクエスト",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "free",
+                        "auto"
+                    ],
+                    "example": "paid"
+                },
+                "item_id": {
+                    "type": "string",
+                    "example": "item001"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "use_priority": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handler.ConsumeResponse": {
+            "description": "通貨消費レスポンス",
+            "type": "object",
+            "properties": {
+                "balance_after": {
+                    "type": "string",
+                    "example": "950"
+                },
+                "consumption_details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ConsumptionDetail"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "total_consumed": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "transaction_id": {
+                    "type": "string",
+                    "example": "txn_456"
+                }
+            }
+        },
+        "handler.ConsumptionDetail": {
+            "description": "消費詳細",
             "type": "object",
             "properties": {
-                "free": {
+                "amount": {
                     "type": "string",
-                    "example": "500"
+                    "example": "50"
                 },
-                "paid": {
+                "balance_after": {
+                    "type": "string",
+                    "example": "950"
+                },
+                "balance_before": {
                     "type": "string",
                     "example": "1000"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "example": "paid"
                 }
             }
         },
-        "handler.BalanceResponse": {
-            "description": "残高レスポンス",
+        "handler.CreateCodeRequest": {
+            "description": "引き換えコード作成リクエスト（複数の通貨を付与する場合はcurrency_typeとamountの代わりにrewardsを指定）",
             "type": "object",
             "properties": {
-                "balances": {
-                    "$ref": "#/definitions/handler.BalanceItem"
+                "amount": {
+                    "type": "string",
+                    "example": "1000"
                 },
-                "user_id": {
+                "campaign_id": {
                     "type": "string",
-                    "example": "user123"
+                    "example": "spring_2024"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "PROMO2024"
+                },
+                "code_type": {
+                    "type": "string",
+                    "enum": [
+                        "promotion",
+                        "gift",
+                        "event"
+                    ],
+                    "example": "promotion"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "free"
+                    ],
+                    "example": "free"
+                },
+                "eligibility": {
+                    "$ref": "#/definitions/handler.EligibilityItem"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
                 }
             }
         },
-        "handler.CodeItem": {
-            "description": "引き換えコードアイテム",
+        "handler.CreateCodeResponse": {
+            "description": "引き換えコード作成レスポンス",
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "string",
                     "example": "1000"
                 },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "spring_2024"
+                },
                 "code": {
                     "type": "string",
                     "example": "PROMO2024"
@@ -859,6 +2400,9 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 0
                 },
+                "eligibility": {
+                    "$ref": "#/definitions/handler.EligibilityItem"
+                },
                 "max_uses": {
                     "type": "integer",
                     "example": 100
@@ -867,14 +2411,16 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": true
                 },
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
+                },
                 "status": {
                     "type": "string",
                     "example": "active"
                 },
-                "updated_at": {
-                    "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
                 "valid_from": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
@@ -885,102 +2431,143 @@ const docTemplate = `{
                 }
             }
         },
-        "handler.ConsumeRequest": {
-            "description": "通貨消費リクエスト",
+        "handler.DailyRedemptionItem": {
+            "description": "1日分の引き換え回数（引き換えのない日は含まない）",
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "string",
-                    "example": "50"
+                "count": {
+                    "type": "integer",
+                    "example": 12
                 },
-                "currency_type": {
+                "date": {
                     "type": "string",
-                    "enum": [
-                        "paid",
-                        "free",
-                        "auto"
-                    ],
-                    "example": "paid"
-                },
-                "item_id": {
+                    "example": "2024-04-01"
+                }
+            }
+        },
+        "handler.DeleteCodeResponse": {
+            "description": "引き換えコード削除レスポンス",
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string",
-                    "example": "item001"
-                },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": true
+                    "example": "PROMO2024"
                 },
-                "requester": {
+                "deleted_at": {
                     "type": "string",
-                    "example": "game-server-01"
-                },
-                "use_priority": {
-                    "type": "boolean",
-                    "example": false
+                    "example": "2024-01-01T00:00:00Z"
                 }
             }
         },
-        "handler.ConsumeResponse": {
-            "description": "通貨消費レスポンス",
+        "handler.EligibilityItem": {
+            "description": "引き換え条件（allowed_user_idsとallowed_user_id_prefixを両方指定した場合はどちらかに一致すれば引き換え可能、キャンペーン単位の条件はcampaign_idが必要）",
             "type": "object",
             "properties": {
-                "balance_after": {
+                "allowed_user_id_prefix": {
                     "type": "string",
-                    "example": "950"
+                    "example": "beta_"
                 },
-                "consumption_details": {
+                "allowed_user_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.ConsumptionDetail"
-                    }
+                        "type": "string"
+                    },
+                    "example": [
+                        "user123",
+                        "user456"
+                    ]
                 },
-                "status": {
-                    "type": "string",
-                    "example": "completed"
+                "exclusive_in_campaign": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "total_consumed": {
-                    "type": "string",
-                    "example": "50"
+                "max_per_user_in_campaign": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "transaction_id": {
+                "new_users_since": {
                     "type": "string",
-                    "example": "txn_456"
+                    "example": "2024-04-01T00:00:00Z"
                 }
             }
         },
-        "handler.ConsumptionDetail": {
-            "description": "消費詳細",
+        "handler.ErrorResponse": {
+            "description": "エラーレスポンス",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid request body"
+                }
+            }
+        },
+        "handler.ExpirationItem": {
+            "description": "失効予定の通貨（有効期限の近い順）",
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "string",
-                    "example": "50"
+                    "example": "100"
                 },
-                "balance_after": {
+                "currency_type": {
                     "type": "string",
-                    "example": "950"
+                    "example": "free"
                 },
-                "balance_before": {
+                "expires_at": {
                     "type": "string",
-                    "example": "1000"
+                    "example": "2026-12-31T23:59:59Z"
+                }
+            }
+        },
+        "handler.ExpireCodesRequest": {
+            "description": "期限切れコードの一括失効リクエスト（limitを省略した場合は500件）",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 500
+                }
+            }
+        },
+        "handler.ExpireCodesResponse": {
+            "description": "期限切れコードの一括失効レスポンス（codesは有効期限の昇順）",
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "SPRING2024",
+                        "SUMMER2024"
+                    ]
                 },
-                "currency_type": {
-                    "type": "string",
-                    "example": "paid"
+                "expired_codes": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "handler.CreateCodeRequest": {
-            "description": "引き換えコード作成リクエスト",
+        "handler.GenerateCodesRequest": {
+            "description": "引き換えコード一括生成リクエスト（コードは prefix + alphabetから選んだlength文字、複数の通貨を付与する場合はcurrency_typeとamountの代わりにrewardsを指定）",
             "type": "object",
             "properties": {
+                "alphabet": {
+                    "type": "string",
+                    "example": "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+                },
                 "amount": {
                     "type": "string",
-                    "example": "1000"
+                    "example": "500"
                 },
-                "code": {
+                "batch_id": {
                     "type": "string",
-                    "example": "PROMO2024"
+                    "example": "influencer_2024_spring"
+                },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "spring_2024"
                 },
                 "code_type": {
                     "type": "string",
@@ -991,6 +2578,10 @@ const docTemplate = `{
                     ],
                     "example": "promotion"
                 },
+                "count": {
+                    "type": "integer",
+                    "example": 1000
+                },
                 "currency_type": {
                     "type": "string",
                     "enum": [
@@ -999,13 +2590,87 @@ const docTemplate = `{
                     ],
                     "example": "free"
                 },
+                "eligibility": {
+                    "$ref": "#/definitions/handler.EligibilityItem"
+                },
+                "length": {
+                    "type": "integer",
+                    "example": 10
+                },
                 "max_uses": {
                     "type": "integer",
-                    "example": 100
+                    "example": 1
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "INF-"
+                },
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                }
+            }
+        },
+        "handler.GenerateCodesResponse": {
+            "description": "引き換えコード一括生成レスポンス",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "batch_id": {
+                    "type": "string",
+                    "example": "influencer_2024_spring"
+                },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "spring_2024"
+                },
+                "code_type": {
+                    "type": "string",
+                    "example": "promotion"
+                },
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "INF-7KQ2M9XHPA"
+                    ]
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1000
+                },
+                "currency_type": {
+                    "type": "string",
+                    "example": "free"
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": true
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
                 },
                 "valid_from": {
                     "type": "string",
@@ -1017,14 +2682,48 @@ const docTemplate = `{
                 }
             }
         },
-        "handler.CreateCodeResponse": {
-            "description": "引き換えコード作成レスポンス",
+        "handler.GenerateTokenResponse": {
+            "description": "トークン生成レスポンス",
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer",
+                    "example": 900
+                },
+                "refresh_expires_in": {
+                    "type": "integer",
+                    "example": 2592000
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "Qm9vZ2llV29vZ2llQm9vZ2llV29vZ2llQm9vZ2llV29v"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoidXNlcjEyMyIsImV4cCI6MTcwMDAwMDAwMH0.signature"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "handler.GetCodeResponse": {
+            "description": "引き換えコード取得レスポンス",
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "string",
                     "example": "1000"
                 },
+                "batch_id": {
+                    "type": "string",
+                    "example": "influencer_2024_spring"
+                },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "spring_2024"
+                },
                 "code": {
                     "type": "string",
                     "example": "PROMO2024"
@@ -1045,6 +2744,9 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 0
                 },
+                "eligibility": {
+                    "$ref": "#/definitions/handler.EligibilityItem"
+                },
                 "max_uses": {
                     "type": "integer",
                     "example": 100
@@ -1053,10 +2755,20 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": true
                 },
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RewardItem"
+                    }
+                },
                 "status": {
                     "type": "string",
                     "example": "active"
                 },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
                 "valid_from": {
                     "type": "string",
                     "example": "2024-01-01T00:00:00Z"
@@ -1067,109 +2779,183 @@ const docTemplate = `{
                 }
             }
         },
-        "handler.DeleteCodeResponse": {
-            "description": "引き換えコード削除レスポンス",
+        "handler.GrantRequest": {
+            "description": "通貨付与リクエスト",
             "type": "object",
             "properties": {
-                "code": {
+                "amount": {
                     "type": "string",
-                    "example": "PROMO2024"
+                    "example": "100"
                 },
-                "deleted_at": {
+                "currency_type": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "enum": [
+                        "paid",
+                        "free"
+                    ],
+                    "example": "free"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2026-12-31T23:59:59Z"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "イベント報酬"
                 }
             }
         },
-        "handler.ErrorResponse": {
-            "description": "エラーレスポンス",
+        "handler.GrantResponse": {
+            "description": "通貨付与レスポンス",
             "type": "object",
             "properties": {
-                "error": {
+                "balance_after": {
                     "type": "string",
-                    "example": "invalid request body"
+                    "example": "600"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "transaction_id": {
+                    "type": "string",
+                    "example": "txn_123"
                 }
             }
         },
-        "handler.GenerateTokenResponse": {
-            "description": "トークン生成レスポンス",
+        "handler.ListCodesResponse": {
+            "description": "引き換えコード一覧取得レスポンス",
             "type": "object",
             "properties": {
-                "expires_in": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CodeItem"
+                    }
+                },
+                "limit": {
                     "type": "integer",
-                    "example": 3600
+                    "example": 50
                 },
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoidXNlcjEyMyIsImV4cCI6MTcwMDAwMDAwMH0.signature"
+                "offset": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "token_type": {
-                    "type": "string",
-                    "example": "Bearer"
+                "total": {
+                    "type": "integer",
+                    "example": 100
                 }
             }
         },
-        "handler.GetCodeResponse": {
-            "description": "引き換えコード取得レスポンス",
+        "handler.ListRedemptionsResponse": {
+            "description": "引き換え履歴取得レスポンス（新しい順）",
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 50
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "redemptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RedemptionItem"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "handler.ProcessPaymentRequest": {
+            "description": "決済処理リクエスト",
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "string",
                     "example": "1000"
                 },
-                "code": {
+                "currency": {
                     "type": "string",
-                    "example": "PROMO2024"
+                    "example": "JPY"
                 },
-                "code_type": {
-                    "type": "string",
-                    "example": "promotion"
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
-                "created_at": {
+                "method_name": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "example": "credit_card"
                 },
-                "currency_type": {
+                "payment_request_id": {
                     "type": "string",
-                    "example": "free"
-                },
-                "current_uses": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "max_uses": {
-                    "type": "integer",
-                    "example": 100
+                    "example": "req_123"
+                }
+            }
+        },
+        "handler.ProcessPaymentResponse": {
+            "description": "決済処理レスポンス",
+            "type": "object",
+            "properties": {
+                "consumption_details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ConsumptionDetail"
+                    }
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": true
+                "payment_request_id": {
+                    "type": "string",
+                    "example": "req_123"
                 },
                 "status": {
                     "type": "string",
-                    "example": "active"
+                    "example": "completed"
                 },
-                "updated_at": {
+                "total_consumed": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
+                    "example": "1000"
                 },
-                "valid_from": {
+                "transaction_id": {
                     "type": "string",
-                    "example": "2024-01-01T00:00:00Z"
-                },
-                "valid_until": {
+                    "example": "txn_789"
+                }
+            }
+        },
+        "handler.RedeemCodeRequest": {
+            "description": "コード引き換えリクエスト",
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string",
-                    "example": "2024-12-31T23:59:59Z"
+                    "example": "REDEEM123"
                 }
             }
         },
-        "handler.GrantRequest": {
-            "description": "通貨付与リクエスト",
+        "handler.RedeemCodeResponse": {
+            "description": "コード引き換えレスポンス",
             "type": "object",
             "properties": {
                 "amount": {
                     "type": "string",
-                    "example": "100"
+                    "example": "500"
+                },
+                "balance_after": {
+                    "type": "string",
+                    "example": "1000"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "REDEEM123"
                 },
                 "currency_type": {
                     "type": "string",
@@ -1179,143 +2965,227 @@ const docTemplate = `{
                     ],
                     "example": "free"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": true
+                "redemption_id": {
+                    "type": "string",
+                    "example": "red_123"
                 },
-                "reason": {
+                "rewards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RedeemedRewardItem"
+                    }
+                },
+                "status": {
                     "type": "string",
-                    "example": "イベント報酬"
+                    "example": "completed"
+                },
+                "transaction_id": {
+                    "type": "string",
+                    "example": "txn_456"
+                }
+            }
+        },
+        "handler.RedeemedRewardItem": {
+            "description": "引き換えで付与した報酬（報酬ごとにトランザクションが記録される）",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "500"
+                },
+                "balance_after": {
+                    "type": "string",
+                    "example": "1000"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "free"
+                    ],
+                    "example": "free"
                 },
-                "requester": {
+                "transaction_id": {
                     "type": "string",
-                    "example": "game-server-01"
+                    "example": "txn_456"
                 }
             }
         },
-        "handler.GrantResponse": {
-            "description": "通貨付与レスポンス",
+        "handler.RedemptionItem": {
+            "description": "引き換え履歴アイテム（transaction_idは先頭の報酬を付与したトランザクション、transaction_idsは報酬ごとに付与したすべてのトランザクション）",
             "type": "object",
             "properties": {
-                "balance_after": {
+                "code": {
                     "type": "string",
-                    "example": "600"
+                    "example": "PROMO2024"
                 },
-                "status": {
+                "redeemed_at": {
                     "type": "string",
-                    "example": "completed"
+                    "example": "2024-04-01T12:00:00Z"
+                },
+                "redemption_id": {
+                    "type": "string",
+                    "example": "red_123"
                 },
                 "transaction_id": {
                     "type": "string",
-                    "example": "txn_123"
+                    "example": "txn_456"
+                },
+                "transaction_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "txn_456",
+                        "txn_457"
+                    ]
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
                 }
             }
         },
-        "handler.ListCodesResponse": {
-            "description": "引き換えコード一覧取得レスポンス",
+        "handler.RefreshTokenRequest": {
+            "description": "トークン更新リクエスト",
             "type": "object",
             "properties": {
-                "codes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.CodeItem"
-                    }
-                },
-                "limit": {
-                    "type": "integer",
-                    "example": 50
-                },
-                "offset": {
-                    "type": "integer",
-                    "example": 0
-                },
-                "total": {
-                    "type": "integer",
-                    "example": 100
+                "refresh_token": {
+                    "type": "string",
+                    "example": "Qm9vZ2llV29vZ2llQm9vZ2llV29vZ2llQm9vZ2llV29v"
                 }
             }
         },
-        "handler.ProcessPaymentRequest": {
-            "description": "決済処理リクエスト",
+        "handler.RefundDetail": {
+            "description": "返金詳細",
             "type": "object",
             "properties": {
                 "amount": {
+                    "type": "string",
+                    "example": "50"
+                },
+                "balance_after": {
                     "type": "string",
                     "example": "1000"
                 },
-                "currency": {
+                "balance_before": {
                     "type": "string",
-                    "example": "JPY"
+                    "example": "950"
                 },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "currency_type": {
+                    "type": "string",
+                    "example": "free"
                 },
-                "method_name": {
+                "original_transaction_id": {
                     "type": "string",
-                    "example": "credit_card"
+                    "example": "txn_456_free"
                 },
-                "payment_request_id": {
+                "transaction_id": {
                     "type": "string",
-                    "example": "req_123"
+                    "example": "refund_txn_456_free"
                 }
             }
         },
-        "handler.ProcessPaymentResponse": {
-            "description": "決済処理レスポンス",
+        "handler.RefundRequest": {
+            "description": "返金リクエスト",
             "type": "object",
             "properties": {
-                "consumption_details": {
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "誤購入のため"
+                }
+            }
+        },
+        "handler.RefundResponse": {
+            "description": "返金レスポンス",
+            "type": "object",
+            "properties": {
+                "original_transaction_id": {
+                    "type": "string",
+                    "example": "txn_456"
+                },
+                "refund_details": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.ConsumptionDetail"
+                        "$ref": "#/definitions/handler.RefundDetail"
                     }
                 },
-                "payment_request_id": {
-                    "type": "string",
-                    "example": "req_123"
-                },
                 "status": {
                     "type": "string",
                     "example": "completed"
                 },
-                "total_consumed": {
+                "total_refunded": {
                     "type": "string",
-                    "example": "1000"
+                    "example": "50"
                 },
                 "transaction_id": {
                     "type": "string",
-                    "example": "txn_789"
+                    "example": "refund_txn_456"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user123"
                 }
             }
         },
-        "handler.RedeemCodeRequest": {
-            "description": "コード引き換えリクエスト",
+        "handler.RevokeTokenRequest": {
+            "description": "トークン失効リクエスト（アクセストークンまたはリフレッシュトークン）",
             "type": "object",
             "properties": {
-                "code": {
+                "token": {
                     "type": "string",
-                    "example": "REDEEM123"
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoidXNlcjEyMyIsImV4cCI6MTcwMDAwMDAwMH0.signature"
                 }
             }
         },
-        "handler.RedeemCodeResponse": {
-            "description": "コード引き換えレスポンス",
+        "handler.RevokeTokenResponse": {
+            "description": "トークン失効レスポンス",
             "type": "object",
             "properties": {
-                "amount": {
+                "revoked_at": {
                     "type": "string",
-                    "example": "500"
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "balance_after": {
+                "token_type": {
                     "type": "string",
-                    "example": "1000"
+                    "enum": [
+                        "access",
+                        "refresh"
+                    ],
+                    "example": "access"
+                }
+            }
+        },
+        "handler.RevokeUserTokensResponse": {
+            "description": "ユーザーのトークン一括失効レスポンス",
+            "type": "object",
+            "properties": {
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
                 },
-                "code": {
+                "revoked_refresh_tokens": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "user_id": {
                     "type": "string",
-                    "example": "REDEEM123"
+                    "example": "user123"
+                }
+            }
+        },
+        "handler.RewardItem": {
+            "description": "引き換えコードの報酬",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100"
                 },
                 "currency_type": {
                     "type": "string",
@@ -1324,18 +3194,6 @@ const docTemplate = `{
                         "free"
                     ],
                     "example": "free"
-                },
-                "redemption_id": {
-                    "type": "string",
-                    "example": "red_123"
-                },
-                "status": {
-                    "type": "string",
-                    "example": "completed"
-                },
-                "transaction_id": {
-                    "type": "string",
-                    "example": "txn_456"
                 }
             }
         },
@@ -1347,6 +3205,11 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 50
                 },
+                "next_cursor": {
+                    "description": "次のページを取得するためのカーソル（最後のページでは省略）",
+                    "type": "string",
+                    "example": "MjAyNS0wMS0wMVQwMDowMDowMFp8dHhuXzEyMw"
+                },
                 "offset": {
                     "type": "integer",
                     "example": 0
@@ -1400,6 +3263,149 @@ const docTemplate = `{
                     "example": "consume"
                 }
             }
+        },
+        "handler.TransferRequest": {
+            "description": "通貨譲渡リクエスト。譲渡できる通貨タイプはサーバー設定で決まる（通常は無償通貨のみ）",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "free"
+                    ],
+                    "example": "free"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "いつもありがとう"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "friend456"
+                }
+            }
+        },
+        "handler.TransferResponse": {
+            "description": "通貨譲渡レスポンス",
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "balance_after": {
+                    "type": "string",
+                    "example": "400"
+                },
+                "currency_type": {
+                    "type": "string",
+                    "example": "free"
+                },
+                "from_user_id": {
+                    "type": "string",
+                    "example": "user123"
+                },
+                "receiver_transaction_id": {
+                    "type": "string",
+                    "example": "trf_0192b6f0-7c1e-7000-8000-000000000001_in"
+                },
+                "sender_transaction_id": {
+                    "type": "string",
+                    "example": "trf_0192b6f0-7c1e-7000-8000-000000000001_out"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                },
+                "to_user_id": {
+                    "type": "string",
+                    "example": "friend456"
+                },
+                "transfer_id": {
+                    "type": "string",
+                    "example": "trf_0192b6f0-7c1e-7000-8000-000000000001"
+                }
+            }
+        },
+        "handler.UpdateCodeRequest": {
+            "description": "引き換えコード更新リクエスト（省略したフィールドは変更しない。valid_untilは延長のみ、max_usesは引き上げのみ可能で、valid_untilを過ぎたコードはactiveに戻せない）",
+            "type": "object",
+            "properties": {
+                "max_uses": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "キャンペーン期間の延長"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "disabled",
+                        "expired"
+                    ],
+                    "example": "active"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2025-03-31T23:59:59Z"
+                }
+            }
+        },
+        "handler.UpdateCodeResponse": {
+            "description": "引き換えコード更新レスポンス（値が変わらなかった場合はaudit_idが空でchangesが空配列）",
+            "type": "object",
+            "properties": {
+                "audit_id": {
+                    "type": "string",
+                    "example": "audit_0190a6e2-7c3b-7d4e-8f5a-1b2c3d4e5f60"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.CodeChangeItem"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "example": "PROMO2024"
+                },
+                "current_uses": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "max_uses": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-06-01T00:00:00Z"
+                },
+                "valid_from": {
+                    "type": "string",
+                    "example": "2024-01-01T00:00:00Z"
+                },
+                "valid_until": {
+                    "type": "string",
+                    "example": "2025-03-31T23:59:59Z"
+                }
+            }
         }
     },
     "securityDefinitions": {
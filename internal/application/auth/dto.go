@@ -1,5 +1,11 @@
 package auth
 
+import (
+	"time"
+
+	"gem-server/internal/infrastructure/jwtkeys"
+)
+
 // GenerateTokenRequest トークン生成リクエスト
 type GenerateTokenRequest struct {
 	UserID string
@@ -7,7 +13,42 @@ type GenerateTokenRequest struct {
 
 // GenerateTokenResponse トークン生成レスポンス
 type GenerateTokenResponse struct {
-	Token     string
-	ExpiresIn int64  // 秒単位
-	TokenType string // "Bearer"
+	Token            string
+	ExpiresIn        int64  // 秒単位
+	TokenType        string // "Bearer"
+	RefreshToken     string
+	RefreshExpiresIn int64 // 秒単位
+}
+
+// RefreshTokenRequest トークン更新リクエスト
+type RefreshTokenRequest struct {
+	RefreshToken string
+}
+
+// RevokeTokenRequest トークン失効リクエスト
+type RevokeTokenRequest struct {
+	Token string // アクセストークン（JWT）またはリフレッシュトークン
+}
+
+// RevokeTokenResponse トークン失効レスポンス
+type RevokeTokenResponse struct {
+	TokenType string // "access" または "refresh"
+	RevokedAt time.Time
+}
+
+// RevokeUserTokensRequest ユーザーのトークン一括失効リクエスト
+type RevokeUserTokensRequest struct {
+	UserID string
+}
+
+// RevokeUserTokensResponse ユーザーのトークン一括失効レスポンス
+type RevokeUserTokensResponse struct {
+	UserID               string
+	RevokedRefreshTokens int
+	RevokedAt            time.Time
+}
+
+// GetJWKSResponse 検証用の公開鍵一覧レスポンス
+type GetJWKSResponse struct {
+	Keys []jwtkeys.JWK
 }
@@ -2,10 +2,16 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"gem-server/internal/domain/auth_token"
+	"gem-server/internal/domain/idgen"
+	"gem-server/internal/domain/transaction"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,19 +22,37 @@ import (
 
 // AuthApplicationService 認証アプリケーションサービス
 type AuthApplicationService struct {
-	jwtConfig *config.JWTConfig
-	logger    *otelinfra.Logger
+	jwtConfig        *config.JWTConfig
+	keyring          *jwtkeys.Keyring
+	refreshTokenRepo auth_token.RefreshTokenRepository
+	txManager        transaction.TransactionManager
+	denylist         auth_token.Denylist
+	idGenerator      idgen.Generator
+	logger           *otelinfra.Logger
 }
 
 // NewAuthApplicationService 新しいAuthApplicationServiceを作成
-func NewAuthApplicationService(jwtConfig *config.JWTConfig, logger *otelinfra.Logger) *AuthApplicationService {
+func NewAuthApplicationService(
+	jwtConfig *config.JWTConfig,
+	keyring *jwtkeys.Keyring,
+	refreshTokenRepo auth_token.RefreshTokenRepository,
+	txManager transaction.TransactionManager,
+	denylist auth_token.Denylist,
+	idGenerator idgen.Generator,
+	logger *otelinfra.Logger,
+) *AuthApplicationService {
 	return &AuthApplicationService{
-		jwtConfig: jwtConfig,
-		logger:    logger,
+		jwtConfig:        jwtConfig,
+		keyring:          keyring,
+		refreshTokenRepo: refreshTokenRepo,
+		txManager:        txManager,
+		denylist:         denylist,
+		idGenerator:      idGenerator,
+		logger:           logger,
 	}
 }
 
-// GenerateToken JWTトークンを生成
+// GenerateToken アクセストークン（JWT）とリフレッシュトークンを生成
 func (s *AuthApplicationService) GenerateToken(ctx context.Context, req *GenerateTokenRequest) (*GenerateTokenResponse, error) {
 	tracer := otel.Tracer("auth-service")
 	ctx, span := tracer.Start(ctx, "AuthApplicationService.GenerateToken")
@@ -47,38 +71,325 @@ func (s *AuthApplicationService) GenerateToken(ctx context.Context, req *Generat
 		return nil, err
 	}
 
+	// 新しい系列のリフレッシュトークンを発行
+	refreshToken, refreshValue, err := s.newRefreshToken(req.UserID, "")
+	if err == nil {
+		err = s.refreshTokenRepo.Save(ctx, refreshToken)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to issue refresh token", err, map[string]interface{}{
+			"user_id": req.UserID,
+		})
+		return nil, fmt.Errorf("failed to issue refresh token: %w", err)
+	}
+
+	resp, err := s.issueAccessToken(ctx, req.UserID, refreshValue)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// RefreshToken リフレッシュトークンをローテーションし、新しいアクセストークンとリフレッシュトークンを発行
+// ローテーション済みのリフレッシュトークンが再度使われた場合は漏洩とみなし、同じ系列のトークンをすべて失効させる
+func (s *AuthApplicationService) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*GenerateTokenResponse, error) {
+	tracer := otel.Tracer("auth-service")
+	ctx, span := tracer.Start(ctx, "AuthApplicationService.RefreshToken")
+	defer span.End()
+
+	if req.RefreshToken == "" {
+		err := fmt.Errorf("%w: refresh_token is required", auth_token.ErrInvalidRefreshToken)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	var (
+		userID       string
+		refreshValue string
+		reused       *auth_token.RefreshToken
+	)
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		current, err := s.refreshTokenRepo.FindByHash(ctx, auth_token.HashToken(req.RefreshToken))
+		if errors.Is(err, auth_token.ErrRefreshTokenNotFound) {
+			return auth_token.ErrInvalidRefreshToken
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find refresh token: %w", err)
+		}
+
+		if current.IsRotated() {
+			// 失効処理をコミットするため、エラーはトランザクションの外で返す
+			if _, err := s.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID(), now); err != nil {
+				return fmt.Errorf("failed to revoke refresh token family: %w", err)
+			}
+			reused = current
+			return nil
+		}
+		if current.IsRevoked() || current.IsExpired(now) {
+			return auth_token.ErrInvalidRefreshToken
+		}
+
+		next, value, err := s.newRefreshToken(current.UserID(), current.FamilyID())
+		if err != nil {
+			return err
+		}
+		if err := current.Rotate(next.TokenID(), now); err != nil {
+			return auth_token.ErrInvalidRefreshToken
+		}
+		if err := s.refreshTokenRepo.Update(ctx, current); err != nil {
+			return fmt.Errorf("failed to update refresh token: %w", err)
+		}
+		if err := s.refreshTokenRepo.Save(ctx, next); err != nil {
+			return fmt.Errorf("failed to save refresh token: %w", err)
+		}
+
+		userID = current.UserID()
+		refreshValue = value
+		return nil
+	})
+	if err == nil && reused != nil {
+		s.logger.Warn(ctx, "Refresh token reuse detected, revoked token family", map[string]interface{}{
+			"user_id":   reused.UserID(),
+			"family_id": reused.FamilyID(),
+			"token_id":  reused.TokenID(),
+		})
+		err = fmt.Errorf("%w: token has already been used", auth_token.ErrInvalidRefreshToken)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if !errors.Is(err, auth_token.ErrInvalidRefreshToken) {
+			s.logger.Error(ctx, "Failed to refresh token", err, nil)
+		}
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.String("user_id", userID))
+
+	resp, err := s.issueAccessToken(ctx, userID, refreshValue)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// RevokeToken アクセストークンまたはリフレッシュトークンを失効させる
+// アクセストークンは有効期限までDenylistに記録し、リフレッシュトークンは同じ系列のトークンをすべて失効させる
+func (s *AuthApplicationService) RevokeToken(ctx context.Context, req *RevokeTokenRequest) (*RevokeTokenResponse, error) {
+	tracer := otel.Tracer("auth-service")
+	ctx, span := tracer.Start(ctx, "AuthApplicationService.RevokeToken")
+	defer span.End()
+
+	if req.Token == "" {
+		err := fmt.Errorf("%w: token is required", auth_token.ErrInvalidToken)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+
+	// JWTはヘッダー・ペイロード・署名の3つの部分からなる
+	if strings.Count(req.Token, ".") == 2 {
+		span.SetAttributes(attribute.String("token_type", "access"))
+
+		jti, expiresAt, err := s.parseAccessToken(req.Token)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, err
+		}
+		if err := s.denylist.Add(ctx, jti, expiresAt); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			s.logger.Error(ctx, "Failed to revoke access token", err, map[string]interface{}{
+				"jti": jti,
+			})
+			return nil, fmt.Errorf("failed to revoke access token: %w", err)
+		}
+
+		s.logger.Info(ctx, "Access token revoked", map[string]interface{}{
+			"jti":        jti,
+			"expires_at": expiresAt.Unix(),
+		})
+		return &RevokeTokenResponse{TokenType: "access", RevokedAt: now}, nil
+	}
+
+	span.SetAttributes(attribute.String("token_type", "refresh"))
+
+	token, err := s.refreshTokenRepo.FindByHash(ctx, auth_token.HashToken(req.Token))
+	if errors.Is(err, auth_token.ErrRefreshTokenNotFound) {
+		err = fmt.Errorf("%w: unknown refresh token", auth_token.ErrInvalidToken)
+	}
+	if err == nil {
+		_, err = s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID(), now)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if !errors.Is(err, auth_token.ErrInvalidToken) {
+			s.logger.Error(ctx, "Failed to revoke refresh token", err, nil)
+		}
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Refresh token revoked", map[string]interface{}{
+		"user_id":   token.UserID(),
+		"family_id": token.FamilyID(),
+	})
+	return &RevokeTokenResponse{TokenType: "refresh", RevokedAt: now}, nil
+}
+
+// RevokeUserTokens ユーザーのリフレッシュトークンと発行済みのアクセストークンをすべて失効させる
+func (s *AuthApplicationService) RevokeUserTokens(ctx context.Context, req *RevokeUserTokensRequest) (*RevokeUserTokensResponse, error) {
+	tracer := otel.Tracer("auth-service")
+	ctx, span := tracer.Start(ctx, "AuthApplicationService.RevokeUserTokens")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user_id", req.UserID),
+	)
+
+	if req.UserID == "" {
+		err := fmt.Errorf("user_id is required")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	now := time.Now()
+
+	revoked, err := s.refreshTokenRepo.RevokeByUserID(ctx, req.UserID, now)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to revoke refresh tokens", err, map[string]interface{}{
+			"user_id": req.UserID,
+		})
+		return nil, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	// 発行済みのアクセストークンがすべて期限切れになるまで記録する
+	if err := s.denylist.RevokeUser(ctx, req.UserID, now, now.Add(s.jwtConfig.Expiration)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to revoke access tokens", err, map[string]interface{}{
+			"user_id": req.UserID,
+		})
+		return nil, fmt.Errorf("failed to revoke access tokens: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("revoked_refresh_tokens", revoked))
+	s.logger.Info(ctx, "User tokens revoked", map[string]interface{}{
+		"user_id":                req.UserID,
+		"revoked_refresh_tokens": revoked,
+	})
+
+	return &RevokeUserTokensResponse{
+		UserID:               req.UserID,
+		RevokedRefreshTokens: revoked,
+		RevokedAt:            now,
+	}, nil
+}
+
+// GetJWKS アクセストークンの検証に使う公開鍵（JWKS）を取得
+// HS256で署名している場合は公開鍵がないため空の一覧を返す
+func (s *AuthApplicationService) GetJWKS(ctx context.Context) *GetJWKSResponse {
+	return &GetJWKSResponse{Keys: s.keyring.PublicKeys()}
+}
+
+// newRefreshToken リフレッシュトークンのエンティティと、クライアントに返す平文の値を作成
+func (s *AuthApplicationService) newRefreshToken(userID, familyID string) (*auth_token.RefreshToken, string, error) {
+	value, err := auth_token.GenerateRefreshTokenValue()
+	if err != nil {
+		return nil, "", err
+	}
+	token := auth_token.NewRefreshToken(
+		"rt_"+s.idGenerator.NewID(),
+		userID,
+		familyID,
+		auth_token.HashToken(value),
+		time.Now().Add(s.jwtConfig.RefreshExpiration),
+	)
+	return token, value, nil
+}
+
+// issueAccessToken アクセストークン（JWT）を署名し、リフレッシュトークンと合わせたレスポンスを作成
+func (s *AuthApplicationService) issueAccessToken(ctx context.Context, userID, refreshToken string) (*GenerateTokenResponse, error) {
 	// トークンの有効期限を計算
 	now := time.Now()
 	expiresAt := now.Add(s.jwtConfig.Expiration)
+	jti := s.idGenerator.NewID()
 
-	// JWTクレームを作成
+	// JWTクレームを作成（jtiは個別に失効させる際の識別子）
 	claims := jwt.MapClaims{
-		"user_id": req.UserID,
+		"user_id": userID,
+		"jti":     jti,
 		"iss":     s.jwtConfig.Issuer,
 		"iat":     now.Unix(),
 		"exp":     expiresAt.Unix(),
 	}
 
-	// JWTトークンを生成
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.jwtConfig.Secret))
+	// JWTトークンを生成（署名鍵を設定した場合はヘッダーにkidが付与される）
+	tokenString, err := s.keyring.Sign(claims)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
 		s.logger.Error(ctx, "Failed to generate token", err, map[string]interface{}{
-			"user_id": req.UserID,
+			"user_id": userID,
 		})
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	s.logger.Info(ctx, "Token generated successfully", map[string]interface{}{
-		"user_id":    req.UserID,
+		"user_id":    userID,
+		"jti":        jti,
+		"kid":        s.keyring.SigningKeyID(),
 		"expires_at": expiresAt.Unix(),
 	})
 
 	return &GenerateTokenResponse{
-		Token:     tokenString,
-		ExpiresIn: int64(s.jwtConfig.Expiration.Seconds()),
-		TokenType: "Bearer",
+		Token:            tokenString,
+		ExpiresIn:        int64(s.jwtConfig.Expiration.Seconds()),
+		TokenType:        "Bearer",
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(s.jwtConfig.RefreshExpiration.Seconds()),
 	}, nil
 }
+
+// parseAccessToken 失効させるアクセストークンの署名を検証し、jtiと有効期限を取得
+// 期限切れのトークンも受け付ける
+func (s *AuthApplicationService) parseAccessToken(tokenString string) (string, time.Time, error) {
+	token, err := jwt.Parse(tokenString, s.keyring.Keyfunc,
+		jwt.WithValidMethods(s.keyring.ValidMethods()), jwt.WithoutClaimsValidation())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("%w: %v", auth_token.ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", time.Time{}, fmt.Errorf("%w: invalid claims", auth_token.ErrInvalidToken)
+	}
+	if iss, _ := claims.GetIssuer(); iss != s.jwtConfig.Issuer {
+		return "", time.Time{}, fmt.Errorf("%w: invalid issuer", auth_token.ErrInvalidToken)
+	}
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return "", time.Time{}, fmt.Errorf("%w: token has no jti", auth_token.ErrInvalidToken)
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return "", time.Time{}, fmt.Errorf("%w: token has no exp", auth_token.ErrInvalidToken)
+	}
+
+	return jti, exp.Time, nil
+}
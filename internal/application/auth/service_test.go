@@ -2,22 +2,120 @@ package auth
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
 
+	"gem-server/internal/domain/auth_token"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 )
 
+// MockRefreshTokenRepository モックリフレッシュトークンリポジトリ
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Save(ctx context.Context, token *auth_token.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_token.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_token.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Update(ctx context.Context, token *auth_token.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, familyID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, userID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
+
+// MockTransactionManager モックトランザクションマネージャー
+type MockTransactionManager struct {
+	mock.Mock
+}
+
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.Called(ctx, fn)
+	return fn(ctx)
+}
+
+// MockDenylist モックDenylist
+type MockDenylist struct {
+	mock.Mock
+}
+
+func (m *MockDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	args := m.Called(ctx, jti, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	args := m.Called(ctx, jti)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDenylist) RevokeUser(ctx context.Context, userID string, revokedAt, until time.Time) error {
+	args := m.Called(ctx, userID, revokedAt, until)
+	return args.Error(0)
+}
+
+func (m *MockDenylist) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func newTestJWTConfig() *config.JWTConfig {
+	return &config.JWTConfig{
+		Secret:            "test-secret-key",
+		Issuer:            "test-issuer",
+		Expiration:        15 * time.Minute,
+		RefreshExpiration: 30 * 24 * time.Hour,
+	}
+}
+
+func newTestAuthService(t *testing.T, jwtConfig *config.JWTConfig, repo *MockRefreshTokenRepository, txManager *MockTransactionManager, denylist *MockDenylist) *AuthApplicationService {
+	t.Helper()
+	keyring, err := jwtkeys.NewKeyring(jwtConfig)
+	require.NoError(t, err)
+	logger := otelinfra.NewLogger(otel.Tracer("test"))
+	return NewAuthApplicationService(jwtConfig, keyring, repo, txManager, denylist, idgen.NewUUIDv7Generator(), logger)
+}
+
 func TestAuthApplicationService_GenerateToken(t *testing.T) {
 	tests := []struct {
 		name      string
 		req       *GenerateTokenRequest
 		jwtConfig *config.JWTConfig
+		setupMock func(*MockRefreshTokenRepository)
 		wantError bool
 		checkFunc func(*testing.T, *GenerateTokenResponse, error)
 	}{
@@ -26,17 +124,29 @@ func TestAuthApplicationService_GenerateToken(t *testing.T) {
 			req: &GenerateTokenRequest{
 				UserID: "user123",
 			},
-			jwtConfig: &config.JWTConfig{
-				Secret:     "test-secret-key",
-				Issuer:     "test-issuer",
-				Expiration: 24 * time.Hour,
+			jwtConfig: newTestJWTConfig(),
+			setupMock: func(repo *MockRefreshTokenRepository) {
+				repo.On("Save", mock.Anything, mock.MatchedBy(func(token *auth_token.RefreshToken) bool {
+					return token.UserID() == "user123" && token.FamilyID() == token.TokenID()
+				})).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GenerateTokenResponse, err error) {
 				require.NoError(t, err)
 				assert.NotEmpty(t, resp.Token)
-				assert.Equal(t, int64(86400), resp.ExpiresIn) // 24時間 = 86400秒
+				assert.Equal(t, int64(900), resp.ExpiresIn) // 15分 = 900秒
 				assert.Equal(t, "Bearer", resp.TokenType)
+				assert.NotEmpty(t, resp.RefreshToken)
+				assert.Equal(t, int64(2592000), resp.RefreshExpiresIn) // 30日 = 2592000秒
+
+				// 個別に失効させるためのjtiを含む
+				claims := jwt.MapClaims{}
+				_, err = jwt.ParseWithClaims(resp.Token, claims, func(token *jwt.Token) (interface{}, error) {
+					return []byte("test-secret-key"), nil
+				})
+				require.NoError(t, err)
+				assert.Equal(t, "user123", claims["user_id"])
+				assert.NotEmpty(t, claims["jti"])
 			},
 		},
 		{
@@ -44,25 +154,33 @@ func TestAuthApplicationService_GenerateToken(t *testing.T) {
 			req: &GenerateTokenRequest{
 				UserID: "",
 			},
-			jwtConfig: &config.JWTConfig{
-				Secret:     "test-secret-key",
-				Issuer:     "test-issuer",
-				Expiration: 24 * time.Hour,
-			},
+			jwtConfig: newTestJWTConfig(),
+			setupMock: func(repo *MockRefreshTokenRepository) {},
 			wantError: true,
 			checkFunc: func(t *testing.T, resp *GenerateTokenResponse, err error) {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), "user_id is required")
 			},
 		},
+		{
+			name: "異常系: リフレッシュトークンの保存に失敗",
+			req: &GenerateTokenRequest{
+				UserID: "user123",
+			},
+			jwtConfig: newTestJWTConfig(),
+			setupMock: func(repo *MockRefreshTokenRepository) {
+				repo.On("Save", mock.Anything, mock.Anything).Return(errors.New("database error"))
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tracer := otel.Tracer("test")
-			logger := otelinfra.NewLogger(tracer)
+			repo := new(MockRefreshTokenRepository)
+			tt.setupMock(repo)
 
-			svc := NewAuthApplicationService(tt.jwtConfig, logger)
+			svc := newTestAuthService(t, tt.jwtConfig, repo, new(MockTransactionManager), new(MockDenylist))
 
 			ctx := context.Background()
 			got, err := svc.GenerateToken(ctx, tt.req)
@@ -80,6 +198,270 @@ func TestAuthApplicationService_GenerateToken(t *testing.T) {
 					assert.NotNil(t, got)
 				}
 			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthApplicationService_RefreshToken(t *testing.T) {
+	const refreshValue = "refresh-token-value"
+	hash := auth_token.HashToken(refreshValue)
+
+	newToken := func(expiresAt time.Time) *auth_token.RefreshToken {
+		return auth_token.NewRefreshToken("rt_1", "user123", "rt_family", hash, expiresAt)
+	}
+
+	tests := []struct {
+		name      string
+		req       *RefreshTokenRequest
+		setupMock func(*MockRefreshTokenRepository, *MockTransactionManager)
+		wantErr   error
+		checkFunc func(*testing.T, *GenerateTokenResponse)
+	}{
+		{
+			name: "正常系: リフレッシュトークンをローテーション",
+			req:  &RefreshTokenRequest{RefreshToken: refreshValue},
+			setupMock: func(repo *MockRefreshTokenRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				repo.On("FindByHash", mock.Anything, hash).Return(newToken(time.Now().Add(time.Hour)), nil)
+				repo.On("Update", mock.Anything, mock.MatchedBy(func(token *auth_token.RefreshToken) bool {
+					return token.TokenID() == "rt_1" && token.IsRotated()
+				})).Return(nil)
+				repo.On("Save", mock.Anything, mock.MatchedBy(func(token *auth_token.RefreshToken) bool {
+					// 同じ系列を引き継ぐ
+					return token.UserID() == "user123" && token.FamilyID() == "rt_family" && token.TokenHash() != hash
+				})).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *GenerateTokenResponse) {
+				assert.NotEmpty(t, resp.Token)
+				assert.NotEmpty(t, resp.RefreshToken)
+				assert.NotEqual(t, refreshValue, resp.RefreshToken)
+			},
+		},
+		{
+			name: "異常系: ローテーション済みのトークンを再利用",
+			req:  &RefreshTokenRequest{RefreshToken: refreshValue},
+			setupMock: func(repo *MockRefreshTokenRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				token := newToken(time.Now().Add(time.Hour))
+				require.NoError(t, token.Rotate("rt_2", time.Now().Add(-time.Minute)))
+				repo.On("FindByHash", mock.Anything, hash).Return(token, nil)
+				repo.On("RevokeFamily", mock.Anything, "rt_family", mock.AnythingOfType("time.Time")).Return(1, nil)
+			},
+			wantErr: auth_token.ErrInvalidRefreshToken,
+		},
+		{
+			name: "異常系: 失効済みのトークン",
+			req:  &RefreshTokenRequest{RefreshToken: refreshValue},
+			setupMock: func(repo *MockRefreshTokenRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				token := newToken(time.Now().Add(time.Hour))
+				revokedAt := time.Now().Add(-time.Minute)
+				token.SetRevoked(&revokedAt, "")
+				repo.On("FindByHash", mock.Anything, hash).Return(token, nil)
+			},
+			wantErr: auth_token.ErrInvalidRefreshToken,
+		},
+		{
+			name: "異常系: 期限切れのトークン",
+			req:  &RefreshTokenRequest{RefreshToken: refreshValue},
+			setupMock: func(repo *MockRefreshTokenRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				repo.On("FindByHash", mock.Anything, hash).Return(newToken(time.Now().Add(-time.Minute)), nil)
+			},
+			wantErr: auth_token.ErrInvalidRefreshToken,
+		},
+		{
+			name: "異常系: 存在しないトークン",
+			req:  &RefreshTokenRequest{RefreshToken: refreshValue},
+			setupMock: func(repo *MockRefreshTokenRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				repo.On("FindByHash", mock.Anything, hash).Return(nil, auth_token.ErrRefreshTokenNotFound)
+			},
+			wantErr: auth_token.ErrInvalidRefreshToken,
+		},
+		{
+			name:      "異常系: トークンが空",
+			req:       &RefreshTokenRequest{},
+			setupMock: func(repo *MockRefreshTokenRepository, mtm *MockTransactionManager) {},
+			wantErr:   auth_token.ErrInvalidRefreshToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRefreshTokenRepository)
+			mtm := new(MockTransactionManager)
+			tt.setupMock(repo, mtm)
+
+			svc := newTestAuthService(t, newTestJWTConfig(), repo, mtm, new(MockDenylist))
+
+			got, err := svc.RefreshToken(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				tt.checkFunc(t, got)
+			}
+			repo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestAuthApplicationService_RevokeToken(t *testing.T) {
+	jwtConfig := newTestJWTConfig()
+
+	signToken := func(secret string, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		require.NoError(t, err)
+		return token
+	}
+	expiresAt := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	accessToken := signToken(jwtConfig.Secret, jwt.MapClaims{
+		"user_id": "user123",
+		"jti":     "jti_123",
+		"iss":     jwtConfig.Issuer,
+		"exp":     expiresAt.Unix(),
+	})
+
+	tests := []struct {
+		name          string
+		token         string
+		setupMock     func(*MockRefreshTokenRepository, *MockDenylist)
+		wantErr       error
+		wantTokenType string
+	}{
+		{
+			name:  "正常系: アクセストークンを失効",
+			token: accessToken,
+			setupMock: func(repo *MockRefreshTokenRepository, d *MockDenylist) {
+				d.On("Add", mock.Anything, "jti_123", expiresAt).Return(nil)
+			},
+			wantTokenType: "access",
+		},
+		{
+			name:  "正常系: リフレッシュトークンの系列を失効",
+			token: "refresh-token-value",
+			setupMock: func(repo *MockRefreshTokenRepository, d *MockDenylist) {
+				token := auth_token.NewRefreshToken("rt_2", "user123", "rt_1", auth_token.HashToken("refresh-token-value"), time.Now().Add(time.Hour))
+				repo.On("FindByHash", mock.Anything, auth_token.HashToken("refresh-token-value")).Return(token, nil)
+				repo.On("RevokeFamily", mock.Anything, "rt_1", mock.AnythingOfType("time.Time")).Return(2, nil)
+			},
+			wantTokenType: "refresh",
+		},
+		{
+			name: "異常系: 署名が不正なアクセストークン",
+			token: signToken("other-secret", jwt.MapClaims{
+				"jti": "jti_123",
+				"iss": jwtConfig.Issuer,
+				"exp": expiresAt.Unix(),
+			}),
+			setupMock: func(repo *MockRefreshTokenRepository, d *MockDenylist) {},
+			wantErr:   auth_token.ErrInvalidToken,
+		},
+		{
+			name: "異常系: jtiのないアクセストークン",
+			token: signToken(jwtConfig.Secret, jwt.MapClaims{
+				"iss": jwtConfig.Issuer,
+				"exp": expiresAt.Unix(),
+			}),
+			setupMock: func(repo *MockRefreshTokenRepository, d *MockDenylist) {},
+			wantErr:   auth_token.ErrInvalidToken,
+		},
+		{
+			name:  "異常系: 存在しないリフレッシュトークン",
+			token: "unknown",
+			setupMock: func(repo *MockRefreshTokenRepository, d *MockDenylist) {
+				repo.On("FindByHash", mock.Anything, auth_token.HashToken("unknown")).Return(nil, auth_token.ErrRefreshTokenNotFound)
+			},
+			wantErr: auth_token.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRefreshTokenRepository)
+			d := new(MockDenylist)
+			tt.setupMock(repo, d)
+
+			svc := newTestAuthService(t, jwtConfig, repo, new(MockTransactionManager), d)
+
+			got, err := svc.RevokeToken(context.Background(), &RevokeTokenRequest{Token: tt.token})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantTokenType, got.TokenType)
+			}
+			repo.AssertExpectations(t)
+			d.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthApplicationService_RevokeUserTokens(t *testing.T) {
+	jwtConfig := newTestJWTConfig()
+	repo := new(MockRefreshTokenRepository)
+	d := new(MockDenylist)
+
+	repo.On("RevokeByUserID", mock.Anything, "user123", mock.AnythingOfType("time.Time")).Return(3, nil)
+	d.On("RevokeUser", mock.Anything, "user123", mock.AnythingOfType("time.Time"), mock.MatchedBy(func(until time.Time) bool {
+		// 発行済みのアクセストークンがすべて期限切れになるまで記録する
+		return until.Sub(time.Now()) > 14*time.Minute && until.Sub(time.Now()) <= 15*time.Minute
+	})).Return(nil)
+
+	svc := newTestAuthService(t, jwtConfig, repo, new(MockTransactionManager), d)
+
+	got, err := svc.RevokeUserTokens(context.Background(), &RevokeUserTokensRequest{UserID: "user123"})
+	require.NoError(t, err)
+	assert.Equal(t, "user123", got.UserID)
+	assert.Equal(t, 3, got.RevokedRefreshTokens)
+	repo.AssertExpectations(t)
+	d.AssertExpectations(t)
+
+	_, err = svc.RevokeUserTokens(context.Background(), &RevokeUserTokensRequest{})
+	assert.Error(t, err)
+}
+
+func TestAuthApplicationService_SigningKeys(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key-1.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	jwtConfig := newTestJWTConfig()
+	jwtConfig.Secret = ""
+	jwtConfig.SigningKeyFiles = []string{path}
+
+	repo := new(MockRefreshTokenRepository)
+	d := new(MockDenylist)
+	repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	d.On("Add", mock.Anything, mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+
+	svc := newTestAuthService(t, jwtConfig, repo, new(MockTransactionManager), d)
+	ctx := context.Background()
+
+	// 署名鍵のkidを付与してEdDSAで署名する
+	resp, err := svc.GenerateToken(ctx, &GenerateTokenRequest{UserID: "user123"})
+	require.NoError(t, err)
+	token, _, err := jwt.NewParser().ParseUnverified(resp.Token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+	assert.Equal(t, "key-1", token.Header["kid"])
+
+	// 公開鍵で検証できるアクセストークンを失効できる
+	revoked, err := svc.RevokeToken(ctx, &RevokeTokenRequest{Token: resp.Token})
+	require.NoError(t, err)
+	assert.Equal(t, "access", revoked.TokenType)
+
+	jwks := svc.GetJWKS(ctx)
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "key-1", jwks.Keys[0].KeyID)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+
+	repo.AssertExpectations(t)
+	d.AssertExpectations(t)
+}
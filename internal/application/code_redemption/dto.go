@@ -13,6 +13,7 @@ type RedeemCodeRequest struct {
 }
 
 // RedeemCodeResponse コード引き換えレスポンス
+// TransactionID・CurrencyType・Amount・BalanceAfterは先頭の報酬の内容
 type RedeemCodeResponse struct {
 	RedemptionID  string
 	TransactionID string
@@ -20,19 +21,40 @@ type RedeemCodeResponse struct {
 	CurrencyType  string
 	Amount        int64
 	BalanceAfter  int64
+	Rewards       []RedeemedReward
 	Status        string
 }
 
+// RedeemedReward 引き換えで付与した報酬1件分の結果
+type RedeemedReward struct {
+	TransactionID string
+	CurrencyType  string
+	Amount        int64
+	BalanceAfter  int64
+}
+
+// RewardLine 引き換えコードの報酬1件分
+type RewardLine struct {
+	CurrencyType string
+	Amount       int64
+}
+
+// Eligibility 引き換えコードを引き換えできるユーザーの条件
+type Eligibility = redemption_code.Eligibility
+
 // CreateCodeRequest 引き換えコード作成リクエスト
 type CreateCodeRequest struct {
 	Code         string
 	CodeType     string
 	CurrencyType string
 	Amount       int64
+	Rewards      []RewardLine // 指定時はCurrencyTypeとAmountの代わりに使用
 	MaxUses      int
 	ValidFrom    time.Time
 	ValidUntil   time.Time
 	Metadata     map[string]interface{}
+	CampaignID   string
+	Eligibility  Eligibility
 }
 
 // CreateCodeResponse 引き換えコード作成レスポンス
@@ -41,15 +63,83 @@ type CreateCodeResponse struct {
 	CodeType     string
 	CurrencyType string
 	Amount       int64
+	Rewards      []RewardLine
 	MaxUses      int
 	CurrentUses  int
 	ValidFrom    time.Time
 	ValidUntil   time.Time
 	Status       string
 	Metadata     map[string]interface{}
+	CampaignID   string
+	Eligibility  Eligibility
 	CreatedAt    time.Time
 }
 
+// GenerateCodesRequest 引き換えコード一括生成リクエスト
+type GenerateCodesRequest struct {
+	BatchID      string // 省略時は自動生成
+	Count        int
+	Prefix       string
+	Length       int
+	Alphabet     string // 省略時はredemption_code.DefaultCodeAlphabet
+	CodeType     string
+	CurrencyType string
+	Amount       int64
+	Rewards      []RewardLine // 指定時はCurrencyTypeとAmountの代わりに使用
+	MaxUses      int
+	ValidFrom    time.Time
+	ValidUntil   time.Time
+	Metadata     map[string]interface{}
+	CampaignID   string
+	Eligibility  Eligibility
+}
+
+// GenerateCodesResponse 引き換えコード一括生成レスポンス
+type GenerateCodesResponse struct {
+	BatchID string
+	Codes   []*redemption_code.RedemptionCode
+}
+
+// ExportBatchCodesRequest 一括生成した引き換えコードのエクスポートリクエスト
+type ExportBatchCodesRequest struct {
+	BatchID string
+}
+
+// ExportBatchCodesResponse 一括生成した引き換えコードのエクスポートレスポンス
+type ExportBatchCodesResponse struct {
+	Count int
+}
+
+// UpdateCodeRequest 引き換えコード更新リクエスト（nilのフィールドは変更しない）
+type UpdateCodeRequest struct {
+	Code       string
+	Status     *string // "active", "expired", "disabled"
+	ValidUntil *time.Time
+	MaxUses    *int   // 0 = 無制限
+	Reason     string // 変更理由（任意）
+	Requester  string // 必須（オペレーターやツール名など）
+}
+
+// CodeChange 変更されたフィールドの変更前後の値
+type CodeChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// UpdateCodeResponse 引き換えコード更新レスポンス
+type UpdateCodeResponse struct {
+	Code        string
+	Status      string
+	MaxUses     int
+	CurrentUses int
+	ValidFrom   time.Time
+	ValidUntil  time.Time
+	AuditID     string // 値が変わらなかった場合は空
+	Changes     []CodeChange
+	UpdatedAt   time.Time
+}
+
 // DeleteCodeRequest 引き換えコード削除リクエスト
 type DeleteCodeRequest struct {
 	Code string
@@ -61,6 +151,17 @@ type DeleteCodeResponse struct {
 	DeletedAt time.Time
 }
 
+// ClearRedemptionLockoutRequest 引き換えロックアウト解除リクエスト
+type ClearRedemptionLockoutRequest struct {
+	UserID string
+}
+
+// ClearRedemptionLockoutResponse 引き換えロックアウト解除レスポンス
+type ClearRedemptionLockoutResponse struct {
+	UserID    string
+	ClearedAt time.Time
+}
+
 // GetCodeRequest 引き換えコード取得リクエスト
 type GetCodeRequest struct {
 	Code string
@@ -72,22 +173,32 @@ type GetCodeResponse struct {
 	CodeType     string
 	CurrencyType string
 	Amount       int64
+	Rewards      []RewardLine
 	MaxUses      int
 	CurrentUses  int
 	ValidFrom    time.Time
 	ValidUntil   time.Time
 	Status       string
 	Metadata     map[string]interface{}
+	BatchID      string
+	CampaignID   string
+	Eligibility  Eligibility
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
 
 // ListCodesRequest 引き換えコード一覧取得リクエスト
 type ListCodesRequest struct {
-	Limit    int
-	Offset   int
-	Status   string // optional: "active", "expired", "disabled"
-	CodeType string // optional: "promotion", "gift", "event"
+	Limit        int
+	Offset       int
+	Status       string     // optional: "active", "expired", "disabled"
+	CodeType     string     // optional: "promotion", "gift", "event"
+	CurrencyType string     // optional: "paid", "free"（報酬に含むコード）
+	CampaignID   string     // optional: campaign_idまたはmetadataのcampaign_id
+	CodePrefix   string     // optional: コードの前方一致
+	ValidFrom    *time.Time // optional: 有効期間がValidFrom以降と重なるコード
+	ValidUntil   *time.Time // optional: 有効期間がValidUntil以前と重なるコード
+	Sort         string     // optional: "created_at_desc"（デフォルト）, "created_at_asc", "code_asc", "valid_until_asc", "valid_until_desc"
 }
 
 // ListCodesResponse 引き換えコード一覧取得レスポンス
@@ -97,3 +208,59 @@ type ListCodesResponse struct {
 	Limit  int
 	Offset int
 }
+
+// ListCodeRedemptionsRequest 引き換えコードの引き換え履歴取得リクエスト
+type ListCodeRedemptionsRequest struct {
+	Code   string
+	Limit  int
+	Offset int
+}
+
+// ListUserRedemptionsRequest ユーザーの引き換え履歴取得リクエスト
+type ListUserRedemptionsRequest struct {
+	UserID string
+	Limit  int
+	Offset int
+}
+
+// ListRedemptionsResponse 引き換え履歴取得レスポンス（新しい順）
+type ListRedemptionsResponse struct {
+	Redemptions []*redemption_code.CodeRedemption
+	Total       int
+	Limit       int
+	Offset      int
+}
+
+// GetCodeStatsRequest 引き換えコードの利用統計取得リクエスト
+type GetCodeStatsRequest struct {
+	Code string
+}
+
+// DailyRedemptions 1日分の引き換え回数
+type DailyRedemptions = redemption_code.DailyRedemptions
+
+// GetCodeStatsResponse 引き換えコードの利用統計取得レスポンス
+type GetCodeStatsResponse struct {
+	Code         string
+	MaxUses      int
+	CurrentUses  int
+	Redemptions  int
+	UniqueUsers  int
+	TotalGranted []RewardLine // 通貨タイプごとの付与した通貨の合計（引き換えで記録したトランザクションの金額の合計）
+	Daily        []DailyRedemptions
+}
+
+// ExpirySweepRequester 定期ジョブによる失効の変更履歴に記録するリクエスト元
+const ExpirySweepRequester = "system:code_expiry"
+
+// ExpireCodesRequest 期限切れコードの一括失効リクエスト
+type ExpireCodesRequest struct {
+	Limit     int    // 1回の実行で処理するコードの最大数（0以下の場合はDefaultExpireCodesLimit）
+	Requester string // 変更履歴に記録するリクエスト元（省略時はExpirySweepRequester）
+}
+
+// ExpireCodesResponse 期限切れコードの一括失効レスポンス
+type ExpireCodesResponse struct {
+	ExpiredCodes int
+	Codes        []string // 失効させたコード（有効期限の昇順）
+}
@@ -0,0 +1,64 @@
+package code_redemption
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gem-server/internal/domain/redemption_code"
+)
+
+// batchCodeColumns 一括生成したコードのCSVのヘッダー行
+var batchCodeColumns = []string{
+	"code",
+	"batch_id",
+	"code_type",
+	"currency_type",
+	"amount",
+	"rewards",
+	"max_uses",
+	"current_uses",
+	"valid_from",
+	"valid_until",
+	"status",
+}
+
+// writeBatchCodesCSV 一括生成したコードをヘッダー行付きのCSVで書き出す
+func writeBatchCodesCSV(w io.Writer, codes []*redemption_code.RedemptionCode) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(batchCodeColumns); err != nil {
+		return err
+	}
+
+	for _, code := range codes {
+		if err := cw.Write([]string{
+			code.Code(),
+			code.BatchID(),
+			code.CodeType().String(),
+			code.CurrencyType().String(),
+			strconv.FormatInt(code.Amount(), 10),
+			formatRewards(code.Rewards()),
+			strconv.Itoa(code.MaxUses()),
+			strconv.Itoa(code.CurrentUses()),
+			code.ValidFrom().UTC().Format(time.RFC3339),
+			code.ValidUntil().UTC().Format(time.RFC3339),
+			code.Status().String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatRewards 報酬の一覧を "free:100;paid:10" の形式に変換
+func formatRewards(rewards []redemption_code.Reward) string {
+	parts := make([]string, len(rewards))
+	for i, r := range rewards {
+		parts[i] = r.CurrencyType().String() + ":" + strconv.FormatInt(r.Amount(), 10)
+	}
+	return strings.Join(parts, ";")
+}
@@ -2,8 +2,9 @@ package code_redemption
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"time"
 
@@ -13,17 +14,32 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/redemption_code"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 )
 
+const (
+	// MaxGenerateCodesCount 1回の一括生成で作成できるコードの最大件数
+	MaxGenerateCodesCount = 10000
+	// maxBatchIDLength バッチIDの最大文字数
+	maxBatchIDLength = 255
+	// maxGenerateAttempts 既存のコードと衝突した場合に生成し直す最大回数
+	maxGenerateAttempts = 3
+	// DefaultExpireCodesLimit 期限切れコードの一括失効で1回に処理するコードのデフォルト件数
+	DefaultExpireCodesLimit = 500
+)
+
 // CodeRedemptionApplicationService コード引き換えアプリケーションサービス
 type CodeRedemptionApplicationService struct {
 	currencyRepo       currency.CurrencyRepository
 	transactionRepo    transaction.TransactionRepository
 	redemptionCodeRepo redemption_code.RedemptionCodeRepository
 	txManager          transaction.TransactionManager
+	idGenerator        idgen.Generator
+	failureTracker     redemption_code.FailureTracker
+	expiryLock         redemption_code.ExpiryLock
 	logger             *otelinfra.Logger
 	metrics            *otelinfra.Metrics
 	tracer             trace.Tracer
@@ -31,11 +47,16 @@ type CodeRedemptionApplicationService struct {
 }
 
 // NewCodeRedemptionApplicationService 新しいCodeRedemptionApplicationServiceを作成
+// failureTrackerがnilの場合は引き換え失敗によるロックアウトを行わない
+// expiryLockがnilの場合は期限切れコードの一括失効をロックせずに実行する（単一インスタンス用）
 func NewCodeRedemptionApplicationService(
 	currencyRepo currency.CurrencyRepository,
 	transactionRepo transaction.TransactionRepository,
 	redemptionCodeRepo redemption_code.RedemptionCodeRepository,
 	txManager transaction.TransactionManager,
+	idGenerator idgen.Generator,
+	failureTracker redemption_code.FailureTracker,
+	expiryLock redemption_code.ExpiryLock,
 	logger *otelinfra.Logger,
 	metrics *otelinfra.Metrics,
 ) *CodeRedemptionApplicationService {
@@ -44,6 +65,9 @@ func NewCodeRedemptionApplicationService(
 		transactionRepo:    transactionRepo,
 		redemptionCodeRepo: redemptionCodeRepo,
 		txManager:          txManager,
+		idGenerator:        idGenerator,
+		failureTracker:     failureTracker,
+		expiryLock:         expiryLock,
 		logger:             logger,
 		metrics:            metrics,
 		tracer:             otel.Tracer("code-redemption-service"),
@@ -66,10 +90,18 @@ func (s *CodeRedemptionApplicationService) Redeem(ctx context.Context, req *Rede
 		"user_id": req.UserID,
 	})
 
+	// 総当たり対策: ロックアウト中のユーザーはコードの存在を確認させない
+	if err := s.checkLockout(ctx, req.UserID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
 	// コードを取得
 	code, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code)
 	if err != nil {
 		if err == redemption_code.ErrCodeNotFound {
+			s.recordFailure(ctx, req.UserID)
 			span.RecordError(err)
 			span.SetStatus(otelcodes.Error, err.Error())
 			return nil, err
@@ -82,6 +114,7 @@ func (s *CodeRedemptionApplicationService) Redeem(ctx context.Context, req *Rede
 	// コードの有効性チェック
 	if !code.IsValid() {
 		err := redemption_code.ErrCodeNotRedeemable
+		s.recordFailure(ctx, req.UserID)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, err
@@ -104,127 +137,92 @@ func (s *CodeRedemptionApplicationService) Redeem(ctx context.Context, req *Rede
 	// 引き換え可能かチェック
 	if !code.CanBeRedeemed() {
 		err := redemption_code.ErrCodeNotRedeemable
+		s.recordFailure(ctx, req.UserID)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	// 引き換え条件のチェック
+	if err := s.checkEligibility(ctx, code, req.UserID); err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, err
 	}
 
-	// トランザクションIDを生成
-	transactionID := s.generateTransactionID()
+	// 報酬ごとにトランザクションIDを生成
+	rewards := code.Rewards()
+	transactionIDs := make([]string, len(rewards))
+	for i := range rewards {
+		transactionIDs[i] = s.generateTransactionID()
+	}
 	redemptionID := s.generateRedemptionID()
 
 	var result *RedeemCodeResponse
 
-	err = s.txManager.WithTransaction(ctx, func(tx *sql.Tx) error {
-		// コードを引き換え（使用回数を増やす）
-		if err := code.Redeem(); err != nil {
-			return err
-		}
-
-		// コードを更新
-		if err := s.redemptionCodeRepo.Update(ctx, code); err != nil {
-			return fmt.Errorf("failed to update code: %w", err)
-		}
-
-		// 通貨を付与
-		currencyType := code.CurrencyType()
-		amount := code.Amount()
-
-		// 楽観的ロックのリトライロジック
-		var retryErr error
-		for attempt := 0; attempt < s.maxRetries; attempt++ {
-			if attempt > 0 {
-				backoff := time.Duration(math.Pow(2, float64(attempt-1))) * 10 * time.Millisecond
-				time.Sleep(backoff)
-			}
-
-			// 通貨を取得
-			c, err := s.currencyRepo.FindByUserIDAndType(ctx, req.UserID, currencyType)
-			if err != nil && err != currency.ErrCurrencyNotFound {
-				return fmt.Errorf("failed to find currency: %w", err)
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// キャンペーン内の引き換え回数の条件は、ユーザーをロックしてから数え直す
+		// （同じユーザーが同時に引き換えた場合に、事前のチェックをすり抜けて上限を超えないようにする）
+		if code.Eligibility().RequiresCampaignUsage() {
+			if err := s.redemptionCodeRepo.LockUser(ctx, req.UserID); err != nil {
+				return err
 			}
-
-			var balanceBefore int64
-			if c == nil {
-				// 通貨が存在しない場合は作成
-				c, err = currency.NewCurrency(req.UserID, currencyType, 0, 0)
-				if err != nil {
-					return fmt.Errorf("failed to create currency entity: %w", err)
-				}
-				if err := s.currencyRepo.Create(ctx, c); err != nil {
-					return fmt.Errorf("failed to create currency: %w", err)
-				}
-			} else {
-				balanceBefore = c.Balance()
+			usage, err := s.redemptionCodeRepo.CountUserCampaignRedemptions(ctx, code.CampaignID(), req.UserID)
+			if err != nil {
+				return fmt.Errorf("failed to count campaign redemptions: %w", err)
 			}
-
-			// 通貨を付与
-			if err := c.Grant(amount); err != nil {
+			if err := code.Eligibility().CheckCampaignUsage(usage); err != nil {
 				return err
 			}
+		}
 
-			// 保存（楽観的ロック）
-			if err := s.currencyRepo.Save(ctx, c); err != nil {
-				if attempt < s.maxRetries-1 {
-					retryErr = err
-					continue
-				}
-				return fmt.Errorf("failed to save currency after retries: %w", err)
+		// 使用回数を増やす（同時の引き換えで上限を超えないよう、DB上で条件付きで加算する）
+		if err := s.redemptionCodeRepo.IncrementUses(ctx, req.Code); err != nil {
+			if errors.Is(err, redemption_code.ErrCodeNotRedeemable) {
+				return err
 			}
+			return fmt.Errorf("failed to update code: %w", err)
+		}
 
-			// トランザクション履歴を記録
-			txn, err := transaction.NewTransaction(
-				transactionID,
-				req.UserID,
-				transaction.TransactionTypeGrant,
-				currencyType,
-				amount,
-				balanceBefore,
-				c.Balance(),
-				transaction.TransactionStatusCompleted,
-				map[string]interface{}{
-					"code":          req.Code,
-					"redemption_id": redemptionID,
-				},
-			)
+		// すべての報酬を付与（報酬ごとにトランザクション履歴を記録）
+		redeemed := make([]RedeemedReward, 0, len(rewards))
+		for i, reward := range rewards {
+			balanceAfter, err := s.grantReward(ctx, req, reward, transactionIDs[i], redemptionID)
 			if err != nil {
-				return fmt.Errorf("failed to create transaction entity: %w", err)
-			}
-
-			if err := s.transactionRepo.Save(ctx, txn); err != nil {
-				return fmt.Errorf("failed to save transaction: %w", err)
+				return err
 			}
+			redeemed = append(redeemed, RedeemedReward{
+				TransactionID: transactionIDs[i],
+				CurrencyType:  reward.CurrencyType().String(),
+				Amount:        reward.Amount(),
+				BalanceAfter:  balanceAfter,
+			})
+		}
 
-			// 引き換え履歴を記録
-			redemption := redemption_code.NewCodeRedemption(
-				redemptionID,
-				req.Code,
-				req.UserID,
-				transactionID,
-			)
-
-			if err := s.redemptionCodeRepo.SaveRedemption(ctx, redemption); err != nil {
-				return fmt.Errorf("failed to save redemption: %w", err)
-			}
+		// 引き換え履歴を記録（先頭の報酬のトランザクションを参照）
+		redemption := redemption_code.NewCodeRedemption(
+			redemptionID,
+			req.Code,
+			req.UserID,
+			transactionIDs[0],
+		)
 
-			// メトリクス記録
-			s.metrics.RecordTransaction(ctx, "grant", currencyType.String())
-			s.metrics.RecordCurrencyBalance(ctx, req.UserID, currencyType.String(), c.Balance())
-
-			result = &RedeemCodeResponse{
-				RedemptionID:  redemptionID,
-				TransactionID: transactionID,
-				Code:          req.Code,
-				CurrencyType:  currencyType.String(),
-				Amount:        amount,
-				BalanceAfter:  c.Balance(),
-				Status:        "completed",
-			}
+		if err := s.redemptionCodeRepo.SaveRedemption(ctx, redemption); err != nil {
+			return fmt.Errorf("failed to save redemption: %w", err)
+		}
 
-			return nil
+		result = &RedeemCodeResponse{
+			RedemptionID:  redemptionID,
+			TransactionID: redeemed[0].TransactionID,
+			Code:          req.Code,
+			CurrencyType:  redeemed[0].CurrencyType,
+			Amount:        redeemed[0].Amount,
+			BalanceAfter:  redeemed[0].BalanceAfter,
+			Rewards:       redeemed,
+			Status:        "completed",
 		}
 
-		return retryErr
+		return nil
 	})
 
 	if err != nil {
@@ -239,23 +237,232 @@ func (s *CodeRedemptionApplicationService) Redeem(ctx context.Context, req *Rede
 	}
 
 	s.logger.Info(ctx, "Code redeemed successfully", map[string]interface{}{
-		"code":           req.Code,
-		"user_id":        req.UserID,
-		"redemption_id":  redemptionID,
-		"transaction_id": transactionID,
+		"code":            req.Code,
+		"user_id":         req.UserID,
+		"redemption_id":   redemptionID,
+		"transaction_ids": transactionIDs,
 	})
 
 	return result, nil
 }
 
+// grantReward 報酬1件分の通貨を付与し、トランザクション履歴を記録する
+// 楽観的ロックの競合時はリトライし、付与後の残高を返す
+func (s *CodeRedemptionApplicationService) grantReward(
+	ctx context.Context,
+	req *RedeemCodeRequest,
+	reward redemption_code.Reward,
+	transactionID string,
+	redemptionID string,
+) (int64, error) {
+	currencyType := reward.CurrencyType()
+	amount := reward.Amount()
+
+	// 楽観的ロックのリトライロジック
+	var retryErr error
+	for attempt := 0; attempt < s.maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * 10 * time.Millisecond
+			time.Sleep(backoff)
+		}
+
+		// 通貨を取得
+		c, err := s.currencyRepo.FindByUserIDAndType(ctx, req.UserID, currencyType)
+		if err != nil && err != currency.ErrCurrencyNotFound {
+			return 0, fmt.Errorf("failed to find currency: %w", err)
+		}
+
+		var balanceBefore int64
+		if c == nil {
+			// 通貨が存在しない場合は作成
+			c, err = currency.NewCurrency(req.UserID, currencyType, 0, 0)
+			if err != nil {
+				return 0, fmt.Errorf("failed to create currency entity: %w", err)
+			}
+			if err := s.currencyRepo.Create(ctx, c); err != nil {
+				return 0, fmt.Errorf("failed to create currency: %w", err)
+			}
+		} else {
+			balanceBefore = c.Balance()
+		}
+
+		// 通貨を付与
+		if err := c.Grant(amount); err != nil {
+			return 0, err
+		}
+
+		// 保存（楽観的ロック）
+		if err := s.currencyRepo.Save(ctx, c); err != nil {
+			if attempt < s.maxRetries-1 {
+				retryErr = err
+				continue
+			}
+			return 0, fmt.Errorf("failed to save currency after retries: %w", err)
+		}
+
+		// トランザクション履歴を記録
+		txn, err := transaction.NewTransaction(
+			transactionID,
+			req.UserID,
+			transaction.TransactionTypeGrant,
+			currencyType,
+			amount,
+			balanceBefore,
+			c.Balance(),
+			transaction.TransactionStatusCompleted,
+			map[string]interface{}{
+				"code":          req.Code,
+				"redemption_id": redemptionID,
+			},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create transaction entity: %w", err)
+		}
+
+		if err := s.transactionRepo.Save(ctx, txn); err != nil {
+			return 0, fmt.Errorf("failed to save transaction: %w", err)
+		}
+
+		// メトリクス記録
+		s.metrics.RecordTransaction(ctx, "grant", currencyType.String())
+		s.metrics.RecordCurrencyBalance(ctx, req.UserID, currencyType.String(), c.Balance())
+
+		return c.Balance(), nil
+	}
+
+	return 0, retryErr
+}
+
+// checkEligibility コードの引き換え条件をユーザーが満たしているかチェック
+// 満たしていない場合はどの条件で拒否されたかを表すドメインエラーを返す
+func (s *CodeRedemptionApplicationService) checkEligibility(ctx context.Context, code *redemption_code.RedemptionCode, userID string) error {
+	eligibility := code.Eligibility()
+	if eligibility.IsZero() {
+		return nil
+	}
+
+	err := eligibility.CheckUser(userID)
+
+	if err == nil && eligibility.RequiresFirstSeen() {
+		firstSeenAt, findErr := s.redemptionCodeRepo.FindUserFirstSeenAt(ctx, userID)
+		if findErr != nil {
+			return fmt.Errorf("failed to find user: %w", findErr)
+		}
+		err = eligibility.CheckFirstSeen(firstSeenAt)
+	}
+
+	if err == nil && eligibility.RequiresCampaignUsage() {
+		usage, countErr := s.redemptionCodeRepo.CountUserCampaignRedemptions(ctx, code.CampaignID(), userID)
+		if countErr != nil {
+			return fmt.Errorf("failed to count campaign redemptions: %w", countErr)
+		}
+		err = eligibility.CheckCampaignUsage(usage)
+	}
+
+	if err != nil {
+		s.logger.Warn(ctx, "Redemption blocked by eligibility rule", map[string]interface{}{
+			"code":        code.Code(),
+			"user_id":     userID,
+			"campaign_id": code.CampaignID(),
+			"error":       err.Error(),
+		})
+	}
+	return err
+}
+
+// checkLockout ユーザーがロックアウト中の場合はLockoutErrorを返す
+// ロックアウトの状態を取得できない場合は引き換えを許可する
+func (s *CodeRedemptionApplicationService) checkLockout(ctx context.Context, userID string) error {
+	if s.failureTracker == nil {
+		return nil
+	}
+
+	until, err := s.failureTracker.LockedUntil(ctx, userID)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to check redemption lockout", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return nil
+	}
+	if until.IsZero() {
+		return nil
+	}
+
+	s.logger.Warn(ctx, "Redemption attempt while locked out", map[string]interface{}{
+		"user_id":      userID,
+		"locked_until": until,
+	})
+	return &redemption_code.LockoutError{Until: until}
+}
+
+// recordFailure コードが見つからない・引き換えできない失敗を記録する
+func (s *CodeRedemptionApplicationService) recordFailure(ctx context.Context, userID string) {
+	if s.failureTracker == nil {
+		return
+	}
+
+	until, err := s.failureTracker.RecordFailure(ctx, userID)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to record redemption failure", map[string]interface{}{
+			"user_id": userID,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if until.IsZero() {
+		return
+	}
+
+	s.logger.Warn(ctx, "User locked out of code redemption", map[string]interface{}{
+		"user_id":      userID,
+		"locked_until": until,
+	})
+	s.metrics.RecordRedemptionLockout(ctx)
+}
+
+// ClearRedemptionLockout ユーザーの引き換え失敗の記録とロックアウトを解除
+func (s *CodeRedemptionApplicationService) ClearRedemptionLockout(ctx context.Context, req *ClearRedemptionLockoutRequest) (*ClearRedemptionLockoutResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.ClearRedemptionLockout")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user_id", req.UserID),
+	)
+
+	if req.UserID == "" {
+		err := fmt.Errorf("user_id is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	if s.failureTracker != nil {
+		if err := s.failureTracker.Clear(ctx, req.UserID); err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+	}
+
+	s.logger.Info(ctx, "Redemption lockout cleared", map[string]interface{}{
+		"user_id": req.UserID,
+	})
+
+	return &ClearRedemptionLockoutResponse{
+		UserID:    req.UserID,
+		ClearedAt: time.Now(),
+	}, nil
+}
+
 // generateTransactionID トランザクションIDを生成
 func (s *CodeRedemptionApplicationService) generateTransactionID() string {
-	return fmt.Sprintf("txn_%d", time.Now().UnixNano())
+	return "txn_" + s.idGenerator.NewID()
 }
 
 // generateRedemptionID 引き換えIDを生成
 func (s *CodeRedemptionApplicationService) generateRedemptionID() string {
-	return fmt.Sprintf("red_%d", time.Now().UnixNano())
+	return "red_" + s.idGenerator.NewID()
 }
 
 // CreateCode 引き換えコードを作成
@@ -289,7 +496,7 @@ func (s *CodeRedemptionApplicationService) CreateCode(ctx context.Context, req *
 	}
 
 	if req.ValidUntil.Before(req.ValidFrom) {
-		err := fmt.Errorf("valid_until must be after valid_from")
+		err := fmt.Errorf("%w: valid_until must be after valid_from", redemption_code.ErrInvalidCodeSettings)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, err
@@ -303,7 +510,7 @@ func (s *CodeRedemptionApplicationService) CreateCode(ctx context.Context, req *
 	}
 
 	if req.MaxUses < 0 {
-		err := fmt.Errorf("max_uses must be non-negative")
+		err := fmt.Errorf("%w: max_uses must be non-negative", redemption_code.ErrInvalidCodeSettings)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, err
@@ -317,20 +524,19 @@ func (s *CodeRedemptionApplicationService) CreateCode(ctx context.Context, req *
 		return nil, fmt.Errorf("invalid code type: %w", err)
 	}
 
-	// 通貨タイプのバリデーション
-	currencyType, err := currency.NewCurrencyType(req.CurrencyType)
+	// 報酬のバリデーション
+	rewards, err := parseRewards(req.CurrencyType, req.Amount, req.Rewards)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, fmt.Errorf("invalid currency type: %w", err)
+		return nil, err
 	}
 
 	// ドメインエンティティの作成
-	rc, err := redemption_code.NewRedemptionCode(
+	rc, err := redemption_code.NewRedemptionCodeWithRewards(
 		req.Code,
 		codeType,
-		currencyType,
-		req.Amount,
+		rewards,
 		req.MaxUses,
 		req.ValidFrom,
 		req.ValidUntil,
@@ -342,6 +548,13 @@ func (s *CodeRedemptionApplicationService) CreateCode(ctx context.Context, req *
 		return nil, fmt.Errorf("failed to create redemption code entity: %w", err)
 	}
 
+	// キャンペーンと引き換え条件を設定
+	if err := rc.SetEligibility(req.CampaignID, req.Eligibility); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
 	// リポジトリに保存
 	if err := s.redemptionCodeRepo.Create(ctx, rc); err != nil {
 		if err == redemption_code.ErrCodeAlreadyExists {
@@ -366,127 +579,653 @@ func (s *CodeRedemptionApplicationService) CreateCode(ctx context.Context, req *
 		CodeType:     rc.CodeType().String(),
 		CurrencyType: rc.CurrencyType().String(),
 		Amount:       rc.Amount(),
+		Rewards:      ToRewardLines(rc.Rewards()),
 		MaxUses:      rc.MaxUses(),
 		CurrentUses:  rc.CurrentUses(),
 		ValidFrom:    rc.ValidFrom(),
 		ValidUntil:   rc.ValidUntil(),
 		Status:       rc.Status().String(),
 		Metadata:     rc.Metadata(),
+		CampaignID:   rc.CampaignID(),
+		Eligibility:  rc.Eligibility(),
 		CreatedAt:    rc.CreatedAt(),
 	}, nil
 }
 
-// DeleteCode 引き換えコードを削除
-func (s *CodeRedemptionApplicationService) DeleteCode(ctx context.Context, req *DeleteCodeRequest) (*DeleteCodeResponse, error) {
-	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.DeleteCode")
+// GenerateCodes パターンからランダムな引き換えコードを一括生成
+// 生成したコードは同じ設定とバッチIDを持ち、1つのDBトランザクションでまとめて作成される
+func (s *CodeRedemptionApplicationService) GenerateCodes(ctx context.Context, req *GenerateCodesRequest) (*GenerateCodesResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.GenerateCodes")
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("code", req.Code),
+		attribute.Int("count", req.Count),
+		attribute.String("prefix", req.Prefix),
+		attribute.Int("length", req.Length),
+		attribute.String("code_type", req.CodeType),
+		attribute.String("currency_type", req.CurrencyType),
+		attribute.Int64("amount", req.Amount),
 	)
 
-	s.logger.Info(ctx, "Deleting redemption code", map[string]interface{}{
-		"code": req.Code,
+	s.logger.Info(ctx, "Generating redemption codes", map[string]interface{}{
+		"batch_id":      req.BatchID,
+		"count":         req.Count,
+		"prefix":        req.Prefix,
+		"length":        req.Length,
+		"code_type":     req.CodeType,
+		"currency_type": req.CurrencyType,
+		"amount":        req.Amount,
+		"max_uses":      req.MaxUses,
+		"valid_from":    req.ValidFrom,
+		"valid_until":   req.ValidUntil,
 	})
 
 	// バリデーション
-	if req.Code == "" {
-		err := fmt.Errorf("code is required")
+	if req.Count <= 0 || req.Count > MaxGenerateCodesCount {
+		err := fmt.Errorf("%w: count must be between 1 and %d", redemption_code.ErrInvalidBatchSize, MaxGenerateCodesCount)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, err
 	}
 
-	// コードの存在確認
-	_, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code)
-	if err != nil {
-		if err == redemption_code.ErrCodeNotFound {
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, err.Error())
-			return nil, err
-		}
+	if len(req.BatchID) > maxBatchIDLength {
+		err := fmt.Errorf("%w: batch_id must be at most %d characters", redemption_code.ErrInvalidBatchID, maxBatchIDLength)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, fmt.Errorf("failed to find code: %w", err)
+		return nil, err
 	}
 
-	// 削除実行（リポジトリ内で使用済みチェックも行う）
-	if err := s.redemptionCodeRepo.Delete(ctx, req.Code); err != nil {
-		if err == redemption_code.ErrCodeNotFound {
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, err.Error())
-			return nil, err
-		}
-		if err == redemption_code.ErrCodeCannotBeDeleted {
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, err.Error())
-			return nil, err
-		}
+	if req.ValidUntil.Before(req.ValidFrom) {
+		err := fmt.Errorf("%w: valid_until must be after valid_from", redemption_code.ErrInvalidCodeSettings)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		s.logger.Error(ctx, "Failed to delete redemption code", err, map[string]interface{}{
-			"code": req.Code,
-		})
-		return nil, fmt.Errorf("failed to delete redemption code: %w", err)
+		return nil, err
 	}
 
-	s.logger.Info(ctx, "Redemption code deleted successfully", map[string]interface{}{
-		"code": req.Code,
-	})
-
-	return &DeleteCodeResponse{
-		Code:      req.Code,
-		DeletedAt: time.Now(),
-	}, nil
-}
+	if len(req.Rewards) == 0 && req.Amount <= 0 {
+		err := fmt.Errorf("%w: amount must be positive", currency.ErrInvalidAmount)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
 
-// GetCode 引き換えコードを取得
-func (s *CodeRedemptionApplicationService) GetCode(ctx context.Context, req *GetCodeRequest) (*GetCodeResponse, error) {
-	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.GetCode")
-	defer span.End()
+	if req.MaxUses < 0 {
+		err := fmt.Errorf("%w: max_uses must be non-negative", redemption_code.ErrInvalidCodeSettings)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
 
-	span.SetAttributes(
-		attribute.String("code", req.Code),
-	)
+	// コードタイプのバリデーション
+	codeType, err := redemption_code.NewCodeType(req.CodeType)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("invalid code type: %w", err)
+	}
 
-	s.logger.Info(ctx, "Getting redemption code", map[string]interface{}{
-		"code": req.Code,
-	})
+	// 報酬のバリデーション
+	rewards, err := parseRewards(req.CurrencyType, req.Amount, req.Rewards)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
 
-	// バリデーション
-	if req.Code == "" {
-		err := fmt.Errorf("code is required")
+	// 引き換え条件のバリデーション
+	if err := req.Eligibility.Validate(req.CampaignID); err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, err
 	}
 
-	// コードを取得
-	code, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code)
+	// 生成パターンのバリデーション
+	pattern, err := redemption_code.NewCodePattern(req.Prefix, req.Length, req.Alphabet)
+	if err == nil {
+		err = pattern.ValidateCount(req.Count)
+	}
 	if err != nil {
-		if err == redemption_code.ErrCodeNotFound {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	batchID := req.BatchID
+	if batchID == "" {
+		batchID = s.generateBatchID()
+	}
+	span.SetAttributes(attribute.String("batch_id", batchID))
+
+	// 既存のコードと衝突した場合はバッチ全体を生成し直す
+	var codes []*redemption_code.RedemptionCode
+	for attempt := 1; ; attempt++ {
+		codes, err = s.buildBatchCodes(pattern, req, batchID, codeType, rewards)
+		if err != nil {
 			span.RecordError(err)
 			span.SetStatus(otelcodes.Error, err.Error())
 			return nil, err
 		}
+
+		err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			// 指定されたバッチIDが使用済みの場合、既存のバッチにコードを追加しない
+			if req.BatchID != "" {
+				existing, err := s.redemptionCodeRepo.FindByBatchID(ctx, batchID)
+				if err != nil {
+					return fmt.Errorf("failed to find code batch: %w", err)
+				}
+				if len(existing) > 0 {
+					return redemption_code.ErrBatchAlreadyExists
+				}
+			}
+			return s.redemptionCodeRepo.CreateBatch(ctx, codes)
+		})
+		if err == nil {
+			break
+		}
+		if errors.Is(err, redemption_code.ErrCodeAlreadyExists) && attempt < maxGenerateAttempts {
+			s.logger.Warn(ctx, "Generated code collided with an existing code, regenerating batch", map[string]interface{}{
+				"batch_id": batchID,
+				"attempt":  attempt,
+			})
+			continue
+		}
+
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, fmt.Errorf("failed to find code: %w", err)
+		s.logger.Error(ctx, "Failed to generate redemption codes", err, map[string]interface{}{
+			"batch_id": batchID,
+			"attempt":  attempt,
+		})
+		return nil, fmt.Errorf("failed to generate redemption codes: %w", err)
 	}
 
-	return &GetCodeResponse{
-		Code:         code.Code(),
-		CodeType:     code.CodeType().String(),
-		CurrencyType: code.CurrencyType().String(),
-		Amount:       code.Amount(),
-		MaxUses:      code.MaxUses(),
-		CurrentUses:  code.CurrentUses(),
-		ValidFrom:    code.ValidFrom(),
-		ValidUntil:   code.ValidUntil(),
-		Status:       code.Status().String(),
-		Metadata:     code.Metadata(),
-		CreatedAt:    code.CreatedAt(),
-		UpdatedAt:    code.UpdatedAt(),
+	s.logger.Info(ctx, "Redemption codes generated successfully", map[string]interface{}{
+		"batch_id": batchID,
+		"count":    len(codes),
+	})
+
+	return &GenerateCodesResponse{
+		BatchID: batchID,
+		Codes:   codes,
+	}, nil
+}
+
+// buildBatchCodes パターンから重複のないコードを生成し、エンティティを作成
+func (s *CodeRedemptionApplicationService) buildBatchCodes(
+	pattern redemption_code.CodePattern,
+	req *GenerateCodesRequest,
+	batchID string,
+	codeType redemption_code.CodeType,
+	rewards []redemption_code.Reward,
+) ([]*redemption_code.RedemptionCode, error) {
+	seen := make(map[string]bool, req.Count)
+	codes := make([]*redemption_code.RedemptionCode, 0, req.Count)
+	for len(codes) < req.Count {
+		value, err := pattern.Generate()
+		if err != nil {
+			return nil, err
+		}
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+
+		rc, err := redemption_code.NewRedemptionCodeWithRewards(
+			value,
+			codeType,
+			rewards,
+			req.MaxUses,
+			req.ValidFrom,
+			req.ValidUntil,
+			req.Metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redemption code entity: %w", err)
+		}
+		rc.SetBatchID(batchID)
+		if err := rc.SetEligibility(req.CampaignID, req.Eligibility); err != nil {
+			return nil, err
+		}
+		codes = append(codes, rc)
+	}
+	return codes, nil
+}
+
+// parseRewards リクエストの報酬指定を検証し、報酬の一覧に変換する
+// linesが空の場合はcurrencyTypeとamountの1件を報酬とする
+func parseRewards(currencyType string, amount int64, lines []RewardLine) ([]redemption_code.Reward, error) {
+	if len(lines) == 0 {
+		ct, err := currency.NewCurrencyType(currencyType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency type: %w", err)
+		}
+		if amount <= 0 {
+			return nil, fmt.Errorf("%w: amount must be positive", currency.ErrInvalidAmount)
+		}
+		reward, err := redemption_code.NewReward(ct, amount)
+		if err != nil {
+			return nil, err
+		}
+		return []redemption_code.Reward{reward}, nil
+	}
+
+	if currencyType != "" || amount != 0 {
+		return nil, fmt.Errorf("%w: specify either rewards or currency_type and amount", redemption_code.ErrInvalidRewards)
+	}
+
+	rewards := make([]redemption_code.Reward, 0, len(lines))
+	for _, line := range lines {
+		ct, err := currency.NewCurrencyType(line.CurrencyType)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid currency type: %s", redemption_code.ErrInvalidRewards, line.CurrencyType)
+		}
+		reward, err := redemption_code.NewReward(ct, line.Amount)
+		if err != nil {
+			return nil, err
+		}
+		rewards = append(rewards, reward)
+	}
+	return rewards, nil
+}
+
+// ToRewardLines 報酬の一覧をDTOに変換
+func ToRewardLines(rewards []redemption_code.Reward) []RewardLine {
+	lines := make([]RewardLine, len(rewards))
+	for i, r := range rewards {
+		lines[i] = RewardLine{
+			CurrencyType: r.CurrencyType().String(),
+			Amount:       r.Amount(),
+		}
+	}
+	return lines
+}
+
+// generateBatchID バッチIDを生成
+func (s *CodeRedemptionApplicationService) generateBatchID() string {
+	return "batch_" + s.idGenerator.NewID()
+}
+
+// ExportBatchCodes 一括生成した引き換えコードをCSVで書き出す
+func (s *CodeRedemptionApplicationService) ExportBatchCodes(ctx context.Context, req *ExportBatchCodesRequest, w io.Writer) (*ExportBatchCodesResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.ExportBatchCodes")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("batch_id", req.BatchID),
+	)
+
+	if req.BatchID == "" {
+		err := fmt.Errorf("batch_id is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	codes, err := s.redemptionCodeRepo.FindByBatchID(ctx, req.BatchID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to find batch codes: %w", err)
+	}
+	if len(codes) == 0 {
+		err := redemption_code.ErrBatchNotFound
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := writeBatchCodesCSV(w, codes); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to export batch codes", err, map[string]interface{}{
+			"batch_id": req.BatchID,
+		})
+		return nil, fmt.Errorf("failed to export batch codes: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("count", len(codes)))
+	s.logger.Info(ctx, "Batch codes exported", map[string]interface{}{
+		"batch_id": req.BatchID,
+		"count":    len(codes),
+	})
+
+	return &ExportBatchCodesResponse{
+		Count: len(codes),
+	}, nil
+}
+
+// UpdateCode 引き換えコードのステータス・有効期限・最大使用回数を更新し、変更履歴を記録する
+func (s *CodeRedemptionApplicationService) UpdateCode(ctx context.Context, req *UpdateCodeRequest) (*UpdateCodeResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.UpdateCode")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("code", req.Code),
+		attribute.String("requester", req.Requester),
+	)
+
+	s.logger.Info(ctx, "Updating redemption code", map[string]interface{}{
+		"code":      req.Code,
+		"requester": req.Requester,
+	})
+
+	// バリデーション
+	if req.Code == "" {
+		err := fmt.Errorf("code is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+	if req.Requester == "" {
+		err := fmt.Errorf("%w: requester is required", redemption_code.ErrInvalidCodeUpdate)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	update := redemption_code.CodeUpdate{
+		ValidUntil: req.ValidUntil,
+		MaxUses:    req.MaxUses,
+	}
+	if req.Status != nil {
+		status, err := redemption_code.NewCodeStatus(*req.Status)
+		if err != nil {
+			err = fmt.Errorf("%w: %v", redemption_code.ErrInvalidCodeUpdate, err)
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		update.Status = &status
+	}
+
+	// 読み込みから更新までを1つのDBトランザクションで行い、コードの行をロックして
+	// 同時に行われた変更を読み込んだ値で上書きしないようにする
+	var code *redemption_code.RedemptionCode
+	var changes []redemption_code.FieldChange
+	var auditID string
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		code, err = s.redemptionCodeRepo.FindByCode(ctx, req.Code)
+		if err != nil {
+			if err == redemption_code.ErrCodeNotFound {
+				return err
+			}
+			return fmt.Errorf("failed to find code: %w", err)
+		}
+
+		changes, err = code.ApplyUpdate(update)
+		if err != nil {
+			return err
+		}
+
+		// 値が変わった場合のみ更新し、変更履歴を記録
+		if len(changes) == 0 {
+			return nil
+		}
+		auditID = "audit_" + s.idGenerator.NewID()
+		auditLog := redemption_code.NewCodeAuditLog(auditID, code.Code(), redemption_code.AuditActionUpdate, changes, req.Reason, req.Requester)
+
+		if err := s.redemptionCodeRepo.UpdateSettings(ctx, code); err != nil {
+			return fmt.Errorf("failed to update redemption code: %w", err)
+		}
+		if err := s.redemptionCodeRepo.SaveAuditLog(ctx, auditLog); err != nil {
+			return fmt.Errorf("failed to update redemption code: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to update redemption code", err, map[string]interface{}{
+			"code": req.Code,
+		})
+		return nil, err
+	}
+
+	codeChanges := make([]CodeChange, len(changes))
+	for i, c := range changes {
+		codeChanges[i] = CodeChange{Field: c.Field, From: c.From, To: c.To}
+	}
+
+	s.logger.Info(ctx, "Redemption code updated successfully", map[string]interface{}{
+		"code":      req.Code,
+		"audit_id":  auditID,
+		"changes":   len(changes),
+		"requester": req.Requester,
+	})
+
+	return &UpdateCodeResponse{
+		Code:        code.Code(),
+		Status:      code.Status().String(),
+		MaxUses:     code.MaxUses(),
+		CurrentUses: code.CurrentUses(),
+		ValidFrom:   code.ValidFrom(),
+		ValidUntil:  code.ValidUntil(),
+		AuditID:     auditID,
+		Changes:     codeChanges,
+		UpdatedAt:   code.UpdatedAt(),
+	}, nil
+}
+
+// ExpireCodes 有効期限を過ぎたactiveのコードを期限切れにし、変更履歴を記録
+// 複数インスタンスで同時に実行しないよう、ロックを取得できない場合はErrExpirySweepInProgressを返す
+// コード1件ごとにDBトランザクションを分け、失敗した時点で処理を中断する
+func (s *CodeRedemptionApplicationService) ExpireCodes(ctx context.Context, req *ExpireCodesRequest) (*ExpireCodesResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.ExpireCodes")
+	defer span.End()
+
+	requester := req.Requester
+	if requester == "" {
+		requester = ExpirySweepRequester
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = DefaultExpireCodesLimit
+	}
+
+	span.SetAttributes(
+		attribute.Int("limit", limit),
+		attribute.String("requester", requester),
+	)
+
+	if s.expiryLock != nil {
+		release, acquired, err := s.expiryLock.TryLock(ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			s.logger.Error(ctx, "Failed to acquire code expiry lock", err, nil)
+			s.metrics.RecordError(ctx, "code_expiry_failed")
+			return nil, fmt.Errorf("failed to acquire code expiry lock: %w", err)
+		}
+		if !acquired {
+			err := redemption_code.ErrExpirySweepInProgress
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		defer release()
+	}
+
+	result := &ExpireCodesResponse{Codes: []string{}}
+	for i := 0; i < limit; i++ {
+		var expired string
+		err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			// トランザクション内で取得して行をロックし、管理APIでの変更と競合しないようにする
+			codes, err := s.redemptionCodeRepo.FindExpiredActive(ctx, time.Now(), 1)
+			if err != nil {
+				return fmt.Errorf("failed to find expired codes: %w", err)
+			}
+			if len(codes) == 0 {
+				return nil
+			}
+			code := codes[0]
+
+			from := code.Status()
+			code.Expire()
+			if err := s.redemptionCodeRepo.Update(ctx, code); err != nil {
+				return fmt.Errorf("failed to update code: %w", err)
+			}
+
+			auditLog := redemption_code.NewCodeAuditLog(
+				"audit_"+s.idGenerator.NewID(),
+				code.Code(),
+				redemption_code.AuditActionExpire,
+				[]redemption_code.FieldChange{{Field: "status", From: from.String(), To: code.Status().String()}},
+				"valid_until passed: "+code.ValidUntil().UTC().Format(time.RFC3339),
+				requester,
+			)
+			if err := s.redemptionCodeRepo.SaveAuditLog(ctx, auditLog); err != nil {
+				return fmt.Errorf("failed to save audit log: %w", err)
+			}
+
+			expired = code.Code()
+			return nil
+		})
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			s.logger.Error(ctx, "Failed to expire redemption codes", err, map[string]interface{}{
+				"expired_codes": result.ExpiredCodes,
+			})
+			s.metrics.RecordError(ctx, "code_expiry_failed")
+			s.metrics.RecordExpiredCodes(ctx, result.ExpiredCodes)
+			return result, err
+		}
+		if expired == "" {
+			break
+		}
+		result.ExpiredCodes++
+		result.Codes = append(result.Codes, expired)
+	}
+
+	s.metrics.RecordExpiredCodes(ctx, result.ExpiredCodes)
+	span.SetAttributes(attribute.Int("expired_codes", result.ExpiredCodes))
+
+	if result.ExpiredCodes > 0 {
+		s.logger.Info(ctx, "Redemption codes expired", map[string]interface{}{
+			"expired_codes": result.ExpiredCodes,
+			"requester":     requester,
+		})
+	}
+
+	return result, nil
+}
+
+// DeleteCode 引き換えコードを削除
+func (s *CodeRedemptionApplicationService) DeleteCode(ctx context.Context, req *DeleteCodeRequest) (*DeleteCodeResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.DeleteCode")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("code", req.Code),
+	)
+
+	s.logger.Info(ctx, "Deleting redemption code", map[string]interface{}{
+		"code": req.Code,
+	})
+
+	// バリデーション
+	if req.Code == "" {
+		err := fmt.Errorf("code is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	// コードの存在確認
+	_, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		if err == redemption_code.ErrCodeNotFound {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to find code: %w", err)
+	}
+
+	// 削除実行（リポジトリ内で使用済みチェックも行う）
+	if err := s.redemptionCodeRepo.Delete(ctx, req.Code); err != nil {
+		if err == redemption_code.ErrCodeNotFound {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		if err == redemption_code.ErrCodeCannotBeDeleted {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to delete redemption code", err, map[string]interface{}{
+			"code": req.Code,
+		})
+		return nil, fmt.Errorf("failed to delete redemption code: %w", err)
+	}
+
+	s.logger.Info(ctx, "Redemption code deleted successfully", map[string]interface{}{
+		"code": req.Code,
+	})
+
+	return &DeleteCodeResponse{
+		Code:      req.Code,
+		DeletedAt: time.Now(),
+	}, nil
+}
+
+// GetCode 引き換えコードを取得
+func (s *CodeRedemptionApplicationService) GetCode(ctx context.Context, req *GetCodeRequest) (*GetCodeResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.GetCode")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("code", req.Code),
+	)
+
+	s.logger.Info(ctx, "Getting redemption code", map[string]interface{}{
+		"code": req.Code,
+	})
+
+	// バリデーション
+	if req.Code == "" {
+		err := fmt.Errorf("code is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	// コードを取得
+	code, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		if err == redemption_code.ErrCodeNotFound {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to find code: %w", err)
+	}
+
+	return &GetCodeResponse{
+		Code:         code.Code(),
+		CodeType:     code.CodeType().String(),
+		CurrencyType: code.CurrencyType().String(),
+		Amount:       code.Amount(),
+		Rewards:      ToRewardLines(code.Rewards()),
+		MaxUses:      code.MaxUses(),
+		CurrentUses:  code.CurrentUses(),
+		ValidFrom:    code.ValidFrom(),
+		ValidUntil:   code.ValidUntil(),
+		Status:       code.Status().String(),
+		Metadata:     code.Metadata(),
+		BatchID:      code.BatchID(),
+		CampaignID:   code.CampaignID(),
+		Eligibility:  code.Eligibility(),
+		CreatedAt:    code.CreatedAt(),
+		UpdatedAt:    code.UpdatedAt(),
 	}, nil
 }
 
@@ -500,28 +1239,45 @@ func (s *CodeRedemptionApplicationService) ListCodes(ctx context.Context, req *L
 		attribute.Int("offset", req.Offset),
 		attribute.String("status", req.Status),
 		attribute.String("code_type", req.CodeType),
+		attribute.String("currency_type", req.CurrencyType),
+		attribute.String("campaign_id", req.CampaignID),
+		attribute.String("code_prefix", req.CodePrefix),
+		attribute.String("sort", req.Sort),
 	)
 
 	s.logger.Info(ctx, "Listing redemption codes", map[string]interface{}{
-		"limit":     req.Limit,
-		"offset":    req.Offset,
-		"status":    req.Status,
-		"code_type": req.CodeType,
+		"limit":         req.Limit,
+		"offset":        req.Offset,
+		"status":        req.Status,
+		"code_type":     req.CodeType,
+		"currency_type": req.CurrencyType,
+		"campaign_id":   req.CampaignID,
+		"code_prefix":   req.CodePrefix,
+		"sort":          req.Sort,
 	})
 
-	// ページネーションパラメータのバリデーション
-	if req.Limit <= 0 {
-		req.Limit = 50 // デフォルト値
-	}
-	if req.Limit > 100 {
-		req.Limit = 100 // 最大値
-	}
-	if req.Offset < 0 {
-		req.Offset = 0
+	// 検索条件のバリデーション
+	filter, err := redemption_code.NewFilter(
+		req.Status,
+		req.CodeType,
+		req.CurrencyType,
+		req.CampaignID,
+		req.CodePrefix,
+		req.Sort,
+		req.ValidFrom,
+		req.ValidUntil,
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
 	}
 
-	// 一覧を取得
-	codes, total, err := s.redemptionCodeRepo.FindAll(ctx, req.Limit, req.Offset)
+	// ページネーションパラメータのバリデーション
+	req.Limit, req.Offset = normalizePage(req.Limit, req.Offset)
+
+	// 検索条件に一致する一覧と総件数を取得
+	codes, total, err := s.redemptionCodeRepo.FindByFilter(ctx, filter, req.Limit, req.Offset)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
@@ -529,37 +1285,196 @@ func (s *CodeRedemptionApplicationService) ListCodes(ctx context.Context, req *L
 		return nil, fmt.Errorf("failed to list redemption codes: %w", err)
 	}
 
-	// フィルタリング（status, code_type）
-	filteredCodes := make([]*redemption_code.RedemptionCode, 0, len(codes))
-	for _, code := range codes {
-		// ステータスフィルタ
-		if req.Status != "" {
-			if code.Status().String() != req.Status {
-				continue
-			}
-		}
-
-		// コードタイプフィルタ
-		if req.CodeType != "" {
-			if code.CodeType().String() != req.CodeType {
-				continue
-			}
-		}
-
-		filteredCodes = append(filteredCodes, code)
-	}
-
 	s.logger.Info(ctx, "Redemption codes listed successfully", map[string]interface{}{
 		"total":  total,
-		"count":  len(filteredCodes),
+		"count":  len(codes),
 		"limit":  req.Limit,
 		"offset": req.Offset,
 	})
 
 	return &ListCodesResponse{
-		Codes:  filteredCodes,
+		Codes:  codes,
 		Total:  total,
 		Limit:  req.Limit,
 		Offset: req.Offset,
 	}, nil
 }
+
+// normalizePage ページネーションパラメータにデフォルト値と上限を適用
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 50 // デフォルト値
+	}
+	if limit > 100 {
+		limit = 100 // 最大値
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// ListCodeRedemptions 引き換えコードの引き換え履歴（ユーザーと付与トランザクション）を新しい順に取得
+func (s *CodeRedemptionApplicationService) ListCodeRedemptions(ctx context.Context, req *ListCodeRedemptionsRequest) (*ListRedemptionsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.ListCodeRedemptions")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("code", req.Code),
+		attribute.Int("limit", req.Limit),
+		attribute.Int("offset", req.Offset),
+	)
+
+	s.logger.Info(ctx, "Listing code redemptions", map[string]interface{}{
+		"code":   req.Code,
+		"limit":  req.Limit,
+		"offset": req.Offset,
+	})
+
+	// バリデーション
+	if req.Code == "" {
+		err := fmt.Errorf("code is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	// 存在しないコードは404にするため、先にコードを取得
+	if _, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		if err == redemption_code.ErrCodeNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find code: %w", err)
+	}
+
+	req.Limit, req.Offset = normalizePage(req.Limit, req.Offset)
+
+	redemptions, total, err := s.redemptionCodeRepo.FindRedemptionsByCode(ctx, req.Code, req.Limit, req.Offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to list code redemptions", err, map[string]interface{}{
+			"code": req.Code,
+		})
+		return nil, fmt.Errorf("failed to list code redemptions: %w", err)
+	}
+
+	return &ListRedemptionsResponse{
+		Redemptions: redemptions,
+		Total:       total,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	}, nil
+}
+
+// ListUserRedemptions ユーザーの引き換え履歴を新しい順に取得
+func (s *CodeRedemptionApplicationService) ListUserRedemptions(ctx context.Context, req *ListUserRedemptionsRequest) (*ListRedemptionsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.ListUserRedemptions")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user_id", req.UserID),
+		attribute.Int("limit", req.Limit),
+		attribute.Int("offset", req.Offset),
+	)
+
+	s.logger.Info(ctx, "Listing user redemptions", map[string]interface{}{
+		"user_id": req.UserID,
+		"limit":   req.Limit,
+		"offset":  req.Offset,
+	})
+
+	// バリデーション
+	if req.UserID == "" {
+		err := fmt.Errorf("user_id is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	req.Limit, req.Offset = normalizePage(req.Limit, req.Offset)
+
+	redemptions, total, err := s.redemptionCodeRepo.FindRedemptionsByUserID(ctx, req.UserID, req.Limit, req.Offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to list user redemptions", err, map[string]interface{}{
+			"user_id": req.UserID,
+		})
+		return nil, fmt.Errorf("failed to list user redemptions: %w", err)
+	}
+
+	return &ListRedemptionsResponse{
+		Redemptions: redemptions,
+		Total:       total,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	}, nil
+}
+
+// GetCodeStats 引き換えコードの利用統計（引き換え回数、ユーザー数、付与した通貨の合計、日ごとの引き換え回数）を取得
+func (s *CodeRedemptionApplicationService) GetCodeStats(ctx context.Context, req *GetCodeStatsRequest) (*GetCodeStatsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CodeRedemptionApplicationService.GetCodeStats")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("code", req.Code),
+	)
+
+	s.logger.Info(ctx, "Getting redemption code stats", map[string]interface{}{
+		"code": req.Code,
+	})
+
+	// バリデーション
+	if req.Code == "" {
+		err := fmt.Errorf("code is required")
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	code, err := s.redemptionCodeRepo.FindByCode(ctx, req.Code)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		if err == redemption_code.ErrCodeNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to find code: %w", err)
+	}
+
+	stats, err := s.redemptionCodeRepo.GetCodeStats(ctx, req.Code)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to get redemption code stats", err, map[string]interface{}{
+			"code": req.Code,
+		})
+		return nil, fmt.Errorf("failed to get code stats: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("redemptions", stats.Redemptions),
+		attribute.Int("unique_users", stats.UniqueUsers),
+	)
+
+	totalGranted := make([]RewardLine, len(stats.Granted))
+	for i, g := range stats.Granted {
+		totalGranted[i] = RewardLine{
+			CurrencyType: g.CurrencyType.String(),
+			Amount:       g.Amount,
+		}
+	}
+
+	return &GetCodeStatsResponse{
+		Code:         code.Code(),
+		MaxUses:      code.MaxUses(),
+		CurrentUses:  code.CurrentUses(),
+		Redemptions:  stats.Redemptions,
+		UniqueUsers:  stats.UniqueUsers,
+		TotalGranted: totalGranted,
+		Daily:        stats.Daily,
+	}, nil
+}
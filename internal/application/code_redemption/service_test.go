@@ -1,8 +1,11 @@
 package code_redemption
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/redemption_code"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
@@ -58,14 +62,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -74,6 +96,19 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 // MockRedemptionCodeRepository モック引き換えコードリポジトリ
 type MockRedemptionCodeRepository struct {
 	mock.Mock
@@ -92,16 +127,46 @@ func (m *MockRedemptionCodeRepository) Update(ctx context.Context, code *redempt
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) IncrementUses(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
 	args := m.Called(ctx, code, userID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRedemptionCodeRepository) CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (redemption_code.CampaignUsage, error) {
+	args := m.Called(ctx, campaignID, userID)
+	return args.Get(0).(redemption_code.CampaignUsage), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) LockUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockRedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
 	args := m.Called(ctx, redemption)
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) UpdateSettings(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) SaveAuditLog(ctx context.Context, log *redemption_code.CodeAuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
 	args := m.Called(ctx, code)
 	return args.Error(0)
@@ -112,27 +177,103 @@ func (m *MockRedemptionCodeRepository) Delete(ctx context.Context, code string)
 	return args.Error(0)
 }
 
-func (m *MockRedemptionCodeRepository) FindAll(ctx context.Context, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockRedemptionCodeRepository) CreateBatch(ctx context.Context, codes []*redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, codes)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindByBatchID(ctx context.Context, batchID string) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindByFilter(ctx context.Context, filter redemption_code.Filter, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
 	return args.Get(0).([]*redemption_code.RedemptionCode), args.Int(1), args.Error(2)
 }
 
+func (m *MockRedemptionCodeRepository) FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, code, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) GetCodeStats(ctx context.Context, code string) (redemption_code.CodeStats, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(redemption_code.CodeStats), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(ctx, fn)
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
 
+// MockFailureTracker モック引き換え失敗トラッカー
+type MockFailureTracker struct {
+	mock.Mock
+}
+
+func (m *MockFailureTracker) LockedUntil(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockFailureTracker) RecordFailure(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockFailureTracker) Clear(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockExpiryLock モック期限切れコード失効ロック
+type MockExpiryLock struct {
+	mock.Mock
+	released bool
+}
+
+func (m *MockExpiryLock) TryLock(ctx context.Context) (func(), bool, error) {
+	args := m.Called(ctx)
+	if !args.Bool(0) {
+		return nil, false, args.Error(1)
+	}
+	return func() { m.released = true }, true, nil
+}
+
 func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -164,7 +305,7 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				// ユーザーはまだ引き換えていない
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
 				// コードを更新
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				// 既存の通貨に付与
 				existingCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
@@ -175,7 +316,7 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
 				// 引き換え履歴を記録
 				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *RedeemCodeResponse, err error) {
@@ -186,6 +327,8 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				assert.Equal(t, "paid", resp.CurrencyType)
 				assert.Equal(t, int64(1000), resp.Amount)
 				assert.Equal(t, int64(1500), resp.BalanceAfter)
+				require.Len(t, resp.Rewards, 1)
+				assert.Equal(t, resp.TransactionID, resp.Rewards[0].TransactionID)
 				assert.Equal(t, "completed", resp.Status)
 			},
 		},
@@ -209,7 +352,7 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				)
 				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				// 通貨が存在しない
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
 				mcr.On("Create", mock.Anything, mock.MatchedBy(func(c *currency.Currency) bool {
@@ -220,7 +363,7 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				})).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
 				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *RedeemCodeResponse, err error) {
@@ -230,6 +373,90 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				assert.Equal(t, int64(500), resp.BalanceAfter)
 			},
 		},
+		{
+			name: "正常系: 複数の報酬を持つコードを引き換え",
+			req: &RedeemCodeRequest{
+				Code:   "BUNDLECODE",
+				UserID: "user123",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				free, _ := redemption_code.NewReward(currency.CurrencyTypeFree, 100)
+				paid, _ := redemption_code.NewReward(currency.CurrencyTypePaid, 10)
+				code, _ := redemption_code.NewRedemptionCodeWithRewards(
+					"BUNDLECODE",
+					redemption_code.CodeTypeEvent,
+					[]redemption_code.Reward{free, paid},
+					1,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				mrcr.On("FindByCode", mock.Anything, "BUNDLECODE").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "BUNDLECODE", "user123").Return(false, nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 50, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 5, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil).Twice()
+				// 報酬ごとにトランザクション履歴を記録
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.CurrencyType() == currency.CurrencyTypeFree && txn.Amount() == 100 && txn.BalanceAfter() == 150
+				})).Return(nil).Once()
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.CurrencyType() == currency.CurrencyTypePaid && txn.Amount() == 10 && txn.BalanceAfter() == 15
+				})).Return(nil).Once()
+				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantError: false,
+			checkFunc: func(t *testing.T, resp *RedeemCodeResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Rewards, 2)
+				assert.Equal(t, "free", resp.Rewards[0].CurrencyType)
+				assert.Equal(t, int64(100), resp.Rewards[0].Amount)
+				assert.Equal(t, int64(150), resp.Rewards[0].BalanceAfter)
+				assert.Equal(t, "paid", resp.Rewards[1].CurrencyType)
+				assert.Equal(t, int64(10), resp.Rewards[1].Amount)
+				assert.Equal(t, int64(15), resp.Rewards[1].BalanceAfter)
+				assert.NotEqual(t, resp.Rewards[0].TransactionID, resp.Rewards[1].TransactionID)
+				// 先頭の報酬はトップレベルにも設定される
+				assert.Equal(t, resp.Rewards[0].TransactionID, resp.TransactionID)
+				assert.Equal(t, "free", resp.CurrencyType)
+				assert.Equal(t, int64(150), resp.BalanceAfter)
+			},
+		},
+		{
+			name: "異常系: 2件目の報酬の付与に失敗",
+			req: &RedeemCodeRequest{
+				Code:   "BUNDLECODE",
+				UserID: "user123",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				free, _ := redemption_code.NewReward(currency.CurrencyTypeFree, 100)
+				paid, _ := redemption_code.NewReward(currency.CurrencyTypePaid, 10)
+				code, _ := redemption_code.NewRedemptionCodeWithRewards(
+					"BUNDLECODE",
+					redemption_code.CodeTypeEvent,
+					[]redemption_code.Reward{free, paid},
+					1,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				mrcr.On("FindByCode", mock.Anything, "BUNDLECODE").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "BUNDLECODE", "user123").Return(false, nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 50, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, errors.New("database error"))
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(errors.New("database error"))
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *RedeemCodeResponse, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+			},
+		},
 		{
 			name: "異常系: コードが見つからない",
 			req: &RedeemCodeRequest{
@@ -370,6 +597,36 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				assert.Contains(t, err.Error(), "failed to check redemption status")
 			},
 		},
+		{
+			name: "異常系: 同時の引き換えで最大使用回数に達した",
+			req: &RedeemCodeRequest{
+				Code:   "TESTCODE123",
+				UserID: "user123",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				codeType, _ := redemption_code.NewCodeType("promotion")
+				code := redemption_code.MustNewRedemptionCode(
+					"TESTCODE123",
+					codeType,
+					currency.CurrencyTypePaid,
+					1000,
+					1,                             // maxUses
+					time.Now().Add(-24*time.Hour), // validFrom
+					time.Now().Add(24*time.Hour),  // validUntil
+					map[string]interface{}{},
+				)
+				// 読み込んだ時点では未使用だが、加算時には他の引き換えで上限に達している
+				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
+				mrcr.On("IncrementUses", mock.Anything, "TESTCODE123").Return(redemption_code.ErrCodeNotRedeemable)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *RedeemCodeResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrCodeNotRedeemable)
+				assert.Nil(t, resp)
+			},
+		},
 		{
 			name: "異常系: コード更新でエラー",
 			req: &RedeemCodeRequest{
@@ -390,8 +647,8 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				)
 				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -415,10 +672,10 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				)
 				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
 				mcr.On("Create", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -442,12 +699,12 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				)
 				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				existingCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1)
 				// リトライをシミュレート（3回失敗）
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil).Times(3)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(assert.AnError).Times(3)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -471,12 +728,12 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				)
 				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				existingCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -500,13 +757,13 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				)
 				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				existingCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
 				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -531,6 +788,9 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -555,127 +815,652 @@ func TestCodeRedemptionApplicationService_Redeem(t *testing.T) {
 	}
 }
 
-func TestCodeRedemptionApplicationService_CreateCode(t *testing.T) {
+func TestCodeRedemptionApplicationService_Redeem_Lockout(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour)
+	codeType, _ := redemption_code.NewCodeType("promotion")
+
 	tests := []struct {
 		name       string
-		req        *CreateCodeRequest
-		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockRedemptionCodeRepository, *MockTransactionManager)
-		wantError  bool
-		checkFunc  func(*testing.T, *CreateCodeResponse, error)
+		setupMocks func(*MockRedemptionCodeRepository, *MockFailureTracker)
+		checkFunc  func(*testing.T, error)
 	}{
 		{
-			name: "正常系: コードを作成",
-			req: &CreateCodeRequest{
-				Code:         "NEWCODE123",
-				CodeType:     "promotion",
-				CurrencyType: "paid",
-				Amount:       1000,
-				MaxUses:      100,
-				ValidFrom:    time.Now().Add(-24 * time.Hour),
-				ValidUntil:   time.Now().Add(24 * time.Hour),
-				Metadata:     map[string]interface{}{"campaign_id": "campaign_001"},
+			name: "異常系: ロックアウト中はコードを検索しない",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mft *MockFailureTracker) {
+				mft.On("LockedUntil", mock.Anything, "user123").Return(lockedUntil, nil)
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
-				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
-			},
-			wantError: false,
-			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
-				require.NoError(t, err)
-				assert.NotNil(t, resp)
-				assert.Equal(t, "NEWCODE123", resp.Code)
-				assert.Equal(t, "promotion", resp.CodeType)
-				assert.Equal(t, "paid", resp.CurrencyType)
-				assert.Equal(t, int64(1000), resp.Amount)
-				assert.Equal(t, 100, resp.MaxUses)
-				assert.Equal(t, 0, resp.CurrentUses)
-				assert.Equal(t, "active", resp.Status)
+			checkFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrRedemptionLockedOut)
+				var lockoutErr *redemption_code.LockoutError
+				require.ErrorAs(t, err, &lockoutErr)
+				assert.Equal(t, lockedUntil, lockoutErr.Until)
 			},
 		},
 		{
-			name: "正常系: メタデータなしでコードを作成",
-			req: &CreateCodeRequest{
-				Code:         "GIFTCODE456",
-				CodeType:     "gift",
-				CurrencyType: "free",
-				Amount:       500,
-				MaxUses:      0, // 無制限
-				ValidFrom:    time.Now(),
-				ValidUntil:   time.Now().Add(7 * 24 * time.Hour),
-				Metadata:     nil,
+			name: "異常系: コードが見つからない場合は失敗を記録",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mft *MockFailureTracker) {
+				mft.On("LockedUntil", mock.Anything, "user123").Return(time.Time{}, nil)
+				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(nil, redemption_code.ErrCodeNotFound)
+				mft.On("RecordFailure", mock.Anything, "user123").Return(time.Time{}, nil).Once()
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
-				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+			checkFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrCodeNotFound)
 			},
-			wantError: false,
 		},
 		{
-			name: "異常系: コードが空",
-			req: &CreateCodeRequest{
-				Code:         "",
-				CodeType:     "promotion",
-				CurrencyType: "paid",
-				Amount:       1000,
-				MaxUses:      100,
-				ValidFrom:    time.Now(),
-				ValidUntil:   time.Now().Add(24 * time.Hour),
-				Metadata:     nil,
+			name: "異常系: 失敗でロックアウトされた場合も元のエラーを返す",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mft *MockFailureTracker) {
+				mft.On("LockedUntil", mock.Anything, "user123").Return(time.Time{}, nil)
+				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(nil, redemption_code.ErrCodeNotFound)
+				mft.On("RecordFailure", mock.Anything, "user123").Return(lockedUntil, nil).Once()
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			checkFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrCodeNotFound)
 			},
-			wantError: true,
 		},
 		{
-			name: "異常系: 有効期限が開始日より前",
-			req: &CreateCodeRequest{
-				Code:         "INVALIDCODE",
-				CodeType:     "promotion",
-				CurrencyType: "paid",
-				Amount:       1000,
-				MaxUses:      100,
-				ValidFrom:    time.Now().Add(24 * time.Hour),
-				ValidUntil:   time.Now(), // 開始日より前
-				Metadata:     nil,
+			name: "異常系: 期限切れのコードは失敗を記録",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mft *MockFailureTracker) {
+				code := redemption_code.MustNewRedemptionCode(
+					"TESTCODE123",
+					codeType,
+					currency.CurrencyTypePaid,
+					1000,
+					1,
+					time.Now().Add(-48*time.Hour),
+					time.Now().Add(-24*time.Hour),
+					map[string]interface{}{},
+				)
+				mft.On("LockedUntil", mock.Anything, "user123").Return(time.Time{}, nil)
+				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
+				mft.On("RecordFailure", mock.Anything, "user123").Return(time.Time{}, nil).Once()
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			checkFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrCodeNotRedeemable)
 			},
-			wantError: true,
 		},
 		{
-			name: "異常系: 金額が負の値",
-			req: &CreateCodeRequest{
-				Code:         "NEGATIVECODE",
-				CodeType:     "promotion",
-				CurrencyType: "paid",
-				Amount:       -100,
-				MaxUses:      100,
-				ValidFrom:    time.Now(),
-				ValidUntil:   time.Now().Add(24 * time.Hour),
-				Metadata:     nil,
+			name: "異常系: 引き換え済みは失敗として記録しない",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mft *MockFailureTracker) {
+				code := redemption_code.MustNewRedemptionCode(
+					"TESTCODE123",
+					codeType,
+					currency.CurrencyTypePaid,
+					1000,
+					0,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					map[string]interface{}{},
+				)
+				mft.On("LockedUntil", mock.Anything, "user123").Return(time.Time{}, nil)
+				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(true, nil)
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			checkFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrUserAlreadyRedeemed)
 			},
-			wantError: true,
 		},
 		{
-			name: "異常系: 無効なコードタイプ",
-			req: &CreateCodeRequest{
-				Code:         "INVALIDTYPECODE",
-				CodeType:     "invalid",
-				CurrencyType: "paid",
-				Amount:       1000,
-				MaxUses:      100,
-				ValidFrom:    time.Now(),
-				ValidUntil:   time.Now().Add(24 * time.Hour),
-				Metadata:     nil,
+			name: "異常系: ロックアウトの状態を取得できない場合は引き換えを続ける",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mft *MockFailureTracker) {
+				mft.On("LockedUntil", mock.Anything, "user123").Return(time.Time{}, assert.AnError)
+				mrcr.On("FindByCode", mock.Anything, "TESTCODE123").Return(nil, redemption_code.ErrCodeNotFound)
+				mft.On("RecordFailure", mock.Anything, "user123").Return(time.Time{}, assert.AnError).Once()
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			checkFunc: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrCodeNotFound)
 			},
-			wantError: true,
 		},
-		{
-			name: "異常系: 無効な通貨タイプ",
-			req: &CreateCodeRequest{
-				Code:         "INVALIDCURRENCYCODE",
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockFailureTracker := new(MockFailureTracker)
+
+			tt.setupMocks(mockRedemptionCodeRepo, mockFailureTracker)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				mockFailureTracker,
+				nil,
+				logger,
+				metrics,
+			)
+
+			_, err = svc.Redeem(context.Background(), &RedeemCodeRequest{
+				Code:   "TESTCODE123",
+				UserID: "user123",
+			})
+			tt.checkFunc(t, err)
+
+			mockRedemptionCodeRepo.AssertExpectations(t)
+			mockFailureTracker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_Redeem_Eligibility(t *testing.T) {
+	// expectGrant 引き換えが成功する場合のモックを設定
+	expectGrant := func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+		mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+		mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 0, 1), nil)
+		mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+		mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+		mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
+		mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+	}
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		campaignID  string
+		eligibility redemption_code.Eligibility
+		setupMocks  func(*MockCurrencyRepository, *MockTransactionRepository, *MockRedemptionCodeRepository, *MockTransactionManager)
+		wantErrIs   error
+	}{
+		{
+			name:        "正常系: 許可リストに含まれるユーザー",
+			eligibility: redemption_code.Eligibility{AllowedUserIDs: []string{"user999", "user123"}},
+			setupMocks:  expectGrant,
+		},
+		{
+			name:        "正常系: プレフィックスに一致するユーザー",
+			eligibility: redemption_code.Eligibility{AllowedUserIDPrefix: "user"},
+			setupMocks:  expectGrant,
+		},
+		{
+			name:        "異常系: 許可リストに含まれないユーザー",
+			eligibility: redemption_code.Eligibility{AllowedUserIDs: []string{"user999"}, AllowedUserIDPrefix: "beta_"},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantErrIs: redemption_code.ErrUserNotEligible,
+		},
+		{
+			name:        "正常系: 指定日以降に登録された新規ユーザー",
+			eligibility: redemption_code.Eligibility{NewUsersSince: since},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindUserFirstSeenAt", mock.Anything, "user123").Return(since.Add(time.Hour), nil)
+				expectGrant(mcr, mtr, mrcr, mtm)
+			},
+		},
+		{
+			name:        "正常系: 未登録のユーザーは新規ユーザーとして扱う",
+			eligibility: redemption_code.Eligibility{NewUsersSince: since},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindUserFirstSeenAt", mock.Anything, "user123").Return(time.Time{}, nil)
+				expectGrant(mcr, mtr, mrcr, mtm)
+			},
+		},
+		{
+			name:        "異常系: 指定日より前に登録された既存ユーザー",
+			eligibility: redemption_code.Eligibility{NewUsersSince: since},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindUserFirstSeenAt", mock.Anything, "user123").Return(since.Add(-time.Hour), nil)
+			},
+			wantErrIs: redemption_code.ErrNewUsersOnly,
+		},
+		{
+			name:        "正常系: キャンペーン内の上限未満",
+			campaignID:  "campaign_001",
+			eligibility: redemption_code.Eligibility{MaxPerUserInCampaign: 2},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 1}, nil)
+				mrcr.On("LockUser", mock.Anything, "user123").Return(nil)
+				expectGrant(mcr, mtr, mrcr, mtm)
+			},
+		},
+		{
+			name:        "異常系: キャンペーン内の上限に到達",
+			campaignID:  "campaign_001",
+			eligibility: redemption_code.Eligibility{MaxPerUserInCampaign: 2},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 2}, nil)
+			},
+			wantErrIs: redemption_code.ErrCampaignLimitReached,
+		},
+		{
+			name:        "異常系: 同時の引き換えでキャンペーン内の上限に到達",
+			campaignID:  "campaign_001",
+			eligibility: redemption_code.Eligibility{MaxPerUserInCampaign: 2},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				// 事前のチェックでは上限未満だが、ユーザーをロックして数え直すと他の引き換えがコミットされている
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 1}, nil).Once()
+				mrcr.On("LockUser", mock.Anything, "user123").Return(nil)
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 2}, nil).Once()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErrIs: redemption_code.ErrCampaignLimitReached,
+		},
+		{
+			name:        "異常系: 同時に同じキャンペーンの排他コードを引き換えた",
+			campaignID:  "campaign_001",
+			eligibility: redemption_code.Eligibility{ExclusiveInCampaign: true},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{}, nil).Once()
+				mrcr.On("LockUser", mock.Anything, "user123").Return(nil)
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 1, ExclusiveRedemptions: 1}, nil).Once()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErrIs: redemption_code.ErrCampaignExclusive,
+		},
+		{
+			name:        "正常系: 排他コード以外の引き換えのみ",
+			campaignID:  "campaign_001",
+			eligibility: redemption_code.Eligibility{ExclusiveInCampaign: true},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 3}, nil)
+				mrcr.On("LockUser", mock.Anything, "user123").Return(nil)
+				expectGrant(mcr, mtr, mrcr, mtm)
+			},
+		},
+		{
+			name:        "異常系: 同じキャンペーンの排他コードを引き換え済み",
+			campaignID:  "campaign_001",
+			eligibility: redemption_code.Eligibility{ExclusiveInCampaign: true},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 1, ExclusiveRedemptions: 1}, nil)
+			},
+			wantErrIs: redemption_code.ErrCampaignExclusive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+			// 引き換え条件による拒否は総当たりの失敗として記録しない
+			mockFailureTracker := new(MockFailureTracker)
+			mockFailureTracker.On("LockedUntil", mock.Anything, "user123").Return(time.Time{}, nil)
+
+			code := redemption_code.MustNewRedemptionCode(
+				"CAMPAIGN1",
+				redemption_code.CodeTypeEvent,
+				currency.CurrencyTypeFree,
+				100,
+				0,
+				time.Now().Add(-24*time.Hour),
+				time.Now().Add(24*time.Hour),
+				nil,
+			)
+			require.NoError(t, code.SetEligibility(tt.campaignID, tt.eligibility))
+			mockRedemptionCodeRepo.On("FindByCode", mock.Anything, "CAMPAIGN1").Return(code, nil)
+			mockRedemptionCodeRepo.On("HasUserRedeemed", mock.Anything, "CAMPAIGN1", "user123").Return(false, nil)
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockRedemptionCodeRepo, mockTxManager)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				mockCurrencyRepo,
+				mockTransactionRepo,
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				mockFailureTracker,
+				nil,
+				logger,
+				metrics,
+			)
+
+			resp, err := svc.Redeem(context.Background(), &RedeemCodeRequest{
+				Code:   "CAMPAIGN1",
+				UserID: "user123",
+			})
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				assert.Nil(t, resp)
+				mockRedemptionCodeRepo.AssertNotCalled(t, "IncrementUses", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "completed", resp.Status)
+			}
+
+			mockRedemptionCodeRepo.AssertExpectations(t)
+			mockFailureTracker.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_ClearRedemptionLockout(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    string
+		tracker   func() *MockFailureTracker
+		wantError bool
+	}{
+		{
+			name:   "正常系: ロックアウトを解除",
+			userID: "user123",
+			tracker: func() *MockFailureTracker {
+				m := new(MockFailureTracker)
+				m.On("Clear", mock.Anything, "user123").Return(nil)
+				return m
+			},
+		},
+		{
+			name:   "正常系: ロックアウトが無効な場合は何もしない",
+			userID: "user123",
+		},
+		{
+			name:      "異常系: ユーザーIDが空",
+			userID:    "",
+			wantError: true,
+		},
+		{
+			name:   "異常系: 解除に失敗",
+			userID: "user123",
+			tracker: func() *MockFailureTracker {
+				m := new(MockFailureTracker)
+				m.On("Clear", mock.Anything, "user123").Return(assert.AnError)
+				return m
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			var tracker redemption_code.FailureTracker
+			var mockTracker *MockFailureTracker
+			if tt.tracker != nil {
+				mockTracker = tt.tracker()
+				tracker = mockTracker
+			}
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				new(MockRedemptionCodeRepository),
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				tracker,
+				nil,
+				logger,
+				metrics,
+			)
+
+			resp, err := svc.ClearRedemptionLockout(context.Background(), &ClearRedemptionLockoutRequest{UserID: tt.userID})
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.userID, resp.UserID)
+			}
+			if mockTracker != nil {
+				mockTracker.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_CreateCode(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *CreateCodeRequest
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockRedemptionCodeRepository, *MockTransactionManager)
+		wantError  bool
+		checkFunc  func(*testing.T, *CreateCodeResponse, error)
+	}{
+		{
+			name: "正常系: コードを作成",
+			req: &CreateCodeRequest{
+				Code:         "NEWCODE123",
+				CodeType:     "promotion",
+				CurrencyType: "paid",
+				Amount:       1000,
+				MaxUses:      100,
+				ValidFrom:    time.Now().Add(-24 * time.Hour),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+				Metadata:     map[string]interface{}{"campaign_id": "campaign_001"},
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+			},
+			wantError: false,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				require.NoError(t, err)
+				assert.NotNil(t, resp)
+				assert.Equal(t, "NEWCODE123", resp.Code)
+				assert.Equal(t, "promotion", resp.CodeType)
+				assert.Equal(t, "paid", resp.CurrencyType)
+				assert.Equal(t, int64(1000), resp.Amount)
+				assert.Equal(t, 100, resp.MaxUses)
+				assert.Equal(t, 0, resp.CurrentUses)
+				assert.Equal(t, "active", resp.Status)
+			},
+		},
+		{
+			name: "正常系: メタデータなしでコードを作成",
+			req: &CreateCodeRequest{
+				Code:         "GIFTCODE456",
+				CodeType:     "gift",
+				CurrencyType: "free",
+				Amount:       500,
+				MaxUses:      0, // 無制限
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(7 * 24 * time.Hour),
+				Metadata:     nil,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+			},
+			wantError: false,
+		},
+		{
+			name: "正常系: 複数の報酬を持つコードを作成",
+			req: &CreateCodeRequest{
+				Code:     "BUNDLECODE",
+				CodeType: "event",
+				Rewards: []RewardLine{
+					{CurrencyType: "free", Amount: 100},
+					{CurrencyType: "paid", Amount: 10},
+				},
+				MaxUses:    1,
+				ValidFrom:  time.Now(),
+				ValidUntil: time.Now().Add(24 * time.Hour),
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.MatchedBy(func(rc *redemption_code.RedemptionCode) bool {
+					return rc.HasMultipleRewards()
+				})).Return(nil)
+			},
+			wantError: false,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []RewardLine{
+					{CurrencyType: "free", Amount: 100},
+					{CurrencyType: "paid", Amount: 10},
+				}, resp.Rewards)
+				assert.Equal(t, "free", resp.CurrencyType)
+				assert.Equal(t, int64(100), resp.Amount)
+			},
+		},
+		{
+			name: "正常系: キャンペーンと引き換え条件を持つコードを作成",
+			req: &CreateCodeRequest{
+				Code:         "CAMPAIGN1",
+				CodeType:     "event",
+				CurrencyType: "free",
+				Amount:       100,
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+				CampaignID:   "campaign_001",
+				Eligibility: redemption_code.Eligibility{
+					AllowedUserIDPrefix:  "beta_",
+					MaxPerUserInCampaign: 1,
+				},
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.MatchedBy(func(rc *redemption_code.RedemptionCode) bool {
+					return rc.CampaignID() == "campaign_001" && rc.Eligibility().MaxPerUserInCampaign == 1
+				})).Return(nil)
+			},
+			wantError: false,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "campaign_001", resp.CampaignID)
+				assert.Equal(t, "beta_", resp.Eligibility.AllowedUserIDPrefix)
+			},
+		},
+		{
+			name: "異常系: キャンペーンIDなしでキャンペーン単位の条件を指定",
+			req: &CreateCodeRequest{
+				Code:         "CAMPAIGN1",
+				CodeType:     "event",
+				CurrencyType: "free",
+				Amount:       100,
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+				Eligibility:  redemption_code.Eligibility{ExclusiveInCampaign: true},
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidEligibility)
+			},
+		},
+		{
+			name: "異常系: 報酬と通貨タイプを同時に指定",
+			req: &CreateCodeRequest{
+				Code:         "BUNDLECODE",
+				CodeType:     "event",
+				CurrencyType: "paid",
+				Amount:       10,
+				Rewards:      []RewardLine{{CurrencyType: "free", Amount: 100}},
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidRewards)
+			},
+		},
+		{
+			name: "異常系: 報酬の通貨タイプが重複",
+			req: &CreateCodeRequest{
+				Code:     "BUNDLECODE",
+				CodeType: "event",
+				Rewards: []RewardLine{
+					{CurrencyType: "free", Amount: 100},
+					{CurrencyType: "free", Amount: 10},
+				},
+				ValidFrom:  time.Now(),
+				ValidUntil: time.Now().Add(24 * time.Hour),
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidRewards)
+			},
+		},
+		{
+			name: "異常系: 報酬の金額が0",
+			req: &CreateCodeRequest{
+				Code:       "BUNDLECODE",
+				CodeType:   "event",
+				Rewards:    []RewardLine{{CurrencyType: "free", Amount: 0}},
+				ValidFrom:  time.Now(),
+				ValidUntil: time.Now().Add(24 * time.Hour),
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *CreateCodeResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidRewards)
+			},
+		},
+		{
+			name: "異常系: コードが空",
+			req: &CreateCodeRequest{
+				Code:         "",
+				CodeType:     "promotion",
+				CurrencyType: "paid",
+				Amount:       1000,
+				MaxUses:      100,
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+				Metadata:     nil,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+		},
+		{
+			name: "異常系: 有効期限が開始日より前",
+			req: &CreateCodeRequest{
+				Code:         "INVALIDCODE",
+				CodeType:     "promotion",
+				CurrencyType: "paid",
+				Amount:       1000,
+				MaxUses:      100,
+				ValidFrom:    time.Now().Add(24 * time.Hour),
+				ValidUntil:   time.Now(), // 開始日より前
+				Metadata:     nil,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+		},
+		{
+			name: "異常系: 金額が負の値",
+			req: &CreateCodeRequest{
+				Code:         "NEGATIVECODE",
+				CodeType:     "promotion",
+				CurrencyType: "paid",
+				Amount:       -100,
+				MaxUses:      100,
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+				Metadata:     nil,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+		},
+		{
+			name: "異常系: 無効なコードタイプ",
+			req: &CreateCodeRequest{
+				Code:         "INVALIDTYPECODE",
+				CodeType:     "invalid",
+				CurrencyType: "paid",
+				Amount:       1000,
+				MaxUses:      100,
+				ValidFrom:    time.Now(),
+				ValidUntil:   time.Now().Add(24 * time.Hour),
+				Metadata:     nil,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+		},
+		{
+			name: "異常系: 無効な通貨タイプ",
+			req: &CreateCodeRequest{
+				Code:         "INVALIDCURRENCYCODE",
 				CodeType:     "promotion",
 				CurrencyType: "invalid",
 				Amount:       1000,
@@ -721,8 +1506,532 @@ func TestCodeRedemptionApplicationService_CreateCode(t *testing.T) {
 				ValidUntil:   time.Now().Add(24 * time.Hour),
 				Metadata:     nil,
 			},
-			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
-				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(sql.ErrConnDone)
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(sql.ErrConnDone)
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockRedemptionCodeRepo, mockTxManager)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				mockCurrencyRepo,
+				mockTransactionRepo,
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			ctx := context.Background()
+			got, err := svc.CreateCode(ctx, tt.req)
+
+			if tt.wantError {
+				assert.Error(t, err)
+				if tt.checkFunc != nil {
+					tt.checkFunc(t, got, err)
+				}
+			} else {
+				if tt.checkFunc != nil {
+					tt.checkFunc(t, got, err)
+				} else {
+					require.NoError(t, err)
+					assert.NotNil(t, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_GenerateCodes(t *testing.T) {
+	validReq := func() *GenerateCodesRequest {
+		return &GenerateCodesRequest{
+			Count:        50,
+			Prefix:       "INF-",
+			Length:       10,
+			CodeType:     "promotion",
+			CurrencyType: "free",
+			Amount:       500,
+			MaxUses:      1,
+			ValidFrom:    time.Now().Add(-time.Hour),
+			ValidUntil:   time.Now().Add(24 * time.Hour),
+			Metadata:     map[string]interface{}{"campaign": "spring"},
+		}
+	}
+	batchOf := func(n int) interface{} {
+		return mock.MatchedBy(func(codes []*redemption_code.RedemptionCode) bool { return len(codes) == n })
+	}
+
+	tests := []struct {
+		name       string
+		req        func() *GenerateCodesRequest
+		setupMocks func(*MockRedemptionCodeRepository, *MockTransactionManager)
+		checkFunc  func(*testing.T, *GenerateCodesResponse, error)
+	}{
+		{
+			name: "正常系: 同じ設定のコードを一括生成",
+			req:  validReq,
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("CreateBatch", mock.Anything, batchOf(50)).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(resp.BatchID, "batch_"))
+				require.Len(t, resp.Codes, 50)
+
+				seen := make(map[string]bool)
+				for _, code := range resp.Codes {
+					assert.Regexp(t, `^INF-[`+redemption_code.DefaultCodeAlphabet+`]{10}$`, code.Code())
+					assert.Equal(t, resp.BatchID, code.BatchID())
+					assert.Equal(t, redemption_code.CodeTypePromotion, code.CodeType())
+					assert.Equal(t, currency.CurrencyTypeFree, code.CurrencyType())
+					assert.Equal(t, int64(500), code.Amount())
+					assert.Equal(t, 1, code.MaxUses())
+					assert.Equal(t, "spring", code.Metadata()["campaign"])
+					seen[code.Code()] = true
+				}
+				assert.Len(t, seen, 50)
+			},
+		},
+		{
+			name: "正常系: バッチIDを指定",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.BatchID = "influencer_2024_spring"
+				return req
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindByBatchID", mock.Anything, "influencer_2024_spring").Return([]*redemption_code.RedemptionCode{}, nil)
+				mrcr.On("CreateBatch", mock.Anything, batchOf(50)).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "influencer_2024_spring", resp.BatchID)
+				assert.Equal(t, "influencer_2024_spring", resp.Codes[0].BatchID())
+			},
+		},
+		{
+			name: "正常系: 既存のコードと衝突した場合は生成し直す",
+			req:  validReq,
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("CreateBatch", mock.Anything, batchOf(50)).Return(redemption_code.ErrCodeAlreadyExists).Once()
+				mrcr.On("CreateBatch", mock.Anything, batchOf(50)).Return(nil).Once()
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				require.NoError(t, err)
+				assert.Len(t, resp.Codes, 50)
+			},
+		},
+		{
+			name: "異常系: 衝突が続く場合はエラー",
+			req:  validReq,
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("CreateBatch", mock.Anything, batchOf(50)).Return(redemption_code.ErrCodeAlreadyExists).Times(maxGenerateAttempts)
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrCodeAlreadyExists)
+				assert.Nil(t, resp)
+			},
+		},
+		{
+			name: "異常系: 指定したバッチIDが使用済み",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.BatchID = "influencer_2024_spring"
+				return req
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindByBatchID", mock.Anything, "influencer_2024_spring").Return([]*redemption_code.RedemptionCode{
+					redemption_code.MustNewRedemptionCode("INF-EXISTING", redemption_code.CodeTypePromotion, currency.CurrencyTypeFree, 500, 1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), nil),
+				}, nil)
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrBatchAlreadyExists)
+				assert.Nil(t, resp)
+			},
+		},
+		{
+			name: "異常系: バッチIDが長すぎる",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.BatchID = strings.Repeat("a", maxBatchIDLength+1)
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidBatchID)
+			},
+		},
+		{
+			name: "異常系: 有効期間が逆転している",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.ValidUntil = req.ValidFrom.Add(-time.Hour)
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidCodeSettings)
+			},
+		},
+		{
+			name: "異常系: 使用上限が負",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.MaxUses = -1
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidCodeSettings)
+			},
+		},
+		{
+			name: "異常系: 件数が0",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.Count = 0
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidBatchSize)
+			},
+		},
+		{
+			name: "異常系: 件数が上限を超える",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.Count = MaxGenerateCodesCount + 1
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidBatchSize)
+			},
+		},
+		{
+			name: "異常系: 件数に対してコードが短すぎる",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.Length = 4
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidCodePattern)
+			},
+		},
+		{
+			name: "異常系: 金額が0",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.Amount = 0
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.ErrorIs(t, err, currency.ErrInvalidAmount)
+			},
+		},
+		{
+			name: "異常系: 無効なコードタイプ",
+			req: func() *GenerateCodesRequest {
+				req := validReq()
+				req.CodeType = "invalid"
+				return req
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "異常系: DBエラー",
+			req:  validReq,
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("CreateBatch", mock.Anything, batchOf(50)).Return(errors.New("database error")).Once()
+			},
+			checkFunc: func(t *testing.T, resp *GenerateCodesResponse, err error) {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockRedemptionCodeRepo, mockTxManager)
+			}
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			got, err := svc.GenerateCodes(context.Background(), tt.req())
+			tt.checkFunc(t, got, err)
+
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_ExportBatchCodes(t *testing.T) {
+	validFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)
+	newBatchCode := func(code string, uses int) *redemption_code.RedemptionCode {
+		rc := redemption_code.MustNewRedemptionCode(code, redemption_code.CodeTypePromotion, currency.CurrencyTypeFree, 500, 1, validFrom, validUntil, nil)
+		rc.SetBatchID("batch_001")
+		rc.SetCurrentUses(uses)
+		return rc
+	}
+
+	tests := []struct {
+		name       string
+		batchID    string
+		setupMocks func(*MockRedemptionCodeRepository)
+		wantCSV    string
+		wantError  bool
+		wantErrIs  error
+	}{
+		{
+			name:    "正常系: CSVで出力",
+			batchID: "batch_001",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByBatchID", mock.Anything, "batch_001").Return([]*redemption_code.RedemptionCode{
+					newBatchCode("INF-AAAA", 0),
+					newBatchCode("INF-BBBB", 1),
+				}, nil)
+			},
+			wantCSV: "code,batch_id,code_type,currency_type,amount,rewards,max_uses,current_uses,valid_from,valid_until,status\n" +
+				"INF-AAAA,batch_001,promotion,free,500,free:500,1,0,2024-01-01T00:00:00Z,2024-12-31T23:59:59Z,active\n" +
+				"INF-BBBB,batch_001,promotion,free,500,free:500,1,1,2024-01-01T00:00:00Z,2024-12-31T23:59:59Z,active\n",
+		},
+		{
+			name:    "異常系: バッチが見つからない",
+			batchID: "unknown",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByBatchID", mock.Anything, "unknown").Return([]*redemption_code.RedemptionCode{}, nil)
+			},
+			wantError: true,
+			wantErrIs: redemption_code.ErrBatchNotFound,
+		},
+		{
+			name:       "異常系: バッチIDが空",
+			batchID:    "",
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {},
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tt.setupMocks(mockRedemptionCodeRepo)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			var buf bytes.Buffer
+			resp, err := svc.ExportBatchCodes(context.Background(), &ExportBatchCodesRequest{BatchID: tt.batchID}, &buf)
+			if tt.wantError {
+				require.Error(t, err)
+				if tt.wantErrIs != nil {
+					assert.ErrorIs(t, err, tt.wantErrIs)
+				}
+				assert.Empty(t, buf.String())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, 2, resp.Count)
+			assert.Equal(t, tt.wantCSV, buf.String())
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_UpdateCode(t *testing.T) {
+	strPtr := func(v string) *string { return &v }
+	intPtr := func(v int) *int { return &v }
+	newCode := func(validUntil time.Time, status redemption_code.CodeStatus) *redemption_code.RedemptionCode {
+		code := redemption_code.MustNewRedemptionCode(
+			"UPDATECODE",
+			redemption_code.CodeTypePromotion,
+			currency.CurrencyTypePaid,
+			1000,
+			100,
+			time.Now().Add(-48*time.Hour),
+			validUntil,
+			map[string]interface{}{},
+		)
+		code.SetStatus(status)
+		return code
+	}
+	extended := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name       string
+		req        *UpdateCodeRequest
+		setupMocks func(*MockRedemptionCodeRepository, *MockTransactionManager)
+		wantErrIs  error
+		wantError  bool
+		checkFunc  func(*testing.T, *UpdateCodeResponse)
+	}{
+		{
+			name: "正常系: 期限切れのコードを延長して再有効化",
+			req: &UpdateCodeRequest{
+				Code:       "UPDATECODE",
+				Status:     strPtr("active"),
+				ValidUntil: &extended,
+				MaxUses:    intPtr(200),
+				Reason:     "campaign extended",
+				Requester:  "ops-tool",
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "UPDATECODE").Return(newCode(time.Now().Add(-time.Hour), redemption_code.CodeStatusExpired), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("UpdateSettings", mock.Anything, mock.MatchedBy(func(rc *redemption_code.RedemptionCode) bool {
+					return rc.Status() == redemption_code.CodeStatusActive && rc.MaxUses() == 200 && rc.ValidUntil().Equal(extended)
+				})).Return(nil)
+				mrcr.On("SaveAuditLog", mock.Anything, mock.MatchedBy(func(l *redemption_code.CodeAuditLog) bool {
+					return l.Code() == "UPDATECODE" &&
+						l.Action() == redemption_code.AuditActionUpdate &&
+						len(l.Changes()) == 3 &&
+						l.Reason() == "campaign extended" &&
+						l.Requester() == "ops-tool"
+				})).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *UpdateCodeResponse) {
+				assert.Equal(t, "active", resp.Status)
+				assert.Equal(t, 200, resp.MaxUses)
+				assert.True(t, strings.HasPrefix(resp.AuditID, "audit_"))
+				assert.Equal(t, []CodeChange{
+					{Field: "status", From: "expired", To: "active"},
+					{Field: "valid_until", From: resp.Changes[1].From, To: extended.UTC().Format(time.RFC3339)},
+					{Field: "max_uses", From: "100", To: "200"},
+				}, resp.Changes)
+			},
+		},
+		{
+			name: "正常系: 値が変わらない場合は更新も履歴の記録もしない",
+			req: &UpdateCodeRequest{
+				Code:      "UPDATECODE",
+				Status:    strPtr("active"),
+				Requester: "ops-tool",
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "UPDATECODE").Return(newCode(time.Now().Add(time.Hour), redemption_code.CodeStatusActive), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *UpdateCodeResponse) {
+				assert.Empty(t, resp.AuditID)
+				assert.Empty(t, resp.Changes)
+			},
+		},
+		{
+			name: "異常系: 有効期限を過ぎたコードを再有効化",
+			req: &UpdateCodeRequest{
+				Code:      "UPDATECODE",
+				Status:    strPtr("active"),
+				Requester: "ops-tool",
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "UPDATECODE").Return(newCode(time.Now().Add(-time.Hour), redemption_code.CodeStatusExpired), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErrIs: redemption_code.ErrInvalidCodeUpdate,
+		},
+		{
+			name: "異常系: 不正なステータス",
+			req: &UpdateCodeRequest{
+				Code:      "UPDATECODE",
+				Status:    strPtr("deleted"),
+				Requester: "ops-tool",
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantErrIs: redemption_code.ErrInvalidCodeUpdate,
+		},
+		{
+			name: "異常系: requesterが空",
+			req: &UpdateCodeRequest{
+				Code:    "UPDATECODE",
+				MaxUses: intPtr(200),
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantErrIs: redemption_code.ErrInvalidCodeUpdate,
+		},
+		{
+			name: "異常系: コードが見つからない",
+			req: &UpdateCodeRequest{
+				Code:      "NOTFOUNDCODE",
+				MaxUses:   intPtr(200),
+				Requester: "ops-tool",
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "NOTFOUNDCODE").Return(nil, redemption_code.ErrCodeNotFound)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErrIs: redemption_code.ErrCodeNotFound,
+		},
+		{
+			name: "異常系: 変更履歴の保存に失敗",
+			req: &UpdateCodeRequest{
+				Code:      "UPDATECODE",
+				Status:    strPtr("disabled"),
+				Requester: "ops-tool",
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "UPDATECODE").Return(newCode(time.Now().Add(time.Hour), redemption_code.CodeStatusActive), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("UpdateSettings", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("SaveAuditLog", mock.Anything, mock.AnythingOfType("*redemption_code.CodeAuditLog")).Return(sql.ErrConnDone)
 			},
 			wantError: true,
 		},
@@ -730,12 +2039,10 @@ func TestCodeRedemptionApplicationService_CreateCode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockCurrencyRepo := new(MockCurrencyRepository)
-			mockTransactionRepo := new(MockTransactionRepository)
 			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
 			mockTxManager := new(MockTransactionManager)
 
-			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockRedemptionCodeRepo, mockTxManager)
+			tt.setupMocks(mockRedemptionCodeRepo, mockTxManager)
 
 			tracer := otel.Tracer("test")
 			logger := otelinfra.NewLogger(tracer)
@@ -743,30 +2050,33 @@ func TestCodeRedemptionApplicationService_CreateCode(t *testing.T) {
 			require.NoError(t, err)
 
 			svc := NewCodeRedemptionApplicationService(
-				mockCurrencyRepo,
-				mockTransactionRepo,
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
 
-			ctx := context.Background()
-			got, err := svc.CreateCode(ctx, tt.req)
-
-			if tt.wantError {
+			got, err := svc.UpdateCode(context.Background(), tt.req)
+			switch {
+			case tt.wantErrIs != nil:
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				assert.Nil(t, got)
+				mockRedemptionCodeRepo.AssertNotCalled(t, "UpdateSettings", mock.Anything, mock.Anything)
+			case tt.wantError:
 				assert.Error(t, err)
-				if tt.checkFunc != nil {
-					tt.checkFunc(t, got, err)
-				}
-			} else {
-				if tt.checkFunc != nil {
-					tt.checkFunc(t, got, err)
-				} else {
-					require.NoError(t, err)
-					assert.NotNil(t, got)
-				}
+				assert.Nil(t, got)
+			default:
+				require.NoError(t, err)
+				tt.checkFunc(t, got)
 			}
+
+			mockRedemptionCodeRepo.AssertExpectations(t)
+			mockTxManager.AssertExpectations(t)
 		})
 	}
 }
@@ -885,6 +2195,9 @@ func TestCodeRedemptionApplicationService_DeleteCode(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -1002,6 +2315,9 @@ func TestCodeRedemptionApplicationService_GetCode(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -1027,6 +2343,10 @@ func TestCodeRedemptionApplicationService_GetCode(t *testing.T) {
 }
 
 func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
+	defaultFilter := redemption_code.Filter{Sort: redemption_code.SortCreatedAtDesc}
+	windowFrom := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	windowUntil := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name       string
 		req        *ListCodesRequest
@@ -1063,7 +2383,7 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 25, nil)
+				mrcr.On("FindByFilter", mock.Anything, defaultFilter, 10, 0).Return(codes, 25, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
@@ -1094,69 +2414,71 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 						time.Now().Add(24*time.Hour),
 						map[string]interface{}{},
 					),
-					redemption_code.MustNewRedemptionCode(
-						"EXPIREDCODE",
-						redemption_code.CodeTypePromotion,
-						currency.CurrencyTypePaid,
-						1000,
-						100,
-						time.Now().Add(-48*time.Hour),
-						time.Now().Add(-24*time.Hour),
-						map[string]interface{}{},
-					),
 				}
-				status, _ := redemption_code.NewCodeStatus("expired")
-				codes[1].SetStatus(status)
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 2, nil)
+				// 検索条件はリポジトリに渡し、総件数も検索条件に一致する件数を返す
+				filter := redemption_code.Filter{Status: redemption_code.CodeStatusActive, Sort: redemption_code.SortCreatedAtDesc}
+				mrcr.On("FindByFilter", mock.Anything, filter, 10, 0).Return(codes, 12, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
 				require.NoError(t, err)
 				assert.NotNil(t, resp)
-				// activeのみがフィルタリングされる
 				assert.Equal(t, 1, len(resp.Codes))
 				assert.Equal(t, "ACTIVECODE", resp.Codes[0].Code())
+				assert.Equal(t, 12, resp.Total)
 			},
 		},
 		{
-			name: "正常系: フィルタリング（code_type=promotion）",
+			name: "正常系: 複数の検索条件と並び順",
 			req: &ListCodesRequest{
-				Limit:    10,
-				Offset:   0,
-				CodeType: "promotion",
+				Limit:        10,
+				Offset:       0,
+				CodeType:     "promotion",
+				CurrencyType: "free",
+				CampaignID:   "campaign_001",
+				CodePrefix:   "PROMO",
+				ValidFrom:    &windowFrom,
+				ValidUntil:   &windowUntil,
+				Sort:         "valid_until_asc",
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
-				codes := []*redemption_code.RedemptionCode{
-					redemption_code.MustNewRedemptionCode(
-						"PROMOCODE",
-						redemption_code.CodeTypePromotion,
-						currency.CurrencyTypePaid,
-						1000,
-						100,
-						time.Now().Add(-24*time.Hour),
-						time.Now().Add(24*time.Hour),
-						map[string]interface{}{},
-					),
-					redemption_code.MustNewRedemptionCode(
-						"GIFTCODE",
-						redemption_code.CodeTypeGift,
-						currency.CurrencyTypeFree,
-						500,
-						0,
-						time.Now().Add(-12*time.Hour),
-						time.Now().Add(12*time.Hour),
-						map[string]interface{}{},
-					),
+				filter := redemption_code.Filter{
+					CodeType:     redemption_code.CodeTypePromotion,
+					CurrencyType: currency.CurrencyTypeFree,
+					CampaignID:   "campaign_001",
+					CodePrefix:   "PROMO",
+					ValidFrom:    &windowFrom,
+					ValidUntil:   &windowUntil,
+					Sort:         redemption_code.SortValidUntilAsc,
 				}
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 2, nil)
+				mrcr.On("FindByFilter", mock.Anything, filter, 10, 0).Return([]*redemption_code.RedemptionCode{}, 0, nil)
 			},
 			wantError: false,
+		},
+		{
+			name: "異常系: 不正なステータス",
+			req: &ListCodesRequest{
+				Limit:  10,
+				Status: "deleted",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
-				require.NoError(t, err)
-				assert.NotNil(t, resp)
-				// promotionのみがフィルタリングされる
-				assert.Equal(t, 1, len(resp.Codes))
-				assert.Equal(t, "PROMOCODE", resp.Codes[0].Code())
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidFilter)
+			},
+		},
+		{
+			name: "異常系: 不正な並び順",
+			req: &ListCodesRequest{
+				Limit: 10,
+				Sort:  "amount_desc",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+			},
+			wantError: true,
+			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
+				assert.ErrorIs(t, err, redemption_code.ErrInvalidFilter)
 			},
 		},
 		{
@@ -1178,7 +2500,7 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mrcr.On("FindAll", mock.Anything, 5, 10).Return(codes, 20, nil)
+				mrcr.On("FindByFilter", mock.Anything, defaultFilter, 5, 10).Return(codes, 20, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
@@ -1198,7 +2520,7 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
 				codes := []*redemption_code.RedemptionCode{}
-				mrcr.On("FindAll", mock.Anything, 50, 0).Return(codes, 0, nil)
+				mrcr.On("FindByFilter", mock.Anything, defaultFilter, 50, 0).Return(codes, 0, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
@@ -1215,7 +2537,7 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
 				codes := []*redemption_code.RedemptionCode{}
-				mrcr.On("FindAll", mock.Anything, 100, 0).Return(codes, 0, nil)
+				mrcr.On("FindByFilter", mock.Anything, defaultFilter, 100, 0).Return(codes, 0, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
@@ -1232,7 +2554,7 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
 				codes := []*redemption_code.RedemptionCode{}
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 0, nil)
+				mrcr.On("FindByFilter", mock.Anything, defaultFilter, 10, 0).Return(codes, 0, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ListCodesResponse, err error) {
@@ -1248,7 +2570,7 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 				Offset: 0,
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(nil, 0, sql.ErrConnDone)
+				mrcr.On("FindByFilter", mock.Anything, defaultFilter, 10, 0).Return(nil, 0, sql.ErrConnDone)
 			},
 			wantError: true,
 		},
@@ -1273,6 +2595,9 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -1297,6 +2622,490 @@ func TestCodeRedemptionApplicationService_ListCodes(t *testing.T) {
 	}
 }
 
+func TestCodeRedemptionApplicationService_ListCodeRedemptions(t *testing.T) {
+	redeemedAt := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+	newCode := func() *redemption_code.RedemptionCode {
+		return redemption_code.MustNewRedemptionCode(
+			"HISTORY123",
+			redemption_code.CodeTypeEvent,
+			currency.CurrencyTypeFree,
+			100,
+			0,
+			time.Now().Add(-24*time.Hour),
+			time.Now().Add(24*time.Hour),
+			nil,
+		)
+	}
+
+	tests := []struct {
+		name       string
+		req        *ListCodeRedemptionsRequest
+		setupMocks func(*MockRedemptionCodeRepository)
+		wantErr    error
+		wantError  bool
+		checkFunc  func(*testing.T, *ListRedemptionsResponse)
+	}{
+		{
+			name: "正常系: 引き換え履歴を取得",
+			req:  &ListCodeRedemptionsRequest{Code: "HISTORY123", Limit: 10},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				redemption := redemption_code.NewCodeRedemption("red1", "HISTORY123", "user123", "txn1")
+				redemption.SetRedeemedAt(redeemedAt)
+				mrcr.On("FindByCode", mock.Anything, "HISTORY123").Return(newCode(), nil)
+				mrcr.On("FindRedemptionsByCode", mock.Anything, "HISTORY123", 10, 0).
+					Return([]*redemption_code.CodeRedemption{redemption}, 11, nil)
+			},
+			checkFunc: func(t *testing.T, resp *ListRedemptionsResponse) {
+				assert.Equal(t, 11, resp.Total)
+				assert.Equal(t, 10, resp.Limit)
+				require.Len(t, resp.Redemptions, 1)
+				assert.Equal(t, "user123", resp.Redemptions[0].UserID())
+				assert.Equal(t, "txn1", resp.Redemptions[0].TransactionID())
+				assert.Equal(t, redeemedAt, resp.Redemptions[0].RedeemedAt())
+			},
+		},
+		{
+			name: "正常系: ページネーションの上限を適用",
+			req:  &ListCodeRedemptionsRequest{Code: "HISTORY123", Limit: 1000, Offset: -1},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "HISTORY123").Return(newCode(), nil)
+				mrcr.On("FindRedemptionsByCode", mock.Anything, "HISTORY123", 100, 0).
+					Return([]*redemption_code.CodeRedemption{}, 0, nil)
+			},
+			checkFunc: func(t *testing.T, resp *ListRedemptionsResponse) {
+				assert.Equal(t, 100, resp.Limit)
+				assert.Equal(t, 0, resp.Offset)
+			},
+		},
+		{
+			name:       "異常系: コードが空",
+			req:        &ListCodeRedemptionsRequest{},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {},
+			wantError:  true,
+		},
+		{
+			name: "異常系: コードが見つからない",
+			req:  &ListCodeRedemptionsRequest{Code: "NOTFOUND"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "NOTFOUND").Return(nil, redemption_code.ErrCodeNotFound)
+			},
+			wantErr: redemption_code.ErrCodeNotFound,
+		},
+		{
+			name: "異常系: DBエラー",
+			req:  &ListCodeRedemptionsRequest{Code: "HISTORY123"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "HISTORY123").Return(newCode(), nil)
+				mrcr.On("FindRedemptionsByCode", mock.Anything, "HISTORY123", 50, 0).Return(nil, 0, sql.ErrConnDone)
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tt.setupMocks(mockRedemptionCodeRepo)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			got, err := svc.ListCodeRedemptions(context.Background(), tt.req)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantError:
+				assert.Error(t, err)
+			default:
+				require.NoError(t, err)
+				tt.checkFunc(t, got)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_ListUserRedemptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *ListUserRedemptionsRequest
+		setupMocks func(*MockRedemptionCodeRepository)
+		wantError  bool
+		wantTotal  int
+	}{
+		{
+			name: "正常系: ユーザーの引き換え履歴を取得",
+			req:  &ListUserRedemptionsRequest{UserID: "user123"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindRedemptionsByUserID", mock.Anything, "user123", 50, 0).
+					Return([]*redemption_code.CodeRedemption{
+						redemption_code.NewCodeRedemption("red2", "CODE2", "user123", "txn2"),
+						redemption_code.NewCodeRedemption("red1", "CODE1", "user123", "txn1"),
+					}, 2, nil)
+			},
+			wantTotal: 2,
+		},
+		{
+			name:       "異常系: ユーザーIDが空",
+			req:        &ListUserRedemptionsRequest{},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {},
+			wantError:  true,
+		},
+		{
+			name: "異常系: DBエラー",
+			req:  &ListUserRedemptionsRequest{UserID: "user123", Limit: 20, Offset: 40},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindRedemptionsByUserID", mock.Anything, "user123", 20, 40).Return(nil, 0, sql.ErrConnDone)
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tt.setupMocks(mockRedemptionCodeRepo)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			got, err := svc.ListUserRedemptions(context.Background(), tt.req)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, got.Total)
+				assert.Len(t, got.Redemptions, tt.wantTotal)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_GetCodeStats(t *testing.T) {
+	day := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	newCode := func() *redemption_code.RedemptionCode {
+		free, _ := redemption_code.NewReward(currency.CurrencyTypeFree, 100)
+		paid, _ := redemption_code.NewReward(currency.CurrencyTypePaid, 10)
+		code, err := redemption_code.NewRedemptionCodeWithRewards(
+			"STATS123",
+			redemption_code.CodeTypeEvent,
+			[]redemption_code.Reward{free, paid},
+			100,
+			time.Now().Add(-24*time.Hour),
+			time.Now().Add(24*time.Hour),
+			nil,
+		)
+		if err != nil {
+			panic(err)
+		}
+		code.SetCurrentUses(3)
+		return code
+	}
+
+	tests := []struct {
+		name       string
+		req        *GetCodeStatsRequest
+		setupMocks func(*MockRedemptionCodeRepository)
+		wantErr    error
+		wantError  bool
+		want       *GetCodeStatsResponse
+	}{
+		{
+			name: "正常系: 利用統計を取得",
+			req:  &GetCodeStatsRequest{Code: "STATS123"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "STATS123").Return(newCode(), nil)
+				// 付与した通貨の合計はコードの現在の報酬ではなく、記録したトランザクションの合計
+				mrcr.On("GetCodeStats", mock.Anything, "STATS123").Return(redemption_code.CodeStats{
+					Redemptions: 3,
+					UniqueUsers: 3,
+					Granted: []redemption_code.GrantedTotal{
+						{CurrencyType: currency.CurrencyTypeFree, Amount: 250},
+						{CurrencyType: currency.CurrencyTypePaid, Amount: 30},
+					},
+					Daily: []redemption_code.DailyRedemptions{
+						{Date: day, Count: 1},
+						{Date: day.AddDate(0, 0, 1), Count: 2},
+					},
+				}, nil)
+			},
+			want: &GetCodeStatsResponse{
+				Code:        "STATS123",
+				MaxUses:     100,
+				CurrentUses: 3,
+				Redemptions: 3,
+				UniqueUsers: 3,
+				TotalGranted: []RewardLine{
+					{CurrencyType: "free", Amount: 250},
+					{CurrencyType: "paid", Amount: 30},
+				},
+				Daily: []DailyRedemptions{
+					{Date: day, Count: 1},
+					{Date: day.AddDate(0, 0, 1), Count: 2},
+				},
+			},
+		},
+		{
+			name:       "異常系: コードが空",
+			req:        &GetCodeStatsRequest{},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {},
+			wantError:  true,
+		},
+		{
+			name: "異常系: コードが見つからない",
+			req:  &GetCodeStatsRequest{Code: "NOTFOUND"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "NOTFOUND").Return(nil, redemption_code.ErrCodeNotFound)
+			},
+			wantErr: redemption_code.ErrCodeNotFound,
+		},
+		{
+			name: "異常系: 集計に失敗",
+			req:  &GetCodeStatsRequest{Code: "STATS123"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "STATS123").Return(newCode(), nil)
+				mrcr.On("GetCodeStats", mock.Anything, "STATS123").Return(redemption_code.CodeStats{}, sql.ErrConnDone)
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tt.setupMocks(mockRedemptionCodeRepo)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			got, err := svc.GetCodeStats(context.Background(), tt.req)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantError:
+				assert.Error(t, err)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionApplicationService_ExpireCodes(t *testing.T) {
+	newExpiredCode := func(code string) *redemption_code.RedemptionCode {
+		return redemption_code.MustNewRedemptionCode(
+			code,
+			redemption_code.CodeTypeEvent,
+			currency.CurrencyTypeFree,
+			100,
+			0,
+			time.Now().Add(-48*time.Hour),
+			time.Now().Add(-time.Hour),
+			nil,
+		)
+	}
+	isExpired := mock.MatchedBy(func(code *redemption_code.RedemptionCode) bool {
+		return code.Status() == redemption_code.CodeStatusExpired
+	})
+	expireAuditLog := func(requester string) interface{} {
+		return mock.MatchedBy(func(log *redemption_code.CodeAuditLog) bool {
+			return log.Action() == redemption_code.AuditActionExpire &&
+				log.Requester() == requester &&
+				len(log.Changes()) == 1 &&
+				log.Changes()[0] == redemption_code.FieldChange{Field: "status", From: "active", To: "expired"}
+		})
+	}
+
+	tests := []struct {
+		name        string
+		req         *ExpireCodesRequest
+		lock        func() *MockExpiryLock
+		setupMocks  func(*MockRedemptionCodeRepository, *MockTransactionManager)
+		wantErr     error
+		wantError   bool
+		wantCodes   []string
+		wantRelease bool
+	}{
+		{
+			name: "正常系: 期限切れのコードをすべて失効",
+			req:  &ExpireCodesRequest{Limit: 10},
+			lock: func() *MockExpiryLock {
+				m := new(MockExpiryLock)
+				m.On("TryLock", mock.Anything).Return(true, nil)
+				return m
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindExpiredActive", mock.Anything, mock.Anything, 1).
+					Return([]*redemption_code.RedemptionCode{newExpiredCode("EXPIRED1")}, nil).Once()
+				mrcr.On("FindExpiredActive", mock.Anything, mock.Anything, 1).
+					Return([]*redemption_code.RedemptionCode{newExpiredCode("EXPIRED2")}, nil).Once()
+				mrcr.On("FindExpiredActive", mock.Anything, mock.Anything, 1).
+					Return([]*redemption_code.RedemptionCode{}, nil).Once()
+				mrcr.On("Update", mock.Anything, isExpired).Return(nil).Twice()
+				mrcr.On("SaveAuditLog", mock.Anything, expireAuditLog(ExpirySweepRequester)).Return(nil).Twice()
+			},
+			wantCodes:   []string{"EXPIRED1", "EXPIRED2"},
+			wantRelease: true,
+		},
+		{
+			name: "正常系: 処理件数の上限で終了",
+			req:  &ExpireCodesRequest{Limit: 1, Requester: "ops-tool"},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindExpiredActive", mock.Anything, mock.Anything, 1).
+					Return([]*redemption_code.RedemptionCode{newExpiredCode("EXPIRED1")}, nil).Once()
+				mrcr.On("Update", mock.Anything, isExpired).Return(nil).Once()
+				mrcr.On("SaveAuditLog", mock.Anything, expireAuditLog("ops-tool")).Return(nil).Once()
+			},
+			wantCodes: []string{"EXPIRED1"},
+		},
+		{
+			name: "正常系: 期限切れのコードなし",
+			req:  &ExpireCodesRequest{Limit: 10},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindExpiredActive", mock.Anything, mock.Anything, 1).
+					Return([]*redemption_code.RedemptionCode{}, nil).Once()
+			},
+			wantCodes: []string{},
+		},
+		{
+			name: "異常系: 他のインスタンスが実行中",
+			req:  &ExpireCodesRequest{Limit: 10},
+			lock: func() *MockExpiryLock {
+				m := new(MockExpiryLock)
+				m.On("TryLock", mock.Anything).Return(false, nil)
+				return m
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {},
+			wantErr:    redemption_code.ErrExpirySweepInProgress,
+		},
+		{
+			name: "異常系: ロックの取得に失敗",
+			req:  &ExpireCodesRequest{Limit: 10},
+			lock: func() *MockExpiryLock {
+				m := new(MockExpiryLock)
+				m.On("TryLock", mock.Anything).Return(false, sql.ErrConnDone)
+				return m
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {},
+			wantErr:    sql.ErrConnDone,
+		},
+		{
+			name: "異常系: 更新に失敗した時点で中断",
+			req:  &ExpireCodesRequest{Limit: 10},
+			lock: func() *MockExpiryLock {
+				m := new(MockExpiryLock)
+				m.On("TryLock", mock.Anything).Return(true, nil)
+				return m
+			},
+			setupMocks: func(mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindExpiredActive", mock.Anything, mock.Anything, 1).
+					Return([]*redemption_code.RedemptionCode{newExpiredCode("EXPIRED1")}, nil).Once()
+				mrcr.On("Update", mock.Anything, isExpired).Return(sql.ErrConnDone).Once()
+			},
+			wantError:   true,
+			wantRelease: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+			tt.setupMocks(mockRedemptionCodeRepo, mockTxManager)
+
+			var lock redemption_code.ExpiryLock
+			var mockLock *MockExpiryLock
+			if tt.lock != nil {
+				mockLock = tt.lock()
+				lock = mockLock
+			}
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				lock,
+				logger,
+				metrics,
+			)
+
+			got, err := svc.ExpireCodes(context.Background(), tt.req)
+			switch {
+			case tt.wantErr != nil:
+				assert.ErrorIs(t, err, tt.wantErr)
+			case tt.wantError:
+				assert.Error(t, err)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, len(tt.wantCodes), got.ExpiredCodes)
+				assert.Equal(t, tt.wantCodes, got.Codes)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+			if mockLock != nil {
+				mockLock.AssertExpectations(t)
+				assert.Equal(t, tt.wantRelease, mockLock.released)
+			}
+		})
+	}
+}
+
 func mustNewCurrency(userID string, currencyType currency.CurrencyType, balance int64, version int) *currency.Currency {
 	c, err := currency.NewCurrency(userID, currencyType, balance, version)
 	if err != nil {
@@ -1,5 +1,7 @@
 package currency
 
+import "time"
+
 // GetBalanceRequest 残高取得リクエスト
 type GetBalanceRequest struct {
 	UserID string
@@ -7,18 +9,28 @@ type GetBalanceRequest struct {
 
 // GetBalanceResponse 残高取得レスポンス
 type GetBalanceResponse struct {
-	UserID   string
-	Balances map[string]int64 // "paid" => 1000, "free" => 500
+	UserID      string
+	Balances    map[string]int64 // "paid" => 1000, "free" => 500
+	Expirations []Expiration     // 有効期限の近い順
+}
+
+// Expiration 失効予定の通貨
+type Expiration struct {
+	CurrencyType string
+	Amount       int64
+	ExpiresAt    time.Time
 }
 
 // GrantRequest 通貨付与リクエスト
 type GrantRequest struct {
-	UserID       string
-	CurrencyType string // "paid" or "free"
-	Amount       int64
-	Reason       string
-	Requester    string // リクエスト元（サービス名やユーザーIDなど）
-	Metadata     map[string]interface{}
+	UserID         string
+	CurrencyType   string // "paid" or "free"
+	Amount         int64
+	Reason         string
+	Requester      string     // リクエスト元（サービス名やユーザーIDなど）
+	ExpiresAt      *time.Time // 有効期限（任意、無償通貨のみ）
+	IdempotencyKey string     // 冪等性キー（任意、同じキーの再送は初回の結果を返す）
+	Metadata       map[string]interface{}
 }
 
 // GrantResponse 通貨付与レスポンス
@@ -30,13 +42,14 @@ type GrantResponse struct {
 
 // ConsumeRequest 通貨消費リクエスト
 type ConsumeRequest struct {
-	UserID       string
-	CurrencyType string // "paid", "free", or "auto"
-	Amount       int64
-	ItemID       string
-	UsePriority  bool   // 優先順位制御（無料通貨優先）
-	Requester    string // リクエスト元（サービス名やユーザーIDなど）
-	Metadata     map[string]interface{}
+	UserID         string
+	CurrencyType   string // "paid", "free", or "auto"
+	Amount         int64
+	ItemID         string
+	UsePriority    bool   // 優先順位制御（無料通貨優先）
+	Requester      string // リクエスト元（サービス名やユーザーIDなど）
+	IdempotencyKey string // 冪等性キー（任意、同じキーの再送は初回の結果を返す）
+	Metadata       map[string]interface{}
 }
 
 // ConsumeResponse 通貨消費レスポンス
@@ -55,3 +68,86 @@ type ConsumptionDetail struct {
 	BalanceBefore int64
 	BalanceAfter  int64
 }
+
+// RefundRequest 返金リクエスト
+type RefundRequest struct {
+	TransactionID string // 元の消費トランザクションID（優先順位消費時はベースID）
+	Reason        string
+	Requester     string // リクエスト元（サービス名やユーザーIDなど）
+	Metadata      map[string]interface{}
+}
+
+// RefundResponse 返金レスポンス
+type RefundResponse struct {
+	TransactionID         string
+	OriginalTransactionID string
+	UserID                string
+	RefundDetails         []RefundDetail
+	TotalRefunded         int64
+	Status                string
+}
+
+// RefundDetail 返金詳細
+type RefundDetail struct {
+	TransactionID         string
+	OriginalTransactionID string
+	CurrencyType          string
+	Amount                int64
+	BalanceBefore         int64
+	BalanceAfter          int64
+}
+
+// ExpireLotsRequest 有効期限切れロットの失効リクエスト
+type ExpireLotsRequest struct {
+	Limit int // 1回の実行で処理するロットの最大数
+}
+
+// ExpireLotsResponse 有効期限切れロットの失効レスポンス
+type ExpireLotsResponse struct {
+	ExpiredLots   int
+	ExpiredAmount int64
+}
+
+// CompensateRequest 補填（調整）リクエスト
+type CompensateRequest struct {
+	UserID       string
+	CurrencyType string // "paid" or "free"
+	Amount       int64  // 正の値で付与、負の値で回収（マイナス残高を許可）
+	Reason       string // 必須
+	Requester    string // 必須（オペレーターやツール名など）
+	Metadata     map[string]interface{}
+}
+
+// CompensateResponse 補填（調整）レスポンス
+type CompensateResponse struct {
+	TransactionID string
+	UserID        string
+	CurrencyType  string
+	Amount        int64 // 符号付きの調整額
+	BalanceBefore int64
+	BalanceAfter  int64
+	Status        string
+}
+
+// TransferRequest ユーザー間の通貨譲渡リクエスト
+type TransferRequest struct {
+	FromUserID   string
+	ToUserID     string
+	CurrencyType string // "paid" or "free"（譲渡ポリシーで許可されたもののみ）
+	Amount       int64
+	Message      string // 受取人へのメッセージ（任意）
+	Metadata     map[string]interface{}
+}
+
+// TransferResponse ユーザー間の通貨譲渡レスポンス
+type TransferResponse struct {
+	TransferID            string
+	FromUserID            string
+	ToUserID              string
+	CurrencyType          string
+	Amount                int64
+	SenderTransactionID   string
+	ReceiverTransactionID string
+	BalanceAfter          int64 // 送信者の譲渡後残高
+	Status                string
+}
@@ -0,0 +1,176 @@
+package currency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"gem-server/internal/domain/transaction"
+)
+
+// maxIdempotencyKeyLength 冪等性キーの最大長（transactions.idempotency_keyのカラム長）
+const maxIdempotencyKeyLength = 255
+
+// idempotencyPayload 冪等性キーの再利用判定に使うリクエスト内容
+type idempotencyPayload struct {
+	Operation    string                 `json:"operation"`
+	UserID       string                 `json:"user_id"`
+	CurrencyType string                 `json:"currency_type"`
+	Amount       int64                  `json:"amount"`
+	Reason       string                 `json:"reason,omitempty"`
+	ItemID       string                 `json:"item_id,omitempty"`
+	UsePriority  bool                   `json:"use_priority,omitempty"`
+	Requester    string                 `json:"requester,omitempty"`
+	ExpiresAt    *time.Time             `json:"expires_at,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// hash リクエスト内容のSHA-256ハッシュを返す（mapのキーはjson.Marshalでソートされる）
+func (p idempotencyPayload) hash() (string, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal idempotency payload: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// grantRequestHash 付与リクエストのハッシュを返す
+func grantRequestHash(req *GrantRequest) (string, error) {
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		t := req.ExpiresAt.UTC()
+		expiresAt = &t
+	}
+	return idempotencyPayload{
+		Operation:    "grant",
+		UserID:       req.UserID,
+		CurrencyType: req.CurrencyType,
+		Amount:       req.Amount,
+		Reason:       req.Reason,
+		Requester:    req.Requester,
+		ExpiresAt:    expiresAt,
+		Metadata:     req.Metadata,
+	}.hash()
+}
+
+// consumeRequestHash 消費リクエストのハッシュを返す
+func consumeRequestHash(req *ConsumeRequest) (string, error) {
+	return idempotencyPayload{
+		Operation:    "consume",
+		UserID:       req.UserID,
+		CurrencyType: req.CurrencyType,
+		Amount:       req.Amount,
+		ItemID:       req.ItemID,
+		UsePriority:  req.UsePriority,
+		Requester:    req.Requester,
+		Metadata:     req.Metadata,
+	}.hash()
+}
+
+// lookupIdempotencyKey 冪等性キーで記録済みのトランザクションを取得し、リクエストのハッシュと合わせて返す
+// キーが空の場合は何もしない。未使用のキーの場合はトランザクションがnil、
+// 異なるリクエスト内容で使用済みの場合はErrIdempotencyKeyConflictを返す
+func (s *CurrencyApplicationService) lookupIdempotencyKey(ctx context.Context, userID, idempotencyKey string, requestHash func() (string, error)) (string, []*transaction.Transaction, error) {
+	if idempotencyKey == "" {
+		return "", nil, nil
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return "", nil, transaction.ErrInvalidIdempotencyKey
+	}
+
+	hash, err := requestHash()
+	if err != nil {
+		return "", nil, err
+	}
+
+	txns, err := s.transactionRepo.FindByIdempotencyKey(ctx, userID, idempotencyKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find transactions by idempotency key: %w", err)
+	}
+	if len(txns) == 0 {
+		return hash, nil, nil
+	}
+
+	for _, txn := range txns {
+		if txn.RequestHash() == nil || *txn.RequestHash() != hash {
+			return "", nil, transaction.ErrIdempotencyKeyConflict
+		}
+	}
+
+	s.logger.Info(ctx, "Returning recorded result for idempotency key", map[string]interface{}{
+		"user_id":         userID,
+		"idempotency_key": idempotencyKey,
+		"transaction_id":  txns[0].TransactionID(),
+	})
+
+	return hash, txns, nil
+}
+
+// reserveIdempotencyKey 冪等性キーを記録する（残高を更新するトランザクション内で呼び出す）
+// 同じキーで同時に実行されたリクエストは、先に記録した方がコミットした後にErrDuplicateIdempotencyKeyで失敗する。
+// キーが空の場合は何もしない
+func (s *CurrencyApplicationService) reserveIdempotencyKey(ctx context.Context, userID, idempotencyKey, requestHash string) error {
+	if idempotencyKey == "" {
+		return nil
+	}
+	return s.transactionRepo.ReserveIdempotencyKey(ctx, userID, idempotencyKey, requestHash)
+}
+
+// replayIdempotencyKey 同じ冪等性キーで先に処理されたリクエストのトランザクションを返す
+// reserveIdempotencyKeyがErrDuplicateIdempotencyKeyを返し、トランザクションをロールバックした後に呼び出す
+func (s *CurrencyApplicationService) replayIdempotencyKey(ctx context.Context, userID, idempotencyKey, requestHash string) ([]*transaction.Transaction, error) {
+	_, recorded, err := s.lookupIdempotencyKey(ctx, userID, idempotencyKey, func() (string, error) {
+		return requestHash, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if recorded == nil {
+		// 記録済みのキーに対応するトランザクションがない場合は、再送で結果を取得させる
+		return nil, transaction.ErrIdempotencyKeyConflict
+	}
+	return recorded, nil
+}
+
+// grantResponseFromTransactions 記録済みのトランザクションから付与レスポンスを再構築
+func grantResponseFromTransactions(txns []*transaction.Transaction) *GrantResponse {
+	txn := txns[0]
+	return &GrantResponse{
+		TransactionID: txn.TransactionID(),
+		BalanceAfter:  txn.BalanceAfter(),
+		Status:        txn.Status().String(),
+	}
+}
+
+// consumeResponseFromTransactions 記録済みのトランザクションから消費レスポンスを再構築
+// 優先順位制御の消費は_free/_paidに分割されて記録されているため、ベースIDと消費詳細を復元する
+func consumeResponseFromTransactions(txns []*transaction.Transaction, withPriority bool) *ConsumeResponse {
+	if !withPriority {
+		txn := txns[0]
+		return &ConsumeResponse{
+			TransactionID: txn.TransactionID(),
+			BalanceAfter:  txn.BalanceAfter(),
+			Status:        txn.Status().String(),
+		}
+	}
+
+	resp := &ConsumeResponse{
+		TransactionID: strings.TrimSuffix(strings.TrimSuffix(txns[0].TransactionID(), "_free"), "_paid"),
+		Status:        txns[0].Status().String(),
+	}
+	for _, txn := range txns {
+		resp.ConsumptionDetails = append(resp.ConsumptionDetails, ConsumptionDetail{
+			CurrencyType:  txn.CurrencyType().String(),
+			Amount:        txn.Amount(),
+			BalanceBefore: txn.BalanceBefore(),
+			BalanceAfter:  txn.BalanceAfter(),
+		})
+		resp.TotalConsumed += txn.Amount()
+	}
+	return resp
+}
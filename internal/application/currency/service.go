@@ -2,9 +2,10 @@ package currency
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -13,6 +14,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/service"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
@@ -22,8 +24,12 @@ import (
 type CurrencyApplicationService struct {
 	currencyRepo    currency.CurrencyRepository
 	transactionRepo transaction.TransactionRepository
+	lotRepo         currency.LotRepository
 	txManager       transaction.TransactionManager
+	idGenerator     idgen.Generator
+	transferPolicy  *currency.TransferPolicy
 	currencyService *service.CurrencyService
+	expiryLock      currency.ExpiryLock
 	logger          *otelinfra.Logger
 	metrics         *otelinfra.Metrics
 	tracer          trace.Tracer
@@ -31,19 +37,28 @@ type CurrencyApplicationService struct {
 }
 
 // NewCurrencyApplicationService 新しいCurrencyApplicationServiceを作成
+// expiryLockがnilの場合は有効期限切れロットの一括失効をロックせずに実行する（単一インスタンス用）
 func NewCurrencyApplicationService(
 	currencyRepo currency.CurrencyRepository,
 	transactionRepo transaction.TransactionRepository,
+	lotRepo currency.LotRepository,
 	txManager transaction.TransactionManager,
+	idGenerator idgen.Generator,
+	transferPolicy *currency.TransferPolicy,
 	currencyService *service.CurrencyService,
+	expiryLock currency.ExpiryLock,
 	logger *otelinfra.Logger,
 	metrics *otelinfra.Metrics,
 ) *CurrencyApplicationService {
 	return &CurrencyApplicationService{
 		currencyRepo:    currencyRepo,
 		transactionRepo: transactionRepo,
+		lotRepo:         lotRepo,
 		txManager:       txManager,
+		idGenerator:     idGenerator,
+		transferPolicy:  transferPolicy,
 		currencyService: currencyService,
+		expiryLock:      expiryLock,
 		logger:          logger,
 		metrics:         metrics,
 		tracer:          otel.Tracer("currency-service"),
@@ -99,9 +114,34 @@ func (s *CurrencyApplicationService) GetBalance(ctx context.Context, req *GetBal
 		balances["free"] = 0
 	}
 
+	// 失効予定の無償通貨
+	lots, err := s.lotRepo.FindAvailableByUserIDAndType(ctx, req.UserID, currency.CurrencyTypeFree)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to find currency lots", err, map[string]interface{}{
+			"user_id": req.UserID,
+		})
+		return nil, fmt.Errorf("failed to find currency lots: %w", err)
+	}
+
+	now := time.Now()
+	expirations := make([]Expiration, 0, len(lots))
+	for _, lot := range lots {
+		if lot.IsExpired(now) {
+			continue
+		}
+		expirations = append(expirations, Expiration{
+			CurrencyType: lot.CurrencyType().String(),
+			Amount:       lot.Remaining(),
+			ExpiresAt:    lot.ExpiresAt(),
+		})
+	}
+
 	return &GetBalanceResponse{
-		UserID:   req.UserID,
-		Balances: balances,
+		UserID:      req.UserID,
+		Balances:    balances,
+		Expirations: expirations,
 	}, nil
 }
 
@@ -137,11 +177,46 @@ func (s *CurrencyApplicationService) Grant(ctx context.Context, req *GrantReques
 		return nil, err
 	}
 
+	// 有効期限付きの付与は無償通貨のみ
+	metadata := req.Metadata
+	if req.ExpiresAt != nil {
+		if currencyType != currency.CurrencyTypeFree || !req.ExpiresAt.After(time.Now()) {
+			err := currency.ErrInvalidExpiry
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		metadata = make(map[string]interface{}, len(req.Metadata)+1)
+		for k, v := range req.Metadata {
+			metadata[k] = v
+		}
+		metadata["expires_at"] = req.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	// 冪等性キーが使用済みの場合は初回の結果を返す
+	requestHash, recorded, err := s.lookupIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, func() (string, error) {
+		return grantRequestHash(req)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+	if recorded != nil {
+		span.SetAttributes(attribute.Bool("idempotent_replay", true))
+		return grantResponseFromTransactions(recorded), nil
+	}
+
 	// トランザクションIDを生成
 	transactionID := s.generateTransactionID()
 
 	var result *GrantResponse
-	err = s.txManager.WithTransaction(ctx, func(tx *sql.Tx) error {
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 冪等性キーを記録（同じキーの同時リクエストは一意制約で後から来た方を失敗させる）
+		if err := s.reserveIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, requestHash); err != nil {
+			return err
+		}
+
 		// 楽観的ロックのリトライロジック
 		var retryErr error
 		for attempt := 0; attempt < s.maxRetries; attempt++ {
@@ -201,16 +276,30 @@ func (s *CurrencyApplicationService) Grant(ctx context.Context, req *GrantReques
 				c.Balance(),
 				transaction.TransactionStatusCompleted,
 				requesterPtr,
-				req.Metadata,
+				metadata,
 			)
 			if err != nil {
 				return fmt.Errorf("failed to create transaction entity: %w", err)
 			}
+			if req.IdempotencyKey != "" {
+				txn.SetIdempotencyKey(req.IdempotencyKey, requestHash)
+			}
 
 			if err := s.transactionRepo.Save(ctx, txn); err != nil {
 				return fmt.Errorf("failed to save transaction: %w", err)
 			}
 
+			// 有効期限付きの場合はロットとして記録（ロットIDは付与トランザクションID）
+			if req.ExpiresAt != nil {
+				lot, err := currency.NewLot(transactionID, req.UserID, currencyType, req.Amount, req.Amount, *req.ExpiresAt)
+				if err != nil {
+					return fmt.Errorf("failed to create lot entity: %w", err)
+				}
+				if err := s.lotRepo.Create(ctx, lot); err != nil {
+					return fmt.Errorf("failed to create lot: %w", err)
+				}
+			}
+
 			// メトリクス記録
 			s.metrics.RecordTransaction(ctx, "grant", currencyType.String())
 			s.metrics.RecordCurrencyBalance(ctx, req.UserID, currencyType.String(), c.Balance())
@@ -227,6 +316,16 @@ func (s *CurrencyApplicationService) Grant(ctx context.Context, req *GrantReques
 		return retryErr
 	})
 
+	// 同じ冪等性キーのリクエストが先に処理された場合はその結果を返す
+	if errors.Is(err, transaction.ErrDuplicateIdempotencyKey) {
+		recorded, replayErr := s.replayIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, requestHash)
+		if replayErr == nil {
+			span.SetAttributes(attribute.Bool("idempotent_replay", true))
+			return grantResponseFromTransactions(recorded), nil
+		}
+		err = replayErr
+	}
+
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
@@ -284,11 +383,30 @@ func (s *CurrencyApplicationService) Consume(ctx context.Context, req *ConsumeRe
 		return nil, err
 	}
 
+	// 冪等性キーが使用済みの場合は初回の結果を返す
+	requestHash, recorded, err := s.lookupIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, func() (string, error) {
+		return consumeRequestHash(req)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+	if recorded != nil {
+		span.SetAttributes(attribute.Bool("idempotent_replay", true))
+		return consumeResponseFromTransactions(recorded, false), nil
+	}
+
 	// トランザクションIDを生成
 	transactionID := s.generateTransactionID()
 
 	var result *ConsumeResponse
-	err = s.txManager.WithTransaction(ctx, func(tx *sql.Tx) error {
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 冪等性キーを記録（同じキーの同時リクエストは一意制約で後から来た方を失敗させる）
+		if err := s.reserveIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, requestHash); err != nil {
+			return err
+		}
+
 		// 楽観的ロックのリトライロジック
 		var retryErr error
 		for attempt := 0; attempt < s.maxRetries; attempt++ {
@@ -305,6 +423,16 @@ func (s *CurrencyApplicationService) Consume(ctx context.Context, req *ConsumeRe
 
 			balanceBefore := c.Balance()
 
+			// 失効処理前の期限切れロットの残量は消費できない
+			now := time.Now()
+			spendable, lots, err := s.spendableBalance(ctx, c, now)
+			if err != nil {
+				return err
+			}
+			if spendable < req.Amount {
+				return currency.ErrInsufficientBalance
+			}
+
 			// 通貨を消費
 			if err := c.Consume(req.Amount); err != nil {
 				return err
@@ -319,6 +447,11 @@ func (s *CurrencyApplicationService) Consume(ctx context.Context, req *ConsumeRe
 				return fmt.Errorf("failed to save currency after retries: %w", err)
 			}
 
+			// 有効期限の近いロットから消費
+			if _, err := s.consumeLots(ctx, transactionID, lots, req.Amount, now); err != nil {
+				return err
+			}
+
 			// トランザクション履歴を記録
 			var requesterPtr *string
 			if req.Requester != "" {
@@ -339,6 +472,9 @@ func (s *CurrencyApplicationService) Consume(ctx context.Context, req *ConsumeRe
 			if err != nil {
 				return fmt.Errorf("failed to create transaction entity: %w", err)
 			}
+			if req.IdempotencyKey != "" {
+				txn.SetIdempotencyKey(req.IdempotencyKey, requestHash)
+			}
 
 			if err := s.transactionRepo.Save(ctx, txn); err != nil {
 				return fmt.Errorf("failed to save transaction: %w", err)
@@ -360,6 +496,16 @@ func (s *CurrencyApplicationService) Consume(ctx context.Context, req *ConsumeRe
 		return retryErr
 	})
 
+	// 同じ冪等性キーのリクエストが先に処理された場合はその結果を返す
+	if errors.Is(err, transaction.ErrDuplicateIdempotencyKey) {
+		recorded, replayErr := s.replayIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, requestHash)
+		if replayErr == nil {
+			span.SetAttributes(attribute.Bool("idempotent_replay", true))
+			return consumeResponseFromTransactions(recorded, false), nil
+		}
+		err = replayErr
+	}
+
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
@@ -404,6 +550,20 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 		return nil, err
 	}
 
+	// 冪等性キーが使用済みの場合は初回の結果を返す（再送時の残高不足で失敗しないよう残高チェックより前に行う）
+	requestHash, recorded, err := s.lookupIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, func() (string, error) {
+		return consumeRequestHash(req)
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+	if recorded != nil {
+		span.SetAttributes(attribute.Bool("idempotent_replay", true))
+		return consumeResponseFromTransactions(recorded, true), nil
+	}
+
 	// 残高チェック
 	hasBalance, err := s.currencyService.HasSufficientBalance(ctx, req.UserID, req.Amount)
 	if err != nil {
@@ -425,7 +585,12 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 	var consumptionDetails []ConsumptionDetail
 	var totalConsumed int64
 
-	err = s.txManager.WithTransaction(ctx, func(tx *sql.Tx) error {
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 冪等性キーを記録（同じキーの同時リクエストは一意制約で後から来た方を失敗させる）
+		if err := s.reserveIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, requestHash); err != nil {
+			return err
+		}
+
 		remainingAmount := req.Amount
 
 		// 無料通貨から消費
@@ -434,11 +599,22 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 			return fmt.Errorf("failed to find free currency: %w", err)
 		}
 
-		if freeCurrency != nil && freeCurrency.Balance() > 0 {
+		// 失効処理前の期限切れロットの残量は消費できない
+		now := time.Now()
+		var freeSpendable int64
+		var freeLots []*currency.Lot
+		if freeCurrency != nil {
+			freeSpendable, freeLots, err = s.spendableBalance(ctx, freeCurrency, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if freeSpendable > 0 {
 			freeBalanceBefore := freeCurrency.Balance()
 			freeConsumeAmount := remainingAmount
-			if freeConsumeAmount > freeBalanceBefore {
-				freeConsumeAmount = freeBalanceBefore
+			if freeConsumeAmount > freeSpendable {
+				freeConsumeAmount = freeSpendable
 			}
 
 			// 楽観的ロックのリトライロジック
@@ -452,10 +628,14 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 					if err != nil {
 						return fmt.Errorf("failed to find free currency: %w", err)
 					}
+					freeSpendable, freeLots, err = s.spendableBalance(ctx, freeCurrency, now)
+					if err != nil {
+						return err
+					}
 					freeBalanceBefore = freeCurrency.Balance()
 					freeConsumeAmount = remainingAmount
-					if freeConsumeAmount > freeBalanceBefore {
-						freeConsumeAmount = freeBalanceBefore
+					if freeConsumeAmount > freeSpendable {
+						freeConsumeAmount = freeSpendable
 					}
 				}
 
@@ -471,6 +651,11 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 					return fmt.Errorf("failed to save free currency after retries: %w", err)
 				}
 
+				// 有効期限の近いロットから消費
+				if _, err := s.consumeLots(ctx, fmt.Sprintf("%s_free", transactionID), freeLots, freeConsumeAmount, now); err != nil {
+					return err
+				}
+
 				consumptionDetails = append(consumptionDetails, ConsumptionDetail{
 					CurrencyType:  "free",
 					Amount:        freeConsumeAmount,
@@ -501,6 +686,9 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 				if err != nil {
 					return fmt.Errorf("failed to create transaction entity: %w", err)
 				}
+				if req.IdempotencyKey != "" {
+					txn.SetIdempotencyKey(req.IdempotencyKey, requestHash)
+				}
 				if err := s.transactionRepo.Save(ctx, txn); err != nil {
 					return fmt.Errorf("failed to save transaction: %w", err)
 				}
@@ -580,6 +768,9 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 				if err != nil {
 					return fmt.Errorf("failed to create transaction entity: %w", err)
 				}
+				if req.IdempotencyKey != "" {
+					txn.SetIdempotencyKey(req.IdempotencyKey, requestHash)
+				}
 				if err := s.transactionRepo.Save(ctx, txn); err != nil {
 					return fmt.Errorf("failed to save transaction: %w", err)
 				}
@@ -605,6 +796,16 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 		return nil
 	})
 
+	// 同じ冪等性キーのリクエストが先に処理された場合はその結果を返す
+	if errors.Is(err, transaction.ErrDuplicateIdempotencyKey) {
+		recorded, replayErr := s.replayIdempotencyKey(ctx, req.UserID, req.IdempotencyKey, requestHash)
+		if replayErr == nil {
+			span.SetAttributes(attribute.Bool("idempotent_replay", true))
+			return consumeResponseFromTransactions(recorded, true), nil
+		}
+		err = replayErr
+	}
+
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
@@ -625,7 +826,881 @@ func (s *CurrencyApplicationService) ConsumeWithPriority(ctx context.Context, re
 	return result, nil
 }
 
+// ExpireLots 有効期限切れのロットを失効させ、expireトランザクションを記録
+// 複数インスタンスで同時に実行しないよう、ロックを取得できない場合はErrExpirySweepInProgressを返す
+// ロット1件ごとにDBトランザクションを分け、失敗した時点で処理を中断する
+func (s *CurrencyApplicationService) ExpireLots(ctx context.Context, req *ExpireLotsRequest) (*ExpireLotsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CurrencyApplicationService.ExpireLots")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("limit", req.Limit),
+	)
+
+	if s.expiryLock != nil {
+		release, acquired, err := s.expiryLock.TryLock(ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			s.logger.Error(ctx, "Failed to acquire lot expiry lock", err, nil)
+			s.metrics.RecordError(ctx, "expire_failed")
+			return nil, fmt.Errorf("failed to acquire lot expiry lock: %w", err)
+		}
+		if !acquired {
+			err := currency.ErrExpirySweepInProgress
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, err
+		}
+		defer release()
+	}
+
+	result := &ExpireLotsResponse{}
+	for i := 0; i < req.Limit; i++ {
+		var processed bool
+		err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			// トランザクション内で再取得して最新の残量を使う
+			lots, err := s.lotRepo.FindExpired(ctx, time.Now(), 1)
+			if err != nil {
+				return fmt.Errorf("failed to find expired lots: %w", err)
+			}
+			if len(lots) == 0 {
+				return nil
+			}
+			processed = true
+
+			expired, err := s.expireLot(ctx, lots[0])
+			if err != nil {
+				return err
+			}
+			result.ExpiredLots++
+			result.ExpiredAmount += expired
+			return nil
+		})
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			s.logger.Error(ctx, "Failed to expire currency lots", err, map[string]interface{}{
+				"expired_lots": result.ExpiredLots,
+			})
+			s.metrics.RecordError(ctx, "expire_failed")
+			return result, err
+		}
+		if !processed {
+			break
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("expired_lots", result.ExpiredLots),
+		attribute.Int64("expired_amount", result.ExpiredAmount),
+	)
+
+	if result.ExpiredLots > 0 {
+		s.logger.Info(ctx, "Currency lots expired", map[string]interface{}{
+			"expired_lots":   result.ExpiredLots,
+			"expired_amount": result.ExpiredAmount,
+		})
+	}
+
+	return result, nil
+}
+
+// expireLot 1件のロットを失効させ、残高から差し引いた数量を返す（トランザクション内で呼び出す）
+func (s *CurrencyApplicationService) expireLot(ctx context.Context, lot *currency.Lot) (int64, error) {
+	remaining := lot.Expire()
+
+	c, err := s.currencyRepo.FindByUserIDAndType(ctx, lot.UserID(), lot.CurrencyType())
+	if err != nil && err != currency.ErrCurrencyNotFound {
+		return 0, fmt.Errorf("failed to find currency: %w", err)
+	}
+
+	// 残高が不足している場合（手動調整など）は残高の範囲で失効させる
+	var expired int64
+	if c != nil && c.Balance() > 0 {
+		expired = remaining
+		if expired > c.Balance() {
+			expired = c.Balance()
+		}
+	}
+
+	if expired > 0 {
+		balanceBefore := c.Balance()
+		if err := c.Consume(expired); err != nil {
+			return 0, err
+		}
+		if err := s.currencyRepo.Save(ctx, c); err != nil {
+			return 0, fmt.Errorf("failed to save currency: %w", err)
+		}
+
+		txn, err := transaction.NewTransaction(
+			fmt.Sprintf("expire_%s", lot.LotID()),
+			lot.UserID(),
+			transaction.TransactionTypeExpire,
+			lot.CurrencyType(),
+			expired,
+			balanceBefore,
+			c.Balance(),
+			transaction.TransactionStatusCompleted,
+			map[string]interface{}{
+				"lot_id":     lot.LotID(),
+				"expires_at": lot.ExpiresAt().UTC().Format(time.RFC3339),
+			},
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create transaction entity: %w", err)
+		}
+		if err := s.transactionRepo.Save(ctx, txn); err != nil {
+			return 0, fmt.Errorf("failed to save transaction: %w", err)
+		}
+
+		s.metrics.RecordTransaction(ctx, "expire", lot.CurrencyType().String())
+		s.metrics.RecordCurrencyBalance(ctx, lot.UserID(), lot.CurrencyType().String(), c.Balance())
+	}
+
+	if err := s.lotRepo.Save(ctx, lot); err != nil {
+		return 0, fmt.Errorf("failed to save lot: %w", err)
+	}
+
+	return expired, nil
+}
+
+// spendableBalance 消費可能な残高と残量のあるロットを返す（トランザクション内で呼び出す）
+// 有効期限を過ぎたが失効処理前のロットの残量は失効処理で差し引かれるため、消費可能な残高に含めない。
+// 有効期限付きの付与は無償通貨のみのため、それ以外の通貨タイプでは残高をそのまま返す
+func (s *CurrencyApplicationService) spendableBalance(ctx context.Context, c *currency.Currency, now time.Time) (int64, []*currency.Lot, error) {
+	if c.CurrencyType() != currency.CurrencyTypeFree {
+		return c.Balance(), nil, nil
+	}
+
+	lots, err := s.lotRepo.FindAvailableByUserIDAndType(ctx, c.UserID(), c.CurrencyType())
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to find currency lots: %w", err)
+	}
+
+	return c.Balance() - currency.ExpiredRemaining(lots, now), lots, nil
+}
+
+// consumeLots 有効期限の近いロットから順に消費し、消費した内訳をトランザクションIDに紐付けて記録する（トランザクション内で呼び出す）
+// lotsはspendableBalanceで取得したもの
+func (s *CurrencyApplicationService) consumeLots(ctx context.Context, transactionID string, lots []*currency.Lot, amount int64, now time.Time) ([]currency.LotConsumption, error) {
+	touched, consumptions := currency.AllocateLots(lots, amount, now)
+	for _, lot := range touched {
+		if err := s.lotRepo.Save(ctx, lot); err != nil {
+			return nil, fmt.Errorf("failed to save lot: %w", err)
+		}
+	}
+
+	if len(consumptions) > 0 {
+		if err := s.lotRepo.SaveConsumptions(ctx, transactionID, consumptions); err != nil {
+			return nil, fmt.Errorf("failed to save lot consumptions: %w", err)
+		}
+	}
+
+	return consumptions, nil
+}
+
+// grantLots 消費内訳と同じ数量・有効期限のロットを作成する（トランザクション内で呼び出す）
+// ロットIDはtransactionIDに連番を付与したもの
+func (s *CurrencyApplicationService) grantLots(ctx context.Context, transactionID string, userID string, currencyType currency.CurrencyType, consumptions []currency.LotConsumption) error {
+	for i, c := range consumptions {
+		lot, err := currency.NewLot(fmt.Sprintf("%s_%d", transactionID, i+1), userID, currencyType, c.Amount, c.Amount, c.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("failed to create lot entity: %w", err)
+		}
+		if err := s.lotRepo.Create(ctx, lot); err != nil {
+			return fmt.Errorf("failed to create lot: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hasAnyCurrency ユーザーがexclude以外の通貨タイプの残高を持つかどうかを返す（トランザクション内で呼び出す）
+func (s *CurrencyApplicationService) hasAnyCurrency(ctx context.Context, userID string, exclude currency.CurrencyType) (bool, error) {
+	for _, ct := range []currency.CurrencyType{currency.CurrencyTypePaid, currency.CurrencyTypeFree} {
+		if ct == exclude {
+			continue
+		}
+		_, err := s.currencyRepo.FindByUserIDAndType(ctx, userID, ct)
+		if err == nil {
+			return true, nil
+		}
+		if err != currency.ErrCurrencyNotFound {
+			return false, fmt.Errorf("failed to find currency: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// Refund 消費トランザクションを返金
+// ConsumeWithPriorityで分割された消費は、ベースIDを指定すると_free/_paidの両方を返金する
+func (s *CurrencyApplicationService) Refund(ctx context.Context, req *RefundRequest) (*RefundResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CurrencyApplicationService.Refund")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("transaction_id", req.TransactionID),
+	)
+
+	s.logger.Info(ctx, "Refunding transaction", map[string]interface{}{
+		"transaction_id": req.TransactionID,
+	})
+
+	// バリデーション
+	if req.TransactionID == "" {
+		err := transaction.ErrInvalidTransactionID
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	refundTransactionID := refundTransactionIDFor(req.TransactionID)
+
+	var result *RefundResponse
+	err := s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		originals, err := s.findRefundTargets(ctx, req.TransactionID)
+		if err != nil {
+			return err
+		}
+
+		userID := originals[0].UserID()
+		var refundDetails []RefundDetail
+		var totalRefunded int64
+
+		for _, original := range originals {
+			detail, err := s.refundTransaction(ctx, original, req)
+			if err != nil {
+				return err
+			}
+			refundDetails = append(refundDetails, *detail)
+			totalRefunded += detail.Amount
+		}
+
+		result = &RefundResponse{
+			TransactionID:         refundTransactionID,
+			OriginalTransactionID: req.TransactionID,
+			UserID:                userID,
+			RefundDetails:         refundDetails,
+			TotalRefunded:         totalRefunded,
+			Status:                "completed",
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to refund transaction", err, map[string]interface{}{
+			"transaction_id": req.TransactionID,
+		})
+		s.metrics.RecordError(ctx, "refund_failed")
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Transaction refunded successfully", map[string]interface{}{
+		"user_id":                 result.UserID,
+		"transaction_id":          refundTransactionID,
+		"original_transaction_id": req.TransactionID,
+		"total_refunded":          result.TotalRefunded,
+	})
+
+	return result, nil
+}
+
+// findRefundTargets 返金対象の消費トランザクションを取得
+// 指定IDが存在しない場合はConsumeWithPriorityの_free/_paidトランザクションを探す
+func (s *CurrencyApplicationService) findRefundTargets(ctx context.Context, transactionID string) ([]*transaction.Transaction, error) {
+	var originals []*transaction.Transaction
+
+	txn, err := s.transactionRepo.FindByTransactionID(ctx, transactionID)
+	switch {
+	case err == nil:
+		originals = append(originals, txn)
+	case errors.Is(err, transaction.ErrTransactionNotFound):
+		for _, suffix := range []string{"_free", "_paid"} {
+			part, err := s.transactionRepo.FindByTransactionID(ctx, transactionID+suffix)
+			if errors.Is(err, transaction.ErrTransactionNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to find transaction: %w", err)
+			}
+			originals = append(originals, part)
+		}
+	default:
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+
+	if len(originals) == 0 {
+		return nil, transaction.ErrTransactionNotFound
+	}
+
+	for _, original := range originals {
+		if original.TransactionType() != transaction.TransactionTypeConsume || !original.Status().IsCompleted() {
+			return nil, transaction.ErrTransactionNotRefundable
+		}
+
+		// 返金トランザクションIDは元のIDから一意に決まるため、存在すれば返金済み
+		_, err := s.transactionRepo.FindByTransactionID(ctx, refundTransactionIDFor(original.TransactionID()))
+		if err == nil {
+			return nil, transaction.ErrTransactionAlreadyRefunded
+		}
+		if !errors.Is(err, transaction.ErrTransactionNotFound) {
+			return nil, fmt.Errorf("failed to find refund transaction: %w", err)
+		}
+	}
+
+	return originals, nil
+}
+
+// refundTransaction 1件の消費トランザクションを返金（トランザクション内で呼び出す）
+func (s *CurrencyApplicationService) refundTransaction(ctx context.Context, original *transaction.Transaction, req *RefundRequest) (*RefundDetail, error) {
+	currencyType := original.CurrencyType()
+	refundID := refundTransactionIDFor(original.TransactionID())
+
+	// 楽観的ロックのリトライロジック
+	var retryErr error
+	for attempt := 0; attempt < s.maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := time.Duration(math.Pow(2, float64(attempt-1))) * 10 * time.Millisecond
+			time.Sleep(backoff)
+		}
+
+		c, err := s.currencyRepo.FindByUserIDAndType(ctx, original.UserID(), currencyType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find currency: %w", err)
+		}
+
+		balanceBefore := c.Balance()
+
+		// 返金分を残高に戻す（有効期限付きのロットから消費した分は、後で同じ有効期限のロットとして戻す）
+		if err := c.Grant(original.Amount()); err != nil {
+			return nil, err
+		}
+
+		if err := s.currencyRepo.Save(ctx, c); err != nil {
+			if attempt < s.maxRetries-1 {
+				retryErr = err
+				continue
+			}
+			return nil, fmt.Errorf("failed to save currency after retries: %w", err)
+		}
+
+		// 元トランザクションとの紐付けをメタデータに記録
+		metadata := make(map[string]interface{}, len(req.Metadata)+2)
+		for k, v := range req.Metadata {
+			metadata[k] = v
+		}
+		metadata["original_transaction_id"] = original.TransactionID()
+		if req.Reason != "" {
+			metadata["reason"] = req.Reason
+		}
+
+		var requesterPtr *string
+		if req.Requester != "" {
+			requesterPtr = &req.Requester
+		}
+		txn, err := transaction.NewTransactionWithRequester(
+			refundID,
+			original.UserID(),
+			transaction.TransactionTypeRefund,
+			currencyType,
+			original.Amount(),
+			balanceBefore,
+			c.Balance(),
+			transaction.TransactionStatusCompleted,
+			requesterPtr,
+			metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transaction entity: %w", err)
+		}
+
+		if err := s.transactionRepo.Save(ctx, txn); err != nil {
+			// 同時に実行された返金が先に記録された
+			if errors.Is(err, transaction.ErrDuplicateTransactionID) {
+				return nil, transaction.ErrTransactionAlreadyRefunded
+			}
+			return nil, fmt.Errorf("failed to save transaction: %w", err)
+		}
+
+		// 消費元のロットと同じ有効期限のロットを作成する（失効済みの期限であれば失効ジョブで差し引かれる）
+		if currencyType == currency.CurrencyTypeFree {
+			consumptions, err := s.lotRepo.FindConsumptionsByTransactionID(ctx, original.TransactionID())
+			if err != nil {
+				return nil, fmt.Errorf("failed to find lot consumptions: %w", err)
+			}
+			if err := s.grantLots(ctx, refundID, original.UserID(), currencyType, consumptions); err != nil {
+				return nil, err
+			}
+		}
+
+		s.metrics.RecordTransaction(ctx, "refund", currencyType.String())
+		s.metrics.RecordCurrencyBalance(ctx, original.UserID(), currencyType.String(), c.Balance())
+
+		return &RefundDetail{
+			TransactionID:         refundID,
+			OriginalTransactionID: original.TransactionID(),
+			CurrencyType:          currencyType.String(),
+			Amount:                original.Amount(),
+			BalanceBefore:         balanceBefore,
+			BalanceAfter:          c.Balance(),
+		}, nil
+	}
+
+	return nil, retryErr
+}
+
+// refundTransactionIDFor 元のトランザクションIDから返金トランザクションIDを生成
+func refundTransactionIDFor(transactionID string) string {
+	return fmt.Sprintf("refund_%s", transactionID)
+}
+
+// Compensate 補填・回収による残高調整
+// 正の金額は付与、負の金額は回収として扱い、回収時はマイナス残高を許可する
+func (s *CurrencyApplicationService) Compensate(ctx context.Context, req *CompensateRequest) (*CompensateResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CurrencyApplicationService.Compensate")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("user_id", req.UserID),
+		attribute.String("currency_type", req.CurrencyType),
+		attribute.Int64("amount", req.Amount),
+		attribute.String("requester", req.Requester),
+	)
+
+	s.logger.Info(ctx, "Compensating currency", map[string]interface{}{
+		"user_id":       req.UserID,
+		"currency_type": req.CurrencyType,
+		"amount":        req.Amount,
+		"requester":     req.Requester,
+	})
+
+	// バリデーション
+	var validationErr error
+	switch {
+	case req.Amount == 0:
+		validationErr = currency.ErrInvalidAmount
+	case req.Reason == "":
+		validationErr = transaction.ErrReasonRequired
+	case req.Requester == "":
+		validationErr = transaction.ErrRequesterRequired
+	}
+	if validationErr != nil {
+		span.RecordError(validationErr)
+		span.SetStatus(otelcodes.Error, validationErr.Error())
+		return nil, validationErr
+	}
+
+	currencyType, err := currency.NewCurrencyType(req.CurrencyType)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	direction := "credit"
+	amount := req.Amount
+	if amount < 0 {
+		direction = "debit"
+		amount = -amount
+	}
+
+	metadata := make(map[string]interface{}, len(req.Metadata)+2)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+	metadata["reason"] = req.Reason
+	metadata["direction"] = direction
+
+	// トランザクションIDを生成
+	transactionID := s.generateTransactionID()
+
+	var result *CompensateResponse
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 楽観的ロックのリトライロジック
+		var retryErr error
+		for attempt := 0; attempt < s.maxRetries; attempt++ {
+			if attempt > 0 {
+				// 指数バックオフ
+				backoff := time.Duration(math.Pow(2, float64(attempt-1))) * 10 * time.Millisecond
+				time.Sleep(backoff)
+			}
+
+			// 通貨を取得
+			c, err := s.currencyRepo.FindByUserIDAndType(ctx, req.UserID, currencyType)
+			if err != nil && err != currency.ErrCurrencyNotFound {
+				return fmt.Errorf("failed to find currency: %w", err)
+			}
+
+			if c == nil {
+				// 通貨が存在しない場合は作成（回収時は残高0からマイナスになる）
+				c, err = currency.NewCurrency(req.UserID, currencyType, 0, 0)
+				if err != nil {
+					return fmt.Errorf("failed to create currency entity: %w", err)
+				}
+				if err := s.currencyRepo.Create(ctx, c); err != nil {
+					return fmt.Errorf("failed to create currency: %w", err)
+				}
+			}
+
+			balanceBefore := c.Balance()
+
+			if req.Amount > 0 {
+				// 補填分は有効期限なしの残高として付与
+				if err := c.Grant(amount); err != nil {
+					return err
+				}
+			} else {
+				if err := c.ConsumeAllowNegative(amount); err != nil {
+					return err
+				}
+			}
+
+			// 保存（楽観的ロック）
+			if err := s.currencyRepo.Save(ctx, c); err != nil {
+				// 楽観的ロックエラーの場合はリトライ
+				if attempt < s.maxRetries-1 {
+					retryErr = err
+					continue
+				}
+				return fmt.Errorf("failed to save currency after retries: %w", err)
+			}
+
+			// 回収時は有効期限付きのロットからも差し引く
+			if req.Amount < 0 {
+				now := time.Now()
+				_, lots, err := s.spendableBalance(ctx, c, now)
+				if err != nil {
+					return err
+				}
+				if _, err := s.consumeLots(ctx, transactionID, lots, amount, now); err != nil {
+					return err
+				}
+			}
+
+			// トランザクション履歴を記録
+			requester := req.Requester
+			txn, err := transaction.NewTransactionWithRequester(
+				transactionID,
+				req.UserID,
+				transaction.TransactionTypeCompensate,
+				currencyType,
+				amount,
+				balanceBefore,
+				c.Balance(),
+				transaction.TransactionStatusCompleted,
+				&requester,
+				metadata,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create transaction entity: %w", err)
+			}
+
+			if err := s.transactionRepo.Save(ctx, txn); err != nil {
+				return fmt.Errorf("failed to save transaction: %w", err)
+			}
+
+			// メトリクス記録
+			s.metrics.RecordTransaction(ctx, "compensate", currencyType.String())
+			s.metrics.RecordCurrencyBalance(ctx, req.UserID, currencyType.String(), c.Balance())
+			if balanceBefore >= 0 && c.Balance() < 0 {
+				s.metrics.RecordNegativeBalance(ctx, req.UserID, currencyType.String())
+			}
+
+			result = &CompensateResponse{
+				TransactionID: transactionID,
+				UserID:        req.UserID,
+				CurrencyType:  currencyType.String(),
+				Amount:        req.Amount,
+				BalanceBefore: balanceBefore,
+				BalanceAfter:  c.Balance(),
+				Status:        "completed",
+			}
+
+			return nil
+		}
+
+		return retryErr
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to compensate currency", err, map[string]interface{}{
+			"user_id":       req.UserID,
+			"currency_type": req.CurrencyType,
+			"amount":        req.Amount,
+		})
+		s.metrics.RecordError(ctx, "compensate_failed")
+		return nil, err
+	}
+
+	if result.BalanceAfter < 0 {
+		s.logger.Warn(ctx, "Balance became negative after compensation", map[string]interface{}{
+			"user_id":        req.UserID,
+			"transaction_id": transactionID,
+			"currency_type":  result.CurrencyType,
+			"balance_after":  result.BalanceAfter,
+		})
+	}
+
+	s.logger.Info(ctx, "Currency compensated successfully", map[string]interface{}{
+		"user_id":        req.UserID,
+		"transaction_id": transactionID,
+		"balance_after":  result.BalanceAfter,
+	})
+
+	return result, nil
+}
+
+// Transfer ユーザー間で通貨を譲渡
+// 送信者からの消費と受信者への付与を1つのDBトランザクションで行い、
+// 譲渡IDを共有するtransfer_out/transfer_inの2つのトランザクションを記録する
+func (s *CurrencyApplicationService) Transfer(ctx context.Context, req *TransferRequest) (*TransferResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "CurrencyApplicationService.Transfer")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("from_user_id", req.FromUserID),
+		attribute.String("to_user_id", req.ToUserID),
+		attribute.String("currency_type", req.CurrencyType),
+		attribute.Int64("amount", req.Amount),
+	)
+
+	s.logger.Info(ctx, "Transferring currency", map[string]interface{}{
+		"from_user_id":  req.FromUserID,
+		"to_user_id":    req.ToUserID,
+		"currency_type": req.CurrencyType,
+		"amount":        req.Amount,
+	})
+
+	// バリデーション
+	if req.Amount <= 0 {
+		err := currency.ErrInvalidAmount
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+	if req.FromUserID == req.ToUserID {
+		err := currency.ErrSelfTransfer
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	currencyType, err := currency.NewCurrencyType(req.CurrencyType)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	if !s.transferPolicy.CanTransfer(currencyType) {
+		err := currency.ErrCurrencyNotTransferable
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	// 譲渡IDを生成（送信側・受信側のトランザクションIDはこれに_out/_inを付与したもの）
+	transferID := "trf_" + s.idGenerator.NewID()
+	senderTransactionID := fmt.Sprintf("%s_out", transferID)
+	receiverTransactionID := fmt.Sprintf("%s_in", transferID)
+
+	senderMetadata := make(map[string]interface{}, len(req.Metadata)+3)
+	receiverMetadata := make(map[string]interface{}, len(req.Metadata)+3)
+	for k, v := range req.Metadata {
+		senderMetadata[k] = v
+		receiverMetadata[k] = v
+	}
+	senderMetadata["transfer_id"] = transferID
+	senderMetadata["to_user_id"] = req.ToUserID
+	receiverMetadata["transfer_id"] = transferID
+	receiverMetadata["from_user_id"] = req.FromUserID
+	if req.Message != "" {
+		senderMetadata["message"] = req.Message
+		receiverMetadata["message"] = req.Message
+	}
+
+	var result *TransferResponse
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		// 楽観的ロックのリトライロジック
+		var retryErr error
+		for attempt := 0; attempt < s.maxRetries; attempt++ {
+			if attempt > 0 {
+				// 指数バックオフ
+				backoff := time.Duration(math.Pow(2, float64(attempt-1))) * 10 * time.Millisecond
+				time.Sleep(backoff)
+			}
+
+			// 送信者と受信者の通貨をユーザーIDの昇順でロックする
+			// 逆方向の譲渡が同時に実行されてもロックの取得順が同じになり、デッドロックしない
+			lockOrder := []string{req.FromUserID, req.ToUserID}
+			sort.Strings(lockOrder)
+			locked := make(map[string]*currency.Currency, len(lockOrder))
+			for _, userID := range lockOrder {
+				c, err := s.currencyRepo.FindByUserIDAndType(ctx, userID, currencyType)
+				if err != nil && err != currency.ErrCurrencyNotFound {
+					return fmt.Errorf("failed to find currency: %w", err)
+				}
+				locked[userID] = c
+			}
+
+			sender := locked[req.FromUserID]
+			if sender == nil {
+				return currency.ErrInsufficientBalance
+			}
+
+			receiver := locked[req.ToUserID]
+			if receiver == nil {
+				// 譲渡する通貨タイプの残高がない受信者は、他の通貨タイプの残高があれば登録済みのユーザーとして通貨を作成する
+				exists, err := s.hasAnyCurrency(ctx, req.ToUserID, currencyType)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return currency.ErrRecipientNotFound
+				}
+				receiver, err = currency.NewCurrency(req.ToUserID, currencyType, 0, 0)
+				if err != nil {
+					return fmt.Errorf("failed to create receiver currency entity: %w", err)
+				}
+				if err := s.currencyRepo.Create(ctx, receiver); err != nil {
+					return fmt.Errorf("failed to create receiver currency: %w", err)
+				}
+			}
+
+			senderBalanceBefore := sender.Balance()
+			receiverBalanceBefore := receiver.Balance()
+
+			// 失効処理前の期限切れロットの残量は譲渡できない
+			now := time.Now()
+			spendable, lots, err := s.spendableBalance(ctx, sender, now)
+			if err != nil {
+				return err
+			}
+			if spendable < req.Amount {
+				return currency.ErrInsufficientBalance
+			}
+			if err := sender.Consume(req.Amount); err != nil {
+				return err
+			}
+
+			// 送信者を保存（楽観的ロック）
+			// まだ残高を書き込んでいないため、競合時はリトライできる
+			if err := s.currencyRepo.Save(ctx, sender); err != nil {
+				if attempt < s.maxRetries-1 {
+					retryErr = err
+					continue
+				}
+				return fmt.Errorf("failed to save sender currency after retries: %w", err)
+			}
+
+			if err := receiver.Grant(req.Amount); err != nil {
+				return err
+			}
+
+			// 受信者を保存（送信者の消費を書き込み済みのため、競合時はリトライせずロールバックする）
+			if err := s.currencyRepo.Save(ctx, receiver); err != nil {
+				return fmt.Errorf("failed to save receiver currency: %w", err)
+			}
+
+			// 有効期限付きのロットから差し引いた分は、受信者にも同じ有効期限のロットとして付与する
+			consumptions, err := s.consumeLots(ctx, senderTransactionID, lots, req.Amount, now)
+			if err != nil {
+				return err
+			}
+			if err := s.grantLots(ctx, receiverTransactionID, req.ToUserID, currencyType, consumptions); err != nil {
+				return err
+			}
+
+			// トランザクション履歴を記録
+			senderTxn, err := transaction.NewTransaction(
+				senderTransactionID,
+				req.FromUserID,
+				transaction.TransactionTypeTransferOut,
+				currencyType,
+				req.Amount,
+				senderBalanceBefore,
+				sender.Balance(),
+				transaction.TransactionStatusCompleted,
+				senderMetadata,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create sender transaction entity: %w", err)
+			}
+			if err := s.transactionRepo.Save(ctx, senderTxn); err != nil {
+				return fmt.Errorf("failed to save sender transaction: %w", err)
+			}
+
+			receiverTxn, err := transaction.NewTransaction(
+				receiverTransactionID,
+				req.ToUserID,
+				transaction.TransactionTypeTransferIn,
+				currencyType,
+				req.Amount,
+				receiverBalanceBefore,
+				receiver.Balance(),
+				transaction.TransactionStatusCompleted,
+				receiverMetadata,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create receiver transaction entity: %w", err)
+			}
+			if err := s.transactionRepo.Save(ctx, receiverTxn); err != nil {
+				return fmt.Errorf("failed to save receiver transaction: %w", err)
+			}
+
+			// メトリクス記録
+			s.metrics.RecordTransaction(ctx, "transfer", currencyType.String())
+			s.metrics.RecordCurrencyBalance(ctx, req.FromUserID, currencyType.String(), sender.Balance())
+			s.metrics.RecordCurrencyBalance(ctx, req.ToUserID, currencyType.String(), receiver.Balance())
+
+			result = &TransferResponse{
+				TransferID:            transferID,
+				FromUserID:            req.FromUserID,
+				ToUserID:              req.ToUserID,
+				CurrencyType:          currencyType.String(),
+				Amount:                req.Amount,
+				SenderTransactionID:   senderTransactionID,
+				ReceiverTransactionID: receiverTransactionID,
+				BalanceAfter:          sender.Balance(),
+				Status:                "completed",
+			}
+
+			return nil
+		}
+
+		return retryErr
+	})
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to transfer currency", err, map[string]interface{}{
+			"from_user_id":  req.FromUserID,
+			"to_user_id":    req.ToUserID,
+			"currency_type": req.CurrencyType,
+			"amount":        req.Amount,
+		})
+		s.metrics.RecordError(ctx, "transfer_failed")
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Currency transferred successfully", map[string]interface{}{
+		"transfer_id":   transferID,
+		"from_user_id":  req.FromUserID,
+		"to_user_id":    req.ToUserID,
+		"balance_after": result.BalanceAfter,
+	})
+
+	return result, nil
+}
+
 // generateTransactionID トランザクションIDを生成
 func (s *CurrencyApplicationService) generateTransactionID() string {
-	return fmt.Sprintf("txn_%d", time.Now().UnixNano())
+	return "txn_" + s.idGenerator.NewID()
 }
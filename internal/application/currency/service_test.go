@@ -2,16 +2,20 @@ package currency
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/service"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
@@ -58,14 +62,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -74,20 +96,95 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
+// MockLotRepository モックロットリポジトリ
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	args := m.Called(ctx, transactionID, consumptions)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]currency.LotConsumption), args.Error(1)
+}
+
+// newEmptyLotRepository 有効期限付きロットが存在しない状態のモックロットリポジトリを作成
+func newEmptyLotRepository() *MockLotRepository {
+	m := new(MockLotRepository)
+	m.On("FindAvailableByUserIDAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*currency.Lot{}, nil).Maybe()
+	m.On("FindConsumptionsByTransactionID", mock.Anything, mock.Anything).Return([]currency.LotConsumption{}, nil).Maybe()
+	return m
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(ctx, fn)
 	// 実際のトランザクションは使わず、関数を直接実行
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
 
+// MockExpiryLock モック有効期限切れロット失効ジョブのロック
+type MockExpiryLock struct {
+	mock.Mock
+}
+
+func (m *MockExpiryLock) TryLock(ctx context.Context) (func(), bool, error) {
+	args := m.Called(ctx)
+	return func() {}, args.Bool(0), args.Error(1)
+}
+
 func TestCurrencyApplicationService_GetBalance(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -216,8 +313,12 @@ func TestCurrencyApplicationService_GetBalance(t *testing.T) {
 			svc := NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -263,7 +364,7 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 					return c.Balance() == 1500 && c.Version() == 2
 				})).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GrantResponse, err error) {
@@ -289,7 +390,7 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 					return c.Balance() == 500 && c.Version() == 1
 				})).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GrantResponse, err error) {
@@ -335,7 +436,7 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -349,7 +450,7 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
 				mcr.On("Create", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -365,7 +466,7 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 				// リトライをシミュレート（3回失敗）
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil).Times(3)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(assert.AnError).Times(3)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -381,7 +482,7 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -404,8 +505,12 @@ func TestCurrencyApplicationService_Grant(t *testing.T) {
 			svc := NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -452,7 +557,7 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 					return c.Balance() == 500 && c.Version() == 2
 				})).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ConsumeResponse, err error) {
@@ -472,7 +577,7 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
 				existingCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(currency.ErrInsufficientBalance)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(currency.ErrInsufficientBalance)
 			},
 			wantError: true,
 			checkFunc: func(t *testing.T, resp *ConsumeResponse, err error) {
@@ -489,7 +594,7 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, currency.ErrCurrencyNotFound)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(currency.ErrCurrencyNotFound)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(currency.ErrCurrencyNotFound)
 			},
 			wantError: true,
 		},
@@ -502,7 +607,7 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 			},
 			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -518,7 +623,7 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 				// リトライをシミュレート（3回失敗）
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil).Times(3)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(assert.AnError).Times(3)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -534,7 +639,7 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -569,8 +674,12 @@ func TestCurrencyApplicationService_Consume(t *testing.T) {
 			svc := NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -620,7 +729,7 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 					return c.Balance() == 500 && c.Version() == 2
 				})).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ConsumeResponse, err error) {
@@ -659,7 +768,7 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 					return c.CurrencyType() == currency.CurrencyTypePaid && c.Balance() == 500 && c.Version() == 2
 				})).Return(nil).Once()
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Twice()
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ConsumeResponse, err error) {
@@ -714,7 +823,7 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 					return c.Balance() == 500 && c.Version() == 2
 				})).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *ConsumeResponse, err error) {
@@ -774,7 +883,7 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil).Once()
 				// ConsumeWithPriority内でのFindByUserIDAndType用のモック
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(nil, assert.AnError).Once()
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -797,7 +906,7 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 				})).Return(nil).Once()
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Once()
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, assert.AnError).Once()
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -816,7 +925,7 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(freeCurrency, nil).Once()
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(assert.AnError)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(assert.AnError)
 			},
 			wantError: true,
 		},
@@ -839,8 +948,12 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 			svc := NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -865,6 +978,194 @@ func TestCurrencyApplicationService_ConsumeWithPriority(t *testing.T) {
 	}
 }
 
+func TestCurrencyApplicationService_Refund(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *RefundRequest
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		wantErr    error
+		checkFunc  func(*testing.T, *RefundResponse)
+	}{
+		{
+			name: "正常系: 単一通貨タイプの消費を返金",
+			req: &RefundRequest{
+				TransactionID: "txn_123",
+				Reason:        "誤購入",
+				Requester:     "support-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				original := transaction.MustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 300, 1000, 700, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(nil, transaction.ErrTransactionNotFound)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 700, 1), nil)
+				mcr.On("Save", mock.Anything, mock.MatchedBy(func(c *currency.Currency) bool {
+					return c.Balance() == 1000
+				})).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.TransactionID() == "refund_txn_123" &&
+						txn.TransactionType() == transaction.TransactionTypeRefund &&
+						txn.Amount() == 300 &&
+						txn.Metadata()["original_transaction_id"] == "txn_123" &&
+						txn.Metadata()["reason"] == "誤購入" &&
+						*txn.Requester() == "support-tool"
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *RefundResponse) {
+				assert.Equal(t, "refund_txn_123", resp.TransactionID)
+				assert.Equal(t, "txn_123", resp.OriginalTransactionID)
+				assert.Equal(t, "user123", resp.UserID)
+				assert.Equal(t, int64(300), resp.TotalRefunded)
+				require.Len(t, resp.RefundDetails, 1)
+				assert.Equal(t, RefundDetail{
+					TransactionID:         "refund_txn_123",
+					OriginalTransactionID: "txn_123",
+					CurrencyType:          "paid",
+					Amount:                300,
+					BalanceBefore:         700,
+					BalanceAfter:          1000,
+				}, resp.RefundDetails[0])
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name: "正常系: 優先順位制御の消費をベースIDで返金",
+			req: &RefundRequest{
+				TransactionID: "txn_456",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				freePart := transaction.MustNewTransaction("txn_456_free", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypeFree, 200, 200, 0, transaction.TransactionStatusCompleted, nil)
+				paidPart := transaction.MustNewTransaction("txn_456_paid", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 100, 500, 400, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_456").Return(nil, transaction.ErrTransactionNotFound)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_456_free").Return(freePart, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_456_paid").Return(paidPart, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_456_free").Return(nil, transaction.ErrTransactionNotFound)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_456_paid").Return(nil, transaction.ErrTransactionNotFound)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 0, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 400, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Times(2)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *RefundResponse) {
+				assert.Equal(t, "refund_txn_456", resp.TransactionID)
+				assert.Equal(t, int64(300), resp.TotalRefunded)
+				require.Len(t, resp.RefundDetails, 2)
+				assert.Equal(t, "refund_txn_456_free", resp.RefundDetails[0].TransactionID)
+				assert.Equal(t, int64(200), resp.RefundDetails[0].BalanceAfter)
+				assert.Equal(t, "refund_txn_456_paid", resp.RefundDetails[1].TransactionID)
+				assert.Equal(t, int64(500), resp.RefundDetails[1].BalanceAfter)
+			},
+		},
+		{
+			name: "異常系: 返金済みのトランザクション",
+			req: &RefundRequest{
+				TransactionID: "txn_123",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				original := transaction.MustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 300, 1000, 700, transaction.TransactionStatusCompleted, nil)
+				refund := transaction.MustNewTransaction("refund_txn_123", "user123", transaction.TransactionTypeRefund, currency.CurrencyTypePaid, 300, 700, 1000, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(refund, nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: transaction.ErrTransactionAlreadyRefunded,
+		},
+		{
+			name: "異常系: 一部のみ返金済みの分割消費",
+			req: &RefundRequest{
+				TransactionID: "txn_456",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				freePart := transaction.MustNewTransaction("txn_456_free", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypeFree, 200, 200, 0, transaction.TransactionStatusCompleted, nil)
+				paidPart := transaction.MustNewTransaction("txn_456_paid", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 100, 500, 400, transaction.TransactionStatusCompleted, nil)
+				refund := transaction.MustNewTransaction("refund_txn_456_free", "user123", transaction.TransactionTypeRefund, currency.CurrencyTypeFree, 200, 0, 200, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_456").Return(nil, transaction.ErrTransactionNotFound)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_456_free").Return(freePart, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_456_paid").Return(paidPart, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_456_free").Return(refund, nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: transaction.ErrTransactionAlreadyRefunded,
+		},
+		{
+			name: "異常系: 消費以外のトランザクション",
+			req: &RefundRequest{
+				TransactionID: "txn_789",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				grant := transaction.MustNewTransaction("txn_789", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 100, 0, 100, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_789").Return(grant, nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: transaction.ErrTransactionNotRefundable,
+		},
+		{
+			name: "異常系: トランザクションが見つからない",
+			req: &RefundRequest{
+				TransactionID: "txn_none",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByTransactionID", mock.Anything, mock.AnythingOfType("string")).Return(nil, transaction.ErrTransactionNotFound)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: transaction.ErrTransactionNotFound,
+		},
+		{
+			name: "異常系: トランザクションIDが空",
+			req: &RefundRequest{
+				TransactionID: "",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				// モックは呼ばれない
+			},
+			wantErr: transaction.ErrInvalidTransactionID,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+			currencyService := service.NewCurrencyService(mockCurrencyRepo)
+
+			svc := NewCurrencyApplicationService(
+				mockCurrencyRepo,
+				mockTransactionRepo,
+				newEmptyLotRepository(),
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				currencyService,
+				nil,
+				logger,
+				metrics,
+			)
+
+			got, err := svc.Refund(context.Background(), tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.checkFunc(t, got)
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func mustNewCurrency(userID string, currencyType currency.CurrencyType, balance int64, version int) *currency.Currency {
 	c, err := currency.NewCurrency(userID, currencyType, balance, version)
 	if err != nil {
@@ -872,3 +1173,1128 @@ func mustNewCurrency(userID string, currencyType currency.CurrencyType, balance
 	}
 	return c
 }
+
+// newCurrencyAppServiceWithLotRepo ロットリポジトリのモックを指定してサービスを作成
+func newCurrencyAppServiceWithLotRepo(t *testing.T, mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) *CurrencyApplicationService {
+	tracer := otel.Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	metrics, err := otelinfra.NewMetrics("test")
+	require.NoError(t, err)
+
+	return NewCurrencyApplicationService(
+		mcr,
+		mtr,
+		mlr,
+		mtm,
+		idgen.NewUUIDv7Generator(),
+		currency.NewTransferPolicy(currency.CurrencyTypeFree),
+		service.NewCurrencyService(mcr),
+		nil,
+		logger,
+		metrics,
+	)
+}
+
+func TestCurrencyApplicationService_GrantWithExpiry(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		req        *GrantRequest
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockLotRepository, *MockTransactionManager)
+		wantErr    error
+	}{
+		{
+			name: "正常系: 有効期限付きの無償通貨を付与するとロットが作成される",
+			req: &GrantRequest{
+				UserID:       "user123",
+				CurrencyType: "free",
+				Amount:       100,
+				ExpiresAt:    &future,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 50, 1), nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.Metadata()["expires_at"] == future.UTC().Format(time.RFC3339)
+				})).Return(nil)
+				mlr.On("Create", mock.Anything, mock.MatchedBy(func(lot *currency.Lot) bool {
+					return lot.UserID() == "user123" &&
+						lot.Amount() == 100 &&
+						lot.Remaining() == 100 &&
+						lot.ExpiresAt().Equal(future)
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+		},
+		{
+			name: "異常系: 有償通貨には有効期限を設定できない",
+			req: &GrantRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       100,
+				ExpiresAt:    &future,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				// モックは呼ばれない
+			},
+			wantErr: currency.ErrInvalidExpiry,
+		},
+		{
+			name: "異常系: 過去の有効期限",
+			req: &GrantRequest{
+				UserID:       "user123",
+				CurrencyType: "free",
+				Amount:       100,
+				ExpiresAt:    &past,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				// モックは呼ばれない
+			},
+			wantErr: currency.ErrInvalidExpiry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockLotRepo := new(MockLotRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			resp, err := svc.Grant(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(150), resp.BalanceAfter)
+			mockLotRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyApplicationService_GetBalance_Expirations(t *testing.T) {
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockLotRepo := new(MockLotRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	now := time.Now()
+	expiredLot, err := currency.NewLot("txn_1", "user123", currency.CurrencyTypeFree, 100, 30, now.Add(-time.Hour))
+	require.NoError(t, err)
+	activeLot, err := currency.NewLot("txn_2", "user123", currency.CurrencyTypeFree, 100, 80, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, currency.ErrCurrencyNotFound)
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 200, 1), nil)
+	mockLotRepo.On("FindAvailableByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return([]*currency.Lot{expiredLot, activeLot}, nil)
+
+	svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+	resp, err := svc.GetBalance(context.Background(), &GetBalanceRequest{UserID: "user123"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), resp.Balances["free"])
+	// 失効処理前のロットは失効予定に含めない
+	require.Len(t, resp.Expirations, 1)
+	assert.Equal(t, Expiration{
+		CurrencyType: "free",
+		Amount:       80,
+		ExpiresAt:    activeLot.ExpiresAt(),
+	}, resp.Expirations[0])
+}
+
+func TestCurrencyApplicationService_Consume_AllocatesLots(t *testing.T) {
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockLotRepo := new(MockLotRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	now := time.Now()
+	lot1, err := currency.NewLot("txn_1", "user123", currency.CurrencyTypeFree, 100, 50, now.Add(time.Hour))
+	require.NoError(t, err)
+	lot2, err := currency.NewLot("txn_2", "user123", currency.CurrencyTypeFree, 100, 100, now.Add(2*time.Hour))
+	require.NoError(t, err)
+
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 300, 1), nil)
+	mockCurrencyRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockTransactionRepo.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+	mockLotRepo.On("FindAvailableByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return([]*currency.Lot{lot1, lot2}, nil)
+	mockLotRepo.On("Save", mock.Anything, mock.MatchedBy(func(lot *currency.Lot) bool {
+		return lot.LotID() == "txn_1" && lot.Remaining() == 0
+	})).Return(nil).Once()
+	mockLotRepo.On("Save", mock.Anything, mock.MatchedBy(func(lot *currency.Lot) bool {
+		return lot.LotID() == "txn_2" && lot.Remaining() == 80
+	})).Return(nil).Once()
+	mockLotRepo.On("SaveConsumptions", mock.Anything, mock.AnythingOfType("string"), []currency.LotConsumption{
+		{LotID: "txn_1", Amount: 50, ExpiresAt: lot1.ExpiresAt()},
+		{LotID: "txn_2", Amount: 20, ExpiresAt: lot2.ExpiresAt()},
+	}).Return(nil).Once()
+	mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+
+	svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+	resp, err := svc.Consume(context.Background(), &ConsumeRequest{
+		UserID:       "user123",
+		CurrencyType: "free",
+		Amount:       70,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(230), resp.BalanceAfter)
+	mockLotRepo.AssertExpectations(t)
+}
+
+func TestCurrencyApplicationService_Refund_RestoresLots(t *testing.T) {
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockLotRepo := new(MockLotRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	expiresAt1 := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiresAt2 := time.Date(2030, 2, 1, 0, 0, 0, 0, time.UTC)
+	original := transaction.MustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypeFree, 100, 300, 200, transaction.TransactionStatusCompleted, nil)
+
+	mockTransactionRepo.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+	mockTransactionRepo.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(nil, transaction.ErrTransactionNotFound)
+	mockTransactionRepo.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 200, 1), nil)
+	mockCurrencyRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	// 100のうち70は有効期限付きのロットから消費していた
+	mockLotRepo.On("FindConsumptionsByTransactionID", mock.Anything, "txn_123").Return([]currency.LotConsumption{
+		{LotID: "txn_1", Amount: 50, ExpiresAt: expiresAt1},
+		{LotID: "txn_2", Amount: 20, ExpiresAt: expiresAt2},
+	}, nil)
+	mockLotRepo.On("Create", mock.Anything, mock.MatchedBy(func(lot *currency.Lot) bool {
+		return lot.LotID() == "refund_txn_123_1" && lot.UserID() == "user123" &&
+			lot.Amount() == 50 && lot.Remaining() == 50 && lot.ExpiresAt().Equal(expiresAt1)
+	})).Return(nil).Once()
+	mockLotRepo.On("Create", mock.Anything, mock.MatchedBy(func(lot *currency.Lot) bool {
+		return lot.LotID() == "refund_txn_123_2" && lot.UserID() == "user123" &&
+			lot.Amount() == 20 && lot.Remaining() == 20 && lot.ExpiresAt().Equal(expiresAt2)
+	})).Return(nil).Once()
+	mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+
+	svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+	resp, err := svc.Refund(context.Background(), &RefundRequest{TransactionID: "txn_123"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), resp.TotalRefunded)
+	assert.Equal(t, int64(300), resp.RefundDetails[0].BalanceAfter)
+	mockLotRepo.AssertExpectations(t)
+}
+
+func TestCurrencyApplicationService_Consume_ExcludesExpiredLots(t *testing.T) {
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockLotRepo := new(MockLotRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	now := time.Now()
+	expiredLot, err := currency.NewLot("txn_1", "user123", currency.CurrencyTypeFree, 100, 60, now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 100, 1), nil)
+	mockLotRepo.On("FindAvailableByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return([]*currency.Lot{expiredLot}, nil)
+	mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+
+	svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+	// 残高100のうち60は失効処理前の期限切れロットのため、消費できるのは40まで
+	_, err = svc.Consume(context.Background(), &ConsumeRequest{
+		UserID:       "user123",
+		CurrencyType: "free",
+		Amount:       50,
+	})
+	assert.ErrorIs(t, err, currency.ErrInsufficientBalance)
+	mockCurrencyRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	mockLotRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	assert.Equal(t, int64(60), expiredLot.Remaining())
+}
+
+func TestCurrencyApplicationService_ExpireLots(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		limit      int
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockLotRepository, *MockTransactionManager)
+		wantErr    bool
+		want       *ExpireLotsResponse
+	}{
+		{
+			name:  "正常系: 失効したロットの残量を残高から差し引きexpireトランザクションを記録",
+			limit: 10,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				lot, _ := currency.NewLot("txn_1", "user123", currency.CurrencyTypeFree, 100, 40, expiresAt)
+				mlr.On("FindExpired", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*currency.Lot{lot}, nil).Once()
+				mlr.On("FindExpired", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*currency.Lot{}, nil).Once()
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 300, 1), nil)
+				mcr.On("Save", mock.Anything, mock.MatchedBy(func(c *currency.Currency) bool {
+					return c.Balance() == 260
+				})).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.TransactionID() == "expire_txn_1" &&
+						txn.TransactionType() == transaction.TransactionTypeExpire &&
+						txn.Amount() == 40 &&
+						txn.BalanceBefore() == 300 &&
+						txn.BalanceAfter() == 260 &&
+						txn.Metadata()["lot_id"] == "txn_1"
+				})).Return(nil)
+				mlr.On("Save", mock.Anything, mock.MatchedBy(func(l *currency.Lot) bool {
+					return l.LotID() == "txn_1" && l.Remaining() == 0
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			want: &ExpireLotsResponse{ExpiredLots: 1, ExpiredAmount: 40},
+		},
+		{
+			name:  "正常系: 残高が不足している場合は残高の範囲で失効",
+			limit: 1,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				lot, _ := currency.NewLot("txn_1", "user123", currency.CurrencyTypeFree, 100, 40, expiresAt)
+				mlr.On("FindExpired", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*currency.Lot{lot}, nil).Once()
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 10, 1), nil)
+				mcr.On("Save", mock.Anything, mock.MatchedBy(func(c *currency.Currency) bool {
+					return c.Balance() == 0
+				})).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.Amount() == 10
+				})).Return(nil)
+				mlr.On("Save", mock.Anything, mock.Anything).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			want: &ExpireLotsResponse{ExpiredLots: 1, ExpiredAmount: 10},
+		},
+		{
+			name:  "正常系: 失効対象のロットがない",
+			limit: 10,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mlr.On("FindExpired", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*currency.Lot{}, nil).Once()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			want: &ExpireLotsResponse{},
+		},
+		{
+			name:  "異常系: ロット取得でエラー",
+			limit: 10,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mlr.On("FindExpired", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return(nil, assert.AnError)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: true,
+			want:    &ExpireLotsResponse{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockLotRepo := new(MockLotRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			resp, err := svc.ExpireLots(context.Background(), &ExpireLotsRequest{Limit: tt.limit})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, resp)
+			mockLotRepo.AssertExpectations(t)
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyApplicationService_ExpireLots_Lock(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(*MockLotRepository, *MockTransactionManager, *MockExpiryLock)
+		wantErr    error
+		want       *ExpireLotsResponse
+	}{
+		{
+			name: "正常系: ロックを取得して失効させる",
+			setupMocks: func(mlr *MockLotRepository, mtm *MockTransactionManager, mel *MockExpiryLock) {
+				mel.On("TryLock", mock.Anything).Return(true, nil).Once()
+				mlr.On("FindExpired", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*currency.Lot{}, nil).Once()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			want: &ExpireLotsResponse{},
+		},
+		{
+			name: "異常系: 他のインスタンスで実行中",
+			setupMocks: func(mlr *MockLotRepository, mtm *MockTransactionManager, mel *MockExpiryLock) {
+				mel.On("TryLock", mock.Anything).Return(false, nil).Once()
+			},
+			wantErr: currency.ErrExpirySweepInProgress,
+		},
+		{
+			name: "異常系: ロックの取得でエラー",
+			setupMocks: func(mlr *MockLotRepository, mtm *MockTransactionManager, mel *MockExpiryLock) {
+				mel.On("TryLock", mock.Anything).Return(false, assert.AnError).Once()
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockLotRepo := new(MockLotRepository)
+			mockTxManager := new(MockTransactionManager)
+			mockExpiryLock := new(MockExpiryLock)
+
+			tt.setupMocks(mockLotRepo, mockTxManager, mockExpiryLock)
+
+			logger := otelinfra.NewLogger(otel.Tracer("test"))
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+			svc := NewCurrencyApplicationService(
+				mockCurrencyRepo,
+				new(MockTransactionRepository),
+				mockLotRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				service.NewCurrencyService(mockCurrencyRepo),
+				mockExpiryLock,
+				logger,
+				metrics,
+			)
+
+			resp, err := svc.ExpireLots(context.Background(), &ExpireLotsRequest{Limit: 10})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, resp)
+			}
+			mockExpiryLock.AssertExpectations(t)
+			mockLotRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyApplicationService_Compensate(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *CompensateRequest
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockLotRepository, *MockTransactionManager)
+		wantErr    error
+		checkFunc  func(*testing.T, *CompensateResponse)
+	}{
+		{
+			name: "正常系: 正の金額で補填",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "free",
+				Amount:       500,
+				Reason:       "障害補填",
+				Requester:    "ops-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("Save", mock.Anything, mock.MatchedBy(func(c *currency.Currency) bool {
+					return c.Balance() == 600
+				})).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.TransactionType() == transaction.TransactionTypeCompensate &&
+						txn.Amount() == 500 &&
+						txn.Metadata()["reason"] == "障害補填" &&
+						txn.Metadata()["direction"] == "credit" &&
+						*txn.Requester() == "ops-tool"
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *CompensateResponse) {
+				assert.Equal(t, int64(500), resp.Amount)
+				assert.Equal(t, int64(100), resp.BalanceBefore)
+				assert.Equal(t, int64(600), resp.BalanceAfter)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name: "正常系: 負の金額で回収しマイナス残高を許可",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       -300,
+				Reason:       "不正付与の回収",
+				Requester:    "ops-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 100, 1), nil)
+				mcr.On("Save", mock.Anything, mock.MatchedBy(func(c *currency.Currency) bool {
+					return c.Balance() == -200
+				})).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.TransactionType() == transaction.TransactionTypeCompensate &&
+						txn.Amount() == 300 &&
+						txn.BalanceBefore() == 100 &&
+						txn.BalanceAfter() == -200 &&
+						txn.Metadata()["direction"] == "debit"
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *CompensateResponse) {
+				assert.Equal(t, int64(-300), resp.Amount)
+				assert.Equal(t, int64(-200), resp.BalanceAfter)
+			},
+		},
+		{
+			name: "正常系: 無償通貨の回収は有効期限付きロットからも差し引く",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "free",
+				Amount:       -50,
+				Reason:       "不正付与の回収",
+				Requester:    "ops-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				lot, _ := currency.NewLot("txn_1", "user123", currency.CurrencyTypeFree, 100, 100, time.Now().Add(time.Hour))
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil)
+				mlr.On("FindAvailableByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return([]*currency.Lot{lot}, nil)
+				mlr.On("Save", mock.Anything, mock.MatchedBy(func(l *currency.Lot) bool {
+					return l.Remaining() == 50
+				})).Return(nil)
+				mlr.On("SaveConsumptions", mock.Anything, mock.AnythingOfType("string"), []currency.LotConsumption{
+					{LotID: "txn_1", Amount: 50, ExpiresAt: lot.ExpiresAt()},
+				}).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *CompensateResponse) {
+				assert.Equal(t, int64(50), resp.BalanceAfter)
+			},
+		},
+		{
+			name: "正常系: 通貨が存在しない場合は作成して回収",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       -100,
+				Reason:       "不正付与の回収",
+				Requester:    "ops-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(nil, currency.ErrCurrencyNotFound)
+				mcr.On("Create", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkFunc: func(t *testing.T, resp *CompensateResponse) {
+				assert.Equal(t, int64(0), resp.BalanceBefore)
+				assert.Equal(t, int64(-100), resp.BalanceAfter)
+			},
+		},
+		{
+			name: "異常系: 金額が0",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       0,
+				Reason:       "補填",
+				Requester:    "ops-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				// モックは呼ばれない
+			},
+			wantErr: currency.ErrInvalidAmount,
+		},
+		{
+			name: "異常系: 理由が未指定",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       100,
+				Requester:    "ops-tool",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				// モックは呼ばれない
+			},
+			wantErr: transaction.ErrReasonRequired,
+		},
+		{
+			name: "異常系: リクエスト元が未指定",
+			req: &CompensateRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       100,
+				Reason:       "補填",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				// モックは呼ばれない
+			},
+			wantErr: transaction.ErrRequesterRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockLotRepo := new(MockLotRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			resp, err := svc.Compensate(context.Background(), tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.checkFunc(t, resp)
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockLotRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyApplicationService_Compensate_RecordsNegativeBalance(t *testing.T) {
+	// マイナス残高のカウンタを検証するため、手動リーダー付きのMeterProviderに差し替える
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	defer otel.SetMeterProvider(previous)
+
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 100, 1), nil)
+	mockCurrencyRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockTransactionRepo.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+	mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+
+	svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, newEmptyLotRepository(), mockTxManager)
+
+	_, err := svc.Compensate(context.Background(), &CompensateRequest{
+		UserID:       "user123",
+		CurrencyType: "paid",
+		Amount:       -300,
+		Reason:       "不正付与の回収",
+		Requester:    "ops-tool",
+	})
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	var negativeCount int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "negative_balance_total" {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			for _, dp := range sum.DataPoints {
+				negativeCount += dp.Value
+			}
+		}
+	}
+	assert.Equal(t, int64(1), negativeCount)
+}
+
+// mustRecordedTransaction 冪等性キー付きで記録済みのトランザクションを作成
+func mustRecordedTransaction(t *testing.T, transactionID string, transactionType transaction.TransactionType, currencyType currency.CurrencyType, amount, balanceBefore, balanceAfter int64, idempotencyKey, requestHash string) *transaction.Transaction {
+	txn, err := transaction.NewTransaction(
+		transactionID,
+		"user123",
+		transactionType,
+		currencyType,
+		amount,
+		balanceBefore,
+		balanceAfter,
+		transaction.TransactionStatusCompleted,
+		nil,
+	)
+	require.NoError(t, err)
+	txn.SetIdempotencyKey(idempotencyKey, requestHash)
+	return txn
+}
+
+func TestCurrencyApplicationService_Grant_IdempotencyKey(t *testing.T) {
+	baseReq := func() *GrantRequest {
+		return &GrantRequest{
+			UserID:         "user123",
+			CurrencyType:   "paid",
+			Amount:         100,
+			Reason:         "campaign",
+			IdempotencyKey: "grant-key-1",
+		}
+	}
+	recordedHash, err := grantRequestHash(baseReq())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		request    func() *GrantRequest
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		wantResp   *GrantResponse
+		wantErr    error
+	}{
+		{
+			name:    "正常系: 未使用のキーは付与してキーを記録する",
+			request: baseReq,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{}, nil)
+				mtr.On("ReserveIdempotencyKey", mock.Anything, "user123", "grant-key-1", recordedHash).Return(nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1), nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.IdempotencyKey() != nil && *txn.IdempotencyKey() == "grant-key-1" &&
+						txn.RequestHash() != nil && *txn.RequestHash() == recordedHash
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantResp: &GrantResponse{BalanceAfter: 600, Status: "completed"},
+		},
+		{
+			name:    "正常系: 使用済みのキーは記録済みのレスポンスを返す",
+			request: baseReq,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{
+					mustRecordedTransaction(t, "txn_recorded", transaction.TransactionTypeGrant, currency.CurrencyTypePaid, 100, 500, 600, "grant-key-1", recordedHash),
+				}, nil)
+			},
+			wantResp: &GrantResponse{TransactionID: "txn_recorded", BalanceAfter: 600, Status: "completed"},
+		},
+		{
+			name:    "正常系: 同じキーの同時リクエストが先に記録した場合はその結果を返す",
+			request: baseReq,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{}, nil).Once()
+				mtr.On("ReserveIdempotencyKey", mock.Anything, "user123", "grant-key-1", recordedHash).Return(transaction.ErrDuplicateIdempotencyKey)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				// ロールバック後に先行したリクエストの記録を読み直す
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{
+					mustRecordedTransaction(t, "txn_concurrent", transaction.TransactionTypeGrant, currency.CurrencyTypePaid, 100, 500, 600, "grant-key-1", recordedHash),
+				}, nil).Once()
+			},
+			wantResp: &GrantResponse{TransactionID: "txn_concurrent", BalanceAfter: 600, Status: "completed"},
+		},
+		{
+			name:    "異常系: 同じキーの同時リクエストが異なる内容で先に記録した",
+			request: baseReq,
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{}, nil).Once()
+				mtr.On("ReserveIdempotencyKey", mock.Anything, "user123", "grant-key-1", recordedHash).Return(transaction.ErrDuplicateIdempotencyKey)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{
+					mustRecordedTransaction(t, "txn_concurrent", transaction.TransactionTypeGrant, currency.CurrencyTypePaid, 200, 500, 700, "grant-key-1", "other-hash"),
+				}, nil).Once()
+			},
+			wantErr: transaction.ErrIdempotencyKeyConflict,
+		},
+		{
+			name: "異常系: 異なるリクエスト内容でのキー再利用",
+			request: func() *GrantRequest {
+				req := baseReq()
+				req.Amount = 200
+				return req
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{
+					mustRecordedTransaction(t, "txn_recorded", transaction.TransactionTypeGrant, currency.CurrencyTypePaid, 100, 500, 600, "grant-key-1", recordedHash),
+				}, nil)
+			},
+			wantErr: transaction.ErrIdempotencyKeyConflict,
+		},
+		{
+			name: "異常系: キーが長すぎる",
+			request: func() *GrantRequest {
+				req := baseReq()
+				req.IdempotencyKey = strings.Repeat("k", maxIdempotencyKeyLength+1)
+				return req
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			wantErr:    transaction.ErrInvalidIdempotencyKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, newEmptyLotRepository(), mockTxManager)
+
+			resp, err := svc.Grant(context.Background(), tt.request())
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				if tt.wantResp.TransactionID != "" {
+					assert.Equal(t, tt.wantResp.TransactionID, resp.TransactionID)
+				}
+				assert.Equal(t, tt.wantResp.BalanceAfter, resp.BalanceAfter)
+				assert.Equal(t, tt.wantResp.Status, resp.Status)
+			}
+
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockTxManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyApplicationService_ConsumeWithPriority_IdempotencyKey(t *testing.T) {
+	req := &ConsumeRequest{
+		UserID:         "user123",
+		CurrencyType:   "auto",
+		Amount:         150,
+		ItemID:         "item_001",
+		UsePriority:    true,
+		IdempotencyKey: "consume-key-1",
+	}
+	recordedHash, err := consumeRequestHash(req)
+	require.NoError(t, err)
+
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	// 記録済みの消費は残高チェックを行わずに返すため、通貨リポジトリは呼ばれない
+	mockTransactionRepo.On("FindByIdempotencyKey", mock.Anything, "user123", "consume-key-1").Return([]*transaction.Transaction{
+		mustRecordedTransaction(t, "txn_base_free", transaction.TransactionTypeConsume, currency.CurrencyTypeFree, 100, 100, 0, "consume-key-1", recordedHash),
+		mustRecordedTransaction(t, "txn_base_paid", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 50, 200, 150, "consume-key-1", recordedHash),
+	}, nil)
+
+	svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, newEmptyLotRepository(), mockTxManager)
+
+	resp, err := svc.Consume(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "txn_base", resp.TransactionID)
+	assert.Equal(t, int64(150), resp.TotalConsumed)
+	require.Len(t, resp.ConsumptionDetails, 2)
+	assert.Equal(t, "free", resp.ConsumptionDetails[0].CurrencyType)
+	assert.Equal(t, int64(100), resp.ConsumptionDetails[0].Amount)
+	assert.Equal(t, "paid", resp.ConsumptionDetails[1].CurrencyType)
+	assert.Equal(t, int64(150), resp.ConsumptionDetails[1].BalanceAfter)
+
+	mockCurrencyRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockTxManager.AssertExpectations(t)
+}
+
+func TestCurrencyApplicationService_Grant_UsesIDGenerator(t *testing.T) {
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockTxManager := new(MockTransactionManager)
+
+	mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 0, 1), nil)
+	mockCurrencyRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockTransactionRepo.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+		return txn.TransactionID() == "txn_0192b6f0-7c1e-7000-8000-000000000001"
+	})).Return(nil)
+	mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+
+	tracer := otel.Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	metrics, err := otelinfra.NewMetrics("test")
+	require.NoError(t, err)
+
+	svc := NewCurrencyApplicationService(
+		mockCurrencyRepo,
+		mockTransactionRepo,
+		newEmptyLotRepository(),
+		mockTxManager,
+		idgen.GeneratorFunc(func() string { return "0192b6f0-7c1e-7000-8000-000000000001" }),
+		nil,
+		service.NewCurrencyService(mockCurrencyRepo),
+		nil,
+		logger,
+		metrics,
+	)
+
+	resp, err := svc.Grant(context.Background(), &GrantRequest{
+		UserID:       "user123",
+		CurrencyType: "paid",
+		Amount:       100,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "txn_0192b6f0-7c1e-7000-8000-000000000001", resp.TransactionID)
+	mockTransactionRepo.AssertExpectations(t)
+}
+
+func TestCurrencyApplicationService_Transfer(t *testing.T) {
+	tests := []struct {
+		name       string
+		request    *TransferRequest
+		setupMocks func(*MockCurrencyRepository, *MockTransactionRepository, *MockLotRepository, *MockTransactionManager)
+		wantErr    error
+		checkResp  func(*testing.T, *TransferResponse)
+	}{
+		{
+			name: "正常系: 無償通貨を譲渡",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "receiver",
+				CurrencyType: "free",
+				Amount:       30,
+				Message:      "ありがとう",
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return(mustNewCurrency("sender", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "receiver", currency.CurrencyTypeFree).Return(mustNewCurrency("receiver", currency.CurrencyTypeFree, 10, 1), nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+				mlr.On("FindAvailableByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return([]*currency.Lot{}, nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.TransactionType() == transaction.TransactionTypeTransferOut &&
+						txn.UserID() == "sender" &&
+						txn.BalanceBefore() == 100 && txn.BalanceAfter() == 70 &&
+						txn.Metadata()["to_user_id"] == "receiver"
+				})).Return(nil).Once()
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(txn *transaction.Transaction) bool {
+					return txn.TransactionType() == transaction.TransactionTypeTransferIn &&
+						txn.UserID() == "receiver" &&
+						txn.BalanceBefore() == 10 && txn.BalanceAfter() == 40 &&
+						txn.Metadata()["from_user_id"] == "sender"
+				})).Return(nil).Once()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkResp: func(t *testing.T, resp *TransferResponse) {
+				assert.True(t, strings.HasPrefix(resp.TransferID, "trf_"))
+				assert.Equal(t, resp.TransferID+"_out", resp.SenderTransactionID)
+				assert.Equal(t, resp.TransferID+"_in", resp.ReceiverTransactionID)
+				assert.Equal(t, int64(70), resp.BalanceAfter)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name: "正常系: 受信者が譲渡する通貨タイプの残高を持たない場合は作成",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "newcomer",
+				CurrencyType: "free",
+				Amount:       50,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return(mustNewCurrency("sender", currency.CurrencyTypeFree, 50, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "newcomer", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
+				mcr.On("FindByUserIDAndType", mock.Anything, "newcomer", currency.CurrencyTypePaid).Return(mustNewCurrency("newcomer", currency.CurrencyTypePaid, 0, 1), nil)
+				mcr.On("Create", mock.Anything, mock.Anything).Return(nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+				mlr.On("FindAvailableByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return([]*currency.Lot{}, nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Twice()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkResp: func(t *testing.T, resp *TransferResponse) {
+				assert.Equal(t, int64(0), resp.BalanceAfter)
+			},
+		},
+		{
+			name: "異常系: 残高不足",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "receiver",
+				CurrencyType: "free",
+				Amount:       200,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return(mustNewCurrency("sender", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "receiver", currency.CurrencyTypeFree).Return(mustNewCurrency("receiver", currency.CurrencyTypeFree, 10, 1), nil)
+				mlr.On("FindAvailableByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return([]*currency.Lot{}, nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: currency.ErrInsufficientBalance,
+		},
+		{
+			name: "正常系: 有効期限付きのロットから譲渡した分は受信者にも同じ有効期限で付与",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "receiver",
+				CurrencyType: "free",
+				Amount:       30,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+				lot, _ := currency.NewLot("txn_1", "sender", currency.CurrencyTypeFree, 100, 20, expiresAt)
+				mcr.On("FindByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return(mustNewCurrency("sender", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "receiver", currency.CurrencyTypeFree).Return(mustNewCurrency("receiver", currency.CurrencyTypeFree, 0, 1), nil)
+				mcr.On("Save", mock.Anything, mock.Anything).Return(nil).Twice()
+				mlr.On("FindAvailableByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return([]*currency.Lot{lot}, nil)
+				mlr.On("Save", mock.Anything, mock.MatchedBy(func(l *currency.Lot) bool {
+					return l.LotID() == "txn_1" && l.Remaining() == 0
+				})).Return(nil).Once()
+				mlr.On("SaveConsumptions", mock.Anything, mock.MatchedBy(func(id string) bool {
+					return strings.HasSuffix(id, "_out")
+				}), []currency.LotConsumption{{LotID: "txn_1", Amount: 20, ExpiresAt: expiresAt}}).Return(nil).Once()
+				mlr.On("Create", mock.Anything, mock.MatchedBy(func(l *currency.Lot) bool {
+					return strings.HasSuffix(l.LotID(), "_in_1") && l.UserID() == "receiver" &&
+						l.Amount() == 20 && l.Remaining() == 20 && l.ExpiresAt().Equal(expiresAt)
+				})).Return(nil).Once()
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Twice()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			checkResp: func(t *testing.T, resp *TransferResponse) {
+				assert.Equal(t, int64(70), resp.BalanceAfter)
+			},
+		},
+		{
+			name: "異常系: 受信者が存在しない",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "nobody",
+				CurrencyType: "free",
+				Amount:       10,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return(mustNewCurrency("sender", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "nobody", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
+				mcr.On("FindByUserIDAndType", mock.Anything, "nobody", currency.CurrencyTypePaid).Return(nil, currency.ErrCurrencyNotFound)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: currency.ErrRecipientNotFound,
+		},
+		{
+			name: "異常系: 送信者の通貨が存在しない",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "receiver",
+				CurrencyType: "free",
+				Amount:       10,
+			},
+			setupMocks: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mlr *MockLotRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "sender", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
+				mcr.On("FindByUserIDAndType", mock.Anything, "receiver", currency.CurrencyTypeFree).Return(mustNewCurrency("receiver", currency.CurrencyTypeFree, 10, 1), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			wantErr: currency.ErrInsufficientBalance,
+		},
+		{
+			name: "異常系: 譲渡できない通貨タイプ",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "receiver",
+				CurrencyType: "paid",
+				Amount:       10,
+			},
+			wantErr: currency.ErrCurrencyNotTransferable,
+		},
+		{
+			name: "異常系: 自分自身への譲渡",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "sender",
+				CurrencyType: "free",
+				Amount:       10,
+			},
+			wantErr: currency.ErrSelfTransfer,
+		},
+		{
+			name: "異常系: 無効な金額",
+			request: &TransferRequest{
+				FromUserID:   "sender",
+				ToUserID:     "receiver",
+				CurrencyType: "free",
+				Amount:       0,
+			},
+			wantErr: currency.ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockLotRepo := new(MockLotRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+			}
+
+			svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, mockLotRepo, mockTxManager)
+
+			resp, err := svc.Transfer(context.Background(), tt.request)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				tt.checkResp(t, resp)
+			}
+
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockLotRepo.AssertExpectations(t)
+			mockTxManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyApplicationService_Transfer_LocksInUserIDOrder(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "正常系: 送信者のユーザーIDが小さい", from: "user_a", to: "user_b"},
+		{name: "正常系: 受信者のユーザーIDが小さい", from: "user_b", to: "user_a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockTxManager := new(MockTransactionManager)
+
+			mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user_a", currency.CurrencyTypeFree).Return(mustNewCurrency("user_a", currency.CurrencyTypeFree, 100, 1), nil)
+			mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user_b", currency.CurrencyTypeFree).Return(mustNewCurrency("user_b", currency.CurrencyTypeFree, 100, 1), nil)
+			mockCurrencyRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+			mockTransactionRepo.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+			mockTxManager.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+
+			svc := newCurrencyAppServiceWithLotRepo(t, mockCurrencyRepo, mockTransactionRepo, newEmptyLotRepository(), mockTxManager)
+
+			_, err := svc.Transfer(context.Background(), &TransferRequest{
+				FromUserID:   tt.from,
+				ToUserID:     tt.to,
+				CurrencyType: "free",
+				Amount:       10,
+			})
+			require.NoError(t, err)
+
+			// 譲渡の方向によらず、ユーザーIDの昇順で通貨をロックする
+			var locked []string
+			for _, call := range mockCurrencyRepo.Calls {
+				if call.Method == "FindByUserIDAndType" {
+					locked = append(locked, call.Arguments.String(1))
+				}
+			}
+			assert.Equal(t, []string{"user_a", "user_b"}, locked)
+		})
+	}
+}
@@ -1,20 +1,44 @@
 package history
 
-import "gem-server/internal/domain/transaction"
+import (
+	"time"
+
+	"gem-server/internal/domain/transaction"
+)
 
 // GetTransactionHistoryRequest トランザクション履歴取得リクエスト
 type GetTransactionHistoryRequest struct {
 	UserID          string
 	Limit           int
 	Offset          int
-	CurrencyType    string // optional: "paid" or "free"
-	TransactionType string // optional: "grant", "consume", etc.
+	CurrencyType    string     // optional: "paid" or "free"
+	TransactionType string     // optional: "grant", "consume", etc.
+	Status          string     // optional: "pending", "completed", etc.
+	Requester       string     // optional: リクエスト元
+	From            *time.Time // optional: この日時以降（含む）
+	To              *time.Time // optional: この日時より前（含まない）
+	Cursor          string     // optional: 前のページのNextCursor（指定時はOffsetより優先）
 }
 
 // GetTransactionHistoryResponse トランザクション履歴取得レスポンス
 type GetTransactionHistoryResponse struct {
 	Transactions []*transaction.Transaction
-	Total        int
+	Total        int // 検索条件に一致する総件数（ページネーション前）
 	Limit        int
 	Offset       int
+	NextCursor   string // 次のページがない場合は空文字列
+}
+
+// ExportTransactionsRequest トランザクションエクスポートリクエスト
+type ExportTransactionsRequest struct {
+	Format          ExportFormat
+	CurrencyType    string     // optional: "paid" or "free"
+	TransactionType string     // optional: "grant", "consume", etc.
+	From            *time.Time // 必須: この日時以降（含む）
+	To              *time.Time // 必須: この日時より前（含まない）
+}
+
+// ExportTransactionsResponse トランザクションエクスポートレスポンス
+type ExportTransactionsResponse struct {
+	Count int // 出力した件数
 }
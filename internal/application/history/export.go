@@ -0,0 +1,204 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"gem-server/internal/domain/transaction"
+)
+
+// ErrUnsupportedExportFormat 未対応のエクスポート形式エラー
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// ExportFormat エクスポート形式
+type ExportFormat string
+
+const (
+	// ExportFormatCSV CSV形式（ヘッダー行付き）
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatNDJSON 1行1JSONオブジェクトの形式
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// ParseExportFormat 文字列からエクスポート形式を取得（空文字列の場合はCSV）
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(s) {
+	case "", ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatNDJSON:
+		return ExportFormatNDJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, s)
+	}
+}
+
+// ContentType HTTPレスポンスのContent-Type
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// exportColumns CSVのヘッダー行（NDJSONのキーと同じ）
+var exportColumns = []string{
+	"transaction_id",
+	"user_id",
+	"transaction_type",
+	"currency_type",
+	"amount",
+	"balance_before",
+	"balance_after",
+	"status",
+	"payment_request_id",
+	"requester",
+	"created_at",
+	"metadata",
+}
+
+// exportRecord エクスポートする1件分のレコード
+type exportRecord struct {
+	TransactionID    string                 `json:"transaction_id"`
+	UserID           string                 `json:"user_id"`
+	TransactionType  string                 `json:"transaction_type"`
+	CurrencyType     string                 `json:"currency_type"`
+	Amount           string                 `json:"amount"`
+	BalanceBefore    string                 `json:"balance_before"`
+	BalanceAfter     string                 `json:"balance_after"`
+	Status           string                 `json:"status"`
+	PaymentRequestID string                 `json:"payment_request_id"`
+	Requester        string                 `json:"requester"`
+	CreatedAt        string                 `json:"created_at"`
+	Metadata         map[string]interface{} `json:"metadata"`
+}
+
+// newExportRecord トランザクションをエクスポート用のレコードに変換
+func newExportRecord(t *transaction.Transaction) exportRecord {
+	record := exportRecord{
+		TransactionID:   t.TransactionID(),
+		UserID:          t.UserID(),
+		TransactionType: t.TransactionType().String(),
+		CurrencyType:    t.CurrencyType().String(),
+		Amount:          strconv.FormatInt(t.Amount(), 10),
+		BalanceBefore:   strconv.FormatInt(t.BalanceBefore(), 10),
+		BalanceAfter:    strconv.FormatInt(t.BalanceAfter(), 10),
+		Status:          t.Status().String(),
+		CreatedAt:       t.CreatedAt().UTC().Format(time.RFC3339),
+		Metadata:        flattenMetadata(t.Metadata()),
+	}
+	if id := t.PaymentRequestID(); id != nil {
+		record.PaymentRequestID = *id
+	}
+	if requester := t.Requester(); requester != nil {
+		record.Requester = *requester
+	}
+	return record
+}
+
+// flattenMetadata ネストしたメタデータを"親.子"形式のキーに展開する
+func flattenMetadata(metadata map[string]interface{}) map[string]interface{} {
+	flat := make(map[string]interface{}, len(metadata))
+	var walk func(prefix string, m map[string]interface{})
+	walk = func(prefix string, m map[string]interface{}) {
+		for k, v := range m {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			if nested, ok := v.(map[string]interface{}); ok {
+				walk(key, nested)
+				continue
+			}
+			flat[key] = v
+		}
+	}
+	walk("", metadata)
+	return flat
+}
+
+// exportWriter トランザクションを1件ずつ書き出すWriter
+type exportWriter interface {
+	Write(t *transaction.Transaction) error
+	Flush() error
+}
+
+// newExportWriter 形式に応じたexportWriterを作成
+func newExportWriter(format ExportFormat, w io.Writer) (exportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{w: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		return &ndjsonExportWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, format)
+	}
+}
+
+// csvExportWriter CSV形式のexportWriter
+// メタデータは展開したキーを持つJSONオブジェクトとして1列に出力する
+type csvExportWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvExportWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.w.Write(exportColumns)
+}
+
+func (w *csvExportWriter) Write(t *transaction.Transaction) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	record := newExportRecord(t)
+	metadata, err := json.Marshal(record.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	return w.w.Write([]string{
+		record.TransactionID,
+		record.UserID,
+		record.TransactionType,
+		record.CurrencyType,
+		record.Amount,
+		record.BalanceBefore,
+		record.BalanceAfter,
+		record.Status,
+		record.PaymentRequestID,
+		record.Requester,
+		record.CreatedAt,
+		string(metadata),
+	})
+}
+
+func (w *csvExportWriter) Flush() error {
+	// 0件の場合もヘッダー行は出力する
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// ndjsonExportWriter NDJSON形式のexportWriter
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *ndjsonExportWriter) Write(t *transaction.Transaction) error {
+	return w.enc.Encode(newExportRecord(t))
+}
+
+func (w *ndjsonExportWriter) Flush() error {
+	return nil
+}
@@ -3,13 +3,14 @@ package history
 import (
 	"context"
 	"fmt"
+	"io"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	otelcodes "go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
-	"gem-server/internal/domain/currency"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 )
@@ -45,6 +46,7 @@ func (s *HistoryApplicationService) GetTransactionHistory(ctx context.Context, r
 		attribute.String("user_id", req.UserID),
 		attribute.Int("limit", req.Limit),
 		attribute.Int("offset", req.Offset),
+		attribute.Bool("cursor", req.Cursor != ""),
 	)
 
 	s.logger.Info(ctx, "Getting transaction history", map[string]interface{}{
@@ -53,6 +55,8 @@ func (s *HistoryApplicationService) GetTransactionHistory(ctx context.Context, r
 		"offset":           req.Offset,
 		"currency_type":    req.CurrencyType,
 		"transaction_type": req.TransactionType,
+		"status":           req.Status,
+		"requester":        req.Requester,
 	})
 
 	// バリデーション
@@ -66,8 +70,43 @@ func (s *HistoryApplicationService) GetTransactionHistory(ctx context.Context, r
 		req.Offset = 0
 	}
 
-	// トランザクション履歴を取得
-	transactions, err := s.transactionRepo.FindByUserID(ctx, req.UserID, req.Limit, req.Offset)
+	filter, err := transaction.NewFilter(
+		req.UserID,
+		req.CurrencyType,
+		req.TransactionType,
+		req.Status,
+		req.Requester,
+		req.From,
+		req.To,
+	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	// トランザクション履歴を取得（絞り込みはSQLで行う）
+	// カーソルが指定された場合はオフセットより優先する
+	var transactions []*transaction.Transaction
+	hasMore := false
+	if req.Cursor != "" {
+		cursor, decodeErr := transaction.DecodeCursor(req.Cursor)
+		if decodeErr != nil {
+			span.RecordError(decodeErr)
+			span.SetStatus(otelcodes.Error, decodeErr.Error())
+			return nil, decodeErr
+		}
+		req.Offset = 0
+
+		// 次のページの有無を判定するため1件多く取得する
+		transactions, err = s.transactionRepo.FindByFilterAfterCursor(ctx, filter, cursor, req.Limit+1)
+		if err == nil && len(transactions) > req.Limit {
+			transactions = transactions[:req.Limit]
+			hasMore = true
+		}
+	} else {
+		transactions, err = s.transactionRepo.FindByFilter(ctx, filter, req.Limit, req.Offset)
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
@@ -77,35 +116,104 @@ func (s *HistoryApplicationService) GetTransactionHistory(ctx context.Context, r
 		return nil, fmt.Errorf("failed to get transaction history: %w", err)
 	}
 
-	// フィルタリング
-	filteredTransactions := make([]*transaction.Transaction, 0, len(transactions))
-	for _, txn := range transactions {
-		// 通貨タイプフィルタ
-		if req.CurrencyType != "" {
-			currencyType, err := currency.NewCurrencyType(req.CurrencyType)
-			if err == nil && txn.CurrencyType() != currencyType {
-				continue
-			}
-		}
+	// 総件数を取得
+	total, err := s.transactionRepo.CountByFilter(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to count transaction history", err, map[string]interface{}{
+			"user_id": req.UserID,
+		})
+		return nil, fmt.Errorf("failed to count transaction history: %w", err)
+	}
 
-		// トランザクションタイプフィルタ
-		if req.TransactionType != "" {
-			transactionType, err := transaction.NewTransactionType(req.TransactionType)
-			if err == nil && txn.TransactionType() != transactionType {
-				continue
-			}
-		}
+	span.SetAttributes(attribute.Int("total", total))
 
-		filteredTransactions = append(filteredTransactions, txn)
+	// 次のページがある場合は最後の要素を指すカーソルを返す
+	if req.Cursor == "" {
+		hasMore = req.Offset+len(transactions) < total
+	}
+	nextCursor := ""
+	if hasMore && len(transactions) > 0 {
+		nextCursor = transaction.NewCursorFromTransaction(transactions[len(transactions)-1]).Encode()
 	}
 
 	// メトリクス記録
 	s.metrics.RecordRequest(ctx, "GET", "/api/v1/users/{user_id}/transactions")
 
 	return &GetTransactionHistoryResponse{
-		Transactions: filteredTransactions,
-		Total:        len(filteredTransactions), // 簡易的な実装（実際には総件数を取得する必要がある）
+		Transactions: transactions,
+		Total:        total,
 		Limit:        req.Limit,
 		Offset:       req.Offset,
+		NextCursor:   nextCursor,
+	}, nil
+}
+
+// ExportTransactions 全ユーザーのトランザクションを指定形式でwに書き出す
+// 1件ずつストリーミングするため件数が多くてもメモリに全件を載せない。
+// 検索条件と形式の検証エラーはwに何も書き込む前に返す
+func (s *HistoryApplicationService) ExportTransactions(ctx context.Context, req *ExportTransactionsRequest, w io.Writer) (*ExportTransactionsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "HistoryApplicationService.ExportTransactions")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("format", string(req.Format)),
+		attribute.String("currency_type", req.CurrencyType),
+		attribute.String("transaction_type", req.TransactionType),
+	)
+
+	filter, err := transaction.NewExportFilter(req.CurrencyType, req.TransactionType, req.From, req.To)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	writer, err := newExportWriter(req.Format, w)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Exporting transactions", map[string]interface{}{
+		"format":           string(req.Format),
+		"currency_type":    req.CurrencyType,
+		"transaction_type": req.TransactionType,
+		"from":             req.From.Format(time.RFC3339),
+		"to":               req.To.Format(time.RFC3339),
+	})
+
+	count := 0
+	err = s.transactionRepo.StreamByFilter(ctx, filter, func(t *transaction.Transaction) error {
+		if err := writer.Write(t); err != nil {
+			return fmt.Errorf("failed to write transaction %s: %w", t.TransactionID(), err)
+		}
+		count++
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		s.logger.Error(ctx, "Failed to export transactions", err, map[string]interface{}{
+			"exported": count,
+		})
+		return nil, fmt.Errorf("failed to export transactions: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("count", count))
+	s.logger.Info(ctx, "Transactions exported", map[string]interface{}{
+		"count": count,
+	})
+
+	// メトリクス記録
+	s.metrics.RecordRequest(ctx, "GET", "/api/v1/admin/transactions/export")
+
+	return &ExportTransactionsResponse{
+		Count: count,
 	}, nil
 }
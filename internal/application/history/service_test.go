@@ -1,8 +1,11 @@
 package history
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -32,14 +35,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -48,7 +69,27 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	cursor := transaction.Cursor{
+		CreatedAt:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		TransactionID: "txn4",
+	}
+
 	tests := []struct {
 		name       string
 		req        *GetTransactionHistoryRequest
@@ -88,7 +129,9 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 						nil,
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(transactions, nil)
+				filter := transaction.Filter{UserID: "user123"}
+				mtr.On("FindByFilter", mock.Anything, filter, 10, 0).Return(transactions, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(2, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
@@ -97,90 +140,133 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 				assert.Equal(t, 2, resp.Total)
 				assert.Equal(t, 10, resp.Limit)
 				assert.Equal(t, 0, resp.Offset)
+				assert.Empty(t, resp.NextCursor)
 			},
 		},
 		{
-			name: "正常系: 通貨タイプでフィルタリング",
+			name: "正常系: 総件数はページの件数ではなく条件に一致する件数",
 			req: &GetTransactionHistoryRequest{
-				UserID:       "user123",
-				Limit:        10,
-				Offset:       0,
-				CurrencyType: "paid",
+				UserID: "user123",
+				Limit:  1,
+				Offset: 1,
 			},
 			setupMocks: func(mtr *MockTransactionRepository) {
 				transactions := []*transaction.Transaction{
-					mustNewTransaction(
-						"txn1",
-						"user123",
-						transaction.TransactionTypeGrant,
-						currency.CurrencyTypePaid,
-						1000,
-						0,
-						1000,
-						transaction.TransactionStatusCompleted,
-						nil,
-					),
 					mustNewTransaction(
 						"txn2",
 						"user123",
-						transaction.TransactionTypeGrant,
-						currency.CurrencyTypeFree,
+						transaction.TransactionTypeConsume,
+						currency.CurrencyTypePaid,
 						500,
-						0,
+						1000,
 						500,
 						transaction.TransactionStatusCompleted,
 						nil,
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(transactions, nil)
+				filter := transaction.Filter{UserID: "user123"}
+				mtr.On("FindByFilter", mock.Anything, filter, 1, 1).Return(transactions, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(42, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
 				require.NoError(t, err)
 				assert.Len(t, resp.Transactions, 1)
-				assert.Equal(t, currency.CurrencyTypePaid, resp.Transactions[0].CurrencyType())
+				assert.Equal(t, 42, resp.Total)
+
+				// オフセット指定でも続きはカーソルで取得できる
+				next, err := transaction.DecodeCursor(resp.NextCursor)
+				require.NoError(t, err)
+				assert.Equal(t, "txn2", next.TransactionID)
 			},
 		},
 		{
-			name: "正常系: トランザクションタイプでフィルタリング",
+			name: "正常系: 検索条件をリポジトリに渡す",
 			req: &GetTransactionHistoryRequest{
 				UserID:          "user123",
 				Limit:           10,
 				Offset:          0,
-				TransactionType: "grant",
+				CurrencyType:    "paid",
+				TransactionType: "consume",
+				Status:          "completed",
+				Requester:       "game-server",
+				From:            &from,
+				To:              &to,
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				filter := transaction.Filter{
+					UserID:          "user123",
+					CurrencyType:    currency.CurrencyTypePaid,
+					TransactionType: transaction.TransactionTypeConsume,
+					Status:          transaction.TransactionStatusCompleted,
+					Requester:       "game-server",
+					From:            &from,
+					To:              &to,
+				}
+				mtr.On("FindByFilter", mock.Anything, filter, 10, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(0, nil)
+			},
+			wantError: false,
+			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, resp.Transactions)
+				assert.Equal(t, 0, resp.Total)
+			},
+		},
+		{
+			name: "正常系: カーソルで次のページを取得",
+			req: &GetTransactionHistoryRequest{
+				UserID: "user123",
+				Limit:  2,
+				Offset: 10, // カーソル指定時は無視される
+				Cursor: cursor.Encode(),
 			},
 			setupMocks: func(mtr *MockTransactionRepository) {
 				transactions := []*transaction.Transaction{
-					mustNewTransaction(
-						"txn1",
-						"user123",
-						transaction.TransactionTypeGrant,
-						currency.CurrencyTypePaid,
-						1000,
-						0,
-						1000,
-						transaction.TransactionStatusCompleted,
-						nil,
-					),
-					mustNewTransaction(
-						"txn2",
-						"user123",
-						transaction.TransactionTypeConsume,
-						currency.CurrencyTypePaid,
-						500,
-						1000,
-						500,
-						transaction.TransactionStatusCompleted,
-						nil,
-					),
+					mustNewTransaction("txn3", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, nil),
+					mustNewTransaction("txn2", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, nil),
+					mustNewTransaction("txn1", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, nil),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(transactions, nil)
+				filter := transaction.Filter{UserID: "user123"}
+				mtr.On("FindByFilterAfterCursor", mock.Anything, filter, mock.MatchedBy(func(c transaction.Cursor) bool {
+					return c.TransactionID == cursor.TransactionID && c.CreatedAt.Equal(cursor.CreatedAt)
+				}), 3).Return(transactions, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(10, nil)
+			},
+			wantError: false,
+			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
+				require.NoError(t, err)
+				require.Len(t, resp.Transactions, 2)
+				assert.Equal(t, "txn3", resp.Transactions[0].TransactionID())
+				assert.Equal(t, "txn2", resp.Transactions[1].TransactionID())
+				assert.Equal(t, 0, resp.Offset)
+				assert.Equal(t, 10, resp.Total)
+
+				next, err := transaction.DecodeCursor(resp.NextCursor)
+				require.NoError(t, err)
+				assert.Equal(t, "txn2", next.TransactionID)
+			},
+		},
+		{
+			name: "正常系: カーソルで最後のページを取得",
+			req: &GetTransactionHistoryRequest{
+				UserID: "user123",
+				Limit:  2,
+				Cursor: cursor.Encode(),
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				transactions := []*transaction.Transaction{
+					mustNewTransaction("txn1", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, nil),
+				}
+				filter := transaction.Filter{UserID: "user123"}
+				mtr.On("FindByFilterAfterCursor", mock.Anything, filter, mock.Anything, 3).Return(transactions, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(10, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
 				require.NoError(t, err)
 				assert.Len(t, resp.Transactions, 1)
-				assert.Equal(t, transaction.TransactionTypeGrant, resp.Transactions[0].TransactionType())
+				assert.Empty(t, resp.NextCursor)
 			},
 		},
 		{
@@ -191,7 +277,8 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 				Offset: -1, // 0に設定される
 			},
 			setupMocks: func(mtr *MockTransactionRepository) {
-				mtr.On("FindByUserID", mock.Anything, "user123", 50, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 50, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(0, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
@@ -208,7 +295,8 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 				Offset: 0,
 			},
 			setupMocks: func(mtr *MockTransactionRepository) {
-				mtr.On("FindByUserID", mock.Anything, "user123", 100, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 100, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(0, nil)
 			},
 			wantError: false,
 			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
@@ -216,6 +304,44 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 				assert.Equal(t, 100, resp.Limit)
 			},
 		},
+		{
+			name: "異常系: 無効な通貨タイプ",
+			req: &GetTransactionHistoryRequest{
+				UserID:       "user123",
+				Limit:        10,
+				CurrencyType: "gold",
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {},
+			wantError:  true,
+			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
+				assert.ErrorIs(t, err, transaction.ErrInvalidFilter)
+			},
+		},
+		{
+			name: "異常系: 無効なカーソル",
+			req: &GetTransactionHistoryRequest{
+				UserID: "user123",
+				Limit:  10,
+				Cursor: "invalid!",
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {},
+			wantError:  true,
+			checkFunc: func(t *testing.T, resp *GetTransactionHistoryResponse, err error) {
+				assert.ErrorIs(t, err, transaction.ErrInvalidCursor)
+			},
+		},
+		{
+			name: "異常系: カーソル指定時のデータベースエラー",
+			req: &GetTransactionHistoryRequest{
+				UserID: "user123",
+				Limit:  10,
+				Cursor: cursor.Encode(),
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				mtr.On("FindByFilterAfterCursor", mock.Anything, transaction.Filter{UserID: "user123"}, mock.Anything, 11).Return(nil, assert.AnError)
+			},
+			wantError: true,
+		},
 		{
 			name: "異常系: データベースエラー",
 			req: &GetTransactionHistoryRequest{
@@ -224,7 +350,20 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 				Offset: 0,
 			},
 			setupMocks: func(mtr *MockTransactionRepository) {
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(nil, assert.AnError)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 10, 0).Return(nil, assert.AnError)
+			},
+			wantError: true,
+		},
+		{
+			name: "異常系: 総件数の取得でデータベースエラー",
+			req: &GetTransactionHistoryRequest{
+				UserID: "user123",
+				Limit:  10,
+				Offset: 0,
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 10, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(0, assert.AnError)
 			},
 			wantError: true,
 		},
@@ -235,6 +374,7 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 			mockTransactionRepo := new(MockTransactionRepository)
 
 			tt.setupMocks(mockTransactionRepo)
+			defer mockTransactionRepo.AssertExpectations(t)
 
 			tracer := otel.Tracer("test")
 			logger := otelinfra.NewLogger(tracer)
@@ -252,6 +392,9 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 
 			if tt.wantError {
 				assert.Error(t, err)
+				if tt.checkFunc != nil {
+					tt.checkFunc(t, got, err)
+				}
 			} else {
 				if tt.checkFunc != nil {
 					tt.checkFunc(t, got, err)
@@ -264,6 +407,195 @@ func TestHistoryApplicationService_GetTransactionHistory(t *testing.T) {
 	}
 }
 
+func TestHistoryApplicationService_ExportTransactions(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	consume := mustNewTransaction(
+		"txn2",
+		"user456",
+		transaction.TransactionTypeConsume,
+		currency.CurrencyTypePaid,
+		500,
+		1000,
+		500,
+		transaction.TransactionStatusCompleted,
+		map[string]interface{}{"item": map[string]interface{}{"id": "sword", "qty": float64(1)}},
+	)
+	consume.SetPaymentRequestID("pr_1")
+	consume.SetRequester("game-server")
+	grant := mustNewTransaction(
+		"txn1",
+		"user123",
+		transaction.TransactionTypeGrant,
+		currency.CurrencyTypePaid,
+		1000,
+		0,
+		1000,
+		transaction.TransactionStatusCompleted,
+		nil,
+	)
+
+	// streamTransactions StreamByFilterのコールバックにトランザクションを渡す
+	streamTransactions := func(txns ...*transaction.Transaction) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			fn := args.Get(2).(func(*transaction.Transaction) error)
+			for _, txn := range txns {
+				if err := fn(txn); err != nil {
+					return
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        *ExportTransactionsRequest
+		setupMocks func(*MockTransactionRepository)
+		wantErr    error
+		wantCount  int
+		wantOutput string
+	}{
+		{
+			name: "正常系: CSVで出力",
+			req: &ExportTransactionsRequest{
+				Format:       ExportFormatCSV,
+				CurrencyType: "paid",
+				From:         &from,
+				To:           &to,
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				filter := transaction.Filter{CurrencyType: currency.CurrencyTypePaid, From: &from, To: &to}
+				mtr.On("StreamByFilter", mock.Anything, filter, mock.Anything).
+					Run(streamTransactions(grant, consume)).
+					Return(nil)
+			},
+			wantCount: 2,
+			wantOutput: "transaction_id,user_id,transaction_type,currency_type,amount,balance_before,balance_after,status,payment_request_id,requester,created_at,metadata\n" +
+				"txn1,user123,grant,paid,1000,0,1000,completed,,," + grant.CreatedAt().UTC().Format(time.RFC3339) + ",{}\n" +
+				"txn2,user456,consume,paid,500,1000,500,completed,pr_1,game-server," + consume.CreatedAt().UTC().Format(time.RFC3339) + `,"{""item.id"":""sword"",""item.qty"":1}"` + "\n",
+		},
+		{
+			name: "正常系: NDJSONで出力",
+			req: &ExportTransactionsRequest{
+				Format:          ExportFormatNDJSON,
+				TransactionType: "consume",
+				From:            &from,
+				To:              &to,
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				filter := transaction.Filter{TransactionType: transaction.TransactionTypeConsume, From: &from, To: &to}
+				mtr.On("StreamByFilter", mock.Anything, filter, mock.Anything).
+					Run(streamTransactions(consume)).
+					Return(nil)
+			},
+			wantCount: 1,
+			wantOutput: `{"transaction_id":"txn2","user_id":"user456","transaction_type":"consume","currency_type":"paid","amount":"500","balance_before":"1000","balance_after":"500","status":"completed","payment_request_id":"pr_1","requester":"game-server","created_at":"` +
+				consume.CreatedAt().UTC().Format(time.RFC3339) + `","metadata":{"item.id":"sword","item.qty":1}}` + "\n",
+		},
+		{
+			name: "正常系: 0件でもCSVのヘッダーを出力",
+			req: &ExportTransactionsRequest{
+				Format: ExportFormatCSV,
+				From:   &from,
+				To:     &to,
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				mtr.On("StreamByFilter", mock.Anything, transaction.Filter{From: &from, To: &to}, mock.Anything).Return(nil)
+			},
+			wantCount:  0,
+			wantOutput: "transaction_id,user_id,transaction_type,currency_type,amount,balance_before,balance_after,status,payment_request_id,requester,created_at,metadata\n",
+		},
+		{
+			name: "異常系: 期間が未指定",
+			req: &ExportTransactionsRequest{
+				Format: ExportFormatCSV,
+				From:   &from,
+			},
+			wantErr: transaction.ErrInvalidFilter,
+		},
+		{
+			name: "異常系: 未対応の形式",
+			req: &ExportTransactionsRequest{
+				Format: "xlsx",
+				From:   &from,
+				To:     &to,
+			},
+			wantErr: ErrUnsupportedExportFormat,
+		},
+		{
+			name: "異常系: データベースエラー",
+			req: &ExportTransactionsRequest{
+				Format: ExportFormatNDJSON,
+				From:   &from,
+				To:     &to,
+			},
+			setupMocks: func(mtr *MockTransactionRepository) {
+				mtr.On("StreamByFilter", mock.Anything, transaction.Filter{From: &from, To: &to}, mock.Anything).Return(assert.AnError)
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTransactionRepo := new(MockTransactionRepository)
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockTransactionRepo)
+			}
+			defer mockTransactionRepo.AssertExpectations(t)
+
+			tracer := otel.Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, err := otelinfra.NewMetrics("test")
+			require.NoError(t, err)
+
+			svc := NewHistoryApplicationService(mockTransactionRepo, logger, metrics)
+
+			var buf bytes.Buffer
+			got, err := svc.ExportTransactions(context.Background(), tt.req, &buf)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				if !errors.Is(tt.wantErr, assert.AnError) {
+					// 検証エラーの場合は何も書き込まない
+					assert.Empty(t, buf.String())
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantCount, got.Count)
+			assert.Equal(t, tt.wantOutput, buf.String())
+		})
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ExportFormat
+		wantErr bool
+	}{
+		{name: "正常系: 未指定はCSV", input: "", want: ExportFormatCSV},
+		{name: "正常系: csv", input: "csv", want: ExportFormatCSV},
+		{name: "正常系: ndjson", input: "ndjson", want: ExportFormatNDJSON},
+		{name: "異常系: 未対応の形式", input: "json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExportFormat(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnsupportedExportFormat)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func mustNewTransaction(transactionID, userID string, transactionType transaction.TransactionType, currencyType currency.CurrencyType, amount, balanceBefore, balanceAfter int64, status transaction.TransactionStatus, metadata map[string]interface{}) *transaction.Transaction {
 	tx, err := transaction.NewTransaction(transactionID, userID, transactionType, currencyType, amount, balanceBefore, balanceAfter, status, metadata)
 	if err != nil {
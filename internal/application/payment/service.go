@@ -2,7 +2,6 @@ package payment
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"math"
 	"time"
@@ -13,6 +12,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
@@ -22,8 +22,10 @@ import (
 type PaymentApplicationService struct {
 	currencyRepo       currency.CurrencyRepository
 	transactionRepo    transaction.TransactionRepository
+	lotRepo            currency.LotRepository
 	paymentRequestRepo payment_request.PaymentRequestRepository
 	txManager          transaction.TransactionManager
+	idGenerator        idgen.Generator
 	logger             *otelinfra.Logger
 	metrics            *otelinfra.Metrics
 	tracer             trace.Tracer
@@ -34,16 +36,20 @@ type PaymentApplicationService struct {
 func NewPaymentApplicationService(
 	currencyRepo currency.CurrencyRepository,
 	transactionRepo transaction.TransactionRepository,
+	lotRepo currency.LotRepository,
 	paymentRequestRepo payment_request.PaymentRequestRepository,
 	txManager transaction.TransactionManager,
+	idGenerator idgen.Generator,
 	logger *otelinfra.Logger,
 	metrics *otelinfra.Metrics,
 ) *PaymentApplicationService {
 	return &PaymentApplicationService{
 		currencyRepo:       currencyRepo,
 		transactionRepo:    transactionRepo,
+		lotRepo:            lotRepo,
 		paymentRequestRepo: paymentRequestRepo,
 		txManager:          txManager,
+		idGenerator:        idGenerator,
 		logger:             logger,
 		metrics:            metrics,
 		tracer:             otel.Tracer("payment-service"),
@@ -154,7 +160,7 @@ func (s *PaymentApplicationService) ProcessPayment(ctx context.Context, req *Pro
 	var consumptionDetails []ConsumptionDetail
 	var totalConsumed int64
 
-	err = s.txManager.WithTransaction(ctx, func(tx *sql.Tx) error {
+	err = s.txManager.WithTransaction(ctx, func(ctx context.Context) error {
 		remainingAmount := req.Amount
 
 		// 無料通貨から消費
@@ -163,11 +169,23 @@ func (s *PaymentApplicationService) ProcessPayment(ctx context.Context, req *Pro
 			return fmt.Errorf("failed to find free currency: %w", err)
 		}
 
-		if freeCurrency != nil && freeCurrency.Balance() > 0 {
+		// 失効処理前の期限切れロットの残量は消費できない
+		now := time.Now()
+		var freeSpendable int64
+		var freeLots []*currency.Lot
+		if freeCurrency != nil {
+			freeLots, err = s.lotRepo.FindAvailableByUserIDAndType(ctx, req.UserID, currency.CurrencyTypeFree)
+			if err != nil {
+				return fmt.Errorf("failed to find currency lots: %w", err)
+			}
+			freeSpendable = freeCurrency.Balance() - currency.ExpiredRemaining(freeLots, now)
+		}
+
+		if freeSpendable > 0 {
 			freeBalanceBefore := freeCurrency.Balance()
 			freeConsumeAmount := remainingAmount
-			if freeConsumeAmount > freeBalanceBefore {
-				freeConsumeAmount = freeBalanceBefore
+			if freeConsumeAmount > freeSpendable {
+				freeConsumeAmount = freeSpendable
 			}
 
 			// 楽観的ロックのリトライロジック
@@ -180,10 +198,15 @@ func (s *PaymentApplicationService) ProcessPayment(ctx context.Context, req *Pro
 					if err != nil {
 						return fmt.Errorf("failed to find free currency: %w", err)
 					}
+					freeLots, err = s.lotRepo.FindAvailableByUserIDAndType(ctx, req.UserID, currency.CurrencyTypeFree)
+					if err != nil {
+						return fmt.Errorf("failed to find currency lots: %w", err)
+					}
+					freeSpendable = freeCurrency.Balance() - currency.ExpiredRemaining(freeLots, now)
 					freeBalanceBefore = freeCurrency.Balance()
 					freeConsumeAmount = remainingAmount
-					if freeConsumeAmount > freeBalanceBefore {
-						freeConsumeAmount = freeBalanceBefore
+					if freeConsumeAmount > freeSpendable {
+						freeConsumeAmount = freeSpendable
 					}
 				}
 
@@ -199,6 +222,19 @@ func (s *PaymentApplicationService) ProcessPayment(ctx context.Context, req *Pro
 					return fmt.Errorf("failed to save free currency after retries: %w", err)
 				}
 
+				// 有効期限の近いロットから消費し、返金に備えて消費した内訳を記録
+				touchedLots, lotConsumptions := currency.AllocateLots(freeLots, freeConsumeAmount, now)
+				for _, lot := range touchedLots {
+					if err := s.lotRepo.Save(ctx, lot); err != nil {
+						return fmt.Errorf("failed to save lot: %w", err)
+					}
+				}
+				if len(lotConsumptions) > 0 {
+					if err := s.lotRepo.SaveConsumptions(ctx, fmt.Sprintf("%s_free", transactionID), lotConsumptions); err != nil {
+						return fmt.Errorf("failed to save lot consumptions: %w", err)
+					}
+				}
+
 				consumptionDetails = append(consumptionDetails, ConsumptionDetail{
 					CurrencyType:  "free",
 					Amount:        freeConsumeAmount,
@@ -376,5 +412,5 @@ func (s *PaymentApplicationService) ProcessPayment(ctx context.Context, req *Pro
 
 // generateTransactionID トランザクションIDを生成
 func (s *PaymentApplicationService) generateTransactionID() string {
-	return fmt.Sprintf("txn_%d", time.Now().UnixNano())
+	return "txn_" + s.idGenerator.NewID()
 }
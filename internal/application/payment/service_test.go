@@ -2,8 +2,8 @@ package payment
 
 import (
 	"context"
-	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -11,6 +11,7 @@ import (
 	"go.opentelemetry.io/otel"
 
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
@@ -57,14 +58,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -73,6 +92,19 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 // MockPaymentRequestRepository モックPaymentRequestリポジトリ
 type MockPaymentRequestRepository struct {
 	mock.Mock
@@ -96,15 +128,67 @@ func (m *MockPaymentRequestRepository) Update(ctx context.Context, pr *payment_r
 	return args.Error(0)
 }
 
+// MockLotRepository モックロットリポジトリ
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	args := m.Called(ctx, transactionID, consumptions)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]currency.LotConsumption), args.Error(1)
+}
+
+// newEmptyLotRepository 有効期限付きロットが存在しない状態のモックロットリポジトリを作成
+func newEmptyLotRepository() *MockLotRepository {
+	m := new(MockLotRepository)
+	m.On("FindAvailableByUserIDAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*currency.Lot{}, nil).Maybe()
+	m.On("FindConsumptionsByTransactionID", mock.Anything, mock.Anything).Return([]currency.LotConsumption{}, nil).Maybe()
+	return m
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(mock.Anything, mock.Anything)
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
@@ -467,8 +551,10 @@ func TestPaymentApplicationService_ProcessPayment(t *testing.T) {
 			svc := NewPaymentApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockPaymentRequestRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
 				logger,
 				metrics,
 			)
@@ -0,0 +1,113 @@
+package api_key
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// keyValueBytes 管理APIキーの乱数部分のバイト数
+const keyValueBytes = 32
+
+// keyValuePrefix 生成した管理APIキーの接頭辞（ログやリポジトリに誤って混入した場合に見つけやすくする）
+const keyValuePrefix = "gsk_"
+
+// namePattern 管理APIキーの名前として使える文字列（トランザクションのリクエスト元として記録される）
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:-]{0,99}$`)
+
+// APIKey 管理APIキーエンティティ
+// キーの値はハッシュのみを保持し、平文は発行時に一度だけ表示する
+type APIKey struct {
+	name      string
+	keyHash   string
+	scopes    []Scope
+	createdAt time.Time
+	revokedAt *time.Time
+}
+
+// NewAPIKey 新しいAPIKeyエンティティを作成
+func NewAPIKey(name, keyHash string, scopes []Scope) (*APIKey, error) {
+	if !namePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAPIKeyName, name)
+	}
+	for _, scope := range scopes {
+		if !scope.Valid() {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	return &APIKey{
+		name:      name,
+		keyHash:   keyHash,
+		scopes:    scopes,
+		createdAt: time.Now(),
+	}, nil
+}
+
+// GenerateKeyValue 推測できない管理APIキーの値を生成
+func GenerateKeyValue() (string, error) {
+	b := make([]byte, keyValueBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return keyValuePrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashKey 管理APIキーの値から保存用のハッシュ（SHA-256の16進数表記）を計算
+func HashKey(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Name キーの名前を返す（トランザクションのリクエスト元として記録される）
+func (k *APIKey) Name() string {
+	return k.name
+}
+
+// KeyHash キーのハッシュを返す
+func (k *APIKey) KeyHash() string {
+	return k.keyHash
+}
+
+// Scopes 許可されたスコープを返す
+func (k *APIKey) Scopes() []Scope {
+	return k.scopes
+}
+
+// CreatedAt 作成日時を返す
+func (k *APIKey) CreatedAt() time.Time {
+	return k.createdAt
+}
+
+// RevokedAt 失効日時を返す（失効していない場合はnil）
+func (k *APIKey) RevokedAt() *time.Time {
+	return k.revokedAt
+}
+
+// IsRevoked 失効しているかどうか
+func (k *APIKey) IsRevoked() bool {
+	return k.revokedAt != nil
+}
+
+// HasScope 指定したスコープの操作が許可されているかどうか（*はすべてのスコープを含む）
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRevokedAt 失効日時を設定（リポジトリから読み込んだ際に使用）
+func (k *APIKey) SetRevokedAt(revokedAt *time.Time) {
+	k.revokedAt = revokedAt
+}
+
+// SetCreatedAt 作成日時を設定（リポジトリから読み込んだ際に使用）
+func (k *APIKey) SetCreatedAt(createdAt time.Time) {
+	k.createdAt = createdAt
+}
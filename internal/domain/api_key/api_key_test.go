@@ -0,0 +1,123 @@
+package api_key
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyName string
+		scopes  []Scope
+		wantErr error
+	}{
+		{
+			name:    "正常系: サービス名",
+			keyName: "game-server",
+			scopes:  []Scope{ScopeCurrencyGrant},
+		},
+		{
+			name:    "正常系: スコープなし",
+			keyName: "ops:readonly",
+		},
+		{
+			name:    "異常系: 名前が空",
+			keyName: "",
+			wantErr: ErrInvalidAPIKeyName,
+		},
+		{
+			name:    "異常系: 名前に空白を含む",
+			keyName: "game server",
+			wantErr: ErrInvalidAPIKeyName,
+		},
+		{
+			name:    "異常系: 名前が長すぎる",
+			keyName: strings.Repeat("a", 101),
+			wantErr: ErrInvalidAPIKeyName,
+		},
+		{
+			name:    "異常系: 不明なスコープ",
+			keyName: "game-server",
+			scopes:  []Scope{"currency:mint"},
+			wantErr: ErrInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewAPIKey(tt.keyName, HashKey("value"), tt.scopes)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.keyName, key.Name())
+			assert.False(t, key.IsRevoked())
+		})
+	}
+}
+
+func TestAPIKey_HasScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []Scope
+		scope  Scope
+		want   bool
+	}{
+		{
+			name:   "正常系: 許可されたスコープ",
+			scopes: []Scope{ScopeCurrencyGrant, ScopeHistoryRead},
+			scope:  ScopeHistoryRead,
+			want:   true,
+		},
+		{
+			name:   "正常系: すべての操作を許可",
+			scopes: []Scope{ScopeAll},
+			scope:  ScopeTokensIssue,
+			want:   true,
+		},
+		{
+			name:   "異常系: 許可されていないスコープ",
+			scopes: []Scope{ScopeCurrencyGrant},
+			scope:  ScopeCurrencyConsume,
+			want:   false,
+		},
+		{
+			name:  "異常系: スコープなし",
+			scope: ScopeCurrencyRead,
+			want:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := NewAPIKey("game-server", HashKey("value"), tt.scopes)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, key.HasScope(tt.scope))
+		})
+	}
+}
+
+func TestGenerateKeyValue(t *testing.T) {
+	v1, err := GenerateKeyValue()
+	require.NoError(t, err)
+	v2, err := GenerateKeyValue()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(v1, keyValuePrefix))
+	assert.NotEqual(t, v1, v2)
+	assert.Len(t, HashKey(v1), 64)
+}
+
+func TestRequesterFromContext(t *testing.T) {
+	assert.Equal(t, "", RequesterFromContext(context.Background()))
+
+	key, err := NewAPIKey("game-server", HashKey("value"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "game-server", RequesterFromContext(NewContext(context.Background(), key)))
+}
@@ -0,0 +1,25 @@
+package api_key
+
+import "context"
+
+// contextKey 認証済みの管理APIキーをコンテキストに格納するためのキー
+type contextKey struct{}
+
+// NewContext 認証済みの管理APIキーを格納したコンテキストを返す
+func NewContext(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, contextKey{}, key)
+}
+
+// FromContext コンテキストから認証済みの管理APIキーを取得
+func FromContext(ctx context.Context) (*APIKey, bool) {
+	key, ok := ctx.Value(contextKey{}).(*APIKey)
+	return key, ok
+}
+
+// RequesterFromContext トランザクションのリクエスト元として記録する管理APIキーの名前を返す（認証されていない場合は空）
+func RequesterFromContext(ctx context.Context) string {
+	if key, ok := FromContext(ctx); ok {
+		return key.Name()
+	}
+	return ""
+}
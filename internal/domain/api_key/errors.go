@@ -0,0 +1,14 @@
+package api_key
+
+import "errors"
+
+var (
+	// ErrAPIKeyNotFound 管理APIキーが見つからないエラー（存在しない、失効済み）
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrAPIKeyAlreadyExists 同じ名前の管理APIキーが既に存在するエラー
+	ErrAPIKeyAlreadyExists = errors.New("api key already exists")
+	// ErrInvalidScope スコープが不正なエラー
+	ErrInvalidScope = errors.New("invalid scope")
+	// ErrInvalidAPIKeyName 管理APIキーの名前が不正なエラー
+	ErrInvalidAPIKeyName = errors.New("invalid api key name")
+)
@@ -0,0 +1,23 @@
+package api_key
+
+import (
+	"context"
+	"time"
+)
+
+// Repository 管理APIキーリポジトリインターフェース
+type Repository interface {
+	// FindByHash キーのハッシュで管理APIキーを取得
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+}
+
+// Store 管理APIキーを登録・失効できるリポジトリ（MySQLに保存する場合に使用）
+type Store interface {
+	Repository
+
+	// Save 管理APIキーを保存（同じ名前のキーがある場合はErrAPIKeyAlreadyExists）
+	Save(ctx context.Context, key *APIKey) error
+
+	// Revoke 名前を指定して管理APIキーを失効させる（未失効のキーがない場合はErrAPIKeyNotFound）
+	Revoke(ctx context.Context, name string, revokedAt time.Time) error
+}
@@ -0,0 +1,82 @@
+package api_key
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope 管理APIキーに許可する操作の範囲を表す値オブジェクト
+type Scope string
+
+const (
+	ScopeAll                Scope = "*"                   // すべての操作
+	ScopeCurrencyRead       Scope = "currency:read"       // 残高の参照
+	ScopeCurrencyGrant      Scope = "currency:grant"      // 通貨の付与
+	ScopeCurrencyConsume    Scope = "currency:consume"    // 通貨の消費
+	ScopeCurrencyRefund     Scope = "currency:refund"     // 消費トランザクションの返金
+	ScopeCurrencyCompensate Scope = "currency:compensate" // 補填・回収による残高調整
+	ScopeCurrencyTransfer   Scope = "currency:transfer"   // ユーザー間の通貨譲渡
+	ScopePaymentsProcess    Scope = "payments:process"    // 決済処理
+	ScopeCodesManage        Scope = "codes:manage"        // 引き換えコードの作成・変更・参照
+	ScopeCodesRedeem        Scope = "codes:redeem"        // ユーザーに代わってのコード引き換え
+	ScopeTokensIssue        Scope = "tokens:issue"        // アクセストークンの発行
+	ScopeTokensRevoke       Scope = "tokens:revoke"       // トークンの失効
+	ScopeHistoryRead        Scope = "history:read"        // トランザクション履歴の参照・エクスポート
+)
+
+// NewScope 新しいScopeを作成
+func NewScope(s string) (Scope, error) {
+	scope := Scope(s)
+	if !scope.Valid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidScope, s)
+	}
+	return scope, nil
+}
+
+// ParseScopes カンマ区切りのスコープを解析する（空の要素と重複は無視する）
+func ParseScopes(s string) ([]Scope, error) {
+	var scopes []Scope
+	seen := make(map[Scope]bool)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		scope, err := NewScope(part)
+		if err != nil {
+			return nil, err
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// FormatScopes スコープをカンマ区切りの文字列にする
+func FormatScopes(scopes []Scope) string {
+	parts := make([]string, len(scopes))
+	for i, scope := range scopes {
+		parts[i] = scope.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// String 文字列表現を返す
+func (s Scope) String() string {
+	return string(s)
+}
+
+// Valid 有効なスコープかどうかを返す
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeAll, ScopeCurrencyRead, ScopeCurrencyGrant, ScopeCurrencyConsume, ScopeCurrencyRefund,
+		ScopeCurrencyCompensate, ScopeCurrencyTransfer, ScopePaymentsProcess, ScopeCodesManage,
+		ScopeCodesRedeem, ScopeTokensIssue, ScopeTokensRevoke, ScopeHistoryRead:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,60 @@
+package api_key
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Scope
+		wantErr error
+	}{
+		{
+			name:  "正常系: カンマ区切り",
+			input: "currency:grant, currency:consume,history:read",
+			want:  []Scope{ScopeCurrencyGrant, ScopeCurrencyConsume, ScopeHistoryRead},
+		},
+		{
+			name:  "正常系: 空の要素と重複を無視",
+			input: "codes:manage,,codes:manage,",
+			want:  []Scope{ScopeCodesManage},
+		},
+		{
+			name:  "正常系: すべての操作",
+			input: "*",
+			want:  []Scope{ScopeAll},
+		},
+		{
+			name:  "正常系: 空文字列",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:    "異常系: 不明なスコープ",
+			input:   "currency:grant,currency:*",
+			wantErr: ErrInvalidScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScopes(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatScopes(t *testing.T) {
+	assert.Equal(t, "currency:grant,history:read", FormatScopes([]Scope{ScopeCurrencyGrant, ScopeHistoryRead}))
+	assert.Equal(t, "", FormatScopes(nil))
+}
@@ -0,0 +1,23 @@
+package auth_token
+
+import (
+	"context"
+	"time"
+)
+
+// Denylist 失効させたアクセストークンの一覧
+// アクセストークンは有効期限まで検証だけで受け入れられるため、失効させたものを有効期限まで記録する
+type Denylist interface {
+	// Add jtiのアクセストークンを失効させる。expiresAtを過ぎたら記録を破棄してよい
+	Add(ctx context.Context, jti string, expiresAt time.Time) error
+
+	// Contains jtiのアクセストークンが失効しているかどうか
+	Contains(ctx context.Context, jti string) (bool, error)
+
+	// RevokeUser revokedAt以前に発行したユーザーのアクセストークンをすべて失効させる
+	// untilを過ぎたら（発行済みのアクセストークンがすべて期限切れになったら）記録を破棄してよい
+	RevokeUser(ctx context.Context, userID string, revokedAt, until time.Time) error
+
+	// UserRevokedAt ユーザーのアクセストークンを失効させた日時を返す（失効させていなければゼロ値）
+	UserRevokedAt(ctx context.Context, userID string) (time.Time, error)
+}
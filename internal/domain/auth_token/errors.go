@@ -0,0 +1,14 @@
+package auth_token
+
+import "errors"
+
+var (
+	// ErrRefreshTokenNotFound リフレッシュトークンが見つからないエラー
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrInvalidRefreshToken リフレッシュトークンが無効なエラー（存在しない、期限切れ、失効済み）
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrRefreshTokenRevoked リフレッシュトークンが失効済みのエラー
+	ErrRefreshTokenRevoked = errors.New("refresh token already revoked")
+	// ErrInvalidToken 失効させるトークンが不正なエラー
+	ErrInvalidToken = errors.New("invalid token")
+)
@@ -0,0 +1,134 @@
+package auth_token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// refreshTokenBytes リフレッシュトークンの乱数部分のバイト数
+const refreshTokenBytes = 32
+
+// RefreshToken リフレッシュトークンエンティティ
+// トークンの値はハッシュのみを保持し、平文は発行時にクライアントへ返すだけにする
+type RefreshToken struct {
+	tokenID    string
+	userID     string
+	familyID   string // ローテーションで引き継ぐ系列ID（最初に発行したトークンのID）
+	tokenHash  string
+	expiresAt  time.Time
+	revokedAt  *time.Time
+	replacedBy string // ローテーション後のトークンID
+	createdAt  time.Time
+}
+
+// NewRefreshToken 新しいRefreshTokenエンティティを作成
+// familyIDが空の場合は新しい系列としてtokenIDを系列IDにする
+func NewRefreshToken(tokenID, userID, familyID, tokenHash string, expiresAt time.Time) *RefreshToken {
+	if familyID == "" {
+		familyID = tokenID
+	}
+	return &RefreshToken{
+		tokenID:   tokenID,
+		userID:    userID,
+		familyID:  familyID,
+		tokenHash: tokenHash,
+		expiresAt: expiresAt,
+		createdAt: time.Now(),
+	}
+}
+
+// GenerateRefreshTokenValue 推測できないリフレッシュトークンの値を生成
+func GenerateRefreshTokenValue() (string, error) {
+	b := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken リフレッシュトークンの値から保存用のハッシュ（SHA-256の16進数表記）を計算
+func HashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenID トークンIDを返す
+func (t *RefreshToken) TokenID() string {
+	return t.tokenID
+}
+
+// UserID ユーザーIDを返す
+func (t *RefreshToken) UserID() string {
+	return t.userID
+}
+
+// FamilyID 系列IDを返す
+func (t *RefreshToken) FamilyID() string {
+	return t.familyID
+}
+
+// TokenHash トークンのハッシュを返す
+func (t *RefreshToken) TokenHash() string {
+	return t.tokenHash
+}
+
+// ExpiresAt 有効期限を返す
+func (t *RefreshToken) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// RevokedAt 失効日時を返す（失効していない場合はnil）
+func (t *RefreshToken) RevokedAt() *time.Time {
+	return t.revokedAt
+}
+
+// ReplacedBy ローテーション後のトークンIDを返す
+func (t *RefreshToken) ReplacedBy() string {
+	return t.replacedBy
+}
+
+// CreatedAt 作成日時を返す
+func (t *RefreshToken) CreatedAt() time.Time {
+	return t.createdAt
+}
+
+// IsExpired 指定時刻時点で有効期限を過ぎているかどうか
+func (t *RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.expiresAt)
+}
+
+// IsRevoked 失効しているかどうか（ローテーション済みを含む）
+func (t *RefreshToken) IsRevoked() bool {
+	return t.revokedAt != nil
+}
+
+// IsRotated ローテーション済みかどうか
+// ローテーション済みのトークンが再度使われた場合は漏洩の可能性がある
+func (t *RefreshToken) IsRotated() bool {
+	return t.replacedBy != ""
+}
+
+// Rotate 新しいトークンに置き換えて失効させる
+func (t *RefreshToken) Rotate(newTokenID string, now time.Time) error {
+	if t.IsRevoked() {
+		return ErrRefreshTokenRevoked
+	}
+	t.revokedAt = &now
+	t.replacedBy = newTokenID
+	return nil
+}
+
+// SetRevoked 失効日時とローテーション後のトークンIDを設定（リポジトリから読み込んだ際に使用）
+func (t *RefreshToken) SetRevoked(revokedAt *time.Time, replacedBy string) {
+	t.revokedAt = revokedAt
+	t.replacedBy = replacedBy
+}
+
+// SetCreatedAt 作成日時を設定（リポジトリから読み込んだ際に使用）
+func (t *RefreshToken) SetCreatedAt(createdAt time.Time) {
+	t.createdAt = createdAt
+}
@@ -0,0 +1,87 @@
+package auth_token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRefreshToken(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Run("正常系: 系列IDを省略すると新しい系列になる", func(t *testing.T) {
+		token := NewRefreshToken("rt_1", "user123", "", HashToken("value"), expiresAt)
+		assert.Equal(t, "rt_1", token.FamilyID())
+		assert.False(t, token.IsRevoked())
+		assert.False(t, token.IsRotated())
+	})
+
+	t.Run("正常系: 系列を引き継ぐ", func(t *testing.T) {
+		token := NewRefreshToken("rt_2", "user123", "rt_1", HashToken("value"), expiresAt)
+		assert.Equal(t, "rt_1", token.FamilyID())
+	})
+}
+
+func TestRefreshToken_Rotate(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		revoked bool
+		wantErr error
+	}{
+		{
+			name: "正常系: 未失効のトークンをローテーション",
+		},
+		{
+			name:    "異常系: 失効済みのトークン",
+			revoked: true,
+			wantErr: ErrRefreshTokenRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := NewRefreshToken("rt_1", "user123", "", HashToken("value"), now.Add(time.Hour))
+			if tt.revoked {
+				revokedAt := now.Add(-time.Minute)
+				token.SetRevoked(&revokedAt, "")
+			}
+
+			err := token.Rotate("rt_2", now)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, token.IsRotated())
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, token.IsRevoked())
+			assert.True(t, token.IsRotated())
+			assert.Equal(t, "rt_2", token.ReplacedBy())
+			assert.Equal(t, now, *token.RevokedAt())
+		})
+	}
+}
+
+func TestRefreshToken_IsExpired(t *testing.T) {
+	now := time.Now()
+	token := NewRefreshToken("rt_1", "user123", "", HashToken("value"), now)
+
+	assert.False(t, token.IsExpired(now.Add(-time.Second)))
+	assert.True(t, token.IsExpired(now))
+}
+
+func TestGenerateRefreshTokenValue(t *testing.T) {
+	v1, err := GenerateRefreshTokenValue()
+	require.NoError(t, err)
+	v2, err := GenerateRefreshTokenValue()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, v1, v2)
+	assert.Len(t, v1, 43) // 32バイトのbase64url（パディングなし）
+	assert.Len(t, HashToken(v1), 64)
+	assert.Equal(t, HashToken(v1), HashToken(v1))
+	assert.NotEqual(t, HashToken(v1), HashToken(v2))
+}
@@ -0,0 +1,25 @@
+package auth_token
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshTokenRepository リフレッシュトークンリポジトリインターフェース
+type RefreshTokenRepository interface {
+	// Save リフレッシュトークンを保存
+	Save(ctx context.Context, token *RefreshToken) error
+
+	// FindByHash トークンのハッシュでリフレッシュトークンを取得
+	// トランザクション内で呼び出した場合は、ローテーションが完了するまで行をロックする
+	FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	// Update リフレッシュトークンの失効状態を更新
+	Update(ctx context.Context, token *RefreshToken) error
+
+	// RevokeFamily 系列の未失効のリフレッシュトークンをすべて失効させ、失効させた件数を返す
+	RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) (int, error)
+
+	// RevokeByUserID ユーザーの未失効のリフレッシュトークンをすべて失効させ、失効させた件数を返す
+	RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) (int, error)
+}
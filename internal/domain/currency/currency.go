@@ -29,6 +29,8 @@ type Currency struct {
 	currencyType CurrencyType
 	balance      int64 // 整数値（小数点なし）、マイナス値を許可
 	version      int   // 楽観的ロック用
+	// loadedVersion 読み込んだ時点のバージョン（保存時に楽観的ロックで比較する）
+	loadedVersion int
 }
 
 // NewCurrency 新しいCurrencyエンティティを作成
@@ -40,10 +42,11 @@ func NewCurrency(userID string, currencyType CurrencyType, balance int64, versio
 		return nil, ErrBalanceOutOfRange
 	}
 	return &Currency{
-		userID:       userID,
-		currencyType: currencyType,
-		balance:      balance,
-		version:      version,
+		userID:        userID,
+		currencyType:  currencyType,
+		balance:       balance,
+		version:       version,
+		loadedVersion: version,
 	}, nil
 }
 
@@ -118,6 +121,12 @@ func (c *Currency) ConsumeAllowNegative(amount int64) error {
 	return nil
 }
 
+// LoadedVersion 読み込んだ時点のバージョンを返す
+// 保存時はこのバージョンのままであることを条件に更新する
+func (c *Currency) LoadedVersion() int {
+	return c.loadedVersion
+}
+
 // IncrementVersion バージョンをインクリメント（楽観的ロック用）
 func (c *Currency) IncrementVersion() {
 	c.version++
@@ -11,4 +11,14 @@ var (
 	ErrDuplicateTransaction = errors.New("duplicate transaction")
 	// ErrCurrencyNotFound 通貨が見つからないエラー
 	ErrCurrencyNotFound = errors.New("currency not found")
+	// ErrInvalidExpiry 無効な有効期限エラー
+	ErrInvalidExpiry = errors.New("invalid expiry")
+	// ErrCurrencyNotTransferable 譲渡できない通貨タイプエラー
+	ErrCurrencyNotTransferable = errors.New("currency type is not transferable")
+	// ErrSelfTransfer 自分自身への譲渡エラー
+	ErrSelfTransfer = errors.New("cannot transfer to self")
+	// ErrRecipientNotFound 譲渡先のユーザーが存在しないエラー
+	ErrRecipientNotFound = errors.New("recipient not found")
+	// ErrExpirySweepInProgress 有効期限切れロットの一括失効が他のインスタンスで実行中のエラー
+	ErrExpirySweepInProgress = errors.New("lot expiry sweep is already running")
 )
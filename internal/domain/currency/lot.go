@@ -0,0 +1,156 @@
+package currency
+
+import (
+	"errors"
+	"regexp"
+	"time"
+)
+
+var (
+	// ErrInvalidLotID ロットIDが無効
+	ErrInvalidLotID = errors.New("invalid lot id")
+)
+
+var lotIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-\.\@]{1,255}$`)
+
+// Lot 有効期限付き通貨のロットエンティティ
+// 有効期限付きで付与された通貨を付与単位で管理する。残高（currency_balances）はロットの残量を含む合計値
+type Lot struct {
+	lotID        string
+	userID       string
+	currencyType CurrencyType
+	amount       int64 // 付与時の数量
+	remaining    int64 // 未消費・未失効の数量
+	expiresAt    time.Time
+}
+
+// NewLot 新しいLotエンティティを作成
+func NewLot(lotID string, userID string, currencyType CurrencyType, amount int64, remaining int64, expiresAt time.Time) (*Lot, error) {
+	if !lotIDRegex.MatchString(lotID) {
+		return nil, ErrInvalidLotID
+	}
+	if !userIDRegex.MatchString(userID) {
+		return nil, ErrInvalidUserID
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if amount > MaxAmount {
+		return nil, ErrAmountTooLarge
+	}
+	if remaining < 0 || remaining > amount {
+		return nil, ErrInvalidAmount
+	}
+	if expiresAt.IsZero() {
+		return nil, ErrInvalidExpiry
+	}
+	return &Lot{
+		lotID:        lotID,
+		userID:       userID,
+		currencyType: currencyType,
+		amount:       amount,
+		remaining:    remaining,
+		expiresAt:    expiresAt,
+	}, nil
+}
+
+// LotID ロットIDを返す（付与トランザクションID、譲渡・返金で作成したロットはそのトランザクションIDに連番を付与したもの）
+func (l *Lot) LotID() string {
+	return l.lotID
+}
+
+// UserID ユーザーIDを返す
+func (l *Lot) UserID() string {
+	return l.userID
+}
+
+// CurrencyType 通貨タイプを返す
+func (l *Lot) CurrencyType() CurrencyType {
+	return l.currencyType
+}
+
+// Amount 付与時の数量を返す
+func (l *Lot) Amount() int64 {
+	return l.amount
+}
+
+// Remaining 残量を返す
+func (l *Lot) Remaining() int64 {
+	return l.remaining
+}
+
+// ExpiresAt 有効期限を返す
+func (l *Lot) ExpiresAt() time.Time {
+	return l.expiresAt
+}
+
+// IsExpired 指定時刻時点で失効しているかどうかを返す
+func (l *Lot) IsExpired(now time.Time) bool {
+	return !now.Before(l.expiresAt)
+}
+
+// Consume ロットから最大amountまで消費し、実際に消費した数量を返す
+func (l *Lot) Consume(amount int64) int64 {
+	if amount <= 0 {
+		return 0
+	}
+	consumed := amount
+	if consumed > l.remaining {
+		consumed = l.remaining
+	}
+	l.remaining -= consumed
+	return consumed
+}
+
+// Expire ロットを失効させ、失効した数量を返す
+func (l *Lot) Expire() int64 {
+	expired := l.remaining
+	l.remaining = 0
+	return expired
+}
+
+// LotConsumption ロットから消費した数量の内訳
+// 返金時に消費元のロットと同じ有効期限で残高を戻すために記録する
+type LotConsumption struct {
+	LotID     string
+	Amount    int64
+	ExpiresAt time.Time
+}
+
+// AllocateLots 有効期限の近いロットから順にamountを割り当てて消費し、変更したロットとロットごとの消費内訳を返す
+// lotsは有効期限の昇順で渡すこと。ロットで賄えない分は期限なしの残高から消費される想定。
+// now時点で失効しているロット（失効処理前のもの）からは消費しない
+func AllocateLots(lots []*Lot, amount int64, now time.Time) ([]*Lot, []LotConsumption) {
+	var touched []*Lot
+	var consumptions []LotConsumption
+	for _, lot := range lots {
+		if amount <= 0 {
+			break
+		}
+		if lot.IsExpired(now) {
+			continue
+		}
+		if consumed := lot.Consume(amount); consumed > 0 {
+			amount -= consumed
+			touched = append(touched, lot)
+			consumptions = append(consumptions, LotConsumption{
+				LotID:     lot.LotID(),
+				Amount:    consumed,
+				ExpiresAt: lot.ExpiresAt(),
+			})
+		}
+	}
+	return touched, consumptions
+}
+
+// ExpiredRemaining now時点で失効しているロット（失効処理前のもの）の残量合計を返す
+// 失効処理で残高から差し引かれる数量のため、消費可能な残高には含めない
+func ExpiredRemaining(lots []*Lot, now time.Time) int64 {
+	var total int64
+	for _, lot := range lots {
+		if lot.IsExpired(now) {
+			total += lot.Remaining()
+		}
+	}
+	return total
+}
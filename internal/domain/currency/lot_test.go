@@ -0,0 +1,205 @@
+package currency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLot(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		lotID     string
+		userID    string
+		amount    int64
+		remaining int64
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{
+			name:      "正常系: ロットの作成",
+			lotID:     "txn_123",
+			userID:    "user123",
+			amount:    100,
+			remaining: 100,
+			expiresAt: expiresAt,
+		},
+		{
+			name:      "異常系: 無効なロットID",
+			lotID:     "",
+			userID:    "user123",
+			amount:    100,
+			remaining: 100,
+			expiresAt: expiresAt,
+			wantErr:   ErrInvalidLotID,
+		},
+		{
+			name:      "異常系: 無効なユーザーID",
+			lotID:     "txn_123",
+			userID:    "user 123",
+			amount:    100,
+			remaining: 100,
+			expiresAt: expiresAt,
+			wantErr:   ErrInvalidUserID,
+		},
+		{
+			name:      "異常系: 数量が0",
+			lotID:     "txn_123",
+			userID:    "user123",
+			amount:    0,
+			remaining: 0,
+			expiresAt: expiresAt,
+			wantErr:   ErrInvalidAmount,
+		},
+		{
+			name:      "異常系: 残量が数量を超える",
+			lotID:     "txn_123",
+			userID:    "user123",
+			amount:    100,
+			remaining: 101,
+			expiresAt: expiresAt,
+			wantErr:   ErrInvalidAmount,
+		},
+		{
+			name:      "異常系: 有効期限が未設定",
+			lotID:     "txn_123",
+			userID:    "user123",
+			amount:    100,
+			remaining: 100,
+			wantErr:   ErrInvalidExpiry,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lot, err := NewLot(tt.lotID, tt.userID, CurrencyTypeFree, tt.amount, tt.remaining, tt.expiresAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, lot)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.lotID, lot.LotID())
+			assert.Equal(t, tt.userID, lot.UserID())
+			assert.Equal(t, CurrencyTypeFree, lot.CurrencyType())
+			assert.Equal(t, tt.amount, lot.Amount())
+			assert.Equal(t, tt.remaining, lot.Remaining())
+			assert.Equal(t, tt.expiresAt, lot.ExpiresAt())
+		})
+	}
+}
+
+func TestLot_IsExpired(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	lot, err := NewLot("txn_123", "user123", CurrencyTypeFree, 100, 100, expiresAt)
+	require.NoError(t, err)
+
+	assert.False(t, lot.IsExpired(expiresAt.Add(-time.Second)))
+	assert.True(t, lot.IsExpired(expiresAt))
+	assert.True(t, lot.IsExpired(expiresAt.Add(time.Second)))
+}
+
+func TestLot_ConsumeAndExpire(t *testing.T) {
+	lot, err := NewLot("txn_123", "user123", CurrencyTypeFree, 100, 100, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(0), lot.Consume(0))
+	assert.Equal(t, int64(30), lot.Consume(30))
+	assert.Equal(t, int64(70), lot.Remaining())
+	assert.Equal(t, int64(70), lot.Consume(100))
+	assert.Equal(t, int64(0), lot.Remaining())
+
+	lot2, err := NewLot("txn_456", "user123", CurrencyTypeFree, 100, 40, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(40), lot2.Expire())
+	assert.Equal(t, int64(0), lot2.Remaining())
+	assert.Equal(t, int64(0), lot2.Expire())
+}
+
+func TestAllocateLots(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	newLots := func() []*Lot {
+		// lot_0はnow時点で失効している（失効処理前）
+		lot0, _ := NewLot("lot_0", "user123", CurrencyTypeFree, 100, 30, now)
+		lot1, _ := NewLot("lot_1", "user123", CurrencyTypeFree, 100, 50, now.Add(time.Hour))
+		lot2, _ := NewLot("lot_2", "user123", CurrencyTypeFree, 100, 100, now.Add(2*time.Hour))
+		return []*Lot{lot0, lot1, lot2}
+	}
+
+	tests := []struct {
+		name             string
+		amount           int64
+		wantTouched      []string
+		wantConsumptions []LotConsumption
+		wantRemaining    []int64
+	}{
+		{
+			name:        "正常系: 最初のロットのみで賄える",
+			amount:      30,
+			wantTouched: []string{"lot_1"},
+			wantConsumptions: []LotConsumption{
+				{LotID: "lot_1", Amount: 30, ExpiresAt: now.Add(time.Hour)},
+			},
+			wantRemaining: []int64{30, 20, 100},
+		},
+		{
+			name:        "正常系: 有効期限の近い順に複数ロットから消費",
+			amount:      80,
+			wantTouched: []string{"lot_1", "lot_2"},
+			wantConsumptions: []LotConsumption{
+				{LotID: "lot_1", Amount: 50, ExpiresAt: now.Add(time.Hour)},
+				{LotID: "lot_2", Amount: 30, ExpiresAt: now.Add(2 * time.Hour)},
+			},
+			wantRemaining: []int64{30, 0, 70},
+		},
+		{
+			name:        "正常系: ロットの合計を超える分は割り当てない",
+			amount:      500,
+			wantTouched: []string{"lot_1", "lot_2"},
+			wantConsumptions: []LotConsumption{
+				{LotID: "lot_1", Amount: 50, ExpiresAt: now.Add(time.Hour)},
+				{LotID: "lot_2", Amount: 100, ExpiresAt: now.Add(2 * time.Hour)},
+			},
+			wantRemaining: []int64{30, 0, 0},
+		},
+		{
+			name:             "正常系: 数量0では何も消費しない",
+			amount:           0,
+			wantTouched:      nil,
+			wantConsumptions: nil,
+			wantRemaining:    []int64{30, 50, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lots := newLots()
+			touched, consumptions := AllocateLots(lots, tt.amount, now)
+
+			var touchedIDs []string
+			for _, lot := range touched {
+				touchedIDs = append(touchedIDs, lot.LotID())
+			}
+			assert.Equal(t, tt.wantTouched, touchedIDs)
+			assert.Equal(t, tt.wantConsumptions, consumptions)
+			for i, lot := range lots {
+				assert.Equal(t, tt.wantRemaining[i], lot.Remaining())
+			}
+		})
+	}
+}
+
+func TestExpiredRemaining(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	expired1, _ := NewLot("lot_1", "user123", CurrencyTypeFree, 100, 30, now.Add(-time.Hour))
+	expired2, _ := NewLot("lot_2", "user123", CurrencyTypeFree, 100, 20, now)
+	active, _ := NewLot("lot_3", "user123", CurrencyTypeFree, 100, 100, now.Add(time.Hour))
+
+	assert.Equal(t, int64(50), ExpiredRemaining([]*Lot{expired1, expired2, active}, now))
+	assert.Equal(t, int64(0), ExpiredRemaining([]*Lot{active}, now))
+	assert.Equal(t, int64(0), ExpiredRemaining(nil, now))
+}
@@ -2,6 +2,7 @@ package currency
 
 import (
 	"context"
+	"time"
 )
 
 // CurrencyRepository 通貨リポジトリインターフェース
@@ -15,3 +16,24 @@ type CurrencyRepository interface {
 	// Create 新しい通貨を作成
 	Create(ctx context.Context, currency *Currency) error
 }
+
+// LotRepository 有効期限付き通貨ロットのリポジトリインターフェース
+type LotRepository interface {
+	// Create 新しいロットを作成
+	Create(ctx context.Context, lot *Lot) error
+
+	// Save ロットの残量を更新
+	Save(ctx context.Context, lot *Lot) error
+
+	// FindAvailableByUserIDAndType 残量のあるロットを有効期限の昇順で取得（失効処理前のロットを含む）
+	FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType CurrencyType) ([]*Lot, error)
+
+	// FindExpired 指定時刻時点で失効済みかつ残量のあるロットを有効期限の昇順で取得
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*Lot, error)
+
+	// SaveConsumptions トランザクションでロットから消費した内訳を記録
+	SaveConsumptions(ctx context.Context, transactionID string, consumptions []LotConsumption) error
+
+	// FindConsumptionsByTransactionID トランザクションでロットから消費した内訳を取得
+	FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]LotConsumption, error)
+}
@@ -0,0 +1,41 @@
+package currency
+
+// TransferPolicy ユーザー間で譲渡できる通貨タイプのルール
+// 有償通貨は通常譲渡不可のため、許可する通貨タイプを明示的に指定する
+type TransferPolicy struct {
+	transferable map[CurrencyType]struct{}
+}
+
+// NewTransferPolicy 指定した通貨タイプのみ譲渡を許可するTransferPolicyを作成
+func NewTransferPolicy(transferableTypes ...CurrencyType) *TransferPolicy {
+	transferable := make(map[CurrencyType]struct{}, len(transferableTypes))
+	for _, ct := range transferableTypes {
+		transferable[ct] = struct{}{}
+	}
+	return &TransferPolicy{
+		transferable: transferable,
+	}
+}
+
+// NewTransferPolicyFromStrings 文字列で指定した通貨タイプからTransferPolicyを作成
+func NewTransferPolicyFromStrings(transferableTypes []string) (*TransferPolicy, error) {
+	types := make([]CurrencyType, 0, len(transferableTypes))
+	for _, s := range transferableTypes {
+		ct, err := NewCurrencyType(s)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, ct)
+	}
+	return NewTransferPolicy(types...), nil
+}
+
+// CanTransfer 指定した通貨タイプが譲渡可能かどうかを返す
+// ポリシーが設定されていない（nil）場合はすべての通貨タイプを譲渡不可とする
+func (p *TransferPolicy) CanTransfer(currencyType CurrencyType) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.transferable[currencyType]
+	return ok
+}
@@ -0,0 +1,63 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferPolicy_CanTransfer(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       *TransferPolicy
+		currencyType CurrencyType
+		want         bool
+	}{
+		{
+			name:         "正常系: 許可された通貨タイプ",
+			policy:       NewTransferPolicy(CurrencyTypeFree),
+			currencyType: CurrencyTypeFree,
+			want:         true,
+		},
+		{
+			name:         "正常系: 許可されていない通貨タイプ",
+			policy:       NewTransferPolicy(CurrencyTypeFree),
+			currencyType: CurrencyTypePaid,
+			want:         false,
+		},
+		{
+			name:         "正常系: 空のポリシーはすべて不可",
+			policy:       NewTransferPolicy(),
+			currencyType: CurrencyTypeFree,
+			want:         false,
+		},
+		{
+			name:         "正常系: nilのポリシーはすべて不可",
+			policy:       nil,
+			currencyType: CurrencyTypeFree,
+			want:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.CanTransfer(tt.currencyType))
+		})
+	}
+}
+
+func TestNewTransferPolicyFromStrings(t *testing.T) {
+	t.Run("正常系: 有効な通貨タイプ", func(t *testing.T) {
+		policy, err := NewTransferPolicyFromStrings([]string{"free", "paid"})
+		require.NoError(t, err)
+		assert.True(t, policy.CanTransfer(CurrencyTypeFree))
+		assert.True(t, policy.CanTransfer(CurrencyTypePaid))
+	})
+
+	t.Run("異常系: 無効な通貨タイプ", func(t *testing.T) {
+		policy, err := NewTransferPolicyFromStrings([]string{"free", "gold"})
+		assert.Error(t, err)
+		assert.Nil(t, policy)
+	})
+}
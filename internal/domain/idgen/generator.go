@@ -0,0 +1,36 @@
+package idgen
+
+import (
+	"github.com/google/uuid"
+)
+
+// Generator 一意なIDの生成インターフェース
+// 複数レプリカから同時に生成しても衝突せず、生成順にソート可能なIDを返す
+type Generator interface {
+	// NewID 新しいIDを生成
+	NewID() string
+}
+
+// GeneratorFunc 関数をGeneratorとして扱うためのアダプター
+type GeneratorFunc func() string
+
+// NewID 新しいIDを生成
+func (f GeneratorFunc) NewID() string {
+	return f()
+}
+
+// UUIDv7Generator UUIDv7（RFC 9562）によるID生成
+// 先頭48ビットがミリ秒単位のUnix時刻、同一ミリ秒内はシーケンスで単調増加するため、
+// 文字列として比較しても生成順に並ぶ
+type UUIDv7Generator struct{}
+
+// NewUUIDv7Generator 新しいUUIDv7Generatorを作成
+func NewUUIDv7Generator() *UUIDv7Generator {
+	return &UUIDv7Generator{}
+}
+
+// NewID 新しいUUIDv7を生成
+// 乱数の読み取りに失敗した場合はpanicする（uuid.Newと同じ扱い）
+func (g *UUIDv7Generator) NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
@@ -0,0 +1,74 @@
+package idgen
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidV7Regex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUUIDv7Generator_NewID(t *testing.T) {
+	g := NewUUIDv7Generator()
+
+	id := g.NewID()
+
+	assert.Regexp(t, uuidV7Regex, id)
+}
+
+func TestUUIDv7Generator_NewID_Sortable(t *testing.T) {
+	g := NewUUIDv7Generator()
+
+	ids := make([]string, 10000)
+	for i := range ids {
+		ids[i] = g.NewID()
+	}
+
+	assert.True(t, sort.StringsAreSorted(ids), "IDs must sort in generation order")
+}
+
+func TestUUIDv7Generator_NewID_Concurrent(t *testing.T) {
+	const (
+		goroutines = 50
+		perWorker  = 2000
+	)
+
+	g := NewUUIDv7Generator()
+
+	var wg sync.WaitGroup
+	results := make([][]string, goroutines)
+	for w := 0; w < goroutines; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			ids := make([]string, perWorker)
+			for i := range ids {
+				ids[i] = g.NewID()
+			}
+			results[w] = ids
+		}(w)
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{}, goroutines*perWorker)
+	for _, ids := range results {
+		// 各ゴルーチン内では生成順に並ぶ
+		require.True(t, sort.StringsAreSorted(ids))
+		for _, id := range ids {
+			_, dup := seen[id]
+			require.False(t, dup, "duplicate id: %s", id)
+			seen[id] = struct{}{}
+		}
+	}
+	assert.Len(t, seen, goroutines*perWorker)
+}
+
+func TestGeneratorFunc_NewID(t *testing.T) {
+	g := GeneratorFunc(func() string { return "fixed" })
+
+	assert.Equal(t, "fixed", g.NewID())
+}
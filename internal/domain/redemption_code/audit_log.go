@@ -0,0 +1,75 @@
+package redemption_code
+
+import "time"
+
+// AuditAction 引き換えコードの変更履歴の操作種別
+type AuditAction string
+
+const (
+	AuditActionUpdate AuditAction = "update" // 管理APIからの変更
+	AuditActionExpire AuditAction = "expire" // 有効期限切れによる失効
+)
+
+// String 文字列表現を返す
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+// CodeAuditLog 引き換えコードの変更履歴エンティティ
+type CodeAuditLog struct {
+	auditID   string
+	code      string
+	action    AuditAction
+	changes   []FieldChange
+	reason    string
+	requester string // 変更を行ったオペレーターやツール名
+	createdAt time.Time
+}
+
+// NewCodeAuditLog 新しいCodeAuditLogエンティティを作成
+func NewCodeAuditLog(auditID, code string, action AuditAction, changes []FieldChange, reason, requester string) *CodeAuditLog {
+	return &CodeAuditLog{
+		auditID:   auditID,
+		code:      code,
+		action:    action,
+		changes:   append([]FieldChange(nil), changes...),
+		reason:    reason,
+		requester: requester,
+		createdAt: time.Now(),
+	}
+}
+
+// AuditID 変更履歴IDを返す
+func (l *CodeAuditLog) AuditID() string {
+	return l.auditID
+}
+
+// Code コードを返す
+func (l *CodeAuditLog) Code() string {
+	return l.code
+}
+
+// Action 操作種別を返す
+func (l *CodeAuditLog) Action() AuditAction {
+	return l.action
+}
+
+// Changes 変更されたフィールドの一覧を返す
+func (l *CodeAuditLog) Changes() []FieldChange {
+	return append([]FieldChange(nil), l.changes...)
+}
+
+// Reason 変更理由を返す
+func (l *CodeAuditLog) Reason() string {
+	return l.reason
+}
+
+// Requester 変更を行ったリクエスト元を返す
+func (l *CodeAuditLog) Requester() string {
+	return l.requester
+}
+
+// CreatedAt 作成日時を返す
+func (l *CodeAuditLog) CreatedAt() time.Time {
+	return l.createdAt
+}
@@ -0,0 +1,128 @@
+package redemption_code
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// DefaultCodeAlphabet 読み間違えやすい文字（0/O、1/I/L）を除いた英大文字と数字
+const DefaultCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+const (
+	// MinCodePatternLength ランダム部分の最小文字数
+	MinCodePatternLength = 4
+	// MaxCodePatternLength ランダム部分の最大文字数
+	MaxCodePatternLength = 64
+	// MaxCodePrefixLength プレフィックスの最大文字数
+	MaxCodePrefixLength = 64
+
+	// minCodeSpaceRatio 生成する件数に対して必要な組み合わせ数の倍率
+	// 総当たりで有効なコードに当たる確率を1/minCodeSpaceRatio以下に抑える
+	minCodeSpaceRatio = 1e6
+)
+
+// CodePattern ランダムな引き換えコードの生成パターン
+// 生成されるコードは Prefix + Alphabetから選んだLength文字
+type CodePattern struct {
+	prefix   string
+	length   int
+	alphabet string
+}
+
+// NewCodePattern 新しいCodePatternを作成
+// alphabetが空の場合はDefaultCodeAlphabetを使用する。コードは大文字と小文字を区別せずに照合される
+// （redemption_codes.codeの照合順序）ため、大文字と小文字が混在するalphabetは重複するコードを生成し得るので拒否する
+func NewCodePattern(prefix string, length int, alphabet string) (CodePattern, error) {
+	if alphabet == "" {
+		alphabet = DefaultCodeAlphabet
+	}
+
+	if len(prefix) > MaxCodePrefixLength {
+		return CodePattern{}, fmt.Errorf("%w: prefix must be at most %d characters", ErrInvalidCodePattern, MaxCodePrefixLength)
+	}
+	for _, c := range prefix {
+		if !isCodeChar(c) && c != '-' && c != '_' {
+			return CodePattern{}, fmt.Errorf("%w: prefix must contain only letters, digits, '-' and '_'", ErrInvalidCodePattern)
+		}
+	}
+
+	if length < MinCodePatternLength || length > MaxCodePatternLength {
+		return CodePattern{}, fmt.Errorf("%w: length must be between %d and %d", ErrInvalidCodePattern, MinCodePatternLength, MaxCodePatternLength)
+	}
+
+	seen := make(map[rune]bool, len(alphabet))
+	var hasUpper, hasLower bool
+	for _, c := range alphabet {
+		if !isCodeChar(c) {
+			return CodePattern{}, fmt.Errorf("%w: alphabet must contain only letters and digits", ErrInvalidCodePattern)
+		}
+		if seen[c] {
+			return CodePattern{}, fmt.Errorf("%w: alphabet contains duplicate character %q", ErrInvalidCodePattern, c)
+		}
+		seen[c] = true
+		hasUpper = hasUpper || (c >= 'A' && c <= 'Z')
+		hasLower = hasLower || (c >= 'a' && c <= 'z')
+	}
+	if hasUpper && hasLower {
+		return CodePattern{}, fmt.Errorf("%w: alphabet must not mix upper and lower case letters because codes are case-insensitive", ErrInvalidCodePattern)
+	}
+	if len(alphabet) < 2 {
+		return CodePattern{}, fmt.Errorf("%w: alphabet must contain at least 2 characters", ErrInvalidCodePattern)
+	}
+
+	return CodePattern{
+		prefix:   prefix,
+		length:   length,
+		alphabet: alphabet,
+	}, nil
+}
+
+// Prefix プレフィックスを返す
+func (p CodePattern) Prefix() string {
+	return p.prefix
+}
+
+// Length ランダム部分の文字数を返す
+func (p CodePattern) Length() int {
+	return p.length
+}
+
+// Alphabet ランダム部分に使用する文字を返す
+func (p CodePattern) Alphabet() string {
+	return p.alphabet
+}
+
+// Combinations 生成できるコードの組み合わせ数を返す
+func (p CodePattern) Combinations() float64 {
+	return math.Pow(float64(len(p.alphabet)), float64(p.length))
+}
+
+// ValidateCount count件のコードを生成するのに十分な組み合わせ数があるかチェック
+// 組み合わせ数が少ないとコードを推測されやすくなるため、件数の一定倍以上を要求する
+func (p CodePattern) ValidateCount(count int) error {
+	if p.Combinations() < float64(count)*minCodeSpaceRatio {
+		return fmt.Errorf("%w: length %d is too short to generate %d unguessable codes", ErrInvalidCodePattern, p.length, count)
+	}
+	return nil
+}
+
+// Generate パターンに従ってランダムなコードを1件生成する
+func (p CodePattern) Generate() (string, error) {
+	max := big.NewInt(int64(len(p.alphabet)))
+	b := make([]byte, p.length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random code: %w", err)
+		}
+		b[i] = p.alphabet[n.Int64()]
+	}
+	return p.prefix + string(b), nil
+}
+
+// isCodeChar コードに使用できる英数字かどうか
+func isCodeChar(c rune) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
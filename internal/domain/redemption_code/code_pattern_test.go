@@ -0,0 +1,173 @@
+package redemption_code
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCodePattern(t *testing.T) {
+	tests := []struct {
+		name         string
+		prefix       string
+		length       int
+		alphabet     string
+		wantAlphabet string
+		wantErr      bool
+	}{
+		{
+			name:         "正常系: デフォルトの文字種",
+			prefix:       "INF-",
+			length:       10,
+			wantAlphabet: DefaultCodeAlphabet,
+		},
+		{
+			name:         "正常系: 文字種を指定",
+			prefix:       "",
+			length:       8,
+			alphabet:     "ABCD",
+			wantAlphabet: "ABCD",
+		},
+		{
+			name:         "正常系: 小文字のみの文字種",
+			length:       8,
+			alphabet:     "abcd1234",
+			wantAlphabet: "abcd1234",
+		},
+		{
+			name:    "異常系: 短すぎる",
+			length:  MinCodePatternLength - 1,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 長すぎる",
+			length:  MaxCodePatternLength + 1,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: プレフィックスに使用できない文字",
+			prefix:  "INF 2024",
+			length:  10,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: プレフィックスが長すぎる",
+			prefix:  strings.Repeat("A", MaxCodePrefixLength+1),
+			length:  10,
+			wantErr: true,
+		},
+		{
+			name:     "異常系: 文字種が1文字",
+			length:   10,
+			alphabet: "A",
+			wantErr:  true,
+		},
+		{
+			name:     "異常系: 文字種に重複",
+			length:   10,
+			alphabet: "ABCA",
+			wantErr:  true,
+		},
+		{
+			name:     "異常系: 文字種に大文字と小文字が混在",
+			length:   10,
+			alphabet: "ABCDabcd",
+			wantErr:  true,
+		},
+		{
+			name:     "異常系: 大文字と小文字だけが異なる文字",
+			length:   10,
+			alphabet: "ABCa",
+			wantErr:  true,
+		},
+		{
+			name:     "異常系: 文字種に記号",
+			length:   10,
+			alphabet: "AB-C",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCodePattern(tt.prefix, tt.length, tt.alphabet)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCodePattern)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.prefix, got.Prefix())
+			assert.Equal(t, tt.length, got.Length())
+			assert.Equal(t, tt.wantAlphabet, got.Alphabet())
+		})
+	}
+}
+
+func TestDefaultCodeAlphabet(t *testing.T) {
+	for _, c := range "0O1IL" {
+		assert.NotContains(t, DefaultCodeAlphabet, string(c))
+	}
+}
+
+func TestCodePattern_Generate(t *testing.T) {
+	pattern, err := NewCodePattern("INF-", 12, "")
+	require.NoError(t, err)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := pattern.Generate()
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(code, "INF-"))
+		require.Len(t, code, len("INF-")+12)
+		for _, c := range strings.TrimPrefix(code, "INF-") {
+			require.Contains(t, DefaultCodeAlphabet, string(c))
+		}
+		seen[code] = true
+	}
+	assert.Len(t, seen, 100)
+}
+
+func TestCodePattern_ValidateCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   int
+		alphabet string
+		count    int
+		wantErr  bool
+	}{
+		{
+			name:   "正常系: 十分な組み合わせ数",
+			length: 10,
+			count:  10000,
+		},
+		{
+			name:     "異常系: 組み合わせ数が少ない",
+			length:   6,
+			alphabet: "ABCD",
+			count:    1,
+			wantErr:  true,
+		},
+		{
+			name:    "異常系: 件数に対して短すぎる",
+			length:  6,
+			count:   10000,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := NewCodePattern("", tt.length, tt.alphabet)
+			require.NoError(t, err)
+
+			err = pattern.ValidateCount(tt.count)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCodePattern)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package redemption_code
+
+import (
+	"time"
+
+	"gem-server/internal/domain/currency"
+)
+
+// CodeStats 引き換えコードの利用統計
+type CodeStats struct {
+	// Redemptions 引き換え回数
+	Redemptions int
+	// UniqueUsers 引き換えたユーザー数
+	UniqueUsers int
+	// Granted 通貨タイプごとの付与した通貨の合計（引き換えで記録したトランザクションの金額の合計、通貨タイプの昇順）
+	Granted []GrantedTotal
+	// Daily 日ごとの引き換え回数（日付の昇順、引き換えのない日は含まない）
+	Daily []DailyRedemptions
+}
+
+// GrantedTotal 1通貨タイプ分の付与した通貨の合計
+type GrantedTotal struct {
+	CurrencyType currency.CurrencyType
+	Amount       int64
+}
+
+// DailyRedemptions 1日分の引き換え回数
+type DailyRedemptions struct {
+	Date  time.Time
+	Count int
+}
@@ -0,0 +1,92 @@
+package redemption_code
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CodeUpdate 引き換えコードの変更内容（nilのフィールドは変更しない）
+type CodeUpdate struct {
+	Status     *CodeStatus
+	ValidUntil *time.Time
+	MaxUses    *int // 0 = 無制限
+}
+
+// IsEmpty 変更するフィールドが指定されていないかどうか
+func (u CodeUpdate) IsEmpty() bool {
+	return u.Status == nil && u.ValidUntil == nil && u.MaxUses == nil
+}
+
+// FieldChange 変更されたフィールドの変更前後の値
+type FieldChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// ApplyUpdate 変更内容を検証して適用し、実際に値が変わったフィールドの一覧を返す
+// valid_untilは延長のみ、max_usesは引き上げ（または無制限への変更）のみ可能で、
+// valid_untilを過ぎたコードは有効状態に戻せない。検証に失敗した場合はコードを変更しない
+func (rc *RedemptionCode) ApplyUpdate(update CodeUpdate) ([]FieldChange, error) {
+	if update.IsEmpty() {
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalidCodeUpdate)
+	}
+
+	validUntil := rc.validUntil
+	if update.ValidUntil != nil {
+		if !update.ValidUntil.After(rc.validFrom) {
+			return nil, fmt.Errorf("%w: valid_until must be after valid_from", ErrInvalidCodeUpdate)
+		}
+		if update.ValidUntil.Before(rc.validUntil) {
+			return nil, fmt.Errorf("%w: valid_until can only be extended", ErrInvalidCodeUpdate)
+		}
+		validUntil = *update.ValidUntil
+	}
+
+	maxUses := rc.maxUses
+	if update.MaxUses != nil {
+		if *update.MaxUses < 0 {
+			return nil, fmt.Errorf("%w: max_uses must be non-negative", ErrInvalidCodeUpdate)
+		}
+		if *update.MaxUses != 0 && (rc.maxUses == 0 || *update.MaxUses < rc.maxUses) {
+			return nil, fmt.Errorf("%w: max_uses can only be raised", ErrInvalidCodeUpdate)
+		}
+		maxUses = *update.MaxUses
+	}
+
+	status := rc.status
+	if update.Status != nil {
+		if !update.Status.Valid() {
+			return nil, fmt.Errorf("%w: invalid status: %s", ErrInvalidCodeUpdate, *update.Status)
+		}
+		if update.Status.IsActive() && time.Now().After(validUntil) {
+			return nil, fmt.Errorf("%w: cannot activate a code past valid_until", ErrInvalidCodeUpdate)
+		}
+		status = *update.Status
+	}
+
+	var changes []FieldChange
+	if status != rc.status {
+		changes = append(changes, FieldChange{Field: "status", From: rc.status.String(), To: status.String()})
+	}
+	if !validUntil.Equal(rc.validUntil) {
+		changes = append(changes, FieldChange{
+			Field: "valid_until",
+			From:  rc.validUntil.UTC().Format(time.RFC3339),
+			To:    validUntil.UTC().Format(time.RFC3339),
+		})
+	}
+	if maxUses != rc.maxUses {
+		changes = append(changes, FieldChange{Field: "max_uses", From: strconv.Itoa(rc.maxUses), To: strconv.Itoa(maxUses)})
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	rc.status = status
+	rc.validUntil = validUntil
+	rc.maxUses = maxUses
+	rc.updatedAt = time.Now()
+	return changes, nil
+}
@@ -0,0 +1,170 @@
+package redemption_code
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/currency"
+)
+
+func TestRedemptionCode_ApplyUpdate(t *testing.T) {
+	statusPtr := func(v CodeStatus) *CodeStatus { return &v }
+	intPtr := func(v int) *int { return &v }
+	timePtr := func(v time.Time) *time.Time { return &v }
+
+	validFrom := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	future := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		validUntil  time.Time
+		status      CodeStatus
+		maxUses     int
+		update      CodeUpdate
+		wantErr     bool
+		wantChanges []FieldChange
+	}{
+		{
+			name:       "正常系: 有効なコードを無効化",
+			validUntil: future,
+			status:     CodeStatusActive,
+			maxUses:    10,
+			update:     CodeUpdate{Status: statusPtr(CodeStatusDisabled)},
+			wantChanges: []FieldChange{
+				{Field: "status", From: "active", To: "disabled"},
+			},
+		},
+		{
+			name:       "正常系: 無効化したコードを再有効化",
+			validUntil: future,
+			status:     CodeStatusDisabled,
+			maxUses:    10,
+			update:     CodeUpdate{Status: statusPtr(CodeStatusActive)},
+			wantChanges: []FieldChange{
+				{Field: "status", From: "disabled", To: "active"},
+			},
+		},
+		{
+			name:       "正常系: 期限切れのコードを延長して再有効化",
+			validUntil: past,
+			status:     CodeStatusExpired,
+			maxUses:    10,
+			update:     CodeUpdate{Status: statusPtr(CodeStatusActive), ValidUntil: timePtr(future)},
+			wantChanges: []FieldChange{
+				{Field: "status", From: "expired", To: "active"},
+				{Field: "valid_until", From: past.UTC().Format(time.RFC3339), To: future.UTC().Format(time.RFC3339)},
+			},
+		},
+		{
+			name:       "正常系: 最大使用回数を引き上げ",
+			validUntil: future,
+			status:     CodeStatusActive,
+			maxUses:    10,
+			update:     CodeUpdate{MaxUses: intPtr(20)},
+			wantChanges: []FieldChange{
+				{Field: "max_uses", From: "10", To: "20"},
+			},
+		},
+		{
+			name:       "正常系: 最大使用回数を無制限に変更",
+			validUntil: future,
+			status:     CodeStatusActive,
+			maxUses:    10,
+			update:     CodeUpdate{MaxUses: intPtr(0)},
+			wantChanges: []FieldChange{
+				{Field: "max_uses", From: "10", To: "0"},
+			},
+		},
+		{
+			name:        "正常系: 値が変わらない",
+			validUntil:  future,
+			status:      CodeStatusActive,
+			maxUses:     10,
+			update:      CodeUpdate{Status: statusPtr(CodeStatusActive), MaxUses: intPtr(10)},
+			wantChanges: nil,
+		},
+		{
+			name:       "異常系: 変更内容なし",
+			validUntil: future,
+			status:     CodeStatusActive,
+			update:     CodeUpdate{},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系: 有効期限を過ぎたコードを再有効化",
+			validUntil: past,
+			status:     CodeStatusExpired,
+			update:     CodeUpdate{Status: statusPtr(CodeStatusActive)},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系: 有効期限を短縮",
+			validUntil: future,
+			status:     CodeStatusActive,
+			update:     CodeUpdate{ValidUntil: timePtr(future.Add(-time.Hour))},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系: 有効期限が開始日時より前",
+			validUntil: future,
+			status:     CodeStatusActive,
+			update:     CodeUpdate{ValidUntil: timePtr(validFrom.Add(-time.Hour))},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系: 最大使用回数を引き下げ",
+			validUntil: future,
+			status:     CodeStatusActive,
+			maxUses:    10,
+			update:     CodeUpdate{MaxUses: intPtr(5)},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系: 無制限のコードに上限を設定",
+			validUntil: future,
+			status:     CodeStatusActive,
+			maxUses:    0,
+			update:     CodeUpdate{MaxUses: intPtr(100)},
+			wantErr:    true,
+		},
+		{
+			name:       "異常系: 不正なステータス",
+			validUntil: future,
+			status:     CodeStatusActive,
+			update:     CodeUpdate{Status: statusPtr(CodeStatus("deleted"))},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := MustNewRedemptionCode(
+				"UPDATECODE",
+				CodeTypePromotion,
+				currency.CurrencyTypeFree,
+				100,
+				tt.maxUses,
+				validFrom,
+				tt.validUntil,
+				nil,
+			)
+			rc.SetStatus(tt.status)
+
+			changes, err := rc.ApplyUpdate(tt.update)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCodeUpdate)
+				// 検証に失敗した場合はコードを変更しない
+				assert.Equal(t, tt.status, rc.Status())
+				assert.Equal(t, tt.maxUses, rc.MaxUses())
+				assert.True(t, tt.validUntil.Equal(rc.ValidUntil()))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanges, changes)
+		})
+	}
+}
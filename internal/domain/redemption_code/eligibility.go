@@ -0,0 +1,106 @@
+package redemption_code
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Eligibility 引き換えコードを引き換えできるユーザーの条件
+// ゼロ値の場合は条件なし（すべてのユーザーが引き換え可能）
+type Eligibility struct {
+	// AllowedUserIDs 引き換えできるユーザーIDの一覧（空の場合は制限なし）
+	AllowedUserIDs []string
+	// AllowedUserIDPrefix 引き換えできるユーザーIDのプレフィックス（空の場合は制限なし）
+	// AllowedUserIDsと両方指定した場合は、どちらかに一致すれば引き換えできる
+	AllowedUserIDPrefix string
+	// NewUsersSince この日時以降に初めて登録されたユーザーのみ引き換えできる（ゼロ値の場合は制限なし）
+	NewUsersSince time.Time
+	// MaxPerUserInCampaign 同じキャンペーンのコードを1ユーザーが引き換えできる合計回数（0 = 無制限）
+	MaxPerUserInCampaign int
+	// ExclusiveInCampaign 同じキャンペーンの排他コードを既に引き換えたユーザーは引き換えできない
+	ExclusiveInCampaign bool
+}
+
+// CampaignUsage ユーザーのキャンペーン内での引き換え状況
+type CampaignUsage struct {
+	// Redemptions キャンペーン内のコードの引き換え回数
+	Redemptions int
+	// ExclusiveRedemptions キャンペーン内の排他コードの引き換え回数
+	ExclusiveRedemptions int
+}
+
+// IsZero 条件が指定されていないかどうか
+func (e Eligibility) IsZero() bool {
+	return len(e.AllowedUserIDs) == 0 &&
+		e.AllowedUserIDPrefix == "" &&
+		e.NewUsersSince.IsZero() &&
+		e.MaxPerUserInCampaign == 0 &&
+		!e.ExclusiveInCampaign
+}
+
+// RequiresFirstSeen 判定にユーザーの初回登録日時が必要かどうか
+func (e Eligibility) RequiresFirstSeen() bool {
+	return !e.NewUsersSince.IsZero()
+}
+
+// RequiresCampaignUsage 判定にキャンペーン内での引き換え状況が必要かどうか
+func (e Eligibility) RequiresCampaignUsage() bool {
+	return e.MaxPerUserInCampaign > 0 || e.ExclusiveInCampaign
+}
+
+// Validate 条件をチェック
+// キャンペーン単位の条件はcampaignIDが必要
+func (e Eligibility) Validate(campaignID string) error {
+	for _, id := range e.AllowedUserIDs {
+		if id == "" {
+			return fmt.Errorf("%w: allowed_user_ids must not contain empty user ID", ErrInvalidEligibility)
+		}
+	}
+	if e.MaxPerUserInCampaign < 0 {
+		return fmt.Errorf("%w: max_per_user_in_campaign must be non-negative", ErrInvalidEligibility)
+	}
+	if e.RequiresCampaignUsage() && campaignID == "" {
+		return fmt.Errorf("%w: campaign_id is required for campaign rules", ErrInvalidEligibility)
+	}
+	return nil
+}
+
+// CheckUser ユーザーIDの条件をチェック
+func (e Eligibility) CheckUser(userID string) error {
+	if len(e.AllowedUserIDs) == 0 && e.AllowedUserIDPrefix == "" {
+		return nil
+	}
+	if e.AllowedUserIDPrefix != "" && strings.HasPrefix(userID, e.AllowedUserIDPrefix) {
+		return nil
+	}
+	for _, id := range e.AllowedUserIDs {
+		if id == userID {
+			return nil
+		}
+	}
+	return ErrUserNotEligible
+}
+
+// CheckFirstSeen 新規ユーザー限定の条件をチェック
+// firstSeenAtがゼロ値の場合はまだ登録されていない新規ユーザーとして扱う
+func (e Eligibility) CheckFirstSeen(firstSeenAt time.Time) error {
+	if e.NewUsersSince.IsZero() || firstSeenAt.IsZero() {
+		return nil
+	}
+	if firstSeenAt.Before(e.NewUsersSince) {
+		return fmt.Errorf("%w: registered before %s", ErrNewUsersOnly, e.NewUsersSince.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// CheckCampaignUsage キャンペーン単位の条件をチェック
+func (e Eligibility) CheckCampaignUsage(usage CampaignUsage) error {
+	if e.ExclusiveInCampaign && usage.ExclusiveRedemptions > 0 {
+		return ErrCampaignExclusive
+	}
+	if e.MaxPerUserInCampaign > 0 && usage.Redemptions >= e.MaxPerUserInCampaign {
+		return fmt.Errorf("%w: at most %d redemptions per user", ErrCampaignLimitReached, e.MaxPerUserInCampaign)
+	}
+	return nil
+}
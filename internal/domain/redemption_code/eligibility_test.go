@@ -0,0 +1,181 @@
+package redemption_code
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/currency"
+)
+
+func TestEligibility_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		eligibility Eligibility
+		campaignID  string
+		wantErr     bool
+	}{
+		{
+			name:        "正常系: 条件なし",
+			eligibility: Eligibility{},
+		},
+		{
+			name:        "正常系: キャンペーン単位の条件",
+			eligibility: Eligibility{MaxPerUserInCampaign: 1, ExclusiveInCampaign: true},
+			campaignID:  "campaign_001",
+		},
+		{
+			name:        "異常系: 空のユーザーID",
+			eligibility: Eligibility{AllowedUserIDs: []string{"user1", ""}},
+			wantErr:     true,
+		},
+		{
+			name:        "異常系: 負の上限回数",
+			eligibility: Eligibility{MaxPerUserInCampaign: -1},
+			campaignID:  "campaign_001",
+			wantErr:     true,
+		},
+		{
+			name:        "異常系: キャンペーンIDなしで排他指定",
+			eligibility: Eligibility{ExclusiveInCampaign: true},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.eligibility.Validate(tt.campaignID)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEligibility)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEligibility_CheckUser(t *testing.T) {
+	tests := []struct {
+		name        string
+		eligibility Eligibility
+		userID      string
+		wantErr     bool
+	}{
+		{
+			name:        "正常系: 制限なし",
+			eligibility: Eligibility{},
+			userID:      "user1",
+		},
+		{
+			name:        "正常系: 許可リストに含まれる",
+			eligibility: Eligibility{AllowedUserIDs: []string{"user1", "user2"}},
+			userID:      "user2",
+		},
+		{
+			name:        "正常系: プレフィックスに一致",
+			eligibility: Eligibility{AllowedUserIDs: []string{"user1"}, AllowedUserIDPrefix: "beta_"},
+			userID:      "beta_user3",
+		},
+		{
+			name:        "異常系: どちらにも一致しない",
+			eligibility: Eligibility{AllowedUserIDs: []string{"user1"}, AllowedUserIDPrefix: "beta_"},
+			userID:      "user3",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.eligibility.CheckUser(tt.userID)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUserNotEligible)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEligibility_CheckFirstSeen(t *testing.T) {
+	since := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	e := Eligibility{NewUsersSince: since}
+
+	assert.NoError(t, e.CheckFirstSeen(since))
+	assert.NoError(t, e.CheckFirstSeen(since.Add(time.Hour)))
+	// 未登録のユーザーは新規ユーザーとして扱う
+	assert.NoError(t, e.CheckFirstSeen(time.Time{}))
+	assert.ErrorIs(t, e.CheckFirstSeen(since.Add(-time.Second)), ErrNewUsersOnly)
+	// 条件なしの場合は登録日時に関係なく引き換えできる
+	assert.NoError(t, Eligibility{}.CheckFirstSeen(since.Add(-time.Hour)))
+}
+
+func TestEligibility_CheckCampaignUsage(t *testing.T) {
+	tests := []struct {
+		name        string
+		eligibility Eligibility
+		usage       CampaignUsage
+		wantErrIs   error
+	}{
+		{
+			name:        "正常系: 上限未満",
+			eligibility: Eligibility{MaxPerUserInCampaign: 2},
+			usage:       CampaignUsage{Redemptions: 1},
+		},
+		{
+			name:        "異常系: 上限に到達",
+			eligibility: Eligibility{MaxPerUserInCampaign: 2},
+			usage:       CampaignUsage{Redemptions: 2},
+			wantErrIs:   ErrCampaignLimitReached,
+		},
+		{
+			name:        "正常系: 排他コードの引き換えなし",
+			eligibility: Eligibility{ExclusiveInCampaign: true},
+			usage:       CampaignUsage{Redemptions: 5},
+		},
+		{
+			name:        "異常系: 排他コードを引き換え済み",
+			eligibility: Eligibility{ExclusiveInCampaign: true, MaxPerUserInCampaign: 5},
+			usage:       CampaignUsage{Redemptions: 1, ExclusiveRedemptions: 1},
+			wantErrIs:   ErrCampaignExclusive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.eligibility.CheckCampaignUsage(tt.usage)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRedemptionCode_SetEligibility(t *testing.T) {
+	rc := MustNewRedemptionCode(
+		"CAMPAIGN1",
+		CodeTypeEvent,
+		currency.CurrencyTypeFree,
+		100,
+		0,
+		time.Now().Add(-time.Hour),
+		time.Now().Add(time.Hour),
+		nil,
+	)
+
+	allowed := []string{"user1"}
+	require.NoError(t, rc.SetEligibility("campaign_001", Eligibility{AllowedUserIDs: allowed, ExclusiveInCampaign: true}))
+	// 呼び出し元のスライスを変更してもコードの条件は変わらない
+	allowed[0] = "user2"
+	assert.Equal(t, "campaign_001", rc.CampaignID())
+	assert.Equal(t, []string{"user1"}, rc.Eligibility().AllowedUserIDs)
+	assert.True(t, rc.Eligibility().ExclusiveInCampaign)
+
+	err := rc.SetEligibility("", Eligibility{MaxPerUserInCampaign: 1})
+	assert.ErrorIs(t, err, ErrInvalidEligibility)
+	// エラー時は既存の条件を維持
+	assert.Equal(t, "campaign_001", rc.CampaignID())
+}
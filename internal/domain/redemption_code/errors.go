@@ -21,4 +21,36 @@ var (
 	ErrCodeAlreadyExists = errors.New("code already exists")
 	// ErrCodeCannotBeDeleted 引き換えコードが削除できないエラー（使用済みのため）
 	ErrCodeCannotBeDeleted = errors.New("code cannot be deleted because it has been used")
+	// ErrInvalidRewards 引き換えコードの報酬が不正なエラー
+	ErrInvalidRewards = errors.New("invalid rewards")
+	// ErrInvalidBatchSize 一括生成するコードの件数が不正なエラー
+	ErrInvalidBatchSize = errors.New("invalid batch size")
+	// ErrInvalidBatchID 一括生成するコードのバッチIDが不正なエラー
+	ErrInvalidBatchID = errors.New("invalid batch id")
+	// ErrBatchAlreadyExists 指定したバッチIDのコードが既に存在するエラー
+	ErrBatchAlreadyExists = errors.New("code batch already exists")
+	// ErrBatchNotFound 一括生成したコードのバッチが見つからないエラー
+	ErrBatchNotFound = errors.New("code batch not found")
+	// ErrInvalidCodeSettings 引き換えコードの有効期間・使用上限が不正なエラー
+	ErrInvalidCodeSettings = errors.New("invalid code settings")
+	// ErrInvalidCodePattern 引き換えコードの生成パターンが不正なエラー
+	ErrInvalidCodePattern = errors.New("invalid code pattern")
+	// ErrInvalidFilter 引き換えコード一覧の検索条件が不正なエラー
+	ErrInvalidFilter = errors.New("invalid code filter")
+	// ErrInvalidCodeUpdate 引き換えコードの変更内容が不正なエラー（許可されていない状態遷移を含む）
+	ErrInvalidCodeUpdate = errors.New("invalid code update")
+	// ErrInvalidEligibility 引き換えコードの引き換え条件が不正なエラー
+	ErrInvalidEligibility = errors.New("invalid eligibility rules")
+	// ErrUserNotEligible ユーザーが引き換え対象に含まれていないエラー
+	ErrUserNotEligible = errors.New("user is not eligible for this code")
+	// ErrNewUsersOnly 新規ユーザー限定のコードを既存ユーザーが引き換えようとしたエラー
+	ErrNewUsersOnly = errors.New("code is only for new users")
+	// ErrCampaignLimitReached キャンペーン内でのユーザーごとの引き換え上限に達しているエラー
+	ErrCampaignLimitReached = errors.New("campaign redemption limit reached")
+	// ErrCampaignExclusive 同じキャンペーンの排他コードを既に引き換え済みのエラー
+	ErrCampaignExclusive = errors.New("another exclusive code in this campaign has already been redeemed")
+	// ErrRedemptionLockedOut 引き換えの失敗が多すぎるためロックアウトされているエラー
+	ErrRedemptionLockedOut = errors.New("too many failed redemption attempts")
+	// ErrExpirySweepInProgress 期限切れコードの一括失効が他のインスタンスで実行中のエラー
+	ErrExpirySweepInProgress = errors.New("code expiry sweep is already running")
 )
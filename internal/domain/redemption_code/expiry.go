@@ -0,0 +1,10 @@
+package redemption_code
+
+import "context"
+
+// ExpiryLock 期限切れコードの一括失効を複数インスタンスで同時に実行しないためのロック
+type ExpiryLock interface {
+	// TryLock ロックの取得を試み、取得できた場合は解放する関数を返す
+	// 他のインスタンスが保持している場合は待たずにacquired=falseを返す
+	TryLock(ctx context.Context) (release func(), acquired bool, err error)
+}
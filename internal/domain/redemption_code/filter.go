@@ -0,0 +1,96 @@
+package redemption_code
+
+import (
+	"fmt"
+	"time"
+
+	"gem-server/internal/domain/currency"
+)
+
+// SortOrder 引き換えコード一覧の並び順
+type SortOrder string
+
+const (
+	SortCreatedAtDesc  SortOrder = "created_at_desc"  // 作成日時の新しい順（デフォルト）
+	SortCreatedAtAsc   SortOrder = "created_at_asc"   // 作成日時の古い順
+	SortCodeAsc        SortOrder = "code_asc"         // コードの昇順
+	SortValidUntilAsc  SortOrder = "valid_until_asc"  // 有効期限の近い順
+	SortValidUntilDesc SortOrder = "valid_until_desc" // 有効期限の遠い順
+)
+
+// NewSortOrder 新しいSortOrderを作成（空文字列の場合はSortCreatedAtDesc）
+func NewSortOrder(s string) (SortOrder, error) {
+	switch SortOrder(s) {
+	case "":
+		return SortCreatedAtDesc, nil
+	case SortCreatedAtDesc, SortCreatedAtAsc, SortCodeAsc, SortValidUntilAsc, SortValidUntilDesc:
+		return SortOrder(s), nil
+	default:
+		return "", fmt.Errorf("invalid sort order: %s", s)
+	}
+}
+
+// String 文字列表現を返す
+func (o SortOrder) String() string {
+	return string(o)
+}
+
+// Filter 引き換えコード一覧の検索条件
+// ゼロ値の項目は条件に含めない
+type Filter struct {
+	Status       CodeStatus
+	CodeType     CodeType
+	CurrencyType currency.CurrencyType // 報酬にこの通貨タイプを含むコード
+	CampaignID   string                // campaign_idまたはmetadataのcampaign_idが一致するコード
+	CodePrefix   string                // コードの前方一致
+	ValidFrom    *time.Time            // 有効期間が [ValidFrom, ValidUntil] と重なるコード
+	ValidUntil   *time.Time
+	Sort         SortOrder
+}
+
+// NewFilter 文字列で指定された検索条件からFilterを作成
+// 空文字列の項目は条件に含めない。無効な値の場合はErrInvalidFilterを返す
+func NewFilter(status, codeType, currencyType, campaignID, codePrefix, sort string, validFrom, validUntil *time.Time) (Filter, error) {
+	f := Filter{
+		CampaignID: campaignID,
+		CodePrefix: codePrefix,
+		ValidFrom:  validFrom,
+		ValidUntil: validUntil,
+	}
+
+	if status != "" {
+		cs, err := NewCodeStatus(status)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		f.Status = cs
+	}
+
+	if codeType != "" {
+		ct, err := NewCodeType(codeType)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		f.CodeType = ct
+	}
+
+	if currencyType != "" {
+		ct, err := currency.NewCurrencyType(currencyType)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		f.CurrencyType = ct
+	}
+
+	so, err := NewSortOrder(sort)
+	if err != nil {
+		return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+	}
+	f.Sort = so
+
+	if validFrom != nil && validUntil != nil && validUntil.Before(*validFrom) {
+		return Filter{}, fmt.Errorf("%w: valid_until must not be before valid_from", ErrInvalidFilter)
+	}
+
+	return f, nil
+}
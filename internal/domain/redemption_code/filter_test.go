@@ -0,0 +1,94 @@
+package redemption_code
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/currency"
+)
+
+func TestNewFilter(t *testing.T) {
+	from := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		status       string
+		codeType     string
+		currencyType string
+		campaignID   string
+		codePrefix   string
+		sort         string
+		validFrom    *time.Time
+		validUntil   *time.Time
+		want         Filter
+		wantErr      bool
+	}{
+		{
+			name: "正常系: 条件なしはデフォルトの並び順",
+			want: Filter{Sort: SortCreatedAtDesc},
+		},
+		{
+			name:         "正常系: すべての条件を指定",
+			status:       "active",
+			codeType:     "event",
+			currencyType: "paid",
+			campaignID:   "spring_2024",
+			codePrefix:   "INF-",
+			sort:         "valid_until_asc",
+			validFrom:    &from,
+			validUntil:   &until,
+			want: Filter{
+				Status:       CodeStatusActive,
+				CodeType:     CodeTypeEvent,
+				CurrencyType: currency.CurrencyTypePaid,
+				CampaignID:   "spring_2024",
+				CodePrefix:   "INF-",
+				ValidFrom:    &from,
+				ValidUntil:   &until,
+				Sort:         SortValidUntilAsc,
+			},
+		},
+		{
+			name:    "異常系: 無効なステータス",
+			status:  "deleted",
+			wantErr: true,
+		},
+		{
+			name:     "異常系: 無効なコードタイプ",
+			codeType: "coupon",
+			wantErr:  true,
+		},
+		{
+			name:         "異常系: 無効な通貨タイプ",
+			currencyType: "gold",
+			wantErr:      true,
+		},
+		{
+			name:    "異常系: 無効な並び順",
+			sort:    "amount_desc",
+			wantErr: true,
+		},
+		{
+			name:       "異常系: 期間の終了が開始より前",
+			validFrom:  &until,
+			validUntil: &from,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFilter(tt.status, tt.codeType, tt.currencyType, tt.campaignID, tt.codePrefix, tt.sort, tt.validFrom, tt.validUntil)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
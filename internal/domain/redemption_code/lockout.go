@@ -0,0 +1,43 @@
+package redemption_code
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LockoutPolicy 引き換えの総当たり対策のルール
+// Window内にMaxFailures回失敗したユーザーはDurationの間引き換えできなくなる
+type LockoutPolicy struct {
+	MaxFailures int
+	Window      time.Duration
+	Duration    time.Duration
+}
+
+// FailureTracker ユーザーごとの引き換え失敗を記録し、ロックアウトを管理する
+type FailureTracker interface {
+	// LockedUntil ロックアウトの解除日時を返す（ロックアウト中でなければゼロ値）
+	LockedUntil(ctx context.Context, userID string) (time.Time, error)
+
+	// RecordFailure 失敗を記録する。この失敗でロックアウトされた場合は解除日時を返す
+	RecordFailure(ctx context.Context, userID string) (time.Time, error)
+
+	// Clear 失敗の記録とロックアウトを解除する
+	Clear(ctx context.Context, userID string) error
+}
+
+// LockoutError ロックアウト中のエラー
+// errors.Is(err, ErrRedemptionLockedOut)で判定でき、解除日時を保持する
+type LockoutError struct {
+	Until time.Time
+}
+
+// Error エラーメッセージを返す
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrRedemptionLockedOut, e.Until.UTC().Format(time.RFC3339))
+}
+
+// Is ErrRedemptionLockedOutと一致するか判定
+func (e *LockoutError) Is(target error) bool {
+	return target == ErrRedemptionLockedOut
+}
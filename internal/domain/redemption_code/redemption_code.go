@@ -10,14 +10,18 @@ import (
 type RedemptionCode struct {
 	code         string
 	codeType     CodeType
-	currencyType currency.CurrencyType
-	amount       int64 // 整数値（小数点なし）
-	maxUses      int   // 0 = 無制限
+	currencyType currency.CurrencyType // 先頭の報酬の通貨タイプ
+	amount       int64                 // 先頭の報酬の金額（整数値、小数点なし）
+	rewards      []Reward              // 引き換え時に付与するすべての報酬
+	maxUses      int                   // 0 = 無制限
 	currentUses  int
 	validFrom    time.Time
 	validUntil   time.Time
 	status       CodeStatus
 	metadata     map[string]interface{}
+	batchID      string // 一括生成したコードのバッチID（個別に作成したコードは空）
+	campaignID   string // キャンペーンID（キャンペーン単位の引き換え条件に使用）
+	eligibility  Eligibility
 	createdAt    time.Time
 	updatedAt    time.Time
 }
@@ -40,12 +44,47 @@ func NewRedemptionCode(
 		return nil, errors.New("invalid amount")
 	}
 
+	return newRedemptionCode(code, codeType, []Reward{{currencyType: currencyType, amount: amount}}, maxUses, validFrom, validUntil, metadata), nil
+}
+
+// NewRedemptionCodeWithRewards 複数の報酬を付与するRedemptionCodeエンティティを作成
+// 報酬は1件以上で、同じ通貨タイプを重複して含めることはできない
+func NewRedemptionCodeWithRewards(
+	code string,
+	codeType CodeType,
+	rewards []Reward,
+	maxUses int,
+	validFrom time.Time,
+	validUntil time.Time,
+	metadata map[string]interface{},
+) (*RedemptionCode, error) {
+	if code == "" {
+		return nil, errors.New("invalid code")
+	}
+	if err := validateRewards(rewards); err != nil {
+		return nil, err
+	}
+
+	return newRedemptionCode(code, codeType, append([]Reward(nil), rewards...), maxUses, validFrom, validUntil, metadata), nil
+}
+
+// newRedemptionCode 検証済みの報酬からRedemptionCodeエンティティを作成
+func newRedemptionCode(
+	code string,
+	codeType CodeType,
+	rewards []Reward,
+	maxUses int,
+	validFrom time.Time,
+	validUntil time.Time,
+	metadata map[string]interface{},
+) *RedemptionCode {
 	now := time.Now()
 	return &RedemptionCode{
 		code:         code,
 		codeType:     codeType,
-		currencyType: currencyType,
-		amount:       amount,
+		currencyType: rewards[0].currencyType,
+		amount:       rewards[0].amount,
+		rewards:      rewards,
 		maxUses:      maxUses,
 		currentUses:  0,
 		validFrom:    validFrom,
@@ -54,7 +93,7 @@ func NewRedemptionCode(
 		metadata:     metadata,
 		createdAt:    now,
 		updatedAt:    now,
-	}, nil
+	}
 }
 
 // Code コードを返す
@@ -67,16 +106,29 @@ func (rc *RedemptionCode) CodeType() CodeType {
 	return rc.codeType
 }
 
-// CurrencyType 通貨タイプを返す
+// CurrencyType 先頭の報酬の通貨タイプを返す
 func (rc *RedemptionCode) CurrencyType() currency.CurrencyType {
 	return rc.currencyType
 }
 
-// Amount 金額を返す
+// Amount 先頭の報酬の金額を返す
 func (rc *RedemptionCode) Amount() int64 {
 	return rc.amount
 }
 
+// Rewards 引き換え時に付与するすべての報酬を返す
+func (rc *RedemptionCode) Rewards() []Reward {
+	if len(rc.rewards) == 0 {
+		return []Reward{{currencyType: rc.currencyType, amount: rc.amount}}
+	}
+	return append([]Reward(nil), rc.rewards...)
+}
+
+// HasMultipleRewards 複数の報酬を付与するコードかどうか
+func (rc *RedemptionCode) HasMultipleRewards() bool {
+	return len(rc.rewards) > 1
+}
+
 // MaxUses 最大使用回数を返す
 func (rc *RedemptionCode) MaxUses() int {
 	return rc.maxUses
@@ -107,6 +159,11 @@ func (rc *RedemptionCode) Metadata() map[string]interface{} {
 	return rc.metadata
 }
 
+// BatchID 一括生成したコードのバッチIDを返す（個別に作成したコードは空）
+func (rc *RedemptionCode) BatchID() string {
+	return rc.batchID
+}
+
 // CreatedAt 作成日時を返す
 func (rc *RedemptionCode) CreatedAt() time.Time {
 	return rc.createdAt
@@ -176,6 +233,33 @@ func (rc *RedemptionCode) SetStatus(status CodeStatus) {
 	rc.status = status
 }
 
+// SetBatchID 一括生成のバッチIDを設定（一括生成時とリポジトリから読み込んだ際に使用）
+func (rc *RedemptionCode) SetBatchID(batchID string) {
+	rc.batchID = batchID
+}
+
+// CampaignID キャンペーンIDを返す
+func (rc *RedemptionCode) CampaignID() string {
+	return rc.campaignID
+}
+
+// Eligibility 引き換え条件を返す
+func (rc *RedemptionCode) Eligibility() Eligibility {
+	return rc.eligibility
+}
+
+// SetEligibility キャンペーンIDと引き換え条件を設定
+// キャンペーン単位の条件を指定する場合はcampaignIDが必要
+func (rc *RedemptionCode) SetEligibility(campaignID string, eligibility Eligibility) error {
+	if err := eligibility.Validate(campaignID); err != nil {
+		return err
+	}
+	eligibility.AllowedUserIDs = append([]string(nil), eligibility.AllowedUserIDs...)
+	rc.campaignID = campaignID
+	rc.eligibility = eligibility
+	return nil
+}
+
 // MustNewRedemptionCode テスト用ヘルパー: NewRedemptionCodeを呼び出し、エラーが発生した場合はpanicする
 func MustNewRedemptionCode(
 	code string,
@@ -7,21 +7,23 @@ import (
 
 // CodeRedemption コード引き換え履歴エンティティ
 type CodeRedemption struct {
-	redemptionID  string
-	code          string
-	userID        string
-	transactionID string
-	redeemedAt    time.Time
+	redemptionID   string
+	code           string
+	userID         string
+	transactionID  string   // 先頭の報酬を付与したトランザクションID
+	transactionIDs []string // 報酬ごとに付与したトランザクションID
+	redeemedAt     time.Time
 }
 
 // NewCodeRedemption 新しいCodeRedemptionエンティティを作成
 func NewCodeRedemption(redemptionID, code, userID, transactionID string) *CodeRedemption {
 	return &CodeRedemption{
-		redemptionID:  redemptionID,
-		code:          code,
-		userID:        userID,
-		transactionID: transactionID,
-		redeemedAt:    time.Now(),
+		redemptionID:   redemptionID,
+		code:           code,
+		userID:         userID,
+		transactionID:  transactionID,
+		transactionIDs: []string{transactionID},
+		redeemedAt:     time.Now(),
 	}
 }
 
@@ -40,37 +42,100 @@ func (cr *CodeRedemption) UserID() string {
 	return cr.userID
 }
 
-// TransactionID トランザクションIDを返す
+// TransactionID 先頭の報酬を付与したトランザクションIDを返す
 func (cr *CodeRedemption) TransactionID() string {
 	return cr.transactionID
 }
 
+// TransactionIDs 報酬ごとに付与したすべてのトランザクションIDを返す
+func (cr *CodeRedemption) TransactionIDs() []string {
+	return cr.transactionIDs
+}
+
+// SetTransactionIDs 報酬ごとに付与したトランザクションIDを設定（リポジトリから読み込んだ際に使用）
+func (cr *CodeRedemption) SetTransactionIDs(transactionIDs []string) {
+	cr.transactionIDs = transactionIDs
+}
+
 // RedeemedAt 引き換え日時を返す
 func (cr *CodeRedemption) RedeemedAt() time.Time {
 	return cr.redeemedAt
 }
 
+// SetRedeemedAt 引き換え日時を設定（リポジトリから読み込んだ際に使用）
+func (cr *CodeRedemption) SetRedeemedAt(redeemedAt time.Time) {
+	cr.redeemedAt = redeemedAt
+}
+
 // RedemptionCodeRepository 引き換えコードリポジトリインターフェース
 type RedemptionCodeRepository interface {
 	// FindByCode コードで引き換えコードを取得
+	// トランザクション内で呼び出した場合は取得した行をロックする
 	FindByCode(ctx context.Context, code string) (*RedemptionCode, error)
 
 	// Update 引き換えコードを更新
 	Update(ctx context.Context, code *RedemptionCode) error
 
+	// IncrementUses 引き換えで使用回数を1増やす
+	// activeで最大使用回数に達していないコードのみ更新し、更新できない場合はErrCodeNotRedeemableを返す
+	IncrementUses(ctx context.Context, code string) error
+
+	// FindExpiredActive 指定時刻時点で有効期限を過ぎたactiveのコードを有効期限の昇順で取得
+	// トランザクション内で呼び出した場合は取得した行をロックする
+	FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*RedemptionCode, error)
+
+	// UpdateSettings 管理APIで変更できる設定（ステータス、有効期限、最大使用回数）を更新
+	// 使用回数は更新しないため、同時に行われた引き換えの結果を上書きしない。
+	// 同時の変更を上書きしないよう、同じトランザクション内でFindByCodeで行をロックしてから呼び出す
+	UpdateSettings(ctx context.Context, code *RedemptionCode) error
+
+	// SaveAuditLog 引き換えコードの変更履歴を保存
+	SaveAuditLog(ctx context.Context, log *CodeAuditLog) error
+
 	// HasUserRedeemed ユーザーが既にこのコードを引き換え済みかチェック
 	HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error)
 
+	// CountUserCampaignRedemptions ユーザーのキャンペーン内での引き換え状況を取得
+	// トランザクション内で呼び出した場合は、コミット済みの最新の引き換えを数える
+	CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (CampaignUsage, error)
+
+	// LockUser ユーザーの行をロックする（未登録の場合は登録する）
+	// トランザクション内で呼び出し、同じユーザーの引き換えを直列化する
+	LockUser(ctx context.Context, userID string) error
+
+	// FindUserFirstSeenAt ユーザーが初めて登録された日時を取得（未登録の場合はゼロ値）
+	FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error)
+
 	// SaveRedemption 引き換え履歴を保存
 	SaveRedemption(ctx context.Context, redemption *CodeRedemption) error
 
+	// FindRedemptionsByCode コードの引き換え履歴を新しい順に取得（ページネーション対応）
+	// 引き換え履歴には報酬ごとに付与したすべてのトランザクションIDを含める
+	// 戻り値: (引き換え履歴, 総件数, エラー)
+	FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*CodeRedemption, int, error)
+
+	// FindRedemptionsByUserID ユーザーの引き換え履歴を新しい順に取得（ページネーション対応）
+	// 引き換え履歴には報酬ごとに付与したすべてのトランザクションIDを含める
+	// 戻り値: (引き換え履歴, 総件数, エラー)
+	FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*CodeRedemption, int, error)
+
+	// GetCodeStats コードの引き換え回数・ユーザー数・付与した通貨の合計・日ごとの引き換え回数を集計
+	GetCodeStats(ctx context.Context, code string) (CodeStats, error)
+
 	// Create 引き換えコードを作成
 	Create(ctx context.Context, code *RedemptionCode) error
 
+	// CreateBatch 複数の引き換えコードを一括で作成
+	// いずれかのコードが既に存在する場合はErrCodeAlreadyExistsを返す
+	CreateBatch(ctx context.Context, codes []*RedemptionCode) error
+
+	// FindByBatchID バッチIDで一括生成した引き換えコードを取得（コード順）
+	FindByBatchID(ctx context.Context, batchID string) ([]*RedemptionCode, error)
+
 	// Delete 引き換えコードを削除
 	Delete(ctx context.Context, code string) error
 
-	// FindAll 引き換えコードの一覧を取得（ページネーション対応）
-	// 戻り値: (コード一覧, 総件数, エラー)
-	FindAll(ctx context.Context, limit, offset int) ([]*RedemptionCode, int, error)
+	// FindByFilter 検索条件に一致する引き換えコードの一覧をfilter.Sortの順に取得（ページネーション対応）
+	// 戻り値: (コード一覧, 検索条件に一致する総件数, エラー)
+	FindByFilter(ctx context.Context, filter Filter, limit, offset int) ([]*RedemptionCode, int, error)
 }
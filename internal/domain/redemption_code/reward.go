@@ -0,0 +1,56 @@
+package redemption_code
+
+import (
+	"fmt"
+
+	"gem-server/internal/domain/currency"
+)
+
+// Reward 引き換え時に付与する報酬（通貨タイプと金額の組）を表す値オブジェクト
+type Reward struct {
+	currencyType currency.CurrencyType
+	amount       int64 // 整数値（小数点なし）
+}
+
+// NewReward 新しいRewardを作成
+func NewReward(currencyType currency.CurrencyType, amount int64) (Reward, error) {
+	if !currencyType.Valid() {
+		return Reward{}, fmt.Errorf("%w: invalid currency type: %s", ErrInvalidRewards, currencyType)
+	}
+	if amount <= 0 {
+		return Reward{}, fmt.Errorf("%w: amount must be positive", ErrInvalidRewards)
+	}
+	return Reward{
+		currencyType: currencyType,
+		amount:       amount,
+	}, nil
+}
+
+// CurrencyType 通貨タイプを返す
+func (r Reward) CurrencyType() currency.CurrencyType {
+	return r.currencyType
+}
+
+// Amount 金額を返す
+func (r Reward) Amount() int64 {
+	return r.amount
+}
+
+// validateRewards 報酬の一覧をチェック（1件以上、通貨タイプの重複なし）
+func validateRewards(rewards []Reward) error {
+	if len(rewards) == 0 {
+		return fmt.Errorf("%w: at least one reward is required", ErrInvalidRewards)
+	}
+
+	seen := make(map[currency.CurrencyType]bool, len(rewards))
+	for _, r := range rewards {
+		if _, err := NewReward(r.currencyType, r.amount); err != nil {
+			return err
+		}
+		if seen[r.currencyType] {
+			return fmt.Errorf("%w: duplicate currency type: %s", ErrInvalidRewards, r.currencyType)
+		}
+		seen[r.currencyType] = true
+	}
+	return nil
+}
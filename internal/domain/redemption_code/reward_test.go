@@ -0,0 +1,131 @@
+package redemption_code
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/currency"
+)
+
+func TestNewReward(t *testing.T) {
+	tests := []struct {
+		name         string
+		currencyType currency.CurrencyType
+		amount       int64
+		wantErr      bool
+	}{
+		{
+			name:         "正常系: 報酬を作成",
+			currencyType: currency.CurrencyTypeFree,
+			amount:       100,
+		},
+		{
+			name:         "異常系: 無効な通貨タイプ",
+			currencyType: currency.CurrencyType("gold"),
+			amount:       100,
+			wantErr:      true,
+		},
+		{
+			name:         "異常系: 金額が0",
+			currencyType: currency.CurrencyTypePaid,
+			amount:       0,
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewReward(tt.currencyType, tt.amount)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRewards)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.currencyType, got.CurrencyType())
+			assert.Equal(t, tt.amount, got.Amount())
+		})
+	}
+}
+
+func TestNewRedemptionCodeWithRewards(t *testing.T) {
+	free, err := NewReward(currency.CurrencyTypeFree, 100)
+	require.NoError(t, err)
+	paid, err := NewReward(currency.CurrencyTypePaid, 10)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		rewards []Reward
+		wantErr bool
+	}{
+		{
+			name:    "正常系: 複数の報酬",
+			rewards: []Reward{free, paid},
+		},
+		{
+			name:    "正常系: 報酬が1件",
+			rewards: []Reward{paid},
+		},
+		{
+			name:    "異常系: 報酬なし",
+			rewards: nil,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 通貨タイプが重複",
+			rewards: []Reward{free, free},
+			wantErr: true,
+		},
+		{
+			name:    "異常系: ゼロ値の報酬",
+			rewards: []Reward{{}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewRedemptionCodeWithRewards(
+				"BUNDLE",
+				CodeTypeEvent,
+				tt.rewards,
+				1,
+				time.Now().Add(-time.Hour),
+				time.Now().Add(time.Hour),
+				nil,
+			)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidRewards)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.rewards, got.Rewards())
+			assert.Equal(t, len(tt.rewards) > 1, got.HasMultipleRewards())
+			// 先頭の報酬がCurrencyTypeとAmountになる
+			assert.Equal(t, tt.rewards[0].CurrencyType(), got.CurrencyType())
+			assert.Equal(t, tt.rewards[0].Amount(), got.Amount())
+		})
+	}
+}
+
+func TestRedemptionCode_Rewards_SingleCurrency(t *testing.T) {
+	rc := MustNewRedemptionCode(
+		"SINGLE",
+		CodeTypePromotion,
+		currency.CurrencyTypePaid,
+		1000,
+		1,
+		time.Now().Add(-time.Hour),
+		time.Now().Add(time.Hour),
+		nil,
+	)
+
+	rewards := rc.Rewards()
+	require.Len(t, rewards, 1)
+	assert.Equal(t, currency.CurrencyTypePaid, rewards[0].CurrencyType())
+	assert.Equal(t, int64(1000), rewards[0].Amount())
+	assert.False(t, rc.HasMultipleRewards())
+}
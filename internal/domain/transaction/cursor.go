@@ -0,0 +1,52 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cursor トランザクション一覧のページネーションカーソル
+// 一覧は (created_at, id) の降順で並ぶため、カーソルは前のページの最後の要素を指す
+type Cursor struct {
+	CreatedAt     time.Time
+	TransactionID string
+}
+
+// NewCursorFromTransaction トランザクションを指すカーソルを作成
+func NewCursorFromTransaction(t *Transaction) Cursor {
+	return Cursor{
+		CreatedAt:     t.CreatedAt(),
+		TransactionID: t.TransactionID(),
+	}
+}
+
+// Encode クライアントに返す不透明な文字列にエンコード
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.TransactionID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor Encodeで作成された文字列をカーソルにデコード
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	createdAt, transactionID, ok := strings.Cut(string(raw), "|")
+	if !ok || transactionID == "" {
+		return Cursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidCursor)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return Cursor{
+		CreatedAt:     t,
+		TransactionID: transactionID,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_EncodeDecode(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt:     time.Date(2025, 1, 2, 3, 4, 5, 600, time.FixedZone("JST", 9*60*60)),
+		TransactionID: "txn_0190a1b2-c3d4-7e5f-8a9b-0c1d2e3f4a5b",
+	}
+
+	got, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, cursor.TransactionID, got.TransactionID)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{
+			name:   "異常系: base64でない",
+			cursor: "not a cursor!",
+		},
+		{
+			name:   "異常系: 区切り文字がない",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("2025-01-01T00:00:00Z")),
+		},
+		{
+			name:   "異常系: トランザクションIDが空",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("2025-01-01T00:00:00Z|")),
+		},
+		{
+			name:   "異常系: 日時の形式が不正",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("yesterday|txn1")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
@@ -9,4 +9,22 @@ var (
 	ErrInvalidTransaction = errors.New("invalid transaction")
 	// ErrDuplicateTransactionID 重複トランザクションIDエラー
 	ErrDuplicateTransactionID = errors.New("duplicate transaction id")
+	// ErrTransactionNotRefundable 返金できないトランザクションエラー
+	ErrTransactionNotRefundable = errors.New("transaction not refundable")
+	// ErrTransactionAlreadyRefunded 返金済みトランザクションエラー
+	ErrTransactionAlreadyRefunded = errors.New("transaction already refunded")
+	// ErrReasonRequired 理由が指定されていないエラー
+	ErrReasonRequired = errors.New("reason is required")
+	// ErrRequesterRequired リクエスト元が指定されていないエラー
+	ErrRequesterRequired = errors.New("requester is required")
+	// ErrInvalidIdempotencyKey 冪等性キーが無効なエラー
+	ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	// ErrIdempotencyKeyConflict 同じ冪等性キーが異なるリクエスト内容で再利用されたエラー
+	ErrIdempotencyKeyConflict = errors.New("idempotency key reused with different request")
+	// ErrDuplicateIdempotencyKey 冪等性キーが記録済みのエラー（同じキーのリクエストが先に処理された）
+	ErrDuplicateIdempotencyKey = errors.New("duplicate idempotency key")
+	// ErrInvalidFilter 無効な検索条件エラー
+	ErrInvalidFilter = errors.New("invalid transaction filter")
+	// ErrInvalidCursor 無効なページネーションカーソルエラー
+	ErrInvalidCursor = errors.New("invalid cursor")
 )
@@ -0,0 +1,85 @@
+package transaction
+
+import (
+	"fmt"
+	"time"
+
+	"gem-server/internal/domain/currency"
+)
+
+// Filter トランザクション一覧の検索条件
+// ゼロ値の項目は条件に含めない（UserIDが空の場合は全ユーザーが対象）
+type Filter struct {
+	UserID          string
+	CurrencyType    currency.CurrencyType
+	TransactionType TransactionType
+	Status          TransactionStatus
+	Requester       string
+	From            *time.Time // created_at >= From
+	To              *time.Time // created_at < To
+}
+
+// NewFilter 文字列で指定された検索条件からFilterを作成
+// 空文字列の項目は条件に含めない。無効な値の場合はErrInvalidFilterを返す
+func NewFilter(userID, currencyType, transactionType, status, requester string, from, to *time.Time) (Filter, error) {
+	if userID == "" {
+		return Filter{}, fmt.Errorf("%w: user_id is required", ErrInvalidFilter)
+	}
+
+	f, err := newFilter(currencyType, transactionType, status, from, to)
+	if err != nil {
+		return Filter{}, err
+	}
+	f.UserID = userID
+	f.Requester = requester
+
+	return f, nil
+}
+
+// NewExportFilter 全ユーザーを対象とするエクスポート用のFilterを作成
+// 走査範囲を限定するため期間（from, to）は必須
+func NewExportFilter(currencyType, transactionType string, from, to *time.Time) (Filter, error) {
+	if from == nil || to == nil {
+		return Filter{}, fmt.Errorf("%w: from and to are required", ErrInvalidFilter)
+	}
+
+	return newFilter(currencyType, transactionType, "", from, to)
+}
+
+// newFilter ユーザー以外の検索条件を検証してFilterを作成
+func newFilter(currencyType, transactionType, status string, from, to *time.Time) (Filter, error) {
+	f := Filter{
+		From: from,
+		To:   to,
+	}
+
+	if currencyType != "" {
+		ct, err := currency.NewCurrencyType(currencyType)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		f.CurrencyType = ct
+	}
+
+	if transactionType != "" {
+		tt, err := NewTransactionType(transactionType)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		f.TransactionType = tt
+	}
+
+	if status != "" {
+		ts, err := NewTransactionStatus(status)
+		if err != nil {
+			return Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		f.Status = ts
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return Filter{}, fmt.Errorf("%w: from must be before to", ErrInvalidFilter)
+	}
+
+	return f, nil
+}
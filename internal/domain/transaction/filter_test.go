@@ -0,0 +1,151 @@
+package transaction
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/currency"
+)
+
+func TestNewFilter(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		userID          string
+		currencyType    string
+		transactionType string
+		status          string
+		requester       string
+		from            *time.Time
+		to              *time.Time
+		want            Filter
+		wantErr         bool
+	}{
+		{
+			name:   "正常系: user_idのみ",
+			userID: "user123",
+			want:   Filter{UserID: "user123"},
+		},
+		{
+			name:            "正常系: すべての条件を指定",
+			userID:          "user123",
+			currencyType:    "paid",
+			transactionType: "consume",
+			status:          "completed",
+			requester:       "game-server",
+			from:            &from,
+			to:              &to,
+			want: Filter{
+				UserID:          "user123",
+				CurrencyType:    currency.CurrencyTypePaid,
+				TransactionType: TransactionTypeConsume,
+				Status:          TransactionStatusCompleted,
+				Requester:       "game-server",
+				From:            &from,
+				To:              &to,
+			},
+		},
+		{
+			name:    "異常系: user_idが空",
+			wantErr: true,
+		},
+		{
+			name:         "異常系: 無効な通貨タイプ",
+			userID:       "user123",
+			currencyType: "gold",
+			wantErr:      true,
+		},
+		{
+			name:            "異常系: 無効なトランザクションタイプ",
+			userID:          "user123",
+			transactionType: "payment",
+			wantErr:         true,
+		},
+		{
+			name:    "異常系: 無効なステータス",
+			userID:  "user123",
+			status:  "done",
+			wantErr: true,
+		},
+		{
+			name:    "異常系: fromがto以降",
+			userID:  "user123",
+			from:    &to,
+			to:      &from,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewFilter(tt.userID, tt.currencyType, tt.transactionType, tt.status, tt.requester, tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewExportFilter(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		currencyType    string
+		transactionType string
+		from            *time.Time
+		to              *time.Time
+		want            Filter
+		wantErr         bool
+	}{
+		{
+			name:         "正常系: 期間と通貨タイプを指定",
+			currencyType: "paid",
+			from:         &from,
+			to:           &to,
+			want: Filter{
+				CurrencyType: currency.CurrencyTypePaid,
+				From:         &from,
+				To:           &to,
+			},
+		},
+		{
+			name:    "異常系: fromが未指定",
+			to:      &to,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: toが未指定",
+			from:    &from,
+			wantErr: true,
+		},
+		{
+			name:            "異常系: 無効なトランザクションタイプ",
+			transactionType: "payment",
+			from:            &from,
+			to:              &to,
+			wantErr:         true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExportFilter(tt.currencyType, tt.transactionType, tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
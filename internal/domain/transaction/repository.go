@@ -12,9 +12,26 @@ type TransactionRepository interface {
 	// FindByTransactionID トランザクションIDでトランザクションを取得
 	FindByTransactionID(ctx context.Context, transactionID string) (*Transaction, error)
 
-	// FindByUserID ユーザーIDでトランザクション一覧を取得（ページネーション対応）
-	FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*Transaction, error)
+	// FindByFilter 検索条件に一致するトランザクション一覧を新しい順に取得（ページネーション対応）
+	FindByFilter(ctx context.Context, filter Filter, limit, offset int) ([]*Transaction, error)
+
+	// FindByFilterAfterCursor 検索条件に一致し、カーソルより古いトランザクション一覧を新しい順に取得
+	FindByFilterAfterCursor(ctx context.Context, filter Filter, cursor Cursor, limit int) ([]*Transaction, error)
+
+	// StreamByFilter 検索条件に一致するトランザクションを古い順に1件ずつfnに渡す
+	// 結果をまとめてメモリに載せないため、大量件数のエクスポートに使用する。fnがエラーを返すと中断する
+	StreamByFilter(ctx context.Context, filter Filter, fn func(*Transaction) error) error
+
+	// CountByFilter 検索条件に一致するトランザクションの総件数を取得
+	CountByFilter(ctx context.Context, filter Filter) (int, error)
 
 	// FindByPaymentRequestID PaymentRequest IDでトランザクションを取得
 	FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*Transaction, error)
+
+	// FindByIdempotencyKey ユーザーIDと冪等性キーでトランザクション一覧を取得（作成順）
+	FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*Transaction, error)
+
+	// ReserveIdempotencyKey ユーザーIDと冪等性キーを記録（記録済みの場合はErrDuplicateIdempotencyKey）
+	// 残高の更新と同じトランザクション内で呼び出し、同じキーの同時リクエストを1件だけ処理させる
+	ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error
 }
@@ -44,6 +44,8 @@ type Transaction struct {
 	status           TransactionStatus
 	paymentRequestID *string // PaymentRequest APIのID（オプション）
 	requester        *string // リクエスト元（サービス名やユーザーIDなど）
+	idempotencyKey   *string // クライアント指定の冪等性キー（オプション）
+	requestHash      *string // 冪等性キーに紐づくリクエスト内容のハッシュ
 	metadata         map[string]interface{}
 	createdAt        time.Time
 	updatedAt        time.Time
@@ -125,6 +127,47 @@ func NewTransactionWithRequester(
 	}, nil
 }
 
+// RestoreTransaction 永続化されたTransactionエンティティを復元（保存済みの作成日時・更新日時を保持する）
+func RestoreTransaction(
+	transactionID string,
+	userID string,
+	transactionType TransactionType,
+	currencyType currency.CurrencyType,
+	amount int64,
+	balanceBefore int64,
+	balanceAfter int64,
+	status TransactionStatus,
+	paymentRequestID *string,
+	requester *string,
+	idempotencyKey *string,
+	requestHash *string,
+	metadata map[string]interface{},
+	createdAt time.Time,
+	updatedAt time.Time,
+) (*Transaction, error) {
+	t, err := NewTransactionWithRequester(
+		transactionID,
+		userID,
+		transactionType,
+		currencyType,
+		amount,
+		balanceBefore,
+		balanceAfter,
+		status,
+		requester,
+		metadata,
+	)
+	if err != nil {
+		return nil, err
+	}
+	t.paymentRequestID = paymentRequestID
+	t.idempotencyKey = idempotencyKey
+	t.requestHash = requestHash
+	t.createdAt = createdAt
+	t.updatedAt = updatedAt
+	return t, nil
+}
+
 // TransactionID トランザクションIDを返す
 func (t *Transaction) TransactionID() string {
 	return t.transactionID
@@ -202,6 +245,23 @@ func (t *Transaction) SetRequester(requester string) {
 	t.updatedAt = time.Now()
 }
 
+// IdempotencyKey 冪等性キーを返す
+func (t *Transaction) IdempotencyKey() *string {
+	return t.idempotencyKey
+}
+
+// RequestHash 冪等性キーに紐づくリクエスト内容のハッシュを返す
+func (t *Transaction) RequestHash() *string {
+	return t.requestHash
+}
+
+// SetIdempotencyKey 冪等性キーとリクエスト内容のハッシュを設定
+func (t *Transaction) SetIdempotencyKey(key string, requestHash string) {
+	t.idempotencyKey = &key
+	t.requestHash = &requestHash
+	t.updatedAt = time.Now()
+}
+
 // UpdateStatus ステータスを更新
 func (t *Transaction) UpdateStatus(status TransactionStatus) error {
 	if !status.Valid() {
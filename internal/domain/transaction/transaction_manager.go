@@ -2,11 +2,12 @@ package transaction
 
 import (
 	"context"
-	"database/sql"
 )
 
 // TransactionManager トランザクション管理インターフェース
 type TransactionManager interface {
 	// WithTransaction トランザクション内で関数を実行
-	WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error
+	// fnに渡されるctxにはトランザクションが紐付けられており、
+	// リポジトリはこのctxを使うことで同一トランザクション内でクエリを実行する
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
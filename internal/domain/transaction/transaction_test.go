@@ -335,3 +335,66 @@ func TestTransaction_SetRequester(t *testing.T) {
 	assert.NotNil(t, tx.Requester())
 	assert.Equal(t, requester, *tx.Requester())
 }
+
+func TestTransaction_SetIdempotencyKey(t *testing.T) {
+	tx := mustNewTransaction(
+		"tx123",
+		"user123",
+		TransactionTypeGrant,
+		currency.CurrencyTypePaid,
+		1000,
+		0,
+		1000,
+		TransactionStatusCompleted,
+		nil,
+	)
+
+	assert.Nil(t, tx.IdempotencyKey())
+	assert.Nil(t, tx.RequestHash())
+
+	tx.SetIdempotencyKey("key-001", "hash-001")
+
+	assert.NotNil(t, tx.IdempotencyKey())
+	assert.Equal(t, "key-001", *tx.IdempotencyKey())
+	assert.NotNil(t, tx.RequestHash())
+	assert.Equal(t, "hash-001", *tx.RequestHash())
+}
+
+func TestRestoreTransaction(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Minute)
+	paymentRequestID := "pr_001"
+	key := "key-001"
+	hash := "hash-001"
+
+	tx, err := RestoreTransaction(
+		"tx123",
+		"user123",
+		TransactionTypeGrant,
+		currency.CurrencyTypePaid,
+		1000,
+		0,
+		1000,
+		TransactionStatusCompleted,
+		&paymentRequestID,
+		nil,
+		&key,
+		&hash,
+		nil,
+		createdAt,
+		updatedAt,
+	)
+	require.NoError(t, err)
+
+	// 保存された日時を保持する
+	assert.Equal(t, createdAt, tx.CreatedAt())
+	assert.Equal(t, updatedAt, tx.UpdatedAt())
+	assert.Equal(t, paymentRequestID, *tx.PaymentRequestID())
+	assert.Equal(t, key, *tx.IdempotencyKey())
+	assert.Equal(t, hash, *tx.RequestHash())
+	assert.Nil(t, tx.Requester())
+
+	_, err = RestoreTransaction("tx123", "user123", TransactionTypeGrant, currency.CurrencyTypePaid,
+		0, 0, 0, TransactionStatusCompleted, nil, nil, nil, nil, nil, createdAt, updatedAt)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+}
@@ -8,17 +8,19 @@ import (
 type TransactionType string
 
 const (
-	TransactionTypeGrant      TransactionType = "grant"      // 付与
-	TransactionTypeConsume    TransactionType = "consume"    // 消費
-	TransactionTypeRefund     TransactionType = "refund"     // 返金
-	TransactionTypeExpire     TransactionType = "expire"     // 失効
-	TransactionTypeCompensate TransactionType = "compensate" // 補填
+	TransactionTypeGrant       TransactionType = "grant"        // 付与
+	TransactionTypeConsume     TransactionType = "consume"      // 消費
+	TransactionTypeRefund      TransactionType = "refund"       // 返金
+	TransactionTypeExpire      TransactionType = "expire"       // 失効
+	TransactionTypeCompensate  TransactionType = "compensate"   // 補填
+	TransactionTypeTransferOut TransactionType = "transfer_out" // 譲渡（送信側）
+	TransactionTypeTransferIn  TransactionType = "transfer_in"  // 譲渡（受信側）
 )
 
 // NewTransactionType 新しいTransactionTypeを作成
 func NewTransactionType(s string) (TransactionType, error) {
 	switch s {
-	case "grant", "consume", "refund", "expire", "compensate":
+	case "grant", "consume", "refund", "expire", "compensate", "transfer_out", "transfer_in":
 		return TransactionType(s), nil
 	default:
 		return "", fmt.Errorf("invalid transaction type: %s", s)
@@ -33,7 +35,8 @@ func (tt TransactionType) String() string {
 // Valid 有効なトランザクションタイプかどうかを返す
 func (tt TransactionType) Valid() bool {
 	switch tt {
-	case TransactionTypeGrant, TransactionTypeConsume, TransactionTypeRefund, TransactionTypeExpire, TransactionTypeCompensate:
+	case TransactionTypeGrant, TransactionTypeConsume, TransactionTypeRefund, TransactionTypeExpire, TransactionTypeCompensate,
+		TransactionTypeTransferOut, TransactionTypeTransferIn:
 		return true
 	default:
 		return false
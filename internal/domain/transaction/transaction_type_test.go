@@ -44,6 +44,18 @@ func TestNewTransactionType(t *testing.T) {
 			want:    TransactionTypeCompensate,
 			wantErr: false,
 		},
+		{
+			name:    "正常系: transfer_out",
+			input:   "transfer_out",
+			want:    TransactionTypeTransferOut,
+			wantErr: false,
+		},
+		{
+			name:    "正常系: transfer_in",
+			input:   "transfer_in",
+			want:    TransactionTypeTransferIn,
+			wantErr: false,
+		},
 		{
 			name:    "異常系: 無効な値",
 			input:   "invalid",
@@ -0,0 +1,86 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/config"
+)
+
+// LegacyKeyName ADMIN_API_KEYで指定したキーの名前（すべてのスコープを持つ）
+const LegacyKeyName = "admin"
+
+// Authenticator X-API-Keyの値から管理APIキーを特定する
+// ADMIN_API_KEY、定義ファイル、MySQLの順に探し、見つからないキーと失効済みのキーはErrAPIKeyNotFoundとする
+type Authenticator struct {
+	legacy *api_key.APIKey
+	file   *FileRepository
+	store  api_key.Repository
+}
+
+// NewAuthenticator 新しいAuthenticatorを作成
+// storeはADMIN_API_KEYS_FROM_DBが有効な場合のみ使用する
+func NewAuthenticator(cfg *config.AdminAPIConfig, store api_key.Repository) (*Authenticator, error) {
+	a := &Authenticator{}
+
+	if cfg.APIKey != "" {
+		legacy, err := api_key.NewAPIKey(LegacyKeyName, api_key.HashKey(cfg.APIKey), []api_key.Scope{api_key.ScopeAll})
+		if err != nil {
+			return nil, err
+		}
+		a.legacy = legacy
+	}
+
+	if cfg.KeysFile != "" {
+		file, err := LoadFile(cfg.KeysFile)
+		if err != nil {
+			return nil, err
+		}
+		if a.legacy != nil && file.names()[LegacyKeyName] {
+			return nil, fmt.Errorf("api keys file %s: %w: %s is reserved for ADMIN_API_KEY", cfg.KeysFile, api_key.ErrAPIKeyAlreadyExists, LegacyKeyName)
+		}
+		a.file = file
+	}
+
+	if cfg.KeysFromDB {
+		a.store = store
+	}
+
+	return a, nil
+}
+
+// Authenticate キーの値に対応する有効な管理APIキーを返す
+func (a *Authenticator) Authenticate(ctx context.Context, value string) (*api_key.APIKey, error) {
+	if value == "" {
+		return nil, api_key.ErrAPIKeyNotFound
+	}
+	keyHash := api_key.HashKey(value)
+
+	if a.legacy != nil && a.legacy.KeyHash() == keyHash {
+		return a.legacy, nil
+	}
+
+	var repos []api_key.Repository
+	if a.file != nil {
+		repos = append(repos, a.file)
+	}
+	if a.store != nil {
+		repos = append(repos, a.store)
+	}
+	for _, repo := range repos {
+		key, err := repo.FindByHash(ctx, keyHash)
+		if errors.Is(err, api_key.ErrAPIKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if key.IsRevoked() {
+			return nil, api_key.ErrAPIKeyNotFound
+		}
+		return key, nil
+	}
+	return nil, api_key.ErrAPIKeyNotFound
+}
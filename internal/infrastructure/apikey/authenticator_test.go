@@ -0,0 +1,185 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/config"
+)
+
+// stubRepository テスト用の管理APIキーリポジトリ
+type stubRepository struct {
+	keys map[string]*api_key.APIKey
+	err  error
+}
+
+func (r *stubRepository) FindByHash(ctx context.Context, keyHash string) (*api_key.APIKey, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	key, ok := r.keys[keyHash]
+	if !ok {
+		return nil, api_key.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func writeKeysFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "api_keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "正常系: 管理APIキーを読み込む",
+			content: `[{"name":"game-server","key_hash":"` + api_key.HashKey("game-key") + `","scopes":["currency:grant","currency:consume"]}]`,
+		},
+		{
+			name:    "異常系: ハッシュではなく平文のキー",
+			content: `[{"name":"game-server","key_hash":"game-key","scopes":["currency:grant"]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 不明なスコープ",
+			content: `[{"name":"game-server","key_hash":"` + api_key.HashKey("game-key") + `","scopes":["currency:mint"]}]`,
+			wantErr: true,
+		},
+		{
+			name: "異常系: 名前が重複",
+			content: `[{"name":"game-server","key_hash":"` + api_key.HashKey("key-1") + `","scopes":[]},` +
+				`{"name":"game-server","key_hash":"` + api_key.HashKey("key-2") + `","scopes":[]}]`,
+			wantErr: true,
+		},
+		{
+			name:    "異常系: JSONが不正",
+			content: `{"name":"game-server"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writeKeysFile(t, tt.content))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	keysFile := writeKeysFile(t, `[{"name":"game-server","key_hash":"`+api_key.HashKey("game-key")+`","scopes":["currency:grant"]}]`)
+
+	support, err := api_key.NewAPIKey("support-tool", api_key.HashKey("support-key"), []api_key.Scope{api_key.ScopeCurrencyRead})
+	require.NoError(t, err)
+	retired, err := api_key.NewAPIKey("retired-tool", api_key.HashKey("retired-key"), []api_key.Scope{api_key.ScopeAll})
+	require.NoError(t, err)
+	revokedAt := time.Now()
+	retired.SetRevokedAt(&revokedAt)
+	store := &stubRepository{keys: map[string]*api_key.APIKey{
+		support.KeyHash(): support,
+		retired.KeyHash(): retired,
+	}}
+
+	tests := []struct {
+		name     string
+		cfg      config.AdminAPIConfig
+		store    api_key.Repository
+		value    string
+		wantName string
+		wantErr  error
+	}{
+		{
+			name:     "正常系: ADMIN_API_KEYはすべてのスコープを持つ",
+			cfg:      config.AdminAPIConfig{APIKey: "legacy-key"},
+			value:    "legacy-key",
+			wantName: LegacyKeyName,
+		},
+		{
+			name:     "正常系: 定義ファイルのキー",
+			cfg:      config.AdminAPIConfig{APIKey: "legacy-key", KeysFile: keysFile},
+			value:    "game-key",
+			wantName: "game-server",
+		},
+		{
+			name:     "正常系: MySQLに登録したキー",
+			cfg:      config.AdminAPIConfig{KeysFile: keysFile, KeysFromDB: true},
+			store:    store,
+			value:    "support-key",
+			wantName: "support-tool",
+		},
+		{
+			name:    "異常系: ADMIN_API_KEYS_FROM_DBが無効な場合はMySQLを参照しない",
+			cfg:     config.AdminAPIConfig{KeysFile: keysFile},
+			store:   store,
+			value:   "support-key",
+			wantErr: api_key.ErrAPIKeyNotFound,
+		},
+		{
+			name:    "異常系: 失効済みのキー",
+			cfg:     config.AdminAPIConfig{KeysFromDB: true},
+			store:   store,
+			value:   "retired-key",
+			wantErr: api_key.ErrAPIKeyNotFound,
+		},
+		{
+			name:    "異常系: 不明なキー",
+			cfg:     config.AdminAPIConfig{APIKey: "legacy-key", KeysFile: keysFile},
+			value:   "unknown-key",
+			wantErr: api_key.ErrAPIKeyNotFound,
+		},
+		{
+			name:    "異常系: 空のキー",
+			cfg:     config.AdminAPIConfig{APIKey: "legacy-key"},
+			value:   "",
+			wantErr: api_key.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAuthenticator(&tt.cfg, tt.store)
+			require.NoError(t, err)
+
+			key, err := a.Authenticate(context.Background(), tt.value)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, key.Name())
+		})
+	}
+
+	t.Run("異常系: MySQLのエラー", func(t *testing.T) {
+		dbErr := errors.New("database error")
+		a, err := NewAuthenticator(&config.AdminAPIConfig{KeysFromDB: true}, &stubRepository{err: dbErr})
+		require.NoError(t, err)
+
+		_, err = a.Authenticate(context.Background(), "support-key")
+		assert.ErrorIs(t, err, dbErr)
+	})
+
+	t.Run("異常系: 定義ファイルでADMIN_API_KEYと同じ名前を使う", func(t *testing.T) {
+		file := writeKeysFile(t, `[{"name":"admin","key_hash":"`+api_key.HashKey("other-key")+`","scopes":["*"]}]`)
+		_, err := NewAuthenticator(&config.AdminAPIConfig{APIKey: "legacy-key", KeysFile: file}, nil)
+		assert.ErrorIs(t, err, api_key.ErrAPIKeyAlreadyExists)
+	})
+}
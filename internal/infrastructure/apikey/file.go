@@ -0,0 +1,81 @@
+package apikey
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gem-server/internal/domain/api_key"
+)
+
+// fileEntry 定義ファイルに記載する管理APIキー
+type fileEntry struct {
+	Name    string   `json:"name"`
+	KeyHash string   `json:"key_hash"` // キーのSHA-256ハッシュ（16進数表記）
+	Scopes  []string `json:"scopes"`
+}
+
+// FileRepository 定義ファイルから読み込んだ管理APIキーのリポジトリ
+type FileRepository struct {
+	keys map[string]*api_key.APIKey // キーのハッシュ → 管理APIキー
+}
+
+// LoadFile 定義ファイル（JSON配列）から管理APIキーを読み込む
+func LoadFile(path string) (*FileRepository, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read api keys file: %w", err)
+	}
+
+	var entries []fileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse api keys file %s: %w", path, err)
+	}
+
+	repo := &FileRepository{keys: make(map[string]*api_key.APIKey, len(entries))}
+	names := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		keyHash := strings.ToLower(entry.KeyHash)
+		if b, err := hex.DecodeString(keyHash); err != nil || len(b) != 32 {
+			return nil, fmt.Errorf("api keys file %s: entry %d: key_hash must be a hex-encoded SHA-256 hash", path, i)
+		}
+		scopes, err := api_key.ParseScopes(strings.Join(entry.Scopes, ","))
+		if err != nil {
+			return nil, fmt.Errorf("api keys file %s: entry %d: %w", path, i, err)
+		}
+		key, err := api_key.NewAPIKey(entry.Name, keyHash, scopes)
+		if err != nil {
+			return nil, fmt.Errorf("api keys file %s: entry %d: %w", path, i, err)
+		}
+		if names[key.Name()] {
+			return nil, fmt.Errorf("api keys file %s: %w: %s", path, api_key.ErrAPIKeyAlreadyExists, key.Name())
+		}
+		if _, ok := repo.keys[keyHash]; ok {
+			return nil, fmt.Errorf("api keys file %s: entry %d: duplicate key_hash", path, i)
+		}
+		names[key.Name()] = true
+		repo.keys[keyHash] = key
+	}
+	return repo, nil
+}
+
+// FindByHash キーのハッシュで管理APIキーを取得
+func (r *FileRepository) FindByHash(ctx context.Context, keyHash string) (*api_key.APIKey, error) {
+	key, ok := r.keys[keyHash]
+	if !ok {
+		return nil, api_key.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// names 定義されている管理APIキーの名前
+func (r *FileRepository) names() map[string]bool {
+	names := make(map[string]bool, len(r.keys))
+	for _, key := range r.keys {
+		names[key.Name()] = true
+	}
+	return names
+}
@@ -0,0 +1,214 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gem-server/internal/domain/currency"
+	"gem-server/internal/infrastructure/persistence/mysql"
+)
+
+// キャッシュエントリはハッシュで保持する
+//   - version, balance: キャッシュされた残高とそのバージョン
+//   - fence: 書き込み時に記録するバージョン。これ以下のバージョンはキャッシュしない
+//
+// 書き込み時はDBトランザクションのコミット後にキャッシュを削除してfenceを記録する
+// （ロールバックされた書き込みのバージョンをfenceにすると、DBのバージョンが追いつくまでキャッシュされなくなるため）。
+// 単純に削除するだけでは、コミット前にDBから読み込まれた古い残高が削除後に再びキャッシュされてしまう。
+// fenceを残すことで、書き込み前のバージョンがキャッシュされることを防ぐ。
+var (
+	// populateScript fenceより新しく、キャッシュ済みのものより古くない場合のみ残高をキャッシュする
+	populateScript = redis.NewScript(`
+local fence = redis.call('HGET', KEYS[1], 'fence')
+if fence and tonumber(ARGV[1]) <= tonumber(fence) then
+  return 0
+end
+local cached = redis.call('HGET', KEYS[1], 'version')
+if cached and tonumber(ARGV[1]) < tonumber(cached) then
+  return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[1], 'version', ARGV[1], 'balance', ARGV[2])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+	// fenceScript キャッシュ済みの残高を破棄し、書き込んだバージョンをfenceとして記録する
+	// 実行に失敗してもエラーにしない（フェイルオープン）。Redisの障害で残高の更新を失敗させないためで、
+	// 古い残高が読まれ得るのはキャッシュの有効期限までに限られる。
+	// 残高の更新はDBトランザクション内でキャッシュを使わずに読み込み、バージョンを確認して行うため影響しない
+	fenceScript = redis.NewScript(`
+local fence = tonumber(ARGV[1])
+local current = redis.call('HGET', KEYS[1], 'fence')
+if current and tonumber(current) > fence then
+  fence = tonumber(current)
+end
+redis.call('DEL', KEYS[1])
+redis.call('HSET', KEYS[1], 'fence', fence)
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+)
+
+// CurrencyRepository Redisで残高をキャッシュするCurrencyRepositoryのデコレーター
+// 読み込みはキャッシュを優先し、ミスした場合は委譲先から取得してキャッシュする。
+// DBトランザクション内の読み込みはコミット前の状態を含み得るため、常に委譲先から取得する
+type CurrencyRepository struct {
+	next   currency.CurrencyRepository
+	client *redis.Client
+	ttl    time.Duration
+	tracer trace.Tracer
+}
+
+// NewCurrencyRepository 新しいCurrencyRepositoryを作成
+func NewCurrencyRepository(next currency.CurrencyRepository, client *redis.Client, ttl time.Duration) *CurrencyRepository {
+	return &CurrencyRepository{
+		next:   next,
+		client: client,
+		ttl:    ttl,
+		tracer: otel.Tracer("currency-cache"),
+	}
+}
+
+// FindByUserIDAndType ユーザーIDと通貨タイプで通貨を取得
+func (r *CurrencyRepository) FindByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) (*currency.Currency, error) {
+	if mysql.InTransaction(ctx) {
+		return r.next.FindByUserIDAndType(ctx, userID, currencyType)
+	}
+
+	ctx, span := r.tracer.Start(ctx, "CachedCurrencyRepository.FindByUserIDAndType")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("cache.user_id", userID),
+		attribute.String("cache.currency_type", currencyType.String()),
+	)
+
+	key := currencyKey(userID, currencyType)
+
+	c, err := r.get(ctx, key, userID, currencyType)
+	if err != nil {
+		// キャッシュが利用できない場合はDBから取得する
+		span.RecordError(err)
+	}
+	if c != nil {
+		span.SetAttributes(attribute.Bool("cache.hit", true))
+		span.SetStatus(otelcodes.Ok, "cache hit")
+		return c, nil
+	}
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+
+	c, err = r.next.FindByUserIDAndType(ctx, userID, currencyType)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	if err := populateScript.Run(ctx, r.client, []string{key},
+		c.Version(),
+		c.Balance(),
+		r.ttl.Milliseconds(),
+	).Err(); err != nil {
+		span.RecordError(err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "cache miss")
+	return c, nil
+}
+
+// Save 通貨を保存し、DBトランザクションのコミット後にキャッシュを無効化する
+// キャッシュの無効化に失敗しても保存は成功として扱う（fenceScriptを参照）
+func (r *CurrencyRepository) Save(ctx context.Context, c *currency.Currency) error {
+	if err := r.next.Save(ctx, c); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, c)
+	return nil
+}
+
+// Create 新しい通貨を作成し、DBトランザクションのコミット後にキャッシュを無効化する
+// キャッシュの無効化に失敗しても作成は成功として扱う（fenceScriptを参照）
+func (r *CurrencyRepository) Create(ctx context.Context, c *currency.Currency) error {
+	if err := r.next.Create(ctx, c); err != nil {
+		return err
+	}
+	r.invalidateAfterCommit(ctx, c)
+	return nil
+}
+
+// invalidateAfterCommit 書き込んだ時点のバージョンでキャッシュの無効化をコミット後に予約する
+// トランザクション外の書き込みはその場で無効化する
+func (r *CurrencyRepository) invalidateAfterCommit(ctx context.Context, c *currency.Currency) {
+	userID, currencyType, version := c.UserID(), c.CurrencyType(), c.Version()
+	mysql.AfterCommit(ctx, func(ctx context.Context) {
+		r.invalidate(ctx, userID, currencyType, version)
+	})
+}
+
+// get キャッシュから通貨を取得（存在しない場合はnil）
+func (r *CurrencyRepository) get(ctx context.Context, key, userID string, currencyType currency.CurrencyType) (*currency.Currency, error) {
+	values, err := r.client.HMGet(ctx, key, "version", "balance").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cached currency: %w", err)
+	}
+
+	versionStr, ok := values[0].(string)
+	if !ok {
+		return nil, nil
+	}
+	balanceStr, ok := values[1].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cached version: %w", err)
+	}
+	balance, err := strconv.ParseInt(balanceStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cached balance: %w", err)
+	}
+
+	return currency.NewCurrency(userID, currencyType, balance, version)
+}
+
+// invalidate キャッシュを破棄し、書き込んだバージョン以前の残高がキャッシュされないようにする
+// 失敗した場合はスパンにエラーを記録するだけで、呼び出し元には返さない
+func (r *CurrencyRepository) invalidate(ctx context.Context, userID string, currencyType currency.CurrencyType, version int) {
+	ctx, span := r.tracer.Start(ctx, "CachedCurrencyRepository.invalidate")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("cache.user_id", userID),
+		attribute.String("cache.currency_type", currencyType.String()),
+		attribute.Int("cache.version", version),
+	)
+
+	key := currencyKey(userID, currencyType)
+	if err := fenceScript.Run(ctx, r.client, []string{key},
+		version,
+		r.ttl.Milliseconds(),
+	).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		span.RecordError(fmt.Errorf("failed to invalidate cached currency: %w", err))
+		span.SetStatus(otelcodes.Error, err.Error())
+		return
+	}
+
+	span.SetStatus(otelcodes.Ok, "cache invalidated")
+}
+
+// currencyKey 通貨のキャッシュキー
+func currencyKey(userID string, currencyType currency.CurrencyType) string {
+	return "gem:currency:" + userID + ":" + currencyType.String()
+}
@@ -0,0 +1,303 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/currency"
+	"gem-server/internal/infrastructure/persistence/mysql"
+)
+
+// MockCurrencyRepository モック通貨リポジトリ
+type MockCurrencyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyRepository) FindByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) (*currency.Currency, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*currency.Currency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) Save(ctx context.Context, c *currency.Currency) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCurrencyRepository) Create(ctx context.Context, c *currency.Currency) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+const testTTL = time.Minute
+
+func setupCachedRepository(t *testing.T) (*CurrencyRepository, *MockCurrencyRepository, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	next := new(MockCurrencyRepository)
+	t.Cleanup(func() { next.AssertExpectations(t) })
+
+	return NewCurrencyRepository(next, client, testTTL), next, mr
+}
+
+func TestCurrencyRepository_FindByUserIDAndType(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: 2回目以降はキャッシュから取得", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 3), nil).Once()
+
+		for i := 0; i < 3; i++ {
+			got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1000), got.Balance())
+			assert.Equal(t, 3, got.Version())
+		}
+
+		assert.Equal(t, "1000", mr.HGet("gem:currency:user123:paid", "balance"))
+		assert.Equal(t, testTTL, mr.TTL("gem:currency:user123:paid"))
+	})
+
+	t.Run("正常系: マイナス残高もキャッシュできる", func(t *testing.T) {
+		repo, next, _ := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypeFree, -500, 2), nil).Once()
+
+		for i := 0; i < 2; i++ {
+			got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypeFree)
+			require.NoError(t, err)
+			assert.Equal(t, int64(-500), got.Balance())
+		}
+	})
+
+	t.Run("正常系: 有効期限が切れたら再取得", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil).Twice()
+
+		_, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+		mr.FastForward(testTTL)
+		_, err = repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+	})
+
+	t.Run("正常系: 見つからない場合はキャッシュしない", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(nil, currency.ErrCurrencyNotFound).Twice()
+
+		for i := 0; i < 2; i++ {
+			_, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+			assert.ErrorIs(t, err, currency.ErrCurrencyNotFound)
+		}
+		assert.False(t, mr.Exists("gem:currency:user123:paid"))
+	})
+
+	t.Run("正常系: Redisが利用できない場合はDBから取得", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		mr.Close()
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil).Once()
+
+		got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), got.Balance())
+	})
+
+	t.Run("正常系: DBトランザクション内ではキャッシュを使わない", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectCommit()
+
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil).Twice()
+
+		tm := mysql.NewTransactionManager(&mysql.DB{DB: db})
+		err = tm.WithTransaction(ctx, func(ctx context.Context) error {
+			for i := 0; i < 2; i++ {
+				if _, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, mr.Exists("gem:currency:user123:paid"))
+	})
+}
+
+func TestCurrencyRepository_Save(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: 保存後は再取得する", func(t *testing.T) {
+		repo, next, _ := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil).Once()
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 3), nil).Once()
+
+		_, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 2)
+		next.On("Save", mock.Anything, c).Return(nil).Once()
+		require.NoError(t, repo.Save(ctx, c))
+
+		got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(900), got.Balance())
+	})
+
+	t.Run("正常系: 書き込み前のバージョンは再びキャッシュされない", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 2)
+		next.On("Save", mock.Anything, c).Return(nil).Once()
+		require.NoError(t, repo.Save(ctx, c))
+
+		// コミット前に別の読み込みが古い残高を取得しても、キャッシュには載らない
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 2), nil).Twice()
+		for i := 0; i < 2; i++ {
+			got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+			require.NoError(t, err)
+			assert.Equal(t, int64(1000), got.Balance())
+		}
+		assert.Empty(t, mr.HGet("gem:currency:user123:paid", "balance"))
+
+		// コミット後の新しいバージョンはキャッシュされる
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 3), nil).Once()
+		for i := 0; i < 2; i++ {
+			got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+			require.NoError(t, err)
+			assert.Equal(t, int64(900), got.Balance())
+		}
+	})
+
+	t.Run("異常系: 保存に失敗した場合はキャッシュを変更しない", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil).Once()
+		_, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 2)
+		next.On("Save", mock.Anything, c).Return(assert.AnError).Once()
+		assert.ErrorIs(t, repo.Save(ctx, c), assert.AnError)
+		assert.Equal(t, "1000", mr.HGet("gem:currency:user123:paid", "balance"))
+	})
+
+	t.Run("正常系: Redisが利用できない場合も保存は成功する", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		mr.Close()
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 2)
+		next.On("Save", mock.Anything, c).Return(nil).Once()
+		assert.NoError(t, repo.Save(ctx, c))
+	})
+
+	t.Run("正常系: DBトランザクション内の保存はコミット後にキャッシュを無効化する", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectCommit()
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 2)
+		next.On("Save", mock.Anything, c).Return(nil).Once()
+
+		tm := mysql.NewTransactionManager(&mysql.DB{DB: db})
+		err = tm.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := repo.Save(ctx, c); err != nil {
+				return err
+			}
+			assert.False(t, mr.Exists("gem:currency:user123:paid"))
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "2", mr.HGet("gem:currency:user123:paid", "fence"))
+	})
+
+	t.Run("正常系: ロールバックした保存はキャッシュを変更しない", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).
+			Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil).Once()
+		_, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+
+		db, sqlMock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+		sqlMock.ExpectBegin()
+		sqlMock.ExpectRollback()
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 2)
+		next.On("Save", mock.Anything, c).Return(nil).Once()
+
+		tm := mysql.NewTransactionManager(&mysql.DB{DB: db})
+		err = tm.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := repo.Save(ctx, c); err != nil {
+				return err
+			}
+			return assert.AnError
+		})
+		require.ErrorIs(t, err, assert.AnError)
+
+		// fenceはDBのバージョンを超えず、コミット済みの残高は引き続きキャッシュから返す
+		assert.Empty(t, mr.HGet("gem:currency:user123:paid", "fence"))
+		got, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypePaid)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), got.Balance())
+	})
+}
+
+func TestCurrencyRepository_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("正常系: 作成前の読み込みの結果は残らない", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+
+		// 作成前に存在しないことを確認した読み込みの結果は残らない
+		next.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).
+			Return(nil, currency.ErrCurrencyNotFound).Once()
+		_, err := repo.FindByUserIDAndType(ctx, "user123", currency.CurrencyTypeFree)
+		require.ErrorIs(t, err, currency.ErrCurrencyNotFound)
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypeFree, 100, 1)
+		next.On("Create", mock.Anything, c).Return(nil).Once()
+		require.NoError(t, repo.Create(ctx, c))
+		assert.Equal(t, "1", mr.HGet("gem:currency:user123:free", "fence"))
+	})
+
+	t.Run("正常系: Redisが利用できない場合も作成は成功する", func(t *testing.T) {
+		repo, next, mr := setupCachedRepository(t)
+		mr.Close()
+
+		c := currency.MustNewCurrency("user123", currency.CurrencyTypeFree, 100, 1)
+		next.On("Create", mock.Anything, c).Return(nil).Once()
+		assert.NoError(t, repo.Create(ctx, c))
+	})
+}
@@ -7,17 +7,25 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+
+	"gem-server/internal/infrastructure/ipaccess"
 )
 
 // Config アプリケーション全体の設定
 type Config struct {
-	Server        ServerConfig
-	Database      DatabaseConfig
-	Redis         RedisConfig
-	JWT           JWTConfig
-	AdminAPI      AdminAPIConfig
-	OpenTelemetry OpenTelemetryConfig
-	Environment   string
+	Server            ServerConfig
+	Database          DatabaseConfig
+	Redis             RedisConfig
+	JWT               JWTConfig
+	OIDC              OIDCConfig
+	AdminAPI          AdminAPIConfig
+	OpenTelemetry     OpenTelemetryConfig
+	CurrencyExpiry    CurrencyExpiryConfig
+	CodeExpiry        CodeExpiryConfig
+	CurrencyTransfer  CurrencyTransferConfig
+	RateLimit         RateLimitConfig
+	RedemptionLockout RedemptionLockoutConfig
+	Environment       string
 }
 
 // ServerConfig サーバー設定
@@ -26,6 +34,9 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// TrustedProxies X-Forwarded-For/X-Real-IPを信頼するプロキシ（IPアドレスまたはCIDR）
+	// 指定しない場合は転送ヘッダーを使わず、接続元のアドレスをクライアントのIPアドレスとする
+	TrustedProxies []string
 }
 
 // DatabaseConfig データベース設定
@@ -48,20 +59,47 @@ type RedisConfig struct {
 	Password string
 	DB       int
 	Enabled  bool
+	CacheTTL time.Duration // 残高キャッシュの有効期間
 }
 
 // JWTConfig JWT設定
+// 署名鍵（SigningKeyFiles/SigningKeysDir）を指定した場合はRS256/ES256/EdDSAで署名し、
+// 指定しない場合はSecretを使ったHS256で署名する
 type JWTConfig struct {
-	Secret     string
-	Expiration time.Duration
-	Issuer     string
+	Secret             string
+	Expiration         time.Duration // アクセストークンの有効期間
+	RefreshExpiration  time.Duration // リフレッシュトークンの有効期間
+	Issuer             string
+	SigningKeyFiles    []string      // 署名鍵（PEM形式の秘密鍵）のファイルパス
+	SigningKeysDir     string        // 署名鍵（*.pem）を配置するディレクトリ
+	KeyReloadInterval  time.Duration // 署名鍵を再読み込みする間隔（0の場合は再読み込みしない）
+	KeyActivationDelay time.Duration // 追加された鍵をJWKSで公開してから署名に使い始めるまでの待機時間
+	AcceptHS256        bool          // 署名鍵の使用中もSecretで署名されたHS256のトークンを受け付ける（移行期間用）
+}
+
+// UsesSigningKeys 非対称鍵による署名が設定されているかどうか
+func (c *JWTConfig) UsesSigningKeys() bool {
+	return len(c.SigningKeyFiles) > 0 || c.SigningKeysDir != ""
+}
+
+// OIDCConfig 外部のOIDCプロバイダーが発行したIDトークンによるユーザーAPIの認証設定
+type OIDCConfig struct {
+	Enabled      bool
+	Issuer       string        // issクレームの期待値（JWT_ISSUERとは異なる値）
+	JWKSURL      string        // 検証用公開鍵（JWKS）のURL
+	Audience     string        // audクレームの期待値（OIDCプロバイダーに登録したクライアントID）
+	UserIDClaim  string        // user_idとして使うクレーム
+	JWKSCacheTTL time.Duration // JWKSをキャッシュする期間
 }
 
 // AdminAPIConfig 管理API設定
+// APIKeyはすべてのスコープを持つ"admin"という名前のキーとして扱う
 type AdminAPIConfig struct {
 	Enabled    bool
 	APIKey     string
-	AllowedIPs []string // オプション: IP制限
+	KeysFile   string   // オプション: 名前とスコープを指定した管理APIキーの定義ファイル（JSON、キーはハッシュで記載）
+	KeysFromDB bool     // MySQLのadmin_api_keysテーブルに登録した管理APIキーを使用するか
+	AllowedIPs []string // オプション: IP制限（IPアドレスまたはCIDR、IPv6も指定可能）
 }
 
 // OpenTelemetryConfig OpenTelemetry設定
@@ -75,6 +113,46 @@ type OpenTelemetryConfig struct {
 	MetricsExporter string // "otlp", "prometheus", "stdout"
 }
 
+// CurrencyExpiryConfig 有効期限付き通貨の失効ジョブ設定
+type CurrencyExpiryConfig struct {
+	Enabled   bool
+	Interval  time.Duration // 失効処理の実行間隔
+	BatchSize int           // 1回の実行で処理するロットの最大数
+}
+
+// CodeExpiryConfig 有効期限切れの引き換えコードを失効させるジョブ設定
+// 複数インスタンスで起動した場合もMySQLのロックにより1つのインスタンスだけが実行する
+type CodeExpiryConfig struct {
+	Enabled   bool
+	Interval  time.Duration // 失効処理の実行間隔
+	BatchSize int           // 1回の実行で処理するコードの最大数
+}
+
+// CurrencyTransferConfig ユーザー間の通貨譲渡設定
+type CurrencyTransferConfig struct {
+	TransferableTypes []string // 譲渡を許可する通貨タイプ（"paid", "free"）
+}
+
+// RateLimitConfig レート制限設定（トークンバケット）
+// Rateが0の識別子は制限しない
+type RateLimitConfig struct {
+	Enabled     bool
+	UserRate    float64 // ユーザーIDごとの1秒あたりの補充トークン数
+	UserBurst   int     // ユーザーIDごとのバケット容量
+	APIKeyRate  float64 // APIキーごとの1秒あたりの補充トークン数
+	APIKeyBurst int     // APIキーごとのバケット容量
+	IPRate      float64 // IPアドレスごとの1秒あたりの補充トークン数
+	IPBurst     int     // IPアドレスごとのバケット容量
+}
+
+// RedemptionLockoutConfig コード引き換えの総当たり対策設定
+type RedemptionLockoutConfig struct {
+	Enabled     bool
+	MaxFailures int           // ロックアウトまでに許容する失敗回数
+	Window      time.Duration // 失敗回数を数える期間
+	Duration    time.Duration // ロックアウトの期間
+}
+
 // Load 設定を読み込む
 func Load() (*Config, error) {
 	// .envファイルを読み込む（存在しない場合は無視）
@@ -85,10 +163,11 @@ func Load() (*Config, error) {
 	cfg := &Config{
 		Environment: env,
 		Server: ServerConfig{
-			Port:         getEnvAsInt("SERVER_PORT", 8080),
-			ReadTimeout:  getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
-			WriteTimeout: getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
-			IdleTimeout:  getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			Port:           getEnvAsInt("SERVER_PORT", 8080),
+			ReadTimeout:    getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:   getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
+			IdleTimeout:    getEnvAsDuration("SERVER_IDLE_TIMEOUT", 60*time.Second),
+			TrustedProxies: getEnvAsStringSlice("TRUSTED_PROXIES", []string{}),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -107,15 +186,32 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 			Enabled:  getEnvAsBool("REDIS_ENABLED", false),
+			CacheTTL: getEnvAsDuration("REDIS_CACHE_TTL", 5*time.Minute),
 		},
 		JWT: JWTConfig{
-			Secret:     getEnv("JWT_SECRET", ""),
-			Expiration: getEnvAsDuration("JWT_EXPIRATION", 24*time.Hour),
-			Issuer:     getEnv("JWT_ISSUER", "gem-server"),
+			Secret:             getEnv("JWT_SECRET", ""),
+			Expiration:         getEnvAsDuration("JWT_EXPIRATION", 15*time.Minute),
+			RefreshExpiration:  getEnvAsDuration("JWT_REFRESH_EXPIRATION", 30*24*time.Hour),
+			Issuer:             getEnv("JWT_ISSUER", "gem-server"),
+			SigningKeyFiles:    getEnvAsStringSlice("JWT_SIGNING_KEY_FILES", []string{}),
+			SigningKeysDir:     getEnv("JWT_SIGNING_KEYS_DIR", ""),
+			KeyReloadInterval:  getEnvAsDuration("JWT_KEY_RELOAD_INTERVAL", 5*time.Minute),
+			KeyActivationDelay: getEnvAsDuration("JWT_KEY_ACTIVATION_DELAY", 15*time.Minute),
+			AcceptHS256:        getEnvAsBool("JWT_ACCEPT_HS256", false),
+		},
+		OIDC: OIDCConfig{
+			Enabled:      getEnvAsBool("OIDC_ENABLED", false),
+			Issuer:       getEnv("OIDC_ISSUER", ""),
+			JWKSURL:      getEnv("OIDC_JWKS_URL", ""),
+			Audience:     getEnv("OIDC_AUDIENCE", ""),
+			UserIDClaim:  getEnv("OIDC_USER_ID_CLAIM", "sub"),
+			JWKSCacheTTL: getEnvAsDuration("OIDC_JWKS_CACHE_TTL", time.Hour),
 		},
 		AdminAPI: AdminAPIConfig{
 			Enabled:    getEnvAsBool("ADMIN_API_ENABLED", false),
 			APIKey:     getEnv("ADMIN_API_KEY", ""),
+			KeysFile:   getEnv("ADMIN_API_KEYS_FILE", ""),
+			KeysFromDB: getEnvAsBool("ADMIN_API_KEYS_FROM_DB", false),
 			AllowedIPs: getEnvAsStringSlice("ADMIN_API_ALLOWED_IPS", []string{}),
 		},
 		OpenTelemetry: OpenTelemetryConfig{
@@ -127,6 +223,34 @@ func Load() (*Config, error) {
 			TraceExporter:   getEnv("OTEL_TRACES_EXPORTER", "otlp"),
 			MetricsExporter: getEnv("OTEL_METRICS_EXPORTER", "otlp"),
 		},
+		CurrencyExpiry: CurrencyExpiryConfig{
+			Enabled:   getEnvAsBool("CURRENCY_EXPIRY_ENABLED", true),
+			Interval:  getEnvAsDuration("CURRENCY_EXPIRY_INTERVAL", time.Minute),
+			BatchSize: getEnvAsInt("CURRENCY_EXPIRY_BATCH_SIZE", 100),
+		},
+		CodeExpiry: CodeExpiryConfig{
+			Enabled:   getEnvAsBool("CODE_EXPIRY_ENABLED", true),
+			Interval:  getEnvAsDuration("CODE_EXPIRY_INTERVAL", 5*time.Minute),
+			BatchSize: getEnvAsInt("CODE_EXPIRY_BATCH_SIZE", 500),
+		},
+		CurrencyTransfer: CurrencyTransferConfig{
+			TransferableTypes: getEnvAsStringSlice("CURRENCY_TRANSFERABLE_TYPES", []string{"free"}),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     getEnvAsBool("RATE_LIMIT_ENABLED", true),
+			UserRate:    getEnvAsFloat("RATE_LIMIT_USER_RATE", 5),
+			UserBurst:   getEnvAsInt("RATE_LIMIT_USER_BURST", 20),
+			APIKeyRate:  getEnvAsFloat("RATE_LIMIT_API_KEY_RATE", 50),
+			APIKeyBurst: getEnvAsInt("RATE_LIMIT_API_KEY_BURST", 100),
+			IPRate:      getEnvAsFloat("RATE_LIMIT_IP_RATE", 20),
+			IPBurst:     getEnvAsInt("RATE_LIMIT_IP_BURST", 40),
+		},
+		RedemptionLockout: RedemptionLockoutConfig{
+			Enabled:     getEnvAsBool("REDEMPTION_LOCKOUT_ENABLED", true),
+			MaxFailures: getEnvAsInt("REDEMPTION_LOCKOUT_MAX_FAILURES", 5),
+			Window:      getEnvAsDuration("REDEMPTION_LOCKOUT_WINDOW", 15*time.Minute),
+			Duration:    getEnvAsDuration("REDEMPTION_LOCKOUT_DURATION", time.Hour),
+		},
 	}
 
 	// 必須設定の検証
@@ -145,11 +269,91 @@ func (c *Config) validate() error {
 	if c.Database.Database == "" {
 		return fmt.Errorf("DB_NAME is required")
 	}
-	if c.JWT.Secret == "" {
-		return fmt.Errorf("JWT_SECRET is required")
+	if c.JWT.Secret == "" && (!c.JWT.UsesSigningKeys() || c.JWT.AcceptHS256) {
+		return fmt.Errorf("JWT_SECRET is required unless signing keys are configured without JWT_ACCEPT_HS256")
+	}
+	if c.JWT.KeyReloadInterval < 0 || c.JWT.KeyActivationDelay < 0 {
+		return fmt.Errorf("JWT_KEY_RELOAD_INTERVAL and JWT_KEY_ACTIVATION_DELAY must not be negative")
+	}
+	if c.JWT.Expiration <= 0 || c.JWT.RefreshExpiration <= 0 {
+		return fmt.Errorf("JWT_EXPIRATION and JWT_REFRESH_EXPIRATION must be positive")
+	}
+	if c.OIDC.Enabled {
+		if err := c.OIDC.validate(c.JWT.Issuer); err != nil {
+			return err
+		}
+	}
+	if c.AdminAPI.Enabled && c.AdminAPI.APIKey == "" && c.AdminAPI.KeysFile == "" && !c.AdminAPI.KeysFromDB {
+		return fmt.Errorf("ADMIN_API_KEY, ADMIN_API_KEYS_FILE or ADMIN_API_KEYS_FROM_DB is required when ADMIN_API_ENABLED is true")
+	}
+	if _, err := ipaccess.ParsePrefixes(c.AdminAPI.AllowedIPs); err != nil {
+		return fmt.Errorf("ADMIN_API_ALLOWED_IPS: %w", err)
+	}
+	if _, err := ipaccess.ParsePrefixes(c.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	if c.Redis.Enabled && c.Redis.CacheTTL <= 0 {
+		return fmt.Errorf("REDIS_CACHE_TTL must be positive when REDIS_ENABLED is true")
+	}
+	if c.RateLimit.Enabled {
+		if err := c.RateLimit.validate(); err != nil {
+			return err
+		}
+	}
+	if c.CodeExpiry.Enabled {
+		if c.CodeExpiry.Interval <= 0 {
+			return fmt.Errorf("CODE_EXPIRY_INTERVAL must be positive when CODE_EXPIRY_ENABLED is true")
+		}
+		if c.CodeExpiry.BatchSize < 1 {
+			return fmt.Errorf("CODE_EXPIRY_BATCH_SIZE must be at least 1 when CODE_EXPIRY_ENABLED is true")
+		}
+	}
+	if c.RedemptionLockout.Enabled {
+		if c.RedemptionLockout.MaxFailures < 1 {
+			return fmt.Errorf("REDEMPTION_LOCKOUT_MAX_FAILURES must be at least 1")
+		}
+		if c.RedemptionLockout.Window <= 0 || c.RedemptionLockout.Duration <= 0 {
+			return fmt.Errorf("REDEMPTION_LOCKOUT_WINDOW and REDEMPTION_LOCKOUT_DURATION must be positive")
+		}
+	}
+	return nil
+}
+
+// validate OIDC設定の検証（jwtIssuerは自身が発行するトークンのiss）
+func (c *OIDCConfig) validate(jwtIssuer string) error {
+	if c.Issuer == "" || c.JWKSURL == "" || c.Audience == "" {
+		return fmt.Errorf("OIDC_ISSUER, OIDC_JWKS_URL and OIDC_AUDIENCE are required when OIDC_ENABLED is true")
+	}
+	if c.Issuer == jwtIssuer {
+		return fmt.Errorf("OIDC_ISSUER must differ from JWT_ISSUER")
+	}
+	if c.UserIDClaim == "" {
+		return fmt.Errorf("OIDC_USER_ID_CLAIM must not be empty")
+	}
+	if c.JWKSCacheTTL <= 0 {
+		return fmt.Errorf("OIDC_JWKS_CACHE_TTL must be positive")
+	}
+	return nil
+}
+
+// validate レート制限設定の検証
+func (c *RateLimitConfig) validate() error {
+	limits := []struct {
+		name  string
+		rate  float64
+		burst int
+	}{
+		{"RATE_LIMIT_USER", c.UserRate, c.UserBurst},
+		{"RATE_LIMIT_API_KEY", c.APIKeyRate, c.APIKeyBurst},
+		{"RATE_LIMIT_IP", c.IPRate, c.IPBurst},
 	}
-	if c.AdminAPI.Enabled && c.AdminAPI.APIKey == "" {
-		return fmt.Errorf("ADMIN_API_KEY is required when ADMIN_API_ENABLED is true")
+	for _, l := range limits {
+		if l.rate < 0 {
+			return fmt.Errorf("%s_RATE must not be negative", l.name)
+		}
+		if l.rate > 0 && l.burst < 1 {
+			return fmt.Errorf("%s_BURST must be at least 1", l.name)
+		}
 	}
 	return nil
 }
@@ -191,6 +395,19 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsFloat 環境変数を浮動小数点数として取得
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 // getEnvAsBool 環境変数を真偽値として取得
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := getEnv(key, "")
@@ -34,8 +34,39 @@ func TestLoad(t *testing.T) {
 				assert.Equal(t, "localhost", cfg.Database.Host)
 				assert.Equal(t, "test_db", cfg.Database.Database)
 				assert.Equal(t, "test-secret", cfg.JWT.Secret)
+				assert.Equal(t, 15*time.Minute, cfg.JWT.Expiration)
+				assert.Equal(t, 30*24*time.Hour, cfg.JWT.RefreshExpiration)
+				assert.False(t, cfg.JWT.UsesSigningKeys())
+				assert.Equal(t, 5*time.Minute, cfg.JWT.KeyReloadInterval)
+				assert.Equal(t, 15*time.Minute, cfg.JWT.KeyActivationDelay)
+				assert.False(t, cfg.JWT.AcceptHS256)
+				assert.False(t, cfg.OIDC.Enabled)
+				assert.False(t, cfg.AdminAPI.KeysFromDB)
+				assert.Empty(t, cfg.Server.TrustedProxies)
+				assert.Equal(t, "sub", cfg.OIDC.UserIDClaim)
+				assert.Equal(t, time.Hour, cfg.OIDC.JWKSCacheTTL)
 				assert.Equal(t, 8080, cfg.Server.Port)
 				assert.Equal(t, 3306, cfg.Database.Port)
+				assert.True(t, cfg.CurrencyExpiry.Enabled)
+				assert.Equal(t, time.Minute, cfg.CurrencyExpiry.Interval)
+				assert.Equal(t, 100, cfg.CurrencyExpiry.BatchSize)
+				assert.True(t, cfg.CodeExpiry.Enabled)
+				assert.Equal(t, 5*time.Minute, cfg.CodeExpiry.Interval)
+				assert.Equal(t, 500, cfg.CodeExpiry.BatchSize)
+				assert.Equal(t, []string{"free"}, cfg.CurrencyTransfer.TransferableTypes)
+				assert.False(t, cfg.Redis.Enabled)
+				assert.Equal(t, 5*time.Minute, cfg.Redis.CacheTTL)
+				assert.True(t, cfg.RateLimit.Enabled)
+				assert.Equal(t, 5.0, cfg.RateLimit.UserRate)
+				assert.Equal(t, 20, cfg.RateLimit.UserBurst)
+				assert.Equal(t, 50.0, cfg.RateLimit.APIKeyRate)
+				assert.Equal(t, 100, cfg.RateLimit.APIKeyBurst)
+				assert.Equal(t, 20.0, cfg.RateLimit.IPRate)
+				assert.Equal(t, 40, cfg.RateLimit.IPBurst)
+				assert.True(t, cfg.RedemptionLockout.Enabled)
+				assert.Equal(t, 5, cfg.RedemptionLockout.MaxFailures)
+				assert.Equal(t, 15*time.Minute, cfg.RedemptionLockout.Window)
+				assert.Equal(t, time.Hour, cfg.RedemptionLockout.Duration)
 			},
 		},
 		{
@@ -116,6 +147,269 @@ func TestLoad(t *testing.T) {
 			wantError:   true,
 			checkConfig: nil,
 		},
+		{
+			name: "正常系: 署名鍵を指定した場合はJWT_SECRETが不要",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SIGNING_KEYS_DIR", "/etc/gem-server/jwt-keys")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SIGNING_KEYS_DIR")
+			},
+			wantError: false,
+			checkConfig: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.JWT.UsesSigningKeys())
+				assert.Equal(t, "/etc/gem-server/jwt-keys", cfg.JWT.SigningKeysDir)
+				assert.Empty(t, cfg.JWT.Secret)
+			},
+		},
+		{
+			name: "異常系: HS256を受け付ける場合はJWT_SECRETが必要",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SIGNING_KEY_FILES", "/etc/gem-server/jwt-2024-06.pem")
+				os.Setenv("JWT_ACCEPT_HS256", "true")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SIGNING_KEY_FILES")
+				os.Unsetenv("JWT_ACCEPT_HS256")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: OIDC有効時にOIDC_AUDIENCEが未設定",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("OIDC_ENABLED", "true")
+				os.Setenv("OIDC_ISSUER", "https://accounts.example.com")
+				os.Setenv("OIDC_JWKS_URL", "https://accounts.example.com/.well-known/jwks.json")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("OIDC_ENABLED")
+				os.Unsetenv("OIDC_ISSUER")
+				os.Unsetenv("OIDC_JWKS_URL")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: 管理API有効時に管理APIキーが未設定",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("ADMIN_API_ENABLED", "true")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("ADMIN_API_ENABLED")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "正常系: MySQLに登録した管理APIキーのみを使用",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("ADMIN_API_ENABLED", "true")
+				os.Setenv("ADMIN_API_KEYS_FROM_DB", "true")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("ADMIN_API_ENABLED")
+				os.Unsetenv("ADMIN_API_KEYS_FROM_DB")
+			},
+			wantError: false,
+			checkConfig: func(t *testing.T, cfg *Config) {
+				assert.True(t, cfg.AdminAPI.KeysFromDB)
+				assert.Empty(t, cfg.AdminAPI.APIKey)
+			},
+		},
+		{
+			name: "正常系: IP制限と信頼するプロキシ（CIDR・IPv6）",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("ADMIN_API_ALLOWED_IPS", "10.0.0.0/8, 2001:db8::/32")
+				os.Setenv("TRUSTED_PROXIES", "192.0.2.10,fd00::/8")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("ADMIN_API_ALLOWED_IPS")
+				os.Unsetenv("TRUSTED_PROXIES")
+			},
+			wantError: false,
+			checkConfig: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, []string{"10.0.0.0/8", "2001:db8::/32"}, cfg.AdminAPI.AllowedIPs)
+				assert.Equal(t, []string{"192.0.2.10", "fd00::/8"}, cfg.Server.TrustedProxies)
+			},
+		},
+		{
+			name: "異常系: IP制限に不正なCIDR",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("ADMIN_API_ALLOWED_IPS", "10.0.0.0/33")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("ADMIN_API_ALLOWED_IPS")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: 信頼するプロキシに不正なアドレス",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("TRUSTED_PROXIES", "proxy.internal")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("TRUSTED_PROXIES")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: リフレッシュトークンの有効期間が0",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("JWT_REFRESH_EXPIRATION", "0s")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("JWT_REFRESH_EXPIRATION")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: Redis有効時にREDIS_CACHE_TTLが0",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("REDIS_ENABLED", "true")
+				os.Setenv("REDIS_CACHE_TTL", "0s")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("REDIS_ENABLED")
+				os.Unsetenv("REDIS_CACHE_TTL")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "正常系: レート0の識別子は制限しない",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("RATE_LIMIT_USER_RATE", "0.5")
+				os.Setenv("RATE_LIMIT_IP_RATE", "0")
+				os.Setenv("RATE_LIMIT_IP_BURST", "0")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("RATE_LIMIT_USER_RATE")
+				os.Unsetenv("RATE_LIMIT_IP_RATE")
+				os.Unsetenv("RATE_LIMIT_IP_BURST")
+			},
+			wantError: false,
+			checkConfig: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, 0.5, cfg.RateLimit.UserRate)
+				assert.Equal(t, 0.0, cfg.RateLimit.IPRate)
+			},
+		},
+		{
+			name: "異常系: レート制限のバケット容量が0",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("RATE_LIMIT_USER_BURST", "0")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("RATE_LIMIT_USER_BURST")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: 引き換えロックアウトの失敗回数が0",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("REDEMPTION_LOCKOUT_MAX_FAILURES", "0")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("REDEMPTION_LOCKOUT_MAX_FAILURES")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
+		{
+			name: "異常系: コード失効ジョブの実行間隔が0",
+			setupEnv: func() {
+				os.Setenv("DB_HOST", "localhost")
+				os.Setenv("DB_NAME", "test_db")
+				os.Setenv("JWT_SECRET", "test-secret")
+				os.Setenv("CODE_EXPIRY_INTERVAL", "0s")
+			},
+			cleanupEnv: func() {
+				os.Unsetenv("DB_HOST")
+				os.Unsetenv("DB_NAME")
+				os.Unsetenv("JWT_SECRET")
+				os.Unsetenv("CODE_EXPIRY_INTERVAL")
+			},
+			wantError:   true,
+			checkConfig: nil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,16 @@
+package denylist
+
+import (
+	"github.com/redis/go-redis/v9"
+
+	"gem-server/internal/domain/auth_token"
+)
+
+// NewDenylist 失効させたアクセストークンのDenylistを作成
+// clientがnilの場合はインメモリ、指定された場合はRedisで管理する
+func NewDenylist(client *redis.Client) auth_token.Denylist {
+	if client != nil {
+		return NewRedisDenylist(client)
+	}
+	return NewMemoryDenylist()
+}
@@ -0,0 +1,103 @@
+package denylist
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/auth_token"
+)
+
+// denylistFactory テスト用のDenylistと時刻を進める関数を作成する
+type denylistFactory func(t *testing.T) (auth_token.Denylist, func(time.Duration))
+
+func newTestMemoryDenylist(t *testing.T) (auth_token.Denylist, func(time.Duration)) {
+	now := time.Now()
+	d := NewMemoryDenylist()
+	d.now = func() time.Time { return now }
+	return d, func(dur time.Duration) { now = now.Add(dur) }
+}
+
+func newTestRedisDenylist(t *testing.T) (auth_token.Denylist, func(time.Duration)) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisDenylist(client), mr.FastForward
+}
+
+func TestDenylist(t *testing.T) {
+	factories := map[string]denylistFactory{
+		"memory": newTestMemoryDenylist,
+		"redis":  newTestRedisDenylist,
+	}
+
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("正常系: 失効させたトークンは有効期限まで記録される", func(t *testing.T) {
+				d, advance := factory(t)
+
+				require.NoError(t, d.Add(ctx, "jti_1", time.Now().Add(10*time.Minute)))
+
+				revoked, err := d.Contains(ctx, "jti_1")
+				require.NoError(t, err)
+				assert.True(t, revoked)
+
+				// 他のトークンには影響しない
+				revoked, err = d.Contains(ctx, "jti_2")
+				require.NoError(t, err)
+				assert.False(t, revoked)
+
+				advance(11 * time.Minute)
+				revoked, err = d.Contains(ctx, "jti_1")
+				require.NoError(t, err)
+				assert.False(t, revoked)
+			})
+
+			t.Run("正常系: 有効期限を過ぎたトークンは記録しない", func(t *testing.T) {
+				d, _ := factory(t)
+
+				require.NoError(t, d.Add(ctx, "jti_1", time.Now().Add(-time.Minute)))
+
+				revoked, err := d.Contains(ctx, "jti_1")
+				require.NoError(t, err)
+				assert.False(t, revoked)
+			})
+
+			t.Run("正常系: ユーザーのトークンを失効させた日時", func(t *testing.T) {
+				d, advance := factory(t)
+				revokedAt := time.Now().Truncate(time.Millisecond)
+
+				require.NoError(t, d.RevokeUser(ctx, "user123", revokedAt, time.Now().Add(15*time.Minute)))
+
+				got, err := d.UserRevokedAt(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, revokedAt.Equal(got))
+
+				got, err = d.UserRevokedAt(ctx, "user456")
+				require.NoError(t, err)
+				assert.True(t, got.IsZero())
+
+				advance(16 * time.Minute)
+				got, err = d.UserRevokedAt(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, got.IsZero())
+			})
+		})
+	}
+}
+
+func TestNewDenylist(t *testing.T) {
+	assert.IsType(t, &MemoryDenylist{}, NewDenylist(nil))
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	assert.IsType(t, &RedisDenylist{}, NewDenylist(client))
+}
@@ -0,0 +1,103 @@
+package denylist
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 不要になった記録を破棄する間隔
+const sweepInterval = time.Minute
+
+// userRevocation ユーザーごとのアクセストークンの失効の記録
+type userRevocation struct {
+	revokedAt time.Time
+	until     time.Time
+}
+
+// MemoryDenylist プロセス内で失効させたアクセストークンを管理するDenylist
+// 複数インスタンスで実行する場合は失効がインスタンスごとになり、再起動すると失われる
+type MemoryDenylist struct {
+	now       func() time.Time
+	mu        sync.Mutex
+	tokens    map[string]time.Time // jti -> 有効期限
+	users     map[string]userRevocation
+	lastSweep time.Time
+}
+
+// NewMemoryDenylist 新しいMemoryDenylistを作成
+func NewMemoryDenylist() *MemoryDenylist {
+	return &MemoryDenylist{
+		now:    time.Now,
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]userRevocation),
+	}
+}
+
+// Add jtiのアクセストークンを失効させる
+func (d *MemoryDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.sweep(now)
+	if !now.Before(expiresAt) {
+		return nil
+	}
+	d.tokens[jti] = expiresAt
+	return nil
+}
+
+// Contains jtiのアクセストークンが失効しているかどうか
+func (d *MemoryDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	expiresAt, ok := d.tokens[jti]
+	return ok && d.now().Before(expiresAt), nil
+}
+
+// RevokeUser revokedAt以前に発行したユーザーのアクセストークンをすべて失効させる
+func (d *MemoryDenylist) RevokeUser(ctx context.Context, userID string, revokedAt, until time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	d.sweep(now)
+	if !now.Before(until) {
+		return nil
+	}
+	d.users[userID] = userRevocation{revokedAt: revokedAt, until: until}
+	return nil
+}
+
+// UserRevokedAt ユーザーのアクセストークンを失効させた日時を返す（失効させていなければゼロ値）
+func (d *MemoryDenylist) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	r, ok := d.users[userID]
+	if !ok || !d.now().Before(r.until) {
+		return time.Time{}, nil
+	}
+	return r.revokedAt, nil
+}
+
+// sweep 有効期限を過ぎた記録を破棄する
+func (d *MemoryDenylist) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < sweepInterval {
+		return
+	}
+	d.lastSweep = now
+
+	for jti, expiresAt := range d.tokens {
+		if !now.Before(expiresAt) {
+			delete(d.tokens, jti)
+		}
+	}
+	for userID, r := range d.users {
+		if !now.Before(r.until) {
+			delete(d.users, userID)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package denylist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisDenylist Redisで失効させたアクセストークンを管理するDenylist
+// 複数インスタンス間で失効を共有し、記録はアクセストークンの有効期限で自動的に削除される
+type RedisDenylist struct {
+	client *redis.Client
+}
+
+// NewRedisDenylist 新しいRedisDenylistを作成
+func NewRedisDenylist(client *redis.Client) *RedisDenylist {
+	return &RedisDenylist{
+		client: client,
+	}
+}
+
+// Add jtiのアクセストークンを失効させる
+func (d *RedisDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := d.client.Set(ctx, tokenKey(jti), "1", ttl).Err(); err != nil {
+		return fmt.Errorf("failed to add token to denylist: %w", err)
+	}
+	return nil
+}
+
+// Contains jtiのアクセストークンが失効しているかどうか
+func (d *RedisDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	n, err := d.client.Exists(ctx, tokenKey(jti)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check token denylist: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RevokeUser revokedAt以前に発行したユーザーのアクセストークンをすべて失効させる
+func (d *RedisDenylist) RevokeUser(ctx context.Context, userID string, revokedAt, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := d.client.Set(ctx, userKey(userID), revokedAt.UnixMilli(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke user tokens: %w", err)
+	}
+	return nil
+}
+
+// UserRevokedAt ユーザーのアクセストークンを失効させた日時を返す（失効させていなければゼロ値）
+func (d *RedisDenylist) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	millis, err := d.client.Get(ctx, userKey(userID)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get user token revocation: %w", err)
+	}
+	return time.UnixMilli(millis), nil
+}
+
+// tokenKey 失効させたアクセストークンのキー
+func tokenKey(jti string) string {
+	return "gem:token_denylist:jti:" + jti
+}
+
+// userKey ユーザーのアクセストークンを失効させた日時のキー
+func userKey(userID string) string {
+	return "gem:token_denylist:user:" + userID
+}
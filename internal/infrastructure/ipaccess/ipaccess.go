@@ -0,0 +1,154 @@
+package ipaccess
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ParsePrefixes IPアドレスまたはCIDR表記（IPv4/IPv6）の一覧を解析する
+// IPアドレスはそのアドレスだけを含むプレフィックスとして扱い、CIDRはホスト部を切り捨てる
+func ParsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+			}
+			if prefix.Addr().Is4In6() {
+				return nil, fmt.Errorf("invalid CIDR %q: use IPv4 notation for IPv4 addresses", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP address %q: %w", entry, err)
+		}
+		addr = normalize(addr)
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Allowlist 接続を許可するIPアドレスの範囲
+type Allowlist struct {
+	prefixes []netip.Prefix
+}
+
+// NewAllowlist 新しいAllowlistを作成（エントリがない場合はすべてのアドレスを許可する）
+func NewAllowlist(entries []string) (*Allowlist, error) {
+	prefixes, err := ParsePrefixes(entries)
+	if err != nil {
+		return nil, err
+	}
+	return &Allowlist{prefixes: prefixes}, nil
+}
+
+// Restricted 許可する範囲が指定されているかどうか
+func (l *Allowlist) Restricted() bool {
+	return len(l.prefixes) > 0
+}
+
+// Allows IPアドレスが許可されているかどうか
+func (l *Allowlist) Allows(addr netip.Addr) bool {
+	if !l.Restricted() {
+		return true
+	}
+	return containsAddr(l.prefixes, normalize(addr))
+}
+
+// Resolver 接続元のアドレスと転送ヘッダーからクライアントのIPアドレスを求める
+// 転送ヘッダー（X-Forwarded-For/X-Real-IP）は信頼するプロキシからの接続の場合のみ使用する
+type Resolver struct {
+	trustedProxies []netip.Prefix
+}
+
+// NewResolver 新しいResolverを作成
+func NewResolver(trustedProxies []string) (*Resolver, error) {
+	prefixes, err := ParsePrefixes(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{trustedProxies: prefixes}, nil
+}
+
+// ClientIP クライアントのIPアドレスを返す
+// remoteAddrは接続元のアドレス（"host:port"またはIPアドレス）、forwardedForはX-Forwarded-Forヘッダーの値。
+// X-Forwarded-Forは右（接続元に近い方）から信頼するプロキシを読み飛ばし、最初に現れた信頼しないアドレスを返す。
+// アドレスを特定できない場合はfalseを返す
+func (r *Resolver) ClientIP(remoteAddr string, forwardedFor []string, realIP string) (netip.Addr, bool) {
+	addr, ok := parseRemoteAddr(remoteAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+	if !r.trusted(addr) {
+		return addr, true
+	}
+
+	var hops []string
+	for _, value := range forwardedFor {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	if len(hops) == 0 {
+		if realIP == "" {
+			return addr, true
+		}
+		return parseAddr(strings.TrimSpace(realIP))
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(hops[i])
+		if !ok {
+			return netip.Addr{}, false
+		}
+		addr = hop
+		if !r.trusted(addr) {
+			break
+		}
+	}
+	return addr, true
+}
+
+// trusted 信頼するプロキシのアドレスかどうか
+func (r *Resolver) trusted(addr netip.Addr) bool {
+	return containsAddr(r.trustedProxies, addr)
+}
+
+// parseRemoteAddr 接続元のアドレス（"host:port"またはIPアドレス）を解析する
+func parseRemoteAddr(s string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(s); err == nil {
+		return normalize(addrPort.Addr()), true
+	}
+	return parseAddr(s)
+}
+
+// parseAddr IPアドレスを解析する
+func parseAddr(s string) (netip.Addr, bool) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return normalize(addr), true
+}
+
+// normalize IPv4射影アドレスをIPv4に変換し、ゾーンを取り除く
+func normalize(addr netip.Addr) netip.Addr {
+	return addr.Unmap().WithZone("")
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
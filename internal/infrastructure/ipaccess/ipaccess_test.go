@@ -0,0 +1,174 @@
+package ipaccess
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrefixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "正常系: IPアドレスとCIDR",
+			entries: []string{"127.0.0.1", "10.0.0.0/8", "2001:db8::/32", "::1"},
+			want:    []string{"127.0.0.1/32", "10.0.0.0/8", "2001:db8::/32", "::1/128"},
+		},
+		{
+			name:    "正常系: ホスト部を切り捨てる",
+			entries: []string{"10.1.2.3/8"},
+			want:    []string{"10.0.0.0/8"},
+		},
+		{
+			name:    "正常系: IPv4射影アドレスはIPv4として扱う",
+			entries: []string{"::ffff:192.0.2.1"},
+			want:    []string{"192.0.2.1/32"},
+		},
+		{
+			name:    "異常系: 不正なIPアドレス",
+			entries: []string{"10.0.0"},
+			wantErr: true,
+		},
+		{
+			name:    "異常系: 不正なプレフィックス長",
+			entries: []string{"10.0.0.0/33"},
+			wantErr: true,
+		},
+		{
+			name:    "異常系: ホスト名",
+			entries: []string{"localhost"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes, err := ParsePrefixes(tt.entries)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got := make([]string, 0, len(prefixes))
+			for _, prefix := range prefixes {
+				got = append(got, prefix.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAllowlist_Allows(t *testing.T) {
+	allowlist, err := NewAllowlist([]string{"10.0.0.0/8", "1.2.3.4/32", "2001:db8::/32"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "正常系: CIDRの範囲内", ip: "10.1.2.3", want: true},
+		{name: "正常系: /32と一致", ip: "1.2.3.4", want: true},
+		{name: "正常系: IPv6のCIDRの範囲内", ip: "2001:db8:1::5", want: true},
+		{name: "正常系: IPv4射影アドレス", ip: "::ffff:10.1.2.3", want: true},
+		{name: "異常系: 前方一致するだけのアドレス", ip: "1.2.3.45", want: false},
+		{name: "異常系: CIDRの範囲外", ip: "11.0.0.1", want: false},
+		{name: "異常系: IPv6のCIDRの範囲外", ip: "2001:db9::1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, allowlist.Allows(netip.MustParseAddr(tt.ip)))
+		})
+	}
+
+	t.Run("正常系: エントリがない場合はすべて許可", func(t *testing.T) {
+		empty, err := NewAllowlist(nil)
+		require.NoError(t, err)
+		assert.False(t, empty.Restricted())
+		assert.True(t, empty.Allows(netip.MustParseAddr("203.0.113.1")))
+	})
+}
+
+func TestResolver_ClientIP(t *testing.T) {
+	resolver, err := NewResolver([]string{"10.0.0.0/8", "fd00::/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		want         string
+		wantOK       bool
+	}{
+		{
+			name:       "正常系: 転送ヘッダーがない場合は接続元",
+			remoteAddr: "198.51.100.7:50000",
+			want:       "198.51.100.7",
+			wantOK:     true,
+		},
+		{
+			name:         "正常系: 信頼しない接続元の転送ヘッダーは無視する",
+			remoteAddr:   "198.51.100.7:50000",
+			forwardedFor: []string{"10.1.2.3"},
+			realIP:       "10.1.2.3",
+			want:         "198.51.100.7",
+			wantOK:       true,
+		},
+		{
+			name:         "正常系: 信頼するプロキシを読み飛ばす",
+			remoteAddr:   "10.0.0.2:443",
+			forwardedFor: []string{"192.0.2.99, 203.0.113.5", "10.0.0.1"},
+			want:         "203.0.113.5",
+			wantOK:       true,
+		},
+		{
+			name:         "正常系: すべて信頼するプロキシの場合は最も遠いアドレス",
+			remoteAddr:   "10.0.0.2:443",
+			forwardedFor: []string{"10.0.0.5, 10.0.0.1"},
+			want:         "10.0.0.5",
+			wantOK:       true,
+		},
+		{
+			name:       "正常系: 信頼するプロキシのX-Real-IP",
+			remoteAddr: "[fd00::1]:443",
+			realIP:     "2001:db8::7",
+			want:       "2001:db8::7",
+			wantOK:     true,
+		},
+		{
+			name:       "正常系: IPv6の接続元",
+			remoteAddr: "[2001:db8::1]:50000",
+			want:       "2001:db8::1",
+			wantOK:     true,
+		},
+		{
+			name:         "異常系: 不正な転送ヘッダー",
+			remoteAddr:   "10.0.0.2:443",
+			forwardedFor: []string{"unknown"},
+			wantOK:       false,
+		},
+		{
+			name:       "異常系: 接続元のアドレスが不明",
+			remoteAddr: "bufconn",
+			wantOK:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolver.ClientIP(tt.remoteAddr, tt.forwardedFor, tt.realIP)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWK 検証用の公開鍵（RFC 7517 JSON Web Key）
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`   // RSAのモジュラス
+	E         string `json:"e,omitempty"`   // RSAの公開指数
+	Curve     string `json:"crv,omitempty"` // EC/OKPの曲線
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// toJWK 署名鍵の公開鍵をJWKに変換する
+func toJWK(key *signingKey) JWK {
+	jwk := JWK{
+		KeyID:     key.id,
+		Use:       "sig",
+		Algorithm: key.method.Alg(),
+	}
+
+	switch pub := key.private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeSegment(pub.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		// 非圧縮形式（0x04 || X || Y）から座標を取り出す
+		ecdhKey, err := pub.ECDH()
+		if err != nil {
+			return jwk
+		}
+		point := ecdhKey.Bytes()
+		size := (len(point) - 1) / 2
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = encodeSegment(point[1 : 1+size])
+		jwk.Y = encodeSegment(point[1+size:])
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeSegment(pub)
+	}
+
+	return jwk
+}
+
+// verificationKey JWKから復元した検証用の公開鍵
+type verificationKey struct {
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+// parseJWK JWKを検証用の公開鍵に変換する
+// algが指定されていない場合は鍵の種類から決める
+func parseJWK(jwk JWK) (*verificationKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeSegment(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeSegment(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if public.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		method, err := methodForJWK(jwk.Algorithm, "RS256", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512")
+		if err != nil {
+			return nil, err
+		}
+		return &verificationKey{method: method, public: public}, nil
+
+	case "EC":
+		var (
+			curve    elliptic.Curve
+			ecdhKind ecdh.Curve
+			alg      string
+		)
+		switch jwk.Curve {
+		case "P-256":
+			curve, ecdhKind, alg = elliptic.P256(), ecdh.P256(), "ES256"
+		case "P-384":
+			curve, ecdhKind, alg = elliptic.P384(), ecdh.P384(), "ES384"
+		case "P-521":
+			curve, ecdhKind, alg = elliptic.P521(), ecdh.P521(), "ES512"
+		default:
+			return nil, fmt.Errorf("unsupported EC curve: %s", jwk.Curve)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		x, errX := decodeSegment(jwk.X)
+		y, errY := decodeSegment(jwk.Y)
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, fmt.Errorf("invalid EC coordinates")
+		}
+		// 曲線上の点であることを確認する
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdhKind.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("invalid EC public key: %w", err)
+		}
+		method, err := methodForJWK(jwk.Algorithm, alg, alg)
+		if err != nil {
+			return nil, err
+		}
+		public := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return &verificationKey{method: method, public: public}, nil
+
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported OKP curve: %s", jwk.Curve)
+		}
+		x, err := decodeSegment(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 public key")
+		}
+		method, err := methodForJWK(jwk.Algorithm, "EdDSA", "EdDSA")
+		if err != nil {
+			return nil, err
+		}
+		return &verificationKey{method: method, public: ed25519.PublicKey(x)}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", jwk.KeyType)
+	}
+}
+
+// methodForJWK JWKのalgを署名アルゴリズムに変換する（空の場合はdefaultAlg）
+func methodForJWK(alg, defaultAlg string, allowed ...string) (jwt.SigningMethod, error) {
+	if alg == "" {
+		alg = defaultAlg
+	}
+	for _, a := range allowed {
+		if a == alg {
+			return jwt.GetSigningMethod(alg), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported algorithm %q for key type", alg)
+}
+
+// encodeSegment パディングなしのbase64url形式にエンコードする
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeSegment パディングなしのbase64url形式をデコードする
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,196 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"gem-server/internal/infrastructure/config"
+)
+
+var (
+	// ErrNoSigningKeys 署名鍵が1つも見つからない
+	ErrNoSigningKeys = errors.New("no signing keys found")
+	// ErrUnknownKey トークンのkidまたはアルゴリズムに対応する検証鍵がない
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+// signingKey kidで識別される署名鍵
+type signingKey struct {
+	id          string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	activatesAt time.Time // この日時以降に署名に使い始める
+}
+
+// Keyring アクセストークン（JWT）の署名と検証に使う鍵の集合
+// 署名鍵を設定した場合はRS256/ES256/EdDSAで署名してヘッダーにkidを付与し、
+// 設定しない場合はJWT_SECRETを使ったHS256で署名する
+type Keyring struct {
+	files           []string
+	dir             string
+	activationDelay time.Duration
+	secret          []byte // HS256の検証鍵（受け付けない場合はnil）
+	validMethods    []string
+	now             func() time.Time
+
+	mu   sync.RWMutex
+	keys map[string]*signingKey
+}
+
+// NewKeyring JWT設定から新しいKeyringを作成
+// 署名鍵を設定した場合は読み込みに失敗するとエラーを返す
+func NewKeyring(cfg *config.JWTConfig) (*Keyring, error) {
+	k := &Keyring{
+		files:           cfg.SigningKeyFiles,
+		dir:             cfg.SigningKeysDir,
+		activationDelay: cfg.KeyActivationDelay,
+		now:             time.Now,
+	}
+
+	if !cfg.UsesSigningKeys() || cfg.AcceptHS256 {
+		k.secret = []byte(cfg.Secret)
+		k.validMethods = append(k.validMethods, jwt.SigningMethodHS256.Alg())
+	}
+	if !cfg.UsesSigningKeys() {
+		return k, nil
+	}
+
+	k.validMethods = append(k.validMethods,
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodES256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	)
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// UsesSigningKeys 非対称鍵で署名しているかどうか
+func (k *Keyring) UsesSigningKeys() bool {
+	return len(k.files) > 0 || k.dir != ""
+}
+
+// Reload 署名鍵をファイルから読み込み直す
+// 追加されたファイルは検証鍵として公開され、削除されたファイルの鍵では検証できなくなる
+// 読み込みに失敗した場合は現在の鍵を維持してエラーを返す
+func (k *Keyring) Reload() error {
+	if !k.UsesSigningKeys() {
+		return nil
+	}
+
+	keys, err := loadKeys(k.files, k.dir, k.activationDelay)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+	return nil
+}
+
+// Sign クレームに署名したJWTを返す
+func (k *Keyring) Sign(claims jwt.Claims) (string, error) {
+	if !k.UsesSigningKeys() {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(k.secret)
+	}
+
+	key := k.currentKey()
+	if key == nil {
+		return "", ErrNoSigningKeys
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// SigningKeyID 現在署名に使っている鍵のkidを返す（HS256の場合は空文字列）
+func (k *Keyring) SigningKeyID() string {
+	if key := k.currentKey(); key != nil {
+		return key.id
+	}
+	return ""
+}
+
+// Keyfunc jwt.Parseに渡す検証鍵の選択関数
+// HS256は受け付ける設定の場合のみ、それ以外はkidに対応する鍵とアルゴリズムが一致する場合のみ検証鍵を返す
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if alg == jwt.SigningMethodHS256.Alg() {
+		if k.secret == nil {
+			return nil, fmt.Errorf("%w: HS256 tokens are not accepted", ErrUnknownKey)
+		}
+		return k.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownKey, kid)
+	}
+	if key.method.Alg() != alg {
+		return nil, fmt.Errorf("%w: kid %q does not use %s", ErrUnknownKey, kid, alg)
+	}
+	return key.private.Public(), nil
+}
+
+// ValidMethods 検証時に受け付ける署名アルゴリズム
+func (k *Keyring) ValidMethods() []string {
+	return append([]string(nil), k.validMethods...)
+}
+
+// PublicKeys 検証用の公開鍵をkidの順に返す
+// 署名に使い始める前の鍵も含めることで、検証側が事前に取得できるようにする
+func (k *Keyring) PublicKeys() []JWK {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	jwks := make([]JWK, 0, len(k.keys))
+	for _, key := range k.keys {
+		jwks = append(jwks, toJWK(key))
+	}
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].KeyID < jwks[j].KeyID })
+	return jwks
+}
+
+// currentKey 署名に使う鍵を選ぶ
+// 使用開始済みの鍵のうち最も新しいものを使い、使用開始済みの鍵がない場合は最も早く使用開始になる鍵を使う
+func (k *Keyring) currentKey() *signingKey {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := k.now()
+	var active, pending *signingKey
+	for _, key := range k.keys {
+		if !key.activatesAt.After(now) {
+			if active == nil || newer(key, active) {
+				active = key
+			}
+			continue
+		}
+		if pending == nil || newer(pending, key) {
+			pending = key
+		}
+	}
+	if active != nil {
+		return active
+	}
+	return pending
+}
+
+// newer aがbより後に使用開始になるかどうか（同時の場合はkidの大きい方）
+func newer(a, b *signingKey) bool {
+	if a.activatesAt.Equal(b.activatesAt) {
+		return a.id > b.id
+	}
+	return a.activatesAt.After(b.activatesAt)
+}
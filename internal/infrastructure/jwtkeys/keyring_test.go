@@ -0,0 +1,293 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/infrastructure/config"
+)
+
+// writeKey 秘密鍵をPKCS#8のPEMファイルとして書き込み、更新日時を設定する
+func writeKey(t *testing.T, dir, name string, key crypto.Signer, modTime time.Time) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+	return path
+}
+
+func generateRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func generateECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func generateEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func parse(k *Keyring, tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, k.Keyfunc, jwt.WithValidMethods(k.ValidMethods()))
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"user_id": "user123",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestKeyring_SignAndVerify(t *testing.T) {
+	old := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		key     crypto.Signer
+		wantAlg string
+		wantKty string
+	}{
+		{name: "正常系: RSA鍵はRS256で署名する", key: generateRSAKey(t), wantAlg: "RS256", wantKty: "RSA"},
+		{name: "正常系: P-256鍵はES256で署名する", key: generateECKey(t, elliptic.P256()), wantAlg: "ES256", wantKty: "EC"},
+		{name: "正常系: Ed25519鍵はEdDSAで署名する", key: generateEd25519Key(t), wantAlg: "EdDSA", wantKty: "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeKey(t, t.TempDir(), "key-1.pem", tt.key, old)
+
+			k, err := NewKeyring(&config.JWTConfig{SigningKeyFiles: []string{path}})
+			require.NoError(t, err)
+
+			tokenString, err := k.Sign(testClaims())
+			require.NoError(t, err)
+
+			token, err := parse(k, tokenString)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAlg, token.Method.Alg())
+			assert.Equal(t, "key-1", token.Header["kid"])
+			assert.Equal(t, "key-1", k.SigningKeyID())
+
+			jwks := k.PublicKeys()
+			require.Len(t, jwks, 1)
+			assert.Equal(t, "key-1", jwks[0].KeyID)
+			assert.Equal(t, tt.wantAlg, jwks[0].Algorithm)
+			assert.Equal(t, tt.wantKty, jwks[0].KeyType)
+			assert.Equal(t, "sig", jwks[0].Use)
+		})
+	}
+}
+
+func TestNewKeyring(t *testing.T) {
+	old := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, dir string) *config.JWTConfig
+		wantErr bool
+	}{
+		{
+			name: "正常系: 署名鍵がない場合はHS256で署名する",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				return &config.JWTConfig{Secret: "test-secret"}
+			},
+		},
+		{
+			name: "正常系: ディレクトリの*.pemファイルを読み込む",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				writeKey(t, dir, "2024-05.pem", generateECKey(t, elliptic.P256()), old)
+				writeKey(t, dir, "2024-06.pem", generateEd25519Key(t), old)
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("keys"), 0o600))
+				return &config.JWTConfig{SigningKeysDir: dir}
+			},
+		},
+		{
+			name: "異常系: ディレクトリに署名鍵がない",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				return &config.JWTConfig{SigningKeysDir: dir}
+			},
+			wantErr: true,
+		},
+		{
+			name: "異常系: ファイルが存在しない",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				return &config.JWTConfig{SigningKeyFiles: []string{filepath.Join(dir, "missing.pem")}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "異常系: PEM形式ではない",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				path := filepath.Join(dir, "broken.pem")
+				require.NoError(t, os.WriteFile(path, []byte("not a key"), 0o600))
+				return &config.JWTConfig{SigningKeyFiles: []string{path}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "異常系: P-256以外の曲線",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				path := writeKey(t, dir, "p384.pem", generateECKey(t, elliptic.P384()), old)
+				return &config.JWTConfig{SigningKeyFiles: []string{path}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "異常系: kidが重複している",
+			setup: func(t *testing.T, dir string) *config.JWTConfig {
+				sub := filepath.Join(dir, "sub")
+				require.NoError(t, os.Mkdir(sub, 0o700))
+				path := writeKey(t, sub, "key-1.pem", generateEd25519Key(t), old)
+				writeKey(t, dir, "key-1.pem", generateEd25519Key(t), old)
+				return &config.JWTConfig{SigningKeyFiles: []string{path}, SigningKeysDir: dir}
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := NewKeyring(tt.setup(t, t.TempDir()))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			tokenString, err := k.Sign(testClaims())
+			require.NoError(t, err)
+			_, err = parse(k, tokenString)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestKeyring_HS256Migration(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "key-1.pem", generateEd25519Key(t), time.Now().Add(-24*time.Hour))
+
+	hs256Token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+
+	t.Run("正常系: JWT_ACCEPT_HS256の場合は移行前のトークンを受け付ける", func(t *testing.T) {
+		k, err := NewKeyring(&config.JWTConfig{Secret: "test-secret", SigningKeysDir: dir, AcceptHS256: true})
+		require.NoError(t, err)
+
+		_, err = parse(k, hs256Token)
+		assert.NoError(t, err)
+
+		// 署名には非対称鍵を使う
+		tokenString, err := k.Sign(testClaims())
+		require.NoError(t, err)
+		token, err := parse(k, tokenString)
+		require.NoError(t, err)
+		assert.Equal(t, "EdDSA", token.Method.Alg())
+	})
+
+	t.Run("異常系: JWT_ACCEPT_HS256でない場合はHS256のトークンを拒否する", func(t *testing.T) {
+		k, err := NewKeyring(&config.JWTConfig{Secret: "test-secret", SigningKeysDir: dir})
+		require.NoError(t, err)
+
+		_, err = parse(k, hs256Token)
+		assert.Error(t, err)
+	})
+
+	t.Run("異常系: kidとアルゴリズムが一致しないトークンを拒否する", func(t *testing.T) {
+		k, err := NewKeyring(&config.JWTConfig{Secret: "test-secret", SigningKeysDir: dir, AcceptHS256: true})
+		require.NoError(t, err)
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+		token.Header["kid"] = "key-1"
+		tokenString, err := token.SignedString([]byte("wrong-secret"))
+		require.NoError(t, err)
+
+		_, err = parse(k, tokenString)
+		assert.Error(t, err)
+	})
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeKey(t, dir, "2024-05.pem", generateEd25519Key(t), now.Add(-24*time.Hour))
+
+	k, err := NewKeyring(&config.JWTConfig{SigningKeysDir: dir, KeyActivationDelay: 15 * time.Minute})
+	require.NoError(t, err)
+	k.now = func() time.Time { return now }
+
+	oldToken, err := k.Sign(testClaims())
+	require.NoError(t, err)
+
+	// 新しい鍵を追加すると公開されるが、使用開始までは古い鍵で署名する
+	writeKey(t, dir, "2024-06.pem", generateRSAKey(t), now)
+	require.NoError(t, k.Reload())
+
+	assert.Equal(t, "2024-05", k.SigningKeyID())
+	jwks := k.PublicKeys()
+	require.Len(t, jwks, 2)
+	assert.Equal(t, "2024-05", jwks[0].KeyID)
+	assert.Equal(t, "2024-06", jwks[1].KeyID)
+
+	// 使用開始後は新しい鍵で署名し、古い鍵で署名したトークンも検証できる
+	now = now.Add(15 * time.Minute)
+	assert.Equal(t, "2024-06", k.SigningKeyID())
+
+	newToken, err := k.Sign(testClaims())
+	require.NoError(t, err)
+	token, err := parse(k, newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06", token.Header["kid"])
+	_, err = parse(k, oldToken)
+	assert.NoError(t, err)
+
+	// 古い鍵を削除すると、その鍵で署名したトークンは検証できなくなる
+	require.NoError(t, os.Remove(filepath.Join(dir, "2024-05.pem")))
+	require.NoError(t, k.Reload())
+
+	_, err = parse(k, oldToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = parse(k, newToken)
+	assert.NoError(t, err)
+
+	// 読み込みに失敗した場合は現在の鍵を維持する
+	require.NoError(t, os.Remove(filepath.Join(dir, "2024-06.pem")))
+	assert.ErrorIs(t, k.Reload(), ErrNoSigningKeys)
+	_, err = parse(k, newToken)
+	assert.NoError(t, err)
+}
+
+func TestKeyring_PendingKeyOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeKey(t, dir, "key-1.pem", generateEd25519Key(t), time.Now())
+
+	// 使用開始済みの鍵がない場合（初回のデプロイ）も署名できる
+	k, err := NewKeyring(&config.JWTConfig{SigningKeysDir: dir, KeyActivationDelay: time.Hour})
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", k.SigningKeyID())
+}
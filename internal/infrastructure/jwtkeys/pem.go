@@ -0,0 +1,140 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits RS256で受け付けるRSA鍵の最小ビット長
+const minRSAKeyBits = 2048
+
+// pemExt 署名鍵ディレクトリから読み込むファイルの拡張子
+const pemExt = ".pem"
+
+// loadKeys ファイルとディレクトリから署名鍵を読み込む
+// kidはファイル名から拡張子を除いたもの。鍵はファイルの更新日時からactivationDelay経過後に署名に使われる
+func loadKeys(files []string, dir string, activationDelay time.Duration) (map[string]*signingKey, error) {
+	paths := append([]string(nil), files...)
+	if dir != "" {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signing keys directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), pemExt) {
+				continue
+			}
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+
+	keys := make(map[string]*signingKey, len(paths))
+	for _, path := range paths {
+		key, err := loadKey(path, activationDelay)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := keys[key.id]; ok {
+			return nil, fmt.Errorf("duplicate signing key id %q: %s", key.id, path)
+		}
+		keys[key.id] = key
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+	return keys, nil
+}
+
+// loadKey PEMファイルから署名鍵を1つ読み込む
+func loadKey(path string, activationDelay time.Duration) (*signingKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat signing key: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	private, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+	method, err := signingMethodFor(private)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+
+	return &signingKey{
+		id:          strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		method:      method,
+		private:     private,
+		activatesAt: info.ModTime().Add(activationDelay),
+	}, nil
+}
+
+// parsePrivateKey PEM形式の秘密鍵（PKCS#8、PKCS#1、SEC 1）をパースする
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var (
+		key interface{}
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+	return signer, nil
+}
+
+// signingMethodFor 秘密鍵の種類から署名アルゴリズムを決める
+func signingMethodFor(key crypto.Signer) (jwt.SigningMethod, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ECDSA key must use the P-256 curve")
+		}
+		return jwt.SigningMethodES256, nil
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type: %T", key)
+	}
+}
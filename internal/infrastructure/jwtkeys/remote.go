@@ -0,0 +1,174 @@
+package jwtkeys
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// remoteFetchTimeout JWKSの取得のタイムアウト
+	remoteFetchTimeout = 5 * time.Second
+	// remoteRefreshInterval 不明なkidや取得失敗による再取得の最短間隔
+	remoteRefreshInterval = 30 * time.Second
+	// maxJWKSBytes JWKSのレスポンスとして受け付ける最大サイズ
+	maxJWKSBytes = 1 << 20
+)
+
+// RemoteKeySet URLから取得したJWKSをキャッシュして検証鍵を選ぶ
+// キャッシュの期限切れ時と不明なkidのトークンを受け取った時に取得し直す（不明なkidによる取得は間隔を制限する）
+// 取得に失敗した場合はキャッシュ済みの鍵を使い続ける。
+// 取得はロックの外で行い、同時に取得が必要になった呼び出しは1回の取得を共有する
+type RemoteKeySet struct {
+	url      string
+	client   *http.Client
+	cacheTTL time.Duration
+	now      func() time.Time
+	group    singleflight.Group
+
+	mu          sync.Mutex
+	keys        map[string]*verificationKey
+	fetchedAt   time.Time // 最後に取得に成功した日時
+	attemptedAt time.Time // 最後に取得を試みた日時
+	fetching    bool      // 取得中かどうか（取得中に必要になった呼び出しは取得の完了を待つ）
+}
+
+// NewRemoteKeySet 新しいRemoteKeySetを作成
+func NewRemoteKeySet(url string, cacheTTL time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:      url,
+		client:   &http.Client{Timeout: remoteFetchTimeout},
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+// Keyfunc jwt.Parseに渡す検証鍵の選択関数
+// kidに対応する鍵とアルゴリズムが一致する場合のみ検証鍵を返す
+func (r *RemoteKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("%w: token has no kid", ErrUnknownKey)
+	}
+
+	key, err := r.key(kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("%w: kid %q does not use %s", ErrUnknownKey, kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+// key kidに対応する検証鍵を返す（必要に応じてJWKSを取得し直す）
+func (r *RemoteKeySet) key(kid string) (*verificationKey, error) {
+	key, ok, refresh := r.cached(kid)
+	if refresh {
+		// 取得中は他の呼び出し（キャッシュ済みの鍵での検証）をブロックしない
+		_, err, _ := r.group.Do("jwks", func() (interface{}, error) {
+			return nil, r.refresh()
+		})
+
+		r.mu.Lock()
+		keys := r.keys
+		key, ok = keys[kid]
+		r.mu.Unlock()
+
+		if err != nil && keys == nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// cached キャッシュ済みの検証鍵と、JWKSを取得し直すべきかどうかを返す
+func (r *RemoteKeySet) cached(kid string) (*verificationKey, bool, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	key, ok := r.keys[kid]
+	expired := r.keys == nil || now.Sub(r.fetchedAt) >= r.cacheTTL
+	refresh := (expired || !ok) && (r.fetching || now.Sub(r.attemptedAt) >= remoteRefreshInterval)
+	return key, ok, refresh
+}
+
+// refresh JWKSを取得してキャッシュを置き換える
+// 直前に他の呼び出しが取得を試みている場合は取得しない（不明なkidのトークンによる取得の間隔を制限する）
+func (r *RemoteKeySet) refresh() error {
+	r.mu.Lock()
+	now := r.now()
+	if now.Sub(r.attemptedAt) < remoteRefreshInterval {
+		r.mu.Unlock()
+		return nil
+	}
+	r.attemptedAt = now
+	r.fetching = true
+	r.mu.Unlock()
+
+	keys, err := r.fetch()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fetching = false
+	if err != nil {
+		return err
+	}
+	r.keys = keys
+	r.fetchedAt = now
+	return nil
+}
+
+// fetch JWKSを取得して検証鍵に変換する
+// 対応していない鍵や署名用でない鍵は無視する
+func (r *RemoteKeySet) fetch() (map[string]*verificationKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*verificationKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.KeyID == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := parseJWK(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,242 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/elliptic"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jwksServer テスト用のJWKSを配信するサーバー
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	keys     []JWK
+	status   int
+	release  chan struct{} // nilでない場合は閉じられるまでレスポンスを返さない
+	requests atomic.Int32
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		s.mu.Lock()
+		release := s.release
+		s.mu.Unlock()
+		if release != nil {
+			<-release
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.status != http.StatusOK {
+			w.WriteHeader(s.status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string][]JWK{"keys": s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// publish 署名鍵の公開鍵を配信する
+func (s *jwksServer) publish(keys ...*signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = nil
+	for _, key := range keys {
+		s.keys = append(s.keys, toJWK(key))
+	}
+}
+
+// hold 返されたチャネルが閉じられるまでレスポンスを返さないようにする
+func (s *jwksServer) hold() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.release = make(chan struct{})
+	return s.release
+}
+
+func (s *jwksServer) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func newSigningKey(t *testing.T, kid string, private crypto.Signer) *signingKey {
+	t.Helper()
+	method, err := signingMethodFor(private)
+	require.NoError(t, err)
+	return &signingKey{id: kid, method: method, private: private}
+}
+
+func signWith(t *testing.T, key *signingKey) string {
+	t.Helper()
+	token := jwt.NewWithClaims(key.method, testClaims())
+	token.Header["kid"] = key.id
+	tokenString, err := token.SignedString(key.private)
+	require.NoError(t, err)
+	return tokenString
+}
+
+func TestParseJWK(t *testing.T) {
+	tests := []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{name: "正常系: RSA", key: generateRSAKey(t)},
+		{name: "正常系: EC P-256", key: generateECKey(t, elliptic.P256())},
+		{name: "正常系: Ed25519", key: generateEd25519Key(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := newSigningKey(t, "key-1", tt.key)
+
+			parsed, err := parseJWK(toJWK(key))
+			require.NoError(t, err)
+			assert.Equal(t, key.method.Alg(), parsed.method.Alg())
+			assert.True(t, key.private.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(parsed.public))
+		})
+	}
+
+	t.Run("異常系: 鍵の種類とalgが一致しない", func(t *testing.T) {
+		jwk := toJWK(newSigningKey(t, "key-1", generateEd25519Key(t)))
+		jwk.Algorithm = "RS256"
+		_, err := parseJWK(jwk)
+		assert.Error(t, err)
+	})
+
+	t.Run("異常系: 曲線上にない点", func(t *testing.T) {
+		jwk := toJWK(newSigningKey(t, "key-1", generateECKey(t, elliptic.P256())))
+		jwk.Y = jwk.X
+		_, err := parseJWK(jwk)
+		assert.Error(t, err)
+	})
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	key1 := newSigningKey(t, "key-1", generateEd25519Key(t))
+	key2 := newSigningKey(t, "key-2", generateRSAKey(t))
+
+	parseRemote := func(r *RemoteKeySet, tokenString string) error {
+		_, err := jwt.Parse(tokenString, r.Keyfunc, jwt.WithValidMethods([]string{"RS256", "EdDSA"}))
+		return err
+	}
+
+	t.Run("正常系: 取得したJWKSをキャッシュする", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.publish(key1)
+		r := NewRemoteKeySet(server.URL, time.Hour)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, parseRemote(r, signWith(t, key1)))
+		}
+		assert.Equal(t, int32(1), server.requests.Load())
+	})
+
+	t.Run("正常系: 不明なkidの場合は取得し直す（間隔を制限する）", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.publish(key1)
+		now := time.Now()
+		r := NewRemoteKeySet(server.URL, time.Hour)
+		r.now = func() time.Time { return now }
+
+		require.NoError(t, parseRemote(r, signWith(t, key1)))
+
+		// プロバイダーが鍵をローテーションした直後は再取得しない
+		server.publish(key1, key2)
+		assert.ErrorIs(t, parseRemote(r, signWith(t, key2)), ErrUnknownKey)
+		assert.Equal(t, int32(1), server.requests.Load())
+
+		now = now.Add(remoteRefreshInterval)
+		require.NoError(t, parseRemote(r, signWith(t, key2)))
+		assert.Equal(t, int32(2), server.requests.Load())
+	})
+
+	t.Run("正常系: 取得中もキャッシュ済みの鍵で検証でき、同時の取得は1回にまとめる", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.publish(key1)
+		now := time.Now()
+		r := NewRemoteKeySet(server.URL, time.Hour)
+		r.now = func() time.Time { return now }
+
+		require.NoError(t, parseRemote(r, signWith(t, key1)))
+
+		server.publish(key1, key2)
+		release := server.hold()
+		now = now.Add(remoteRefreshInterval)
+
+		// 不明なkidのトークンを同時に検証すると、1回の取得の完了を待つ
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = parseRemote(r, signWith(t, key2))
+			}(i)
+		}
+		require.Eventually(t, func() bool { return server.requests.Load() == 2 }, time.Second, 5*time.Millisecond)
+
+		// 取得中でもキャッシュ済みの鍵のトークンは待たずに検証できる
+		done := make(chan error, 1)
+		go func() { done <- parseRemote(r, signWith(t, key1)) }()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("verification with a cached key blocked on the JWKS fetch")
+		}
+
+		close(release)
+		wg.Wait()
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), server.requests.Load())
+	})
+
+	t.Run("正常系: 取得に失敗した場合はキャッシュ済みの鍵を使い続ける", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.publish(key1)
+		now := time.Now()
+		r := NewRemoteKeySet(server.URL, time.Minute)
+		r.now = func() time.Time { return now }
+
+		require.NoError(t, parseRemote(r, signWith(t, key1)))
+
+		server.setStatus(http.StatusInternalServerError)
+		now = now.Add(time.Hour)
+		require.NoError(t, parseRemote(r, signWith(t, key1)))
+		assert.Equal(t, int32(2), server.requests.Load())
+	})
+
+	t.Run("異常系: JWKSを取得できない", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.setStatus(http.StatusServiceUnavailable)
+		r := NewRemoteKeySet(server.URL, time.Hour)
+
+		assert.Error(t, parseRemote(r, signWith(t, key1)))
+	})
+
+	t.Run("異常系: kidとアルゴリズムが一致しない", func(t *testing.T) {
+		server := newJWKSServer(t)
+		server.publish(key1)
+		r := NewRemoteKeySet(server.URL, time.Hour)
+
+		forged := newSigningKey(t, "key-1", generateRSAKey(t))
+		assert.ErrorIs(t, parseRemote(r, signWith(t, forged)), ErrUnknownKey)
+	})
+}
@@ -0,0 +1,109 @@
+package lockout
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gem-server/internal/domain/redemption_code"
+)
+
+// sweepInterval 不要になった記録を破棄する間隔
+const sweepInterval = time.Minute
+
+// failureRecord ユーザーごとの失敗の記録
+type failureRecord struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// expired 失敗回数の期間もロックアウトも過ぎているか
+func (r *failureRecord) expired(now time.Time, window time.Duration) bool {
+	return !now.Before(r.lockedUntil) && now.Sub(r.windowStart) >= window
+}
+
+// MemoryFailureTracker プロセス内で失敗回数を管理するFailureTracker
+// 複数インスタンスで実行する場合は失敗回数とロックアウトの解除がインスタンスごとになる
+type MemoryFailureTracker struct {
+	policy    redemption_code.LockoutPolicy
+	now       func() time.Time
+	mu        sync.Mutex
+	records   map[string]*failureRecord
+	lastSweep time.Time
+}
+
+// NewMemoryFailureTracker 新しいMemoryFailureTrackerを作成
+func NewMemoryFailureTracker(policy redemption_code.LockoutPolicy) *MemoryFailureTracker {
+	return &MemoryFailureTracker{
+		policy:  policy,
+		now:     time.Now,
+		records: make(map[string]*failureRecord),
+	}
+}
+
+// LockedUntil ロックアウトの解除日時を返す（ロックアウト中でなければゼロ値）
+func (t *MemoryFailureTracker) LockedUntil(ctx context.Context, userID string) (time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.records[userID]
+	if !ok || !t.now().Before(r.lockedUntil) {
+		return time.Time{}, nil
+	}
+	return r.lockedUntil, nil
+}
+
+// RecordFailure 失敗を記録する。この失敗でロックアウトされた場合は解除日時を返す
+func (t *MemoryFailureTracker) RecordFailure(ctx context.Context, userID string) (time.Time, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+
+	// 期間を過ぎていれば数え直す
+	r, ok := t.records[userID]
+	if !ok || r.expired(now, t.policy.Window) {
+		r = &failureRecord{windowStart: now}
+		t.records[userID] = r
+	}
+
+	// ロックアウト中の失敗は数えない
+	if now.Before(r.lockedUntil) {
+		return time.Time{}, nil
+	}
+
+	r.failures++
+	if r.failures < t.policy.MaxFailures {
+		return time.Time{}, nil
+	}
+
+	r.failures = 0
+	r.windowStart = now
+	r.lockedUntil = now.Add(t.policy.Duration)
+	return r.lockedUntil, nil
+}
+
+// Clear 失敗の記録とロックアウトを解除する
+func (t *MemoryFailureTracker) Clear(ctx context.Context, userID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.records, userID)
+	return nil
+}
+
+// sweep 期間もロックアウトも過ぎた記録を破棄する
+func (t *MemoryFailureTracker) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < sweepInterval {
+		return
+	}
+	t.lastSweep = now
+
+	for userID, r := range t.records {
+		if r.expired(now, t.policy.Window) {
+			delete(t.records, userID)
+		}
+	}
+}
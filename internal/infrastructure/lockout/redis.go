@@ -0,0 +1,92 @@
+package lockout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"gem-server/internal/domain/redemption_code"
+)
+
+// recordFailureScript 失敗回数を加算し、上限に達した場合はロックアウトする
+// ロックアウトされた場合はその期間（ミリ秒）、それ以外は0を返す
+var recordFailureScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 1 then
+  return 0
+end
+local failures = redis.call('INCR', KEYS[1])
+if failures == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+if failures < tonumber(ARGV[1]) then
+  return 0
+end
+redis.call('DEL', KEYS[1])
+redis.call('SET', KEYS[2], '1', 'PX', ARGV[3])
+return tonumber(ARGV[3])
+`)
+
+// RedisFailureTracker Redisで失敗回数を管理するFailureTracker
+// 複数インスタンス間で失敗回数とロックアウトを共有する
+type RedisFailureTracker struct {
+	client *redis.Client
+	policy redemption_code.LockoutPolicy
+}
+
+// NewRedisFailureTracker 新しいRedisFailureTrackerを作成
+func NewRedisFailureTracker(client *redis.Client, policy redemption_code.LockoutPolicy) *RedisFailureTracker {
+	return &RedisFailureTracker{
+		client: client,
+		policy: policy,
+	}
+}
+
+// LockedUntil ロックアウトの解除日時を返す（ロックアウト中でなければゼロ値）
+func (t *RedisFailureTracker) LockedUntil(ctx context.Context, userID string) (time.Time, error) {
+	ttl, err := t.client.PTTL(ctx, lockedKey(userID)).Result()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get redemption lockout: %w", err)
+	}
+	// キーが存在しない場合は負の値が返る
+	if ttl <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(ttl), nil
+}
+
+// RecordFailure 失敗を記録する。この失敗でロックアウトされた場合は解除日時を返す
+func (t *RedisFailureTracker) RecordFailure(ctx context.Context, userID string) (time.Time, error) {
+	lockedMillis, err := recordFailureScript.Run(ctx, t.client,
+		[]string{failuresKey(userID), lockedKey(userID)},
+		t.policy.MaxFailures,
+		t.policy.Window.Milliseconds(),
+		t.policy.Duration.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to record redemption failure: %w", err)
+	}
+	if lockedMillis == 0 {
+		return time.Time{}, nil
+	}
+	return time.Now().Add(time.Duration(lockedMillis) * time.Millisecond), nil
+}
+
+// Clear 失敗の記録とロックアウトを解除する
+func (t *RedisFailureTracker) Clear(ctx context.Context, userID string) error {
+	if err := t.client.Del(ctx, failuresKey(userID), lockedKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear redemption lockout: %w", err)
+	}
+	return nil
+}
+
+// failuresKey 失敗回数のキー
+func failuresKey(userID string) string {
+	return "gem:redemption_lockout:" + userID + ":failures"
+}
+
+// lockedKey ロックアウトのキー
+func lockedKey(userID string) string {
+	return "gem:redemption_lockout:" + userID + ":locked"
+}
@@ -0,0 +1,27 @@
+package lockout
+
+import (
+	"github.com/redis/go-redis/v9"
+
+	"gem-server/internal/domain/redemption_code"
+	"gem-server/internal/infrastructure/config"
+)
+
+// NewFailureTracker 設定から引き換え失敗のFailureTrackerを作成
+// clientがnilの場合はインメモリ、指定された場合はRedisで失敗回数を管理する。
+// ロックアウトが無効な場合はnilを返す
+func NewFailureTracker(cfg *config.RedemptionLockoutConfig, client *redis.Client) redemption_code.FailureTracker {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	policy := redemption_code.LockoutPolicy{
+		MaxFailures: cfg.MaxFailures,
+		Window:      cfg.Window,
+		Duration:    cfg.Duration,
+	}
+	if client != nil {
+		return NewRedisFailureTracker(client, policy)
+	}
+	return NewMemoryFailureTracker(policy)
+}
@@ -0,0 +1,182 @@
+package lockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/domain/redemption_code"
+	"gem-server/internal/infrastructure/config"
+)
+
+// trackerFactory テスト用のFailureTrackerと時刻を進める関数を作成する
+type trackerFactory func(t *testing.T, policy redemption_code.LockoutPolicy) (redemption_code.FailureTracker, func(time.Duration))
+
+func newTestMemoryTracker(t *testing.T, policy redemption_code.LockoutPolicy) (redemption_code.FailureTracker, func(time.Duration)) {
+	now := time.Now()
+	tracker := NewMemoryFailureTracker(policy)
+	tracker.now = func() time.Time { return now }
+	return tracker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func newTestRedisTracker(t *testing.T, policy redemption_code.LockoutPolicy) (redemption_code.FailureTracker, func(time.Duration)) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisFailureTracker(client, policy), mr.FastForward
+}
+
+func TestFailureTracker(t *testing.T) {
+	factories := map[string]trackerFactory{
+		"memory": newTestMemoryTracker,
+		"redis":  newTestRedisTracker,
+	}
+	policy := redemption_code.LockoutPolicy{
+		MaxFailures: 3,
+		Window:      10 * time.Minute,
+		Duration:    time.Hour,
+	}
+
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("正常系: 上限回数の失敗でロックアウト", func(t *testing.T) {
+				tracker, _ := factory(t, policy)
+
+				for i := 0; i < 2; i++ {
+					until, err := tracker.RecordFailure(ctx, "user123")
+					require.NoError(t, err)
+					assert.True(t, until.IsZero())
+				}
+				until, err := tracker.LockedUntil(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+
+				lockedAt := time.Now()
+				until, err = tracker.RecordFailure(ctx, "user123")
+				require.NoError(t, err)
+				assert.WithinDuration(t, lockedAt.Add(time.Hour), until, 5*time.Second)
+
+				until, err = tracker.LockedUntil(ctx, "user123")
+				require.NoError(t, err)
+				assert.False(t, until.IsZero())
+
+				// 他のユーザーには影響しない
+				until, err = tracker.LockedUntil(ctx, "user456")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+			})
+
+			t.Run("正常系: 期間を過ぎた失敗は数えない", func(t *testing.T) {
+				tracker, advance := factory(t, policy)
+
+				for i := 0; i < 2; i++ {
+					_, err := tracker.RecordFailure(ctx, "user123")
+					require.NoError(t, err)
+				}
+				advance(policy.Window)
+
+				until, err := tracker.RecordFailure(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+			})
+
+			t.Run("正常系: ロックアウトは期間を過ぎると解除される", func(t *testing.T) {
+				tracker, advance := factory(t, policy)
+
+				for i := 0; i < 3; i++ {
+					_, err := tracker.RecordFailure(ctx, "user123")
+					require.NoError(t, err)
+				}
+				advance(policy.Duration)
+
+				until, err := tracker.LockedUntil(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+
+				// 解除後は最初から数え直す
+				until, err = tracker.RecordFailure(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+			})
+
+			t.Run("正常系: Clearでロックアウトを解除", func(t *testing.T) {
+				tracker, _ := factory(t, policy)
+
+				for i := 0; i < 3; i++ {
+					_, err := tracker.RecordFailure(ctx, "user123")
+					require.NoError(t, err)
+				}
+				require.NoError(t, tracker.Clear(ctx, "user123"))
+
+				until, err := tracker.LockedUntil(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+
+				until, err = tracker.RecordFailure(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, until.IsZero())
+			})
+		})
+	}
+}
+
+func TestMemoryFailureTracker_Sweep(t *testing.T) {
+	tracker := NewMemoryFailureTracker(redemption_code.LockoutPolicy{
+		MaxFailures: 3,
+		Window:      time.Minute,
+		Duration:    time.Hour,
+	})
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := tracker.RecordFailure(ctx, "user123")
+	require.NoError(t, err)
+	assert.Len(t, tracker.records, 1)
+
+	now = now.Add(sweepInterval)
+	_, err = tracker.RecordFailure(ctx, "user456")
+	require.NoError(t, err)
+	assert.Len(t, tracker.records, 1)
+	assert.Contains(t, tracker.records, "user456")
+}
+
+func TestRedisFailureTracker_RedisUnavailable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	mr.Close()
+
+	tracker := NewRedisFailureTracker(client, redemption_code.LockoutPolicy{MaxFailures: 1, Window: time.Minute, Duration: time.Minute})
+	ctx := context.Background()
+
+	_, err := tracker.LockedUntil(ctx, "user123")
+	assert.Error(t, err)
+	_, err = tracker.RecordFailure(ctx, "user123")
+	assert.Error(t, err)
+	assert.Error(t, tracker.Clear(ctx, "user123"))
+}
+
+func TestNewFailureTracker(t *testing.T) {
+	cfg := &config.RedemptionLockoutConfig{
+		Enabled:     true,
+		MaxFailures: 5,
+		Window:      time.Minute,
+		Duration:    time.Hour,
+	}
+
+	assert.IsType(t, &MemoryFailureTracker{}, NewFailureTracker(cfg, nil))
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	defer client.Close()
+	assert.IsType(t, &RedisFailureTracker{}, NewFailureTracker(cfg, client))
+
+	assert.Nil(t, NewFailureTracker(&config.RedemptionLockoutConfig{Enabled: false}, nil))
+}
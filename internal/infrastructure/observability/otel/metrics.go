@@ -27,6 +27,12 @@ type Metrics struct {
 
 	// エラー率
 	ErrorCount metric.Int64Counter
+
+	// コード引き換えのロックアウト数
+	RedemptionLockoutCount metric.Int64Counter
+
+	// 有効期限切れで失効させた引き換えコード数
+	ExpiredCodeCount metric.Int64Counter
 }
 
 // NewMetrics 新しいMetricsを作成
@@ -81,13 +87,31 @@ func NewMetrics(meterName string) (*Metrics, error) {
 		return nil, err
 	}
 
+	redemptionLockoutCount, err := meter.Int64Counter(
+		"redemption_lockouts_total",
+		metric.WithDescription("Total number of users locked out of code redemption"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	expiredCodeCount, err := meter.Int64Counter(
+		"redemption_codes_expired_total",
+		metric.WithDescription("Total number of redemption codes marked as expired by the expiry sweep"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Metrics{
-		TransactionCount:     transactionCount,
-		CurrencyBalance:      currencyBalance,
-		NegativeBalanceCount: negativeBalanceCount,
-		RequestCount:         requestCount,
-		ResponseTime:         responseTime,
-		ErrorCount:           errorCount,
+		TransactionCount:       transactionCount,
+		CurrencyBalance:        currencyBalance,
+		NegativeBalanceCount:   negativeBalanceCount,
+		RequestCount:           requestCount,
+		ResponseTime:           responseTime,
+		ErrorCount:             errorCount,
+		RedemptionLockoutCount: redemptionLockoutCount,
+		ExpiredCodeCount:       expiredCodeCount,
 	}, nil
 }
 
@@ -149,3 +173,15 @@ func (m *Metrics) RecordError(ctx context.Context, errorType string) {
 		),
 	)
 }
+
+// RecordRedemptionLockout コード引き換えのロックアウトを記録
+func (m *Metrics) RecordRedemptionLockout(ctx context.Context) {
+	m.RedemptionLockoutCount.Add(ctx, 1)
+}
+
+// RecordExpiredCodes 有効期限切れで失効させた引き換えコード数を記録
+func (m *Metrics) RecordExpiredCodes(ctx context.Context, count int) {
+	if count > 0 {
+		m.ExpiredCodeCount.Add(ctx, int64(count))
+	}
+}
@@ -117,6 +117,37 @@ func TestMetrics_RecordError(t *testing.T) {
 	// エラーが発生しないことを確認
 }
 
+func TestMetrics_RecordRedemptionLockout(t *testing.T) {
+	mp := noop.NewMeterProvider()
+	otel.SetMeterProvider(mp)
+
+	metrics, err := NewMetrics("test-meter")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// ロックアウトを記録
+	metrics.RecordRedemptionLockout(ctx)
+
+	// エラーが発生しないことを確認
+}
+
+func TestMetrics_RecordExpiredCodes(t *testing.T) {
+	mp := noop.NewMeterProvider()
+	otel.SetMeterProvider(mp)
+
+	metrics, err := NewMetrics("test-meter")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	// 失効させたコード数を記録（0件の場合は記録しない）
+	metrics.RecordExpiredCodes(ctx, 3)
+	metrics.RecordExpiredCodes(ctx, 0)
+
+	// エラーが発生しないことを確認
+}
+
 func TestMetrics_RecordTransactionWithDifferentTypes(t *testing.T) {
 	mp := noop.NewMeterProvider()
 	otel.SetMeterProvider(mp)
@@ -0,0 +1,67 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/jwtkeys"
+)
+
+// ErrMissingUserID user_idとして使うクレームがトークンに含まれていない
+var ErrMissingUserID = errors.New("missing user id claim")
+
+// validMethods 外部のOIDCプロバイダーのトークンで受け付ける署名アルゴリズム（HS256などの共通鍵は受け付けない）
+var validMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Verifier 外部のOIDCプロバイダーが発行したIDトークンを検証する
+// 署名はプロバイダーのJWKSで検証し、iss・aud・expを確認する
+type Verifier struct {
+	issuer      string
+	audience    string
+	userIDClaim string
+	keys        *jwtkeys.RemoteKeySet
+}
+
+// NewVerifier 新しいVerifierを作成（無効な場合はnil）
+func NewVerifier(cfg *config.OIDCConfig) *Verifier {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Verifier{
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		userIDClaim: cfg.UserIDClaim,
+		keys:        jwtkeys.NewRemoteKeySet(cfg.JWKSURL, cfg.JWKSCacheTTL),
+	}
+}
+
+// Issuer 検証するトークンの発行者
+func (v *Verifier) Issuer() string {
+	return v.issuer
+}
+
+// Verify トークンを検証し、クレームと設定されたクレームから取得したユーザーIDを返す
+// ユーザーIDのクレームがない場合はErrMissingUserIDを返す
+func (v *Verifier) Verify(tokenString string) (jwt.MapClaims, string, error) {
+	token, err := jwt.Parse(tokenString, v.keys.Keyfunc,
+		jwt.WithValidMethods(validMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audience))
+	if err != nil {
+		return nil, "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, "", fmt.Errorf("invalid token claims")
+	}
+	userID, _ := claims[v.userIDClaim].(string)
+	if userID == "" {
+		return nil, "", fmt.Errorf("%w: %s", ErrMissingUserID, v.userIDClaim)
+	}
+	return claims, userID, nil
+}
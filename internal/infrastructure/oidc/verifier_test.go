@@ -0,0 +1,157 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/infrastructure/config"
+)
+
+// newTestProvider JWKSを配信するOIDCプロバイダーの代わりのサーバーと署名鍵を作成
+func newTestProvider(t *testing.T) (*httptest.Server, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwks := map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "provider-key-1",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+	return server, key
+}
+
+func TestNewVerifier(t *testing.T) {
+	assert.Nil(t, NewVerifier(&config.OIDCConfig{Enabled: false}))
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	server, key := newTestProvider(t)
+	const issuer = "https://accounts.example.com"
+
+	sign := func(method jwt.SigningMethod, signingKey interface{}, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = "provider-key-1"
+		tokenString, err := token.SignedString(signingKey)
+		require.NoError(t, err)
+		return tokenString
+	}
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":       issuer,
+			"aud":       "gem-client",
+			"sub":       "player-42",
+			"player_id": "p_42",
+			"iat":       time.Now().Unix(),
+			"exp":       time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name        string
+		userIDClaim string
+		token       string
+		wantUserID  string
+		wantErr     error
+	}{
+		{
+			name:        "正常系: subをuser_idとして使う",
+			userIDClaim: "sub",
+			token:       sign(jwt.SigningMethodRS256, key, claims(nil)),
+			wantUserID:  "player-42",
+		},
+		{
+			name:        "正常系: 設定したクレームをuser_idとして使う",
+			userIDClaim: "player_id",
+			token:       sign(jwt.SigningMethodRS256, key, claims(nil)),
+			wantUserID:  "p_42",
+		},
+		{
+			name:        "正常系: audが配列",
+			userIDClaim: "sub",
+			token:       sign(jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"aud": []string{"other-client", "gem-client"}})),
+			wantUserID:  "player-42",
+		},
+		{
+			name:        "異常系: user_idとして使うクレームがない",
+			userIDClaim: "player_id",
+			token:       sign(jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"player_id": nil})),
+			wantErr:     ErrMissingUserID,
+		},
+		{
+			name:        "異常系: 別のクライアント向けのトークン",
+			userIDClaim: "sub",
+			token:       sign(jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"aud": "other-client"})),
+			wantErr:     jwt.ErrTokenInvalidAudience,
+		},
+		{
+			name:        "異常系: 発行者が異なる",
+			userIDClaim: "sub",
+			token:       sign(jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"iss": "https://evil.example.com"})),
+			wantErr:     jwt.ErrTokenInvalidIssuer,
+		},
+		{
+			name:        "異常系: 期限切れ",
+			userIDClaim: "sub",
+			token:       sign(jwt.SigningMethodRS256, key, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+			wantErr:     jwt.ErrTokenExpired,
+		},
+		{
+			name:        "異常系: 共通鍵による署名は受け付けない",
+			userIDClaim: "sub",
+			token:       sign(jwt.SigningMethodHS256, []byte("shared-secret"), claims(nil)),
+			wantErr:     jwt.ErrTokenSignatureInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(&config.OIDCConfig{
+				Enabled:      true,
+				Issuer:       issuer,
+				JWKSURL:      server.URL,
+				Audience:     "gem-client",
+				UserIDClaim:  tt.userIDClaim,
+				JWKSCacheTTL: time.Hour,
+			})
+
+			got, userID, err := v.Verify(tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUserID, userID)
+			assert.Equal(t, issuer, got["iss"])
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// AdvisoryLock MySQLのGET_LOCKによる名前付きロック
+// 複数のインスタンスのうち1つだけが定期ジョブを実行するためのリーダー選出に使用する
+type AdvisoryLock struct {
+	db     *DB
+	name   string
+	tracer trace.Tracer
+}
+
+// NewAdvisoryLock 新しいAdvisoryLockを作成
+func NewAdvisoryLock(db *DB, name string) *AdvisoryLock {
+	return &AdvisoryLock{
+		db:     db,
+		name:   name,
+		tracer: otel.Tracer("advisory-lock"),
+	}
+}
+
+// TryLock ロックの取得を試み、取得できた場合は解放する関数を返す
+// 他の接続が保持している場合は待たずにacquired=falseを返す
+// GET_LOCKは接続単位のロックのため、解放するまで専用の接続を保持する
+func (l *AdvisoryLock) TryLock(ctx context.Context) (func(), bool, error) {
+	ctx, span := l.tracer.Start(ctx, "AdvisoryLock.TryLock")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.lock_name", l.name),
+		attribute.String("db.operation", "GET_LOCK"),
+	)
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	// 1: 取得、0: 他の接続が保持中、NULL: エラー
+	var result sql.NullInt64
+	if err := conn.QueryRowContext(ctx, `SELECT GET_LOCK(?, 0)`, l.name).Scan(&result); err != nil {
+		_ = conn.Close()
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, false, fmt.Errorf("failed to get advisory lock: %w", err)
+	}
+	if !result.Valid || result.Int64 != 1 {
+		_ = conn.Close()
+		span.SetAttributes(attribute.Bool("db.lock_acquired", false))
+		span.SetStatus(otelcodes.Ok, "advisory lock held by another connection")
+		return nil, false, nil
+	}
+
+	release := func() {
+		// 呼び出し元のコンテキストがキャンセルされていても解放する
+		var released sql.NullInt64
+		_ = conn.QueryRowContext(context.Background(), `SELECT RELEASE_LOCK(?)`, l.name).Scan(&released)
+		_ = conn.Close()
+	}
+
+	span.SetAttributes(attribute.Bool("db.lock_acquired", true))
+	span.SetStatus(otelcodes.Ok, "advisory lock acquired")
+	return release, true, nil
+}
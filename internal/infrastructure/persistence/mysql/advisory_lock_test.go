@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdvisoryLock_TryLock(t *testing.T) {
+	tests := []struct {
+		name         string
+		setupMock    func(sqlmock.Sqlmock)
+		wantAcquired bool
+		wantErr      bool
+	}{
+		{
+			name: "正常系: ロックを取得して解放",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT GET_LOCK\(\?, 0\)`).
+					WithArgs("test_lock").
+					WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK"}).AddRow(1))
+				mock.ExpectQuery(`SELECT RELEASE_LOCK\(\?\)`).
+					WithArgs("test_lock").
+					WillReturnRows(sqlmock.NewRows([]string{"RELEASE_LOCK"}).AddRow(1))
+			},
+			wantAcquired: true,
+		},
+		{
+			name: "正常系: 他の接続が保持中",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT GET_LOCK`).
+					WithArgs("test_lock").
+					WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK"}).AddRow(0))
+			},
+			wantAcquired: false,
+		},
+		{
+			name: "正常系: GET_LOCKがNULLを返した場合は取得できない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT GET_LOCK`).
+					WithArgs("test_lock").
+					WillReturnRows(sqlmock.NewRows([]string{"GET_LOCK"}).AddRow(nil))
+			},
+			wantAcquired: false,
+		},
+		{
+			name: "異常系: DBエラー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT GET_LOCK`).
+					WithArgs("test_lock").
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.setupMock(mock)
+
+			lock := NewAdvisoryLock(&DB{DB: db}, "test_lock")
+			release, acquired, err := lock.TryLock(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.False(t, acquired)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantAcquired, acquired)
+				if acquired {
+					require.NotNil(t, release)
+					release()
+				} else {
+					assert.Nil(t, release)
+				}
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gem-server/internal/domain/api_key"
+)
+
+// APIKeyRepository MySQL実装の管理APIキーリポジトリ
+type APIKeyRepository struct {
+	db     *DB
+	tracer trace.Tracer
+}
+
+// NewAPIKeyRepository 新しいAPIKeyRepositoryを作成
+func NewAPIKeyRepository(db *DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		db:     db,
+		tracer: otel.Tracer("api-key-repository"),
+	}
+}
+
+// Save 管理APIキーを保存
+func (r *APIKeyRepository) Save(ctx context.Context, key *api_key.APIKey) error {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.Save")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.api_key_name", key.Name()),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "admin_api_keys"),
+	)
+
+	query := `
+		INSERT INTO admin_api_keys (name, key_hash, scopes, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err := r.db.executor(ctx).ExecContext(ctx, query,
+		key.Name(),
+		key.KeyHash(),
+		api_key.FormatScopes(key.Scopes()),
+		key.CreatedAt(),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", api_key.ErrAPIKeyAlreadyExists, key.Name())
+		}
+		return fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "api key saved")
+	return nil
+}
+
+// FindByHash キーのハッシュで管理APIキーを取得
+func (r *APIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*api_key.APIKey, error) {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.FindByHash")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "admin_api_keys"),
+	)
+
+	query := `
+		SELECT name, key_hash, scopes, created_at, revoked_at
+		FROM admin_api_keys
+		WHERE key_hash = ?
+	`
+
+	var (
+		name, hash, scopesValue string
+		createdAt               time.Time
+		revokedAt               sql.NullTime
+	)
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, keyHash).Scan(
+		&name, &hash, &scopesValue, &createdAt, &revokedAt,
+	)
+	if err == sql.ErrNoRows {
+		span.SetStatus(otelcodes.Ok, "api key not found")
+		return nil, api_key.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to find api key: %w", err)
+	}
+
+	scopes, err := api_key.ParseScopes(scopesValue)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to parse scopes of api key %s: %w", name, err)
+	}
+	key, err := api_key.NewAPIKey(name, hash, scopes)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to restore api key: %w", err)
+	}
+	key.SetCreatedAt(createdAt)
+	if revokedAt.Valid {
+		key.SetRevokedAt(&revokedAt.Time)
+	}
+
+	span.SetAttributes(
+		attribute.String("db.api_key_name", name),
+		attribute.Bool("db.revoked", key.IsRevoked()),
+	)
+	span.SetStatus(otelcodes.Ok, "api key found")
+
+	return key, nil
+}
+
+// Revoke 名前を指定して管理APIキーを失効させる
+func (r *APIKeyRepository) Revoke(ctx context.Context, name string, revokedAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "APIKeyRepository.Revoke")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.api_key_name", name),
+		attribute.String("db.operation", "UPDATE"),
+		attribute.String("db.table", "admin_api_keys"),
+	)
+
+	query := `
+		UPDATE admin_api_keys
+		SET revoked_at = ?
+		WHERE name = ? AND revoked_at IS NULL
+	`
+
+	result, err := r.db.executor(ctx).ExecContext(ctx, query, revokedAt, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		span.SetStatus(otelcodes.Error, "api key not found")
+		return api_key.ErrAPIKeyNotFound
+	}
+
+	span.SetStatus(otelcodes.Ok, "api key revoked")
+	return nil
+}
@@ -0,0 +1,211 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"gem-server/internal/domain/api_key"
+)
+
+func newTestAPIKeyRepository(t *testing.T) (*APIKeyRepository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	repo := &APIKeyRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+	return repo, mock, func() { db.Close() }
+}
+
+var apiKeyColumns = []string{"name", "key_hash", "scopes", "created_at", "revoked_at"}
+
+func TestAPIKeyRepository_Save(t *testing.T) {
+	key, err := api_key.NewAPIKey("game-server", "hash", []api_key.Scope{api_key.ScopeCurrencyGrant, api_key.ScopeCurrencyConsume})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+		wantError bool
+	}{
+		{
+			name: "正常系: 管理APIキーを保存",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO admin_api_keys`).
+					WithArgs("game-server", "hash", "currency:grant,currency:consume", sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "異常系: 同じ名前のキーが存在する",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO admin_api_keys`).
+					WillReturnError(errors.New("Error 1062: Duplicate entry 'game-server' for key 'name'"))
+			},
+			wantErr:   api_key.ErrAPIKeyAlreadyExists,
+			wantError: true,
+		},
+		{
+			name: "異常系: データベースエラー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO admin_api_keys`).
+					WillReturnError(errors.New("database error"))
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestAPIKeyRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			err := repo.Save(context.Background(), key)
+			if tt.wantError {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyRepository_FindByHash(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		setupMock   func(sqlmock.Sqlmock)
+		wantScopes  []api_key.Scope
+		wantRevoked bool
+		wantErr     error
+		wantError   bool
+	}{
+		{
+			name: "正常系: 管理APIキーを取得",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT name, key_hash, scopes, created_at, revoked_at\s+FROM admin_api_keys`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+						AddRow("game-server", "hash", "currency:grant,history:read", createdAt, nil))
+			},
+			wantScopes: []api_key.Scope{api_key.ScopeCurrencyGrant, api_key.ScopeHistoryRead},
+		},
+		{
+			name: "正常系: 失効済みのキー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT name, key_hash, scopes, created_at, revoked_at\s+FROM admin_api_keys`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+						AddRow("game-server", "hash", "", createdAt, revokedAt))
+			},
+			wantRevoked: true,
+		},
+		{
+			name: "異常系: 存在しない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT name, key_hash, scopes, created_at, revoked_at\s+FROM admin_api_keys`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(apiKeyColumns))
+			},
+			wantErr:   api_key.ErrAPIKeyNotFound,
+			wantError: true,
+		},
+		{
+			name: "異常系: 不明なスコープが保存されている",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT name, key_hash, scopes, created_at, revoked_at\s+FROM admin_api_keys`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(apiKeyColumns).
+						AddRow("game-server", "hash", "currency:mint", createdAt, nil))
+			},
+			wantErr:   api_key.ErrInvalidScope,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestAPIKeyRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			key, err := repo.FindByHash(context.Background(), "hash")
+			if tt.wantError {
+				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "game-server", key.Name())
+				assert.Equal(t, tt.wantScopes, key.Scopes())
+				assert.Equal(t, createdAt, key.CreatedAt())
+				assert.Equal(t, tt.wantRevoked, key.IsRevoked())
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	revokedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "正常系: 管理APIキーを失効",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE admin_api_keys\s+SET revoked_at = \?\s+WHERE name = \? AND revoked_at IS NULL`).
+					WithArgs(revokedAt, "game-server").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "異常系: 未失効のキーが存在しない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE admin_api_keys`).
+					WithArgs(revokedAt, "game-server").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: api_key.ErrAPIKeyNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestAPIKeyRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			err := repo.Revoke(context.Background(), "game-server", revokedAt)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -28,6 +28,8 @@ func NewCurrencyRepository(db *DB) *CurrencyRepository {
 }
 
 // FindByUserIDAndType ユーザーIDと通貨タイプで通貨を取得
+// トランザクション内では行ロックを取得して最新の残高を読む（FOR UPDATE）。
+// REPEATABLE READのスナップショットを読むと、楽観的ロックのリトライで毎回同じ古いバージョンを読んでしまうため
 func (r *CurrencyRepository) FindByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) (*currency.Currency, error) {
 	ctx, span := r.tracer.Start(ctx, "CurrencyRepository.FindByUserIDAndType")
 	defer span.End()
@@ -44,13 +46,17 @@ func (r *CurrencyRepository) FindByUserIDAndType(ctx context.Context, userID str
 		FROM currency_balances
 		WHERE user_id = ? AND currency_type = ?
 	`
+	if InTransaction(ctx) {
+		query += "FOR UPDATE"
+		span.SetAttributes(attribute.Bool("db.for_update", true))
+	}
 
 	var dbUserID string
 	var dbCurrencyType string
 	var balance int64
 	var version int
 
-	err := r.db.QueryRowContext(ctx, query, userID, currencyType.String()).Scan(
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, userID, currencyType.String()).Scan(
 		&dbUserID,
 		&dbCurrencyType,
 		&balance,
@@ -96,21 +102,24 @@ func (r *CurrencyRepository) Save(ctx context.Context, c *currency.Currency) err
 		attribute.String("db.currency_type", c.CurrencyType().String()),
 		attribute.Int64("db.balance", c.Balance()),
 		attribute.Int("db.version", c.Version()),
+		attribute.Int("db.loaded_version", c.LoadedVersion()),
 		attribute.String("db.operation", "UPDATE"),
 		attribute.String("db.table", "currency_balances"),
 	)
 
 	query := `
 		UPDATE currency_balances
-		SET balance = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		SET balance = ?, version = ?, updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = ? AND currency_type = ? AND version = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	// 読み込んだ時点のバージョンと比較し、エンティティのバージョンで更新する
+	result, err := r.db.executor(ctx).ExecContext(ctx, query,
 		c.Balance(),
+		c.Version(),
 		c.UserID(),
 		c.CurrencyType().String(),
-		c.Version(),
+		c.LoadedVersion(),
 	)
 
 	if err != nil {
@@ -168,7 +177,7 @@ func (r *CurrencyRepository) Create(ctx context.Context, c *currency.Currency) e
 			updated_at = CURRENT_TIMESTAMP
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.executor(ctx).ExecContext(ctx, query,
 		c.UserID(),
 		c.CurrencyType().String(),
 		c.Balance(),
@@ -193,7 +202,7 @@ func (r *CurrencyRepository) ensureUserExists(ctx context.Context, userID string
 		ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID)
+	_, err := r.db.executor(ctx).ExecContext(ctx, query, userID)
 	if err != nil {
 		return fmt.Errorf("failed to ensure user exists: %w", err)
 	}
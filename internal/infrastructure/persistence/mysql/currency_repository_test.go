@@ -120,17 +120,31 @@ func TestCurrencyRepository_Save(t *testing.T) {
 			currency: currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1),
 			setupMock: func() {
 				mock.ExpectExec(`UPDATE currency_balances`).
-					WithArgs(int64(1000), "user123", "paid", 1).
+					WithArgs(int64(1000), 1, "user123", "paid", 1).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantError: false,
 		},
+		{
+			name: "正常系: 読み込んだ時点のバージョンで比較して更新する",
+			currency: func() *currency.Currency {
+				c := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1)
+				_ = c.Grant(500)
+				return c
+			}(),
+			setupMock: func() {
+				mock.ExpectExec(`UPDATE currency_balances`).
+					WithArgs(int64(1000), 2, "user123", "paid", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			wantError: false,
+		},
 		{
 			name:     "異常系: 楽観的ロック失敗（行が更新されない）",
 			currency: currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1),
 			setupMock: func() {
 				mock.ExpectExec(`UPDATE currency_balances`).
-					WithArgs(int64(1000), "user123", "paid", 1).
+					WithArgs(int64(1000), 1, "user123", "paid", 1).
 					WillReturnResult(sqlmock.NewResult(0, 0))
 			},
 			wantError: true,
@@ -140,7 +154,7 @@ func TestCurrencyRepository_Save(t *testing.T) {
 			currency: currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1),
 			setupMock: func() {
 				mock.ExpectExec(`UPDATE currency_balances`).
-					WithArgs(int64(1000), "user123", "paid", 1).
+					WithArgs(int64(1000), 1, "user123", "paid", 1).
 					WillReturnError(sql.ErrConnDone)
 			},
 			wantError: true,
@@ -39,6 +39,21 @@ func NewDB(cfg *config.DatabaseConfig) (*DB, error) {
 	return &DB{DB: db}, nil
 }
 
+// executor クエリ実行インターフェース（*sql.DBと*sql.Txの共通部分）
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// executor コンテキストにトランザクションがあればそれを、なければ接続プールを返す
+func (db *DB) executor(ctx context.Context) executor {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return db.DB
+}
+
 // Close データベース接続を閉じる
 func (db *DB) Close() error {
 	return db.DB.Close()
@@ -0,0 +1,141 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	historyapp "gem-server/internal/application/history"
+	"gem-server/internal/domain/transaction"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+)
+
+// newHistoryAppServiceWithMockDB sqlmockを使った実リポジトリでHistoryApplicationServiceを作成
+func newHistoryAppServiceWithMockDB(t *testing.T) (*historyapp.HistoryApplicationService, sqlmock.Sqlmock, func()) {
+	return newAppServiceWithMockDB(t, func(db *DB, logger *otelinfra.Logger, metrics *otelinfra.Metrics) *historyapp.HistoryApplicationService {
+		return historyapp.NewHistoryApplicationService(NewTransactionRepository(db), logger, metrics)
+	})
+}
+
+// newTransactionRows transactionsテーブルの行
+func newTransactionRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{
+		"transaction_id", "user_id", "transaction_type", "currency_type",
+		"amount", "balance_before", "balance_after", "status",
+		"payment_request_id", "requester", "idempotency_key", "request_hash",
+		"metadata", "created_at", "updated_at",
+	})
+}
+
+func TestHistoryApplicationService_GetTransactionHistory_CursorPagination(t *testing.T) {
+	svc, mock, cleanup := newHistoryAppServiceWithMockDB(t)
+	defer cleanup()
+
+	// 保存された作成日時（取得した時刻とは異なる過去の日時）
+	t1 := time.Date(2025, 1, 3, 9, 0, 0, 123456000, time.UTC)
+	t2 := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	t3 := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	// 1ページ目（カーソルなし）
+	mock.ExpectQuery(`ORDER BY created_at DESC, transaction_id DESC\s+LIMIT \? OFFSET \?`).
+		WithArgs("user123", 2, 0).
+		WillReturnRows(newTransactionRows().
+			AddRow("txn_3", "user123", "grant", "paid", 100, 200, 300, "completed", nil, nil, nil, nil, nil, t1, t1).
+			AddRow("txn_2", "user123", "grant", "paid", 100, 100, 200, "completed", nil, nil, nil, nil, nil, t2, t2))
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	first, err := svc.GetTransactionHistory(context.Background(), &historyapp.GetTransactionHistoryRequest{
+		UserID: "user123",
+		Limit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, first.Transactions, 2)
+	assert.Equal(t, t1, first.Transactions[0].CreatedAt())
+	require.NotEmpty(t, first.NextCursor)
+
+	// カーソルは1ページ目の最後の行の保存された作成日時とIDを指す
+	cursor, err := transaction.DecodeCursor(first.NextCursor)
+	require.NoError(t, err)
+	assert.True(t, t2.Equal(cursor.CreatedAt), "cursor created_at = %s", cursor.CreatedAt)
+	assert.Equal(t, "txn_2", cursor.TransactionID)
+
+	// 2ページ目（カーソルの位置より古い行）
+	mock.ExpectQuery(`AND \(created_at < \? OR \(created_at = \? AND transaction_id < \?\)\)`).
+		WithArgs("user123", t2, t2, "txn_2", 3).
+		WillReturnRows(newTransactionRows().
+			AddRow("txn_1", "user123", "grant", "paid", 100, 0, 100, "completed", nil, nil, nil, nil, nil, t3, t3))
+	mock.ExpectQuery(`SELECT COUNT\(\*\)`).
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	second, err := svc.GetTransactionHistory(context.Background(), &historyapp.GetTransactionHistoryRequest{
+		UserID: "user123",
+		Limit:  2,
+		Cursor: first.NextCursor,
+	})
+	require.NoError(t, err)
+	require.Len(t, second.Transactions, 1)
+	assert.Equal(t, "txn_1", second.Transactions[0].TransactionID())
+	assert.NotEqual(t, first.Transactions[0].TransactionID(), second.Transactions[0].TransactionID())
+	assert.Empty(t, second.NextCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHistoryApplicationService_ExportTransactions_CreatedAt(t *testing.T) {
+	// 出力する作成日時は保存された値（エクスポートを実行した時刻ではない）
+	createdAt1 := time.Date(2025, 1, 5, 12, 30, 0, 0, time.UTC)
+	createdAt2 := time.Date(2025, 1, 20, 3, 4, 5, 0, time.FixedZone("JST", 9*60*60))
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		format historyapp.ExportFormat
+		want   string
+	}{
+		{
+			name:   "正常系: CSV",
+			format: historyapp.ExportFormatCSV,
+			want: "transaction_id,user_id,transaction_type,currency_type,amount,balance_before,balance_after,status,payment_request_id,requester,created_at,metadata\n" +
+				"txn_1,user123,grant,paid,100,0,100,completed,,ops-tool,2025-01-05T12:30:00Z,\"{\"\"campaign.id\"\":\"\"c1\"\"}\"\n" +
+				"txn_2,user456,consume,free,50,200,150,completed,pr_1,,2025-01-19T18:04:05Z,{}\n",
+		},
+		{
+			name:   "正常系: NDJSON",
+			format: historyapp.ExportFormatNDJSON,
+			want: `{"transaction_id":"txn_1","user_id":"user123","transaction_type":"grant","currency_type":"paid","amount":"100","balance_before":"0","balance_after":"100","status":"completed","payment_request_id":"","requester":"ops-tool","created_at":"2025-01-05T12:30:00Z","metadata":{"campaign.id":"c1"}}` + "\n" +
+				`{"transaction_id":"txn_2","user_id":"user456","transaction_type":"consume","currency_type":"free","amount":"50","balance_before":"200","balance_after":"150","status":"completed","payment_request_id":"pr_1","requester":"","created_at":"2025-01-19T18:04:05Z","metadata":{}}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newHistoryAppServiceWithMockDB(t)
+			defer cleanup()
+
+			mock.ExpectQuery(`ORDER BY created_at ASC, transaction_id ASC`).
+				WillReturnRows(newTransactionRows().
+					AddRow("txn_1", "user123", "grant", "paid", 100, 0, 100, "completed", nil, "ops-tool", nil, nil, `{"campaign":{"id":"c1"}}`, createdAt1, createdAt1).
+					AddRow("txn_2", "user456", "consume", "free", 50, 200, 150, "completed", "pr_1", nil, nil, nil, nil, createdAt2, createdAt2))
+
+			var buf bytes.Buffer
+			res, err := svc.ExportTransactions(context.Background(), &historyapp.ExportTransactionsRequest{
+				Format: tt.format,
+				From:   &from,
+				To:     &to,
+			}, &buf)
+			require.NoError(t, err)
+			assert.Equal(t, 2, res.Count)
+			assert.Equal(t, tt.want, buf.String())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+)
+
+// newAppServiceWithMockDB sqlmockを使った実リポジトリでアプリケーションサービスを作成
+// newServiceにはsqlmockに接続したDBと、テスト用のロガー・メトリクスを渡す
+func newAppServiceWithMockDB[S any](t *testing.T, newService func(db *DB, logger *otelinfra.Logger, metrics *otelinfra.Metrics) S) (S, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	logger := otelinfra.NewLogger(otel.Tracer("test"))
+	metrics, err := otelinfra.NewMetrics("test")
+	require.NoError(t, err)
+
+	return newService(&DB{DB: db}, logger, metrics), mock, func() { db.Close() }
+}
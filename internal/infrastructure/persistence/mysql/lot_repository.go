@@ -0,0 +1,281 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gem-server/internal/domain/currency"
+)
+
+// LotRepository MySQL実装のLotRepository
+type LotRepository struct {
+	db     *DB
+	tracer trace.Tracer
+}
+
+// NewLotRepository 新しいLotRepositoryを作成
+func NewLotRepository(db *DB) *LotRepository {
+	return &LotRepository{
+		db:     db,
+		tracer: otel.Tracer("lot-repository"),
+	}
+}
+
+// Create 新しいロットを作成
+func (r *LotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	ctx, span := r.tracer.Start(ctx, "LotRepository.Create")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.lot_id", lot.LotID()),
+		attribute.String("db.user_id", lot.UserID()),
+		attribute.String("db.currency_type", lot.CurrencyType().String()),
+		attribute.Int64("db.amount", lot.Amount()),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "currency_lots"),
+	)
+
+	query := `
+		INSERT INTO currency_lots (lot_id, user_id, currency_type, amount, remaining, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.executor(ctx).ExecContext(ctx, query,
+		lot.LotID(),
+		lot.UserID(),
+		lot.CurrencyType().String(),
+		lot.Amount(),
+		lot.Remaining(),
+		lot.ExpiresAt(),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to create lot: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "lot created")
+	return nil
+}
+
+// Save ロットの残量を更新
+func (r *LotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	ctx, span := r.tracer.Start(ctx, "LotRepository.Save")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.lot_id", lot.LotID()),
+		attribute.Int64("db.remaining", lot.Remaining()),
+		attribute.String("db.operation", "UPDATE"),
+		attribute.String("db.table", "currency_lots"),
+	)
+
+	query := `
+		UPDATE currency_lots
+		SET remaining = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE lot_id = ?
+	`
+
+	_, err := r.db.executor(ctx).ExecContext(ctx, query, lot.Remaining(), lot.LotID())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to save lot: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "lot saved")
+	return nil
+}
+
+// FindAvailableByUserIDAndType 残量のあるロットを有効期限の昇順で取得（失効処理前のロットを含む）
+func (r *LotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	ctx, span := r.tracer.Start(ctx, "LotRepository.FindAvailableByUserIDAndType")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.user_id", userID),
+		attribute.String("db.currency_type", currencyType.String()),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "currency_lots"),
+	)
+
+	query := `
+		SELECT lot_id, user_id, currency_type, amount, remaining, expires_at
+		FROM currency_lots
+		WHERE user_id = ? AND currency_type = ? AND remaining > 0
+		ORDER BY expires_at ASC, id ASC
+	`
+
+	lots, err := r.queryLots(ctx, query, userID, currencyType.String())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("db.rows_returned", len(lots)))
+	span.SetStatus(otelcodes.Ok, "lots found")
+	return lots, nil
+}
+
+// FindExpired 指定時刻時点で失効済みかつ残量のあるロットを有効期限の昇順で取得
+func (r *LotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	ctx, span := r.tracer.Start(ctx, "LotRepository.FindExpired")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("db.limit", limit),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "currency_lots"),
+	)
+
+	query := `
+		SELECT lot_id, user_id, currency_type, amount, remaining, expires_at
+		FROM currency_lots
+		WHERE remaining > 0 AND expires_at <= ?
+		ORDER BY expires_at ASC, id ASC
+		LIMIT ?
+	`
+
+	lots, err := r.queryLots(ctx, query, now, limit)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("db.rows_returned", len(lots)))
+	span.SetStatus(otelcodes.Ok, "expired lots found")
+	return lots, nil
+}
+
+// SaveConsumptions トランザクションでロットから消費した内訳を記録
+func (r *LotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	if len(consumptions) == 0 {
+		return nil
+	}
+
+	ctx, span := r.tracer.Start(ctx, "LotRepository.SaveConsumptions")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.transaction_id", transactionID),
+		attribute.Int("db.rows", len(consumptions)),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "currency_lot_consumptions"),
+	)
+
+	placeholders := make([]string, 0, len(consumptions))
+	args := make([]interface{}, 0, len(consumptions)*3)
+	for _, c := range consumptions {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		args = append(args, transactionID, c.LotID, c.Amount)
+	}
+
+	query := `
+		INSERT INTO currency_lot_consumptions (transaction_id, lot_id, amount)
+		VALUES ` + strings.Join(placeholders, ", ")
+
+	if _, err := r.db.executor(ctx).ExecContext(ctx, query, args...); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to save lot consumptions: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "lot consumptions saved")
+	return nil
+}
+
+// FindConsumptionsByTransactionID トランザクションでロットから消費した内訳を消費順に取得
+func (r *LotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	ctx, span := r.tracer.Start(ctx, "LotRepository.FindConsumptionsByTransactionID")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.transaction_id", transactionID),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "currency_lot_consumptions"),
+	)
+
+	query := `
+		SELECT c.lot_id, c.amount, l.expires_at
+		FROM currency_lot_consumptions c
+		JOIN currency_lots l ON l.lot_id = c.lot_id
+		WHERE c.transaction_id = ?
+		ORDER BY c.id ASC
+	`
+
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, transactionID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to query lot consumptions: %w", err)
+	}
+	defer rows.Close()
+
+	var consumptions []currency.LotConsumption
+	for rows.Next() {
+		var c currency.LotConsumption
+		if err := rows.Scan(&c.LotID, &c.Amount, &c.ExpiresAt); err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return nil, fmt.Errorf("failed to scan lot consumption: %w", err)
+		}
+		consumptions = append(consumptions, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to iterate lot consumptions: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int("db.rows_returned", len(consumptions)))
+	span.SetStatus(otelcodes.Ok, "lot consumptions found")
+	return consumptions, nil
+}
+
+// queryLots ロット一覧を取得するクエリを実行
+func (r *LotRepository) queryLots(ctx context.Context, query string, args ...interface{}) ([]*currency.Lot, error) {
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lots: %w", err)
+	}
+	defer rows.Close()
+
+	var lots []*currency.Lot
+	for rows.Next() {
+		var lotID, userID, dbCurrencyType string
+		var amount, remaining int64
+		var expiresAt time.Time
+
+		if err := rows.Scan(&lotID, &userID, &dbCurrencyType, &amount, &remaining, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan lot: %w", err)
+		}
+
+		ct, err := currency.NewCurrencyType(dbCurrencyType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency type: %w", err)
+		}
+
+		lot, err := currency.NewLot(lotID, userID, ct, amount, remaining, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconstruct lot entity: %w", err)
+		}
+		lots = append(lots, lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate lots: %w", err)
+	}
+
+	return lots, nil
+}
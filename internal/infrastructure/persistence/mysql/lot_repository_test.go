@@ -0,0 +1,247 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"gem-server/internal/domain/currency"
+)
+
+func newTestLotRepository(t *testing.T) (*LotRepository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	repo := &LotRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+	return repo, mock, func() { db.Close() }
+}
+
+func TestLotRepository_Create(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	lot, err := currency.NewLot("txn_123", "user123", currency.CurrencyTypeFree, 100, 100, expiresAt)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantError bool
+	}{
+		{
+			name: "正常系: ロットを作成",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO currency_lots`).
+					WithArgs("txn_123", "user123", "free", int64(100), int64(100), expiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantError: false,
+		},
+		{
+			name: "異常系: データベースエラー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO currency_lots`).
+					WillReturnError(errors.New("database error"))
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestLotRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			err := repo.Create(context.Background(), lot)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLotRepository_Save(t *testing.T) {
+	repo, mock, cleanup := newTestLotRepository(t)
+	defer cleanup()
+
+	lot, err := currency.NewLot("txn_123", "user123", currency.CurrencyTypeFree, 100, 40, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	mock.ExpectExec(`UPDATE currency_lots`).
+		WithArgs(int64(40), "txn_123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Save(context.Background(), lot)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLotRepository_FindAvailableByUserIDAndType(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"lot_id", "user_id", "currency_type", "amount", "remaining", "expires_at"}
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantLots  int
+		wantError bool
+	}{
+		{
+			name: "正常系: ロットを取得",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT lot_id, user_id, currency_type, amount, remaining, expires_at`).
+					WithArgs("user123", "free").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("txn_1", "user123", "free", 100, 50, expiresAt).
+						AddRow("txn_2", "user123", "free", 200, 200, expiresAt.Add(time.Hour)))
+			},
+			wantLots:  2,
+			wantError: false,
+		},
+		{
+			name: "正常系: ロットが存在しない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT lot_id, user_id, currency_type, amount, remaining, expires_at`).
+					WithArgs("user123", "free").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			wantLots:  0,
+			wantError: false,
+		},
+		{
+			name: "異常系: データベースエラー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT lot_id, user_id, currency_type, amount, remaining, expires_at`).
+					WillReturnError(errors.New("database error"))
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestLotRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			lots, err := repo.FindAvailableByUserIDAndType(context.Background(), "user123", currency.CurrencyTypeFree)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Len(t, lots, tt.wantLots)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLotRepository_FindExpired(t *testing.T) {
+	repo, mock, cleanup := newTestLotRepository(t)
+	defer cleanup()
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT lot_id, user_id, currency_type, amount, remaining, expires_at`).
+		WithArgs(now, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"lot_id", "user_id", "currency_type", "amount", "remaining", "expires_at"}).
+			AddRow("txn_1", "user123", "free", 100, 30, now.Add(-time.Hour)))
+
+	lots, err := repo.FindExpired(context.Background(), now, 10)
+	require.NoError(t, err)
+	require.Len(t, lots, 1)
+	assert.Equal(t, "txn_1", lots[0].LotID())
+	assert.Equal(t, int64(30), lots[0].Remaining())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLotRepository_SaveConsumptions(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		consumptions []currency.LotConsumption
+		setupMock    func(sqlmock.Sqlmock)
+		wantError    bool
+	}{
+		{
+			name: "正常系: 複数ロットの消費内訳を1回のINSERTで記録",
+			consumptions: []currency.LotConsumption{
+				{LotID: "txn_1", Amount: 50, ExpiresAt: expiresAt},
+				{LotID: "txn_2", Amount: 20, ExpiresAt: expiresAt.Add(time.Hour)},
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO currency_lot_consumptions \(transaction_id, lot_id, amount\)\s+VALUES \(\?, \?, \?\), \(\?, \?, \?\)`).
+					WithArgs("txn_consume", "txn_1", int64(50), "txn_consume", "txn_2", int64(20)).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+			},
+			wantError: false,
+		},
+		{
+			name:         "正常系: 内訳がない場合は何もしない",
+			consumptions: nil,
+			setupMock:    func(mock sqlmock.Sqlmock) {},
+			wantError:    false,
+		},
+		{
+			name: "異常系: データベースエラー",
+			consumptions: []currency.LotConsumption{
+				{LotID: "txn_1", Amount: 50, ExpiresAt: expiresAt},
+			},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO currency_lot_consumptions`).
+					WillReturnError(errors.New("database error"))
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestLotRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			err := repo.SaveConsumptions(context.Background(), "txn_consume", tt.consumptions)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLotRepository_FindConsumptionsByTransactionID(t *testing.T) {
+	repo, mock, cleanup := newTestLotRepository(t)
+	defer cleanup()
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT c.lot_id, c.amount, l.expires_at\s+FROM currency_lot_consumptions c\s+JOIN currency_lots l ON l.lot_id = c.lot_id\s+WHERE c.transaction_id = \?`).
+		WithArgs("txn_consume").
+		WillReturnRows(sqlmock.NewRows([]string{"lot_id", "amount", "expires_at"}).
+			AddRow("txn_1", 50, expiresAt).
+			AddRow("txn_2", 20, expiresAt.Add(time.Hour)))
+
+	consumptions, err := repo.FindConsumptionsByTransactionID(context.Background(), "txn_consume")
+	require.NoError(t, err)
+	assert.Equal(t, []currency.LotConsumption{
+		{LotID: "txn_1", Amount: 50, ExpiresAt: expiresAt},
+		{LotID: "txn_2", Amount: 20, ExpiresAt: expiresAt.Add(time.Hour)},
+	}, consumptions)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
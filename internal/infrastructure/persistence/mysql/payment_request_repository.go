@@ -79,7 +79,7 @@ func (r *PaymentRequestRepository) Save(ctx context.Context, pr *payment_request
 		return fmt.Errorf("failed to marshal response: %w", err)
 	}
 
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = r.db.executor(ctx).ExecContext(ctx, query,
 		pr.PaymentRequestID(),
 		pr.UserID(),
 		pr.Amount(),
@@ -128,7 +128,7 @@ func (r *PaymentRequestRepository) FindByPaymentRequestID(ctx context.Context, p
 	var paymentMethodDataJSON, detailsJSON, responseJSON sql.NullString
 	var createdAt, updatedAt time.Time
 
-	err := r.db.QueryRowContext(ctx, query, paymentRequestID).Scan(
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, paymentRequestID).Scan(
 		&dbPaymentRequestID,
 		&dbUserID,
 		&amount,
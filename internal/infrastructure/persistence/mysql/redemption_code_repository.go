@@ -17,6 +17,17 @@ import (
 	"gem-server/internal/domain/redemption_code"
 )
 
+// redemptionCodeColumns redemption_codesテーブルから取得するカラム（scanRedemptionCodeと順序を合わせる）
+const redemptionCodeColumns = `
+			code, code_type, currency_type, amount, rewards,
+			max_uses, current_uses, valid_from, valid_until,
+			status, metadata, batch_id, campaign_id, campaign_exclusive,
+			eligibility, created_at, updated_at`
+
+// createBatchChunkSize CreateBatchで1回のINSERT文にまとめる行数
+// プレースホルダー数の上限（65535）を超えないようにする
+const createBatchChunkSize = 500
+
 // RedemptionCodeRepository MySQL実装のRedemptionCodeRepository
 type RedemptionCodeRepository struct {
 	db     *DB
@@ -32,6 +43,7 @@ func NewRedemptionCodeRepository(db *DB) *RedemptionCodeRepository {
 }
 
 // FindByCode コードで引き換えコードを取得
+// トランザクション内で呼び出した場合は、更新するまでの間に他の変更が割り込まないよう行をロックする
 func (r *RedemptionCodeRepository) FindByCode(ctx context.Context, code string) (*redemption_code.RedemptionCode, error) {
 	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindByCode")
 	defer span.End()
@@ -43,36 +55,16 @@ func (r *RedemptionCodeRepository) FindByCode(ctx context.Context, code string)
 	)
 
 	query := `
-		SELECT 
-			code, code_type, currency_type, amount,
-			max_uses, current_uses, valid_from, valid_until,
-			status, metadata, created_at, updated_at
+		SELECT ` + redemptionCodeColumns + `
 		FROM redemption_codes
 		WHERE code = ?
 	`
+	if InTransaction(ctx) {
+		query += "FOR UPDATE"
+		span.SetAttributes(attribute.Bool("db.for_update", true))
+	}
 
-	var dbCode, dbCodeType, dbCurrencyType, dbStatus string
-	var amount int64
-	var maxUses, currentUses int
-	var validFrom, validUntil time.Time
-	var metadataJSON sql.NullString
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRowContext(ctx, query, code).Scan(
-		&dbCode,
-		&dbCodeType,
-		&dbCurrencyType,
-		&amount,
-		&maxUses,
-		&currentUses,
-		&validFrom,
-		&validUntil,
-		&dbStatus,
-		&metadataJSON,
-		&createdAt,
-		&updatedAt,
-	)
-
+	rc, err := scanRedemptionCode(r.db.executor(ctx).QueryRowContext(ctx, query, code))
 	if err == sql.ErrNoRows {
 		span.SetStatus(otelcodes.Ok, "redemption code not found")
 		return nil, redemption_code.ErrCodeNotFound
@@ -84,53 +76,13 @@ func (r *RedemptionCodeRepository) FindByCode(ctx context.Context, code string)
 	}
 
 	span.SetAttributes(
-		attribute.String("db.code_type", dbCodeType),
-		attribute.String("db.currency_type", dbCurrencyType),
-		attribute.Int64("db.amount", amount),
-		attribute.String("db.status", dbStatus),
+		attribute.String("db.code_type", rc.CodeType().String()),
+		attribute.String("db.currency_type", rc.CurrencyType().String()),
+		attribute.Int64("db.amount", rc.Amount()),
+		attribute.String("db.status", rc.Status().String()),
 	)
 	span.SetStatus(otelcodes.Ok, "redemption code found")
 
-	ct, err := redemption_code.NewCodeType(dbCodeType)
-	if err != nil {
-		return nil, fmt.Errorf("invalid code type: %w", err)
-	}
-
-	currencyType, err := currency.NewCurrencyType(dbCurrencyType)
-	if err != nil {
-		return nil, fmt.Errorf("invalid currency type: %w", err)
-	}
-
-	status, err := redemption_code.NewCodeStatus(dbStatus)
-	if err != nil {
-		return nil, fmt.Errorf("invalid code status: %w", err)
-	}
-
-	var metadata map[string]interface{}
-	if metadataJSON.Valid && metadataJSON.String != "" {
-		if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-	}
-
-	rc, err := redemption_code.NewRedemptionCode(
-		dbCode,
-		ct,
-		currencyType,
-		amount,
-		maxUses,
-		validFrom,
-		validUntil,
-		metadata,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create redemption code entity: %w", err)
-	}
-
-	// current_usesとstatusを設定
-	rc.SetCurrentUses(currentUses)
-	rc.SetStatus(status)
-
 	return rc, nil
 }
 
@@ -156,7 +108,7 @@ func (r *RedemptionCodeRepository) Update(ctx context.Context, code *redemption_
 		WHERE code = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := r.db.executor(ctx).ExecContext(ctx, query,
 		code.CurrentUses(),
 		code.Status().String(),
 		code.Code(),
@@ -180,293 +132,995 @@ func (r *RedemptionCodeRepository) Update(ctx context.Context, code *redemption_
 	return nil
 }
 
-// HasUserRedeemed ユーザーが既にこのコードを引き換え済みかチェック
-func (r *RedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
-	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.HasUserRedeemed")
+// IncrementUses 引き換えで使用回数を1増やす
+// 読み込んだ値を書き戻さずにDB上で加算し、条件を満たさない場合（同時の引き換えで上限に達した、
+// 無効化されたなど）はErrCodeNotRedeemableを返す
+func (r *RedemptionCodeRepository) IncrementUses(ctx context.Context, code string) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.IncrementUses")
 	defer span.End()
 
 	span.SetAttributes(
 		attribute.String("db.code", code),
-		attribute.String("db.user_id", userID),
-		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.table", "code_redemptions"),
+		attribute.String("db.operation", "UPDATE"),
+		attribute.String("db.table", "redemption_codes"),
 	)
 
 	query := `
-		SELECT COUNT(*) 
-		FROM code_redemptions
-		WHERE code = ? AND user_id = ?
+		UPDATE redemption_codes
+		SET
+			current_uses = current_uses + 1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE code = ?
+			AND status = 'active'
+			AND (max_uses = 0 OR current_uses < max_uses)
 	`
 
-	var count int
-	err := r.db.QueryRowContext(ctx, query, code, userID).Scan(&count)
+	result, err := r.db.executor(ctx).ExecContext(ctx, query, code)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return false, fmt.Errorf("failed to check redemption: %w", err)
+		return fmt.Errorf("failed to increment redemption code uses: %w", err)
 	}
 
-	span.SetAttributes(attribute.Int("db.count", count))
-	span.SetStatus(otelcodes.Ok, fmt.Sprintf("user redeemed: %v", count > 0))
-	return count > 0, nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
+	if rowsAffected == 0 {
+		span.SetStatus(otelcodes.Error, "redemption code not redeemable")
+		return redemption_code.ErrCodeNotRedeemable
+	}
+
+	span.SetStatus(otelcodes.Ok, "redemption code uses incremented")
+	return nil
 }
 
-// SaveRedemption 引き換え履歴を保存
-func (r *RedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
-	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.SaveRedemption")
+// FindExpiredActive 指定時刻時点で有効期限を過ぎたactiveのコードを有効期限の昇順で取得
+// トランザクション内で呼び出した場合は、失効させるまでの間に管理APIで変更されないよう行をロックする
+func (r *RedemptionCodeRepository) FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*redemption_code.RedemptionCode, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindExpiredActive")
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("db.redemption_id", redemption.RedemptionID()),
-		attribute.String("db.code", redemption.Code()),
-		attribute.String("db.user_id", redemption.UserID()),
-		attribute.String("db.transaction_id", redemption.TransactionID()),
-		attribute.String("db.operation", "INSERT"),
-		attribute.String("db.table", "code_redemptions"),
+		attribute.Int("db.limit", limit),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "redemption_codes"),
 	)
 
 	query := `
-		INSERT INTO code_redemptions (
-			redemption_id, code, user_id, transaction_id, redeemed_at
-		) VALUES (?, ?, ?, ?, ?)
+		SELECT ` + redemptionCodeColumns + `
+		FROM redemption_codes
+		WHERE status = 'active' AND valid_until < ?
+		ORDER BY valid_until ASC, code ASC
+		LIMIT ?
+		FOR UPDATE
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		redemption.RedemptionID(),
-		redemption.Code(),
-		redemption.UserID(),
-		redemption.TransactionID(),
-		redemption.RedeemedAt(),
-	)
-
+	codes, err := r.queryRedemptionCodes(ctx, query, now, limit)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return fmt.Errorf("failed to save redemption: %w", err)
+		return nil, err
 	}
 
-	span.SetStatus(otelcodes.Ok, "redemption saved")
-	return nil
+	span.SetAttributes(attribute.Int("db.count", len(codes)))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d expired redemption codes", len(codes)))
+	return codes, nil
 }
 
-// Create 引き換えコードを作成
-func (r *RedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
-	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.Create")
+// UpdateSettings 管理APIで変更できる設定（ステータス、有効期限、最大使用回数）を更新
+func (r *RedemptionCodeRepository) UpdateSettings(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.UpdateSettings")
 	defer span.End()
 
 	span.SetAttributes(
 		attribute.String("db.code", code.Code()),
-		attribute.String("db.code_type", code.CodeType().String()),
-		attribute.String("db.currency_type", code.CurrencyType().String()),
-		attribute.Int64("db.amount", code.Amount()),
+		attribute.String("db.status", code.Status().String()),
 		attribute.Int("db.max_uses", code.MaxUses()),
-		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.operation", "UPDATE"),
 		attribute.String("db.table", "redemption_codes"),
 	)
 
-	// メタデータをJSONに変換
-	var metadataJSON sql.NullString
-	if code.Metadata() != nil && len(code.Metadata()) > 0 {
-		metadataBytes, err := json.Marshal(code.Metadata())
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, err.Error())
-			return fmt.Errorf("failed to marshal metadata: %w", err)
-		}
-		metadataJSON = sql.NullString{
-			String: string(metadataBytes),
-			Valid:  true,
-		}
-	}
-
 	query := `
-		INSERT INTO redemption_codes (
-			code, code_type, currency_type, amount,
-			max_uses, current_uses, valid_from, valid_until,
-			status, metadata, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		UPDATE redemption_codes
+		SET
+			status = ?,
+			valid_until = ?,
+			max_uses = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE code = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		code.Code(),
-		code.CodeType().String(),
-		code.CurrencyType().String(),
-		code.Amount(),
-		code.MaxUses(),
-		code.CurrentUses(),
-		code.ValidFrom(),
-		code.ValidUntil(),
+	result, err := r.db.executor(ctx).ExecContext(ctx, query,
 		code.Status().String(),
-		metadataJSON,
-		code.CreatedAt(),
-		code.UpdatedAt(),
+		code.ValidUntil(),
+		code.MaxUses(),
+		code.Code(),
 	)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to update redemption code settings: %w", err)
+	}
 
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		// MySQLの重複キーエラーをチェック
-		if isDuplicateKeyError(err) {
-			span.SetStatus(otelcodes.Error, "code already exists")
-			return redemption_code.ErrCodeAlreadyExists
-		}
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return fmt.Errorf("failed to create redemption code: %w", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 
-	span.SetStatus(otelcodes.Ok, "redemption code created")
+	span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
+	span.SetStatus(otelcodes.Ok, "redemption code settings updated")
 	return nil
 }
 
-// Delete 引き換えコードを削除
-func (r *RedemptionCodeRepository) Delete(ctx context.Context, code string) error {
-	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.Delete")
+// SaveAuditLog 引き換えコードの変更履歴を保存
+func (r *RedemptionCodeRepository) SaveAuditLog(ctx context.Context, log *redemption_code.CodeAuditLog) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.SaveAuditLog")
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("db.code", code),
-		attribute.String("db.operation", "DELETE"),
-		attribute.String("db.table", "redemption_codes"),
+		attribute.String("db.audit_id", log.AuditID()),
+		attribute.String("db.code", log.Code()),
+		attribute.String("db.action", log.Action().String()),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "redemption_code_audit_logs"),
 	)
 
-	// まず、コードが使用されているかチェック
-	checkQuery := `
-		SELECT COUNT(*) 
-		FROM code_redemptions
-		WHERE code = ?
-	`
-	var count int
-	err := r.db.QueryRowContext(ctx, checkQuery, code).Scan(&count)
+	changes := make([]auditChangeRecord, 0, len(log.Changes()))
+	for _, c := range log.Changes() {
+		changes = append(changes, auditChangeRecord{Field: c.Field, From: c.From, To: c.To})
+	}
+	changesJSON, err := json.Marshal(changes)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return fmt.Errorf("failed to check code usage: %w", err)
+		return fmt.Errorf("failed to marshal audit changes: %w", err)
 	}
 
-	if count > 0 {
-		span.SetStatus(otelcodes.Error, "code has been used and cannot be deleted")
-		return redemption_code.ErrCodeCannotBeDeleted
+	var reason sql.NullString
+	if log.Reason() != "" {
+		reason = sql.NullString{String: log.Reason(), Valid: true}
 	}
 
-	// コードを削除
-	query := `DELETE FROM redemption_codes WHERE code = ?`
-
-	result, err := r.db.ExecContext(ctx, query, code)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, err.Error())
-		return fmt.Errorf("failed to delete redemption code: %w", err)
-	}
+	query := `
+		INSERT INTO redemption_code_audit_logs (
+			audit_id, code, action, changes, reason, requester, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
 
-	rowsAffected, err := result.RowsAffected()
+	_, err = r.db.executor(ctx).ExecContext(ctx, query,
+		log.AuditID(),
+		log.Code(),
+		log.Action().String(),
+		string(changesJSON),
+		reason,
+		log.Requester(),
+		log.CreatedAt(),
+	)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to save audit log: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		span.SetStatus(otelcodes.Ok, "redemption code not found")
-		return redemption_code.ErrCodeNotFound
-	}
-
-	span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
-	span.SetStatus(otelcodes.Ok, "redemption code deleted")
+	span.SetStatus(otelcodes.Ok, "audit log saved")
 	return nil
 }
 
-// FindAll 引き換えコードの一覧を取得（ページネーション対応）
-func (r *RedemptionCodeRepository) FindAll(ctx context.Context, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
-	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindAll")
+// auditChangeRecord changesカラムに保存する変更内容1件分
+type auditChangeRecord struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// HasUserRedeemed ユーザーが既にこのコードを引き換え済みかチェック
+func (r *RedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.HasUserRedeemed")
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.Int("db.limit", limit),
-		attribute.Int("db.offset", offset),
+		attribute.String("db.code", code),
+		attribute.String("db.user_id", userID),
 		attribute.String("db.operation", "SELECT"),
-		attribute.String("db.table", "redemption_codes"),
+		attribute.String("db.table", "code_redemptions"),
 	)
 
-	// 総件数を取得
-	countQuery := `SELECT COUNT(*) FROM redemption_codes`
-	var total int
-	err := r.db.QueryRowContext(ctx, countQuery).Scan(&total)
+	query := `
+		SELECT COUNT(*) 
+		FROM code_redemptions
+		WHERE code = ? AND user_id = ?
+	`
+
+	var count int
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, code, userID).Scan(&count)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, 0, fmt.Errorf("failed to count redemption codes: %w", err)
+		return false, fmt.Errorf("failed to check redemption: %w", err)
 	}
 
-	// 一覧を取得
+	span.SetAttributes(attribute.Int("db.count", count))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("user redeemed: %v", count > 0))
+	return count > 0, nil
+}
+
+// CountUserCampaignRedemptions ユーザーのキャンペーン内での引き換え状況を取得
+// トランザクション内で呼び出した場合は、コミット済みの最新の引き換えを数える（FOR SHARE）
+func (r *RedemptionCodeRepository) CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (redemption_code.CampaignUsage, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.CountUserCampaignRedemptions")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.campaign_id", campaignID),
+		attribute.String("db.user_id", userID),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "code_redemptions"),
+	)
+
 	query := `
-		SELECT 
-			code, code_type, currency_type, amount,
-			max_uses, current_uses, valid_from, valid_until,
-			status, metadata, created_at, updated_at
-		FROM redemption_codes
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
+		SELECT COUNT(*), COALESCE(SUM(rc.campaign_exclusive), 0)
+		FROM code_redemptions cr
+		INNER JOIN redemption_codes rc ON rc.code = cr.code
+		WHERE rc.campaign_id = ? AND cr.user_id = ?
 	`
+	if InTransaction(ctx) {
+		// スナップショットではなく最新の引き換えを数える
+		query += "FOR SHARE"
+		span.SetAttributes(attribute.Bool("db.for_share", true))
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	var usage redemption_code.CampaignUsage
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, campaignID, userID).Scan(&usage.Redemptions, &usage.ExclusiveRedemptions)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, 0, fmt.Errorf("failed to query redemption codes: %w", err)
+		return redemption_code.CampaignUsage{}, fmt.Errorf("failed to count campaign redemptions: %w", err)
 	}
-	defer rows.Close()
-
-	codes := []*redemption_code.RedemptionCode{}
-	for rows.Next() {
-		var dbCode, dbCodeType, dbCurrencyType, dbStatus string
-		var amount int64
-		var maxUses, currentUses int
-		var validFrom, validUntil time.Time
-		var metadataJSON sql.NullString
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(
-			&dbCode,
-			&dbCodeType,
-			&dbCurrencyType,
-			&amount,
-			&maxUses,
-			&currentUses,
-			&validFrom,
-			&validUntil,
-			&dbStatus,
-			&metadataJSON,
-			&createdAt,
-			&updatedAt,
-		)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, err.Error())
-			return nil, 0, fmt.Errorf("failed to scan redemption code: %w", err)
-		}
 
-		ct, err := redemption_code.NewCodeType(dbCodeType)
+	span.SetAttributes(
+		attribute.Int("db.count", usage.Redemptions),
+		attribute.Int("db.exclusive_count", usage.ExclusiveRedemptions),
+	)
+	span.SetStatus(otelcodes.Ok, "campaign redemptions counted")
+	return usage, nil
+}
+
+// LockUser ユーザーの行をロックする（未登録の場合は登録する）
+// 存在しない行へのSELECT ... FOR UPDATEはギャップロックになり同時実行時にデッドロックしやすいため、
+// 通貨の作成時と同じくINSERT ... ON DUPLICATE KEY UPDATEで行を確保してロックする
+func (r *RedemptionCodeRepository) LockUser(ctx context.Context, userID string) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.LockUser")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.user_id", userID),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "users"),
+	)
+
+	query := `
+		INSERT INTO users (user_id)
+		VALUES (?)
+		ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.executor(ctx).ExecContext(ctx, query, userID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "user locked")
+	return nil
+}
+
+// FindUserFirstSeenAt ユーザーが初めて登録された日時を取得（未登録の場合はゼロ値）
+func (r *RedemptionCodeRepository) FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindUserFirstSeenAt")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.user_id", userID),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "users"),
+	)
+
+	query := `
+		SELECT created_at
+		FROM users
+		WHERE user_id = ?
+	`
+
+	var createdAt time.Time
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, userID).Scan(&createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(otelcodes.Ok, "user not found")
+			return time.Time{}, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return time.Time{}, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "user found")
+	return createdAt, nil
+}
+
+// SaveRedemption 引き換え履歴を保存
+func (r *RedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.SaveRedemption")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.redemption_id", redemption.RedemptionID()),
+		attribute.String("db.code", redemption.Code()),
+		attribute.String("db.user_id", redemption.UserID()),
+		attribute.String("db.transaction_id", redemption.TransactionID()),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "code_redemptions"),
+	)
+
+	query := `
+		INSERT INTO code_redemptions (
+			redemption_id, code, user_id, transaction_id, redeemed_at
+		) VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.executor(ctx).ExecContext(ctx, query,
+		redemption.RedemptionID(),
+		redemption.Code(),
+		redemption.UserID(),
+		redemption.TransactionID(),
+		redemption.RedeemedAt(),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to save redemption: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "redemption saved")
+	return nil
+}
+
+// FindRedemptionsByCode コードの引き換え履歴を新しい順に取得（ページネーション対応）
+func (r *RedemptionCodeRepository) FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindRedemptionsByCode")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.code", code),
+		attribute.Int("db.limit", limit),
+		attribute.Int("db.offset", offset),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "code_redemptions"),
+	)
+
+	redemptions, total, err := r.findRedemptions(ctx, "cr.code = ?", code, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, 0, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("db.total", total),
+		attribute.Int("db.count", len(redemptions)),
+	)
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d redemptions", len(redemptions)))
+	return redemptions, total, nil
+}
+
+// FindRedemptionsByUserID ユーザーの引き換え履歴を新しい順に取得（ページネーション対応）
+func (r *RedemptionCodeRepository) FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindRedemptionsByUserID")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.user_id", userID),
+		attribute.Int("db.limit", limit),
+		attribute.Int("db.offset", offset),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "code_redemptions"),
+	)
+
+	redemptions, total, err := r.findRedemptions(ctx, "cr.user_id = ?", userID, limit, offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, 0, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("db.total", total),
+		attribute.Int("db.count", len(redemptions)),
+	)
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d redemptions", len(redemptions)))
+	return redemptions, total, nil
+}
+
+// findRedemptions 条件に一致する引き換え履歴の総件数と、新しい順の1ページ分を取得
+// 報酬ごとのトランザクションIDは、引き換えで記録したトランザクション（メタデータのredemption_id）から求める
+func (r *RedemptionCodeRepository) findRedemptions(ctx context.Context, where string, arg interface{}, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	countQuery := `SELECT COUNT(*) FROM code_redemptions cr WHERE ` + where
+	var total int
+	if err := r.db.executor(ctx).QueryRowContext(ctx, countQuery, arg).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count redemptions: %w", err)
+	}
+
+	query := `
+		SELECT cr.redemption_id, cr.code, cr.user_id, cr.transaction_id, cr.redeemed_at,
+			(
+				SELECT GROUP_CONCAT(t.transaction_id ORDER BY t.id ASC SEPARATOR ',')
+				FROM transactions t
+				WHERE t.user_id = cr.user_id
+					AND t.transaction_type = 'grant'
+					AND JSON_UNQUOTE(JSON_EXTRACT(t.metadata, '$.redemption_id')) = cr.redemption_id
+			) AS transaction_ids
+		FROM code_redemptions cr
+		WHERE ` + where + `
+		ORDER BY cr.redeemed_at DESC, cr.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, arg, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find redemptions: %w", err)
+	}
+	defer rows.Close()
+
+	var redemptions []*redemption_code.CodeRedemption
+	for rows.Next() {
+		var redemptionID, code, userID, transactionID string
+		var redeemedAt time.Time
+		var transactionIDs sql.NullString
+		if err := rows.Scan(&redemptionID, &code, &userID, &transactionID, &redeemedAt, &transactionIDs); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan redemption: %w", err)
+		}
+		redemption := redemption_code.NewCodeRedemption(redemptionID, code, userID, transactionID)
+		redemption.SetRedeemedAt(redeemedAt)
+		if transactionIDs.Valid && transactionIDs.String != "" {
+			redemption.SetTransactionIDs(strings.Split(transactionIDs.String, ","))
+		}
+		redemptions = append(redemptions, redemption)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate redemptions: %w", err)
+	}
+
+	return redemptions, total, nil
+}
+
+// GetCodeStats コードの引き換え回数・ユーザー数・付与した通貨の合計・日ごとの引き換え回数を集計
+// 付与した通貨の合計は、引き換えで記録したトランザクション（メタデータのredemption_id）の金額から求める。
+// 日付はDBのタイムゾーンで区切る
+func (r *RedemptionCodeRepository) GetCodeStats(ctx context.Context, code string) (redemption_code.CodeStats, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.GetCodeStats")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.code", code),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "code_redemptions"),
+	)
+
+	var stats redemption_code.CodeStats
+
+	totalQuery := `
+		SELECT COUNT(*), COUNT(DISTINCT user_id)
+		FROM code_redemptions
+		WHERE code = ?
+	`
+	if err := r.db.executor(ctx).QueryRowContext(ctx, totalQuery, code).Scan(&stats.Redemptions, &stats.UniqueUsers); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return redemption_code.CodeStats{}, fmt.Errorf("failed to count redemptions: %w", err)
+	}
+
+	granted, err := r.sumGranted(ctx, code)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return redemption_code.CodeStats{}, err
+	}
+	stats.Granted = granted
+
+	dailyQuery := `
+		SELECT DATE(redeemed_at) AS redeemed_on, COUNT(*)
+		FROM code_redemptions
+		WHERE code = ?
+		GROUP BY redeemed_on
+		ORDER BY redeemed_on ASC
+	`
+	rows, err := r.db.executor(ctx).QueryContext(ctx, dailyQuery, code)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return redemption_code.CodeStats{}, fmt.Errorf("failed to count daily redemptions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var daily redemption_code.DailyRedemptions
+		if err := rows.Scan(&daily.Date, &daily.Count); err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return redemption_code.CodeStats{}, fmt.Errorf("failed to scan daily redemptions: %w", err)
+		}
+		stats.Daily = append(stats.Daily, daily)
+	}
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return redemption_code.CodeStats{}, fmt.Errorf("failed to iterate daily redemptions: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("db.redemptions", stats.Redemptions),
+		attribute.Int("db.unique_users", stats.UniqueUsers),
+	)
+	span.SetStatus(otelcodes.Ok, "code stats aggregated")
+	return stats, nil
+}
+
+// sumGranted コードの引き換えで付与した通貨の合計を通貨タイプごとに集計
+func (r *RedemptionCodeRepository) sumGranted(ctx context.Context, code string) ([]redemption_code.GrantedTotal, error) {
+	query := `
+		SELECT t.currency_type, SUM(t.amount)
+		FROM code_redemptions cr
+		JOIN transactions t
+			ON t.user_id = cr.user_id
+			AND t.transaction_type = 'grant'
+			AND JSON_UNQUOTE(JSON_EXTRACT(t.metadata, '$.redemption_id')) = cr.redemption_id
+		WHERE cr.code = ?
+		GROUP BY t.currency_type
+		ORDER BY t.currency_type ASC
+	`
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum granted currency: %w", err)
+	}
+	defer rows.Close()
+
+	var granted []redemption_code.GrantedTotal
+	for rows.Next() {
+		var dbCurrencyType string
+		var amount int64
+		if err := rows.Scan(&dbCurrencyType, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan granted currency: %w", err)
+		}
+		currencyType, err := currency.NewCurrencyType(dbCurrencyType)
 		if err != nil {
-			return nil, 0, fmt.Errorf("invalid code type: %w", err)
+			return nil, fmt.Errorf("invalid currency type: %w", err)
 		}
+		granted = append(granted, redemption_code.GrantedTotal{CurrencyType: currencyType, Amount: amount})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate granted currency: %w", err)
+	}
+
+	return granted, nil
+}
+
+// Create 引き換えコードを作成
+func (r *RedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.Create")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.code", code.Code()),
+		attribute.String("db.code_type", code.CodeType().String()),
+		attribute.String("db.currency_type", code.CurrencyType().String()),
+		attribute.Int64("db.amount", code.Amount()),
+		attribute.Int("db.max_uses", code.MaxUses()),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "redemption_codes"),
+	)
+
+	args, err := redemptionCodeInsertArgs(code)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return err
+	}
+
+	query := `
+		INSERT INTO redemption_codes (
+			code, code_type, currency_type, amount, rewards,
+			max_uses, current_uses, valid_from, valid_until,
+			status, metadata, batch_id, campaign_id, campaign_exclusive,
+			eligibility, created_at, updated_at
+		) VALUES ` + redemptionCodeInsertPlaceholder
+
+	_, err = r.db.executor(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		// MySQLの重複キーエラーをチェック
+		if isDuplicateKeyError(err) {
+			span.SetStatus(otelcodes.Error, "code already exists")
+			return redemption_code.ErrCodeAlreadyExists
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to create redemption code: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "redemption code created")
+	return nil
+}
+
+// CreateBatch 複数の引き換えコードを一括で作成
+// createBatchChunkSize件ずつ複数行のINSERT文で挿入する。
+// 途中で失敗した場合に挿入済みの行を取り消すには、呼び出し側でトランザクション内で実行すること
+func (r *RedemptionCodeRepository) CreateBatch(ctx context.Context, codes []*redemption_code.RedemptionCode) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.CreateBatch")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.Int("db.count", len(codes)),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "redemption_codes"),
+	)
+
+	for start := 0; start < len(codes); start += createBatchChunkSize {
+		end := start + createBatchChunkSize
+		if end > len(codes) {
+			end = len(codes)
+		}
+		chunk := codes[start:end]
+
+		placeholders := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*17)
+		for _, code := range chunk {
+			codeArgs, err := redemptionCodeInsertArgs(code)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(otelcodes.Error, err.Error())
+				return err
+			}
+			placeholders = append(placeholders, redemptionCodeInsertPlaceholder)
+			args = append(args, codeArgs...)
+		}
+
+		query := `
+		INSERT INTO redemption_codes (
+			code, code_type, currency_type, amount, rewards,
+			max_uses, current_uses, valid_from, valid_until,
+			status, metadata, batch_id, campaign_id, campaign_exclusive,
+			eligibility, created_at, updated_at
+		) VALUES ` + strings.Join(placeholders, ", ")
+
+		if _, err := r.db.executor(ctx).ExecContext(ctx, query, args...); err != nil {
+			if isDuplicateKeyError(err) {
+				span.SetStatus(otelcodes.Error, "code already exists")
+				return redemption_code.ErrCodeAlreadyExists
+			}
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return fmt.Errorf("failed to create redemption codes: %w", err)
+		}
+	}
+
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("%d redemption codes created", len(codes)))
+	return nil
+}
+
+// FindByBatchID バッチIDで一括生成した引き換えコードを取得（コード順）
+func (r *RedemptionCodeRepository) FindByBatchID(ctx context.Context, batchID string) ([]*redemption_code.RedemptionCode, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindByBatchID")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.batch_id", batchID),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "redemption_codes"),
+	)
+
+	query := `
+		SELECT ` + redemptionCodeColumns + `
+		FROM redemption_codes
+		WHERE batch_id = ?
+		ORDER BY code
+	`
+
+	codes, err := r.queryRedemptionCodes(ctx, query, batchID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("db.count", len(codes)))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d redemption codes", len(codes)))
+	return codes, nil
+}
+
+// Delete 引き換えコードを削除
+func (r *RedemptionCodeRepository) Delete(ctx context.Context, code string) error {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.Delete")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.code", code),
+		attribute.String("db.operation", "DELETE"),
+		attribute.String("db.table", "redemption_codes"),
+	)
 
-		currencyType, err := currency.NewCurrencyType(dbCurrencyType)
+	// まず、コードが使用されているかチェック
+	checkQuery := `
+		SELECT COUNT(*) 
+		FROM code_redemptions
+		WHERE code = ?
+	`
+	var count int
+	err := r.db.executor(ctx).QueryRowContext(ctx, checkQuery, code).Scan(&count)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to check code usage: %w", err)
+	}
+
+	if count > 0 {
+		span.SetStatus(otelcodes.Error, "code has been used and cannot be deleted")
+		return redemption_code.ErrCodeCannotBeDeleted
+	}
+
+	// コードを削除
+	query := `DELETE FROM redemption_codes WHERE code = ?`
+
+	result, err := r.db.executor(ctx).ExecContext(ctx, query, code)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to delete redemption code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		span.SetStatus(otelcodes.Ok, "redemption code not found")
+		return redemption_code.ErrCodeNotFound
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
+	span.SetStatus(otelcodes.Ok, "redemption code deleted")
+	return nil
+}
+
+// FindByFilter 検索条件に一致する引き換えコードの一覧を取得（ページネーション対応）
+func (r *RedemptionCodeRepository) FindByFilter(ctx context.Context, filter redemption_code.Filter, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
+	ctx, span := r.tracer.Start(ctx, "RedemptionCodeRepository.FindByFilter")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.status", filter.Status.String()),
+		attribute.String("db.code_type", filter.CodeType.String()),
+		attribute.String("db.campaign_id", filter.CampaignID),
+		attribute.String("db.sort", filter.Sort.String()),
+		attribute.Int("db.limit", limit),
+		attribute.Int("db.offset", offset),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "redemption_codes"),
+	)
+
+	where, args := buildRedemptionCodeFilter(filter)
+
+	// 総件数を取得（ページングではなく検索条件に一致する件数）
+	countQuery := `SELECT COUNT(*) FROM redemption_codes WHERE ` + where
+	var total int
+	err := r.db.executor(ctx).QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, 0, fmt.Errorf("failed to count redemption codes: %w", err)
+	}
+
+	// 一覧を取得
+	query := `
+		SELECT ` + redemptionCodeColumns + `
+		FROM redemption_codes
+		WHERE ` + where + `
+		ORDER BY ` + redemptionCodeOrderBy(filter.Sort) + `
+		LIMIT ? OFFSET ?
+	`
+
+	codes, err := r.queryRedemptionCodes(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, 0, err
+	}
+
+	span.SetAttributes(
+		attribute.Int("db.total", total),
+		attribute.Int("db.count", len(codes)),
+	)
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d redemption codes", len(codes)))
+	return codes, total, nil
+}
+
+// buildRedemptionCodeFilter 検索条件からWHERE句とバインド引数を組み立てる
+func buildRedemptionCodeFilter(filter redemption_code.Filter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status.String())
+	}
+	if filter.CodeType != "" {
+		conditions = append(conditions, "code_type = ?")
+		args = append(args, filter.CodeType.String())
+	}
+	if filter.CurrencyType != "" {
+		// 複数の報酬を持つコードは2件目以降の報酬も対象にする
+		conditions = append(conditions, "(currency_type = ? OR JSON_CONTAINS(rewards, JSON_OBJECT('currency_type', ?)))")
+		args = append(args, filter.CurrencyType.String(), filter.CurrencyType.String())
+	}
+	if filter.CampaignID != "" {
+		// campaign_idカラム追加前のコードはmetadataのcampaign_idでキャンペーンを管理している
+		conditions = append(conditions, "(campaign_id = ? OR JSON_UNQUOTE(JSON_EXTRACT(metadata, '$.campaign_id')) = ?)")
+		args = append(args, filter.CampaignID, filter.CampaignID)
+	}
+	if filter.CodePrefix != "" {
+		conditions = append(conditions, "code LIKE ?")
+		args = append(args, escapeLike(filter.CodePrefix)+"%")
+	}
+	if filter.ValidFrom != nil {
+		conditions = append(conditions, "valid_until >= ?")
+		args = append(args, *filter.ValidFrom)
+	}
+	if filter.ValidUntil != nil {
+		conditions = append(conditions, "valid_from <= ?")
+		args = append(args, *filter.ValidUntil)
+	}
+
+	if len(conditions) == 0 {
+		return "1 = 1", args
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// redemptionCodeOrderBy 並び順に対応するORDER BY句を返す（同じ値の場合はコード順）
+func redemptionCodeOrderBy(sort redemption_code.SortOrder) string {
+	switch sort {
+	case redemption_code.SortCreatedAtAsc:
+		return "created_at ASC, code ASC"
+	case redemption_code.SortCodeAsc:
+		return "code ASC"
+	case redemption_code.SortValidUntilAsc:
+		return "valid_until ASC, code ASC"
+	case redemption_code.SortValidUntilDesc:
+		return "valid_until DESC, code ASC"
+	default:
+		return "created_at DESC, code ASC"
+	}
+}
+
+// escapeLike LIKE句のワイルドカード文字をエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// queryRedemptionCodes 引き換えコード一覧を取得するクエリを実行
+func (r *RedemptionCodeRepository) queryRedemptionCodes(ctx context.Context, query string, args ...interface{}) ([]*redemption_code.RedemptionCode, error) {
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query redemption codes: %w", err)
+	}
+	defer rows.Close()
+
+	codes := []*redemption_code.RedemptionCode{}
+	for rows.Next() {
+		rc, err := scanRedemptionCode(rows)
 		if err != nil {
-			return nil, 0, fmt.Errorf("invalid currency type: %w", err)
+			return nil, err
 		}
+		codes = append(codes, rc)
+	}
 
-		status, err := redemption_code.NewCodeStatus(dbStatus)
-		if err != nil {
-			return nil, 0, fmt.Errorf("invalid code status: %w", err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate redemption codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// scanRedemptionCode redemptionCodeColumnsの順で1行を読み取りエンティティを再構築
+// 行が存在しない場合はsql.ErrNoRowsをそのまま返す
+func scanRedemptionCode(row rowScanner) (*redemption_code.RedemptionCode, error) {
+	var dbCode, dbCodeType, dbCurrencyType, dbStatus string
+	var amount int64
+	var rewardsJSON sql.NullString
+	var maxUses, currentUses int
+	var validFrom, validUntil time.Time
+	var metadataJSON sql.NullString
+	var batchID, campaignID sql.NullString
+	var campaignExclusive bool
+	var eligibilityJSON sql.NullString
+	var createdAt, updatedAt time.Time
+
+	if err := row.Scan(
+		&dbCode,
+		&dbCodeType,
+		&dbCurrencyType,
+		&amount,
+		&rewardsJSON,
+		&maxUses,
+		&currentUses,
+		&validFrom,
+		&validUntil,
+		&dbStatus,
+		&metadataJSON,
+		&batchID,
+		&campaignID,
+		&campaignExclusive,
+		&eligibilityJSON,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
 		}
+		return nil, fmt.Errorf("failed to scan redemption code: %w", err)
+	}
 
-		var metadata map[string]interface{}
-		if metadataJSON.Valid && metadataJSON.String != "" {
-			if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
-				return nil, 0, fmt.Errorf("failed to unmarshal metadata: %w", err)
-			}
+	ct, err := redemption_code.NewCodeType(dbCodeType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid code type: %w", err)
+	}
+
+	currencyType, err := currency.NewCurrencyType(dbCurrencyType)
+	if err != nil {
+		return nil, fmt.Errorf("invalid currency type: %w", err)
+	}
+
+	status, err := redemption_code.NewCodeStatus(dbStatus)
+	if err != nil {
+		return nil, fmt.Errorf("invalid code status: %w", err)
+	}
+
+	var metadata map[string]interface{}
+	if metadataJSON.Valid && metadataJSON.String != "" {
+		if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
 		}
+	}
 
-		rc, err := redemption_code.NewRedemptionCode(
+	var rc *redemption_code.RedemptionCode
+	if rewardsJSON.Valid && rewardsJSON.String != "" {
+		// 複数の報酬を持つコード
+		rewards, err := unmarshalRewards(rewardsJSON.String)
+		if err != nil {
+			return nil, err
+		}
+		rc, err = redemption_code.NewRedemptionCodeWithRewards(
+			dbCode,
+			ct,
+			rewards,
+			maxUses,
+			validFrom,
+			validUntil,
+			metadata,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redemption code entity: %w", err)
+		}
+	} else {
+		rc, err = redemption_code.NewRedemptionCode(
 			dbCode,
 			ct,
 			currencyType,
@@ -477,27 +1131,188 @@ func (r *RedemptionCodeRepository) FindAll(ctx context.Context, limit, offset in
 			metadata,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to create redemption code entity: %w", err)
+			return nil, fmt.Errorf("failed to create redemption code entity: %w", err)
 		}
+	}
 
-		rc.SetCurrentUses(currentUses)
-		rc.SetStatus(status)
+	// current_uses・status・batch_idを設定
+	rc.SetCurrentUses(currentUses)
+	rc.SetStatus(status)
+	if batchID.Valid {
+		rc.SetBatchID(batchID.String)
+	}
 
-		codes = append(codes, rc)
+	// キャンペーンIDと引き換え条件を設定
+	eligibility, err := unmarshalEligibility(eligibilityJSON)
+	if err != nil {
+		return nil, err
+	}
+	eligibility.ExclusiveInCampaign = campaignExclusive
+	if err := rc.SetEligibility(campaignID.String, eligibility); err != nil {
+		return nil, fmt.Errorf("invalid eligibility: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, 0, fmt.Errorf("failed to iterate redemption codes: %w", err)
+	return rc, nil
+}
+
+// redemptionCodeInsertPlaceholder INSERT文の1行分のプレースホルダー（redemptionCodeInsertArgsと順序を合わせる）
+const redemptionCodeInsertPlaceholder = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+// redemptionCodeInsertArgs INSERT文の1行分の値を返す
+func redemptionCodeInsertArgs(code *redemption_code.RedemptionCode) ([]interface{}, error) {
+	// メタデータをJSONに変換
+	var metadataJSON sql.NullString
+	if len(code.Metadata()) > 0 {
+		metadataBytes, err := json.Marshal(code.Metadata())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		metadataJSON = sql.NullString{
+			String: string(metadataBytes),
+			Valid:  true,
+		}
 	}
 
-	span.SetAttributes(
-		attribute.Int("db.total", total),
-		attribute.Int("db.count", len(codes)),
-	)
-	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d redemption codes", len(codes)))
-	return codes, total, nil
+	// 報酬が1件のみの場合はcurrency_typeとamountで表せるためNULLにする
+	var rewardsJSON sql.NullString
+	if code.HasMultipleRewards() {
+		rewards, err := marshalRewards(code.Rewards())
+		if err != nil {
+			return nil, err
+		}
+		rewardsJSON = sql.NullString{String: rewards, Valid: true}
+	}
+
+	var batchID sql.NullString
+	if code.BatchID() != "" {
+		batchID = sql.NullString{String: code.BatchID(), Valid: true}
+	}
+
+	var campaignID sql.NullString
+	if code.CampaignID() != "" {
+		campaignID = sql.NullString{String: code.CampaignID(), Valid: true}
+	}
+
+	eligibilityJSON, err := marshalEligibility(code.Eligibility())
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		code.Code(),
+		code.CodeType().String(),
+		code.CurrencyType().String(),
+		code.Amount(),
+		rewardsJSON,
+		code.MaxUses(),
+		code.CurrentUses(),
+		code.ValidFrom(),
+		code.ValidUntil(),
+		code.Status().String(),
+		metadataJSON,
+		batchID,
+		campaignID,
+		code.Eligibility().ExclusiveInCampaign,
+		eligibilityJSON,
+		code.CreatedAt(),
+		code.UpdatedAt(),
+	}, nil
+}
+
+// eligibilityRecord eligibilityカラムに保存する引き換え条件
+// 排他条件は検索に使用するためcampaign_exclusiveカラムに保存する
+type eligibilityRecord struct {
+	AllowedUserIDs       []string   `json:"allowed_user_ids,omitempty"`
+	AllowedUserIDPrefix  string     `json:"allowed_user_id_prefix,omitempty"`
+	NewUsersSince        *time.Time `json:"new_users_since,omitempty"`
+	MaxPerUserInCampaign int        `json:"max_per_user_in_campaign,omitempty"`
+}
+
+// marshalEligibility 引き換え条件をeligibilityカラムのJSONに変換（条件がない場合はNULL）
+func marshalEligibility(e redemption_code.Eligibility) (sql.NullString, error) {
+	record := eligibilityRecord{
+		AllowedUserIDs:       e.AllowedUserIDs,
+		AllowedUserIDPrefix:  e.AllowedUserIDPrefix,
+		MaxPerUserInCampaign: e.MaxPerUserInCampaign,
+	}
+	if !e.NewUsersSince.IsZero() {
+		since := e.NewUsersSince.UTC()
+		record.NewUsersSince = &since
+	}
+	if len(record.AllowedUserIDs) == 0 && record.AllowedUserIDPrefix == "" && record.NewUsersSince == nil && record.MaxPerUserInCampaign == 0 {
+		return sql.NullString{}, nil
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal eligibility: %w", err)
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// unmarshalEligibility eligibilityカラムのJSONから引き換え条件を再構築
+func unmarshalEligibility(s sql.NullString) (redemption_code.Eligibility, error) {
+	if !s.Valid || s.String == "" {
+		return redemption_code.Eligibility{}, nil
+	}
+
+	var record eligibilityRecord
+	if err := json.Unmarshal([]byte(s.String), &record); err != nil {
+		return redemption_code.Eligibility{}, fmt.Errorf("failed to unmarshal eligibility: %w", err)
+	}
+
+	e := redemption_code.Eligibility{
+		AllowedUserIDs:       record.AllowedUserIDs,
+		AllowedUserIDPrefix:  record.AllowedUserIDPrefix,
+		MaxPerUserInCampaign: record.MaxPerUserInCampaign,
+	}
+	if record.NewUsersSince != nil {
+		e.NewUsersSince = *record.NewUsersSince
+	}
+	return e, nil
+}
+
+// rewardRecord rewardsカラムに保存する報酬1件分
+type rewardRecord struct {
+	CurrencyType string `json:"currency_type"`
+	Amount       int64  `json:"amount"`
+}
+
+// marshalRewards 報酬の一覧をrewardsカラムのJSONに変換
+func marshalRewards(rewards []redemption_code.Reward) (string, error) {
+	records := make([]rewardRecord, len(rewards))
+	for i, r := range rewards {
+		records[i] = rewardRecord{
+			CurrencyType: r.CurrencyType().String(),
+			Amount:       r.Amount(),
+		}
+	}
+	b, err := json.Marshal(records)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal rewards: %w", err)
+	}
+	return string(b), nil
+}
+
+// unmarshalRewards rewardsカラムのJSONから報酬の一覧を再構築
+func unmarshalRewards(s string) ([]redemption_code.Reward, error) {
+	var records []rewardRecord
+	if err := json.Unmarshal([]byte(s), &records); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal rewards: %w", err)
+	}
+
+	rewards := make([]redemption_code.Reward, len(records))
+	for i, record := range records {
+		ct, err := currency.NewCurrencyType(record.CurrencyType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reward currency type: %w", err)
+		}
+		rewards[i], err = redemption_code.NewReward(ct, record.Amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rewards, nil
 }
 
 // isDuplicateKeyError MySQLの重複キーエラーかどうかをチェック
@@ -3,7 +3,9 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -39,11 +41,12 @@ func TestRedemptionCodeRepository_FindByCode(t *testing.T) {
 			code: "TESTCODE123",
 			setupMock: func() {
 				rows := sqlmock.NewRows([]string{
-					"code", "code_type", "currency_type", "amount",
+					"code", "code_type", "currency_type", "amount", "rewards",
 					"max_uses", "current_uses", "valid_from", "valid_until",
-					"status", "metadata", "created_at", "updated_at",
+					"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+					"eligibility", "created_at", "updated_at",
 				}).
-					AddRow("TESTCODE123", "promotion", "paid", 1000, 1, 0, time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), "active", nil, time.Now(), time.Now())
+					AddRow("TESTCODE123", "promotion", "paid", 1000, nil, 1, 0, time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now())
 				mock.ExpectQuery(`SELECT`).
 					WithArgs("TESTCODE123").
 					WillReturnRows(rows)
@@ -98,6 +101,172 @@ func TestRedemptionCodeRepository_FindByCode(t *testing.T) {
 	}
 }
 
+func TestRedemptionCodeRepository_FindByCode_InTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mysqlDB := &DB{DB: db}
+	repo := NewRedemptionCodeRepository(mysqlDB)
+	txManager := NewTransactionManager(mysqlDB)
+
+	// トランザクション内では更新するまで行をロックする
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM redemption_codes\s+WHERE code = \?\s+FOR UPDATE`).
+		WithArgs("TESTCODE123").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"code", "code_type", "currency_type", "amount", "rewards",
+			"max_uses", "current_uses", "valid_from", "valid_until",
+			"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+			"eligibility", "created_at", "updated_at",
+		}).
+			AddRow("TESTCODE123", "promotion", "paid", 1000, nil, 1, 0, time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now()))
+	mock.ExpectCommit()
+
+	err = txManager.WithTransaction(context.Background(), func(ctx context.Context) error {
+		got, err := repo.FindByCode(ctx, "TESTCODE123")
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "TESTCODE123", got.Code())
+		return nil
+	})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedemptionCodeRepository_FindByCode_RewardsAndEligibility(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	columns := []string{
+		"code", "code_type", "currency_type", "amount", "rewards",
+		"max_uses", "current_uses", "valid_from", "valid_until",
+		"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+		"eligibility", "created_at", "updated_at",
+	}
+
+	t.Run("正常系: 複数の報酬を復元", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("BUNDLECODE", "event", "free", 100, `[{"currency_type":"free","amount":100},{"currency_type":"paid","amount":10}]`, 1, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now())
+		mock.ExpectQuery(`SELECT`).
+			WithArgs("BUNDLECODE").
+			WillReturnRows(rows)
+
+		got, err := repo.FindByCode(context.Background(), "BUNDLECODE")
+		require.NoError(t, err)
+		rewards := got.Rewards()
+		require.Len(t, rewards, 2)
+		assert.Equal(t, currency.CurrencyTypeFree, rewards[0].CurrencyType())
+		assert.Equal(t, int64(100), rewards[0].Amount())
+		assert.Equal(t, currency.CurrencyTypePaid, rewards[1].CurrencyType())
+		assert.Equal(t, int64(10), rewards[1].Amount())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: キャンペーンと引き換え条件を復元", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("CAMPAIGN1", "event", "free", 100, nil, 0, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, nil, "campaign_001", true, `{"allowed_user_id_prefix":"beta_","new_users_since":"2024-04-01T00:00:00Z","max_per_user_in_campaign":2}`, time.Now(), time.Now())
+		mock.ExpectQuery(`SELECT`).
+			WithArgs("CAMPAIGN1").
+			WillReturnRows(rows)
+
+		got, err := repo.FindByCode(context.Background(), "CAMPAIGN1")
+		require.NoError(t, err)
+		assert.Equal(t, "campaign_001", got.CampaignID())
+		eligibility := got.Eligibility()
+		assert.Equal(t, "beta_", eligibility.AllowedUserIDPrefix)
+		assert.True(t, eligibility.NewUsersSince.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, 2, eligibility.MaxPerUserInCampaign)
+		assert.True(t, eligibility.ExclusiveInCampaign)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: 不正な報酬", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("BUNDLECODE", "event", "free", 100, `[{"currency_type":"gold","amount":100}]`, 1, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now())
+		mock.ExpectQuery(`SELECT`).
+			WithArgs("BUNDLECODE").
+			WillReturnRows(rows)
+
+		_, err := repo.FindByCode(context.Background(), "BUNDLECODE")
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedemptionCodeRepository_IncrementUses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	query := `SET\s+current_uses = current_uses \+ 1,\s+updated_at = CURRENT_TIMESTAMP\s+WHERE code = \?\s+AND status = 'active'\s+AND \(max_uses = 0 OR current_uses < max_uses\)`
+
+	tests := []struct {
+		name      string
+		setupMock func()
+		wantError bool
+		errorType error
+	}{
+		{
+			name: "正常系: 使用回数を加算",
+			setupMock: func() {
+				mock.ExpectExec(query).
+					WithArgs("TESTCODE123").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "異常系: 最大使用回数に達している（行が更新されない）",
+			setupMock: func() {
+				mock.ExpectExec(query).
+					WithArgs("TESTCODE123").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantError: true,
+			errorType: redemption_code.ErrCodeNotRedeemable,
+		},
+		{
+			name: "異常系: DBエラー",
+			setupMock: func() {
+				mock.ExpectExec(query).
+					WithArgs("TESTCODE123").
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			err := repo.IncrementUses(context.Background(), "TESTCODE123")
+
+			if tt.wantError {
+				assert.Error(t, err)
+				if tt.errorType != nil {
+					assert.ErrorIs(t, err, tt.errorType)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestRedemptionCodeRepository_Update(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -260,6 +429,131 @@ func TestRedemptionCodeRepository_HasUserRedeemed(t *testing.T) {
 	}
 }
 
+func TestRedemptionCodeRepository_CountUserCampaignRedemptions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	t.Run("正常系: キャンペーン内の引き換え状況を取得", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"COUNT(*)", "exclusive"}).AddRow(3, 1)
+		mock.ExpectQuery(`SELECT COUNT\(\*\), COALESCE\(SUM\(rc.campaign_exclusive\), 0\)\s+FROM code_redemptions cr\s+INNER JOIN redemption_codes rc`).
+			WithArgs("campaign_001", "user123").
+			WillReturnRows(rows)
+
+		got, err := repo.CountUserCampaignRedemptions(context.Background(), "campaign_001", "user123")
+		require.NoError(t, err)
+		assert.Equal(t, redemption_code.CampaignUsage{Redemptions: 3, ExclusiveRedemptions: 1}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs("campaign_001", "user123").
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.CountUserCampaignRedemptions(context.Background(), "campaign_001", "user123")
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: トランザクション内では最新の引き換えを数える", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WHERE rc.campaign_id = \? AND cr.user_id = \?\s+FOR SHARE`).
+			WithArgs("campaign_001", "user123").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "exclusive"}).AddRow(1, 0))
+		mock.ExpectCommit()
+
+		err := NewTransactionManager(repo.db).WithTransaction(context.Background(), func(ctx context.Context) error {
+			got, err := repo.CountUserCampaignRedemptions(ctx, "campaign_001", "user123")
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, redemption_code.CampaignUsage{Redemptions: 1}, got)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedemptionCodeRepository_LockUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	t.Run("正常系: ユーザーの行をロック", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO users \(user_id\)\s+VALUES \(\?\)\s+ON DUPLICATE KEY UPDATE`).
+			WithArgs("user123").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		require.NoError(t, repo.LockUser(context.Background(), "user123"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectExec(`INSERT INTO users`).
+			WithArgs("user123").
+			WillReturnError(sql.ErrConnDone)
+
+		assert.Error(t, repo.LockUser(context.Background(), "user123"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedemptionCodeRepository_FindUserFirstSeenAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	t.Run("正常系: 登録日時を取得", func(t *testing.T) {
+		createdAt := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT created_at\s+FROM users`).
+			WithArgs("user123").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+		got, err := repo.FindUserFirstSeenAt(context.Background(), "user123")
+		require.NoError(t, err)
+		assert.Equal(t, createdAt, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: 未登録のユーザーはゼロ値", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT created_at`).
+			WithArgs("newuser").
+			WillReturnError(sql.ErrNoRows)
+
+		got, err := repo.FindUserFirstSeenAt(context.Background(), "newuser")
+		require.NoError(t, err)
+		assert.True(t, got.IsZero())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT created_at`).
+			WithArgs("user123").
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.FindUserFirstSeenAt(context.Background(), "user123")
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestRedemptionCodeRepository_SaveRedemption(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -282,6 +576,289 @@ func TestRedemptionCodeRepository_SaveRedemption(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestRedemptionCodeRepository_FindRedemptionsByCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	columns := []string{"redemption_id", "code", "user_id", "transaction_id", "redeemed_at", "transaction_ids"}
+	redeemedAt := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: コードの引き換え履歴を取得", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT\(\*\) FROM code_redemptions cr WHERE cr.code = \?`).
+			WithArgs("TESTCODE123").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(3))
+		mock.ExpectQuery(`SELECT cr.redemption_id, cr.code, cr.user_id, cr.transaction_id, cr.redeemed_at,\s+\(\s+SELECT GROUP_CONCAT\(t.transaction_id ORDER BY t.id ASC SEPARATOR ','\)\s+FROM transactions t\s+WHERE t.user_id = cr.user_id\s+AND t.transaction_type = 'grant'\s+AND JSON_UNQUOTE\(JSON_EXTRACT\(t.metadata, '\$.redemption_id'\)\) = cr.redemption_id\s+\) AS transaction_ids\s+FROM code_redemptions cr\s+WHERE cr.code = \?\s+ORDER BY cr.redeemed_at DESC, cr.id DESC\s+LIMIT \? OFFSET \?`).
+			WithArgs("TESTCODE123", 2, 0).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("red2", "TESTCODE123", "user2", "txn2", redeemedAt, "txn2,txn3").
+				AddRow("red1", "TESTCODE123", "user1", "txn1", redeemedAt.Add(-time.Hour), nil))
+
+		got, total, err := repo.FindRedemptionsByCode(context.Background(), "TESTCODE123", 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, got, 2)
+		assert.Equal(t, "red2", got[0].RedemptionID())
+		assert.Equal(t, "user2", got[0].UserID())
+		assert.Equal(t, "txn2", got[0].TransactionID())
+		assert.Equal(t, []string{"txn2", "txn3"}, got[0].TransactionIDs())
+		assert.Equal(t, redeemedAt, got[0].RedeemedAt())
+		// トランザクションが見つからない場合は引き換え履歴のトランザクションIDのみ
+		assert.Equal(t, []string{"txn1"}, got[1].TransactionIDs())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs("TESTCODE123").
+			WillReturnError(sql.ErrConnDone)
+
+		_, _, err := repo.FindRedemptionsByCode(context.Background(), "TESTCODE123", 2, 0)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedemptionCodeRepository_FindRedemptionsByUserID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	redeemedAt := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM code_redemptions cr WHERE cr.user_id = \?`).
+		WithArgs("user123").
+		WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+	mock.ExpectQuery(`FROM code_redemptions cr\s+WHERE cr.user_id = \?\s+ORDER BY cr.redeemed_at DESC, cr.id DESC`).
+		WithArgs("user123", 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"redemption_id", "code", "user_id", "transaction_id", "redeemed_at", "transaction_ids"}).
+			AddRow("red1", "TESTCODE123", "user123", "txn1", redeemedAt, "txn1"))
+
+	got, total, err := repo.FindRedemptionsByUserID(context.Background(), "user123", 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, got, 1)
+	assert.Equal(t, "TESTCODE123", got[0].Code())
+	assert.Equal(t, redeemedAt, got[0].RedeemedAt())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedemptionCodeRepository_GetCodeStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	t.Run("正常系: 引き換え回数と付与した通貨の合計、日ごとの回数を集計", func(t *testing.T) {
+		day1 := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+		day2 := time.Date(2024, 4, 3, 0, 0, 0, 0, time.UTC)
+		mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(DISTINCT user_id\)\s+FROM code_redemptions`).
+			WithArgs("TESTCODE123").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "COUNT(DISTINCT user_id)"}).AddRow(5, 4))
+		mock.ExpectQuery(`SELECT t.currency_type, SUM\(t.amount\)\s+FROM code_redemptions cr\s+JOIN transactions t\s+ON t.user_id = cr.user_id\s+AND t.transaction_type = 'grant'\s+AND JSON_UNQUOTE\(JSON_EXTRACT\(t.metadata, '\$.redemption_id'\)\) = cr.redemption_id\s+WHERE cr.code = \?\s+GROUP BY t.currency_type`).
+			WithArgs("TESTCODE123").
+			WillReturnRows(sqlmock.NewRows([]string{"currency_type", "SUM(t.amount)"}).
+				AddRow("free", 480).
+				AddRow("paid", 50))
+		mock.ExpectQuery(`SELECT DATE\(redeemed_at\) AS redeemed_on, COUNT\(\*\)\s+FROM code_redemptions\s+WHERE code = \?\s+GROUP BY redeemed_on`).
+			WithArgs("TESTCODE123").
+			WillReturnRows(sqlmock.NewRows([]string{"redeemed_on", "COUNT(*)"}).
+				AddRow(day1, 2).
+				AddRow(day2, 3))
+
+		got, err := repo.GetCodeStats(context.Background(), "TESTCODE123")
+		require.NoError(t, err)
+		assert.Equal(t, redemption_code.CodeStats{
+			Redemptions: 5,
+			UniqueUsers: 4,
+			Granted: []redemption_code.GrantedTotal{
+				{CurrencyType: currency.CurrencyTypeFree, Amount: 480},
+				{CurrencyType: currency.CurrencyTypePaid, Amount: 50},
+			},
+			Daily: []redemption_code.DailyRedemptions{
+				{Date: day1, Count: 2},
+				{Date: day2, Count: 3},
+			},
+		}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: 引き換えなし", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs("UNUSED").
+			WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)", "COUNT(DISTINCT user_id)"}).AddRow(0, 0))
+		mock.ExpectQuery(`SELECT t.currency_type, SUM\(t.amount\)`).
+			WithArgs("UNUSED").
+			WillReturnRows(sqlmock.NewRows([]string{"currency_type", "SUM(t.amount)"}))
+		mock.ExpectQuery(`SELECT DATE\(redeemed_at\)`).
+			WithArgs("UNUSED").
+			WillReturnRows(sqlmock.NewRows([]string{"redeemed_on", "COUNT(*)"}))
+
+		got, err := repo.GetCodeStats(context.Background(), "UNUSED")
+		require.NoError(t, err)
+		assert.Equal(t, redemption_code.CodeStats{}, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs("TESTCODE123").
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.GetCodeStats(context.Background(), "TESTCODE123")
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedemptionCodeRepository_FindExpiredActive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	columns := []string{
+		"code", "code_type", "currency_type", "amount", "rewards",
+		"max_uses", "current_uses", "valid_from", "valid_until",
+		"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+		"eligibility", "created_at", "updated_at",
+	}
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 有効期限切れのactiveなコードを取得", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("EXPIRED1", "event", "free", 100, nil, 0, 3, now.Add(-48*time.Hour), now.Add(-time.Hour), "active", nil, nil, nil, false, nil, now, now)
+		mock.ExpectQuery(`FROM redemption_codes\s+WHERE status = 'active' AND valid_until < \?\s+ORDER BY valid_until ASC, code ASC\s+LIMIT \?\s+FOR UPDATE`).
+			WithArgs(now, 1).
+			WillReturnRows(rows)
+
+		codes, err := repo.FindExpiredActive(context.Background(), now, 1)
+		require.NoError(t, err)
+		require.Len(t, codes, 1)
+		assert.Equal(t, "EXPIRED1", codes[0].Code())
+		assert.Equal(t, redemption_code.CodeStatusActive, codes[0].Status())
+		assert.Equal(t, 3, codes[0].CurrentUses())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).
+			WithArgs(now, 1).
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.FindExpiredActive(context.Background(), now, 1)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRedemptionCodeRepository_UpdateSettings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	validUntil := time.Now().Add(48 * time.Hour)
+	rc := redemption_code.MustNewRedemptionCode(
+		"TESTCODE123",
+		redemption_code.CodeTypePromotion,
+		currency.CurrencyTypePaid,
+		1000,
+		100,
+		time.Now().Add(-24*time.Hour),
+		validUntil,
+		nil,
+	)
+	rc.Disable()
+
+	// 使用回数は更新しない
+	mock.ExpectExec(`UPDATE redemption_codes\s+SET\s+status = \?,\s+valid_until = \?,\s+max_uses = \?`).
+		WithArgs("disabled", validUntil, 100, "TESTCODE123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateSettings(context.Background(), rc)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedemptionCodeRepository_SaveAuditLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	tests := []struct {
+		name       string
+		reason     string
+		wantReason interface{}
+	}{
+		{
+			name:       "正常系: 変更理由あり",
+			reason:     "campaign extended",
+			wantReason: "campaign extended",
+		},
+		{
+			name:       "正常系: 変更理由なし",
+			wantReason: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := redemption_code.NewCodeAuditLog(
+				"audit123",
+				"TESTCODE123",
+				redemption_code.AuditActionUpdate,
+				[]redemption_code.FieldChange{{Field: "max_uses", From: "100", To: "200"}},
+				tt.reason,
+				"ops-tool",
+			)
+
+			mock.ExpectExec(`INSERT INTO redemption_code_audit_logs`).
+				WithArgs(
+					"audit123",
+					"TESTCODE123",
+					"update",
+					`[{"field":"max_uses","from":"100","to":"200"}]`,
+					tt.wantReason,
+					"ops-tool",
+					sqlmock.AnyArg(),
+				).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err := repo.SaveAuditLog(context.Background(), log)
+			assert.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestRedemptionCodeRepository_Create(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -322,14 +899,19 @@ func TestRedemptionCodeRepository_Create(t *testing.T) {
 						"promotion",
 						"paid",
 						int64(1000),
+						sql.NullString{Valid: false}, // rewards
 						100,
 						0,
 						sqlmock.AnyArg(), // valid_from
 						sqlmock.AnyArg(), // valid_until
 						"active",
-						sqlmock.AnyArg(), // metadata JSON
-						sqlmock.AnyArg(), // created_at
-						sqlmock.AnyArg(), // updated_at
+						sqlmock.AnyArg(),             // metadata JSON
+						sql.NullString{Valid: false}, // batch_id
+						sql.NullString{Valid: false}, // campaign_id
+						false,                        // campaign_exclusive
+						sql.NullString{Valid: false}, // eligibility
+						sqlmock.AnyArg(),             // created_at
+						sqlmock.AnyArg(),             // updated_at
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -358,12 +940,103 @@ func TestRedemptionCodeRepository_Create(t *testing.T) {
 						"gift",
 						"free",
 						int64(500),
+						sql.NullString{Valid: false}, // rewards
+						0,
+						0,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+						"active",
+						sql.NullString{Valid: false},
+						sql.NullString{Valid: false},
+						sql.NullString{Valid: false},
+						false,
+						sql.NullString{Valid: false},
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantError: false,
+		},
+		{
+			name: "正常系: 複数の報酬を持つコードを作成",
+			code: func() *redemption_code.RedemptionCode {
+				free, _ := redemption_code.NewReward(currency.CurrencyTypeFree, 100)
+				paid, _ := redemption_code.NewReward(currency.CurrencyTypePaid, 10)
+				rc, _ := redemption_code.NewRedemptionCodeWithRewards(
+					"BUNDLECODE",
+					redemption_code.CodeTypeEvent,
+					[]redemption_code.Reward{free, paid},
+					1,
+					time.Now(),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				return rc
+			}(),
+			setupMock: func() {
+				mock.ExpectExec(`INSERT INTO redemption_codes`).
+					WithArgs(
+						"BUNDLECODE",
+						"event",
+						"free",
+						int64(100),
+						sql.NullString{String: `[{"currency_type":"free","amount":100},{"currency_type":"paid","amount":10}]`, Valid: true},
+						1,
+						0,
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+						"active",
+						sql.NullString{Valid: false},
+						sql.NullString{Valid: false},
+						sql.NullString{Valid: false},
+						false,
+						sql.NullString{Valid: false},
+						sqlmock.AnyArg(),
+						sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+			wantError: false,
+		},
+		{
+			name: "正常系: 引き換え条件を持つコードを作成",
+			code: func() *redemption_code.RedemptionCode {
+				rc := redemption_code.MustNewRedemptionCode(
+					"CAMPAIGN1",
+					redemption_code.CodeTypeEvent,
+					currency.CurrencyTypeFree,
+					100,
+					0,
+					time.Now(),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				_ = rc.SetEligibility("campaign_001", redemption_code.Eligibility{
+					AllowedUserIDs:       []string{"user123"},
+					MaxPerUserInCampaign: 1,
+					ExclusiveInCampaign:  true,
+				})
+				return rc
+			}(),
+			setupMock: func() {
+				mock.ExpectExec(`INSERT INTO redemption_codes`).
+					WithArgs(
+						"CAMPAIGN1",
+						"event",
+						"free",
+						int64(100),
+						sql.NullString{Valid: false},
 						0,
 						0,
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 						"active",
 						sql.NullString{Valid: false},
+						sql.NullString{Valid: false},
+						sql.NullString{String: "campaign_001", Valid: true},
+						true,
+						sql.NullString{String: `{"allowed_user_ids":["user123"],"max_per_user_in_campaign":1}`, Valid: true},
 						sqlmock.AnyArg(),
 						sqlmock.AnyArg(),
 					).
@@ -438,6 +1111,169 @@ func TestRedemptionCodeRepository_Create(t *testing.T) {
 	}
 }
 
+func TestRedemptionCodeRepository_CreateBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	newCodes := func(n int) []*redemption_code.RedemptionCode {
+		codes := make([]*redemption_code.RedemptionCode, n)
+		for i := range codes {
+			codes[i] = redemption_code.MustNewRedemptionCode(
+				fmt.Sprintf("BATCH%05d", i),
+				redemption_code.CodeTypePromotion,
+				currency.CurrencyTypeFree,
+				100,
+				1,
+				time.Now(),
+				time.Now().Add(24*time.Hour),
+				nil,
+			)
+			codes[i].SetBatchID("batch_001")
+		}
+		return codes
+	}
+
+	tests := []struct {
+		name      string
+		codes     []*redemption_code.RedemptionCode
+		setupMock func()
+		wantError bool
+		errorType error
+	}{
+		{
+			name:  "正常系: 1回のINSERT文でまとめて作成",
+			codes: newCodes(2),
+			setupMock: func() {
+				mock.ExpectExec(`INSERT INTO redemption_codes .* VALUES \(\?(, \?){16}\), \(\?(, \?){16}\)$`).
+					WithArgs(
+						"BATCH00000", "promotion", "free", int64(100), sql.NullString{Valid: false}, 1, 0,
+						sqlmock.AnyArg(), sqlmock.AnyArg(), "active",
+						sql.NullString{Valid: false},
+						sql.NullString{String: "batch_001", Valid: true},
+						sql.NullString{Valid: false}, false, sql.NullString{Valid: false},
+						sqlmock.AnyArg(), sqlmock.AnyArg(),
+						"BATCH00001", "promotion", "free", int64(100), sql.NullString{Valid: false}, 1, 0,
+						sqlmock.AnyArg(), sqlmock.AnyArg(), "active",
+						sql.NullString{Valid: false},
+						sql.NullString{String: "batch_001", Valid: true},
+						sql.NullString{Valid: false}, false, sql.NullString{Valid: false},
+						sqlmock.AnyArg(), sqlmock.AnyArg(),
+					).
+					WillReturnResult(sqlmock.NewResult(1, 2))
+			},
+		},
+		{
+			name:  "正常系: 上限を超える件数は分割して作成",
+			codes: newCodes(createBatchChunkSize + 1),
+			setupMock: func() {
+				mock.ExpectExec(`INSERT INTO redemption_codes`).
+					WillReturnResult(sqlmock.NewResult(1, createBatchChunkSize))
+				mock.ExpectExec(`INSERT INTO redemption_codes .* VALUES \(\?(, \?){16}\)$`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name:  "異常系: コードが既に存在",
+			codes: newCodes(2),
+			setupMock: func() {
+				mock.ExpectExec(`INSERT INTO redemption_codes`).
+					WillReturnError(errors.New("Error 1062: Duplicate entry 'BATCH00001' for key 'code'"))
+			},
+			wantError: true,
+			errorType: redemption_code.ErrCodeAlreadyExists,
+		},
+		{
+			name:  "異常系: DBエラー",
+			codes: newCodes(2),
+			setupMock: func() {
+				mock.ExpectExec(`INSERT INTO redemption_codes`).
+					WillReturnError(sql.ErrConnDone)
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			err := repo.CreateBatch(context.Background(), tt.codes)
+
+			if tt.wantError {
+				assert.Error(t, err)
+				if tt.errorType != nil {
+					assert.Equal(t, tt.errorType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRedemptionCodeRepository_FindByBatchID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := &RedemptionCodeRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+
+	columns := []string{
+		"code", "code_type", "currency_type", "amount", "rewards",
+		"max_uses", "current_uses", "valid_from", "valid_until",
+		"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+		"eligibility", "created_at", "updated_at",
+	}
+
+	t.Run("正常系: バッチのコードを取得", func(t *testing.T) {
+		rows := sqlmock.NewRows(columns).
+			AddRow("INF2AAAA", "promotion", "free", 100, nil, 1, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, "batch_001", nil, false, nil, time.Now(), time.Now()).
+			AddRow("INF2BBBB", "promotion", "free", 100, nil, 1, 1, time.Now(), time.Now().Add(24*time.Hour), "active", nil, "batch_001", nil, false, nil, time.Now(), time.Now())
+		mock.ExpectQuery(`SELECT .* FROM redemption_codes\s+WHERE batch_id = \?\s+ORDER BY code`).
+			WithArgs("batch_001").
+			WillReturnRows(rows)
+
+		codes, err := repo.FindByBatchID(context.Background(), "batch_001")
+		require.NoError(t, err)
+		require.Len(t, codes, 2)
+		assert.Equal(t, "INF2AAAA", codes[0].Code())
+		assert.Equal(t, "batch_001", codes[0].BatchID())
+		assert.Equal(t, 1, codes[1].CurrentUses())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: 該当なし", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).
+			WithArgs("unknown").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		codes, err := repo.FindByBatchID(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, codes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: DBエラー", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).
+			WithArgs("batch_001").
+			WillReturnError(sql.ErrConnDone)
+
+		_, err := repo.FindByBatchID(context.Background(), "batch_001")
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestRedemptionCodeRepository_Delete(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
@@ -550,7 +1386,7 @@ func TestRedemptionCodeRepository_Delete(t *testing.T) {
 	}
 }
 
-func TestRedemptionCodeRepository_FindAll(t *testing.T) {
+func TestRedemptionCodeRepository_FindByFilter(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	require.NoError(t, err)
 	defer db.Close()
@@ -560,8 +1396,12 @@ func TestRedemptionCodeRepository_FindAll(t *testing.T) {
 		tracer: otel.Tracer("test"),
 	}
 
+	windowFrom := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	windowUntil := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+
 	tests := []struct {
 		name      string
+		filter    redemption_code.Filter
 		limit     int
 		offset    int
 		setupMock func()
@@ -580,13 +1420,14 @@ func TestRedemptionCodeRepository_FindAll(t *testing.T) {
 					WillReturnRows(countRows)
 				// 一覧取得
 				rows := sqlmock.NewRows([]string{
-					"code", "code_type", "currency_type", "amount",
+					"code", "code_type", "currency_type", "amount", "rewards",
 					"max_uses", "current_uses", "valid_from", "valid_until",
-					"status", "metadata", "created_at", "updated_at",
+					"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+					"eligibility", "created_at", "updated_at",
 				}).
-					AddRow("CODE1", "promotion", "paid", 1000, 100, 0, time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), "active", nil, time.Now(), time.Now()).
-					AddRow("CODE2", "gift", "free", 500, 0, 5, time.Now().Add(-12*time.Hour), time.Now().Add(12*time.Hour), "active", `{"campaign_id":"campaign_001"}`, time.Now(), time.Now()).
-					AddRow("CODE3", "event", "paid", 2000, 50, 10, time.Now().Add(-6*time.Hour), time.Now().Add(6*time.Hour), "active", nil, time.Now(), time.Now())
+					AddRow("CODE1", "promotion", "paid", 1000, nil, 100, 0, time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now()).
+					AddRow("CODE2", "gift", "free", 500, nil, 0, 5, time.Now().Add(-12*time.Hour), time.Now().Add(12*time.Hour), "active", `{"campaign_id":"campaign_001"}`, nil, nil, false, nil, time.Now(), time.Now()).
+					AddRow("CODE3", "event", "paid", 2000, nil, 50, 10, time.Now().Add(-6*time.Hour), time.Now().Add(6*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now())
 				mock.ExpectQuery(`SELECT`).
 					WithArgs(10, 0).
 					WillReturnRows(rows)
@@ -606,9 +1447,10 @@ func TestRedemptionCodeRepository_FindAll(t *testing.T) {
 					WillReturnRows(countRows)
 				// 一覧取得（空）
 				rows := sqlmock.NewRows([]string{
-					"code", "code_type", "currency_type", "amount",
+					"code", "code_type", "currency_type", "amount", "rewards",
 					"max_uses", "current_uses", "valid_from", "valid_until",
-					"status", "metadata", "created_at", "updated_at",
+					"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+					"eligibility", "created_at", "updated_at",
 				})
 				mock.ExpectQuery(`SELECT`).
 					WithArgs(10, 0).
@@ -629,12 +1471,13 @@ func TestRedemptionCodeRepository_FindAll(t *testing.T) {
 					WillReturnRows(countRows)
 				// 一覧取得
 				rows := sqlmock.NewRows([]string{
-					"code", "code_type", "currency_type", "amount",
+					"code", "code_type", "currency_type", "amount", "rewards",
 					"max_uses", "current_uses", "valid_from", "valid_until",
-					"status", "metadata", "created_at", "updated_at",
+					"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+					"eligibility", "created_at", "updated_at",
 				}).
-					AddRow("CODE11", "promotion", "paid", 1000, 100, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, time.Now(), time.Now()).
-					AddRow("CODE12", "gift", "free", 500, 0, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, time.Now(), time.Now())
+					AddRow("CODE11", "promotion", "paid", 1000, nil, 100, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now()).
+					AddRow("CODE12", "gift", "free", 500, nil, 0, 0, time.Now(), time.Now().Add(24*time.Hour), "active", nil, nil, nil, false, nil, time.Now(), time.Now())
 				mock.ExpectQuery(`SELECT`).
 					WithArgs(5, 10).
 					WillReturnRows(rows)
@@ -643,6 +1486,43 @@ func TestRedemptionCodeRepository_FindAll(t *testing.T) {
 			wantTotal: 20,
 			wantError: false,
 		},
+		{
+			name: "正常系: 検索条件と並び順を指定",
+			filter: redemption_code.Filter{
+				Status:       redemption_code.CodeStatusActive,
+				CodeType:     redemption_code.CodeTypeEvent,
+				CurrencyType: currency.CurrencyTypePaid,
+				CampaignID:   "campaign_001",
+				CodePrefix:   "INF_",
+				ValidFrom:    &windowFrom,
+				ValidUntil:   &windowUntil,
+				Sort:         redemption_code.SortValidUntilAsc,
+			},
+			limit:  10,
+			offset: 0,
+			setupMock: func() {
+				filterArgs := []driver.Value{
+					"active", "event", "paid", "paid", "campaign_001", "campaign_001", `INF\_%`, windowFrom, windowUntil,
+				}
+				// 総件数は検索条件に一致する件数
+				mock.ExpectQuery(`SELECT COUNT\(\*\) FROM redemption_codes WHERE status = \? AND code_type = \? AND \(currency_type = \? OR JSON_CONTAINS\(rewards, .+\)\) AND \(campaign_id = \? OR .+metadata.+\) AND code LIKE \? AND valid_until >= \? AND valid_from <= \?`).
+					WithArgs(filterArgs...).
+					WillReturnRows(sqlmock.NewRows([]string{"COUNT(*)"}).AddRow(1))
+				rows := sqlmock.NewRows([]string{
+					"code", "code_type", "currency_type", "amount", "rewards",
+					"max_uses", "current_uses", "valid_from", "valid_until",
+					"status", "metadata", "batch_id", "campaign_id", "campaign_exclusive",
+					"eligibility", "created_at", "updated_at",
+				}).
+					AddRow("INF_001", "event", "paid", 100, nil, 0, 0, windowFrom, windowUntil, "active", nil, nil, "campaign_001", false, nil, time.Now(), time.Now())
+				mock.ExpectQuery(`SELECT .+ FROM redemption_codes WHERE .+ ORDER BY valid_until ASC, code ASC\s+LIMIT \? OFFSET \?`).
+					WithArgs(append(filterArgs, 10, 0)...).
+					WillReturnRows(rows)
+			},
+			wantCount: 1,
+			wantTotal: 1,
+			wantError: false,
+		},
 		{
 			name:   "異常系: 総件数取得でDBエラー",
 			limit:  10,
@@ -675,7 +1555,7 @@ func TestRedemptionCodeRepository_FindAll(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.setupMock()
 			ctx := context.Background()
-			codes, total, err := repo.FindAll(ctx, tt.limit, tt.offset)
+			codes, total, err := repo.FindByFilter(ctx, tt.filter, tt.limit, tt.offset)
 
 			if tt.wantError {
 				assert.Error(t, err)
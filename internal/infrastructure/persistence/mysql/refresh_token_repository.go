@@ -0,0 +1,220 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"gem-server/internal/domain/auth_token"
+)
+
+// RefreshTokenRepository MySQL実装のRefreshTokenRepository
+type RefreshTokenRepository struct {
+	db     *DB
+	tracer trace.Tracer
+}
+
+// NewRefreshTokenRepository 新しいRefreshTokenRepositoryを作成
+func NewRefreshTokenRepository(db *DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		db:     db,
+		tracer: otel.Tracer("refresh-token-repository"),
+	}
+}
+
+// Save リフレッシュトークンを保存
+func (r *RefreshTokenRepository) Save(ctx context.Context, token *auth_token.RefreshToken) error {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.Save")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.token_id", token.TokenID()),
+		attribute.String("db.user_id", token.UserID()),
+		attribute.String("db.family_id", token.FamilyID()),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "refresh_tokens"),
+	)
+
+	query := `
+		INSERT INTO refresh_tokens (token_id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := r.db.executor(ctx).ExecContext(ctx, query,
+		token.TokenID(),
+		token.UserID(),
+		token.FamilyID(),
+		token.TokenHash(),
+		token.ExpiresAt(),
+		token.CreatedAt(),
+	)
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "refresh token saved")
+	return nil
+}
+
+// FindByHash トークンのハッシュでリフレッシュトークンを取得
+// トランザクション内で呼び出した場合は、ローテーションが完了するまで行をロックする
+func (r *RefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_token.RefreshToken, error) {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.FindByHash")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "refresh_tokens"),
+	)
+
+	query := `
+		SELECT token_id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at
+		FROM refresh_tokens
+		WHERE token_hash = ?
+		FOR UPDATE
+	`
+
+	var (
+		tokenID, userID, familyID, hash string
+		expiresAt, createdAt            time.Time
+		revokedAt                       sql.NullTime
+		replacedBy                      sql.NullString
+	)
+	err := r.db.executor(ctx).QueryRowContext(ctx, query, tokenHash).Scan(
+		&tokenID, &userID, &familyID, &hash, &expiresAt, &revokedAt, &replacedBy, &createdAt,
+	)
+	if err == sql.ErrNoRows {
+		span.SetStatus(otelcodes.Ok, "refresh token not found")
+		return nil, auth_token.ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	token := auth_token.NewRefreshToken(tokenID, userID, familyID, hash, expiresAt)
+	if revokedAt.Valid {
+		token.SetRevoked(&revokedAt.Time, replacedBy.String)
+	}
+	token.SetCreatedAt(createdAt)
+
+	span.SetAttributes(
+		attribute.String("db.token_id", tokenID),
+		attribute.String("db.user_id", userID),
+		attribute.Bool("db.revoked", token.IsRevoked()),
+	)
+	span.SetStatus(otelcodes.Ok, "refresh token found")
+
+	return token, nil
+}
+
+// Update リフレッシュトークンの失効状態を更新
+func (r *RefreshTokenRepository) Update(ctx context.Context, token *auth_token.RefreshToken) error {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.Update")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.token_id", token.TokenID()),
+		attribute.String("db.operation", "UPDATE"),
+		attribute.String("db.table", "refresh_tokens"),
+	)
+
+	var revokedAt sql.NullTime
+	if token.RevokedAt() != nil {
+		revokedAt = sql.NullTime{Time: *token.RevokedAt(), Valid: true}
+	}
+	var replacedBy sql.NullString
+	if token.ReplacedBy() != "" {
+		replacedBy = sql.NullString{String: token.ReplacedBy(), Valid: true}
+	}
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = ?, replaced_by = ?
+		WHERE token_id = ?
+	`
+
+	result, err := r.db.executor(ctx).ExecContext(ctx, query, revokedAt, replacedBy, token.TokenID())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to update refresh token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		span.SetStatus(otelcodes.Error, "refresh token not found")
+		return auth_token.ErrRefreshTokenNotFound
+	}
+
+	span.SetStatus(otelcodes.Ok, "refresh token updated")
+	return nil
+}
+
+// RevokeFamily 系列の未失効のリフレッシュトークンをすべて失効させ、失効させた件数を返す
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.RevokeFamily")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("db.family_id", familyID))
+
+	return r.revoke(ctx, span, "family_id", familyID, revokedAt)
+}
+
+// RevokeByUserID ユーザーの未失効のリフレッシュトークンをすべて失効させ、失効させた件数を返す
+func (r *RefreshTokenRepository) RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "RefreshTokenRepository.RevokeByUserID")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("db.user_id", userID))
+
+	return r.revoke(ctx, span, "user_id", userID, revokedAt)
+}
+
+// revoke 指定した列の値が一致する未失効のリフレッシュトークンを失効させる
+// columnは呼び出し元で固定した列名のみを渡す
+func (r *RefreshTokenRepository) revoke(ctx context.Context, span trace.Span, column, value string, revokedAt time.Time) (int, error) {
+	span.SetAttributes(
+		attribute.String("db.operation", "UPDATE"),
+		attribute.String("db.table", "refresh_tokens"),
+	)
+
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = ?
+		WHERE ` + column + ` = ? AND revoked_at IS NULL
+	`
+
+	result, err := r.db.executor(ctx).ExecContext(ctx, query, revokedAt, value)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return 0, fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	span.SetAttributes(attribute.Int64("db.rows_affected", rowsAffected))
+	span.SetStatus(otelcodes.Ok, "refresh tokens revoked")
+	return int(rowsAffected), nil
+}
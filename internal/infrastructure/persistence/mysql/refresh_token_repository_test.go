@@ -0,0 +1,235 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+
+	"gem-server/internal/domain/auth_token"
+)
+
+func newTestRefreshTokenRepository(t *testing.T) (*RefreshTokenRepository, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	repo := &RefreshTokenRepository{
+		db:     &DB{DB: db},
+		tracer: otel.Tracer("test"),
+	}
+	return repo, mock, func() { db.Close() }
+}
+
+var refreshTokenColumns = []string{"token_id", "user_id", "family_id", "token_hash", "expires_at", "revoked_at", "replaced_by", "created_at"}
+
+func TestRefreshTokenRepository_Save(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := auth_token.NewRefreshToken("rt_2", "user123", "rt_1", "hash", expiresAt)
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantError bool
+	}{
+		{
+			name: "正常系: リフレッシュトークンを保存",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO refresh_tokens`).
+					WithArgs("rt_2", "user123", "rt_1", "hash", expiresAt, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name: "異常系: データベースエラー",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`INSERT INTO refresh_tokens`).
+					WillReturnError(errors.New("database error"))
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestRefreshTokenRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			err := repo.Save(context.Background(), token)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_FindByHash(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	revokedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+		checkFunc func(*testing.T, *auth_token.RefreshToken)
+	}{
+		{
+			name: "正常系: 未失効のトークン",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens WHERE token_hash = \? FOR UPDATE`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(refreshTokenColumns).
+						AddRow("rt_1", "user123", "rt_1", "hash", expiresAt, nil, nil, createdAt))
+			},
+			checkFunc: func(t *testing.T, token *auth_token.RefreshToken) {
+				assert.Equal(t, "rt_1", token.TokenID())
+				assert.Equal(t, "user123", token.UserID())
+				assert.Equal(t, expiresAt, token.ExpiresAt())
+				assert.Equal(t, createdAt, token.CreatedAt())
+				assert.False(t, token.IsRevoked())
+			},
+		},
+		{
+			name: "正常系: ローテーション済みのトークン",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(refreshTokenColumns).
+						AddRow("rt_1", "user123", "rt_1", "hash", expiresAt, revokedAt, "rt_2", createdAt))
+			},
+			checkFunc: func(t *testing.T, token *auth_token.RefreshToken) {
+				assert.True(t, token.IsRevoked())
+				assert.True(t, token.IsRotated())
+				assert.Equal(t, "rt_2", token.ReplacedBy())
+				assert.Equal(t, revokedAt, *token.RevokedAt())
+			},
+		},
+		{
+			name: "異常系: トークンが見つからない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT (.+) FROM refresh_tokens`).
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(refreshTokenColumns))
+			},
+			wantErr: auth_token.ErrRefreshTokenNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestRefreshTokenRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			token, err := repo.FindByHash(context.Background(), "hash")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				tt.checkFunc(t, token)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_Update(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	token := auth_token.NewRefreshToken("rt_1", "user123", "", "hash", now.Add(time.Hour))
+	require.NoError(t, token.Rotate("rt_2", now))
+
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "正常系: ローテーションを記録",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \?, replaced_by = \? WHERE token_id = \?`).
+					WithArgs(now, "rt_2", "rt_1").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "異常系: トークンが見つからない",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE refresh_tokens`).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: auth_token.ErrRefreshTokenNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, mock, cleanup := newTestRefreshTokenRepository(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			err := repo.Update(context.Background(), token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("正常系: 系列のトークンを失効", func(t *testing.T) {
+		repo, mock, cleanup := newTestRefreshTokenRepository(t)
+		defer cleanup()
+
+		mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \? WHERE family_id = \? AND revoked_at IS NULL`).
+			WithArgs(now, "rt_1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		n, err := repo.RevokeFamily(context.Background(), "rt_1", now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: ユーザーのトークンを失効", func(t *testing.T) {
+		repo, mock, cleanup := newTestRefreshTokenRepository(t)
+		defer cleanup()
+
+		mock.ExpectExec(`UPDATE refresh_tokens SET revoked_at = \? WHERE user_id = \? AND revoked_at IS NULL`).
+			WithArgs(now, "user123").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		n, err := repo.RevokeByUserID(context.Background(), "user123", now)
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("異常系: データベースエラー", func(t *testing.T) {
+		repo, mock, cleanup := newTestRefreshTokenRepository(t)
+		defer cleanup()
+
+		mock.ExpectExec(`UPDATE refresh_tokens`).
+			WillReturnError(errors.New("database error"))
+
+		_, err := repo.RevokeByUserID(context.Background(), "user123", now)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,177 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	currencyapp "gem-server/internal/application/currency"
+	"gem-server/internal/domain/idgen"
+	"gem-server/internal/domain/service"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+)
+
+// newCurrencyAppServiceWithMockDB sqlmockを使った実リポジトリでCurrencyApplicationServiceを作成
+func newCurrencyAppServiceWithMockDB(t *testing.T) (*currencyapp.CurrencyApplicationService, sqlmock.Sqlmock, func()) {
+	return newAppServiceWithMockDB(t, func(db *DB, logger *otelinfra.Logger, metrics *otelinfra.Metrics) *currencyapp.CurrencyApplicationService {
+		currencyRepo := NewCurrencyRepository(db)
+		return currencyapp.NewCurrencyApplicationService(
+			currencyRepo,
+			NewTransactionRepository(db),
+			NewLotRepository(db),
+			NewTransactionManager(db),
+			idgen.NewUUIDv7Generator(),
+			nil,
+			service.NewCurrencyService(currencyRepo),
+			nil,
+			logger,
+			metrics,
+		)
+	})
+}
+
+func TestCurrencyApplicationService_Grant_Atomicity(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func(sqlmock.Sqlmock)
+		wantError bool
+	}{
+		{
+			name: "正常系: 残高更新と履歴記録が同一トランザクションでコミットされる",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT user_id, currency_type, balance, version\s+FROM currency_balances\s+WHERE user_id = \? AND currency_type = \?\s+FOR UPDATE`).
+					WithArgs("user123", "paid").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "currency_type", "balance", "version"}).
+						AddRow("user123", "paid", 500, 1))
+				mock.ExpectExec(`UPDATE currency_balances`).
+					WithArgs(int64(1500), 2, "user123", "paid", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO transactions`).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+			wantError: false,
+		},
+		{
+			name: "異常系: 履歴保存に失敗した場合は残高更新もロールバックされる",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`SELECT user_id, currency_type, balance, version\s+FROM currency_balances\s+WHERE user_id = \? AND currency_type = \?\s+FOR UPDATE`).
+					WithArgs("user123", "paid").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "currency_type", "balance", "version"}).
+						AddRow("user123", "paid", 500, 1))
+				mock.ExpectExec(`UPDATE currency_balances`).
+					WithArgs(int64(1500), 2, "user123", "paid", 1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`INSERT INTO transactions`).
+					WillReturnError(errors.New("insert failed"))
+				mock.ExpectRollback()
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newCurrencyAppServiceWithMockDB(t)
+			defer cleanup()
+
+			tt.setupMock(mock)
+
+			resp, err := svc.Grant(context.Background(), &currencyapp.GrantRequest{
+				UserID:       "user123",
+				CurrencyType: "paid",
+				Amount:       1000,
+			})
+
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(1500), resp.BalanceAfter)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCurrencyApplicationService_Grant_VersionConflict(t *testing.T) {
+	svc, mock, cleanup := newCurrencyAppServiceWithMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	// 1回目: 読み込み後に他の更新がコミットされ、バージョンが一致しない
+	mock.ExpectQuery(`SELECT user_id, currency_type, balance, version\s+FROM currency_balances\s+WHERE user_id = \? AND currency_type = \?\s+FOR UPDATE`).
+		WithArgs("user123", "paid").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "currency_type", "balance", "version"}).
+			AddRow("user123", "paid", 500, 1))
+	mock.ExpectExec(`UPDATE currency_balances`).
+		WithArgs(int64(1500), 2, "user123", "paid", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// 2回目: ロックを取得して最新の残高とバージョンを読み直し、更新に成功する
+	mock.ExpectQuery(`SELECT user_id, currency_type, balance, version\s+FROM currency_balances\s+WHERE user_id = \? AND currency_type = \?\s+FOR UPDATE`).
+		WithArgs("user123", "paid").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "currency_type", "balance", "version"}).
+			AddRow("user123", "paid", 700, 2))
+	mock.ExpectExec(`UPDATE currency_balances`).
+		WithArgs(int64(1700), 3, "user123", "paid", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO transactions`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	resp, err := svc.Grant(context.Background(), &currencyapp.GrantRequest{
+		UserID:       "user123",
+		CurrencyType: "paid",
+		Amount:       1000,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1700), resp.BalanceAfter)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCurrencyApplicationService_Consume_Atomicity(t *testing.T) {
+	svc, mock, cleanup := newCurrencyAppServiceWithMockDB(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT user_id, currency_type, balance, version\s+FROM currency_balances\s+WHERE user_id = \? AND currency_type = \?\s+FOR UPDATE`).
+		WithArgs("user123", "free").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "currency_type", "balance", "version"}).
+			AddRow("user123", "free", 500, 1))
+	mock.ExpectQuery(`SELECT lot_id, user_id, currency_type, amount, remaining, expires_at`).
+		WithArgs("user123", "free").
+		WillReturnRows(sqlmock.NewRows([]string{"lot_id", "user_id", "currency_type", "amount", "remaining", "expires_at"}).
+			AddRow("txn_1", "user123", "free", 100, 100, time.Now().Add(24*time.Hour)))
+	mock.ExpectExec(`UPDATE currency_balances`).
+		WithArgs(int64(200), 2, "user123", "free", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE currency_lots`).
+		WithArgs(int64(0), "txn_1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO currency_lot_consumptions`).
+		WithArgs(sqlmock.AnyArg(), "txn_1", int64(100)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO transactions`).
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	resp, err := svc.Consume(context.Background(), &currencyapp.ConsumeRequest{
+		UserID:       "user123",
+		CurrencyType: "free",
+		Amount:       300,
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
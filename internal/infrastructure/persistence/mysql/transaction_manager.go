@@ -5,6 +5,17 @@ import (
 	"database/sql"
 )
 
+// txContextKey コンテキストにトランザクションを格納するためのキー
+type txContextKey struct{}
+
+// afterCommitContextKey コンテキストにコミット後に実行する関数を格納するためのキー
+type afterCommitContextKey struct{}
+
+// afterCommitHooks トランザクションのコミット後に実行する関数
+type afterCommitHooks struct {
+	fns []func(ctx context.Context)
+}
+
 // TransactionManager トランザクション管理を提供
 type TransactionManager struct {
 	db *DB
@@ -16,23 +27,63 @@ func NewTransactionManager(db *DB) *TransactionManager {
 }
 
 // WithTransaction トランザクション内で関数を実行
-func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+// トランザクションはfnに渡すctxに格納され、各リポジトリはそれを使ってクエリを実行する。
+// ctxに既にトランザクションが存在する場合は、そのトランザクションに参加する。
+func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
 	tx, err := tm.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
+	hooks := &afterCommitHooks{}
 	defer func() {
 		if p := recover(); p != nil {
 			_ = tx.Rollback()
 			panic(p)
 		} else if err != nil {
 			_ = tx.Rollback()
-		} else {
-			err = tx.Commit()
+		} else if err = tx.Commit(); err == nil {
+			// コミット後の処理はトランザクションを含まないctxで実行する
+			for _, hook := range hooks.fns {
+				hook(ctx)
+			}
 		}
 	}()
 
-	err = fn(tx)
+	err = fn(context.WithValue(withTx(ctx, tx), afterCommitContextKey{}, hooks))
 	return err
 }
+
+// AfterCommit ctxのトランザクションがコミットされた後にfnを実行する
+// ロールバックされた場合は実行しない。トランザクション外で呼び出した場合はその場で実行する。
+// キャッシュの無効化など、コミットされた書き込みにのみ反映すべき処理に使用する
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	hooks, ok := ctx.Value(afterCommitContextKey{}).(*afterCommitHooks)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// withTx トランザクションを格納したコンテキストを返す
+func withTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// txFromContext コンテキストからトランザクションを取得
+func txFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
+
+// InTransaction ctxにトランザクションが格納されているかを返す
+// キャッシュなど、コミット前の状態を扱ってはいけない処理の判定に使用する
+func InTransaction(ctx context.Context) bool {
+	_, ok := txFromContext(ctx)
+	return ok
+}
@@ -2,7 +2,6 @@ package mysql
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 
@@ -20,13 +19,13 @@ func TestTransactionManager_WithTransaction(t *testing.T) {
 
 	tests := []struct {
 		name      string
-		fn        func(*sql.Tx) error
+		fn        func(context.Context) error
 		setupMock func()
 		wantError bool
 	}{
 		{
 			name: "正常系: トランザクション成功",
-			fn: func(tx *sql.Tx) error {
+			fn: func(ctx context.Context) error {
 				return nil
 			},
 			setupMock: func() {
@@ -37,7 +36,7 @@ func TestTransactionManager_WithTransaction(t *testing.T) {
 		},
 		{
 			name: "正常系: トランザクションロールバック（エラー発生）",
-			fn: func(tx *sql.Tx) error {
+			fn: func(ctx context.Context) error {
 				return errors.New("test error")
 			},
 			setupMock: func() {
@@ -48,7 +47,7 @@ func TestTransactionManager_WithTransaction(t *testing.T) {
 		},
 		{
 			name: "異常系: Beginエラー",
-			fn: func(tx *sql.Tx) error {
+			fn: func(ctx context.Context) error {
 				return nil
 			},
 			setupMock: func() {
@@ -58,7 +57,7 @@ func TestTransactionManager_WithTransaction(t *testing.T) {
 		},
 		{
 			name: "正常系: パニック発生時もロールバック",
-			fn: func(tx *sql.Tx) error {
+			fn: func(ctx context.Context) error {
 				panic("test panic")
 			},
 			setupMock: func() {
@@ -96,3 +95,140 @@ func TestTransactionManager_WithTransaction(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionManager_WithTransaction_PropagatesTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	tm := &TransactionManager{db: &DB{DB: db}}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	assert.False(t, InTransaction(context.Background()))
+
+	err = tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		tx, ok := txFromContext(ctx)
+		assert.True(t, ok)
+		assert.True(t, InTransaction(ctx))
+		assert.Same(t, tx, tm.db.executor(ctx))
+
+		// ネストした呼び出しは外側のトランザクションに参加する（Beginは1回のみ）
+		return tm.WithTransaction(ctx, func(inner context.Context) error {
+			innerTx, ok := txFromContext(inner)
+			assert.True(t, ok)
+			assert.Same(t, tx, innerTx)
+			return nil
+		})
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_WithTransaction_CommitError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	tm := &TransactionManager{db: &DB{DB: db}}
+
+	mock.ExpectBegin()
+	mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+
+	err = tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAfterCommit(t *testing.T) {
+	t.Run("正常系: コミット後にトランザクション外のctxで実行する", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		tm := &TransactionManager{db: &DB{DB: db}}
+		mock.ExpectBegin()
+		mock.ExpectCommit()
+
+		var calls []bool
+		err = tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(ctx context.Context) {
+				calls = append(calls, InTransaction(ctx))
+			})
+			// ネストした呼び出しで登録したものも外側のコミット後に実行する
+			return tm.WithTransaction(ctx, func(inner context.Context) error {
+				AfterCommit(inner, func(ctx context.Context) {
+					calls = append(calls, InTransaction(ctx))
+				})
+				assert.Empty(t, calls)
+				return nil
+			})
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []bool{false, false}, calls)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: ロールバックした場合は実行しない", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		tm := &TransactionManager{db: &DB{DB: db}}
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		called := false
+		err = tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(ctx context.Context) { called = true })
+			return errors.New("fn error")
+		})
+
+		assert.Error(t, err)
+		assert.False(t, called)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: コミットに失敗した場合は実行しない", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		defer db.Close()
+
+		tm := &TransactionManager{db: &DB{DB: db}}
+		mock.ExpectBegin()
+		mock.ExpectCommit().WillReturnError(errors.New("commit error"))
+
+		called := false
+		err = tm.WithTransaction(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func(ctx context.Context) { called = true })
+			return nil
+		})
+
+		assert.Error(t, err)
+		assert.False(t, called)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("正常系: トランザクション外ではその場で実行する", func(t *testing.T) {
+		called := false
+		AfterCommit(context.Background(), func(ctx context.Context) { called = true })
+		assert.True(t, called)
+	})
+}
+
+func TestDB_Executor(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	d := &DB{DB: db}
+
+	// トランザクションがない場合は接続プールを使用
+	assert.Same(t, db, d.executor(context.Background()))
+}
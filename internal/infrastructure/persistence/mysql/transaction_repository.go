@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -16,6 +17,18 @@ import (
 	"gem-server/internal/domain/transaction"
 )
 
+// transactionColumns transactionsテーブルから取得するカラム（scanTransactionと順序を合わせる）
+const transactionColumns = `
+			transaction_id, user_id, transaction_type, currency_type,
+			amount, balance_before, balance_after, status,
+			payment_request_id, requester, idempotency_key, request_hash,
+			metadata, created_at, updated_at`
+
+// rowScanner *sql.Rowと*sql.Rowsの共通インターフェース
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 // TransactionRepository MySQL実装のTransactionRepository
 type TransactionRepository struct {
 	db     *DB
@@ -50,11 +63,9 @@ func (r *TransactionRepository) Save(ctx context.Context, t *transaction.Transac
 		INSERT INTO transactions (
 			transaction_id, user_id, transaction_type, currency_type,
 			amount, balance_before, balance_after, status,
-			payment_request_id, requester, metadata, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-		ON DUPLICATE KEY UPDATE
-			status = VALUES(status),
-			updated_at = VALUES(updated_at)
+			payment_request_id, requester, idempotency_key, request_hash,
+			metadata, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var metadataJSON []byte
@@ -68,19 +79,7 @@ func (r *TransactionRepository) Save(ctx context.Context, t *transaction.Transac
 		}
 	}
 
-	paymentRequestID := t.PaymentRequestID()
-	var paymentRequestIDValue interface{}
-	if paymentRequestID != nil {
-		paymentRequestIDValue = *paymentRequestID
-	}
-
-	requester := t.Requester()
-	var requesterValue interface{}
-	if requester != nil {
-		requesterValue = *requester
-	}
-
-	_, err = r.db.ExecContext(ctx, query,
+	_, err = r.db.executor(ctx).ExecContext(ctx, query,
 		t.TransactionID(),
 		t.UserID(),
 		t.TransactionType().String(),
@@ -89,8 +88,10 @@ func (r *TransactionRepository) Save(ctx context.Context, t *transaction.Transac
 		t.BalanceBefore(),
 		t.BalanceAfter(),
 		t.Status().String(),
-		paymentRequestIDValue,
-		requesterValue,
+		nullableString(t.PaymentRequestID()),
+		nullableString(t.Requester()),
+		nullableString(t.IdempotencyKey()),
+		nullableString(t.RequestHash()),
 		string(metadataJSON),
 		t.CreatedAt(),
 		t.UpdatedAt(),
@@ -99,6 +100,10 @@ func (r *TransactionRepository) Save(ctx context.Context, t *transaction.Transac
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
+		// 記録済みのトランザクションは上書きしない（返金・失効などのIDは元のIDから一意に決まる）
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", transaction.ErrDuplicateTransactionID, t.TransactionID())
+		}
 		return fmt.Errorf("failed to save transaction: %w", err)
 	}
 
@@ -118,38 +123,12 @@ func (r *TransactionRepository) FindByTransactionID(ctx context.Context, transac
 	)
 
 	query := `
-		SELECT 
-			transaction_id, user_id, transaction_type, currency_type,
-			amount, balance_before, balance_after, status,
-			payment_request_id, requester, metadata, created_at, updated_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE transaction_id = ?
 	`
 
-	var dbTransactionID, dbUserID, dbTransactionType, dbCurrencyType string
-	var amount, balanceBefore, balanceAfter int64
-	var dbStatus string
-	var paymentRequestID sql.NullString
-	var requester sql.NullString
-	var metadataJSON sql.NullString
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRowContext(ctx, query, transactionID).Scan(
-		&dbTransactionID,
-		&dbUserID,
-		&dbTransactionType,
-		&dbCurrencyType,
-		&amount,
-		&balanceBefore,
-		&balanceAfter,
-		&dbStatus,
-		&paymentRequestID,
-		&requester,
-		&metadataJSON,
-		&createdAt,
-		&updatedAt,
-	)
-
+	t, err := scanTransaction(r.db.executor(ctx).QueryRowContext(ctx, query, transactionID))
 	if err == sql.ErrNoRows {
 		span.SetStatus(otelcodes.Ok, "transaction not found")
 		return nil, transaction.ErrTransactionNotFound
@@ -161,181 +140,207 @@ func (r *TransactionRepository) FindByTransactionID(ctx context.Context, transac
 	}
 
 	span.SetAttributes(
-		attribute.String("db.user_id", dbUserID),
-		attribute.String("db.transaction_type", dbTransactionType),
-		attribute.Int64("db.amount", amount),
+		attribute.String("db.user_id", t.UserID()),
+		attribute.String("db.transaction_type", t.TransactionType().String()),
+		attribute.Int64("db.amount", t.Amount()),
 	)
 	span.SetStatus(otelcodes.Ok, "transaction found")
 
-	tt, err := transaction.NewTransactionType(dbTransactionType)
-	if err != nil {
-		return nil, fmt.Errorf("invalid transaction type: %w", err)
-	}
+	return t, nil
+}
 
-	ct, err := currency.NewCurrencyType(dbCurrencyType)
-	if err != nil {
-		return nil, fmt.Errorf("invalid currency type: %w", err)
-	}
+// FindByFilter 検索条件に一致するトランザクション一覧を新しい順に取得（ページネーション対応）
+func (r *TransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	ctx, span := r.tracer.Start(ctx, "TransactionRepository.FindByFilter")
+	defer span.End()
 
-	ts, err := transaction.NewTransactionStatus(dbStatus)
+	span.SetAttributes(
+		attribute.String("db.user_id", filter.UserID),
+		attribute.Int("db.limit", limit),
+		attribute.Int("db.offset", offset),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "transactions"),
+	)
+
+	where, args := buildTransactionFilter(filter)
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE ` + where + `
+		ORDER BY created_at DESC, transaction_id DESC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, limit, offset)
+
+	transactions, err := r.queryTransactions(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("invalid transaction status: %w", err)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
 	}
 
-	var metadata map[string]interface{}
-	if metadataJSON.Valid && metadataJSON.String != "" {
-		if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-		}
-	}
+	span.SetAttributes(attribute.Int("db.result_count", len(transactions)))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d transactions", len(transactions)))
+	return transactions, nil
+}
 
-	var requesterPtr *string
-	if requester.Valid {
-		requesterPtr = &requester.String
-	}
+// FindByFilterAfterCursor 検索条件に一致し、カーソルより古いトランザクション一覧を新しい順に取得
+func (r *TransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	ctx, span := r.tracer.Start(ctx, "TransactionRepository.FindByFilterAfterCursor")
+	defer span.End()
 
-	t, err := transaction.NewTransactionWithRequester(
-		dbTransactionID,
-		dbUserID,
-		tt,
-		ct,
-		amount,
-		balanceBefore,
-		balanceAfter,
-		ts,
-		requesterPtr,
-		metadata,
+	span.SetAttributes(
+		attribute.String("db.user_id", filter.UserID),
+		attribute.String("db.cursor_transaction_id", cursor.TransactionID),
+		attribute.Int("db.limit", limit),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "transactions"),
 	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to reconstruct transaction entity: %w", err)
-	}
 
-	if paymentRequestID.Valid {
-		t.SetPaymentRequestID(paymentRequestID.String)
+	// (created_at, id) の降順でカーソルより後ろの行だけを取得する
+	where, args := buildTransactionFilter(filter)
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE ` + where + `
+		AND (created_at < ? OR (created_at = ? AND transaction_id < ?))
+		ORDER BY created_at DESC, transaction_id DESC
+		LIMIT ?
+	`
+	args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.TransactionID, limit)
+
+	transactions, err := r.queryTransactions(ctx, query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
 	}
 
-	return t, nil
+	span.SetAttributes(attribute.Int("db.result_count", len(transactions)))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d transactions", len(transactions)))
+	return transactions, nil
 }
 
-// FindByUserID ユーザーIDでトランザクション一覧を取得（ページネーション対応）
-func (r *TransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	ctx, span := r.tracer.Start(ctx, "TransactionRepository.FindByUserID")
+// StreamByFilter 検索条件に一致するトランザクションを古い順に1件ずつfnに渡す
+func (r *TransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	ctx, span := r.tracer.Start(ctx, "TransactionRepository.StreamByFilter")
 	defer span.End()
 
 	span.SetAttributes(
-		attribute.String("db.user_id", userID),
-		attribute.Int("db.limit", limit),
-		attribute.Int("db.offset", offset),
 		attribute.String("db.operation", "SELECT"),
 		attribute.String("db.table", "transactions"),
 	)
 
+	where, args := buildTransactionFilter(filter)
 	query := `
-		SELECT 
-			transaction_id, user_id, transaction_type, currency_type,
-			amount, balance_before, balance_after, status,
-			payment_request_id, requester, metadata, created_at, updated_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
-		WHERE user_id = ?
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?
+		WHERE ` + where + `
+		ORDER BY created_at ASC, transaction_id ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, fmt.Errorf("failed to query transactions: %w", err)
+		return fmt.Errorf("failed to query transactions: %w", err)
 	}
 	defer rows.Close()
 
-	var transactions []*transaction.Transaction
+	count := 0
 	for rows.Next() {
-		var dbTransactionID, dbUserID, dbTransactionType, dbCurrencyType string
-		var amount, balanceBefore, balanceAfter int64
-		var dbStatus string
-		var paymentRequestID sql.NullString
-		var requester sql.NullString
-		var metadataJSON sql.NullString
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(
-			&dbTransactionID,
-			&dbUserID,
-			&dbTransactionType,
-			&dbCurrencyType,
-			&amount,
-			&balanceBefore,
-			&balanceAfter,
-			&dbStatus,
-			&paymentRequestID,
-			&requester,
-			&metadataJSON,
-			&createdAt,
-			&updatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("failed to scan transaction: %w", err)
-		}
-
-		tt, err := transaction.NewTransactionType(dbTransactionType)
-		if err != nil {
-			return nil, fmt.Errorf("invalid transaction type: %w", err)
-		}
-
-		ct, err := currency.NewCurrencyType(dbCurrencyType)
+		t, err := scanTransaction(rows)
 		if err != nil {
-			return nil, fmt.Errorf("invalid currency type: %w", err)
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return err
 		}
-
-		ts, err := transaction.NewTransactionStatus(dbStatus)
-		if err != nil {
-			return nil, fmt.Errorf("invalid transaction status: %w", err)
+		if err := fn(t); err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, err.Error())
+			return err
 		}
+		count++
+	}
 
-		var metadata map[string]interface{}
-		if metadataJSON.Valid && metadataJSON.String != "" {
-			if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
-			}
-		}
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to iterate transactions: %w", err)
+	}
 
-		var requesterPtr *string
-		if requester.Valid {
-			requesterPtr = &requester.String
-		}
+	span.SetAttributes(attribute.Int("db.result_count", count))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("streamed %d transactions", count))
+	return nil
+}
 
-		t, err := transaction.NewTransactionWithRequester(
-			dbTransactionID,
-			dbUserID,
-			tt,
-			ct,
-			amount,
-			balanceBefore,
-			balanceAfter,
-			ts,
-			requesterPtr,
-			metadata,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to reconstruct transaction entity: %w", err)
-		}
+// CountByFilter 検索条件に一致するトランザクションの総件数を取得
+func (r *TransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "TransactionRepository.CountByFilter")
+	defer span.End()
 
-		if paymentRequestID.Valid {
-			t.SetPaymentRequestID(paymentRequestID.String)
-		}
+	span.SetAttributes(
+		attribute.String("db.user_id", filter.UserID),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "transactions"),
+	)
 
-		transactions = append(transactions, t)
-	}
+	where, args := buildTransactionFilter(filter)
+	query := `
+		SELECT COUNT(*)
+		FROM transactions
+		WHERE ` + where
 
-	if err := rows.Err(); err != nil {
+	var total int
+	if err := r.db.executor(ctx).QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+		return 0, fmt.Errorf("failed to count transactions: %w", err)
 	}
 
-	span.SetAttributes(attribute.Int("db.result_count", len(transactions)))
-	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d transactions", len(transactions)))
-	return transactions, nil
+	span.SetAttributes(attribute.Int("db.result_count", total))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("counted %d transactions", total))
+	return total, nil
+}
+
+// buildTransactionFilter 検索条件からWHERE句とバインド引数を組み立てる
+func buildTransactionFilter(filter transaction.Filter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.CurrencyType != "" {
+		conditions = append(conditions, "currency_type = ?")
+		args = append(args, filter.CurrencyType.String())
+	}
+	if filter.TransactionType != "" {
+		conditions = append(conditions, "transaction_type = ?")
+		args = append(args, filter.TransactionType.String())
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status.String())
+	}
+	if filter.Requester != "" {
+		conditions = append(conditions, "requester = ?")
+		args = append(args, filter.Requester)
+	}
+	if filter.From != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+
+	if len(conditions) == 0 {
+		return "1 = 1", args
+	}
+	return strings.Join(conditions, " AND "), args
 }
 
 // FindByPaymentRequestID PaymentRequest IDでトランザクションを取得
@@ -350,25 +355,135 @@ func (r *TransactionRepository) FindByPaymentRequestID(ctx context.Context, paym
 	)
 
 	query := `
-		SELECT 
-			transaction_id, user_id, transaction_type, currency_type,
-			amount, balance_before, balance_after, status,
-			payment_request_id, requester, metadata, created_at, updated_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
 		WHERE payment_request_id = ?
 		ORDER BY created_at DESC
 		LIMIT 1
 	`
 
+	t, err := scanTransaction(r.db.executor(ctx).QueryRowContext(ctx, query, paymentRequestID))
+	if err == sql.ErrNoRows {
+		span.SetStatus(otelcodes.Ok, "transaction not found")
+		return nil, transaction.ErrTransactionNotFound
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	}
+
+	span.SetAttributes(
+		attribute.String("db.transaction_id", t.TransactionID()),
+		attribute.String("db.user_id", t.UserID()),
+		attribute.String("db.transaction_type", t.TransactionType().String()),
+	)
+	span.SetStatus(otelcodes.Ok, "transaction found")
+
+	return t, nil
+}
+
+// FindByIdempotencyKey ユーザーIDと冪等性キーでトランザクション一覧を取得（作成順）
+func (r *TransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	ctx, span := r.tracer.Start(ctx, "TransactionRepository.FindByIdempotencyKey")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.user_id", userID),
+		attribute.String("db.idempotency_key", idempotencyKey),
+		attribute.String("db.operation", "SELECT"),
+		attribute.String("db.table", "transactions"),
+	)
+
+	query := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE user_id = ? AND idempotency_key = ?
+		ORDER BY id ASC
+	`
+
+	transactions, err := r.queryTransactions(ctx, query, userID, idempotencyKey)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("db.result_count", len(transactions)))
+	span.SetStatus(otelcodes.Ok, fmt.Sprintf("found %d transactions", len(transactions)))
+	return transactions, nil
+}
+
+// ReserveIdempotencyKey ユーザーIDと冪等性キーを記録（記録済みの場合はErrDuplicateIdempotencyKey）
+// 一意制約により、同じキーで同時に実行されたトランザクションは先行するものの完了を待ってから失敗する
+func (r *TransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	ctx, span := r.tracer.Start(ctx, "TransactionRepository.ReserveIdempotencyKey")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.user_id", userID),
+		attribute.String("db.idempotency_key", idempotencyKey),
+		attribute.String("db.operation", "INSERT"),
+		attribute.String("db.table", "idempotency_keys"),
+	)
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash)
+		VALUES (?, ?, ?)
+	`
+
+	if _, err := r.db.executor(ctx).ExecContext(ctx, query, userID, idempotencyKey, requestHash); err != nil {
+		if isDuplicateKeyError(err) {
+			span.SetStatus(otelcodes.Error, "idempotency key already reserved")
+			return fmt.Errorf("%w: %s", transaction.ErrDuplicateIdempotencyKey, idempotencyKey)
+		}
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, err.Error())
+		return fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	span.SetStatus(otelcodes.Ok, "idempotency key reserved")
+	return nil
+}
+
+// queryTransactions トランザクション一覧を取得するクエリを実行
+func (r *TransactionRepository) queryTransactions(ctx context.Context, query string, args ...interface{}) ([]*transaction.Transaction, error) {
+	rows, err := r.db.executor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var transactions []*transaction.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// scanTransaction transactionColumnsの順で1行を読み取りエンティティを再構築
+// 行が存在しない場合はsql.ErrNoRowsをそのまま返す
+func scanTransaction(row rowScanner) (*transaction.Transaction, error) {
 	var dbTransactionID, dbUserID, dbTransactionType, dbCurrencyType string
 	var amount, balanceBefore, balanceAfter int64
 	var dbStatus string
-	var paymentRequestIDValue sql.NullString
+	var paymentRequestID sql.NullString
 	var requester sql.NullString
+	var idempotencyKey sql.NullString
+	var requestHash sql.NullString
 	var metadataJSON sql.NullString
 	var createdAt, updatedAt time.Time
 
-	err := r.db.QueryRowContext(ctx, query, paymentRequestID).Scan(
+	if err := row.Scan(
 		&dbTransactionID,
 		&dbUserID,
 		&dbTransactionType,
@@ -377,66 +492,43 @@ func (r *TransactionRepository) FindByPaymentRequestID(ctx context.Context, paym
 		&balanceBefore,
 		&balanceAfter,
 		&dbStatus,
-		&paymentRequestIDValue,
+		&paymentRequestID,
 		&requester,
+		&idempotencyKey,
+		&requestHash,
 		&metadataJSON,
 		&createdAt,
 		&updatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		span.SetStatus(otelcodes.Ok, "transaction not found")
-		return nil, transaction.ErrTransactionNotFound
-	}
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, err.Error())
-		return nil, fmt.Errorf("failed to find transaction: %w", err)
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan transaction: %w", err)
 	}
 
-	span.SetAttributes(
-		attribute.String("db.transaction_id", dbTransactionID),
-		attribute.String("db.user_id", dbUserID),
-		attribute.String("db.transaction_type", dbTransactionType),
-	)
-	span.SetStatus(otelcodes.Ok, "transaction found")
-
 	tt, err := transaction.NewTransactionType(dbTransactionType)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, fmt.Errorf("invalid transaction type: %w", err)
 	}
 
 	ct, err := currency.NewCurrencyType(dbCurrencyType)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, fmt.Errorf("invalid currency type: %w", err)
 	}
 
 	ts, err := transaction.NewTransactionStatus(dbStatus)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(otelcodes.Error, err.Error())
 		return nil, fmt.Errorf("invalid transaction status: %w", err)
 	}
 
 	var metadata map[string]interface{}
 	if metadataJSON.Valid && metadataJSON.String != "" {
 		if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err != nil {
-			span.RecordError(err)
-			span.SetStatus(otelcodes.Error, err.Error())
 			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
 		}
 	}
 
-	var requesterPtr *string
-	if requester.Valid {
-		requesterPtr = &requester.String
-	}
-
-	t, err := transaction.NewTransactionWithRequester(
+	t, err := transaction.RestoreTransaction(
 		dbTransactionID,
 		dbUserID,
 		tt,
@@ -445,16 +537,33 @@ func (r *TransactionRepository) FindByPaymentRequestID(ctx context.Context, paym
 		balanceBefore,
 		balanceAfter,
 		ts,
-		requesterPtr,
+		nullStringPtr(paymentRequestID),
+		nullStringPtr(requester),
+		nullStringPtr(idempotencyKey),
+		nullStringPtr(requestHash),
 		metadata,
+		createdAt,
+		updatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to reconstruct transaction entity: %w", err)
 	}
 
-	if paymentRequestIDValue.Valid {
-		t.SetPaymentRequestID(paymentRequestIDValue.String)
+	return t, nil
+}
+
+// nullableString *stringをNULL許容のカラム値に変換
+func nullableString(s *string) interface{} {
+	if s == nil {
+		return nil
 	}
+	return *s
+}
 
-	return t, nil
+// nullStringPtr NULL許容のカラム値を*stringに変換
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
 }
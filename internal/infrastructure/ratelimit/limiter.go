@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"gem-server/internal/infrastructure/config"
+)
+
+// Limit トークンバケットの設定
+type Limit struct {
+	Rate  float64 // 1秒あたりに補充されるトークン数
+	Burst int     // バケットの容量
+}
+
+// refillDuration 空のバケットが満杯になるまでの時間
+func (l Limit) refillDuration() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Result レート制限の判定結果
+type Result struct {
+	Allowed    bool
+	RetryAfter time.Duration // 拒否された場合、次のトークンが補充されるまでの時間
+}
+
+// Limiter キーごとにリクエストを許可するか判定する
+type Limiter interface {
+	Allow(ctx context.Context, key string) (*Result, error)
+}
+
+// Limiters 識別子の種類ごとのLimiter（無効な種類はnil）
+type Limiters struct {
+	User   Limiter // JWTのユーザーIDごと
+	APIKey Limiter // 管理APIのAPIキーごと
+	IP     Limiter // クライアントのIPアドレスごと
+}
+
+// NewLimiters 設定からLimitersを作成
+// clientがnilの場合はインメモリ、指定された場合はRedisでバケットを管理する。
+// レート制限が無効な場合はnilを返す
+func NewLimiters(cfg *config.RateLimitConfig, client *redis.Client) *Limiters {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	newLimiter := func(scope string, rate float64, burst int) Limiter {
+		if rate <= 0 {
+			return nil
+		}
+		limit := Limit{Rate: rate, Burst: burst}
+		if client != nil {
+			return NewRedisLimiter(client, scope, limit)
+		}
+		return NewMemoryLimiter(limit)
+	}
+
+	return &Limiters{
+		User:   newLimiter("user", cfg.UserRate, cfg.UserBurst),
+		APIKey: newLimiter("api_key", cfg.APIKeyRate, cfg.APIKeyBurst),
+		IP:     newLimiter("ip", cfg.IPRate, cfg.IPBurst),
+	}
+}
+
+// HashKey 秘密情報をキーに使う場合にハッシュ化する（平文をメモリやRedisに残さない）
+func HashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:16])
+}
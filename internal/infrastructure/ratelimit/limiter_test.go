@@ -0,0 +1,176 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gem-server/internal/infrastructure/config"
+)
+
+// limiterFactory テスト用のLimiterと時刻を進める関数を作成する
+type limiterFactory func(t *testing.T, limit Limit) (Limiter, func(time.Duration))
+
+func newTestMemoryLimiter(t *testing.T, limit Limit) (Limiter, func(time.Duration)) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewMemoryLimiter(limit)
+	l.now = func() time.Time { return now }
+	return l, func(d time.Duration) { now = now.Add(d) }
+}
+
+func newTestRedisLimiter(t *testing.T, limit Limit) (Limiter, func(time.Duration)) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+	return NewRedisLimiter(client, "test", limit), func(d time.Duration) {
+		now = now.Add(d)
+		mr.SetTime(now)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	factories := map[string]limiterFactory{
+		"memory": newTestMemoryLimiter,
+		"redis":  newTestRedisLimiter,
+	}
+
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			t.Run("正常系: バケット容量まで許可し、以降は拒否", func(t *testing.T) {
+				l, _ := factory(t, Limit{Rate: 1, Burst: 2})
+
+				for i := 0; i < 2; i++ {
+					res, err := l.Allow(ctx, "user123")
+					require.NoError(t, err)
+					assert.True(t, res.Allowed)
+				}
+
+				res, err := l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+				assert.Equal(t, time.Second, res.RetryAfter)
+			})
+
+			t.Run("正常系: 経過時間に応じてトークンが補充される", func(t *testing.T) {
+				l, advance := factory(t, Limit{Rate: 2, Burst: 1})
+
+				res, err := l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				require.True(t, res.Allowed)
+
+				advance(250 * time.Millisecond)
+				res, err = l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+				assert.Equal(t, 250*time.Millisecond, res.RetryAfter)
+
+				advance(250 * time.Millisecond)
+				res, err = l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+			})
+
+			t.Run("正常系: 補充はバケット容量を超えない", func(t *testing.T) {
+				l, advance := factory(t, Limit{Rate: 10, Burst: 2})
+
+				advance(time.Hour)
+				for i := 0; i < 2; i++ {
+					res, err := l.Allow(ctx, "user123")
+					require.NoError(t, err)
+					assert.True(t, res.Allowed)
+				}
+				res, err := l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+			})
+
+			t.Run("正常系: キーごとに独立して制限する", func(t *testing.T) {
+				l, _ := factory(t, Limit{Rate: 1, Burst: 1})
+
+				res, err := l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				res, err = l.Allow(ctx, "user456")
+				require.NoError(t, err)
+				assert.True(t, res.Allowed)
+
+				res, err = l.Allow(ctx, "user123")
+				require.NoError(t, err)
+				assert.False(t, res.Allowed)
+			})
+		})
+	}
+}
+
+func TestMemoryLimiter_Sweep(t *testing.T) {
+	l, advance := newTestMemoryLimiter(t, Limit{Rate: 1, Burst: 5})
+	ml := l.(*MemoryLimiter)
+	ctx := context.Background()
+
+	_, err := l.Allow(ctx, "user123")
+	require.NoError(t, err)
+	assert.Len(t, ml.buckets, 1)
+
+	// 満杯まで補充されたバケットは破棄される
+	advance(sweepInterval)
+	_, err = l.Allow(ctx, "user456")
+	require.NoError(t, err)
+	assert.Len(t, ml.buckets, 1)
+	assert.Contains(t, ml.buckets, "user456")
+}
+
+func TestRedisLimiter_Allow_RedisUnavailable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	mr.Close()
+
+	l := NewRedisLimiter(client, "test", Limit{Rate: 1, Burst: 1})
+	_, err := l.Allow(context.Background(), "user123")
+	assert.Error(t, err)
+}
+
+func TestNewLimiters(t *testing.T) {
+	cfg := &config.RateLimitConfig{
+		Enabled:     true,
+		UserRate:    1,
+		UserBurst:   1,
+		APIKeyRate:  0,
+		APIKeyBurst: 0,
+		IPRate:      1,
+		IPBurst:     1,
+	}
+
+	t.Run("正常系: Redisクライアントがない場合はインメモリ", func(t *testing.T) {
+		limiters := NewLimiters(cfg, nil)
+		require.NotNil(t, limiters)
+		assert.IsType(t, &MemoryLimiter{}, limiters.User)
+		assert.Nil(t, limiters.APIKey)
+		assert.IsType(t, &MemoryLimiter{}, limiters.IP)
+	})
+
+	t.Run("正常系: Redisクライアントがある場合はRedis", func(t *testing.T) {
+		client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+		defer client.Close()
+
+		limiters := NewLimiters(cfg, client)
+		require.NotNil(t, limiters)
+		assert.IsType(t, &RedisLimiter{}, limiters.User)
+		assert.Nil(t, limiters.APIKey)
+	})
+
+	t.Run("正常系: 無効な場合はnil", func(t *testing.T) {
+		assert.Nil(t, NewLimiters(&config.RateLimitConfig{Enabled: false}, nil))
+	})
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 満杯になったバケットを破棄する間隔
+const sweepInterval = time.Minute
+
+// bucket トークンバケットの状態
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// MemoryLimiter プロセス内でバケットを管理するLimiter
+// 複数インスタンスで実行する場合は制限がインスタンスごとになる
+type MemoryLimiter struct {
+	limit     Limit
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter 新しいMemoryLimiterを作成
+func NewMemoryLimiter(limit Limit) *MemoryLimiter {
+	return &MemoryLimiter{
+		limit:   limit,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Allow キーのバケットからトークンを1つ消費できるか判定
+func (l *MemoryLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+
+	// 経過時間分のトークンを補充
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(float64(l.limit.Burst), b.tokens+elapsed*l.limit.Rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return &Result{Allowed: true}, nil
+	}
+
+	retryAfter := time.Duration((1 - b.tokens) / l.limit.Rate * float64(time.Second))
+	return &Result{Allowed: false, RetryAfter: retryAfter}, nil
+}
+
+// sweep 満杯まで補充されたバケットを破棄する（新規作成と同じ状態のため）
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	refill := l.limit.refillDuration()
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript トークンバケットを更新し、許可された場合は1、拒否された場合は0を返す
+// 複数インスタンス間で時刻を揃えるため、Redisサーバーの時刻を使用する。
+// 2つ目の戻り値は拒否された場合に次のトークンが補充されるまでのマイクロ秒
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tokens = burst
+local last = now
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+if state[1] then
+  tokens = tonumber(state[1])
+  last = tonumber(state[2])
+end
+
+local elapsed = now - last
+if elapsed > 0 then
+  tokens = math.min(burst, tokens + elapsed * rate / 1000000)
+end
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry_after = math.ceil((1 - tokens) / rate * 1000000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, retry_after}
+`)
+
+// RedisLimiter Redisでバケットを管理するLimiter
+// 複数インスタンス間で制限を共有する
+type RedisLimiter struct {
+	client *redis.Client
+	scope  string
+	limit  Limit
+}
+
+// NewRedisLimiter 新しいRedisLimiterを作成
+func NewRedisLimiter(client *redis.Client, scope string, limit Limit) *RedisLimiter {
+	return &RedisLimiter{
+		client: client,
+		scope:  scope,
+		limit:  limit,
+	}
+}
+
+// Allow キーのバケットからトークンを1つ消費できるか判定
+func (l *RedisLimiter) Allow(ctx context.Context, key string) (*Result, error) {
+	// 満杯まで補充される時間が経過したバケットは新規作成と同じ状態のため破棄する
+	ttl := int64(math.Ceil(float64(l.limit.refillDuration()) / float64(time.Millisecond)))
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	values, err := tokenBucketScript.Run(ctx, l.client, []string{l.key(key)},
+		l.limit.Rate,
+		l.limit.Burst,
+		ttl,
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+	if len(values) != 2 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+
+	if values[0] == 1 {
+		return &Result{Allowed: true}, nil
+	}
+	return &Result{Allowed: false, RetryAfter: time.Duration(values[1]) * time.Microsecond}, nil
+}
+
+// key バケットのキー
+func (l *RedisLimiter) key(key string) string {
+	return "gem:ratelimit:" + l.scope + ":" + key
+}
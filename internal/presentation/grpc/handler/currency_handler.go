@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"time"
 
 	redemptionapp "gem-server/internal/application/code_redemption"
 	currencyapp "gem-server/internal/application/currency"
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/api_key"
 	"gem-server/internal/domain/currency"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
@@ -63,9 +65,19 @@ func (h *CurrencyHandler) GetBalance(ctx context.Context, req *pb.GetBalanceRequ
 	balances["paid"] = strconv.FormatInt(appResp.Balances["paid"], 10)
 	balances["free"] = strconv.FormatInt(appResp.Balances["free"], 10)
 
+	expirations := make([]*pb.Expiration, len(appResp.Expirations))
+	for i, expiration := range appResp.Expirations {
+		expirations[i] = &pb.Expiration{
+			CurrencyType: expiration.CurrencyType,
+			Amount:       strconv.FormatInt(expiration.Amount, 10),
+			ExpiresAt:    expiration.ExpiresAt.Format(time.RFC3339),
+		}
+	}
+
 	return &pb.GetBalanceResponse{
-		UserId:   appResp.UserID,
-		Balances: balances,
+		UserId:      appResp.UserID,
+		Balances:    balances,
+		Expirations: expirations,
 	}, nil
 }
 
@@ -87,6 +99,16 @@ func (h *CurrencyHandler) Grant(ctx context.Context, req *pb.GrantRequest) (*pb.
 		return nil, status.Error(codes.InvalidArgument, "invalid amount format")
 	}
 
+	// 有効期限（任意）
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid expires_at format")
+		}
+		expiresAt = &t
+	}
+
 	// metadataをmap[string]interface{}に変換
 	metadata := make(map[string]interface{})
 	for k, v := range req.Metadata {
@@ -94,12 +116,14 @@ func (h *CurrencyHandler) Grant(ctx context.Context, req *pb.GrantRequest) (*pb.
 	}
 
 	appReq := &currencyapp.GrantRequest{
-		UserID:       req.UserId,
-		CurrencyType: req.CurrencyType,
-		Amount:       amount,
-		Reason:       req.Reason,
-		Requester:    req.Requester,
-		Metadata:     metadata,
+		UserID:         req.UserId,
+		CurrencyType:   req.CurrencyType,
+		Amount:         amount,
+		Reason:         req.Reason,
+		Requester:      api_key.RequesterFromContext(ctx),
+		ExpiresAt:      expiresAt,
+		IdempotencyKey: req.IdempotencyKey,
+		Metadata:       metadata,
 	}
 
 	appResp, err := h.currencyService.Grant(ctx, appReq)
@@ -139,13 +163,14 @@ func (h *CurrencyHandler) Consume(ctx context.Context, req *pb.ConsumeRequest) (
 	}
 
 	appReq := &currencyapp.ConsumeRequest{
-		UserID:       req.UserId,
-		CurrencyType: req.CurrencyType,
-		Amount:       amount,
-		ItemID:       req.ItemId,
-		UsePriority:  req.UsePriority,
-		Requester:    req.Requester,
-		Metadata:     metadata,
+		UserID:         req.UserId,
+		CurrencyType:   req.CurrencyType,
+		Amount:         amount,
+		ItemID:         req.ItemId,
+		UsePriority:    req.UsePriority,
+		Requester:      api_key.RequesterFromContext(ctx),
+		IdempotencyKey: req.IdempotencyKey,
+		Metadata:       metadata,
 	}
 
 	var appResp *currencyapp.ConsumeResponse
@@ -188,6 +213,156 @@ func (h *CurrencyHandler) Consume(ctx context.Context, req *pb.ConsumeRequest) (
 	return resp, nil
 }
 
+// Refund 消費トランザクションの返金
+func (h *CurrencyHandler) Refund(ctx context.Context, req *pb.RefundRequest) (*pb.RefundResponse, error) {
+	if req.TransactionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "transaction_id is required")
+	}
+
+	// metadataをmap[string]interface{}に変換
+	metadata := make(map[string]interface{})
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+
+	appReq := &currencyapp.RefundRequest{
+		TransactionID: req.TransactionId,
+		Reason:        req.Reason,
+		Requester:     api_key.RequesterFromContext(ctx),
+		Metadata:      metadata,
+	}
+
+	appResp, err := h.currencyService.Refund(ctx, appReq)
+	if err != nil {
+		return nil, h.handleError(err)
+	}
+
+	// レスポンスを構築
+	details := make([]*pb.RefundDetail, len(appResp.RefundDetails))
+	for i, detail := range appResp.RefundDetails {
+		details[i] = &pb.RefundDetail{
+			TransactionId:         detail.TransactionID,
+			OriginalTransactionId: detail.OriginalTransactionID,
+			CurrencyType:          detail.CurrencyType,
+			Amount:                strconv.FormatInt(detail.Amount, 10),
+			BalanceBefore:         strconv.FormatInt(detail.BalanceBefore, 10),
+			BalanceAfter:          strconv.FormatInt(detail.BalanceAfter, 10),
+		}
+	}
+
+	return &pb.RefundResponse{
+		TransactionId:         appResp.TransactionID,
+		OriginalTransactionId: appResp.OriginalTransactionID,
+		UserId:                appResp.UserID,
+		RefundDetails:         details,
+		TotalRefunded:         strconv.FormatInt(appResp.TotalRefunded, 10),
+		Status:                appResp.Status,
+	}, nil
+}
+
+// Compensate 補填・回収による残高調整
+func (h *CurrencyHandler) Compensate(ctx context.Context, req *pb.CompensateRequest) (*pb.CompensateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.CurrencyType == "" {
+		return nil, status.Error(codes.InvalidArgument, "currency_type is required")
+	}
+	if req.Amount == "" {
+		return nil, status.Error(codes.InvalidArgument, "amount is required")
+	}
+
+	// 金額をint64に変換（符号付き）
+	amount, err := strconv.ParseInt(req.Amount, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid amount format")
+	}
+
+	// metadataをmap[string]interface{}に変換
+	metadata := make(map[string]interface{})
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+
+	appReq := &currencyapp.CompensateRequest{
+		UserID:       req.UserId,
+		CurrencyType: req.CurrencyType,
+		Amount:       amount,
+		Reason:       req.Reason,
+		Requester:    api_key.RequesterFromContext(ctx),
+		Metadata:     metadata,
+	}
+
+	appResp, err := h.currencyService.Compensate(ctx, appReq)
+	if err != nil {
+		return nil, h.handleError(err)
+	}
+
+	return &pb.CompensateResponse{
+		TransactionId: appResp.TransactionID,
+		UserId:        appResp.UserID,
+		CurrencyType:  appResp.CurrencyType,
+		Amount:        strconv.FormatInt(appResp.Amount, 10),
+		BalanceBefore: strconv.FormatInt(appResp.BalanceBefore, 10),
+		BalanceAfter:  strconv.FormatInt(appResp.BalanceAfter, 10),
+		Status:        appResp.Status,
+	}, nil
+}
+
+// Transfer ユーザー間の通貨譲渡
+func (h *CurrencyHandler) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
+	if req.FromUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "from_user_id is required")
+	}
+	if req.ToUserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "to_user_id is required")
+	}
+	if req.CurrencyType == "" {
+		return nil, status.Error(codes.InvalidArgument, "currency_type is required")
+	}
+	if req.Amount == "" {
+		return nil, status.Error(codes.InvalidArgument, "amount is required")
+	}
+
+	// 金額をint64に変換
+	amount, err := strconv.ParseInt(req.Amount, 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid amount format")
+	}
+
+	// metadataをmap[string]interface{}に変換
+	metadata := make(map[string]interface{})
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+
+	appReq := &currencyapp.TransferRequest{
+		FromUserID:   req.FromUserId,
+		ToUserID:     req.ToUserId,
+		CurrencyType: req.CurrencyType,
+		Amount:       amount,
+		Message:      req.Message,
+		Metadata:     metadata,
+	}
+
+	appResp, err := h.currencyService.Transfer(ctx, appReq)
+	if err != nil {
+		return nil, h.handleError(err)
+	}
+
+	return &pb.TransferResponse{
+		TransferId:            appResp.TransferID,
+		FromUserId:            appResp.FromUserID,
+		ToUserId:              appResp.ToUserID,
+		CurrencyType:          appResp.CurrencyType,
+		Amount:                strconv.FormatInt(appResp.Amount, 10),
+		SenderTransactionId:   appResp.SenderTransactionID,
+		ReceiverTransactionId: appResp.ReceiverTransactionID,
+		BalanceAfter:          strconv.FormatInt(appResp.BalanceAfter, 10),
+		Status:                appResp.Status,
+	}, nil
+}
+
 // ProcessPayment 決済処理
 func (h *CurrencyHandler) ProcessPayment(ctx context.Context, req *pb.ProcessPaymentRequest) (*pb.ProcessPaymentResponse, error) {
 	if req.PaymentRequestId == "" {
@@ -259,6 +434,16 @@ func (h *CurrencyHandler) RedeemCode(ctx context.Context, req *pb.RedeemCodeRequ
 		return nil, h.handleError(err)
 	}
 
+	rewards := make([]*pb.RedeemedReward, len(appResp.Rewards))
+	for i, r := range appResp.Rewards {
+		rewards[i] = &pb.RedeemedReward{
+			TransactionId: r.TransactionID,
+			CurrencyType:  r.CurrencyType,
+			Amount:        strconv.FormatInt(r.Amount, 10),
+			BalanceAfter:  strconv.FormatInt(r.BalanceAfter, 10),
+		}
+	}
+
 	return &pb.RedeemCodeResponse{
 		RedemptionId:  appResp.RedemptionID,
 		TransactionId: appResp.TransactionID,
@@ -267,6 +452,7 @@ func (h *CurrencyHandler) RedeemCode(ctx context.Context, req *pb.RedeemCodeRequ
 		Amount:        strconv.FormatInt(appResp.Amount, 10),
 		BalanceAfter:  strconv.FormatInt(appResp.BalanceAfter, 10),
 		Status:        appResp.Status,
+		Rewards:       rewards,
 	}, nil
 }
 
@@ -289,12 +475,35 @@ func (h *CurrencyHandler) GetTransactionHistory(ctx context.Context, req *pb.Get
 		offset = 0
 	}
 
+	var from *time.Time
+	if req.From != "" {
+		t, err := time.Parse(time.RFC3339, req.From)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid from")
+		}
+		from = &t
+	}
+
+	var to *time.Time
+	if req.To != "" {
+		t, err := time.Parse(time.RFC3339, req.To)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid to")
+		}
+		to = &t
+	}
+
 	appReq := &historyapp.GetTransactionHistoryRequest{
 		UserID:          req.UserId,
 		Limit:           limit,
 		Offset:          offset,
 		CurrencyType:    req.CurrencyType,
 		TransactionType: req.TransactionType,
+		Status:          req.Status,
+		Requester:       req.Requester,
+		From:            from,
+		To:              to,
+		Cursor:          req.Cursor,
 	}
 
 	appResp, err := h.historyService.GetTransactionHistory(ctx, appReq)
@@ -322,6 +531,7 @@ func (h *CurrencyHandler) GetTransactionHistory(ctx context.Context, req *pb.Get
 		Total:        int32(appResp.Total),
 		Limit:        int32(appResp.Limit),
 		Offset:       int32(appResp.Offset),
+		NextCursor:   appResp.NextCursor,
 	}, nil
 }
 
@@ -336,6 +546,22 @@ func (h *CurrencyHandler) handleError(err error) error {
 		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if errors.Is(err, currency.ErrInvalidExpiry) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, currency.ErrCurrencyNotTransferable) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, currency.ErrSelfTransfer) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, currency.ErrRecipientNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+
 	if errors.Is(err, currency.ErrCurrencyNotFound) {
 		return status.Error(codes.NotFound, err.Error())
 	}
@@ -344,6 +570,42 @@ func (h *CurrencyHandler) handleError(err error) error {
 		return status.Error(codes.NotFound, err.Error())
 	}
 
+	if errors.Is(err, transaction.ErrTransactionNotRefundable) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrTransactionAlreadyRefunded) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrDuplicateTransactionID) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrReasonRequired) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrRequesterRequired) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrInvalidCursor) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrInvalidIdempotencyKey) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if errors.Is(err, transaction.ErrIdempotencyKeyConflict) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+
 	if errors.Is(err, payment_request.ErrPaymentRequestNotFound) {
 		return status.Error(codes.NotFound, err.Error())
 	}
@@ -368,6 +630,17 @@ func (h *CurrencyHandler) handleError(err error) error {
 		return status.Error(codes.AlreadyExists, err.Error())
 	}
 
+	if errors.Is(err, redemption_code.ErrUserNotEligible) ||
+		errors.Is(err, redemption_code.ErrNewUsersOnly) ||
+		errors.Is(err, redemption_code.ErrCampaignLimitReached) ||
+		errors.Is(err, redemption_code.ErrCampaignExclusive) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	if errors.Is(err, redemption_code.ErrRedemptionLockedOut) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	// gRPCステータスエラーの場合はそのまま返す
 	if _, ok := status.FromError(err); ok {
 		return err
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -11,7 +10,9 @@ import (
 	currencyapp "gem-server/internal/application/currency"
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/api_key"
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
 	"gem-server/internal/domain/service"
@@ -68,14 +69,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -84,6 +103,19 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 // MockPaymentRequestRepository モックPaymentRequestリポジトリ
 type MockPaymentRequestRepository struct {
 	mock.Mock
@@ -107,15 +139,67 @@ func (m *MockPaymentRequestRepository) Update(ctx context.Context, pr *payment_r
 	return args.Error(0)
 }
 
+// MockLotRepository モックロットリポジトリ
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	args := m.Called(ctx, transactionID, consumptions)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]currency.LotConsumption), args.Error(1)
+}
+
+// newEmptyLotRepository 有効期限付きロットが存在しない状態のモックロットリポジトリを作成
+func newEmptyLotRepository() *MockLotRepository {
+	m := new(MockLotRepository)
+	m.On("FindAvailableByUserIDAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*currency.Lot{}, nil).Maybe()
+	m.On("FindConsumptionsByTransactionID", mock.Anything, mock.Anything).Return([]currency.LotConsumption{}, nil).Maybe()
+	return m
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(ctx, fn)
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
@@ -138,16 +222,46 @@ func (m *MockRedemptionCodeRepository) Update(ctx context.Context, code *redempt
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) IncrementUses(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
 	args := m.Called(ctx, code, userID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRedemptionCodeRepository) CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (redemption_code.CampaignUsage, error) {
+	args := m.Called(ctx, campaignID, userID)
+	return args.Get(0).(redemption_code.CampaignUsage), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) LockUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockRedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
 	args := m.Called(ctx, redemption)
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) UpdateSettings(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) SaveAuditLog(ctx context.Context, log *redemption_code.CodeAuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
 	args := m.Called(ctx, code)
 	return args.Error(0)
@@ -158,14 +272,56 @@ func (m *MockRedemptionCodeRepository) Delete(ctx context.Context, code string)
 	return args.Error(0)
 }
 
-func (m *MockRedemptionCodeRepository) FindAll(ctx context.Context, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockRedemptionCodeRepository) CreateBatch(ctx context.Context, codes []*redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, codes)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindByBatchID(ctx context.Context, batchID string) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindByFilter(ctx context.Context, filter redemption_code.Filter, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
 	return args.Get(0).([]*redemption_code.RedemptionCode), args.Int(1), args.Error(2)
 }
 
+func (m *MockRedemptionCodeRepository) FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, code, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) GetCodeStats(ctx context.Context, code string) (redemption_code.CodeStats, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(redemption_code.CodeStats), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
 // setupTestHandler テスト用のハンドラーをセットアップ
 func setupTestHandler(t *testing.T) (*CurrencyHandler, *MockCurrencyRepository, *MockTransactionRepository, *MockPaymentRequestRepository, *MockTransactionManager, *MockRedemptionCodeRepository) {
 	t.Helper()
@@ -184,16 +340,22 @@ func setupTestHandler(t *testing.T) (*CurrencyHandler, *MockCurrencyRepository,
 	currencyAppService := currencyapp.NewCurrencyApplicationService(
 		mockCurrencyRepo,
 		mockTransactionRepo,
+		newEmptyLotRepository(),
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		currency.NewTransferPolicy(currency.CurrencyTypeFree),
 		currencyService,
+		nil,
 		logger,
 		metrics,
 	)
 	paymentAppService := paymentapp.NewPaymentApplicationService(
 		mockCurrencyRepo,
 		mockTransactionRepo,
+		newEmptyLotRepository(),
 		mockPaymentRequestRepo,
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
 		logger,
 		metrics,
 	)
@@ -202,6 +364,9 @@ func setupTestHandler(t *testing.T) (*CurrencyHandler, *MockCurrencyRepository,
 		mockTransactionRepo,
 		mockRedemptionCodeRepo,
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		nil,
+		nil,
 		logger,
 		metrics,
 	)
@@ -322,7 +487,7 @@ func TestCurrencyHandler_Grant(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GrantResponse) {
@@ -344,7 +509,7 @@ func TestCurrencyHandler_Grant(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(freeCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GrantResponse) {
@@ -393,6 +558,28 @@ func TestCurrencyHandler_Grant(t *testing.T) {
 			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
 			expectedStatus: codes.InvalidArgument,
 		},
+		{
+			name: "異常系: 無効なexpires_atフォーマット",
+			req: &pb.GrantRequest{
+				UserId:       "user123",
+				CurrencyType: "free",
+				Amount:       "100",
+				ExpiresAt:    "2030-01-01",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: 有償通貨に有効期限を指定",
+			req: &pb.GrantRequest{
+				UserId:       "user123",
+				CurrencyType: "paid",
+				Amount:       "100",
+				ExpiresAt:    "2099-01-01T00:00:00Z",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
 		{
 			name: "異常系: 無効な金額（負の値）",
 			req: &pb.GrantRequest{
@@ -415,7 +602,7 @@ func TestCurrencyHandler_Grant(t *testing.T) {
 				mcr.On("Create", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GrantResponse) {
@@ -477,7 +664,7 @@ func TestCurrencyHandler_Consume(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.ConsumeResponse) {
@@ -504,7 +691,7 @@ func TestCurrencyHandler_Consume(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(freeCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.ConsumeResponse) {
@@ -531,7 +718,7 @@ func TestCurrencyHandler_Consume(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(freeCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.ConsumeResponse) {
@@ -591,7 +778,7 @@ func TestCurrencyHandler_Consume(t *testing.T) {
 			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
 				paidCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.FailedPrecondition,
 		},
@@ -626,6 +813,297 @@ func TestCurrencyHandler_Consume(t *testing.T) {
 	}
 }
 
+func TestCurrencyHandler_Refund(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            *pb.RefundRequest
+		setupMock      func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		expectedStatus codes.Code
+		checkResponse  func(*testing.T, *pb.RefundResponse)
+	}{
+		{
+			name: "正常系: 単一通貨タイプの消費を返金",
+			req: &pb.RefundRequest{
+				TransactionId: "txn_123",
+				Reason:        "誤購入",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				original := mustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 100, 1000, 900, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(nil, transaction.ErrTransactionNotFound)
+				paidCurrency := mustNewCurrency("user123", currency.CurrencyTypePaid, 900, 1)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: codes.OK,
+			checkResponse: func(t *testing.T, resp *pb.RefundResponse) {
+				assert.Equal(t, "refund_txn_123", resp.TransactionId)
+				assert.Equal(t, "txn_123", resp.OriginalTransactionId)
+				assert.Equal(t, "user123", resp.UserId)
+				assert.Equal(t, "100", resp.TotalRefunded)
+				require.Len(t, resp.RefundDetails, 1)
+				assert.Equal(t, "1000", resp.RefundDetails[0].BalanceAfter)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name: "異常系: transaction_idが空",
+			req: &pb.RefundRequest{
+				TransactionId: "",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: 返金済み",
+			req: &pb.RefundRequest{
+				TransactionId: "txn_123",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				original := mustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 100, 1000, 900, transaction.TransactionStatusCompleted, nil)
+				refunded := mustNewTransaction("refund_txn_123", "user123", transaction.TransactionTypeRefund, currency.CurrencyTypePaid, 100, 900, 1000, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(refunded, nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: codes.AlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockCurrencyRepo, mockTransactionRepo, _, mockTxManager, _ := setupTestHandler(t)
+
+			tt.setupMock(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			ctx := context.Background()
+			resp, err := handler.Refund(ctx, tt.req)
+
+			if tt.expectedStatus == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				if tt.checkResponse != nil {
+					tt.checkResponse(t, resp)
+				}
+			} else {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+			}
+
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockTxManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyHandler_Compensate(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            *pb.CompensateRequest
+		apiKeyName     string // 認証した管理APIキーの名前（空の場合はインターセプターを経由していない）
+		setupMock      func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		expectedStatus codes.Code
+		checkResponse  func(*testing.T, *pb.CompensateResponse)
+	}{
+		{
+			name: "正常系: 回収でマイナス残高になる",
+			req: &pb.CompensateRequest{
+				UserId:       "user123",
+				CurrencyType: "paid",
+				Amount:       "-300",
+				Reason:       "不正付与の回収",
+			},
+			apiKeyName: "ops-tool",
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 100, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+					return tx.Requester() != nil && *tx.Requester() == "ops-tool"
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: codes.OK,
+			checkResponse: func(t *testing.T, resp *pb.CompensateResponse) {
+				assert.NotEmpty(t, resp.TransactionId)
+				assert.Equal(t, "-300", resp.Amount)
+				assert.Equal(t, "100", resp.BalanceBefore)
+				assert.Equal(t, "-200", resp.BalanceAfter)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name: "異常系: amountが空",
+			req: &pb.CompensateRequest{
+				UserId:       "user123",
+				CurrencyType: "paid",
+				Reason:       "補填",
+			},
+			apiKeyName:     "ops-tool",
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: reasonが空",
+			req: &pb.CompensateRequest{
+				UserId:       "user123",
+				CurrencyType: "paid",
+				Amount:       "100",
+			},
+			apiKeyName:     "ops-tool",
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: 管理APIキーの名前がない",
+			req: &pb.CompensateRequest{
+				UserId:       "user123",
+				CurrencyType: "paid",
+				Amount:       "100",
+				Reason:       "補填",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockCurrencyRepo, mockTransactionRepo, _, mockTxManager, _ := setupTestHandler(t)
+
+			tt.setupMock(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			ctx := context.Background()
+			if tt.apiKeyName != "" {
+				key, err := api_key.NewAPIKey(tt.apiKeyName, api_key.HashKey("ops-key"), []api_key.Scope{api_key.ScopeCurrencyCompensate})
+				require.NoError(t, err)
+				ctx = api_key.NewContext(ctx, key)
+			}
+			resp, err := handler.Compensate(ctx, tt.req)
+
+			if tt.expectedStatus == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				if tt.checkResponse != nil {
+					tt.checkResponse(t, resp)
+				}
+			} else {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+			}
+
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockTxManager.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCurrencyHandler_Transfer(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            *pb.TransferRequest
+		setupMock      func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		expectedStatus codes.Code
+		checkResponse  func(*testing.T, *pb.TransferResponse)
+	}{
+		{
+			name: "正常系: 無償通貨を譲渡",
+			req: &pb.TransferRequest{
+				FromUserId:   "user123",
+				ToUserId:     "friend456",
+				CurrencyType: "free",
+				Amount:       "100",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 500, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "friend456", currency.CurrencyTypeFree).Return(mustNewCurrency("friend456", currency.CurrencyTypeFree, 0, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: codes.OK,
+			checkResponse: func(t *testing.T, resp *pb.TransferResponse) {
+				assert.NotEmpty(t, resp.TransferId)
+				assert.Equal(t, resp.TransferId+"_in", resp.ReceiverTransactionId)
+				assert.Equal(t, "100", resp.Amount)
+				assert.Equal(t, "400", resp.BalanceAfter)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name: "異常系: 譲渡できない通貨タイプ",
+			req: &pb.TransferRequest{
+				FromUserId:   "user123",
+				ToUserId:     "friend456",
+				CurrencyType: "paid",
+				Amount:       "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: 残高不足",
+			req: &pb.TransferRequest{
+				FromUserId:   "user123",
+				ToUserId:     "friend456",
+				CurrencyType: "free",
+				Amount:       "1000",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 500, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "friend456", currency.CurrencyTypeFree).Return(mustNewCurrency("friend456", currency.CurrencyTypeFree, 0, 1), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: codes.FailedPrecondition,
+		},
+		{
+			name: "異常系: to_user_idが空",
+			req: &pb.TransferRequest{
+				FromUserId:   "user123",
+				CurrencyType: "free",
+				Amount:       "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockCurrencyRepo, mockTransactionRepo, _, mockTxManager, _ := setupTestHandler(t)
+
+			tt.setupMock(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			ctx := context.Background()
+			resp, err := handler.Transfer(ctx, tt.req)
+
+			if tt.expectedStatus == codes.OK {
+				require.NoError(t, err)
+				require.NotNil(t, resp)
+				if tt.checkResponse != nil {
+					tt.checkResponse(t, resp)
+				}
+			} else {
+				require.Error(t, err)
+				st, ok := status.FromError(err)
+				require.True(t, ok)
+				assert.Equal(t, tt.expectedStatus, st.Code())
+			}
+
+			mockCurrencyRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockTxManager.AssertExpectations(t)
+		})
+	}
+}
+
 func TestCurrencyHandler_ProcessPayment(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -655,7 +1133,7 @@ func TestCurrencyHandler_ProcessPayment(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil).Maybe()
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil).Maybe()
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Maybe()
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.ProcessPaymentResponse) {
@@ -727,7 +1205,7 @@ func TestCurrencyHandler_ProcessPayment(t *testing.T) {
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil).Maybe()
 				mcr.On("Save", mock.Anything, mock.Anything).Return(nil)
 				mtr.On("Save", mock.Anything, mock.Anything).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.ProcessPaymentResponse) {
@@ -825,10 +1303,10 @@ func TestCurrencyHandler_RedeemCode(t *testing.T) {
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(paidCurrency, nil)
 				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
 				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
-				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.RedeemCodeResponse) {
@@ -841,6 +1319,47 @@ func TestCurrencyHandler_RedeemCode(t *testing.T) {
 				assert.Equal(t, "completed", resp.Status)
 			},
 		},
+		{
+			name: "正常系: 複数の報酬を持つコードの引き換え",
+			req: &pb.RedeemCodeRequest{
+				Code:   "BUNDLECODE",
+				UserId: "user123",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtm *MockTransactionManager) {
+				now := time.Now()
+				free, _ := redemption_code.NewReward(currency.CurrencyTypeFree, 100)
+				paid, _ := redemption_code.NewReward(currency.CurrencyTypePaid, 10)
+				code, _ := redemption_code.NewRedemptionCodeWithRewards(
+					"BUNDLECODE",
+					redemption_code.CodeTypeEvent,
+					[]redemption_code.Reward{free, paid},
+					1,
+					now.Add(-24*time.Hour),
+					now.Add(24*time.Hour),
+					nil,
+				)
+				mrcr.On("FindByCode", mock.Anything, "BUNDLECODE").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "BUNDLECODE", "user123").Return(false, nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(mustNewCurrency("user123", currency.CurrencyTypeFree, 200, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(mustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Twice()
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: codes.OK,
+			checkResponse: func(t *testing.T, resp *pb.RedeemCodeResponse) {
+				require.Len(t, resp.Rewards, 2)
+				assert.Equal(t, "free", resp.Rewards[0].CurrencyType)
+				assert.Equal(t, "100", resp.Rewards[0].Amount)
+				assert.Equal(t, "300", resp.Rewards[0].BalanceAfter)
+				assert.Equal(t, "paid", resp.Rewards[1].CurrencyType)
+				assert.Equal(t, "10", resp.Rewards[1].Amount)
+				assert.Equal(t, "1010", resp.Rewards[1].BalanceAfter)
+				assert.Equal(t, resp.Rewards[0].TransactionId, resp.TransactionId)
+			},
+		},
 		{
 			name: "異常系: codeが空",
 			req: &pb.RedeemCodeRequest{
@@ -990,12 +1509,13 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 10, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(25, nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
 				assert.Len(t, resp.Transactions, 2)
-				assert.Equal(t, int32(2), resp.Total)
+				assert.Equal(t, int32(25), resp.Total)
 				assert.Equal(t, int32(10), resp.Limit)
 				assert.Equal(t, int32(0), resp.Offset)
 				assert.Equal(t, "txn1", resp.Transactions[0].TransactionId)
@@ -1011,7 +1531,8 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
 				txns := []*transaction.Transaction{}
-				mtr.On("FindByUserID", mock.Anything, "user123", 50, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 50, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(0, nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
@@ -1027,7 +1548,8 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
 				txns := []*transaction.Transaction{}
-				mtr.On("FindByUserID", mock.Anything, "user123", 100, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 100, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(0, nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
@@ -1043,7 +1565,8 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
 				txns := []*transaction.Transaction{}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 10, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(0, nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
@@ -1060,7 +1583,9 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
 				txns := []*transaction.Transaction{}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(txns, nil)
+				filter := transaction.Filter{UserID: "user123", CurrencyType: currency.CurrencyTypePaid}
+				mtr.On("FindByFilter", mock.Anything, filter, 10, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(0, nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
@@ -1077,13 +1602,94 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
 				txns := []*transaction.Transaction{}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(txns, nil)
+				filter := transaction.Filter{UserID: "user123", TransactionType: transaction.TransactionTypeGrant}
+				mtr.On("FindByFilter", mock.Anything, filter, 10, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(0, nil)
 			},
 			expectedStatus: codes.OK,
 			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
 				assert.NotNil(t, resp)
 			},
 		},
+		{
+			name: "正常系: status・requester・期間でフィルタリング",
+			req: &pb.GetTransactionHistoryRequest{
+				UserId:    "user123",
+				Limit:     10,
+				Offset:    0,
+				Status:    "completed",
+				Requester: "game-server",
+				From:      "2025-01-01T00:00:00Z",
+				To:        "2025-02-01T00:00:00Z",
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+				filter := transaction.Filter{
+					UserID:    "user123",
+					Status:    transaction.TransactionStatusCompleted,
+					Requester: "game-server",
+					From:      &from,
+					To:        &to,
+				}
+				mtr.On("FindByFilter", mock.Anything, filter, 10, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(0, nil)
+			},
+			expectedStatus: codes.OK,
+		},
+		{
+			name: "正常系: cursorで次のページを取得",
+			req: &pb.GetTransactionHistoryRequest{
+				UserId: "user123",
+				Limit:  1,
+				Cursor: transaction.Cursor{
+					CreatedAt:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					TransactionID: "txn3",
+				}.Encode(),
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				txns := []*transaction.Transaction{
+					mustNewTransaction("txn2", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, map[string]interface{}{}),
+					mustNewTransaction("txn1", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, map[string]interface{}{}),
+				}
+				mtr.On("FindByFilterAfterCursor", mock.Anything, transaction.Filter{UserID: "user123"}, mock.Anything, 2).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(3, nil)
+			},
+			expectedStatus: codes.OK,
+			checkResponse: func(t *testing.T, resp *pb.GetTransactionHistoryResponse) {
+				require.Len(t, resp.Transactions, 1)
+				next, err := transaction.DecodeCursor(resp.NextCursor)
+				require.NoError(t, err)
+				assert.Equal(t, "txn2", next.TransactionID)
+			},
+		},
+		{
+			name: "異常系: cursorが不正",
+			req: &pb.GetTransactionHistoryRequest{
+				UserId: "user123",
+				Cursor: "invalid!",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: fromの形式が不正",
+			req: &pb.GetTransactionHistoryRequest{
+				UserId: "user123",
+				From:   "yesterday",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: codes.InvalidArgument,
+		},
+		{
+			name: "異常系: 無効なtransaction_type",
+			req: &pb.GetTransactionHistoryRequest{
+				UserId:          "user123",
+				TransactionType: "payment",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: codes.InvalidArgument,
+		},
 		{
 			name: "異常系: user_idが空",
 			req: &pb.GetTransactionHistoryRequest{
@@ -1100,7 +1706,7 @@ func TestCurrencyHandler_GetTransactionHistory(t *testing.T) {
 				Offset: 0,
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 0).Return(nil, transaction.ErrTransactionNotFound)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 10, 0).Return(nil, transaction.ErrTransactionNotFound)
 			},
 			expectedStatus: codes.NotFound,
 		},
@@ -1152,6 +1758,21 @@ func TestCurrencyHandler_handleError(t *testing.T) {
 			err:          currency.ErrInvalidAmount,
 			expectedCode: codes.InvalidArgument,
 		},
+		{
+			name:         "currency.ErrCurrencyNotTransferable -> InvalidArgument",
+			err:          currency.ErrCurrencyNotTransferable,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "currency.ErrSelfTransfer -> InvalidArgument",
+			err:          currency.ErrSelfTransfer,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "currency.ErrRecipientNotFound -> NotFound",
+			err:          currency.ErrRecipientNotFound,
+			expectedCode: codes.NotFound,
+		},
 		{
 			name:         "currency.ErrCurrencyNotFound -> NotFound",
 			err:          currency.ErrCurrencyNotFound,
@@ -1162,6 +1783,56 @@ func TestCurrencyHandler_handleError(t *testing.T) {
 			err:          transaction.ErrTransactionNotFound,
 			expectedCode: codes.NotFound,
 		},
+		{
+			name:         "currency.ErrInvalidExpiry -> InvalidArgument",
+			err:          currency.ErrInvalidExpiry,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "transaction.ErrTransactionNotRefundable -> FailedPrecondition",
+			err:          transaction.ErrTransactionNotRefundable,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "transaction.ErrTransactionAlreadyRefunded -> AlreadyExists",
+			err:          transaction.ErrTransactionAlreadyRefunded,
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "transaction.ErrDuplicateTransactionID -> AlreadyExists",
+			err:          transaction.ErrDuplicateTransactionID,
+			expectedCode: codes.AlreadyExists,
+		},
+		{
+			name:         "transaction.ErrReasonRequired -> InvalidArgument",
+			err:          transaction.ErrReasonRequired,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "transaction.ErrRequesterRequired -> InvalidArgument",
+			err:          transaction.ErrRequesterRequired,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "transaction.ErrInvalidFilter -> InvalidArgument",
+			err:          transaction.ErrInvalidFilter,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "transaction.ErrInvalidCursor -> InvalidArgument",
+			err:          transaction.ErrInvalidCursor,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "transaction.ErrInvalidIdempotencyKey -> InvalidArgument",
+			err:          transaction.ErrInvalidIdempotencyKey,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "transaction.ErrIdempotencyKeyConflict -> AlreadyExists",
+			err:          transaction.ErrIdempotencyKeyConflict,
+			expectedCode: codes.AlreadyExists,
+		},
 		{
 			name:         "payment_request.ErrPaymentRequestNotFound -> NotFound",
 			err:          payment_request.ErrPaymentRequestNotFound,
@@ -1192,6 +1863,31 @@ func TestCurrencyHandler_handleError(t *testing.T) {
 			err:          redemption_code.ErrUserAlreadyRedeemed,
 			expectedCode: codes.AlreadyExists,
 		},
+		{
+			name:         "redemption_code.ErrUserNotEligible -> PermissionDenied",
+			err:          redemption_code.ErrUserNotEligible,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "redemption_code.ErrNewUsersOnly -> PermissionDenied",
+			err:          redemption_code.ErrNewUsersOnly,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "redemption_code.ErrCampaignLimitReached -> PermissionDenied",
+			err:          redemption_code.ErrCampaignLimitReached,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "redemption_code.ErrCampaignExclusive -> PermissionDenied",
+			err:          redemption_code.ErrCampaignExclusive,
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "redemption_code.LockoutError -> ResourceExhausted",
+			err:          &redemption_code.LockoutError{Until: time.Now().Add(time.Hour)},
+			expectedCode: codes.ResourceExhausted,
+		},
 		{
 			name:         "gRPCステータスエラーはそのまま返す",
 			err:          status.Error(codes.Unauthenticated, "unauthorized"),
@@ -2,9 +2,12 @@ package interceptor
 
 import (
 	"context"
-	"strings"
+	"errors"
 
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/ipaccess"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 
 	"google.golang.org/grpc"
@@ -14,7 +17,17 @@ import (
 )
 
 // APIKeyInterceptor APIキー認証インターセプター
-func APIKeyInterceptor(cfg *config.AdminAPIConfig, logger *otelinfra.Logger) grpc.UnaryServerInterceptor {
+// methodScopesでメソッドごとに必要なスコープを指定する（登録されていないメソッドは拒否する）。
+// 認証した管理APIキーはコンテキストに格納し、ハンドラーはその名前をリクエスト元として記録する。
+// allowlistで制限している場合、クライアントのIPアドレスはresolverで求める（特定できない場合は拒否する）
+func APIKeyInterceptor(
+	cfg *config.AdminAPIConfig,
+	authenticator *apikey.Authenticator,
+	allowlist *ipaccess.Allowlist,
+	resolver *ipaccess.Resolver,
+	methodScopes map[string]api_key.Scope,
+	logger *otelinfra.Logger,
+) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -44,62 +57,39 @@ func APIKeyInterceptor(cfg *config.AdminAPIConfig, logger *otelinfra.Logger) grp
 		apiKey := apiKeys[0]
 
 		// APIキーの検証
-		if apiKey != cfg.APIKey {
+		key, err := authenticator.Authenticate(ctx, apiKey)
+		if errors.Is(err, api_key.ErrAPIKeyNotFound) {
 			logger.Warn(ctx, "Invalid API key", nil)
 			return nil, status.Error(codes.Unauthenticated, "invalid API key")
 		}
+		if err != nil {
+			logger.Error(ctx, "Failed to authenticate API key", err, nil)
+			return nil, status.Error(codes.Unavailable, "failed to authenticate API key")
+		}
 
 		// IP制限のチェック（設定されている場合）
-		if len(cfg.AllowedIPs) > 0 {
-			clientIP := getClientIPFromMetadata(md)
-			if clientIP != "" && !isIPAllowed(clientIP, cfg.AllowedIPs) {
+		if allowlist.Restricted() {
+			ip, ok := clientIP(ctx, resolver)
+			if !ok || !allowlist.Allows(ip) {
 				logger.Warn(ctx, "IP address not allowed", map[string]interface{}{
-					"ip": clientIP,
+					"ip": ip.String(),
 				})
 				return nil, status.Error(codes.PermissionDenied, "IP address not allowed")
 			}
 		}
 
-		// 次のハンドラーを実行
-		return handler(ctx, req)
-	}
-}
-
-// getClientIPFromMetadata メタデータからクライアントのIPアドレスを取得
-func getClientIPFromMetadata(md metadata.MD) string {
-	// X-Forwarded-Forメタデータから取得
-	forwardedFor := md.Get("x-forwarded-for")
-	if len(forwardedFor) > 0 {
-		// カンマ区切りの最初のIPを取得
-		ips := strings.Split(forwardedFor[0], ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
+		// スコープのチェック
+		scope, ok := methodScopes[info.FullMethod]
+		if !ok || !key.HasScope(scope) {
+			logger.Warn(ctx, "API key does not have the required scope", map[string]interface{}{
+				"method":       info.FullMethod,
+				"scope":        scope.String(),
+				"api_key_name": key.Name(),
+			})
+			return nil, status.Error(codes.PermissionDenied, "API key does not have the required scope")
 		}
-	}
-
-	// X-Real-IPメタデータから取得
-	realIP := md.Get("x-real-ip")
-	if len(realIP) > 0 {
-		return realIP[0]
-	}
-
-	return ""
-}
 
-// isIPAllowed IPアドレスが許可リストに含まれているかチェック
-func isIPAllowed(ip string, allowedIPs []string) bool {
-	for _, allowedIP := range allowedIPs {
-		if ip == allowedIP {
-			return true
-		}
-		// CIDR表記のサポート（簡易版）
-		if strings.Contains(allowedIP, "/") {
-			// CIDRマッチングの実装は必要に応じて追加
-			// ここでは簡易的にプレフィックスマッチのみ
-			if strings.HasPrefix(ip, strings.Split(allowedIP, "/")[0]) {
-				return true
-			}
-		}
+		// 次のハンドラーを実行
+		return handler(api_key.NewContext(ctx, key), req)
 	}
-	return false
 }
@@ -2,26 +2,40 @@ package interceptor
 
 import (
 	"context"
+	"net"
 	"testing"
 
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/ipaccess"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 func TestAPIKeyInterceptor(t *testing.T) {
+	methodScopes := map[string]api_key.Scope{
+		"/test.Test/TestMethod": api_key.ScopeCurrencyGrant,
+	}
+
 	tests := []struct {
-		name          string
-		apiKey        string
-		config        *config.AdminAPIConfig
-		expectedCode  codes.Code
-		expectedError string
+		name           string
+		apiKey         string
+		method         string
+		peerIP         string
+		forwardedFor   string
+		trustedProxies []string
+		config         *config.AdminAPIConfig
+		expectedCode   codes.Code
+		expectedError  string
 	}{
 		{
 			name:   "正常系: 有効なAPIキー",
@@ -62,6 +76,66 @@ func TestAPIKeyInterceptor(t *testing.T) {
 			expectedCode:  codes.PermissionDenied,
 			expectedError: "admin API is disabled",
 		},
+		{
+			name:   "異常系: 必要なスコープがないメソッド",
+			apiKey: "test-api-key",
+			method: "/test.Test/UnknownMethod",
+			config: &config.AdminAPIConfig{
+				Enabled: true,
+				APIKey:  "test-api-key",
+			},
+			expectedCode:  codes.PermissionDenied,
+			expectedError: "required scope",
+		},
+		{
+			name:   "正常系: IP制限あり（CIDRの範囲内の接続元）",
+			apiKey: "test-api-key",
+			peerIP: "10.1.2.3",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "異常系: IP制限あり（接続元のアドレスが不明）",
+			apiKey:       "test-api-key",
+			forwardedFor: "10.0.0.1",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+			expectedCode:  codes.PermissionDenied,
+			expectedError: "IP address not allowed",
+		},
+		{
+			name:         "異常系: IP制限あり（信頼しない接続元のx-forwarded-forは無視する）",
+			apiKey:       "test-api-key",
+			peerIP:       "203.0.113.5",
+			forwardedFor: "10.0.0.1",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+			expectedCode:  codes.PermissionDenied,
+			expectedError: "IP address not allowed",
+		},
+		{
+			name:           "正常系: IP制限あり（信頼するプロキシ経由のIPv6）",
+			apiKey:         "test-api-key",
+			peerIP:         "192.0.2.10",
+			forwardedFor:   "2001:db8::5",
+			trustedProxies: []string{"192.0.2.10"},
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"2001:db8::/32"},
+			},
+			expectedCode: codes.OK,
+		},
 		{
 			name:   "異常系: メタデータが存在しない",
 			apiKey: "",
@@ -79,7 +153,13 @@ func TestAPIKeyInterceptor(t *testing.T) {
 			tracer := noop.NewTracerProvider().Tracer("test")
 			logger := otelinfra.NewLogger(tracer)
 
-			interceptor := APIKeyInterceptor(tt.config, logger)
+			authenticator, err := apikey.NewAuthenticator(tt.config, nil)
+			require.NoError(t, err)
+			allowlist, err := ipaccess.NewAllowlist(tt.config.AllowedIPs)
+			require.NoError(t, err)
+			resolver, err := ipaccess.NewResolver(tt.trustedProxies)
+			require.NoError(t, err)
+			interceptor := APIKeyInterceptor(tt.config, authenticator, allowlist, resolver, methodScopes, logger)
 
 			ctx := context.Background()
 			if tt.name != "異常系: メタデータが存在しない" {
@@ -87,15 +167,27 @@ func TestAPIKeyInterceptor(t *testing.T) {
 				if tt.apiKey != "" {
 					md.Set("x-api-key", tt.apiKey)
 				}
+				if tt.forwardedFor != "" {
+					md.Set("x-forwarded-for", tt.forwardedFor)
+				}
 				ctx = metadata.NewIncomingContext(ctx, md)
 			}
+			if tt.peerIP != "" {
+				ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(tt.peerIP), Port: 50000}})
+			}
 
 			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				// 認証したキーの名前がハンドラーに渡される
+				assert.Equal(t, apikey.LegacyKeyName, api_key.RequesterFromContext(ctx))
 				return "success", nil
 			}
 
+			method := tt.method
+			if method == "" {
+				method = "/test.Test/TestMethod"
+			}
 			info := &grpc.UnaryServerInfo{
-				FullMethod: "/test.Test/TestMethod",
+				FullMethod: method,
 			}
 
 			resp, err := interceptor(ctx, "test-request", info, handler)
@@ -0,0 +1,30 @@
+package interceptor
+
+import (
+	"context"
+	"net/netip"
+
+	"gem-server/internal/infrastructure/ipaccess"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// clientIP クライアントのIPアドレスを取得
+// 接続元（peer）のアドレスを基準とし、x-forwarded-for/x-real-ipメタデータは信頼するプロキシからの接続の場合のみ使用する
+func clientIP(ctx context.Context, resolver *ipaccess.Resolver) (netip.Addr, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return netip.Addr{}, false
+	}
+
+	var forwardedFor []string
+	var realIP string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		forwardedFor = md.Get("x-forwarded-for")
+		if values := md.Get("x-real-ip"); len(values) > 0 {
+			realIP = values[0]
+		}
+	}
+	return resolver.ClientIP(p.Addr.String(), forwardedFor, realIP)
+}
@@ -0,0 +1,89 @@
+package interceptor
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"gem-server/internal/infrastructure/ipaccess"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitKeyFunc コンテキストからレート制限のキーを取得する（空文字の場合は制限しない）
+type RateLimitKeyFunc func(ctx context.Context) string
+
+// RateLimitInterceptor レート制限インターセプター
+// 制限を超えた場合はResourceExhaustedを返し、retry-afterヘッダーに待機秒数を設定する。
+// Limiterが利用できない場合はリクエストを許可する
+func RateLimitInterceptor(limiter ratelimit.Limiter, keyFunc RateLimitKeyFunc, logger *otelinfra.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		key := keyFunc(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		res, err := limiter.Allow(ctx, key)
+		if err != nil {
+			logger.Warn(ctx, "Rate limiter unavailable", map[string]interface{}{
+				"error": err.Error(),
+			})
+			return handler(ctx, req)
+		}
+
+		if !res.Allowed {
+			logger.Warn(ctx, "Rate limit exceeded", map[string]interface{}{
+				"method": info.FullMethod,
+			})
+			// ヘッダーの送信に失敗してもステータスは返す
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfterSeconds(res.RetryAfter))))
+			return nil, status.Error(codes.ResourceExhausted, "too many requests")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitByIP クライアントのIPアドレスをキーにする（IPアドレスはresolverで求める）
+func RateLimitByIP(resolver *ipaccess.Resolver) RateLimitKeyFunc {
+	return func(ctx context.Context) string {
+		ip, ok := clientIP(ctx, resolver)
+		if !ok {
+			return ""
+		}
+		return ip.String()
+	}
+}
+
+// RateLimitByAPIKey APIキーをキーにする（ハッシュ化して保持する）
+func RateLimitByAPIKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	apiKeys := md.Get("x-api-key")
+	if len(apiKeys) == 0 || apiKeys[0] == "" {
+		return ""
+	}
+	return ratelimit.HashKey(apiKeys[0])
+}
+
+// retryAfterSeconds retry-afterヘッダーの秒数（切り上げ、最小1秒）
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
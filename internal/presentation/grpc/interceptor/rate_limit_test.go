@@ -0,0 +1,147 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"gem-server/internal/infrastructure/ipaccess"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// stubLimiter 固定の結果を返すLimiter
+type stubLimiter struct {
+	result *ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (l *stubLimiter) Allow(ctx context.Context, key string) (*ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	return l.result, l.err
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	direct, err := ipaccess.NewResolver(nil)
+	require.NoError(t, err)
+	viaProxy, err := ipaccess.NewResolver([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	withPeer := func(ctx context.Context, ip string) context.Context {
+		return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000}})
+	}
+
+	tests := []struct {
+		name         string
+		limiter      *stubLimiter
+		keyFunc      RateLimitKeyFunc
+		setupContext func(ctx context.Context) context.Context
+		expectedCode codes.Code
+		expectedKeys []string
+	}{
+		{
+			name:    "正常系: 制限内",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc: RateLimitByAPIKey,
+			setupContext: func(ctx context.Context) context.Context {
+				return metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "test-api-key"))
+			},
+			expectedCode: codes.OK,
+			expectedKeys: []string{ratelimit.HashKey("test-api-key")},
+		},
+		{
+			name:    "異常系: 制限超過",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: false, RetryAfter: time.Second}},
+			keyFunc: RateLimitByIP(direct),
+			setupContext: func(ctx context.Context) context.Context {
+				return withPeer(ctx, "192.0.2.1")
+			},
+			expectedCode: codes.ResourceExhausted,
+			expectedKeys: []string{"192.0.2.1"},
+		},
+		{
+			name:    "正常系: 信頼しない接続元のメタデータのIPアドレスは無視する",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc: RateLimitByIP(direct),
+			setupContext: func(ctx context.Context) context.Context {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-real-ip", "192.0.2.1"))
+				return withPeer(ctx, "198.51.100.7")
+			},
+			expectedCode: codes.OK,
+			expectedKeys: []string{"198.51.100.7"},
+		},
+		{
+			name:    "正常系: 信頼するプロキシ経由の場合はメタデータのIPアドレスを使用",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc: RateLimitByIP(viaProxy),
+			setupContext: func(ctx context.Context) context.Context {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "192.0.2.1"))
+				return withPeer(ctx, "10.0.0.2")
+			},
+			expectedCode: codes.OK,
+			expectedKeys: []string{"192.0.2.1"},
+		},
+		{
+			name:         "正常系: キーがない場合は制限しない",
+			limiter:      &stubLimiter{result: &ratelimit.Result{Allowed: false}},
+			keyFunc:      RateLimitByAPIKey,
+			expectedCode: codes.OK,
+		},
+		{
+			name:    "正常系: Limiterが利用できない場合は許可",
+			limiter: &stubLimiter{err: errors.New("connection refused")},
+			keyFunc: RateLimitByAPIKey,
+			setupContext: func(ctx context.Context) context.Context {
+				return metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", "test-api-key"))
+			},
+			expectedCode: codes.OK,
+			expectedKeys: []string{ratelimit.HashKey("test-api-key")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+
+			interceptor := RateLimitInterceptor(tt.limiter, tt.keyFunc, logger)
+
+			ctx := context.Background()
+			if tt.setupContext != nil {
+				ctx = tt.setupContext(ctx)
+			}
+
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "success", nil
+			}
+
+			info := &grpc.UnaryServerInfo{
+				FullMethod: "/test.Test/TestMethod",
+			}
+
+			resp, err := interceptor(ctx, "test-request", info, handler)
+
+			if tt.expectedCode == codes.OK {
+				assert.NoError(t, err)
+				assert.Equal(t, "success", resp)
+			} else {
+				assert.Error(t, err)
+				st, ok := status.FromError(err)
+				assert.True(t, ok)
+				assert.Equal(t, tt.expectedCode, st.Code())
+			}
+			assert.Equal(t, tt.expectedKeys, tt.limiter.keys)
+		})
+	}
+}
@@ -71,6 +71,7 @@ type GetBalanceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Balances      map[string]string      `protobuf:"bytes,2,rep,name=balances,proto3" json:"balances,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // "paid" => "1000", "free" => "500" (整数値の文字列)
+	Expirations   []*Expiration          `protobuf:"bytes,3,rep,name=expirations,proto3" json:"expirations,omitempty"`                                                                     // 失効予定（有効期限の近い順）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -119,22 +120,93 @@ func (x *GetBalanceResponse) GetBalances() map[string]string {
 	return nil
 }
 
-// GrantRequest 通貨付与リクエスト
-type GrantRequest struct {
+func (x *GetBalanceResponse) GetExpirations() []*Expiration {
+	if x != nil {
+		return x.Expirations
+	}
+	return nil
+}
+
+// Expiration 失効予定の通貨
+type Expiration struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CurrencyType  string                 `protobuf:"bytes,2,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // "paid" or "free"
-	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列（例: "100"）
-	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Requester     string                 `protobuf:"bytes,6,opt,name=requester,proto3" json:"requester,omitempty"` // リクエスト元（サービス名やユーザーIDなど）
+	CurrencyType  string                 `protobuf:"bytes,1,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // "free"
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列
+	ExpiresAt     string                 `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`          // RFC3339形式
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *Expiration) Reset() {
+	*x = Expiration{}
+	mi := &file_currency_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expiration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expiration) ProtoMessage() {}
+
+func (x *Expiration) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expiration.ProtoReflect.Descriptor instead.
+func (*Expiration) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Expiration) GetCurrencyType() string {
+	if x != nil {
+		return x.CurrencyType
+	}
+	return ""
+}
+
+func (x *Expiration) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Expiration) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+// GrantRequest 通貨付与リクエスト
+type GrantRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserId       string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CurrencyType string                 `protobuf:"bytes,2,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // "paid" or "free"
+	Amount       string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列（例: "100"）
+	Reason       string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Metadata     map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Deprecated: Marked as deprecated in currency.proto.
+	Requester      string `protobuf:"bytes,6,opt,name=requester,proto3" json:"requester,omitempty"`                                 // 無視される（リクエスト元には認証した管理APIキーの名前を記録する）
+	ExpiresAt      string `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                // 有効期限（RFC3339形式、任意、無償通貨のみ）
+	IdempotencyKey string `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // 冪等性キー（任意、同じキーでの再送は初回の結果を返す）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
 func (x *GrantRequest) Reset() {
 	*x = GrantRequest{}
-	mi := &file_currency_proto_msgTypes[2]
+	mi := &file_currency_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -146,7 +218,7 @@ func (x *GrantRequest) String() string {
 func (*GrantRequest) ProtoMessage() {}
 
 func (x *GrantRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[2]
+	mi := &file_currency_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -159,7 +231,7 @@ func (x *GrantRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GrantRequest.ProtoReflect.Descriptor instead.
 func (*GrantRequest) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{2}
+	return file_currency_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GrantRequest) GetUserId() string {
@@ -197,6 +269,7 @@ func (x *GrantRequest) GetMetadata() map[string]string {
 	return nil
 }
 
+// Deprecated: Marked as deprecated in currency.proto.
 func (x *GrantRequest) GetRequester() string {
 	if x != nil {
 		return x.Requester
@@ -204,6 +277,20 @@ func (x *GrantRequest) GetRequester() string {
 	return ""
 }
 
+func (x *GrantRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *GrantRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
 // GrantResponse 通貨付与レスポンス
 type GrantResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -216,7 +303,7 @@ type GrantResponse struct {
 
 func (x *GrantResponse) Reset() {
 	*x = GrantResponse{}
-	mi := &file_currency_proto_msgTypes[3]
+	mi := &file_currency_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -228,7 +315,7 @@ func (x *GrantResponse) String() string {
 func (*GrantResponse) ProtoMessage() {}
 
 func (x *GrantResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[3]
+	mi := &file_currency_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -241,7 +328,7 @@ func (x *GrantResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GrantResponse.ProtoReflect.Descriptor instead.
 func (*GrantResponse) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{3}
+	return file_currency_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GrantResponse) GetTransactionId() string {
@@ -267,21 +354,23 @@ func (x *GrantResponse) GetStatus() string {
 
 // ConsumeRequest 通貨消費リクエスト
 type ConsumeRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	CurrencyType  string                 `protobuf:"bytes,2,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // "paid", "free", or "auto"
-	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列（例: "50"）
-	ItemId        string                 `protobuf:"bytes,4,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	UsePriority   bool                   `protobuf:"varint,5,opt,name=use_priority,json=usePriority,proto3" json:"use_priority,omitempty"` // 優先順位制御（無料通貨優先）
-	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Requester     string                 `protobuf:"bytes,7,opt,name=requester,proto3" json:"requester,omitempty"` // リクエスト元（サービス名やユーザーIDなど）
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserId       string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CurrencyType string                 `protobuf:"bytes,2,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // "paid", "free", or "auto"
+	Amount       string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列（例: "50"）
+	ItemId       string                 `protobuf:"bytes,4,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	UsePriority  bool                   `protobuf:"varint,5,opt,name=use_priority,json=usePriority,proto3" json:"use_priority,omitempty"` // 優先順位制御（無料通貨優先）
+	Metadata     map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Deprecated: Marked as deprecated in currency.proto.
+	Requester      string `protobuf:"bytes,7,opt,name=requester,proto3" json:"requester,omitempty"`                                 // 無視される（リクエスト元には認証した管理APIキーの名前を記録する）
+	IdempotencyKey string `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // 冪等性キー（任意、同じキーでの再送は初回の結果を返す）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ConsumeRequest) Reset() {
 	*x = ConsumeRequest{}
-	mi := &file_currency_proto_msgTypes[4]
+	mi := &file_currency_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -293,7 +382,7 @@ func (x *ConsumeRequest) String() string {
 func (*ConsumeRequest) ProtoMessage() {}
 
 func (x *ConsumeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[4]
+	mi := &file_currency_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -306,85 +395,661 @@ func (x *ConsumeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ConsumeRequest.ProtoReflect.Descriptor instead.
 func (*ConsumeRequest) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{4}
+	return file_currency_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConsumeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ConsumeRequest) GetCurrencyType() string {
+	if x != nil {
+		return x.CurrencyType
+	}
+	return ""
+}
+
+func (x *ConsumeRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ConsumeRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *ConsumeRequest) GetUsePriority() bool {
+	if x != nil {
+		return x.UsePriority
+	}
+	return false
+}
+
+func (x *ConsumeRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in currency.proto.
+func (x *ConsumeRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+func (x *ConsumeRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+// ConsumeResponse 通貨消費レスポンス
+type ConsumeResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId      string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ConsumptionDetails []*ConsumptionDetail   `protobuf:"bytes,2,rep,name=consumption_details,json=consumptionDetails,proto3" json:"consumption_details,omitempty"` // 優先順位制御使用時
+	BalanceAfter       string                 `protobuf:"bytes,3,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`                   // 単一通貨タイプ消費時
+	TotalConsumed      string                 `protobuf:"bytes,4,opt,name=total_consumed,json=totalConsumed,proto3" json:"total_consumed,omitempty"`                // 優先順位制御使用時
+	Status             string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ConsumeResponse) Reset() {
+	*x = ConsumeResponse{}
+	mi := &file_currency_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumeResponse) ProtoMessage() {}
+
+func (x *ConsumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumeResponse.ProtoReflect.Descriptor instead.
+func (*ConsumeResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ConsumeResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *ConsumeResponse) GetConsumptionDetails() []*ConsumptionDetail {
+	if x != nil {
+		return x.ConsumptionDetails
+	}
+	return nil
+}
+
+func (x *ConsumeResponse) GetBalanceAfter() string {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return ""
+}
+
+func (x *ConsumeResponse) GetTotalConsumed() string {
+	if x != nil {
+		return x.TotalConsumed
+	}
+	return ""
+}
+
+func (x *ConsumeResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// ConsumptionDetail 消費詳細
+type ConsumptionDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CurrencyType  string                 `protobuf:"bytes,1,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`    // "paid" or "free"
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`                                    // 整数値の文字列
+	BalanceBefore string                 `protobuf:"bytes,3,opt,name=balance_before,json=balanceBefore,proto3" json:"balance_before,omitempty"` // 整数値の文字列
+	BalanceAfter  string                 `protobuf:"bytes,4,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`    // 整数値の文字列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConsumptionDetail) Reset() {
+	*x = ConsumptionDetail{}
+	mi := &file_currency_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumptionDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumptionDetail) ProtoMessage() {}
+
+func (x *ConsumptionDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumptionDetail.ProtoReflect.Descriptor instead.
+func (*ConsumptionDetail) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ConsumptionDetail) GetCurrencyType() string {
+	if x != nil {
+		return x.CurrencyType
+	}
+	return ""
+}
+
+func (x *ConsumptionDetail) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ConsumptionDetail) GetBalanceBefore() string {
+	if x != nil {
+		return x.BalanceBefore
+	}
+	return ""
+}
+
+func (x *ConsumptionDetail) GetBalanceAfter() string {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return ""
+}
+
+// RefundRequest 返金リクエスト
+type RefundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // 元の消費トランザクションID（優先順位制御時はベースID）
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Metadata      map[string]string      `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Deprecated: Marked as deprecated in currency.proto.
+	Requester     string `protobuf:"bytes,4,opt,name=requester,proto3" json:"requester,omitempty"` // 無視される（リクエスト元には認証した管理APIキーの名前を記録する）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundRequest) Reset() {
+	*x = RefundRequest{}
+	mi := &file_currency_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundRequest) ProtoMessage() {}
+
+func (x *RefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundRequest.ProtoReflect.Descriptor instead.
+func (*RefundRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RefundRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in currency.proto.
+func (x *RefundRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+// RefundResponse 返金レスポンス
+type RefundResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId         string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	OriginalTransactionId string                 `protobuf:"bytes,2,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"`
+	UserId                string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RefundDetails         []*RefundDetail        `protobuf:"bytes,4,rep,name=refund_details,json=refundDetails,proto3" json:"refund_details,omitempty"`
+	TotalRefunded         string                 `protobuf:"bytes,5,opt,name=total_refunded,json=totalRefunded,proto3" json:"total_refunded,omitempty"` // 整数値の文字列
+	Status                string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RefundResponse) Reset() {
+	*x = RefundResponse{}
+	mi := &file_currency_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundResponse) ProtoMessage() {}
+
+func (x *RefundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundResponse.ProtoReflect.Descriptor instead.
+func (*RefundResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RefundResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetOriginalTransactionId() string {
+	if x != nil {
+		return x.OriginalTransactionId
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetRefundDetails() []*RefundDetail {
+	if x != nil {
+		return x.RefundDetails
+	}
+	return nil
+}
+
+func (x *RefundResponse) GetTotalRefunded() string {
+	if x != nil {
+		return x.TotalRefunded
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+// RefundDetail 返金詳細
+type RefundDetail struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId         string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	OriginalTransactionId string                 `protobuf:"bytes,2,opt,name=original_transaction_id,json=originalTransactionId,proto3" json:"original_transaction_id,omitempty"`
+	CurrencyType          string                 `protobuf:"bytes,3,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`    // "paid" or "free"
+	Amount                string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`                                    // 整数値の文字列
+	BalanceBefore         string                 `protobuf:"bytes,5,opt,name=balance_before,json=balanceBefore,proto3" json:"balance_before,omitempty"` // 整数値の文字列
+	BalanceAfter          string                 `protobuf:"bytes,6,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`    // 整数値の文字列
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *RefundDetail) Reset() {
+	*x = RefundDetail{}
+	mi := &file_currency_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundDetail) ProtoMessage() {}
+
+func (x *RefundDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundDetail.ProtoReflect.Descriptor instead.
+func (*RefundDetail) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RefundDetail) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *RefundDetail) GetOriginalTransactionId() string {
+	if x != nil {
+		return x.OriginalTransactionId
+	}
+	return ""
+}
+
+func (x *RefundDetail) GetCurrencyType() string {
+	if x != nil {
+		return x.CurrencyType
+	}
+	return ""
+}
+
+func (x *RefundDetail) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *RefundDetail) GetBalanceBefore() string {
+	if x != nil {
+		return x.BalanceBefore
+	}
+	return ""
+}
+
+func (x *RefundDetail) GetBalanceAfter() string {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return ""
+}
+
+// CompensateRequest 補填（調整）リクエスト
+type CompensateRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	UserId       string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CurrencyType string                 `protobuf:"bytes,2,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // "paid" or "free"
+	Amount       string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 符号付き整数値の文字列（正: 補填、負: 回収）
+	Reason       string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                                 // 必須
+	Metadata     map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Deprecated: Marked as deprecated in currency.proto.
+	Requester     string `protobuf:"bytes,6,opt,name=requester,proto3" json:"requester,omitempty"` // 無視される（リクエスト元には認証した管理APIキーの名前を記録する）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompensateRequest) Reset() {
+	*x = CompensateRequest{}
+	mi := &file_currency_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompensateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompensateRequest) ProtoMessage() {}
+
+func (x *CompensateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompensateRequest.ProtoReflect.Descriptor instead.
+func (*CompensateRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CompensateRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CompensateRequest) GetCurrencyType() string {
+	if x != nil {
+		return x.CurrencyType
+	}
+	return ""
+}
+
+func (x *CompensateRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *CompensateRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CompensateRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Deprecated: Marked as deprecated in currency.proto.
+func (x *CompensateRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+// CompensateResponse 補填（調整）レスポンス
+type CompensateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CurrencyType  string                 `protobuf:"bytes,3,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`
+	Amount        string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`                                    // 符号付き整数値の文字列
+	BalanceBefore string                 `protobuf:"bytes,5,opt,name=balance_before,json=balanceBefore,proto3" json:"balance_before,omitempty"` // 整数値の文字列
+	BalanceAfter  string                 `protobuf:"bytes,6,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`    // 整数値の文字列（マイナスの場合あり）
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompensateResponse) Reset() {
+	*x = CompensateResponse{}
+	mi := &file_currency_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompensateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompensateResponse) ProtoMessage() {}
+
+func (x *CompensateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompensateResponse.ProtoReflect.Descriptor instead.
+func (*CompensateResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ConsumeRequest) GetUserId() string {
+func (x *CompensateResponse) GetTransactionId() string {
 	if x != nil {
-		return x.UserId
+		return x.TransactionId
 	}
 	return ""
 }
 
-func (x *ConsumeRequest) GetCurrencyType() string {
+func (x *CompensateResponse) GetUserId() string {
 	if x != nil {
-		return x.CurrencyType
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ConsumeRequest) GetAmount() string {
+func (x *CompensateResponse) GetCurrencyType() string {
 	if x != nil {
-		return x.Amount
+		return x.CurrencyType
 	}
 	return ""
 }
 
-func (x *ConsumeRequest) GetItemId() string {
+func (x *CompensateResponse) GetAmount() string {
 	if x != nil {
-		return x.ItemId
+		return x.Amount
 	}
 	return ""
 }
 
-func (x *ConsumeRequest) GetUsePriority() bool {
+func (x *CompensateResponse) GetBalanceBefore() string {
 	if x != nil {
-		return x.UsePriority
+		return x.BalanceBefore
 	}
-	return false
+	return ""
 }
 
-func (x *ConsumeRequest) GetMetadata() map[string]string {
+func (x *CompensateResponse) GetBalanceAfter() string {
 	if x != nil {
-		return x.Metadata
+		return x.BalanceAfter
 	}
-	return nil
+	return ""
 }
 
-func (x *ConsumeRequest) GetRequester() string {
+func (x *CompensateResponse) GetStatus() string {
 	if x != nil {
-		return x.Requester
+		return x.Status
 	}
 	return ""
 }
 
-// ConsumeResponse 通貨消費レスポンス
-type ConsumeResponse struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	TransactionId      string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	ConsumptionDetails []*ConsumptionDetail   `protobuf:"bytes,2,rep,name=consumption_details,json=consumptionDetails,proto3" json:"consumption_details,omitempty"` // 優先順位制御使用時
-	BalanceAfter       string                 `protobuf:"bytes,3,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`                   // 単一通貨タイプ消費時
-	TotalConsumed      string                 `protobuf:"bytes,4,opt,name=total_consumed,json=totalConsumed,proto3" json:"total_consumed,omitempty"`                // 優先順位制御使用時
-	Status             string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+// TransferRequest ユーザー間の通貨譲渡リクエスト
+type TransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromUserId    string                 `protobuf:"bytes,1,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`     // 送信者
+	ToUserId      string                 `protobuf:"bytes,2,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`           // 受信者
+	CurrencyType  string                 `protobuf:"bytes,3,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"` // 譲渡が許可された通貨タイプ（通常は"free"）
+	Amount        string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列（例: "100"）
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`                               // 受信者へのメッセージ（任意）
+	Metadata      map[string]string      `protobuf:"bytes,6,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ConsumeResponse) Reset() {
-	*x = ConsumeResponse{}
-	mi := &file_currency_proto_msgTypes[5]
+func (x *TransferRequest) Reset() {
+	*x = TransferRequest{}
+	mi := &file_currency_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConsumeResponse) String() string {
+func (x *TransferRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConsumeResponse) ProtoMessage() {}
+func (*TransferRequest) ProtoMessage() {}
 
-func (x *ConsumeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[5]
+func (x *TransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -395,72 +1060,84 @@ func (x *ConsumeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConsumeResponse.ProtoReflect.Descriptor instead.
-func (*ConsumeResponse) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
+func (*TransferRequest) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ConsumeResponse) GetTransactionId() string {
+func (x *TransferRequest) GetFromUserId() string {
 	if x != nil {
-		return x.TransactionId
+		return x.FromUserId
 	}
 	return ""
 }
 
-func (x *ConsumeResponse) GetConsumptionDetails() []*ConsumptionDetail {
+func (x *TransferRequest) GetToUserId() string {
 	if x != nil {
-		return x.ConsumptionDetails
+		return x.ToUserId
 	}
-	return nil
+	return ""
 }
 
-func (x *ConsumeResponse) GetBalanceAfter() string {
+func (x *TransferRequest) GetCurrencyType() string {
 	if x != nil {
-		return x.BalanceAfter
+		return x.CurrencyType
 	}
 	return ""
 }
 
-func (x *ConsumeResponse) GetTotalConsumed() string {
+func (x *TransferRequest) GetAmount() string {
 	if x != nil {
-		return x.TotalConsumed
+		return x.Amount
 	}
 	return ""
 }
 
-func (x *ConsumeResponse) GetStatus() string {
+func (x *TransferRequest) GetMessage() string {
 	if x != nil {
-		return x.Status
+		return x.Message
 	}
 	return ""
 }
 
-// ConsumptionDetail 消費詳細
-type ConsumptionDetail struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CurrencyType  string                 `protobuf:"bytes,1,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`    // "paid" or "free"
-	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"`                                    // 整数値の文字列
-	BalanceBefore string                 `protobuf:"bytes,3,opt,name=balance_before,json=balanceBefore,proto3" json:"balance_before,omitempty"` // 整数値の文字列
-	BalanceAfter  string                 `protobuf:"bytes,4,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`    // 整数値の文字列
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *TransferRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
 }
 
-func (x *ConsumptionDetail) Reset() {
-	*x = ConsumptionDetail{}
-	mi := &file_currency_proto_msgTypes[6]
+// TransferResponse ユーザー間の通貨譲渡レスポンス
+type TransferResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	TransferId            string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	FromUserId            string                 `protobuf:"bytes,2,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`
+	ToUserId              string                 `protobuf:"bytes,3,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	CurrencyType          string                 `protobuf:"bytes,4,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`
+	Amount                string                 `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`                                                              // 整数値の文字列
+	SenderTransactionId   string                 `protobuf:"bytes,6,opt,name=sender_transaction_id,json=senderTransactionId,proto3" json:"sender_transaction_id,omitempty"`       // 送信側のトランザクションID（transfer_id + "_out"）
+	ReceiverTransactionId string                 `protobuf:"bytes,7,opt,name=receiver_transaction_id,json=receiverTransactionId,proto3" json:"receiver_transaction_id,omitempty"` // 受信側のトランザクションID（transfer_id + "_in"）
+	BalanceAfter          string                 `protobuf:"bytes,8,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`                              // 送信者の譲渡後残高（整数値の文字列）
+	Status                string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *TransferResponse) Reset() {
+	*x = TransferResponse{}
+	mi := &file_currency_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConsumptionDetail) String() string {
+func (x *TransferResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConsumptionDetail) ProtoMessage() {}
+func (*TransferResponse) ProtoMessage() {}
 
-func (x *ConsumptionDetail) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[6]
+func (x *TransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -471,39 +1148,74 @@ func (x *ConsumptionDetail) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConsumptionDetail.ProtoReflect.Descriptor instead.
-func (*ConsumptionDetail) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
+func (*TransferResponse) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ConsumptionDetail) GetCurrencyType() string {
+func (x *TransferResponse) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *TransferResponse) GetFromUserId() string {
+	if x != nil {
+		return x.FromUserId
+	}
+	return ""
+}
+
+func (x *TransferResponse) GetToUserId() string {
+	if x != nil {
+		return x.ToUserId
+	}
+	return ""
+}
+
+func (x *TransferResponse) GetCurrencyType() string {
 	if x != nil {
 		return x.CurrencyType
 	}
 	return ""
 }
 
-func (x *ConsumptionDetail) GetAmount() string {
+func (x *TransferResponse) GetAmount() string {
 	if x != nil {
 		return x.Amount
 	}
 	return ""
 }
 
-func (x *ConsumptionDetail) GetBalanceBefore() string {
+func (x *TransferResponse) GetSenderTransactionId() string {
 	if x != nil {
-		return x.BalanceBefore
+		return x.SenderTransactionId
 	}
 	return ""
 }
 
-func (x *ConsumptionDetail) GetBalanceAfter() string {
+func (x *TransferResponse) GetReceiverTransactionId() string {
+	if x != nil {
+		return x.ReceiverTransactionId
+	}
+	return ""
+}
+
+func (x *TransferResponse) GetBalanceAfter() string {
 	if x != nil {
 		return x.BalanceAfter
 	}
 	return ""
 }
 
+func (x *TransferResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 // ProcessPaymentRequest 決済処理リクエスト
 type ProcessPaymentRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
@@ -519,7 +1231,7 @@ type ProcessPaymentRequest struct {
 
 func (x *ProcessPaymentRequest) Reset() {
 	*x = ProcessPaymentRequest{}
-	mi := &file_currency_proto_msgTypes[7]
+	mi := &file_currency_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -531,7 +1243,7 @@ func (x *ProcessPaymentRequest) String() string {
 func (*ProcessPaymentRequest) ProtoMessage() {}
 
 func (x *ProcessPaymentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[7]
+	mi := &file_currency_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -544,7 +1256,7 @@ func (x *ProcessPaymentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProcessPaymentRequest.ProtoReflect.Descriptor instead.
 func (*ProcessPaymentRequest) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{7}
+	return file_currency_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *ProcessPaymentRequest) GetPaymentRequestId() string {
@@ -603,7 +1315,7 @@ type ProcessPaymentResponse struct {
 
 func (x *ProcessPaymentResponse) Reset() {
 	*x = ProcessPaymentResponse{}
-	mi := &file_currency_proto_msgTypes[8]
+	mi := &file_currency_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -615,7 +1327,7 @@ func (x *ProcessPaymentResponse) String() string {
 func (*ProcessPaymentResponse) ProtoMessage() {}
 
 func (x *ProcessPaymentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[8]
+	mi := &file_currency_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,7 +1340,7 @@ func (x *ProcessPaymentResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProcessPaymentResponse.ProtoReflect.Descriptor instead.
 func (*ProcessPaymentResponse) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{8}
+	return file_currency_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ProcessPaymentResponse) GetTransactionId() string {
@@ -677,7 +1389,7 @@ type RedeemCodeRequest struct {
 
 func (x *RedeemCodeRequest) Reset() {
 	*x = RedeemCodeRequest{}
-	mi := &file_currency_proto_msgTypes[9]
+	mi := &file_currency_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -689,7 +1401,7 @@ func (x *RedeemCodeRequest) String() string {
 func (*RedeemCodeRequest) ProtoMessage() {}
 
 func (x *RedeemCodeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[9]
+	mi := &file_currency_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -702,7 +1414,7 @@ func (x *RedeemCodeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RedeemCodeRequest.ProtoReflect.Descriptor instead.
 func (*RedeemCodeRequest) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{9}
+	return file_currency_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *RedeemCodeRequest) GetCode() string {
@@ -729,13 +1441,14 @@ type RedeemCodeResponse struct {
 	Amount        string                 `protobuf:"bytes,5,opt,name=amount,proto3" json:"amount,omitempty"`
 	BalanceAfter  string                 `protobuf:"bytes,6,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"`
 	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Rewards       []*RedeemedReward      `protobuf:"bytes,8,rep,name=rewards,proto3" json:"rewards,omitempty"` // 付与したすべての報酬（先頭はtransaction_idなどと同じ）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RedeemCodeResponse) Reset() {
 	*x = RedeemCodeResponse{}
-	mi := &file_currency_proto_msgTypes[10]
+	mi := &file_currency_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -747,7 +1460,7 @@ func (x *RedeemCodeResponse) String() string {
 func (*RedeemCodeResponse) ProtoMessage() {}
 
 func (x *RedeemCodeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[10]
+	mi := &file_currency_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -760,7 +1473,7 @@ func (x *RedeemCodeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RedeemCodeResponse.ProtoReflect.Descriptor instead.
 func (*RedeemCodeResponse) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{10}
+	return file_currency_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *RedeemCodeResponse) GetRedemptionId() string {
@@ -812,6 +1525,82 @@ func (x *RedeemCodeResponse) GetStatus() string {
 	return ""
 }
 
+func (x *RedeemCodeResponse) GetRewards() []*RedeemedReward {
+	if x != nil {
+		return x.Rewards
+	}
+	return nil
+}
+
+// RedeemedReward 引き換えで付与した報酬
+type RedeemedReward struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId string                 `protobuf:"bytes,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	CurrencyType  string                 `protobuf:"bytes,2,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`
+	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                                 // 整数値の文字列
+	BalanceAfter  string                 `protobuf:"bytes,4,opt,name=balance_after,json=balanceAfter,proto3" json:"balance_after,omitempty"` // 整数値の文字列
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeemedReward) Reset() {
+	*x = RedeemedReward{}
+	mi := &file_currency_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeemedReward) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemedReward) ProtoMessage() {}
+
+func (x *RedeemedReward) ProtoReflect() protoreflect.Message {
+	mi := &file_currency_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemedReward.ProtoReflect.Descriptor instead.
+func (*RedeemedReward) Descriptor() ([]byte, []int) {
+	return file_currency_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RedeemedReward) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *RedeemedReward) GetCurrencyType() string {
+	if x != nil {
+		return x.CurrencyType
+	}
+	return ""
+}
+
+func (x *RedeemedReward) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *RedeemedReward) GetBalanceAfter() string {
+	if x != nil {
+		return x.BalanceAfter
+	}
+	return ""
+}
+
 // GetTransactionHistoryRequest トランザクション履歴取得リクエスト
 type GetTransactionHistoryRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -820,13 +1609,18 @@ type GetTransactionHistoryRequest struct {
 	Offset          int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
 	CurrencyType    string                 `protobuf:"bytes,4,opt,name=currency_type,json=currencyType,proto3" json:"currency_type,omitempty"`          // optional
 	TransactionType string                 `protobuf:"bytes,5,opt,name=transaction_type,json=transactionType,proto3" json:"transaction_type,omitempty"` // optional
+	Status          string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`                                          // optional
+	Requester       string                 `protobuf:"bytes,7,opt,name=requester,proto3" json:"requester,omitempty"`                                    // optional
+	From            string                 `protobuf:"bytes,8,opt,name=from,proto3" json:"from,omitempty"`                                              // optional, RFC3339 (inclusive)
+	To              string                 `protobuf:"bytes,9,opt,name=to,proto3" json:"to,omitempty"`                                                  // optional, RFC3339 (exclusive)
+	Cursor          string                 `protobuf:"bytes,10,opt,name=cursor,proto3" json:"cursor,omitempty"`                                         // optional, next_cursor of the previous page (takes precedence over offset)
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *GetTransactionHistoryRequest) Reset() {
 	*x = GetTransactionHistoryRequest{}
-	mi := &file_currency_proto_msgTypes[11]
+	mi := &file_currency_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -838,7 +1632,7 @@ func (x *GetTransactionHistoryRequest) String() string {
 func (*GetTransactionHistoryRequest) ProtoMessage() {}
 
 func (x *GetTransactionHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[11]
+	mi := &file_currency_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -851,7 +1645,7 @@ func (x *GetTransactionHistoryRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionHistoryRequest.ProtoReflect.Descriptor instead.
 func (*GetTransactionHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{11}
+	return file_currency_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *GetTransactionHistoryRequest) GetUserId() string {
@@ -889,6 +1683,41 @@ func (x *GetTransactionHistoryRequest) GetTransactionType() string {
 	return ""
 }
 
+func (x *GetTransactionHistoryRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetTransactionHistoryRequest) GetRequester() string {
+	if x != nil {
+		return x.Requester
+	}
+	return ""
+}
+
+func (x *GetTransactionHistoryRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetTransactionHistoryRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetTransactionHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
 // GetTransactionHistoryResponse トランザクション履歴取得レスポンス
 type GetTransactionHistoryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -896,13 +1725,14 @@ type GetTransactionHistoryResponse struct {
 	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
 	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // empty on the last page
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetTransactionHistoryResponse) Reset() {
 	*x = GetTransactionHistoryResponse{}
-	mi := &file_currency_proto_msgTypes[12]
+	mi := &file_currency_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -914,7 +1744,7 @@ func (x *GetTransactionHistoryResponse) String() string {
 func (*GetTransactionHistoryResponse) ProtoMessage() {}
 
 func (x *GetTransactionHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[12]
+	mi := &file_currency_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -927,7 +1757,7 @@ func (x *GetTransactionHistoryResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionHistoryResponse.ProtoReflect.Descriptor instead.
 func (*GetTransactionHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{12}
+	return file_currency_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetTransactionHistoryResponse) GetTransactions() []*Transaction {
@@ -958,6 +1788,13 @@ func (x *GetTransactionHistoryResponse) GetOffset() int32 {
 	return 0
 }
 
+func (x *GetTransactionHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 // Transaction トランザクション
 type Transaction struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
@@ -975,7 +1812,7 @@ type Transaction struct {
 
 func (x *Transaction) Reset() {
 	*x = Transaction{}
-	mi := &file_currency_proto_msgTypes[13]
+	mi := &file_currency_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -987,7 +1824,7 @@ func (x *Transaction) String() string {
 func (*Transaction) ProtoMessage() {}
 
 func (x *Transaction) ProtoReflect() protoreflect.Message {
-	mi := &file_currency_proto_msgTypes[13]
+	mi := &file_currency_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1000,7 +1837,7 @@ func (x *Transaction) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return file_currency_proto_rawDescGZIP(), []int{13}
+	return file_currency_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Transaction) GetTransactionId() string {
@@ -1065,35 +1902,46 @@ const file_currency_proto_rawDesc = "" +
 	"\n" +
 	"\x0ecurrency.proto\x12\bcurrency\",\n" +
 	"\x11GetBalanceRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xb2\x01\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xea\x01\n" +
 	"\x12GetBalanceResponse\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12F\n" +
-	"\bbalances\x18\x02 \x03(\v2*.currency.GetBalanceResponse.BalancesEntryR\bbalances\x1a;\n" +
+	"\bbalances\x18\x02 \x03(\v2*.currency.GetBalanceResponse.BalancesEntryR\bbalances\x126\n" +
+	"\vexpirations\x18\x03 \x03(\v2\x14.currency.ExpirationR\vexpirations\x1a;\n" +
 	"\rBalancesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x99\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"h\n" +
+	"\n" +
+	"Expiration\x12#\n" +
+	"\rcurrency_type\x18\x01 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\"\xe5\x02\n" +
 	"\fGrantRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
 	"\rcurrency_type\x18\x02 \x01(\tR\fcurrencyType\x12\x16\n" +
 	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x16\n" +
 	"\x06reason\x18\x04 \x01(\tR\x06reason\x12@\n" +
-	"\bmetadata\x18\x05 \x03(\v2$.currency.GrantRequest.MetadataEntryR\bmetadata\x12\x1c\n" +
-	"\trequester\x18\x06 \x01(\tR\trequester\x1a;\n" +
+	"\bmetadata\x18\x05 \x03(\v2$.currency.GrantRequest.MetadataEntryR\bmetadata\x12 \n" +
+	"\trequester\x18\x06 \x01(\tB\x02\x18\x01R\trequester\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\tR\texpiresAt\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"s\n" +
 	"\rGrantResponse\x12%\n" +
 	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12#\n" +
 	"\rbalance_after\x18\x02 \x01(\tR\fbalanceAfter\x12\x16\n" +
-	"\x06status\x18\x03 \x01(\tR\x06status\"\xc1\x02\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\"\xee\x02\n" +
 	"\x0eConsumeRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
 	"\rcurrency_type\x18\x02 \x01(\tR\fcurrencyType\x12\x16\n" +
 	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x17\n" +
 	"\aitem_id\x18\x04 \x01(\tR\x06itemId\x12!\n" +
 	"\fuse_priority\x18\x05 \x01(\bR\vusePriority\x12B\n" +
-	"\bmetadata\x18\x06 \x03(\v2&.currency.ConsumeRequest.MetadataEntryR\bmetadata\x12\x1c\n" +
-	"\trequester\x18\a \x01(\tR\trequester\x1a;\n" +
+	"\bmetadata\x18\x06 \x03(\v2&.currency.ConsumeRequest.MetadataEntryR\bmetadata\x12 \n" +
+	"\trequester\x18\a \x01(\tB\x02\x18\x01R\trequester\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xea\x01\n" +
@@ -1107,7 +1955,72 @@ const file_currency_proto_rawDesc = "" +
 	"\rcurrency_type\x18\x01 \x01(\tR\fcurrencyType\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\tR\x06amount\x12%\n" +
 	"\x0ebalance_before\x18\x03 \x01(\tR\rbalanceBefore\x12#\n" +
-	"\rbalance_after\x18\x04 \x01(\tR\fbalanceAfter\"\xb7\x02\n" +
+	"\rbalance_after\x18\x04 \x01(\tR\fbalanceAfter\"\xf0\x01\n" +
+	"\rRefundRequest\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12A\n" +
+	"\bmetadata\x18\x03 \x03(\v2%.currency.RefundRequest.MetadataEntryR\bmetadata\x12 \n" +
+	"\trequester\x18\x04 \x01(\tB\x02\x18\x01R\trequester\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x86\x02\n" +
+	"\x0eRefundResponse\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x126\n" +
+	"\x17original_transaction_id\x18\x02 \x01(\tR\x15originalTransactionId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12=\n" +
+	"\x0erefund_details\x18\x04 \x03(\v2\x16.currency.RefundDetailR\rrefundDetails\x12%\n" +
+	"\x0etotal_refunded\x18\x05 \x01(\tR\rtotalRefunded\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\"\xf6\x01\n" +
+	"\fRefundDetail\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x126\n" +
+	"\x17original_transaction_id\x18\x02 \x01(\tR\x15originalTransactionId\x12#\n" +
+	"\rcurrency_type\x18\x03 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12%\n" +
+	"\x0ebalance_before\x18\x05 \x01(\tR\rbalanceBefore\x12#\n" +
+	"\rbalance_after\x18\x06 \x01(\tR\fbalanceAfter\"\xa7\x02\n" +
+	"\x11CompensateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
+	"\rcurrency_type\x18\x02 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12E\n" +
+	"\bmetadata\x18\x05 \x03(\v2).currency.CompensateRequest.MetadataEntryR\bmetadata\x12 \n" +
+	"\trequester\x18\x06 \x01(\tB\x02\x18\x01R\trequester\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf5\x01\n" +
+	"\x12CompensateResponse\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12#\n" +
+	"\rcurrency_type\x18\x03 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12%\n" +
+	"\x0ebalance_before\x18\x05 \x01(\tR\rbalanceBefore\x12#\n" +
+	"\rbalance_after\x18\x06 \x01(\tR\fbalanceAfter\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\"\xaa\x02\n" +
+	"\x0fTransferRequest\x12 \n" +
+	"\ffrom_user_id\x18\x01 \x01(\tR\n" +
+	"fromUserId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x02 \x01(\tR\btoUserId\x12#\n" +
+	"\rcurrency_type\x18\x03 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12C\n" +
+	"\bmetadata\x18\x06 \x03(\v2'.currency.TransferRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd9\x02\n" +
+	"\x10TransferResponse\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\x12 \n" +
+	"\ffrom_user_id\x18\x02 \x01(\tR\n" +
+	"fromUserId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x03 \x01(\tR\btoUserId\x12#\n" +
+	"\rcurrency_type\x18\x04 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\tR\x06amount\x122\n" +
+	"\x15sender_transaction_id\x18\x06 \x01(\tR\x13senderTransactionId\x126\n" +
+	"\x17receiver_transaction_id\x18\a \x01(\tR\x15receiverTransactionId\x12#\n" +
+	"\rbalance_after\x18\b \x01(\tR\fbalanceAfter\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\"\xb7\x02\n" +
 	"\x15ProcessPaymentRequest\x12,\n" +
 	"\x12payment_request_id\x18\x01 \x01(\tR\x10paymentRequestId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
@@ -1127,7 +2040,7 @@ const file_currency_proto_rawDesc = "" +
 	"\x06status\x18\x05 \x01(\tR\x06status\"@\n" +
 	"\x11RedeemCodeRequest\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xee\x01\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xa2\x02\n" +
 	"\x12RedeemCodeResponse\x12#\n" +
 	"\rredemption_id\x18\x01 \x01(\tR\fredemptionId\x12%\n" +
 	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12\x12\n" +
@@ -1135,18 +2048,32 @@ const file_currency_proto_rawDesc = "" +
 	"\rcurrency_type\x18\x04 \x01(\tR\fcurrencyType\x12\x16\n" +
 	"\x06amount\x18\x05 \x01(\tR\x06amount\x12#\n" +
 	"\rbalance_after\x18\x06 \x01(\tR\fbalanceAfter\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\"\xb5\x01\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x122\n" +
+	"\arewards\x18\b \x03(\v2\x18.currency.RedeemedRewardR\arewards\"\x99\x01\n" +
+	"\x0eRedeemedReward\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12#\n" +
+	"\rcurrency_type\x18\x02 \x01(\tR\fcurrencyType\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12#\n" +
+	"\rbalance_after\x18\x04 \x01(\tR\fbalanceAfter\"\xa7\x02\n" +
 	"\x1cGetTransactionHistoryRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12#\n" +
 	"\rcurrency_type\x18\x04 \x01(\tR\fcurrencyType\x12)\n" +
-	"\x10transaction_type\x18\x05 \x01(\tR\x0ftransactionType\"\x9e\x01\n" +
+	"\x10transaction_type\x18\x05 \x01(\tR\x0ftransactionType\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1c\n" +
+	"\trequester\x18\a \x01(\tR\trequester\x12\x12\n" +
+	"\x04from\x18\b \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\t \x01(\tR\x02to\x12\x16\n" +
+	"\x06cursor\x18\n" +
+	" \x01(\tR\x06cursor\"\xbf\x01\n" +
 	"\x1dGetTransactionHistoryResponse\x129\n" +
 	"\ftransactions\x18\x01 \x03(\v2\x15.currency.TransactionR\ftransactions\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
 	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\x9f\x02\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\"\x9f\x02\n" +
 	"\vTransaction\x12%\n" +
 	"\x0etransaction_id\x18\x01 \x01(\tR\rtransactionId\x12)\n" +
 	"\x10transaction_type\x18\x02 \x01(\tR\x0ftransactionType\x12#\n" +
@@ -1156,12 +2083,16 @@ const file_currency_proto_rawDesc = "" +
 	"\rbalance_after\x18\x06 \x01(\tR\fbalanceAfter\x12\x16\n" +
 	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
-	"created_at\x18\b \x01(\tR\tcreatedAt2\xdc\x03\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt2\xa5\x05\n" +
 	"\x0fCurrencyService\x12G\n" +
 	"\n" +
 	"GetBalance\x12\x1b.currency.GetBalanceRequest\x1a\x1c.currency.GetBalanceResponse\x128\n" +
 	"\x05Grant\x12\x16.currency.GrantRequest\x1a\x17.currency.GrantResponse\x12>\n" +
-	"\aConsume\x12\x18.currency.ConsumeRequest\x1a\x19.currency.ConsumeResponse\x12S\n" +
+	"\aConsume\x12\x18.currency.ConsumeRequest\x1a\x19.currency.ConsumeResponse\x12;\n" +
+	"\x06Refund\x12\x17.currency.RefundRequest\x1a\x18.currency.RefundResponse\x12G\n" +
+	"\n" +
+	"Compensate\x12\x1b.currency.CompensateRequest\x1a\x1c.currency.CompensateResponse\x12A\n" +
+	"\bTransfer\x12\x19.currency.TransferRequest\x1a\x1a.currency.TransferResponse\x12S\n" +
 	"\x0eProcessPayment\x12\x1f.currency.ProcessPaymentRequest\x1a .currency.ProcessPaymentResponse\x12G\n" +
 	"\n" +
 	"RedeemCode\x12\x1b.currency.RedeemCodeRequest\x1a\x1c.currency.RedeemCodeResponse\x12h\n" +
@@ -1179,52 +2110,76 @@ func file_currency_proto_rawDescGZIP() []byte {
 	return file_currency_proto_rawDescData
 }
 
-var file_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_currency_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_currency_proto_goTypes = []any{
 	(*GetBalanceRequest)(nil),             // 0: currency.GetBalanceRequest
 	(*GetBalanceResponse)(nil),            // 1: currency.GetBalanceResponse
-	(*GrantRequest)(nil),                  // 2: currency.GrantRequest
-	(*GrantResponse)(nil),                 // 3: currency.GrantResponse
-	(*ConsumeRequest)(nil),                // 4: currency.ConsumeRequest
-	(*ConsumeResponse)(nil),               // 5: currency.ConsumeResponse
-	(*ConsumptionDetail)(nil),             // 6: currency.ConsumptionDetail
-	(*ProcessPaymentRequest)(nil),         // 7: currency.ProcessPaymentRequest
-	(*ProcessPaymentResponse)(nil),        // 8: currency.ProcessPaymentResponse
-	(*RedeemCodeRequest)(nil),             // 9: currency.RedeemCodeRequest
-	(*RedeemCodeResponse)(nil),            // 10: currency.RedeemCodeResponse
-	(*GetTransactionHistoryRequest)(nil),  // 11: currency.GetTransactionHistoryRequest
-	(*GetTransactionHistoryResponse)(nil), // 12: currency.GetTransactionHistoryResponse
-	(*Transaction)(nil),                   // 13: currency.Transaction
-	nil,                                   // 14: currency.GetBalanceResponse.BalancesEntry
-	nil,                                   // 15: currency.GrantRequest.MetadataEntry
-	nil,                                   // 16: currency.ConsumeRequest.MetadataEntry
-	nil,                                   // 17: currency.ProcessPaymentRequest.DetailsEntry
+	(*Expiration)(nil),                    // 2: currency.Expiration
+	(*GrantRequest)(nil),                  // 3: currency.GrantRequest
+	(*GrantResponse)(nil),                 // 4: currency.GrantResponse
+	(*ConsumeRequest)(nil),                // 5: currency.ConsumeRequest
+	(*ConsumeResponse)(nil),               // 6: currency.ConsumeResponse
+	(*ConsumptionDetail)(nil),             // 7: currency.ConsumptionDetail
+	(*RefundRequest)(nil),                 // 8: currency.RefundRequest
+	(*RefundResponse)(nil),                // 9: currency.RefundResponse
+	(*RefundDetail)(nil),                  // 10: currency.RefundDetail
+	(*CompensateRequest)(nil),             // 11: currency.CompensateRequest
+	(*CompensateResponse)(nil),            // 12: currency.CompensateResponse
+	(*TransferRequest)(nil),               // 13: currency.TransferRequest
+	(*TransferResponse)(nil),              // 14: currency.TransferResponse
+	(*ProcessPaymentRequest)(nil),         // 15: currency.ProcessPaymentRequest
+	(*ProcessPaymentResponse)(nil),        // 16: currency.ProcessPaymentResponse
+	(*RedeemCodeRequest)(nil),             // 17: currency.RedeemCodeRequest
+	(*RedeemCodeResponse)(nil),            // 18: currency.RedeemCodeResponse
+	(*RedeemedReward)(nil),                // 19: currency.RedeemedReward
+	(*GetTransactionHistoryRequest)(nil),  // 20: currency.GetTransactionHistoryRequest
+	(*GetTransactionHistoryResponse)(nil), // 21: currency.GetTransactionHistoryResponse
+	(*Transaction)(nil),                   // 22: currency.Transaction
+	nil,                                   // 23: currency.GetBalanceResponse.BalancesEntry
+	nil,                                   // 24: currency.GrantRequest.MetadataEntry
+	nil,                                   // 25: currency.ConsumeRequest.MetadataEntry
+	nil,                                   // 26: currency.RefundRequest.MetadataEntry
+	nil,                                   // 27: currency.CompensateRequest.MetadataEntry
+	nil,                                   // 28: currency.TransferRequest.MetadataEntry
+	nil,                                   // 29: currency.ProcessPaymentRequest.DetailsEntry
 }
 var file_currency_proto_depIdxs = []int32{
-	14, // 0: currency.GetBalanceResponse.balances:type_name -> currency.GetBalanceResponse.BalancesEntry
-	15, // 1: currency.GrantRequest.metadata:type_name -> currency.GrantRequest.MetadataEntry
-	16, // 2: currency.ConsumeRequest.metadata:type_name -> currency.ConsumeRequest.MetadataEntry
-	6,  // 3: currency.ConsumeResponse.consumption_details:type_name -> currency.ConsumptionDetail
-	17, // 4: currency.ProcessPaymentRequest.details:type_name -> currency.ProcessPaymentRequest.DetailsEntry
-	6,  // 5: currency.ProcessPaymentResponse.consumption_details:type_name -> currency.ConsumptionDetail
-	13, // 6: currency.GetTransactionHistoryResponse.transactions:type_name -> currency.Transaction
-	0,  // 7: currency.CurrencyService.GetBalance:input_type -> currency.GetBalanceRequest
-	2,  // 8: currency.CurrencyService.Grant:input_type -> currency.GrantRequest
-	4,  // 9: currency.CurrencyService.Consume:input_type -> currency.ConsumeRequest
-	7,  // 10: currency.CurrencyService.ProcessPayment:input_type -> currency.ProcessPaymentRequest
-	9,  // 11: currency.CurrencyService.RedeemCode:input_type -> currency.RedeemCodeRequest
-	11, // 12: currency.CurrencyService.GetTransactionHistory:input_type -> currency.GetTransactionHistoryRequest
-	1,  // 13: currency.CurrencyService.GetBalance:output_type -> currency.GetBalanceResponse
-	3,  // 14: currency.CurrencyService.Grant:output_type -> currency.GrantResponse
-	5,  // 15: currency.CurrencyService.Consume:output_type -> currency.ConsumeResponse
-	8,  // 16: currency.CurrencyService.ProcessPayment:output_type -> currency.ProcessPaymentResponse
-	10, // 17: currency.CurrencyService.RedeemCode:output_type -> currency.RedeemCodeResponse
-	12, // 18: currency.CurrencyService.GetTransactionHistory:output_type -> currency.GetTransactionHistoryResponse
-	13, // [13:19] is the sub-list for method output_type
-	7,  // [7:13] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	23, // 0: currency.GetBalanceResponse.balances:type_name -> currency.GetBalanceResponse.BalancesEntry
+	2,  // 1: currency.GetBalanceResponse.expirations:type_name -> currency.Expiration
+	24, // 2: currency.GrantRequest.metadata:type_name -> currency.GrantRequest.MetadataEntry
+	25, // 3: currency.ConsumeRequest.metadata:type_name -> currency.ConsumeRequest.MetadataEntry
+	7,  // 4: currency.ConsumeResponse.consumption_details:type_name -> currency.ConsumptionDetail
+	26, // 5: currency.RefundRequest.metadata:type_name -> currency.RefundRequest.MetadataEntry
+	10, // 6: currency.RefundResponse.refund_details:type_name -> currency.RefundDetail
+	27, // 7: currency.CompensateRequest.metadata:type_name -> currency.CompensateRequest.MetadataEntry
+	28, // 8: currency.TransferRequest.metadata:type_name -> currency.TransferRequest.MetadataEntry
+	29, // 9: currency.ProcessPaymentRequest.details:type_name -> currency.ProcessPaymentRequest.DetailsEntry
+	7,  // 10: currency.ProcessPaymentResponse.consumption_details:type_name -> currency.ConsumptionDetail
+	19, // 11: currency.RedeemCodeResponse.rewards:type_name -> currency.RedeemedReward
+	22, // 12: currency.GetTransactionHistoryResponse.transactions:type_name -> currency.Transaction
+	0,  // 13: currency.CurrencyService.GetBalance:input_type -> currency.GetBalanceRequest
+	3,  // 14: currency.CurrencyService.Grant:input_type -> currency.GrantRequest
+	5,  // 15: currency.CurrencyService.Consume:input_type -> currency.ConsumeRequest
+	8,  // 16: currency.CurrencyService.Refund:input_type -> currency.RefundRequest
+	11, // 17: currency.CurrencyService.Compensate:input_type -> currency.CompensateRequest
+	13, // 18: currency.CurrencyService.Transfer:input_type -> currency.TransferRequest
+	15, // 19: currency.CurrencyService.ProcessPayment:input_type -> currency.ProcessPaymentRequest
+	17, // 20: currency.CurrencyService.RedeemCode:input_type -> currency.RedeemCodeRequest
+	20, // 21: currency.CurrencyService.GetTransactionHistory:input_type -> currency.GetTransactionHistoryRequest
+	1,  // 22: currency.CurrencyService.GetBalance:output_type -> currency.GetBalanceResponse
+	4,  // 23: currency.CurrencyService.Grant:output_type -> currency.GrantResponse
+	6,  // 24: currency.CurrencyService.Consume:output_type -> currency.ConsumeResponse
+	9,  // 25: currency.CurrencyService.Refund:output_type -> currency.RefundResponse
+	12, // 26: currency.CurrencyService.Compensate:output_type -> currency.CompensateResponse
+	14, // 27: currency.CurrencyService.Transfer:output_type -> currency.TransferResponse
+	16, // 28: currency.CurrencyService.ProcessPayment:output_type -> currency.ProcessPaymentResponse
+	18, // 29: currency.CurrencyService.RedeemCode:output_type -> currency.RedeemCodeResponse
+	21, // 30: currency.CurrencyService.GetTransactionHistory:output_type -> currency.GetTransactionHistoryResponse
+	22, // [22:31] is the sub-list for method output_type
+	13, // [13:22] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_currency_proto_init() }
@@ -1238,7 +2193,7 @@ func file_currency_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_currency_proto_rawDesc), len(file_currency_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   18,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,9 @@ const (
 	CurrencyService_GetBalance_FullMethodName            = "/currency.CurrencyService/GetBalance"
 	CurrencyService_Grant_FullMethodName                 = "/currency.CurrencyService/Grant"
 	CurrencyService_Consume_FullMethodName               = "/currency.CurrencyService/Consume"
+	CurrencyService_Refund_FullMethodName                = "/currency.CurrencyService/Refund"
+	CurrencyService_Compensate_FullMethodName            = "/currency.CurrencyService/Compensate"
+	CurrencyService_Transfer_FullMethodName              = "/currency.CurrencyService/Transfer"
 	CurrencyService_ProcessPayment_FullMethodName        = "/currency.CurrencyService/ProcessPayment"
 	CurrencyService_RedeemCode_FullMethodName            = "/currency.CurrencyService/RedeemCode"
 	CurrencyService_GetTransactionHistory_FullMethodName = "/currency.CurrencyService/GetTransactionHistory"
@@ -39,6 +42,12 @@ type CurrencyServiceClient interface {
 	Grant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*GrantResponse, error)
 	// Consume 通貨消費
 	Consume(ctx context.Context, in *ConsumeRequest, opts ...grpc.CallOption) (*ConsumeResponse, error)
+	// Refund 消費トランザクションの返金
+	Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundResponse, error)
+	// Compensate 補填・回収による残高調整（マイナス残高を許可）
+	Compensate(ctx context.Context, in *CompensateRequest, opts ...grpc.CallOption) (*CompensateResponse, error)
+	// Transfer ユーザー間の通貨譲渡
+	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
 	// ProcessPayment 決済処理
 	ProcessPayment(ctx context.Context, in *ProcessPaymentRequest, opts ...grpc.CallOption) (*ProcessPaymentResponse, error)
 	// RedeemCode コード引き換え
@@ -85,6 +94,36 @@ func (c *currencyServiceClient) Consume(ctx context.Context, in *ConsumeRequest,
 	return out, nil
 }
 
+func (c *currencyServiceClient) Refund(ctx context.Context, in *RefundRequest, opts ...grpc.CallOption) (*RefundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefundResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_Refund_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyServiceClient) Compensate(ctx context.Context, in *CompensateRequest, opts ...grpc.CallOption) (*CompensateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompensateResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_Compensate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_Transfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *currencyServiceClient) ProcessPayment(ctx context.Context, in *ProcessPaymentRequest, opts ...grpc.CallOption) (*ProcessPaymentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ProcessPaymentResponse)
@@ -127,6 +166,12 @@ type CurrencyServiceServer interface {
 	Grant(context.Context, *GrantRequest) (*GrantResponse, error)
 	// Consume 通貨消費
 	Consume(context.Context, *ConsumeRequest) (*ConsumeResponse, error)
+	// Refund 消費トランザクションの返金
+	Refund(context.Context, *RefundRequest) (*RefundResponse, error)
+	// Compensate 補填・回収による残高調整（マイナス残高を許可）
+	Compensate(context.Context, *CompensateRequest) (*CompensateResponse, error)
+	// Transfer ユーザー間の通貨譲渡
+	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
 	// ProcessPayment 決済処理
 	ProcessPayment(context.Context, *ProcessPaymentRequest) (*ProcessPaymentResponse, error)
 	// RedeemCode コード引き換え
@@ -152,6 +197,15 @@ func (UnimplementedCurrencyServiceServer) Grant(context.Context, *GrantRequest)
 func (UnimplementedCurrencyServiceServer) Consume(context.Context, *ConsumeRequest) (*ConsumeResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Consume not implemented")
 }
+func (UnimplementedCurrencyServiceServer) Refund(context.Context, *RefundRequest) (*RefundResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Refund not implemented")
+}
+func (UnimplementedCurrencyServiceServer) Compensate(context.Context, *CompensateRequest) (*CompensateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Compensate not implemented")
+}
+func (UnimplementedCurrencyServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Transfer not implemented")
+}
 func (UnimplementedCurrencyServiceServer) ProcessPayment(context.Context, *ProcessPaymentRequest) (*ProcessPaymentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ProcessPayment not implemented")
 }
@@ -236,6 +290,60 @@ func _CurrencyService_Consume_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CurrencyService_Refund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).Refund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_Refund_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).Refund(ctx, req.(*RefundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CurrencyService_Compensate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompensateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).Compensate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_Compensate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).Compensate(ctx, req.(*CompensateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CurrencyService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).Transfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_Transfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).Transfer(ctx, req.(*TransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CurrencyService_ProcessPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ProcessPaymentRequest)
 	if err := dec(in); err != nil {
@@ -309,6 +417,18 @@ var CurrencyService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Consume",
 			Handler:    _CurrencyService_Consume_Handler,
 		},
+		{
+			MethodName: "Refund",
+			Handler:    _CurrencyService_Refund_Handler,
+		},
+		{
+			MethodName: "Compensate",
+			Handler:    _CurrencyService_Compensate_Handler,
+		},
+		{
+			MethodName: "Transfer",
+			Handler:    _CurrencyService_Transfer_Handler,
+		},
 		{
 			MethodName: "ProcessPayment",
 			Handler:    _CurrencyService_ProcessPayment_Handler,
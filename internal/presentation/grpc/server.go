@@ -11,8 +11,12 @@ import (
 	currencyapp "gem-server/internal/application/currency"
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/ipaccess"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/ratelimit"
 	"gem-server/internal/presentation/grpc/handler"
 	"gem-server/internal/presentation/grpc/interceptor"
 	"gem-server/internal/presentation/grpc/pb"
@@ -22,6 +26,19 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+// methodScopes メソッドごとに管理APIキーに必要なスコープ
+var methodScopes = map[string]api_key.Scope{
+	pb.CurrencyService_GetBalance_FullMethodName:            api_key.ScopeCurrencyRead,
+	pb.CurrencyService_Grant_FullMethodName:                 api_key.ScopeCurrencyGrant,
+	pb.CurrencyService_Consume_FullMethodName:               api_key.ScopeCurrencyConsume,
+	pb.CurrencyService_Refund_FullMethodName:                api_key.ScopeCurrencyRefund,
+	pb.CurrencyService_Compensate_FullMethodName:            api_key.ScopeCurrencyCompensate,
+	pb.CurrencyService_Transfer_FullMethodName:              api_key.ScopeCurrencyTransfer,
+	pb.CurrencyService_ProcessPayment_FullMethodName:        api_key.ScopePaymentsProcess,
+	pb.CurrencyService_RedeemCode_FullMethodName:            api_key.ScopeCodesRedeem,
+	pb.CurrencyService_GetTransactionHistory_FullMethodName: api_key.ScopeHistoryRead,
+}
+
 // Server gRPCサーバー
 type Server struct {
 	server   *grpc.Server
@@ -33,6 +50,8 @@ type Server struct {
 func NewServer(
 	cfg *config.Config,
 	logger *otelinfra.Logger,
+	limiters *ratelimit.Limiters,
+	authenticator *apikey.Authenticator,
 	currencyService *currencyapp.CurrencyApplicationService,
 	paymentService *paymentapp.PaymentApplicationService,
 	redemptionService *redemptionapp.CodeRedemptionApplicationService,
@@ -45,13 +64,20 @@ func NewServer(
 		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
 	}
 
-	return NewServerWithListener(cfg, logger, currencyService, paymentService, redemptionService, historyService, listener, port)
+	server, err := NewServerWithListener(cfg, logger, limiters, authenticator, currencyService, paymentService, redemptionService, historyService, listener, port)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return server, nil
 }
 
 // NewServerWithListener リスナーを指定してgRPCサーバーを作成（テスト用）
 func NewServerWithListener(
 	cfg *config.Config,
 	logger *otelinfra.Logger,
+	limiters *ratelimit.Limiters,
+	authenticator *apikey.Authenticator,
 	currencyService *currencyapp.CurrencyApplicationService,
 	paymentService *paymentapp.PaymentApplicationService,
 	redemptionService *redemptionapp.CodeRedemptionApplicationService,
@@ -59,9 +85,28 @@ func NewServerWithListener(
 	listener net.Listener,
 	port int,
 ) (*Server, error) {
-	// インターセプターを設定（APIキー認証のみ）
+	// クライアントのIPアドレスの判定（転送メタデータは信頼するプロキシからの接続の場合のみ使用する）
+	resolver, err := ipaccess.NewResolver(cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	adminAllowlist, err := ipaccess.NewAllowlist(cfg.AdminAPI.AllowedIPs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin API allowed IPs: %w", err)
+	}
+
+	// インターセプターを設定（IPアドレスごとのレート制限、APIキー認証、APIキーごとのレート制限の順）
+	var interceptors []grpc.UnaryServerInterceptor
+	if limiters != nil && limiters.IP != nil {
+		interceptors = append(interceptors, interceptor.RateLimitInterceptor(limiters.IP, interceptor.RateLimitByIP(resolver), logger))
+	}
+	interceptors = append(interceptors, interceptor.APIKeyInterceptor(&cfg.AdminAPI, authenticator, adminAllowlist, resolver, methodScopes, logger))
+	if limiters != nil && limiters.APIKey != nil {
+		interceptors = append(interceptors, interceptor.RateLimitInterceptor(limiters.APIKey, interceptor.RateLimitByAPIKey, logger))
+	}
+
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(interceptor.APIKeyInterceptor(&cfg.AdminAPI, logger)),
+		grpc.ChainUnaryInterceptor(interceptors...),
 		grpc.KeepaliveParams(keepalive.ServerParameters{
 			MaxConnectionIdle:     15 * time.Second,
 			MaxConnectionAge:      30 * time.Second,
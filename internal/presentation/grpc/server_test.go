@@ -2,7 +2,6 @@ package grpc
 
 import (
 	"context"
-	"database/sql"
 	"testing"
 	"time"
 
@@ -11,12 +10,15 @@ import (
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
 	"gem-server/internal/domain/service"
 	"gem-server/internal/domain/transaction"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/presentation/grpc/pb"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -66,14 +68,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -82,6 +102,19 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 // MockPaymentRequestRepository モックPaymentRequestリポジトリ
 type MockPaymentRequestRepository struct {
 	mock.Mock
@@ -105,15 +138,67 @@ func (m *MockPaymentRequestRepository) Update(ctx context.Context, pr *payment_r
 	return args.Error(0)
 }
 
+// MockLotRepository モックロットリポジトリ
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	args := m.Called(ctx, transactionID, consumptions)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]currency.LotConsumption), args.Error(1)
+}
+
+// newEmptyLotRepository 有効期限付きロットが存在しない状態のモックロットリポジトリを作成
+func newEmptyLotRepository() *MockLotRepository {
+	m := new(MockLotRepository)
+	m.On("FindAvailableByUserIDAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*currency.Lot{}, nil).Maybe()
+	m.On("FindConsumptionsByTransactionID", mock.Anything, mock.Anything).Return([]currency.LotConsumption{}, nil).Maybe()
+	return m
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(ctx, fn)
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
@@ -136,16 +221,46 @@ func (m *MockRedemptionCodeRepository) Update(ctx context.Context, code *redempt
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) IncrementUses(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
 	args := m.Called(ctx, code, userID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRedemptionCodeRepository) CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (redemption_code.CampaignUsage, error) {
+	args := m.Called(ctx, campaignID, userID)
+	return args.Get(0).(redemption_code.CampaignUsage), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) LockUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockRedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
 	args := m.Called(ctx, redemption)
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) UpdateSettings(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) SaveAuditLog(ctx context.Context, log *redemption_code.CodeAuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
 	args := m.Called(ctx, code)
 	return args.Error(0)
@@ -156,14 +271,56 @@ func (m *MockRedemptionCodeRepository) Delete(ctx context.Context, code string)
 	return args.Error(0)
 }
 
-func (m *MockRedemptionCodeRepository) FindAll(ctx context.Context, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockRedemptionCodeRepository) CreateBatch(ctx context.Context, codes []*redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, codes)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindByBatchID(ctx context.Context, batchID string) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindByFilter(ctx context.Context, filter redemption_code.Filter, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
 	return args.Get(0).([]*redemption_code.RedemptionCode), args.Int(1), args.Error(2)
 }
 
+func (m *MockRedemptionCodeRepository) FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, code, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) GetCodeStats(ctx context.Context, code string) (redemption_code.CodeStats, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(redemption_code.CodeStats), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
 func setupTestServer(t *testing.T) (*Server, *MockCurrencyRepository, *MockTransactionRepository, *MockPaymentRequestRepository, *MockTransactionManager, *MockRedemptionCodeRepository) {
 	t.Helper()
 
@@ -195,16 +352,22 @@ func setupTestServer(t *testing.T) (*Server, *MockCurrencyRepository, *MockTrans
 	currencyAppService := currencyapp.NewCurrencyApplicationService(
 		mockCurrencyRepo,
 		mockTransactionRepo,
+		newEmptyLotRepository(),
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		nil,
 		currencyService,
+		nil,
 		logger,
 		metrics,
 	)
 	paymentAppService := paymentapp.NewPaymentApplicationService(
 		mockCurrencyRepo,
 		mockTransactionRepo,
+		newEmptyLotRepository(),
 		mockPaymentRequestRepo,
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
 		logger,
 		metrics,
 	)
@@ -213,6 +376,9 @@ func setupTestServer(t *testing.T) (*Server, *MockCurrencyRepository, *MockTrans
 		mockTransactionRepo,
 		mockRedemptionCodeRepo,
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		nil,
+		nil,
 		logger,
 		metrics,
 	)
@@ -226,9 +392,14 @@ func setupTestServer(t *testing.T) (*Server, *MockCurrencyRepository, *MockTrans
 	listener := bufconn.Listen(1024 * 1024) // 1MB buffer
 	port := 8081                            // テスト用のポート番号
 
+	authenticator, err := apikey.NewAuthenticator(&cfg.AdminAPI, nil)
+	require.NoError(t, err)
+
 	server, err := NewServerWithListener(
 		cfg,
 		logger,
+		nil,
+		authenticator,
 		currencyAppService,
 		paymentAppService,
 		redemptionAppService,
@@ -278,6 +449,22 @@ func TestNewServer(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "異常系: 信頼するプロキシに不正なアドレス",
+			cfg: &config.Config{
+				Server: config.ServerConfig{
+					Port:           8080,
+					TrustedProxies: []string{"10.0.0.0/33"},
+				},
+				JWT: config.JWTConfig{
+					Secret:     "test-secret",
+					Expiration: 24 * time.Hour,
+					Issuer:     "test-issuer",
+				},
+				Environment: "production",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -298,16 +485,22 @@ func TestNewServer(t *testing.T) {
 			currencyAppService := currencyapp.NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
 			paymentAppService := paymentapp.NewPaymentApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockPaymentRequestRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
 				logger,
 				metrics,
 			)
@@ -316,6 +509,9 @@ func TestNewServer(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -329,9 +525,14 @@ func TestNewServer(t *testing.T) {
 			listener := bufconn.Listen(1024 * 1024)
 			port := tt.cfg.Server.Port + 1
 
+			authenticator, err := apikey.NewAuthenticator(&tt.cfg.AdminAPI, nil)
+			require.NoError(t, err)
+
 			server, err := NewServerWithListener(
 				tt.cfg,
 				logger,
+				nil,
+				authenticator,
 				currencyAppService,
 				paymentAppService,
 				redemptionAppService,
@@ -353,6 +554,17 @@ func TestNewServer(t *testing.T) {
 	}
 }
 
+func TestMethodScopes(t *testing.T) {
+	// すべてのメソッドに必要なスコープが設定されていることを確認（未設定のメソッドは常に拒否される）
+	for _, method := range pb.CurrencyService_ServiceDesc.Methods {
+		fullMethod := "/" + pb.CurrencyService_ServiceDesc.ServiceName + "/" + method.MethodName
+		scope, ok := methodScopes[fullMethod]
+		assert.True(t, ok, "%s has no scope", fullMethod)
+		assert.True(t, scope.Valid(), "%s has invalid scope %q", fullMethod, scope)
+	}
+	assert.Len(t, methodScopes, len(pb.CurrencyService_ServiceDesc.Methods))
+}
+
 func TestServer_Port(t *testing.T) {
 	server, _, _, _, _, _ := setupTestServer(t)
 	defer func() {
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	authapp "gem-server/internal/application/auth"
 
@@ -22,13 +23,16 @@ func NewAuthHandler(authService *authapp.AuthApplicationService) *AuthHandler {
 
 // GenerateToken トークン生成ハンドラー
 // @Summary 認証トークンを生成
-// @Description ユーザーIDを元にJWT認証トークンを生成します
+// @Description ユーザーIDを元にJWT認証トークン（アクセストークン）とリフレッシュトークンを生成します
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param user_id path string true "ユーザーID"
+// @Param X-API-Key header string true "APIキー"
 // @Success 200 {object} GenerateTokenResponse "トークン生成成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Router /admin/users/{user_id}/issue_token [post]
 func (h *AuthHandler) GenerateToken(c echo.Context) error {
 	userID := c.Param("user_id")
@@ -45,9 +49,139 @@ func (h *AuthHandler) GenerateToken(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, GenerateTokenResponse{
-		Token:     resp.Token,
-		ExpiresIn: int(resp.ExpiresIn),
+	return c.JSON(http.StatusOK, toGenerateTokenResponse(resp))
+}
+
+// RefreshToken トークン更新ハンドラー
+// @Summary 認証トークンを更新
+// @Description リフレッシュトークンを使用して新しいアクセストークンとリフレッシュトークンを発行します。使用したリフレッシュトークンは無効になり、再度使用された場合は同じ系列のリフレッシュトークンがすべて失効します
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshTokenRequest true "トークン更新リクエスト"
+// @Success 200 {object} GenerateTokenResponse "トークン更新成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "リフレッシュトークンが無効、期限切れ、または失効済み"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c echo.Context) error {
+	var reqBody RefreshTokenRequest
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if reqBody.RefreshToken == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "refresh_token is required")
+	}
+
+	resp, err := h.authService.RefreshToken(c.Request().Context(), &authapp.RefreshTokenRequest{
+		RefreshToken: reqBody.RefreshToken,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toGenerateTokenResponse(resp))
+}
+
+// RevokeToken トークン失効ハンドラー（管理API用）
+// @Summary 認証トークンを失効（管理API）
+// @Description アクセストークンまたはリフレッシュトークンを失効させます。アクセストークンは有効期限まで拒否され、リフレッシュトークンは同じ系列のリフレッシュトークンがすべて失効します
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "APIキー"
+// @Param request body RevokeTokenRequest true "トークン失効リクエスト"
+// @Success 200 {object} RevokeTokenResponse "トークン失効成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト、またはトークンが不正"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Router /admin/tokens/revoke [post]
+func (h *AuthHandler) RevokeToken(c echo.Context) error {
+	var reqBody RevokeTokenRequest
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if reqBody.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "token is required")
+	}
+
+	resp, err := h.authService.RevokeToken(c.Request().Context(), &authapp.RevokeTokenRequest{
+		Token: reqBody.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, RevokeTokenResponse{
 		TokenType: resp.TokenType,
+		RevokedAt: resp.RevokedAt.Format(time.RFC3339),
 	})
 }
+
+// RevokeUserTokens ユーザーのトークン一括失効ハンドラー（管理API用）
+// @Summary ユーザーの認証トークンを一括失効（管理API）
+// @Description ユーザーのリフレッシュトークンと、発行済みのアクセストークンをすべて失効させます
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "ユーザーID" example(user123)
+// @Param X-API-Key header string true "APIキー"
+// @Success 200 {object} RevokeUserTokensResponse "トークン一括失効成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Router /admin/users/{user_id}/revoke_tokens [post]
+func (h *AuthHandler) RevokeUserTokens(c echo.Context) error {
+	userID := c.Param("user_id")
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "user_id is required")
+	}
+
+	resp, err := h.authService.RevokeUserTokens(c.Request().Context(), &authapp.RevokeUserTokensRequest{
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, RevokeUserTokensResponse{
+		UserID:               resp.UserID,
+		RevokedRefreshTokens: resp.RevokedRefreshTokens,
+		RevokedAt:            resp.RevokedAt.Format(time.RFC3339),
+	})
+}
+
+// GetJWKS 検証用の公開鍵一覧ハンドラー
+// /.well-known/jwks.json で認証なしに公開する。HS256で署名している場合は空の一覧を返す
+func (h *AuthHandler) GetJWKS(c echo.Context) error {
+	resp := h.authService.GetJWKS(c.Request().Context())
+
+	keys := make([]JWKResponse, 0, len(resp.Keys))
+	for _, key := range resp.Keys {
+		keys = append(keys, JWKResponse{
+			KeyType:   key.KeyType,
+			KeyID:     key.KeyID,
+			Use:       key.Use,
+			Algorithm: key.Algorithm,
+			N:         key.N,
+			E:         key.E,
+			Curve:     key.Curve,
+			X:         key.X,
+			Y:         key.Y,
+		})
+	}
+
+	// 検証側がキャッシュできるようにする（新しい鍵は使用開始より前に公開される）
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(http.StatusOK, JWKSResponse{Keys: keys})
+}
+
+// toGenerateTokenResponse 発行したトークンをレスポンス形式に変換
+func toGenerateTokenResponse(resp *authapp.GenerateTokenResponse) GenerateTokenResponse {
+	return GenerateTokenResponse{
+		Token:            resp.Token,
+		ExpiresIn:        int(resp.ExpiresIn),
+		TokenType:        resp.TokenType,
+		RefreshToken:     resp.RefreshToken,
+		RefreshExpiresIn: int(resp.RefreshExpiresIn),
+	}
+}
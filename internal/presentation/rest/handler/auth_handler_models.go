@@ -9,9 +9,58 @@ type GenerateTokenRequest struct {
 // GenerateTokenResponse トークン生成レスポンス
 // @Description トークン生成レスポンス
 type GenerateTokenResponse struct {
-	Token     string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoidXNlcjEyMyIsImV4cCI6MTcwMDAwMDAwMH0.signature"`
-	ExpiresIn int    `json:"expires_in" example:"3600"`
-	TokenType string `json:"token_type" example:"Bearer"`
+	Token            string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoidXNlcjEyMyIsImV4cCI6MTcwMDAwMDAwMH0.signature"`
+	ExpiresIn        int    `json:"expires_in" example:"900"`
+	TokenType        string `json:"token_type" example:"Bearer"`
+	RefreshToken     string `json:"refresh_token" example:"Qm9vZ2llV29vZ2llQm9vZ2llV29vZ2llQm9vZ2llV29v"`
+	RefreshExpiresIn int    `json:"refresh_expires_in" example:"2592000"`
+}
+
+// RefreshTokenRequest トークン更新リクエスト
+// @Description トークン更新リクエスト
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" example:"Qm9vZ2llV29vZ2llQm9vZ2llV29vZ2llQm9vZ2llV29v"`
+}
+
+// RevokeTokenRequest トークン失効リクエスト
+// @Description トークン失効リクエスト（アクセストークンまたはリフレッシュトークン）
+type RevokeTokenRequest struct {
+	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoidXNlcjEyMyIsImV4cCI6MTcwMDAwMDAwMH0.signature"`
+}
+
+// RevokeTokenResponse トークン失効レスポンス
+// @Description トークン失効レスポンス
+type RevokeTokenResponse struct {
+	TokenType string `json:"token_type" example:"access" enums:"access,refresh"`
+	RevokedAt string `json:"revoked_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// RevokeUserTokensResponse ユーザーのトークン一括失効レスポンス
+// @Description ユーザーのトークン一括失効レスポンス
+type RevokeUserTokensResponse struct {
+	UserID               string `json:"user_id" example:"user123"`
+	RevokedRefreshTokens int    `json:"revoked_refresh_tokens" example:"2"`
+	RevokedAt            string `json:"revoked_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// JWKSResponse 検証用の公開鍵一覧（JWK Set）
+// @Description アクセストークンの検証に使う公開鍵一覧（RFC 7517 JWK Set）
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys"`
+}
+
+// JWKResponse 検証用の公開鍵（JWK）
+// @Description アクセストークンの検証に使う公開鍵（kidはトークンのヘッダーのkidに対応）
+type JWKResponse struct {
+	KeyType   string `json:"kty" example:"OKP" enums:"RSA,EC,OKP"`
+	KeyID     string `json:"kid" example:"2024-06"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"EdDSA" enums:"RS256,ES256,EdDSA"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty" example:"AQAB"`
+	Curve     string `json:"crv,omitempty" example:"Ed25519"`
+	X         string `json:"x,omitempty" example:"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"`
+	Y         string `json:"y,omitempty"`
 }
 
 // ErrorResponse エラーレスポンス
@@ -4,20 +4,56 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	authapp "gem-server/internal/application/auth"
+	"gem-server/internal/domain/auth_token"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/denylist"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 	restmiddleware "gem-server/internal/presentation/rest/middleware"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
 )
 
+// newTestAuthHandler テスト用のAuthHandlerとルーティングを設定したEchoを作成
+func newTestAuthHandler(t *testing.T, repo *MockRefreshTokenRepository, txManager *MockTransactionManager) *echo.Echo {
+	t.Helper()
+	e := echo.New()
+	cfg := &config.JWTConfig{
+		Secret:            "test-secret",
+		Expiration:        900 * time.Second,
+		RefreshExpiration: 30 * 24 * time.Hour,
+		Issuer:            "test",
+	}
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	// エラーハンドリングミドルウェアを設定
+	e.Use(restmiddleware.ErrorHandlerMiddleware(logger))
+
+	keyring, err := jwtkeys.NewKeyring(cfg)
+	require.NoError(t, err)
+	service := authapp.NewAuthApplicationService(cfg, keyring, repo, txManager, denylist.NewMemoryDenylist(), idgen.NewUUIDv7Generator(), logger)
+	handler := NewAuthHandler(service)
+
+	// ルーティングを設定（パスパラメータを使用）
+	e.POST("/admin/users/:user_id/issue_token", handler.GenerateToken)
+	e.POST("/admin/users/:user_id/revoke_tokens", handler.RevokeUserTokens)
+	e.POST("/admin/tokens/revoke", handler.RevokeToken)
+	e.POST("/auth/refresh", handler.RefreshToken)
+	e.GET("/.well-known/jwks.json", handler.GetJWKS)
+	return e
+}
+
 func TestAuthHandler_GenerateToken(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -38,23 +74,9 @@ func TestAuthHandler_GenerateToken(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			e := echo.New()
-			cfg := &config.JWTConfig{
-				Secret:     "test-secret",
-				Expiration: 86400 * time.Second,
-				Issuer:     "test",
-			}
-			tracer := noop.NewTracerProvider().Tracer("test")
-			logger := otelinfra.NewLogger(tracer)
-
-			// エラーハンドリングミドルウェアを設定
-			e.Use(restmiddleware.ErrorHandlerMiddleware(logger))
-
-			service := authapp.NewAuthApplicationService(cfg, logger)
-			handler := NewAuthHandler(service)
-
-			// ルーティングを設定（パスパラメータを使用）
-			e.POST("/admin/users/:user_id/issue_token", handler.GenerateToken)
+			repo := new(MockRefreshTokenRepository)
+			repo.On("Save", mock.Anything, mock.AnythingOfType("*auth_token.RefreshToken")).Return(nil).Maybe()
+			e := newTestAuthHandler(t, repo, new(MockTransactionManager))
 
 			path := "/admin/users/" + tt.userID + "/issue_token"
 			req := httptest.NewRequest(http.MethodPost, path, nil)
@@ -69,9 +91,171 @@ func TestAuthHandler_GenerateToken(t *testing.T) {
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				require.NoError(t, err)
 				assert.NotEmpty(t, response["token"])
-				assert.Equal(t, float64(86400), response["expires_in"])
+				assert.Equal(t, float64(900), response["expires_in"])
 				assert.Equal(t, "Bearer", response["token_type"])
+				assert.NotEmpty(t, response["refresh_token"])
+				assert.Equal(t, float64(2592000), response["refresh_expires_in"])
+			}
+		})
+	}
+}
+
+func TestAuthHandler_RefreshToken(t *testing.T) {
+	const refreshValue = "refresh-token-value"
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*MockRefreshTokenRepository, *MockTransactionManager)
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:        "正常系: トークンを更新",
+			requestBody: `{"refresh_token":"` + refreshValue + `"}`,
+			setupMock: func(repo *MockRefreshTokenRepository, mtx *MockTransactionManager) {
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+				token := auth_token.NewRefreshToken("rt_1", "user123", "", auth_token.HashToken(refreshValue), time.Now().Add(time.Hour))
+				repo.On("FindByHash", mock.Anything, auth_token.HashToken(refreshValue)).Return(token, nil)
+				repo.On("Update", mock.Anything, token).Return(nil)
+				repo.On("Save", mock.Anything, mock.AnythingOfType("*auth_token.RefreshToken")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "異常系: 存在しないリフレッシュトークン",
+			requestBody: `{"refresh_token":"unknown"}`,
+			setupMock: func(repo *MockRefreshTokenRepository, mtx *MockTransactionManager) {
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+				repo.On("FindByHash", mock.Anything, auth_token.HashToken("unknown")).Return(nil, auth_token.ErrRefreshTokenNotFound)
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "invalid_refresh_token",
+		},
+		{
+			name:           "異常系: refresh_tokenが空",
+			requestBody:    `{}`,
+			setupMock:      func(repo *MockRefreshTokenRepository, mtx *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRefreshTokenRepository)
+			mtx := new(MockTransactionManager)
+			tt.setupMock(repo, mtx)
+			e := newTestAuthHandler(t, repo, mtx)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response GenerateTokenResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.NotEmpty(t, response.Token)
+				assert.NotEmpty(t, response.RefreshToken)
+				assert.NotEqual(t, refreshValue, response.RefreshToken)
 			}
+			if tt.expectedError != "" {
+				assert.Contains(t, rec.Body.String(), tt.expectedError)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuthHandler_RevokeToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func(*MockRefreshTokenRepository)
+		expectedStatus int
+		expectedType   string
+	}{
+		{
+			name:        "正常系: リフレッシュトークンを失効",
+			requestBody: `{"token":"refresh-token-value"}`,
+			setupMock: func(repo *MockRefreshTokenRepository) {
+				token := auth_token.NewRefreshToken("rt_1", "user123", "", auth_token.HashToken("refresh-token-value"), time.Now().Add(time.Hour))
+				repo.On("FindByHash", mock.Anything, auth_token.HashToken("refresh-token-value")).Return(token, nil)
+				repo.On("RevokeFamily", mock.Anything, "rt_1", mock.AnythingOfType("time.Time")).Return(1, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "refresh",
+		},
+		{
+			name:           "異常系: 不正なアクセストークン",
+			requestBody:    `{"token":"invalid.jwt.token"}`,
+			setupMock:      func(repo *MockRefreshTokenRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "異常系: tokenが空",
+			requestBody:    `{}`,
+			setupMock:      func(repo *MockRefreshTokenRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRefreshTokenRepository)
+			tt.setupMock(repo)
+			e := newTestAuthHandler(t, repo, new(MockTransactionManager))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/tokens/revoke", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedType != "" {
+				var response RevokeTokenResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedType, response.TokenType)
+			}
+			repo.AssertExpectations(t)
 		})
 	}
 }
+
+func TestAuthHandler_RevokeUserTokens(t *testing.T) {
+	repo := new(MockRefreshTokenRepository)
+	repo.On("RevokeByUserID", mock.Anything, "user123", mock.AnythingOfType("time.Time")).Return(2, nil)
+	e := newTestAuthHandler(t, repo, new(MockTransactionManager))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/user123/revoke_tokens", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response RevokeUserTokensResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "user123", response.UserID)
+	assert.Equal(t, 2, response.RevokedRefreshTokens)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthHandler_GetJWKS(t *testing.T) {
+	e := newTestAuthHandler(t, new(MockRefreshTokenRepository), new(MockTransactionManager))
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=300", rec.Header().Get(echo.HeaderCacheControl))
+
+	// HS256で署名している場合は公開鍵がない
+	var response JWKSResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.NotNil(t, response.Keys)
+	assert.Empty(t, response.Keys)
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -32,8 +34,9 @@ func NewCodeRedemptionHandler(redemptionService *redemptionapp.CodeRedemptionApp
 // @Param request body RedeemCodeRequest true "コード引き換えリクエスト"
 // @Success 200 {object} RedeemCodeResponse "コード引き換え成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
-// @Failure 403 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "認証エラー、または引き換え条件を満たしていない"
 // @Failure 404 {object} ErrorResponse "コードが見つからない"
+// @Failure 429 {object} ErrorResponse "引き換えの失敗が多すぎるためロックアウト中"
 // @Router /codes/redeem [post]
 func (h *CodeRedemptionHandler) RedeemCode(c echo.Context) error {
 	// トークンからuser_idを取得
@@ -67,10 +70,95 @@ func (h *CodeRedemptionHandler) RedeemCode(c echo.Context) error {
 		CurrencyType:  resp.CurrencyType,
 		Amount:        strconv.FormatInt(resp.Amount, 10),
 		BalanceAfter:  strconv.FormatInt(resp.BalanceAfter, 10),
+		Rewards:       toRedeemedRewardItems(resp.Rewards),
 		Status:        resp.Status,
 	})
 }
 
+// toRedeemedRewardItems 付与した報酬をレスポンス形式に変換
+func toRedeemedRewardItems(rewards []redemptionapp.RedeemedReward) []RedeemedRewardItem {
+	items := make([]RedeemedRewardItem, len(rewards))
+	for i, r := range rewards {
+		items[i] = RedeemedRewardItem{
+			TransactionID: r.TransactionID,
+			CurrencyType:  r.CurrencyType,
+			Amount:        strconv.FormatInt(r.Amount, 10),
+			BalanceAfter:  strconv.FormatInt(r.BalanceAfter, 10),
+		}
+	}
+	return items
+}
+
+// parseRewardItems リクエストの報酬を変換（金額は文字列からint64に変換）
+func parseRewardItems(items []RewardItem) ([]redemptionapp.RewardLine, error) {
+	lines := make([]redemptionapp.RewardLine, len(items))
+	for i, item := range items {
+		amount, err := strconv.ParseInt(item.Amount, 10, 64)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid rewards amount format")
+		}
+		lines[i] = redemptionapp.RewardLine{
+			CurrencyType: item.CurrencyType,
+			Amount:       amount,
+		}
+	}
+	return lines, nil
+}
+
+// toRewardItems 報酬をレスポンス形式に変換
+func toRewardItems(rewards []redemptionapp.RewardLine) []RewardItem {
+	items := make([]RewardItem, len(rewards))
+	for i, r := range rewards {
+		items[i] = RewardItem{
+			CurrencyType: r.CurrencyType,
+			Amount:       strconv.FormatInt(r.Amount, 10),
+		}
+	}
+	return items
+}
+
+// parseEligibilityItem リクエストの引き換え条件を変換（new_users_sinceはRFC3339形式）
+func parseEligibilityItem(item *EligibilityItem) (redemptionapp.Eligibility, error) {
+	if item == nil {
+		return redemptionapp.Eligibility{}, nil
+	}
+
+	var newUsersSince time.Time
+	if item.NewUsersSince != "" {
+		var err error
+		newUsersSince, err = time.Parse(time.RFC3339, item.NewUsersSince)
+		if err != nil {
+			return redemptionapp.Eligibility{}, echo.NewHTTPError(http.StatusBadRequest, "invalid eligibility.new_users_since format")
+		}
+	}
+
+	return redemptionapp.Eligibility{
+		AllowedUserIDs:       item.AllowedUserIDs,
+		AllowedUserIDPrefix:  item.AllowedUserIDPrefix,
+		NewUsersSince:        newUsersSince,
+		MaxPerUserInCampaign: item.MaxPerUserInCampaign,
+		ExclusiveInCampaign:  item.ExclusiveInCampaign,
+	}, nil
+}
+
+// toEligibilityItem 引き換え条件をレスポンス形式に変換（条件なしの場合はnil）
+func toEligibilityItem(eligibility redemptionapp.Eligibility) *EligibilityItem {
+	if eligibility.IsZero() {
+		return nil
+	}
+
+	item := &EligibilityItem{
+		AllowedUserIDs:       eligibility.AllowedUserIDs,
+		AllowedUserIDPrefix:  eligibility.AllowedUserIDPrefix,
+		MaxPerUserInCampaign: eligibility.MaxPerUserInCampaign,
+		ExclusiveInCampaign:  eligibility.ExclusiveInCampaign,
+	}
+	if !eligibility.NewUsersSince.IsZero() {
+		item.NewUsersSince = eligibility.NewUsersSince.Format(time.RFC3339)
+	}
+	return item
+}
+
 // CreateCode 引き換えコード作成ハンドラー（管理API用）
 // @Summary 引き換えコードを作成（管理API）
 // @Description 新しい引き換えコードを作成します
@@ -82,6 +170,7 @@ func (h *CodeRedemptionHandler) RedeemCode(c echo.Context) error {
 // @Success 201 {object} CreateCodeResponse "引き換えコード作成成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Failure 409 {object} ErrorResponse "コードが既に存在"
 // @Router /admin/codes [post]
 func (h *CodeRedemptionHandler) CreateCode(c echo.Context) error {
@@ -107,10 +196,23 @@ func (h *CodeRedemptionHandler) CreateCode(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "invalid valid_until format")
 	}
 
-	// 金額をint64に変換
-	amount, err := strconv.ParseInt(reqBody.Amount, 10, 64)
+	// 金額をint64に変換（rewardsを指定した場合は省略可能）
+	var amount int64
+	if len(reqBody.Rewards) == 0 || reqBody.Amount != "" {
+		amount, err = strconv.ParseInt(reqBody.Amount, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid amount format")
+		}
+	}
+
+	rewards, err := parseRewardItems(reqBody.Rewards)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "invalid amount format")
+		return err
+	}
+
+	eligibility, err := parseEligibilityItem(reqBody.Eligibility)
+	if err != nil {
+		return err
 	}
 
 	req := &redemptionapp.CreateCodeRequest{
@@ -118,10 +220,13 @@ func (h *CodeRedemptionHandler) CreateCode(c echo.Context) error {
 		CodeType:     reqBody.CodeType,
 		CurrencyType: reqBody.CurrencyType,
 		Amount:       amount,
+		Rewards:      rewards,
 		MaxUses:      reqBody.MaxUses,
 		ValidFrom:    validFrom,
 		ValidUntil:   validUntil,
 		Metadata:     reqBody.Metadata,
+		CampaignID:   reqBody.CampaignID,
+		Eligibility:  eligibility,
 	}
 
 	resp, err := h.redemptionService.CreateCode(c.Request().Context(), req)
@@ -134,16 +239,277 @@ func (h *CodeRedemptionHandler) CreateCode(c echo.Context) error {
 		CodeType:     resp.CodeType,
 		CurrencyType: resp.CurrencyType,
 		Amount:       strconv.FormatInt(resp.Amount, 10),
+		Rewards:      toRewardItems(resp.Rewards),
 		MaxUses:      resp.MaxUses,
 		CurrentUses:  resp.CurrentUses,
 		ValidFrom:    resp.ValidFrom.Format(time.RFC3339),
 		ValidUntil:   resp.ValidUntil.Format(time.RFC3339),
 		Status:       resp.Status,
 		Metadata:     resp.Metadata,
+		CampaignID:   resp.CampaignID,
+		Eligibility:  toEligibilityItem(resp.Eligibility),
 		CreatedAt:    resp.CreatedAt.Format(time.RFC3339),
 	})
 }
 
+// GenerateCodes 引き換えコード一括生成ハンドラー（管理API用）
+// @Summary 引き換えコードを一括生成（管理API）
+// @Description パターンからランダムな引き換えコードを一括生成します。生成したコードは同じ設定とバッチIDを持ちます。alphabetを省略した場合は読み間違えやすい文字（0/O、1/I/L）を除いた英大文字と数字を使用します
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "APIキー"
+// @Param request body GenerateCodesRequest true "引き換えコード一括生成リクエスト"
+// @Success 201 {object} GenerateCodesResponse "引き換えコード一括生成成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 409 {object} ErrorResponse "バッチIDが使用済み"
+// @Router /admin/code_batches [post]
+func (h *CodeRedemptionHandler) GenerateCodes(c echo.Context) error {
+	var reqBody GenerateCodesRequest
+
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	// 日付のパース
+	validFrom, err := time.Parse(time.RFC3339, reqBody.ValidFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid valid_from format")
+	}
+
+	validUntil, err := time.Parse(time.RFC3339, reqBody.ValidUntil)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid valid_until format")
+	}
+
+	if validUntil.Before(validFrom) {
+		return echo.NewHTTPError(http.StatusBadRequest, "valid_until must be after valid_from")
+	}
+
+	if reqBody.MaxUses < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "max_uses must be non-negative")
+	}
+
+	// 金額をint64に変換（rewardsを指定した場合は省略可能）
+	var amount int64
+	if len(reqBody.Rewards) == 0 || reqBody.Amount != "" {
+		amount, err = strconv.ParseInt(reqBody.Amount, 10, 64)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid amount format")
+		}
+	}
+
+	rewards, err := parseRewardItems(reqBody.Rewards)
+	if err != nil {
+		return err
+	}
+
+	eligibility, err := parseEligibilityItem(reqBody.Eligibility)
+	if err != nil {
+		return err
+	}
+
+	req := &redemptionapp.GenerateCodesRequest{
+		BatchID:      reqBody.BatchID,
+		Count:        reqBody.Count,
+		Prefix:       reqBody.Prefix,
+		Length:       reqBody.Length,
+		Alphabet:     reqBody.Alphabet,
+		CodeType:     reqBody.CodeType,
+		CurrencyType: reqBody.CurrencyType,
+		Amount:       amount,
+		Rewards:      rewards,
+		MaxUses:      reqBody.MaxUses,
+		ValidFrom:    validFrom,
+		ValidUntil:   validUntil,
+		Metadata:     reqBody.Metadata,
+		CampaignID:   reqBody.CampaignID,
+		Eligibility:  eligibility,
+	}
+
+	resp, err := h.redemptionService.GenerateCodes(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]string, len(resp.Codes))
+	for i, code := range resp.Codes {
+		codes[i] = code.Code()
+	}
+
+	// 生成したコードはすべて同じ設定のため、先頭のコードから報酬を取得
+	first := resp.Codes[0]
+
+	return c.JSON(http.StatusCreated, GenerateCodesResponse{
+		BatchID:      resp.BatchID,
+		Count:        len(codes),
+		CodeType:     req.CodeType,
+		CurrencyType: first.CurrencyType().String(),
+		Amount:       strconv.FormatInt(first.Amount(), 10),
+		Rewards:      toRewardItems(redemptionapp.ToRewardLines(first.Rewards())),
+		MaxUses:      req.MaxUses,
+		ValidFrom:    req.ValidFrom.Format(time.RFC3339),
+		ValidUntil:   req.ValidUntil.Format(time.RFC3339),
+		CampaignID:   req.CampaignID,
+		Codes:        codes,
+	})
+}
+
+// ExportBatchCodes 一括生成した引き換えコードのエクスポートハンドラー（管理API用）
+// @Summary 一括生成した引き換えコードをCSVでダウンロード（管理API）
+// @Description バッチIDで一括生成した引き換えコードを、使用回数とステータスを含めてCSVで出力します
+// @Tags admin
+// @Produce text/csv
+// @Param batch_id path string true "バッチID" example(influencer_2024_spring)
+// @Param X-API-Key header string true "APIキー"
+// @Success 200 {string} string "エクスポート成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 404 {object} ErrorResponse "バッチが見つからない"
+// @Router /admin/code_batches/{batch_id}/export [get]
+func (h *CodeRedemptionHandler) ExportBatchCodes(c echo.Context) error {
+	batchID := c.Param("batch_id")
+	if batchID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "batch_id is required")
+	}
+
+	req := &redemptionapp.ExportBatchCodesRequest{
+		BatchID: batchID,
+	}
+
+	// 1バッチの件数には上限があるため、エラー時に通常のエラーレスポンスを返せるようバッファしてから送信する
+	var buf bytes.Buffer
+	if _, err := h.redemptionService.ExportBatchCodes(c.Request().Context(), req, &buf); err != nil {
+		return err
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.csv"`, sanitizeFilename(batchID)))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// sanitizeFilename Content-Dispositionのファイル名に使用できない文字を置き換える
+func sanitizeFilename(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' && c != '_' && c != '.' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// UpdateCode 引き換えコード更新ハンドラー（管理API用）
+// @Summary 引き換えコードを更新（管理API）
+// @Description 引き換えコードの無効化・再有効化、有効期限の延長、最大使用回数の引き上げを行います。変更内容は管理APIキーの名前とともに変更履歴に記録されます
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param code path string true "引き換えコード" example(PROMO2024)
+// @Param X-API-Key header string true "APIキー"
+// @Param request body UpdateCodeRequest true "引き換えコード更新リクエスト"
+// @Success 200 {object} UpdateCodeResponse "引き換えコード更新成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト、または許可されていない変更"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 404 {object} ErrorResponse "コードが見つからない"
+// @Router /admin/codes/{code} [patch]
+func (h *CodeRedemptionHandler) UpdateCode(c echo.Context) error {
+	code := c.Param("code")
+	if code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "code is required")
+	}
+
+	var reqBody UpdateCodeRequest
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	req := &redemptionapp.UpdateCodeRequest{
+		Code:      code,
+		Status:    reqBody.Status,
+		MaxUses:   reqBody.MaxUses,
+		Reason:    reqBody.Reason,
+		Requester: apiKeyName(c),
+	}
+
+	// 日付のパース
+	if reqBody.ValidUntil != nil {
+		validUntil, err := time.Parse(time.RFC3339, *reqBody.ValidUntil)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid valid_until format")
+		}
+		req.ValidUntil = &validUntil
+	}
+
+	resp, err := h.redemptionService.UpdateCode(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	changes := make([]CodeChangeItem, len(resp.Changes))
+	for i, change := range resp.Changes {
+		changes[i] = CodeChangeItem{
+			Field: change.Field,
+			From:  change.From,
+			To:    change.To,
+		}
+	}
+
+	return c.JSON(http.StatusOK, UpdateCodeResponse{
+		Code:        resp.Code,
+		Status:      resp.Status,
+		MaxUses:     resp.MaxUses,
+		CurrentUses: resp.CurrentUses,
+		ValidFrom:   resp.ValidFrom.Format(time.RFC3339),
+		ValidUntil:  resp.ValidUntil.Format(time.RFC3339),
+		AuditID:     resp.AuditID,
+		Changes:     changes,
+		UpdatedAt:   resp.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// ExpireCodes 期限切れコードの一括失効ハンドラー（管理API用）
+// @Summary 期限切れコードを一括失効（管理API）
+// @Description 有効期限を過ぎたactiveなコードをexpiredに変更します。定期ジョブと同じ処理を即時に実行し、失効は管理APIキーの名前とともに変更履歴に記録されます。リクエストボディは省略できます
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "APIキー"
+// @Param request body ExpireCodesRequest false "期限切れコードの一括失効リクエスト"
+// @Success 200 {object} ExpireCodesResponse "一括失効成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 409 {object} ErrorResponse "他のインスタンスで一括失効を実行中"
+// @Router /admin/codes/expire_sweep [post]
+func (h *CodeRedemptionHandler) ExpireCodes(c echo.Context) error {
+	var reqBody ExpireCodesRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&reqBody); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+		}
+	}
+	if reqBody.Limit < 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "limit must be non-negative")
+	}
+
+	resp, err := h.redemptionService.ExpireCodes(c.Request().Context(), &redemptionapp.ExpireCodesRequest{
+		Limit:     reqBody.Limit,
+		Requester: apiKeyName(c),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ExpireCodesResponse{
+		ExpiredCodes: resp.ExpiredCodes,
+		Codes:        resp.Codes,
+	})
+}
+
 // DeleteCode 引き換えコード削除ハンドラー（管理API用）
 // @Summary 引き換えコードを削除（管理API）
 // @Description 引き換えコードを削除します（使用済みコードは削除不可）
@@ -155,6 +521,7 @@ func (h *CodeRedemptionHandler) CreateCode(c echo.Context) error {
 // @Success 200 {object} DeleteCodeResponse "引き換えコード削除成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Failure 404 {object} ErrorResponse "コードが見つからない"
 // @Failure 409 {object} ErrorResponse "コードが使用済みのため削除不可"
 // @Router /admin/codes/{code} [delete]
@@ -179,6 +546,40 @@ func (h *CodeRedemptionHandler) DeleteCode(c echo.Context) error {
 	})
 }
 
+// ClearRedemptionLockout 引き換えロックアウト解除ハンドラー（管理API用）
+// @Summary 引き換えロックアウトを解除（管理API）
+// @Description ユーザーの引き換え失敗の記録を消去し、ロックアウトを解除します
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "ユーザーID" example(user123)
+// @Param X-API-Key header string true "APIキー"
+// @Success 200 {object} ClearRedemptionLockoutResponse "ロックアウト解除成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Router /admin/users/{user_id}/redemption_lockout [delete]
+func (h *CodeRedemptionHandler) ClearRedemptionLockout(c echo.Context) error {
+	userID := c.Param("user_id")
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "user_id is required")
+	}
+
+	req := &redemptionapp.ClearRedemptionLockoutRequest{
+		UserID: userID,
+	}
+
+	resp, err := h.redemptionService.ClearRedemptionLockout(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ClearRedemptionLockoutResponse{
+		UserID:    resp.UserID,
+		ClearedAt: resp.ClearedAt.Format(time.RFC3339),
+	})
+}
+
 // GetCode 引き換えコード取得ハンドラー（管理API用）
 // @Summary 引き換えコードを取得（管理API）
 // @Description 指定された引き換えコードの詳細を取得します
@@ -190,6 +591,7 @@ func (h *CodeRedemptionHandler) DeleteCode(c echo.Context) error {
 // @Success 200 {object} GetCodeResponse "引き換えコード取得成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Failure 404 {object} ErrorResponse "コードが見つからない"
 // @Router /admin/codes/{code} [get]
 func (h *CodeRedemptionHandler) GetCode(c echo.Context) error {
@@ -212,12 +614,16 @@ func (h *CodeRedemptionHandler) GetCode(c echo.Context) error {
 		CodeType:     resp.CodeType,
 		CurrencyType: resp.CurrencyType,
 		Amount:       strconv.FormatInt(resp.Amount, 10),
+		Rewards:      toRewardItems(resp.Rewards),
 		MaxUses:      resp.MaxUses,
 		CurrentUses:  resp.CurrentUses,
 		ValidFrom:    resp.ValidFrom.Format(time.RFC3339),
 		ValidUntil:   resp.ValidUntil.Format(time.RFC3339),
 		Status:       resp.Status,
 		Metadata:     resp.Metadata,
+		BatchID:      resp.BatchID,
+		CampaignID:   resp.CampaignID,
+		Eligibility:  toEligibilityItem(resp.Eligibility),
 		CreatedAt:    resp.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:    resp.UpdatedAt.Format(time.RFC3339),
 	})
@@ -225,7 +631,7 @@ func (h *CodeRedemptionHandler) GetCode(c echo.Context) error {
 
 // ListCodes 引き換えコード一覧取得ハンドラー（管理API用）
 // @Summary 引き換えコード一覧を取得（管理API）
-// @Description 引き換えコードの一覧を取得します（ページネーション・フィルタリング対応）
+// @Description 引き換えコードの一覧を取得します（ページネーション・フィルタリング対応）。totalは検索条件に一致する総件数です
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -233,39 +639,45 @@ func (h *CodeRedemptionHandler) GetCode(c echo.Context) error {
 // @Param offset query int false "オフセット" default(0) example(0)
 // @Param status query string false "ステータスフィルタ" example(active) enums(active,expired,disabled)
 // @Param code_type query string false "コードタイプフィルタ" example(promotion) enums(promotion,gift,event)
+// @Param currency_type query string false "通貨タイプフィルタ（報酬にこの通貨を含むコード）" example(free) enums(paid,free)
+// @Param campaign_id query string false "キャンペーンIDフィルタ（campaign_idまたはmetadataのcampaign_id）" example(spring_2024)
+// @Param code_prefix query string false "コードの前方一致検索" example(INF-)
+// @Param valid_from query string false "有効期間がこの日時以降と重なるコード（RFC3339）" example(2024-04-01T00:00:00Z)
+// @Param valid_until query string false "有効期間がこの日時以前と重なるコード（RFC3339）" example(2024-04-30T23:59:59Z)
+// @Param sort query string false "並び順" default(created_at_desc) enums(created_at_desc,created_at_asc,code_asc,valid_until_asc,valid_until_desc)
 // @Param X-API-Key header string true "APIキー"
 // @Success 200 {object} ListCodesResponse "引き換えコード一覧取得成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Router /admin/codes [get]
 func (h *CodeRedemptionHandler) ListCodes(c echo.Context) error {
 	// クエリパラメータの取得
-	limit := 50 // デフォルト値
-	if limitStr := c.QueryParam("limit"); limitStr != "" {
-		var err error
-		limit, err = strconv.Atoi(limitStr)
-		if err != nil || limit <= 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "invalid limit parameter")
-		}
+	limit, offset, err := parsePageQueryParams(c)
+	if err != nil {
+		return err
 	}
 
-	offset := 0 // デフォルト値
-	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
-		var err error
-		offset, err = strconv.Atoi(offsetStr)
-		if err != nil || offset < 0 {
-			return echo.NewHTTPError(http.StatusBadRequest, "invalid offset parameter")
-		}
+	validFrom, err := parseTimeQueryParam(c, "valid_from")
+	if err != nil {
+		return err
+	}
+	validUntil, err := parseTimeQueryParam(c, "valid_until")
+	if err != nil {
+		return err
 	}
-
-	status := c.QueryParam("status")
-	codeType := c.QueryParam("code_type")
 
 	req := &redemptionapp.ListCodesRequest{
-		Limit:    limit,
-		Offset:   offset,
-		Status:   status,
-		CodeType: codeType,
+		Limit:        limit,
+		Offset:       offset,
+		Status:       c.QueryParam("status"),
+		CodeType:     c.QueryParam("code_type"),
+		CurrencyType: c.QueryParam("currency_type"),
+		CampaignID:   c.QueryParam("campaign_id"),
+		CodePrefix:   c.QueryParam("code_prefix"),
+		ValidFrom:    validFrom,
+		ValidUntil:   validUntil,
+		Sort:         c.QueryParam("sort"),
 	}
 
 	resp, err := h.redemptionService.ListCodes(c.Request().Context(), req)
@@ -281,12 +693,16 @@ func (h *CodeRedemptionHandler) ListCodes(c echo.Context) error {
 			CodeType:     code.CodeType().String(),
 			CurrencyType: code.CurrencyType().String(),
 			Amount:       strconv.FormatInt(code.Amount(), 10),
+			Rewards:      toRewardItems(redemptionapp.ToRewardLines(code.Rewards())),
 			MaxUses:      code.MaxUses(),
 			CurrentUses:  code.CurrentUses(),
 			ValidFrom:    code.ValidFrom().Format(time.RFC3339),
 			ValidUntil:   code.ValidUntil().Format(time.RFC3339),
 			Status:       code.Status().String(),
 			Metadata:     code.Metadata(),
+			BatchID:      code.BatchID(),
+			CampaignID:   code.CampaignID(),
+			Eligibility:  toEligibilityItem(code.Eligibility()),
 			CreatedAt:    code.CreatedAt().Format(time.RFC3339),
 			UpdatedAt:    code.UpdatedAt().Format(time.RFC3339),
 		}
@@ -299,3 +715,170 @@ func (h *CodeRedemptionHandler) ListCodes(c echo.Context) error {
 		Offset: resp.Offset,
 	})
 }
+
+// ListCodeRedemptions 引き換え履歴取得ハンドラー（管理API用）
+// @Summary 引き換えコードの引き換え履歴を取得（管理API）
+// @Description 指定された引き換えコードを引き換えたユーザーと日時、付与トランザクションを新しい順に取得します
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param code path string true "引き換えコード" example(PROMO2024)
+// @Param limit query int false "取得件数（最大: 100）" default(50) example(50)
+// @Param offset query int false "オフセット" default(0) example(0)
+// @Param X-API-Key header string true "APIキー"
+// @Success 200 {object} ListRedemptionsResponse "引き換え履歴取得成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 404 {object} ErrorResponse "コードが見つからない"
+// @Router /admin/codes/{code}/redemptions [get]
+func (h *CodeRedemptionHandler) ListCodeRedemptions(c echo.Context) error {
+	code := c.Param("code")
+	if code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "code is required")
+	}
+
+	limit, offset, err := parsePageQueryParams(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.redemptionService.ListCodeRedemptions(c.Request().Context(), &redemptionapp.ListCodeRedemptionsRequest{
+		Code:   code,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newListRedemptionsResponse(resp))
+}
+
+// ListMyRedemptions 引き換え履歴取得ハンドラー（ユーザーAPI用）
+// @Summary 引き換え履歴を取得
+// @Description 自分がこれまでに引き換えたコードの履歴を新しい順に取得します
+// @Tags redemption
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param limit query int false "取得件数（最大: 100）" default(50) example(50)
+// @Param offset query int false "オフセット" default(0) example(0)
+// @Success 200 {object} ListRedemptionsResponse "引き換え履歴取得成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Router /me/redemptions [get]
+func (h *CodeRedemptionHandler) ListMyRedemptions(c echo.Context) error {
+	// トークンからuser_idを取得
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user_id not found in token")
+	}
+
+	limit, offset, err := parsePageQueryParams(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.redemptionService.ListUserRedemptions(c.Request().Context(), &redemptionapp.ListUserRedemptionsRequest{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, newListRedemptionsResponse(resp))
+}
+
+// GetCodeStats 引き換えコードの利用統計取得ハンドラー（管理API用）
+// @Summary 引き換えコードの利用統計を取得（管理API）
+// @Description 指定された引き換えコードの引き換え回数・ユーザー数・付与した通貨の合計・日ごとの引き換え回数を取得します
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param code path string true "引き換えコード" example(PROMO2024)
+// @Param X-API-Key header string true "APIキー"
+// @Success 200 {object} CodeStatsResponse "利用統計取得成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 404 {object} ErrorResponse "コードが見つからない"
+// @Router /admin/codes/{code}/stats [get]
+func (h *CodeRedemptionHandler) GetCodeStats(c echo.Context) error {
+	code := c.Param("code")
+	if code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "code is required")
+	}
+
+	resp, err := h.redemptionService.GetCodeStats(c.Request().Context(), &redemptionapp.GetCodeStatsRequest{
+		Code: code,
+	})
+	if err != nil {
+		return err
+	}
+
+	daily := make([]DailyRedemptionItem, len(resp.Daily))
+	for i, d := range resp.Daily {
+		daily[i] = DailyRedemptionItem{
+			Date:  d.Date.Format(time.DateOnly),
+			Count: d.Count,
+		}
+	}
+
+	return c.JSON(http.StatusOK, CodeStatsResponse{
+		Code:         resp.Code,
+		MaxUses:      resp.MaxUses,
+		CurrentUses:  resp.CurrentUses,
+		Redemptions:  resp.Redemptions,
+		UniqueUsers:  resp.UniqueUsers,
+		TotalGranted: toRewardItems(resp.TotalGranted),
+		Daily:        daily,
+	})
+}
+
+// newListRedemptionsResponse 引き換え履歴をレスポンス形式に変換
+func newListRedemptionsResponse(resp *redemptionapp.ListRedemptionsResponse) ListRedemptionsResponse {
+	redemptions := make([]RedemptionItem, len(resp.Redemptions))
+	for i, r := range resp.Redemptions {
+		redemptions[i] = RedemptionItem{
+			RedemptionID:   r.RedemptionID(),
+			Code:           r.Code(),
+			UserID:         r.UserID(),
+			TransactionID:  r.TransactionID(),
+			TransactionIDs: r.TransactionIDs(),
+			RedeemedAt:     r.RedeemedAt().Format(time.RFC3339),
+		}
+	}
+
+	return ListRedemptionsResponse{
+		Redemptions: redemptions,
+		Total:       resp.Total,
+		Limit:       resp.Limit,
+		Offset:      resp.Offset,
+	}
+}
+
+// parsePageQueryParams limit・offsetクエリパラメータを取得（省略時は50件・先頭から）
+func parsePageQueryParams(c echo.Context) (int, int, error) {
+	limit := 50 // デフォルト値
+	if limitStr := c.QueryParam("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "invalid limit parameter")
+		}
+	}
+
+	offset := 0 // デフォルト値
+	if offsetStr := c.QueryParam("offset"); offsetStr != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return 0, 0, echo.NewHTTPError(http.StatusBadRequest, "invalid offset parameter")
+		}
+	}
+
+	return limit, offset, nil
+}
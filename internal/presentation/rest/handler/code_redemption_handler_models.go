@@ -9,26 +9,56 @@ type RedeemCodeRequest struct {
 // RedeemCodeResponse コード引き換えレスポンス
 // @Description コード引き換えレスポンス
 type RedeemCodeResponse struct {
-	RedemptionID  string `json:"redemption_id" example:"red_123"`
+	RedemptionID  string               `json:"redemption_id" example:"red_123"`
+	TransactionID string               `json:"transaction_id" example:"txn_456"`
+	Code          string               `json:"code" example:"REDEEM123"`
+	CurrencyType  string               `json:"currency_type" example:"free" enums:"paid,free"`
+	Amount        string               `json:"amount" example:"500"`
+	BalanceAfter  string               `json:"balance_after" example:"1000"`
+	Rewards       []RedeemedRewardItem `json:"rewards"`
+	Status        string               `json:"status" example:"completed"`
+}
+
+// RedeemedRewardItem 引き換えで付与した報酬
+// @Description 引き換えで付与した報酬（報酬ごとにトランザクションが記録される）
+type RedeemedRewardItem struct {
 	TransactionID string `json:"transaction_id" example:"txn_456"`
-	Code          string `json:"code" example:"REDEEM123"`
 	CurrencyType  string `json:"currency_type" example:"free" enums:"paid,free"`
 	Amount        string `json:"amount" example:"500"`
 	BalanceAfter  string `json:"balance_after" example:"1000"`
-	Status        string `json:"status" example:"completed"`
+}
+
+// RewardItem 引き換えコードの報酬
+// @Description 引き換えコードの報酬
+type RewardItem struct {
+	CurrencyType string `json:"currency_type" example:"free" enums:"paid,free"`
+	Amount       string `json:"amount" example:"100"`
+}
+
+// EligibilityItem 引き換えコードを引き換えできるユーザーの条件
+// @Description 引き換え条件（allowed_user_idsとallowed_user_id_prefixを両方指定した場合はどちらかに一致すれば引き換え可能、キャンペーン単位の条件はcampaign_idが必要）
+type EligibilityItem struct {
+	AllowedUserIDs       []string `json:"allowed_user_ids,omitempty" example:"user123,user456"`
+	AllowedUserIDPrefix  string   `json:"allowed_user_id_prefix,omitempty" example:"beta_"`
+	NewUsersSince        string   `json:"new_users_since,omitempty" example:"2024-04-01T00:00:00Z"`
+	MaxPerUserInCampaign int      `json:"max_per_user_in_campaign,omitempty" example:"1"`
+	ExclusiveInCampaign  bool     `json:"exclusive_in_campaign,omitempty" example:"true"`
 }
 
 // CreateCodeRequest 引き換えコード作成リクエスト
-// @Description 引き換えコード作成リクエスト
+// @Description 引き換えコード作成リクエスト（複数の通貨を付与する場合はcurrency_typeとamountの代わりにrewardsを指定）
 type CreateCodeRequest struct {
 	Code         string                 `json:"code" example:"PROMO2024"`
 	CodeType     string                 `json:"code_type" example:"promotion" enums:"promotion,gift,event"`
 	CurrencyType string                 `json:"currency_type" example:"free" enums:"paid,free"`
 	Amount       string                 `json:"amount" example:"1000"`
+	Rewards      []RewardItem           `json:"rewards,omitempty"`
 	MaxUses      int                    `json:"max_uses" example:"100"`
 	ValidFrom    string                 `json:"valid_from" example:"2024-01-01T00:00:00Z"`
 	ValidUntil   string                 `json:"valid_until" example:"2024-12-31T23:59:59Z"`
 	Metadata     map[string]interface{} `json:"metadata"`
+	CampaignID   string                 `json:"campaign_id,omitempty" example:"spring_2024"`
+	Eligibility  *EligibilityItem       `json:"eligibility,omitempty"`
 }
 
 // CreateCodeResponse 引き換えコード作成レスポンス
@@ -38,15 +68,85 @@ type CreateCodeResponse struct {
 	CodeType     string                 `json:"code_type" example:"promotion"`
 	CurrencyType string                 `json:"currency_type" example:"free"`
 	Amount       string                 `json:"amount" example:"1000"`
+	Rewards      []RewardItem           `json:"rewards"`
 	MaxUses      int                    `json:"max_uses" example:"100"`
 	CurrentUses  int                    `json:"current_uses" example:"0"`
 	ValidFrom    string                 `json:"valid_from" example:"2024-01-01T00:00:00Z"`
 	ValidUntil   string                 `json:"valid_until" example:"2024-12-31T23:59:59Z"`
 	Status       string                 `json:"status" example:"active"`
 	Metadata     map[string]interface{} `json:"metadata"`
+	CampaignID   string                 `json:"campaign_id,omitempty" example:"spring_2024"`
+	Eligibility  *EligibilityItem       `json:"eligibility,omitempty"`
 	CreatedAt    string                 `json:"created_at" example:"2024-01-01T00:00:00Z"`
 }
 
+// GenerateCodesRequest 引き換えコード一括生成リクエスト
+// @Description 引き換えコード一括生成リクエスト（コードは prefix + alphabetから選んだlength文字、複数の通貨を付与する場合はcurrency_typeとamountの代わりにrewardsを指定）
+type GenerateCodesRequest struct {
+	BatchID      string                 `json:"batch_id" example:"influencer_2024_spring"`
+	Count        int                    `json:"count" example:"1000"`
+	Prefix       string                 `json:"prefix" example:"INF-"`
+	Length       int                    `json:"length" example:"10"`
+	Alphabet     string                 `json:"alphabet" example:"ABCDEFGHJKMNPQRSTUVWXYZ23456789"`
+	CodeType     string                 `json:"code_type" example:"promotion" enums:"promotion,gift,event"`
+	CurrencyType string                 `json:"currency_type" example:"free" enums:"paid,free"`
+	Amount       string                 `json:"amount" example:"500"`
+	Rewards      []RewardItem           `json:"rewards,omitempty"`
+	MaxUses      int                    `json:"max_uses" example:"1"`
+	ValidFrom    string                 `json:"valid_from" example:"2024-01-01T00:00:00Z"`
+	ValidUntil   string                 `json:"valid_until" example:"2024-12-31T23:59:59Z"`
+	Metadata     map[string]interface{} `json:"metadata"`
+	CampaignID   string                 `json:"campaign_id,omitempty" example:"spring_2024"`
+	Eligibility  *EligibilityItem       `json:"eligibility,omitempty"`
+}
+
+// GenerateCodesResponse 引き換えコード一括生成レスポンス
+// @Description 引き換えコード一括生成レスポンス
+type GenerateCodesResponse struct {
+	BatchID      string       `json:"batch_id" example:"influencer_2024_spring"`
+	Count        int          `json:"count" example:"1000"`
+	CodeType     string       `json:"code_type" example:"promotion"`
+	CurrencyType string       `json:"currency_type" example:"free"`
+	Amount       string       `json:"amount" example:"500"`
+	Rewards      []RewardItem `json:"rewards"`
+	MaxUses      int          `json:"max_uses" example:"1"`
+	ValidFrom    string       `json:"valid_from" example:"2024-01-01T00:00:00Z"`
+	ValidUntil   string       `json:"valid_until" example:"2024-12-31T23:59:59Z"`
+	CampaignID   string       `json:"campaign_id,omitempty" example:"spring_2024"`
+	Codes        []string     `json:"codes" example:"INF-7KQ2M9XHPA"`
+}
+
+// UpdateCodeRequest 引き換えコード更新リクエスト
+// @Description 引き換えコード更新リクエスト（省略したフィールドは変更しない。valid_untilは延長のみ、max_usesは引き上げのみ可能で、valid_untilを過ぎたコードはactiveに戻せない）
+type UpdateCodeRequest struct {
+	Status     *string `json:"status,omitempty" example:"active" enums:"active,disabled,expired"`
+	ValidUntil *string `json:"valid_until,omitempty" example:"2025-03-31T23:59:59Z"`
+	MaxUses    *int    `json:"max_uses,omitempty" example:"200"`
+	Reason     string  `json:"reason" example:"キャンペーン期間の延長"`
+}
+
+// CodeChangeItem 変更されたフィールド
+// @Description 変更されたフィールドと変更前後の値
+type CodeChangeItem struct {
+	Field string `json:"field" example:"max_uses"`
+	From  string `json:"from" example:"100"`
+	To    string `json:"to" example:"200"`
+}
+
+// UpdateCodeResponse 引き換えコード更新レスポンス
+// @Description 引き換えコード更新レスポンス（値が変わらなかった場合はaudit_idが空でchangesが空配列）
+type UpdateCodeResponse struct {
+	Code        string           `json:"code" example:"PROMO2024"`
+	Status      string           `json:"status" example:"active"`
+	MaxUses     int              `json:"max_uses" example:"200"`
+	CurrentUses int              `json:"current_uses" example:"100"`
+	ValidFrom   string           `json:"valid_from" example:"2024-01-01T00:00:00Z"`
+	ValidUntil  string           `json:"valid_until" example:"2025-03-31T23:59:59Z"`
+	AuditID     string           `json:"audit_id,omitempty" example:"audit_0190a6e2-7c3b-7d4e-8f5a-1b2c3d4e5f60"`
+	Changes     []CodeChangeItem `json:"changes"`
+	UpdatedAt   string           `json:"updated_at" example:"2024-06-01T00:00:00Z"`
+}
+
 // DeleteCodeResponse 引き換えコード削除レスポンス
 // @Description 引き換えコード削除レスポンス
 type DeleteCodeResponse struct {
@@ -54,6 +154,13 @@ type DeleteCodeResponse struct {
 	DeletedAt string `json:"deleted_at" example:"2024-01-01T00:00:00Z"`
 }
 
+// ClearRedemptionLockoutResponse 引き換えロックアウト解除レスポンス
+// @Description 引き換えロックアウト解除レスポンス
+type ClearRedemptionLockoutResponse struct {
+	UserID    string `json:"user_id" example:"user123"`
+	ClearedAt string `json:"cleared_at" example:"2024-01-01T00:00:00Z"`
+}
+
 // GetCodeResponse 引き換えコード取得レスポンス
 // @Description 引き換えコード取得レスポンス
 type GetCodeResponse struct {
@@ -61,12 +168,16 @@ type GetCodeResponse struct {
 	CodeType     string                 `json:"code_type" example:"promotion"`
 	CurrencyType string                 `json:"currency_type" example:"free"`
 	Amount       string                 `json:"amount" example:"1000"`
+	Rewards      []RewardItem           `json:"rewards"`
 	MaxUses      int                    `json:"max_uses" example:"100"`
 	CurrentUses  int                    `json:"current_uses" example:"0"`
 	ValidFrom    string                 `json:"valid_from" example:"2024-01-01T00:00:00Z"`
 	ValidUntil   string                 `json:"valid_until" example:"2024-12-31T23:59:59Z"`
 	Status       string                 `json:"status" example:"active"`
 	Metadata     map[string]interface{} `json:"metadata"`
+	BatchID      string                 `json:"batch_id,omitempty" example:"influencer_2024_spring"`
+	CampaignID   string                 `json:"campaign_id,omitempty" example:"spring_2024"`
+	Eligibility  *EligibilityItem       `json:"eligibility,omitempty"`
 	CreatedAt    string                 `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt    string                 `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
@@ -87,12 +198,68 @@ type CodeItem struct {
 	CodeType     string                 `json:"code_type" example:"promotion"`
 	CurrencyType string                 `json:"currency_type" example:"free"`
 	Amount       string                 `json:"amount" example:"1000"`
+	Rewards      []RewardItem           `json:"rewards"`
 	MaxUses      int                    `json:"max_uses" example:"100"`
 	CurrentUses  int                    `json:"current_uses" example:"0"`
 	ValidFrom    string                 `json:"valid_from" example:"2024-01-01T00:00:00Z"`
 	ValidUntil   string                 `json:"valid_until" example:"2024-12-31T23:59:59Z"`
 	Status       string                 `json:"status" example:"active"`
 	Metadata     map[string]interface{} `json:"metadata"`
+	BatchID      string                 `json:"batch_id,omitempty" example:"influencer_2024_spring"`
+	CampaignID   string                 `json:"campaign_id,omitempty" example:"spring_2024"`
+	Eligibility  *EligibilityItem       `json:"eligibility,omitempty"`
 	CreatedAt    string                 `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt    string                 `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 }
+
+// ListRedemptionsResponse 引き換え履歴取得レスポンス
+// @Description 引き換え履歴取得レスポンス（新しい順）
+type ListRedemptionsResponse struct {
+	Redemptions []RedemptionItem `json:"redemptions"`
+	Total       int              `json:"total" example:"100"`
+	Limit       int              `json:"limit" example:"50"`
+	Offset      int              `json:"offset" example:"0"`
+}
+
+// RedemptionItem 引き換え履歴アイテム
+// @Description 引き換え履歴アイテム（transaction_idは先頭の報酬を付与したトランザクション、transaction_idsは報酬ごとに付与したすべてのトランザクション）
+type RedemptionItem struct {
+	RedemptionID   string   `json:"redemption_id" example:"red_123"`
+	Code           string   `json:"code" example:"PROMO2024"`
+	UserID         string   `json:"user_id" example:"user123"`
+	TransactionID  string   `json:"transaction_id" example:"txn_456"`
+	TransactionIDs []string `json:"transaction_ids" example:"txn_456,txn_457"`
+	RedeemedAt     string   `json:"redeemed_at" example:"2024-04-01T12:00:00Z"`
+}
+
+// CodeStatsResponse 引き換えコードの利用統計レスポンス
+// @Description 引き換えコードの利用統計レスポンス
+type CodeStatsResponse struct {
+	Code         string                `json:"code" example:"PROMO2024"`
+	MaxUses      int                   `json:"max_uses" example:"100"`
+	CurrentUses  int                   `json:"current_uses" example:"42"`
+	Redemptions  int                   `json:"redemptions" example:"42"`
+	UniqueUsers  int                   `json:"unique_users" example:"42"`
+	TotalGranted []RewardItem          `json:"total_granted"`
+	Daily        []DailyRedemptionItem `json:"daily"`
+}
+
+// DailyRedemptionItem 1日分の引き換え回数
+// @Description 1日分の引き換え回数（引き換えのない日は含まない）
+type DailyRedemptionItem struct {
+	Date  string `json:"date" example:"2024-04-01"`
+	Count int    `json:"count" example:"12"`
+}
+
+// ExpireCodesRequest 期限切れコードの一括失効リクエスト
+// @Description 期限切れコードの一括失効リクエスト（limitを省略した場合は500件）
+type ExpireCodesRequest struct {
+	Limit int `json:"limit,omitempty" example:"500"`
+}
+
+// ExpireCodesResponse 期限切れコードの一括失効レスポンス
+// @Description 期限切れコードの一括失効レスポンス（codesは有効期限の昇順）
+type ExpireCodesResponse struct {
+	ExpiredCodes int      `json:"expired_codes" example:"2"`
+	Codes        []string `json:"codes" example:"SPRING2024,SUMMER2024"`
+}
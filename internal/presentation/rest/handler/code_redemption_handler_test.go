@@ -5,12 +5,15 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	redemptionapp "gem-server/internal/application/code_redemption"
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/redemption_code"
+	"gem-server/internal/infrastructure/lockout"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 	restmiddleware "gem-server/internal/presentation/rest/middleware"
 
@@ -52,7 +55,7 @@ func TestCodeRedemptionHandler_RedeemCode(t *testing.T) {
 				// ユーザーはまだ引き換えていない
 				mrcr.On("HasUserRedeemed", mock.Anything, "TESTCODE123", "user123").Return(false, nil)
 				// コードを更新
-				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
 				// 既存の通貨に付与
 				existingCurrency := currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1)
 				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(existingCurrency, nil)
@@ -64,7 +67,7 @@ func TestCodeRedemptionHandler_RedeemCode(t *testing.T) {
 				// 引き換え履歴を記録
 				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
 				// トランザクション処理
-				mtx.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(*sql.Tx) error")).Return(nil)
+				mtx.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -79,6 +82,50 @@ func TestCodeRedemptionHandler_RedeemCode(t *testing.T) {
 				assert.Equal(t, "completed", response["status"])
 			},
 		},
+		{
+			name:        "正常系: 複数の報酬を持つコードの引き換え",
+			tokenUserID: "user123",
+			requestBody: map[string]interface{}{
+				"code": "BUNDLECODE",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				free, _ := redemption_code.NewReward(currency.CurrencyTypeFree, 100)
+				paid, _ := redemption_code.NewReward(currency.CurrencyTypePaid, 10)
+				code, _ := redemption_code.NewRedemptionCodeWithRewards(
+					"BUNDLECODE",
+					redemption_code.CodeTypeEvent,
+					[]redemption_code.Reward{free, paid},
+					1,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				mrcr.On("FindByCode", mock.Anything, "BUNDLECODE").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "BUNDLECODE", "user123").Return(false, nil)
+				mrcr.On("IncrementUses", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(nil, currency.ErrCurrencyNotFound)
+				mcr.On("Create", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 500, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil).Twice()
+				mrcr.On("SaveRedemption", mock.Anything, mock.AnythingOfType("*redemption_code.CodeRedemption")).Return(nil)
+				mtx.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response RedeemCodeResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.Rewards, 2)
+				assert.Equal(t, "free", response.Rewards[0].CurrencyType)
+				assert.Equal(t, "100", response.Rewards[0].Amount)
+				assert.Equal(t, "100", response.Rewards[0].BalanceAfter)
+				assert.Equal(t, "paid", response.Rewards[1].CurrencyType)
+				assert.Equal(t, "10", response.Rewards[1].Amount)
+				assert.Equal(t, "510", response.Rewards[1].BalanceAfter)
+				assert.Equal(t, response.Rewards[0].TransactionID, response.TransactionID)
+			},
+		},
 		{
 			name:        "異常系: user_idがトークンにない",
 			tokenUserID: "",
@@ -130,6 +177,33 @@ func TestCodeRedemptionHandler_RedeemCode(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest, // ErrUserAlreadyRedeemedは400を返す
 		},
+		{
+			name:        "異常系: キャンペーンの引き換え上限に到達",
+			tokenUserID: "user123",
+			requestBody: map[string]interface{}{
+				"code": "CAMPAIGN1",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				code := redemption_code.MustNewRedemptionCode(
+					"CAMPAIGN1",
+					redemption_code.CodeTypeEvent,
+					currency.CurrencyTypeFree,
+					100,
+					0,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					map[string]interface{}{},
+				)
+				_ = code.SetEligibility("campaign_001", redemption_code.Eligibility{MaxPerUserInCampaign: 1})
+				mrcr.On("FindByCode", mock.Anything, "CAMPAIGN1").Return(code, nil)
+				mrcr.On("HasUserRedeemed", mock.Anything, "CAMPAIGN1", "user123").Return(false, nil)
+				mrcr.On("CountUserCampaignRedemptions", mock.Anything, "campaign_001", "user123").Return(redemption_code.CampaignUsage{Redemptions: 1}, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "campaign_limit_reached")
+			},
+		},
 		{
 			name:        "異常系: コードが無効",
 			tokenUserID: "user123",
@@ -176,6 +250,9 @@ func TestCodeRedemptionHandler_RedeemCode(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -251,6 +328,138 @@ func TestCodeRedemptionHandler_CreateCode(t *testing.T) {
 				assert.Equal(t, "active", response["status"])
 			},
 		},
+		{
+			name: "正常系: 複数の報酬を持つコードを作成",
+			requestBody: map[string]interface{}{
+				"code":      "BUNDLECODE",
+				"code_type": "event",
+				"rewards": []map[string]interface{}{
+					{"currency_type": "free", "amount": "100"},
+					{"currency_type": "paid", "amount": "10"},
+				},
+				"max_uses":    1,
+				"valid_from":  time.Now().Format(time.RFC3339),
+				"valid_until": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response CreateCodeResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, []RewardItem{
+					{CurrencyType: "free", Amount: "100"},
+					{CurrencyType: "paid", Amount: "10"},
+				}, response.Rewards)
+			},
+		},
+		{
+			name: "正常系: 引き換え条件を持つコードを作成",
+			requestBody: map[string]interface{}{
+				"code":          "CAMPAIGN1",
+				"code_type":     "event",
+				"currency_type": "free",
+				"amount":        "100",
+				"valid_from":    time.Now().Format(time.RFC3339),
+				"valid_until":   time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+				"campaign_id":   "spring_2024",
+				"eligibility": map[string]interface{}{
+					"allowed_user_id_prefix":   "beta_",
+					"new_users_since":          "2024-04-01T00:00:00Z",
+					"max_per_user_in_campaign": 1,
+					"exclusive_in_campaign":    true,
+				},
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				mrcr.On("Create", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response CreateCodeResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "spring_2024", response.CampaignID)
+				assert.Equal(t, &EligibilityItem{
+					AllowedUserIDPrefix:  "beta_",
+					NewUsersSince:        "2024-04-01T00:00:00Z",
+					MaxPerUserInCampaign: 1,
+					ExclusiveInCampaign:  true,
+				}, response.Eligibility)
+			},
+		},
+		{
+			name: "異常系: キャンペーンIDなしでキャンペーン単位の条件を指定",
+			requestBody: map[string]interface{}{
+				"code":          "CAMPAIGN1",
+				"code_type":     "event",
+				"currency_type": "free",
+				"amount":        "100",
+				"valid_from":    time.Now().Format(time.RFC3339),
+				"valid_until":   time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+				"eligibility": map[string]interface{}{
+					"max_per_user_in_campaign": 1,
+				},
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "invalid_eligibility")
+			},
+		},
+		{
+			name: "異常系: new_users_sinceの形式が不正",
+			requestBody: map[string]interface{}{
+				"code":          "CAMPAIGN1",
+				"code_type":     "event",
+				"currency_type": "free",
+				"amount":        "100",
+				"valid_from":    time.Now().Format(time.RFC3339),
+				"valid_until":   time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+				"eligibility": map[string]interface{}{
+					"new_users_since": "2024-04-01",
+				},
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: 報酬の通貨タイプが重複",
+			requestBody: map[string]interface{}{
+				"code":      "BUNDLECODE",
+				"code_type": "event",
+				"rewards": []map[string]interface{}{
+					{"currency_type": "free", "amount": "100"},
+					{"currency_type": "free", "amount": "10"},
+				},
+				"valid_from":  time.Now().Format(time.RFC3339),
+				"valid_until": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "invalid_rewards")
+			},
+		},
+		{
+			name: "異常系: 報酬の金額が不正",
+			requestBody: map[string]interface{}{
+				"code":      "BUNDLECODE",
+				"code_type": "event",
+				"rewards": []map[string]interface{}{
+					{"currency_type": "free", "amount": "abc"},
+				},
+				"valid_from":  time.Now().Format(time.RFC3339),
+				"valid_until": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:        "異常系: 無効なリクエストボディ",
 			requestBody: nil,
@@ -326,6 +535,9 @@ func TestCodeRedemptionHandler_CreateCode(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -360,6 +572,247 @@ func TestCodeRedemptionHandler_CreateCode(t *testing.T) {
 	}
 }
 
+func TestCodeRedemptionHandler_GenerateCodes(t *testing.T) {
+	validBody := func() map[string]interface{} {
+		return map[string]interface{}{
+			"batch_id":      "influencer_2024_spring",
+			"count":         3,
+			"prefix":        "INF-",
+			"length":        10,
+			"code_type":     "promotion",
+			"currency_type": "free",
+			"amount":        "500",
+			"max_uses":      1,
+			"valid_from":    "2024-01-01T00:00:00Z",
+			"valid_until":   "2099-12-31T23:59:59Z",
+		}
+	}
+
+	tests := []struct {
+		name             string
+		body             func() map[string]interface{}
+		setupMock        func(*MockRedemptionCodeRepository, *MockTransactionManager)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "正常系: コードを一括生成",
+			body: validBody,
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				mtx.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindByBatchID", mock.Anything, "influencer_2024_spring").Return([]*redemption_code.RedemptionCode{}, nil)
+				mrcr.On("CreateBatch", mock.Anything, mock.AnythingOfType("[]*redemption_code.RedemptionCode")).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response GenerateCodesResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, "influencer_2024_spring", response.BatchID)
+				assert.Equal(t, 3, response.Count)
+				assert.Equal(t, "500", response.Amount)
+				require.Len(t, response.Codes, 3)
+				for _, code := range response.Codes {
+					assert.Regexp(t, `^INF-[A-Z2-9]{10}$`, code)
+				}
+			},
+		},
+		{
+			name: "異常系: バッチIDが使用済み",
+			body: validBody,
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				existing := redemption_code.MustNewRedemptionCode("INF-EXISTING", redemption_code.CodeTypePromotion, currency.CurrencyTypeFree, 500, 1, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), nil)
+				mtx.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+				mrcr.On("FindByBatchID", mock.Anything, "influencer_2024_spring").Return([]*redemption_code.RedemptionCode{existing}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "batch_already_exists")
+			},
+		},
+		{
+			name: "異常系: 不正なパターン",
+			body: func() map[string]interface{} {
+				body := validBody()
+				body["alphabet"] = "AB-C"
+				return body
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "invalid_code_pattern")
+			},
+		},
+		{
+			name: "異常系: 件数が上限を超える",
+			body: func() map[string]interface{} {
+				body := validBody()
+				body["count"] = redemptionapp.MaxGenerateCodesCount + 1
+				return body
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "invalid_batch_size")
+			},
+		},
+		{
+			name: "異常系: 有効期限が開始日時より前",
+			body: func() map[string]interface{} {
+				body := validBody()
+				body["valid_until"] = "2023-12-31T00:00:00Z"
+				return body
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: 金額の形式が不正",
+			body: func() map[string]interface{} {
+				body := validBody()
+				body["amount"] = "abc"
+				return body
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			if tt.setupMock != nil {
+				tt.setupMock(mockRedemptionCodeRepo, mockTxManager)
+			}
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			body, _ := json.Marshal(tt.body())
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/code_batches", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			err := middlewareFunc(handler.GenerateCodes)(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionHandler_ExportBatchCodes(t *testing.T) {
+	tests := []struct {
+		name             string
+		batchID          string
+		setupMock        func(*MockRedemptionCodeRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "正常系: CSVをダウンロード",
+			batchID: "influencer_2024_spring",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				code := redemption_code.MustNewRedemptionCode(
+					"INF-7KQ2M9XHPA",
+					redemption_code.CodeTypePromotion,
+					currency.CurrencyTypeFree,
+					500,
+					1,
+					time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
+					nil,
+				)
+				code.SetBatchID("influencer_2024_spring")
+				mrcr.On("FindByBatchID", mock.Anything, "influencer_2024_spring").Return([]*redemption_code.RedemptionCode{code}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+				assert.Equal(t, `attachment; filename="influencer_2024_spring.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+				lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+				require.Len(t, lines, 2)
+				assert.True(t, strings.HasPrefix(lines[0], "code,batch_id,"))
+				assert.True(t, strings.HasPrefix(lines[1], "INF-7KQ2M9XHPA,influencer_2024_spring,promotion,free,500,"))
+			},
+		},
+		{
+			name:    "異常系: バッチが見つからない",
+			batchID: "unknown",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByBatchID", mock.Anything, "unknown").Return([]*redemption_code.RedemptionCode{}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "batch_not_found")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			tt.setupMock(mockRedemptionCodeRepo)
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/code_batches/"+tt.batchID+"/export", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("batch_id")
+			c.SetParamValues(tt.batchID)
+
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			err := middlewareFunc(handler.ExportBatchCodes)(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+		})
+	}
+}
+
 func TestCodeRedemptionHandler_DeleteCode(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -450,6 +903,9 @@ func TestCodeRedemptionHandler_DeleteCode(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -479,39 +935,392 @@ func TestCodeRedemptionHandler_DeleteCode(t *testing.T) {
 	}
 }
 
-func TestCodeRedemptionHandler_GetCode(t *testing.T) {
+func TestCodeRedemptionHandler_UpdateCode(t *testing.T) {
+	newCode := func(validUntil time.Time, status redemption_code.CodeStatus) *redemption_code.RedemptionCode {
+		code := redemption_code.MustNewRedemptionCode(
+			"UPDATECODE",
+			redemption_code.CodeTypePromotion,
+			currency.CurrencyTypePaid,
+			1000,
+			100,
+			time.Now().Add(-48*time.Hour),
+			validUntil,
+			map[string]interface{}{},
+		)
+		code.SetStatus(status)
+		return code
+	}
+
 	tests := []struct {
 		name             string
 		code             string
-		setupMock        func(*MockCurrencyRepository, *MockTransactionRepository, *MockRedemptionCodeRepository, *MockTransactionManager)
+		requestBody      map[string]interface{}
+		anonymous        bool // APIKeyMiddlewareを経由していない
+		setupMock        func(*MockRedemptionCodeRepository, *MockTransactionManager)
 		expectedStatus   int
 		validateResponse func(*testing.T, *httptest.ResponseRecorder)
 	}{
 		{
-			name: "正常系: コード取得成功",
-			code: "GETCODE123",
-			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
-				code := redemption_code.MustNewRedemptionCode(
-					"GETCODE123",
-					redemption_code.CodeTypePromotion,
-					currency.CurrencyTypePaid,
-					1000,
-					100,
-					time.Now().Add(-24*time.Hour),
-					time.Now().Add(24*time.Hour),
-					map[string]interface{}{"campaign_id": "campaign_001"},
-				)
-				code.SetCurrentUses(5)
-				mrcr.On("FindByCode", mock.Anything, "GETCODE123").Return(code, nil)
+			name: "正常系: 有効期限を延長して再有効化",
+			code: "UPDATECODE",
+			requestBody: map[string]interface{}{
+				"status":      "active",
+				"valid_until": "2099-12-31T23:59:59Z",
+				"max_uses":    200,
+				"reason":      "campaign extended",
+			},
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "UPDATECODE").Return(newCode(time.Now().Add(-time.Hour), redemption_code.CodeStatusExpired), nil)
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+				mrcr.On("UpdateSettings", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("SaveAuditLog", mock.Anything, mock.MatchedBy(func(l *redemption_code.CodeAuditLog) bool {
+					return l.Requester() == "ops-tool"
+				})).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				var response map[string]interface{}
+				var response UpdateCodeResponse
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				require.NoError(t, err)
-				assert.Equal(t, "GETCODE123", response["code"])
-				assert.Equal(t, "promotion", response["code_type"])
-				assert.Equal(t, "paid", response["currency_type"])
+				assert.Equal(t, "active", response.Status)
+				assert.Equal(t, "2099-12-31T23:59:59Z", response.ValidUntil)
+				assert.Equal(t, 200, response.MaxUses)
+				assert.NotEmpty(t, response.AuditID)
+				require.Len(t, response.Changes, 3)
+				assert.Equal(t, CodeChangeItem{Field: "max_uses", From: "100", To: "200"}, response.Changes[2])
+			},
+		},
+		{
+			name: "異常系: 有効期限を過ぎたコードを再有効化",
+			code: "UPDATECODE",
+			requestBody: map[string]interface{}{
+				"status": "active",
+			},
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "UPDATECODE").Return(newCode(time.Now().Add(-time.Hour), redemption_code.CodeStatusExpired), nil)
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "invalid_code_update")
+			},
+		},
+		{
+			name: "異常系: 管理APIキーの名前がない",
+			code: "UPDATECODE",
+			requestBody: map[string]interface{}{
+				"status": "disabled",
+			},
+			anonymous:      true,
+			setupMock:      func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: 無効な日付フォーマット",
+			code: "UPDATECODE",
+			requestBody: map[string]interface{}{
+				"valid_until": "2099-12-31",
+			},
+			setupMock:      func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: コードが見つからない",
+			code: "NOTFOUNDCODE",
+			requestBody: map[string]interface{}{
+				"status": "disabled",
+			},
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				mrcr.On("FindByCode", mock.Anything, "NOTFOUNDCODE").Return(nil, redemption_code.ErrCodeNotFound)
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			tt.setupMock(mockRedemptionCodeRepo, mockTxManager)
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			body, _ := json.Marshal(tt.requestBody)
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/admin/codes/"+tt.code, bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("code")
+			c.SetParamValues(tt.code)
+			if !tt.anonymous {
+				c.Set("api_key_name", "ops-tool")
+			}
+
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.UpdateCode(c)
+			})
+			err := handlerFunc(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionHandler_ExpireCodes(t *testing.T) {
+	expiredCode := func() *redemption_code.RedemptionCode {
+		return redemption_code.MustNewRedemptionCode(
+			"EXPIREDCODE",
+			redemption_code.CodeTypePromotion,
+			currency.CurrencyTypeFree,
+			100,
+			0,
+			time.Now().Add(-48*time.Hour),
+			time.Now().Add(-time.Hour),
+			map[string]interface{}{},
+		)
+	}
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		setupMock        func(*MockRedemptionCodeRepository, *MockTransactionManager, *MockExpiryLock)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "正常系: 期限切れのコードを失効",
+			requestBody: `{"limit":10}`,
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager, mel *MockExpiryLock) {
+				mel.On("TryLock", mock.Anything).Return(true, nil)
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+				mrcr.On("FindExpiredActive", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*redemption_code.RedemptionCode{expiredCode()}, nil).Once()
+				mrcr.On("FindExpiredActive", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*redemption_code.RedemptionCode{}, nil).Once()
+				mrcr.On("Update", mock.Anything, mock.AnythingOfType("*redemption_code.RedemptionCode")).Return(nil)
+				mrcr.On("SaveAuditLog", mock.Anything, mock.MatchedBy(func(l *redemption_code.CodeAuditLog) bool {
+					return l.Action() == redemption_code.AuditActionExpire && l.Requester() == "ops-tool"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response ExpireCodesResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, 1, response.ExpiredCodes)
+				assert.Equal(t, []string{"EXPIREDCODE"}, response.Codes)
+			},
+		},
+		{
+			name:        "正常系: リクエストボディなし",
+			requestBody: "",
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager, mel *MockExpiryLock) {
+				mel.On("TryLock", mock.Anything).Return(true, nil)
+				mtx.On("WithTransaction", mock.Anything, mock.Anything).Return(nil)
+				mrcr.On("FindExpiredActive", mock.Anything, mock.AnythingOfType("time.Time"), 1).Return([]*redemption_code.RedemptionCode{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response ExpireCodesResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, 0, response.ExpiredCodes)
+				assert.Empty(t, response.Codes)
+			},
+		},
+		{
+			name:        "異常系: 他のインスタンスで実行中",
+			requestBody: `{}`,
+			setupMock: func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager, mel *MockExpiryLock) {
+				mel.On("TryLock", mock.Anything).Return(false, nil)
+			},
+			expectedStatus: http.StatusConflict,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "expiry_sweep_in_progress")
+			},
+		},
+		{
+			name:           "異常系: limitが負の値",
+			requestBody:    `{"limit":-1}`,
+			setupMock:      func(mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager, mel *MockExpiryLock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			mockTxManager := new(MockTransactionManager)
+			mockExpiryLock := new(MockExpiryLock)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			tt.setupMock(mockRedemptionCodeRepo, mockTxManager, mockExpiryLock)
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				mockExpiryLock,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/codes/expire_sweep", strings.NewReader(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("api_key_name", "ops-tool")
+
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.ExpireCodes(c)
+			})
+			err := handlerFunc(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+			mockExpiryLock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionHandler_RedemptionLockout(t *testing.T) {
+	e := echo.New()
+	mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	metrics, _ := otelinfra.NewMetrics("test")
+
+	mockRedemptionCodeRepo.On("FindByCode", mock.Anything, "WRONGCODE").Return(nil, redemption_code.ErrCodeNotFound)
+
+	tracker := lockout.NewMemoryFailureTracker(redemption_code.LockoutPolicy{
+		MaxFailures: 2,
+		Window:      time.Minute,
+		Duration:    time.Hour,
+	})
+	appService := redemptionapp.NewCodeRedemptionApplicationService(
+		new(MockCurrencyRepository),
+		new(MockTransactionRepository),
+		mockRedemptionCodeRepo,
+		new(MockTransactionManager),
+		idgen.NewUUIDv7Generator(),
+		tracker,
+		nil,
+		logger,
+		metrics,
+	)
+	handler := NewCodeRedemptionHandler(appService)
+	middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+
+	redeem := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(RedeemCodeRequest{Code: "WRONGCODE"})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/codes/redeem", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user_id", "user123")
+		if err := middlewareFunc(handler.RedeemCode)(c); err != nil {
+			e.HTTPErrorHandler(err, c)
+		}
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNotFound, redeem().Code)
+	assert.Equal(t, http.StatusNotFound, redeem().Code)
+
+	rec := redeem()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+	mockRedemptionCodeRepo.AssertNumberOfCalls(t, "FindByCode", 2)
+
+	// 管理APIでロックアウトを解除
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/users/user123/redemption_lockout", nil)
+	rec = httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("user_id")
+	c.SetParamValues("user123")
+	require.NoError(t, middlewareFunc(handler.ClearRedemptionLockout)(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "user123", response["user_id"])
+	assert.NotEmpty(t, response["cleared_at"])
+
+	assert.Equal(t, http.StatusNotFound, redeem().Code)
+}
+
+func TestCodeRedemptionHandler_GetCode(t *testing.T) {
+	tests := []struct {
+		name             string
+		code             string
+		setupMock        func(*MockCurrencyRepository, *MockTransactionRepository, *MockRedemptionCodeRepository, *MockTransactionManager)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "正常系: コード取得成功",
+			code: "GETCODE123",
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+				code := redemption_code.MustNewRedemptionCode(
+					"GETCODE123",
+					redemption_code.CodeTypePromotion,
+					currency.CurrencyTypePaid,
+					1000,
+					100,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					map[string]interface{}{"campaign_id": "campaign_001"},
+				)
+				code.SetCurrentUses(5)
+				mrcr.On("FindByCode", mock.Anything, "GETCODE123").Return(code, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response map[string]interface{}
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				assert.Equal(t, "GETCODE123", response["code"])
+				assert.Equal(t, "promotion", response["code_type"])
+				assert.Equal(t, "paid", response["currency_type"])
 				assert.Equal(t, "1000", response["amount"])
 				assert.Equal(t, float64(100), response["max_uses"])
 				assert.Equal(t, float64(5), response["current_uses"])
@@ -554,6 +1363,9 @@ func TestCodeRedemptionHandler_GetCode(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -617,7 +1429,7 @@ func TestCodeRedemptionHandler_ListCodes(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 25, nil)
+				mrcr.On("FindByFilter", mock.Anything, redemption_code.Filter{Sort: redemption_code.SortCreatedAtDesc}, 10, 0).Return(codes, 25, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -633,85 +1445,62 @@ func TestCodeRedemptionHandler_ListCodes(t *testing.T) {
 			},
 		},
 		{
-			name:        "正常系: フィルタリング（status=active）",
-			queryParams: "limit=10&offset=0&status=active",
+			name:        "正常系: 検索条件をリポジトリに渡す",
+			queryParams: "limit=10&offset=0&status=active&code_type=promotion&currency_type=free&campaign_id=spring_2024&code_prefix=INF-&valid_from=2024-04-01T00:00:00Z&valid_until=2024-04-30T00:00:00Z&sort=code_asc",
 			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
 				codes := []*redemption_code.RedemptionCode{
 					redemption_code.MustNewRedemptionCode(
-						"ACTIVECODE",
+						"INF-ACTIVE",
 						redemption_code.CodeTypePromotion,
-						currency.CurrencyTypePaid,
+						currency.CurrencyTypeFree,
 						1000,
 						100,
 						time.Now().Add(-24*time.Hour),
 						time.Now().Add(24*time.Hour),
-						map[string]interface{}{},
-					),
-					redemption_code.MustNewRedemptionCode(
-						"EXPIREDCODE",
-						redemption_code.CodeTypePromotion,
-						currency.CurrencyTypePaid,
-						1000,
-						100,
-						time.Now().Add(-48*time.Hour),
-						time.Now().Add(-24*time.Hour),
-						map[string]interface{}{},
+						map[string]interface{}{"campaign_id": "spring_2024"},
 					),
 				}
-				status, _ := redemption_code.NewCodeStatus("expired")
-				codes[1].SetStatus(status)
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 2, nil)
+				validFrom := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+				validUntil := time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC)
+				mrcr.On("FindByFilter", mock.Anything, mock.MatchedBy(func(f redemption_code.Filter) bool {
+					return f.Status == redemption_code.CodeStatusActive &&
+						f.CodeType == redemption_code.CodeTypePromotion &&
+						f.CurrencyType == currency.CurrencyTypeFree &&
+						f.CampaignID == "spring_2024" &&
+						f.CodePrefix == "INF-" &&
+						f.ValidFrom.Equal(validFrom) &&
+						f.ValidUntil.Equal(validUntil) &&
+						f.Sort == redemption_code.SortCodeAsc
+				}), 10, 0).Return(codes, 31, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				var response map[string]interface{}
+				var response ListCodesResponse
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				require.NoError(t, err)
-				codes, ok := response["codes"].([]interface{})
-				require.True(t, ok)
-				// activeのみがフィルタリングされる
-				assert.Equal(t, 1, len(codes))
+				require.Len(t, response.Codes, 1)
+				assert.Equal(t, "INF-ACTIVE", response.Codes[0].Code)
+				// 総件数は検索条件に一致する件数
+				assert.Equal(t, 31, response.Total)
 			},
 		},
 		{
-			name:        "正常系: フィルタリング（code_type=promotion）",
-			queryParams: "limit=10&offset=0&code_type=promotion",
+			name:        "異常系: 不正なステータス",
+			queryParams: "status=deleted",
 			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
-				codes := []*redemption_code.RedemptionCode{
-					redemption_code.MustNewRedemptionCode(
-						"PROMOCODE",
-						redemption_code.CodeTypePromotion,
-						currency.CurrencyTypePaid,
-						1000,
-						100,
-						time.Now().Add(-24*time.Hour),
-						time.Now().Add(24*time.Hour),
-						map[string]interface{}{},
-					),
-					redemption_code.MustNewRedemptionCode(
-						"GIFTCODE",
-						redemption_code.CodeTypeGift,
-						currency.CurrencyTypeFree,
-						500,
-						0,
-						time.Now().Add(-12*time.Hour),
-						time.Now().Add(12*time.Hour),
-						map[string]interface{}{},
-					),
-				}
-				mrcr.On("FindAll", mock.Anything, 10, 0).Return(codes, 2, nil)
 			},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusBadRequest,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				var response map[string]interface{}
-				err := json.Unmarshal(rec.Body.Bytes(), &response)
-				require.NoError(t, err)
-				codes, ok := response["codes"].([]interface{})
-				require.True(t, ok)
-				// promotionのみがフィルタリングされる
-				assert.Equal(t, 1, len(codes))
+				assert.Contains(t, rec.Body.String(), "invalid_filter")
 			},
 		},
+		{
+			name:        "異常系: 有効期間の日付フォーマットが不正",
+			queryParams: "valid_from=2024-04-01",
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:        "異常系: 無効なlimitパラメータ",
 			queryParams: "limit=invalid&offset=0",
@@ -731,7 +1520,7 @@ func TestCodeRedemptionHandler_ListCodes(t *testing.T) {
 			queryParams: "",
 			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mrcr *MockRedemptionCodeRepository, mtx *MockTransactionManager) {
 				codes := []*redemption_code.RedemptionCode{}
-				mrcr.On("FindAll", mock.Anything, 50, 0).Return(codes, 0, nil)
+				mrcr.On("FindByFilter", mock.Anything, redemption_code.Filter{Sort: redemption_code.SortCreatedAtDesc}, 50, 0).Return(codes, 0, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -764,6 +1553,9 @@ func TestCodeRedemptionHandler_ListCodes(t *testing.T) {
 				mockTransactionRepo,
 				mockRedemptionCodeRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
 				logger,
 				metrics,
 			)
@@ -794,3 +1586,307 @@ func TestCodeRedemptionHandler_ListCodes(t *testing.T) {
 		})
 	}
 }
+
+func TestCodeRedemptionHandler_ListCodeRedemptions(t *testing.T) {
+	redeemedAt := time.Date(2024, 4, 2, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		code             string
+		query            string
+		setupMock        func(*MockRedemptionCodeRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "正常系: 引き換え履歴を取得",
+			code:  "HISTORY123",
+			query: "?limit=10&offset=20",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				code := redemption_code.MustNewRedemptionCode(
+					"HISTORY123",
+					redemption_code.CodeTypeEvent,
+					currency.CurrencyTypeFree,
+					100,
+					0,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				redemption := redemption_code.NewCodeRedemption("red1", "HISTORY123", "user123", "txn1")
+				redemption.SetRedeemedAt(redeemedAt)
+				redemption.SetTransactionIDs([]string{"txn1", "txn2"})
+				mrcr.On("FindByCode", mock.Anything, "HISTORY123").Return(code, nil)
+				mrcr.On("FindRedemptionsByCode", mock.Anything, "HISTORY123", 10, 20).
+					Return([]*redemption_code.CodeRedemption{redemption}, 21, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response ListRedemptionsResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, 21, response.Total)
+				assert.Equal(t, 10, response.Limit)
+				assert.Equal(t, 20, response.Offset)
+				require.Len(t, response.Redemptions, 1)
+				assert.Equal(t, RedemptionItem{
+					RedemptionID:   "red1",
+					Code:           "HISTORY123",
+					UserID:         "user123",
+					TransactionID:  "txn1",
+					TransactionIDs: []string{"txn1", "txn2"},
+					RedeemedAt:     "2024-04-02T10:00:00Z",
+				}, response.Redemptions[0])
+			},
+		},
+		{
+			name:           "異常系: 不正なlimit",
+			code:           "HISTORY123",
+			query:          "?limit=abc",
+			setupMock:      func(mrcr *MockRedemptionCodeRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: コードが見つからない",
+			code: "NOTFOUNDCODE",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "NOTFOUNDCODE").Return(nil, redemption_code.ErrCodeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			tt.setupMock(mockRedemptionCodeRepo)
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/codes/"+tt.code+"/redemptions"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("code")
+			c.SetParamValues(tt.code)
+
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.ListCodeRedemptions(c)
+			})
+			err := handlerFunc(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionHandler_ListMyRedemptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		setupMock      func(*MockRedemptionCodeRepository)
+		expectedStatus int
+		expectedTotal  int
+	}{
+		{
+			name:   "正常系: 自分の引き換え履歴を取得",
+			userID: "user123",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindRedemptionsByUserID", mock.Anything, "user123", 50, 0).
+					Return([]*redemption_code.CodeRedemption{
+						redemption_code.NewCodeRedemption("red2", "CODE2", "user123", "txn2"),
+						redemption_code.NewCodeRedemption("red1", "CODE1", "user123", "txn1"),
+					}, 2, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTotal:  2,
+		},
+		{
+			name:           "異常系: トークンにuser_idがない",
+			setupMock:      func(mrcr *MockRedemptionCodeRepository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			tt.setupMock(mockRedemptionCodeRepo)
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/me/redemptions", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.userID != "" {
+				c.Set("user_id", tt.userID)
+			}
+
+			err := handler.ListMyRedemptions(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var response ListRedemptionsResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedTotal, response.Total)
+				assert.Len(t, response.Redemptions, tt.expectedTotal)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCodeRedemptionHandler_GetCodeStats(t *testing.T) {
+	tests := []struct {
+		name             string
+		code             string
+		setupMock        func(*MockRedemptionCodeRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "正常系: 利用統計を取得",
+			code: "STATS123",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				code := redemption_code.MustNewRedemptionCode(
+					"STATS123",
+					redemption_code.CodeTypeEvent,
+					currency.CurrencyTypeFree,
+					100,
+					10,
+					time.Now().Add(-24*time.Hour),
+					time.Now().Add(24*time.Hour),
+					nil,
+				)
+				code.SetCurrentUses(3)
+				mrcr.On("FindByCode", mock.Anything, "STATS123").Return(code, nil)
+				mrcr.On("GetCodeStats", mock.Anything, "STATS123").Return(redemption_code.CodeStats{
+					Redemptions: 3,
+					UniqueUsers: 3,
+					Granted: []redemption_code.GrantedTotal{
+						{CurrencyType: currency.CurrencyTypeFree, Amount: 300},
+					},
+					Daily: []redemption_code.DailyRedemptions{
+						{Date: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Count: 1},
+						{Date: time.Date(2024, 4, 2, 0, 0, 0, 0, time.UTC), Count: 2},
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response CodeStatsResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, CodeStatsResponse{
+					Code:         "STATS123",
+					MaxUses:      10,
+					CurrentUses:  3,
+					Redemptions:  3,
+					UniqueUsers:  3,
+					TotalGranted: []RewardItem{{CurrencyType: "free", Amount: "300"}},
+					Daily: []DailyRedemptionItem{
+						{Date: "2024-04-01", Count: 1},
+						{Date: "2024-04-02", Count: 2},
+					},
+				}, response)
+			},
+		},
+		{
+			name: "異常系: コードが見つからない",
+			code: "NOTFOUNDCODE",
+			setupMock: func(mrcr *MockRedemptionCodeRepository) {
+				mrcr.On("FindByCode", mock.Anything, "NOTFOUNDCODE").Return(nil, redemption_code.ErrCodeNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockRedemptionCodeRepo := new(MockRedemptionCodeRepository)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+
+			tt.setupMock(mockRedemptionCodeRepo)
+
+			appService := redemptionapp.NewCodeRedemptionApplicationService(
+				new(MockCurrencyRepository),
+				new(MockTransactionRepository),
+				mockRedemptionCodeRepo,
+				new(MockTransactionManager),
+				idgen.NewUUIDv7Generator(),
+				nil,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCodeRedemptionHandler(appService)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/codes/"+tt.code+"/stats", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("code")
+			c.SetParamValues(tt.code)
+
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.GetCodeStats(c)
+			})
+			err := handlerFunc(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+			mockRedemptionCodeRepo.AssertExpectations(t)
+		})
+	}
+}
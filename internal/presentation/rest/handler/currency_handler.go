@@ -23,7 +23,7 @@ func NewCurrencyHandler(currencyService *currencyapp.CurrencyApplicationService)
 
 // GetBalance 残高取得ハンドラー（ユーザーAPI用）
 // @Summary 残高を取得
-// @Description 自分の通貨残高を取得します
+// @Description 自分の通貨残高と、有効期限付き無償通貨の失効予定を取得します
 // @Tags currency
 // @Accept json
 // @Produce json
@@ -48,13 +48,7 @@ func (h *CurrencyHandler) GetBalance(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, BalanceResponse{
-		UserID: resp.UserID,
-		Balances: BalanceItem{
-			Paid: strconv.FormatInt(resp.Balances["paid"], 10),
-			Free: strconv.FormatInt(resp.Balances["free"], 10),
-		},
-	})
+	return c.JSON(http.StatusOK, newBalanceResponse(resp))
 }
 
 // GetBalanceAdmin 残高取得ハンドラー（管理API用）
@@ -68,6 +62,7 @@ func (h *CurrencyHandler) GetBalance(c echo.Context) error {
 // @Success 200 {object} BalanceResponse "残高取得成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Router /admin/users/{user_id}/balance [get]
 func (h *CurrencyHandler) GetBalanceAdmin(c echo.Context) error {
 	userID := c.Param("user_id")
@@ -84,27 +79,45 @@ func (h *CurrencyHandler) GetBalanceAdmin(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, BalanceResponse{
+	return c.JSON(http.StatusOK, newBalanceResponse(resp))
+}
+
+// newBalanceResponse 残高取得結果をレスポンス形式に変換
+func newBalanceResponse(resp *currencyapp.GetBalanceResponse) BalanceResponse {
+	expirations := make([]ExpirationItem, len(resp.Expirations))
+	for i, expiration := range resp.Expirations {
+		expirations[i] = ExpirationItem{
+			CurrencyType: expiration.CurrencyType,
+			Amount:       strconv.FormatInt(expiration.Amount, 10),
+			ExpiresAt:    expiration.ExpiresAt,
+		}
+	}
+
+	return BalanceResponse{
 		UserID: resp.UserID,
 		Balances: BalanceItem{
 			Paid: strconv.FormatInt(resp.Balances["paid"], 10),
 			Free: strconv.FormatInt(resp.Balances["free"], 10),
 		},
-	})
+		Expirations: expirations,
+	}
 }
 
 // GrantCurrency 通貨付与ハンドラー（管理API用）
 // @Summary 通貨を付与（管理API）
-// @Description 指定されたユーザーに無償通貨を付与します
+// @Description 指定されたユーザーに通貨を付与します。expires_atを指定すると有効期限付きの無償通貨として付与します
 // @Tags admin
 // @Accept json
 // @Produce json
 // @Param user_id path string true "ユーザーID" example(user123)
 // @Param X-API-Key header string true "APIキー"
+// @Param Idempotency-Key header string false "冪等性キー（同じキーでの再送は初回の結果を返す）"
 // @Param request body GrantRequest true "通貨付与リクエスト"
 // @Success 200 {object} GrantResponse "通貨付与成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 409 {object} ErrorResponse "冪等性キーが異なるリクエスト内容で使用済み"
 // @Router /admin/users/{user_id}/grant [post]
 func (h *CurrencyHandler) GrantCurrency(c echo.Context) error {
 	userID := c.Param("user_id")
@@ -124,12 +137,14 @@ func (h *CurrencyHandler) GrantCurrency(c echo.Context) error {
 	}
 
 	req := &currencyapp.GrantRequest{
-		UserID:       userID,
-		CurrencyType: reqBody.CurrencyType,
-		Amount:       amount,
-		Reason:       reqBody.Reason,
-		Requester:    reqBody.Requester,
-		Metadata:     reqBody.Metadata,
+		UserID:         userID,
+		CurrencyType:   reqBody.CurrencyType,
+		Amount:         amount,
+		Reason:         reqBody.Reason,
+		Requester:      apiKeyName(c),
+		ExpiresAt:      reqBody.ExpiresAt,
+		IdempotencyKey: c.Request().Header.Get("Idempotency-Key"),
+		Metadata:       reqBody.Metadata,
 	}
 
 	resp, err := h.currencyService.Grant(c.Request().Context(), req)
@@ -152,11 +167,13 @@ func (h *CurrencyHandler) GrantCurrency(c echo.Context) error {
 // @Produce json
 // @Param user_id path string true "ユーザーID" example(user123)
 // @Param X-API-Key header string true "APIキー"
+// @Param Idempotency-Key header string false "冪等性キー（同じキーでの再送は初回の結果を返す）"
 // @Param request body ConsumeRequest true "通貨消費リクエスト"
 // @Success 200 {object} ConsumeResponse "通貨消費成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
-// @Failure 409 {object} ErrorResponse "残高不足"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 409 {object} ErrorResponse "残高不足、または冪等性キーが異なるリクエスト内容で使用済み"
 // @Router /admin/users/{user_id}/consume [post]
 func (h *CurrencyHandler) ConsumeCurrency(c echo.Context) error {
 	userID := c.Param("user_id")
@@ -176,13 +193,14 @@ func (h *CurrencyHandler) ConsumeCurrency(c echo.Context) error {
 	}
 
 	req := &currencyapp.ConsumeRequest{
-		UserID:       userID,
-		CurrencyType: reqBody.CurrencyType,
-		Amount:       amount,
-		ItemID:       reqBody.ItemID,
-		UsePriority:  reqBody.UsePriority,
-		Requester:    reqBody.Requester,
-		Metadata:     reqBody.Metadata,
+		UserID:         userID,
+		CurrencyType:   reqBody.CurrencyType,
+		Amount:         amount,
+		ItemID:         reqBody.ItemID,
+		UsePriority:    reqBody.UsePriority,
+		Requester:      apiKeyName(c),
+		IdempotencyKey: c.Request().Header.Get("Idempotency-Key"),
+		Metadata:       reqBody.Metadata,
 	}
 
 	var resp *currencyapp.ConsumeResponse
@@ -224,3 +242,188 @@ func (h *CurrencyHandler) ConsumeCurrency(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, consumeResp)
 }
+
+// RefundTransaction 返金ハンドラー（管理API用）
+// @Summary 消費トランザクションを返金（管理API）
+// @Description 消費トランザクションの金額を元の通貨タイプに返金します。優先順位制御で分割された消費はベースIDを指定すると一括で返金されます
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param transaction_id path string true "返金対象のトランザクションID" example(txn_456)
+// @Param X-API-Key header string true "APIキー"
+// @Param request body RefundRequest false "返金リクエスト"
+// @Success 200 {object} RefundResponse "返金成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Failure 404 {object} ErrorResponse "トランザクションが見つからない"
+// @Failure 409 {object} ErrorResponse "返金済み"
+// @Router /admin/transactions/{transaction_id}/refund [post]
+func (h *CurrencyHandler) RefundTransaction(c echo.Context) error {
+	transactionID := c.Param("transaction_id")
+	if transactionID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "transaction_id is required")
+	}
+
+	var reqBody RefundRequest
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	req := &currencyapp.RefundRequest{
+		TransactionID: transactionID,
+		Reason:        reqBody.Reason,
+		Requester:     apiKeyName(c),
+		Metadata:      reqBody.Metadata,
+	}
+
+	resp, err := h.currencyService.Refund(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	details := make([]RefundDetail, len(resp.RefundDetails))
+	for i, detail := range resp.RefundDetails {
+		details[i] = RefundDetail{
+			TransactionID:         detail.TransactionID,
+			OriginalTransactionID: detail.OriginalTransactionID,
+			CurrencyType:          detail.CurrencyType,
+			Amount:                strconv.FormatInt(detail.Amount, 10),
+			BalanceBefore:         strconv.FormatInt(detail.BalanceBefore, 10),
+			BalanceAfter:          strconv.FormatInt(detail.BalanceAfter, 10),
+		}
+	}
+
+	return c.JSON(http.StatusOK, RefundResponse{
+		TransactionID:         resp.TransactionID,
+		OriginalTransactionID: resp.OriginalTransactionID,
+		UserID:                resp.UserID,
+		RefundDetails:         details,
+		TotalRefunded:         strconv.FormatInt(resp.TotalRefunded, 10),
+		Status:                resp.Status,
+	})
+}
+
+// TransferCurrency 通貨譲渡ハンドラー（ユーザーAPI用）
+// @Summary 通貨を他のユーザーに譲渡
+// @Description 自分の通貨を他のユーザーに譲渡します。送信側の消費と受信側の付与は同一トランザクションで行われます。譲渡できる通貨タイプはサーバー設定で決まります（通常は無償通貨のみ）
+// @Tags currency
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param request body TransferRequest true "通貨譲渡リクエスト"
+// @Success 200 {object} TransferResponse "譲渡成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト、譲渡できない通貨タイプ、または自分自身への譲渡"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 404 {object} ErrorResponse "譲渡先のユーザーが存在しない"
+// @Failure 409 {object} ErrorResponse "残高不足"
+// @Router /me/transfers [post]
+func (h *CurrencyHandler) TransferCurrency(c echo.Context) error {
+	// トークンからuser_idを取得
+	userID, ok := c.Get("user_id").(string)
+	if !ok || userID == "" {
+		return echo.NewHTTPError(http.StatusUnauthorized, "user_id not found in token")
+	}
+
+	var reqBody TransferRequest
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+	if reqBody.ToUserID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "to_user_id is required")
+	}
+
+	// 金額をint64に変換
+	amount, err := strconv.ParseInt(reqBody.Amount, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid amount format")
+	}
+
+	req := &currencyapp.TransferRequest{
+		FromUserID:   userID,
+		ToUserID:     reqBody.ToUserID,
+		CurrencyType: reqBody.CurrencyType,
+		Amount:       amount,
+		Message:      reqBody.Message,
+		Metadata:     reqBody.Metadata,
+	}
+
+	resp, err := h.currencyService.Transfer(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, TransferResponse{
+		TransferID:            resp.TransferID,
+		FromUserID:            resp.FromUserID,
+		ToUserID:              resp.ToUserID,
+		CurrencyType:          resp.CurrencyType,
+		Amount:                strconv.FormatInt(resp.Amount, 10),
+		SenderTransactionID:   resp.SenderTransactionID,
+		ReceiverTransactionID: resp.ReceiverTransactionID,
+		BalanceAfter:          strconv.FormatInt(resp.BalanceAfter, 10),
+		Status:                resp.Status,
+	})
+}
+
+// CompensateCurrency 補填（調整）ハンドラー（管理API用）
+// @Summary 通貨を補填・回収（管理API）
+// @Description 符号付きの金額で残高を調整します。正の値で補填、負の値で回収し、回収時はマイナス残高を許可します。reasonは必須です
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "ユーザーID" example(user123)
+// @Param X-API-Key header string true "APIキー"
+// @Param request body CompensateRequest true "補填リクエスト"
+// @Success 200 {object} CompensateResponse "調整成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Router /admin/users/{user_id}/compensate [post]
+func (h *CurrencyHandler) CompensateCurrency(c echo.Context) error {
+	userID := c.Param("user_id")
+	if userID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "user_id is required")
+	}
+
+	var reqBody CompensateRequest
+	if err := c.Bind(&reqBody); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid request body")
+	}
+
+	// 金額をint64に変換（符号付き）
+	amount, err := strconv.ParseInt(reqBody.Amount, 10, 64)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid amount format")
+	}
+
+	req := &currencyapp.CompensateRequest{
+		UserID:       userID,
+		CurrencyType: reqBody.CurrencyType,
+		Amount:       amount,
+		Reason:       reqBody.Reason,
+		Requester:    apiKeyName(c),
+		Metadata:     reqBody.Metadata,
+	}
+
+	resp, err := h.currencyService.Compensate(c.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, CompensateResponse{
+		TransactionID: resp.TransactionID,
+		UserID:        resp.UserID,
+		CurrencyType:  resp.CurrencyType,
+		Amount:        strconv.FormatInt(resp.Amount, 10),
+		BalanceBefore: strconv.FormatInt(resp.BalanceBefore, 10),
+		BalanceAfter:  strconv.FormatInt(resp.BalanceAfter, 10),
+		Status:        resp.Status,
+	})
+}
+
+// apiKeyName APIKeyMiddlewareが認証した管理APIキーの名前（トランザクションや変更履歴のリクエスト元として記録する）
+func apiKeyName(c echo.Context) string {
+	name, _ := c.Get("api_key_name").(string)
+	return name
+}
@@ -1,5 +1,7 @@
 package handler
 
+import "time"
+
 // BalanceItem 残高アイテム
 // @Description 残高アイテム
 type BalanceItem struct {
@@ -10,8 +12,17 @@ type BalanceItem struct {
 // BalanceResponse 残高レスポンス
 // @Description 残高レスポンス
 type BalanceResponse struct {
-	UserID   string      `json:"user_id" example:"user123"`
-	Balances BalanceItem `json:"balances"`
+	UserID      string           `json:"user_id" example:"user123"`
+	Balances    BalanceItem      `json:"balances"`
+	Expirations []ExpirationItem `json:"expirations"`
+}
+
+// ExpirationItem 失効予定アイテム
+// @Description 失効予定の通貨（有効期限の近い順）
+type ExpirationItem struct {
+	CurrencyType string    `json:"currency_type" example:"free"`
+	Amount       string    `json:"amount" example:"100"`
+	ExpiresAt    time.Time `json:"expires_at" example:"2026-12-31T23:59:59Z"`
 }
 
 // GrantRequest 通貨付与リクエスト
@@ -20,7 +31,7 @@ type GrantRequest struct {
 	CurrencyType string                 `json:"currency_type" example:"free" enums:"paid,free"`
 	Amount       string                 `json:"amount" example:"100"`
 	Reason       string                 `json:"reason" example:"イベント報酬"`
-	Requester    string                 `json:"requester" example:"game-server-01"`
+	ExpiresAt    *time.Time             `json:"expires_at,omitempty" example:"2026-12-31T23:59:59Z"`
 	Metadata     map[string]interface{} `json:"metadata"`
 }
 
@@ -39,7 +50,6 @@ type ConsumeRequest struct {
 	Amount       string                 `json:"amount" example:"50"`
 	ItemID       string                 `json:"item_id" example:"item001"`
 	UsePriority  bool                   `json:"use_priority" example:"false"`
-	Requester    string                 `json:"requester" example:"game-server-01"`
 	Metadata     map[string]interface{} `json:"metadata"`
 }
 
@@ -61,3 +71,77 @@ type ConsumeResponse struct {
 	TotalConsumed      string              `json:"total_consumed,omitempty" example:"50"`
 	Status             string              `json:"status" example:"completed"`
 }
+
+// RefundRequest 返金リクエスト
+// @Description 返金リクエスト
+type RefundRequest struct {
+	Reason   string                 `json:"reason" example:"誤購入のため"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// RefundDetail 返金詳細
+// @Description 返金詳細
+type RefundDetail struct {
+	TransactionID         string `json:"transaction_id" example:"refund_txn_456_free"`
+	OriginalTransactionID string `json:"original_transaction_id" example:"txn_456_free"`
+	CurrencyType          string `json:"currency_type" example:"free"`
+	Amount                string `json:"amount" example:"50"`
+	BalanceBefore         string `json:"balance_before" example:"950"`
+	BalanceAfter          string `json:"balance_after" example:"1000"`
+}
+
+// RefundResponse 返金レスポンス
+// @Description 返金レスポンス
+type RefundResponse struct {
+	TransactionID         string         `json:"transaction_id" example:"refund_txn_456"`
+	OriginalTransactionID string         `json:"original_transaction_id" example:"txn_456"`
+	UserID                string         `json:"user_id" example:"user123"`
+	RefundDetails         []RefundDetail `json:"refund_details"`
+	TotalRefunded         string         `json:"total_refunded" example:"50"`
+	Status                string         `json:"status" example:"completed"`
+}
+
+// TransferRequest 通貨譲渡リクエスト
+// @Description 通貨譲渡リクエスト。譲渡できる通貨タイプはサーバー設定で決まる（通常は無償通貨のみ）
+type TransferRequest struct {
+	ToUserID     string                 `json:"to_user_id" example:"friend456"`
+	CurrencyType string                 `json:"currency_type" example:"free" enums:"paid,free"`
+	Amount       string                 `json:"amount" example:"100"`
+	Message      string                 `json:"message" example:"いつもありがとう"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// TransferResponse 通貨譲渡レスポンス
+// @Description 通貨譲渡レスポンス
+type TransferResponse struct {
+	TransferID            string `json:"transfer_id" example:"trf_0192b6f0-7c1e-7000-8000-000000000001"`
+	FromUserID            string `json:"from_user_id" example:"user123"`
+	ToUserID              string `json:"to_user_id" example:"friend456"`
+	CurrencyType          string `json:"currency_type" example:"free"`
+	Amount                string `json:"amount" example:"100"`
+	SenderTransactionID   string `json:"sender_transaction_id" example:"trf_0192b6f0-7c1e-7000-8000-000000000001_out"`
+	ReceiverTransactionID string `json:"receiver_transaction_id" example:"trf_0192b6f0-7c1e-7000-8000-000000000001_in"`
+	BalanceAfter          string `json:"balance_after" example:"400"`
+	Status                string `json:"status" example:"completed"`
+}
+
+// CompensateRequest 補填（調整）リクエスト
+// @Description 補填（調整）リクエスト。amountが正の値で付与、負の値で回収（マイナス残高を許可）
+type CompensateRequest struct {
+	CurrencyType string                 `json:"currency_type" example:"paid" enums:"paid,free"`
+	Amount       string                 `json:"amount" example:"-100"`
+	Reason       string                 `json:"reason" example:"不正付与の回収"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// CompensateResponse 補填（調整）レスポンス
+// @Description 補填（調整）レスポンス
+type CompensateResponse struct {
+	TransactionID string `json:"transaction_id" example:"txn_789"`
+	UserID        string `json:"user_id" example:"user123"`
+	CurrencyType  string `json:"currency_type" example:"paid"`
+	Amount        string `json:"amount" example:"-100"`
+	BalanceBefore string `json:"balance_before" example:"50"`
+	BalanceAfter  string `json:"balance_after" example:"-50"`
+	Status        string `json:"status" example:"completed"`
+}
@@ -9,7 +9,9 @@ import (
 
 	currencyapp "gem-server/internal/application/currency"
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/service"
+	"gem-server/internal/domain/transaction"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 	restmiddleware "gem-server/internal/presentation/rest/middleware"
 
@@ -65,8 +67,12 @@ func TestCurrencyHandler_GetBalance(t *testing.T) {
 			appService := currencyapp.NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -148,8 +154,12 @@ func TestCurrencyHandler_GetBalanceAdmin(t *testing.T) {
 			appService := currencyapp.NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -232,8 +242,12 @@ func TestCurrencyHandler_GrantCurrency(t *testing.T) {
 			appService := currencyapp.NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -265,6 +279,69 @@ func TestCurrencyHandler_GrantCurrency(t *testing.T) {
 	}
 }
 
+func TestCurrencyHandler_GrantCurrency_IdempotencyKeyConflict(t *testing.T) {
+	e := echo.New()
+	mockCurrencyRepo := new(MockCurrencyRepository)
+	mockTransactionRepo := new(MockTransactionRepository)
+	mockTxManager := new(MockTransactionManager)
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	metrics, _ := otelinfra.NewMetrics("test")
+	currencyService := service.NewCurrencyService(mockCurrencyRepo)
+
+	// 同じキーで別内容のリクエストが記録済み
+	recorded, err := transaction.NewTransaction(
+		"txn_recorded",
+		"user123",
+		transaction.TransactionTypeGrant,
+		currency.CurrencyTypePaid,
+		500,
+		0,
+		500,
+		transaction.TransactionStatusCompleted,
+		nil,
+	)
+	require.NoError(t, err)
+	recorded.SetIdempotencyKey("grant-key-1", "other-request-hash")
+	mockTransactionRepo.On("FindByIdempotencyKey", mock.Anything, "user123", "grant-key-1").Return([]*transaction.Transaction{recorded}, nil)
+
+	appService := currencyapp.NewCurrencyApplicationService(
+		mockCurrencyRepo,
+		mockTransactionRepo,
+		newEmptyLotRepository(),
+		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		nil,
+		currencyService,
+		nil,
+		logger,
+		metrics,
+	)
+
+	handler := NewCurrencyHandler(appService)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"currency_type": "paid",
+		"amount":        "100",
+		"reason":        "campaign",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/user123/grant", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("Idempotency-Key", "grant-key-1")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("user_id")
+	c.SetParamValues("user123")
+
+	handlerFunc := restmiddleware.ErrorHandlerMiddleware(logger)(handler.GrantCurrency)
+	require.NoError(t, handlerFunc(c))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "idempotency_key_conflict")
+	mockTransactionRepo.AssertExpectations(t)
+	mockCurrencyRepo.AssertNotCalled(t, "FindByUserIDAndType", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestCurrencyHandler_ConsumeCurrency(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -306,8 +383,12 @@ func TestCurrencyHandler_ConsumeCurrency(t *testing.T) {
 			appService := currencyapp.NewCurrencyApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
 				currencyService,
+				nil,
 				logger,
 				metrics,
 			)
@@ -338,3 +419,393 @@ func TestCurrencyHandler_ConsumeCurrency(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrencyHandler_RefundTransaction(t *testing.T) {
+	tests := []struct {
+		name           string
+		transactionID  string
+		setupMock      func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		expectedStatus int
+	}{
+		{
+			name:          "正常系: 返金成功",
+			transactionID: "txn_123",
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				original := transaction.MustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 100, 1000, 900, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(nil, transaction.ErrTransactionNotFound)
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 900, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: transaction_idが空",
+			transactionID:  "",
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:          "異常系: 返金済み",
+			transactionID: "txn_123",
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				original := transaction.MustNewTransaction("txn_123", "user123", transaction.TransactionTypeConsume, currency.CurrencyTypePaid, 100, 1000, 900, transaction.TransactionStatusCompleted, nil)
+				refund := transaction.MustNewTransaction("refund_txn_123", "user123", transaction.TransactionTypeRefund, currency.CurrencyTypePaid, 100, 900, 1000, transaction.TransactionStatusCompleted, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "txn_123").Return(original, nil)
+				mtr.On("FindByTransactionID", mock.Anything, "refund_txn_123").Return(refund, nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:          "異常系: トランザクションが見つからない",
+			transactionID: "txn_none",
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mtr.On("FindByTransactionID", mock.Anything, mock.AnythingOfType("string")).Return(nil, transaction.ErrTransactionNotFound)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockTxManager := new(MockTransactionManager)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+			currencyService := service.NewCurrencyService(mockCurrencyRepo)
+
+			tt.setupMock(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			appService := currencyapp.NewCurrencyApplicationService(
+				mockCurrencyRepo,
+				mockTransactionRepo,
+				newEmptyLotRepository(),
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				currencyService,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCurrencyHandler(appService)
+
+			body, _ := json.Marshal(map[string]interface{}{"reason": "誤購入"})
+			req := httptest.NewRequest(http.MethodPost, "/admin/transactions/"+tt.transactionID+"/refund", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("transaction_id")
+			c.SetParamValues(tt.transactionID)
+
+			// ミドルウェアを手動で実行
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.RefundTransaction(c)
+			})
+			err := handlerFunc(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp RefundResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				assert.Equal(t, "refund_txn_123", resp.TransactionID)
+				assert.Equal(t, "100", resp.TotalRefunded)
+				assert.Equal(t, "completed", resp.Status)
+			}
+		})
+	}
+}
+
+func TestCurrencyHandler_CompensateCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		setupMock      func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		expectedStatus int
+		checkResponse  func(*testing.T, CompensateResponse)
+	}{
+		{
+			name:   "正常系: 補填で付与（リクエストボディのrequesterではなく管理APIキーの名前を記録）",
+			userID: "user123",
+			body: map[string]interface{}{
+				"currency_type": "free",
+				"amount":        "500",
+				"reason":        "障害補填",
+				"requester":     "forged-tool",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(currency.MustNewCurrency("user123", currency.CurrencyTypeFree, 100, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.MatchedBy(func(tx *transaction.Transaction) bool {
+					return tx.Requester() != nil && *tx.Requester() == "ops-tool"
+				})).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp CompensateResponse) {
+				assert.Equal(t, "500", resp.Amount)
+				assert.Equal(t, "100", resp.BalanceBefore)
+				assert.Equal(t, "600", resp.BalanceAfter)
+			},
+		},
+		{
+			name:   "正常系: 回収でマイナス残高になる",
+			userID: "user123",
+			body: map[string]interface{}{
+				"currency_type": "paid",
+				"amount":        "-300",
+				"reason":        "不正付与の回収",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 100, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp CompensateResponse) {
+				assert.Equal(t, "-300", resp.Amount)
+				assert.Equal(t, "-200", resp.BalanceAfter)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name:   "異常系: 無効な金額フォーマット",
+			userID: "user123",
+			body: map[string]interface{}{
+				"currency_type": "paid",
+				"amount":        "abc",
+				"reason":        "補填",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "異常系: reasonが空",
+			userID: "user123",
+			body: map[string]interface{}{
+				"currency_type": "paid",
+				"amount":        "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockTxManager := new(MockTransactionManager)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+			currencyService := service.NewCurrencyService(mockCurrencyRepo)
+
+			tt.setupMock(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			appService := currencyapp.NewCurrencyApplicationService(
+				mockCurrencyRepo,
+				mockTransactionRepo,
+				newEmptyLotRepository(),
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				nil,
+				currencyService,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCurrencyHandler(appService)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.userID+"/compensate", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("user_id")
+			c.SetParamValues(tt.userID)
+			c.Set("api_key_name", "ops-tool")
+
+			// ミドルウェアを手動で実行
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.CompensateCurrency(c)
+			})
+			err := handlerFunc(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.checkResponse != nil {
+				var resp CompensateResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				tt.checkResponse(t, resp)
+			}
+		})
+	}
+}
+
+func TestCurrencyHandler_TransferCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         string
+		body           map[string]interface{}
+		setupMock      func(*MockCurrencyRepository, *MockTransactionRepository, *MockTransactionManager)
+		expectedStatus int
+		checkResponse  func(*testing.T, TransferResponse)
+	}{
+		{
+			name:   "正常系: 無償通貨を譲渡",
+			userID: "user123",
+			body: map[string]interface{}{
+				"to_user_id":    "friend456",
+				"currency_type": "free",
+				"amount":        "100",
+				"message":       "いつもありがとう",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(currency.MustNewCurrency("user123", currency.CurrencyTypeFree, 500, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "friend456", currency.CurrencyTypeFree).Return(currency.MustNewCurrency("friend456", currency.CurrencyTypeFree, 0, 1), nil)
+				mcr.On("Save", mock.Anything, mock.AnythingOfType("*currency.Currency")).Return(nil)
+				mtr.On("Save", mock.Anything, mock.AnythingOfType("*transaction.Transaction")).Return(nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			checkResponse: func(t *testing.T, resp TransferResponse) {
+				assert.Equal(t, "user123", resp.FromUserID)
+				assert.Equal(t, "friend456", resp.ToUserID)
+				assert.Equal(t, "100", resp.Amount)
+				assert.Equal(t, "400", resp.BalanceAfter)
+				assert.Equal(t, resp.TransferID+"_out", resp.SenderTransactionID)
+				assert.Equal(t, "completed", resp.Status)
+			},
+		},
+		{
+			name:   "異常系: 有償通貨は譲渡できない",
+			userID: "user123",
+			body: map[string]interface{}{
+				"to_user_id":    "friend456",
+				"currency_type": "paid",
+				"amount":        "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "異常系: 自分自身への譲渡",
+			userID: "user123",
+			body: map[string]interface{}{
+				"to_user_id":    "user123",
+				"currency_type": "free",
+				"amount":        "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "異常系: 残高不足",
+			userID: "user123",
+			body: map[string]interface{}{
+				"to_user_id":    "friend456",
+				"currency_type": "free",
+				"amount":        "1000",
+			},
+			setupMock: func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {
+				mcr.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(currency.MustNewCurrency("user123", currency.CurrencyTypeFree, 500, 1), nil)
+				mcr.On("FindByUserIDAndType", mock.Anything, "friend456", currency.CurrencyTypeFree).Return(currency.MustNewCurrency("friend456", currency.CurrencyTypeFree, 0, 1), nil)
+				mtm.On("WithTransaction", mock.Anything, mock.AnythingOfType("func(context.Context) error")).Return(nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "異常系: to_user_idが空",
+			userID: "user123",
+			body: map[string]interface{}{
+				"currency_type": "free",
+				"amount":        "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "異常系: トークンにuser_idがない",
+			userID: "",
+			body: map[string]interface{}{
+				"to_user_id":    "friend456",
+				"currency_type": "free",
+				"amount":        "100",
+			},
+			setupMock:      func(mcr *MockCurrencyRepository, mtr *MockTransactionRepository, mtm *MockTransactionManager) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			mockCurrencyRepo := new(MockCurrencyRepository)
+			mockTransactionRepo := new(MockTransactionRepository)
+			mockTxManager := new(MockTransactionManager)
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+			metrics, _ := otelinfra.NewMetrics("test")
+			currencyService := service.NewCurrencyService(mockCurrencyRepo)
+
+			tt.setupMock(mockCurrencyRepo, mockTransactionRepo, mockTxManager)
+
+			appService := currencyapp.NewCurrencyApplicationService(
+				mockCurrencyRepo,
+				mockTransactionRepo,
+				newEmptyLotRepository(),
+				mockTxManager,
+				idgen.NewUUIDv7Generator(),
+				currency.NewTransferPolicy(currency.CurrencyTypeFree),
+				currencyService,
+				nil,
+				logger,
+				metrics,
+			)
+
+			handler := NewCurrencyHandler(appService)
+
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/me/transfers", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.userID != "" {
+				c.Set("user_id", tt.userID)
+			}
+
+			// ミドルウェアを手動で実行
+			middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+			handlerFunc := middlewareFunc(func(c echo.Context) error {
+				return handler.TransferCurrency(c)
+			})
+			err := handlerFunc(c)
+			if err != nil {
+				e.HTTPErrorHandler(err, c)
+			}
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.checkResponse != nil {
+				var resp TransferResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+				tt.checkResponse(t, resp)
+			}
+		})
+	}
+}
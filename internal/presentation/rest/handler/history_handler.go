@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	historyapp "gem-server/internal/application/history"
 
@@ -29,9 +31,14 @@ func NewHistoryHandler(historyService *historyapp.HistoryApplicationService) *Hi
 // @Produce json
 // @Security Bearer
 // @Param limit query int false "取得件数（デフォルト: 50, 最大: 100)" default(50) example(50)
-// @Param offset query int false "オフセット（デフォルト: 0)" default(0) example(0)
+// @Param offset query int false "オフセット（デフォルト: 0)。cursor指定時は無視されます" default(0) example(0)
+// @Param cursor query string false "前のレスポンスのnext_cursor。指定するとその続きから取得します"
 // @Param currency_type query string false "通貨タイプでフィルタ（paid/free）" example(paid)
-// @Param transaction_type query string false "トランザクションタイプでフィルタ（grant/consume/payment/redemption）" example(consume)
+// @Param transaction_type query string false "トランザクションタイプでフィルタ（grant/consume/refund/expire/compensate/transfer_out/transfer_in）" example(consume)
+// @Param status query string false "ステータスでフィルタ（pending/completed/failed）" example(completed)
+// @Param requester query string false "リクエスト元でフィルタ" example(game-server)
+// @Param from query string false "この日時以降に作成されたものに絞り込み（RFC3339）" example(2025-01-01T00:00:00Z)
+// @Param to query string false "この日時より前に作成されたものに絞り込み（RFC3339）" example(2025-02-01T00:00:00Z)
 // @Success 200 {object} TransactionHistoryResponse "履歴取得成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
@@ -55,12 +62,18 @@ func (h *HistoryHandler) GetTransactionHistory(c echo.Context) error {
 // @Param user_id path string true "ユーザーID" example(user123)
 // @Param X-API-Key header string true "APIキー"
 // @Param limit query int false "取得件数（デフォルト: 50, 最大: 100)" default(50) example(50)
-// @Param offset query int false "オフセット（デフォルト: 0)" default(0) example(0)
+// @Param offset query int false "オフセット（デフォルト: 0)。cursor指定時は無視されます" default(0) example(0)
+// @Param cursor query string false "前のレスポンスのnext_cursor。指定するとその続きから取得します"
 // @Param currency_type query string false "通貨タイプでフィルタ（paid/free）" example(paid)
-// @Param transaction_type query string false "トランザクションタイプでフィルタ（grant/consume/payment/redemption）" example(consume)
+// @Param transaction_type query string false "トランザクションタイプでフィルタ（grant/consume/refund/expire/compensate/transfer_out/transfer_in）" example(consume)
+// @Param status query string false "ステータスでフィルタ（pending/completed/failed）" example(completed)
+// @Param requester query string false "リクエスト元でフィルタ" example(game-server)
+// @Param from query string false "この日時以降に作成されたものに絞り込み（RFC3339）" example(2025-01-01T00:00:00Z)
+// @Param to query string false "この日時より前に作成されたものに絞り込み（RFC3339）" example(2025-02-01T00:00:00Z)
 // @Success 200 {object} TransactionHistoryResponse "履歴取得成功"
 // @Failure 400 {object} ErrorResponse "不正なリクエスト"
 // @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
 // @Router /admin/users/{user_id}/transactions [get]
 func (h *HistoryHandler) GetTransactionHistoryAdmin(c echo.Context) error {
 	userID := c.Param("user_id")
@@ -95,6 +108,18 @@ func (h *HistoryHandler) getTransactionHistoryInternal(c echo.Context, userID st
 
 	currencyType := c.QueryParam("currency_type")
 	transactionType := c.QueryParam("transaction_type")
+	status := c.QueryParam("status")
+	requester := c.QueryParam("requester")
+	cursor := c.QueryParam("cursor")
+
+	from, err := parseTimeQueryParam(c, "from")
+	if err != nil {
+		return err
+	}
+	to, err := parseTimeQueryParam(c, "to")
+	if err != nil {
+		return err
+	}
 
 	req := &historyapp.GetTransactionHistoryRequest{
 		UserID:          userID,
@@ -102,6 +127,11 @@ func (h *HistoryHandler) getTransactionHistoryInternal(c echo.Context, userID st
 		Offset:          offset,
 		CurrencyType:    currencyType,
 		TransactionType: transactionType,
+		Status:          status,
+		Requester:       requester,
+		From:            from,
+		To:              to,
+		Cursor:          cursor,
 	}
 
 	resp, err := h.historyService.GetTransactionHistory(c.Request().Context(), req)
@@ -129,5 +159,96 @@ func (h *HistoryHandler) getTransactionHistoryInternal(c echo.Context, userID st
 		Total:        resp.Total,
 		Limit:        resp.Limit,
 		Offset:       resp.Offset,
+		NextCursor:   resp.NextCursor,
 	})
 }
+
+// ExportTransactions トランザクションエクスポートハンドラー（管理API用）
+// @Summary トランザクションをエクスポート（管理API）
+// @Description 全ユーザーのトランザクションを期間・通貨タイプ・トランザクションタイプで絞り込み、CSVまたはNDJSONでストリーミング出力します。メタデータは"親.子"形式のキーに展開されます
+// @Tags admin
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param X-API-Key header string true "APIキー"
+// @Param format query string false "出力形式（csv/ndjson、デフォルト: csv）" example(csv)
+// @Param from query string true "この日時以降に作成されたものを出力（RFC3339）" example(2025-01-01T00:00:00Z)
+// @Param to query string true "この日時より前に作成されたものを出力（RFC3339）" example(2025-02-01T00:00:00Z)
+// @Param currency_type query string false "通貨タイプでフィルタ（paid/free）" example(paid)
+// @Param transaction_type query string false "トランザクションタイプでフィルタ（grant/consume/refund/expire/compensate/transfer_out/transfer_in）" example(consume)
+// @Success 200 {string} string "エクスポート成功"
+// @Failure 400 {object} ErrorResponse "不正なリクエスト"
+// @Failure 401 {object} ErrorResponse "認証エラー"
+// @Failure 403 {object} ErrorResponse "APIキーに必要なスコープがない"
+// @Router /admin/transactions/export [get]
+func (h *HistoryHandler) ExportTransactions(c echo.Context) error {
+	format, err := historyapp.ParseExportFormat(c.QueryParam("format"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid format parameter")
+	}
+
+	from, err := parseTimeQueryParam(c, "from")
+	if err != nil {
+		return err
+	}
+	to, err := parseTimeQueryParam(c, "to")
+	if err != nil {
+		return err
+	}
+
+	req := &historyapp.ExportTransactionsRequest{
+		Format:          format,
+		CurrencyType:    c.QueryParam("currency_type"),
+		TransactionType: c.QueryParam("transaction_type"),
+		From:            from,
+		To:              to,
+	}
+
+	w := &exportResponseWriter{response: c.Response(), format: format}
+	if _, err := h.historyService.ExportTransactions(c.Request().Context(), req, w); err != nil {
+		if w.committed {
+			// 出力の途中で失敗した場合はステータスを変更できないため、接続を切断して不完全な出力であることを伝える
+			panic(http.ErrAbortHandler)
+		}
+		return err
+	}
+
+	// 0件でNDJSONの場合など、まだ何も書き込んでいない場合もヘッダーを送信する
+	w.commit()
+	return nil
+}
+
+// exportResponseWriter 最初の書き込み時にレスポンスヘッダーを確定させるWriter
+// 書き込み前に発生したエラーは通常のエラーレスポンスとして返せるようにする
+type exportResponseWriter struct {
+	response  *echo.Response
+	format    historyapp.ExportFormat
+	committed bool
+}
+
+func (w *exportResponseWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+	w.response.Header().Set(echo.HeaderContentType, w.format.ContentType())
+	w.response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="transactions.%s"`, w.format))
+	w.response.WriteHeader(http.StatusOK)
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	w.commit()
+	return w.response.Write(p)
+}
+
+// parseTimeQueryParam RFC3339形式のクエリパラメータを取得（未指定の場合はnil）
+func parseTimeQueryParam(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s parameter", name))
+	}
+	return &t, nil
+}
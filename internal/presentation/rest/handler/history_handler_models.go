@@ -20,4 +20,5 @@ type TransactionHistoryResponse struct {
 	Total        int               `json:"total" example:"1"`
 	Limit        int               `json:"limit" example:"50"`
 	Offset       int               `json:"offset" example:"0"`
+	NextCursor   string            `json:"next_cursor,omitempty" example:"MjAyNS0wMS0wMVQwMDowMDowMFp8dHhuXzEyMw"` // 次のページを取得するためのカーソル（最後のページでは省略）
 }
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	historyapp "gem-server/internal/application/history"
 	"gem-server/internal/domain/currency"
@@ -57,13 +59,15 @@ func TestHistoryHandler_GetTransactionHistory(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 50, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 50, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(120, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
 				var response map[string]interface{}
 				err := json.Unmarshal(rec.Body.Bytes(), &response)
 				require.NoError(t, err)
+				assert.Equal(t, float64(120), response["total"])
 				assert.Contains(t, response, "transactions")
 				assert.Contains(t, response, "total")
 				assert.Contains(t, response, "limit")
@@ -81,7 +85,8 @@ func TestHistoryHandler_GetTransactionHistory(t *testing.T) {
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
 				txns := []*transaction.Transaction{}
-				mtr.On("FindByUserID", mock.Anything, "user123", 10, 5).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 10, 5).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(5, nil)
 			},
 			expectedStatus: http.StatusOK,
 			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
@@ -99,6 +104,7 @@ func TestHistoryHandler_GetTransactionHistory(t *testing.T) {
 				"currency_type": "paid",
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
+				filter := transaction.Filter{UserID: "user123", CurrencyType: currency.CurrencyTypePaid}
 				txns := []*transaction.Transaction{
 					mustNewTransaction(
 						"txn1",
@@ -112,7 +118,8 @@ func TestHistoryHandler_GetTransactionHistory(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 50, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, filter, 50, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(1, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -123,6 +130,7 @@ func TestHistoryHandler_GetTransactionHistory(t *testing.T) {
 				"transaction_type": "grant",
 			},
 			setupMock: func(mtr *MockTransactionRepository) {
+				filter := transaction.Filter{UserID: "user123", TransactionType: transaction.TransactionTypeGrant}
 				txns := []*transaction.Transaction{
 					mustNewTransaction(
 						"txn1",
@@ -136,10 +144,103 @@ func TestHistoryHandler_GetTransactionHistory(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 50, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, filter, 50, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, filter).Return(1, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:        "正常系: status・requester・期間でフィルタ",
+			tokenUserID: "user123",
+			queryParams: map[string]string{
+				"status":    "completed",
+				"requester": "game-server",
+				"from":      "2025-01-01T00:00:00Z",
+				"to":        "2025-02-01T00:00:00+09:00",
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+				to := time.Date(2025, 1, 31, 15, 0, 0, 0, time.UTC)
+				matchFilter := mock.MatchedBy(func(f transaction.Filter) bool {
+					return f.UserID == "user123" &&
+						f.Status == transaction.TransactionStatusCompleted &&
+						f.Requester == "game-server" &&
+						f.From != nil && f.From.Equal(from) &&
+						f.To != nil && f.To.Equal(to)
+				})
+				mtr.On("FindByFilter", mock.Anything, matchFilter, 50, 0).Return([]*transaction.Transaction{}, nil)
+				mtr.On("CountByFilter", mock.Anything, matchFilter).Return(0, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "正常系: cursorで次のページを取得",
+			tokenUserID: "user123",
+			queryParams: map[string]string{
+				"limit": "1",
+				"cursor": transaction.Cursor{
+					CreatedAt:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					TransactionID: "txn3",
+				}.Encode(),
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				txns := []*transaction.Transaction{
+					mustNewTransaction("txn2", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, map[string]interface{}{}),
+					mustNewTransaction("txn1", "user123", transaction.TransactionTypeGrant, currency.CurrencyTypeFree, 10, 0, 10, transaction.TransactionStatusCompleted, map[string]interface{}{}),
+				}
+				mtr.On("FindByFilterAfterCursor", mock.Anything, transaction.Filter{UserID: "user123"}, mock.Anything, 2).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(3, nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				var response TransactionHistoryResponse
+				err := json.Unmarshal(rec.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Len(t, response.Transactions, 1)
+				assert.Equal(t, "txn2", response.Transactions[0].TransactionID)
+
+				next, err := transaction.DecodeCursor(response.NextCursor)
+				require.NoError(t, err)
+				assert.Equal(t, "txn2", next.TransactionID)
+			},
+		},
+		{
+			name:        "異常系: 無効なcursor",
+			tokenUserID: "user123",
+			queryParams: map[string]string{
+				"cursor": "invalid!",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "異常系: 無効なfrom",
+			tokenUserID: "user123",
+			queryParams: map[string]string{
+				"from": "2025-01-01",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "異常系: fromがto以降",
+			tokenUserID: "user123",
+			queryParams: map[string]string{
+				"from": "2025-02-01T00:00:00Z",
+				"to":   "2025-01-01T00:00:00Z",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "異常系: 無効なstatus",
+			tokenUserID: "user123",
+			queryParams: map[string]string{
+				"status": "unknown",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
 		{
 			name:           "異常系: user_idがトークンにない",
 			tokenUserID:    "",
@@ -275,7 +376,8 @@ func TestHistoryHandler_GetTransactionHistoryAdmin(t *testing.T) {
 						map[string]interface{}{},
 					),
 				}
-				mtr.On("FindByUserID", mock.Anything, "user123", 50, 0).Return(txns, nil)
+				mtr.On("FindByFilter", mock.Anything, transaction.Filter{UserID: "user123"}, 50, 0).Return(txns, nil)
+				mtr.On("CountByFilter", mock.Anything, transaction.Filter{UserID: "user123"}).Return(1, nil)
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -341,6 +443,181 @@ func TestHistoryHandler_GetTransactionHistoryAdmin(t *testing.T) {
 	}
 }
 
+func TestHistoryHandler_ExportTransactions(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	txn := mustNewTransaction(
+		"txn1",
+		"user123",
+		transaction.TransactionTypeGrant,
+		currency.CurrencyTypePaid,
+		1000,
+		0,
+		1000,
+		transaction.TransactionStatusCompleted,
+		map[string]interface{}{},
+	)
+
+	tests := []struct {
+		name             string
+		queryParams      map[string]string
+		setupMock        func(*MockTransactionRepository)
+		expectedStatus   int
+		validateResponse func(*testing.T, *httptest.ResponseRecorder)
+	}{
+		{
+			name: "正常系: CSVでエクスポート",
+			queryParams: map[string]string{
+				"from":          "2025-01-01T00:00:00Z",
+				"to":            "2025-02-01T00:00:00Z",
+				"currency_type": "paid",
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				filter := transaction.Filter{CurrencyType: currency.CurrencyTypePaid, From: &from, To: &to}
+				mtr.On("StreamByFilter", mock.Anything, filter, mock.Anything).
+					Run(func(args mock.Arguments) {
+						fn := args.Get(2).(func(*transaction.Transaction) error)
+						_ = fn(txn)
+					}).
+					Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+				assert.Equal(t, `attachment; filename="transactions.csv"`, rec.Header().Get(echo.HeaderContentDisposition))
+				lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+				require.Len(t, lines, 2)
+				assert.True(t, strings.HasPrefix(lines[0], "transaction_id,user_id,"))
+				assert.True(t, strings.HasPrefix(lines[1], "txn1,user123,grant,paid,1000,"))
+			},
+		},
+		{
+			name: "正常系: 0件のNDJSON",
+			queryParams: map[string]string{
+				"format": "ndjson",
+				"from":   "2025-01-01T00:00:00Z",
+				"to":     "2025-02-01T00:00:00Z",
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				mtr.On("StreamByFilter", mock.Anything, transaction.Filter{From: &from, To: &to}, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+				assert.Empty(t, rec.Body.String())
+			},
+		},
+		{
+			name: "異常系: 未対応の形式",
+			queryParams: map[string]string{
+				"format": "xlsx",
+				"from":   "2025-01-01T00:00:00Z",
+				"to":     "2025-02-01T00:00:00Z",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: 期間が未指定",
+			queryParams: map[string]string{
+				"from": "2025-01-01T00:00:00Z",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			validateResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
+				assert.Contains(t, rec.Body.String(), "invalid_filter")
+			},
+		},
+		{
+			name: "異常系: 無効なto",
+			queryParams: map[string]string{
+				"from": "2025-01-01T00:00:00Z",
+				"to":   "2025-02-01",
+			},
+			setupMock:      func(mtr *MockTransactionRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "異常系: 出力前のデータベースエラー",
+			queryParams: map[string]string{
+				"from": "2025-01-01T00:00:00Z",
+				"to":   "2025-02-01T00:00:00Z",
+			},
+			setupMock: func(mtr *MockTransactionRepository) {
+				mtr.On("StreamByFilter", mock.Anything, transaction.Filter{From: &from, To: &to}, mock.Anything).Return(assert.AnError)
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := serveExportTransactions(t, tt.queryParams, tt.setupMock)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.validateResponse != nil {
+				tt.validateResponse(t, rec)
+			}
+		})
+	}
+
+	t.Run("異常系: 出力途中のエラーは接続を切断する", func(t *testing.T) {
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			serveExportTransactions(t, map[string]string{
+				"from": "2025-01-01T00:00:00Z",
+				"to":   "2025-02-01T00:00:00Z",
+			}, func(mtr *MockTransactionRepository) {
+				mtr.On("StreamByFilter", mock.Anything, transaction.Filter{From: &from, To: &to}, mock.Anything).
+					Run(func(args mock.Arguments) {
+						// CSVのバッファを超えてレスポンスへの書き込みが始まるまで出力する
+						fn := args.Get(2).(func(*transaction.Transaction) error)
+						for i := 0; i < 100; i++ {
+							_ = fn(txn)
+						}
+					}).
+					Return(assert.AnError)
+			})
+		})
+	})
+}
+
+// serveExportTransactions エクスポートハンドラーをエラーハンドリングミドルウェア経由で実行する
+func serveExportTransactions(t *testing.T, queryParams map[string]string, setupMock func(*MockTransactionRepository)) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+
+	e := echo.New()
+	mockTransactionRepo := new(MockTransactionRepository)
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	metrics, _ := otelinfra.NewMetrics("test")
+
+	setupMock(mockTransactionRepo)
+
+	appService := historyapp.NewHistoryApplicationService(
+		mockTransactionRepo,
+		logger,
+		metrics,
+	)
+	handler := NewHistoryHandler(appService)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/transactions/export", nil)
+	q := req.URL.Query()
+	for k, v := range queryParams {
+		q.Add(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middlewareFunc := restmiddleware.ErrorHandlerMiddleware(logger)
+	err := middlewareFunc(handler.ExportTransactions)(c)
+	if err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+	return rec, err
+}
+
 func mustNewTransaction(transactionID, userID string, transactionType transaction.TransactionType, currencyType currency.CurrencyType, amount, balanceBefore, balanceAfter int64, status transaction.TransactionStatus, metadata map[string]interface{}) *transaction.Transaction {
 	tx, err := transaction.NewTransaction(transactionID, userID, transactionType, currencyType, amount, balanceBefore, balanceAfter, status, metadata)
 	if err != nil {
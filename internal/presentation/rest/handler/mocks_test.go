@@ -2,8 +2,9 @@ package handler
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
+	"gem-server/internal/domain/auth_token"
 	"gem-server/internal/domain/currency"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
@@ -53,14 +54,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -69,6 +88,19 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 // MockPaymentRequestRepository モックPaymentRequestリポジトリ
 type MockPaymentRequestRepository struct {
 	mock.Mock
@@ -92,15 +124,67 @@ func (m *MockPaymentRequestRepository) Update(ctx context.Context, pr *payment_r
 	return args.Error(0)
 }
 
+// MockLotRepository モックロットリポジトリ
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	args := m.Called(ctx, transactionID, consumptions)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]currency.LotConsumption), args.Error(1)
+}
+
+// newEmptyLotRepository 有効期限付きロットが存在しない状態のモックロットリポジトリを作成
+func newEmptyLotRepository() *MockLotRepository {
+	m := new(MockLotRepository)
+	m.On("FindAvailableByUserIDAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*currency.Lot{}, nil).Maybe()
+	m.On("FindConsumptionsByTransactionID", mock.Anything, mock.Anything).Return([]currency.LotConsumption{}, nil).Maybe()
+	return m
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(ctx, fn)
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
@@ -123,16 +207,46 @@ func (m *MockRedemptionCodeRepository) Update(ctx context.Context, code *redempt
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) IncrementUses(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
 	args := m.Called(ctx, code, userID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRedemptionCodeRepository) CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (redemption_code.CampaignUsage, error) {
+	args := m.Called(ctx, campaignID, userID)
+	return args.Get(0).(redemption_code.CampaignUsage), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) LockUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockRedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
 	args := m.Called(ctx, redemption)
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) UpdateSettings(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) SaveAuditLog(ctx context.Context, log *redemption_code.CodeAuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
 	args := m.Called(ctx, code)
 	return args.Error(0)
@@ -143,10 +257,95 @@ func (m *MockRedemptionCodeRepository) Delete(ctx context.Context, code string)
 	return args.Error(0)
 }
 
-func (m *MockRedemptionCodeRepository) FindAll(ctx context.Context, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockRedemptionCodeRepository) CreateBatch(ctx context.Context, codes []*redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, codes)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindByBatchID(ctx context.Context, batchID string) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindByFilter(ctx context.Context, filter redemption_code.Filter, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
 	return args.Get(0).([]*redemption_code.RedemptionCode), args.Int(1), args.Error(2)
 }
+
+func (m *MockRedemptionCodeRepository) FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, code, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) GetCodeStats(ctx context.Context, code string) (redemption_code.CodeStats, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(redemption_code.CodeStats), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+// MockExpiryLock モック期限切れコード失効ジョブのロック
+type MockExpiryLock struct {
+	mock.Mock
+}
+
+func (m *MockExpiryLock) TryLock(ctx context.Context) (func(), bool, error) {
+	args := m.Called(ctx)
+	return func() {}, args.Bool(0), args.Error(1)
+}
+
+// MockRefreshTokenRepository モックリフレッシュトークンリポジトリ
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Save(ctx context.Context, token *auth_token.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_token.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_token.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Update(ctx context.Context, token *auth_token.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, familyID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, userID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
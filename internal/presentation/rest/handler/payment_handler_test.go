@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/idgen"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 	restmiddleware "gem-server/internal/presentation/rest/middleware"
 
@@ -65,8 +66,10 @@ func TestPaymentHandler_ProcessPayment(t *testing.T) {
 			appService := paymentapp.NewPaymentApplicationService(
 				mockCurrencyRepo,
 				mockTransactionRepo,
+				newEmptyLotRepository(),
 				mockPaymentRequestRepo,
 				mockTxManager,
+				idgen.NewUUIDv7Generator(),
 				logger,
 				metrics,
 			)
@@ -1,17 +1,28 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
-	"strings"
 
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/ipaccess"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 
 	"github.com/labstack/echo/v4"
 )
 
 // APIKeyMiddleware APIキー認証ミドルウェア
-func APIKeyMiddleware(cfg *config.AdminAPIConfig, logger *otelinfra.Logger) echo.MiddlewareFunc {
+// 認証した管理APIキーをリクエストのコンテキストに格納し、キーの名前をapi_key_nameとして設定する。
+// allowlistで制限している場合、クライアントのIPアドレスはresolverで求める（特定できない場合は拒否する）
+func APIKeyMiddleware(
+	cfg *config.AdminAPIConfig,
+	authenticator *apikey.Authenticator,
+	allowlist *ipaccess.Allowlist,
+	resolver *ipaccess.Resolver,
+	logger *otelinfra.Logger,
+) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ctx := c.Request().Context()
@@ -36,20 +47,29 @@ func APIKeyMiddleware(cfg *config.AdminAPIConfig, logger *otelinfra.Logger) echo
 			}
 
 			// APIキーの検証
-			if apiKey != cfg.APIKey {
+			key, err := authenticator.Authenticate(ctx, apiKey)
+			if errors.Is(err, api_key.ErrAPIKeyNotFound) {
 				logger.Warn(ctx, "Invalid API key", nil)
 				return c.JSON(http.StatusUnauthorized, ErrorResponse{
 					Error:   "unauthorized",
 					Message: "Invalid API key",
 				})
 			}
+			if err != nil {
+				logger.Error(ctx, "Failed to authenticate API key", err, nil)
+				return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+					Error:   "service_unavailable",
+					Message: "Failed to authenticate API key",
+				})
+			}
 
 			// IP制限のチェック（設定されている場合）
-			if len(cfg.AllowedIPs) > 0 {
-				clientIP := getClientIP(c)
-				if !isIPAllowed(clientIP, cfg.AllowedIPs) {
+			if allowlist.Restricted() {
+				ip, ok := clientIP(c, resolver)
+				if !ok || !allowlist.Allows(ip) {
 					logger.Warn(ctx, "IP address not allowed", map[string]interface{}{
-						"ip": clientIP,
+						"ip":          ip.String(),
+						"remote_addr": c.Request().RemoteAddr,
 					})
 					return c.JSON(http.StatusForbidden, ErrorResponse{
 						Error:   "forbidden",
@@ -58,52 +78,39 @@ func APIKeyMiddleware(cfg *config.AdminAPIConfig, logger *otelinfra.Logger) echo
 				}
 			}
 
+			// 認証した管理APIキーを後続のハンドラーに渡す
+			c.Set("api_key_name", key.Name())
+			c.SetRequest(c.Request().WithContext(api_key.NewContext(ctx, key)))
+
 			// 次のハンドラーを実行
 			return next(c)
 		}
 	}
 }
 
-// getClientIP クライアントのIPアドレスを取得
-func getClientIP(c echo.Context) string {
-	// X-Forwarded-Forヘッダーから取得（プロキシ経由の場合）
-	forwardedFor := c.Request().Header.Get("X-Forwarded-For")
-	if forwardedFor != "" {
-		// カンマ区切りの最初のIPを取得
-		ips := strings.Split(forwardedFor, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
-	}
-
-	// X-Real-IPヘッダーから取得
-	realIP := c.Request().Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
-	}
-
-	// RemoteAddrから取得
-	addr := c.Request().RemoteAddr
-	if idx := strings.LastIndex(addr, ":"); idx != -1 {
-		return addr[:idx]
-	}
-	return addr
-}
+// RequireScope 管理APIキーにスコープが許可されているかを確認するミドルウェア（APIKeyMiddlewareの後に適用する）
+func RequireScope(scope api_key.Scope, logger *otelinfra.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
 
-// isIPAllowed IPアドレスが許可リストに含まれているかチェック
-func isIPAllowed(ip string, allowedIPs []string) bool {
-	for _, allowedIP := range allowedIPs {
-		if ip == allowedIP {
-			return true
-		}
-		// CIDR表記のサポート（簡易版）
-		if strings.Contains(allowedIP, "/") {
-			// CIDRマッチングの実装は必要に応じて追加
-			// ここでは簡易的にプレフィックスマッチのみ
-			if strings.HasPrefix(ip, strings.Split(allowedIP, "/")[0]) {
-				return true
+			key, ok := api_key.FromContext(ctx)
+			if !ok || !key.HasScope(scope) {
+				fields := map[string]interface{}{
+					"scope": scope.String(),
+					"path":  c.Path(),
+				}
+				if ok {
+					fields["api_key_name"] = key.Name()
+				}
+				logger.Warn(ctx, "API key does not have the required scope", fields)
+				return c.JSON(http.StatusForbidden, ErrorResponse{
+					Error:   "insufficient_scope",
+					Message: "API key does not have the required scope: " + scope.String(),
+				})
 			}
+
+			return next(c)
 		}
 	}
-	return false
 }
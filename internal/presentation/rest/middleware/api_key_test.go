@@ -5,11 +5,15 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/ipaccess"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
 )
 
@@ -17,7 +21,9 @@ func TestAPIKeyMiddleware(t *testing.T) {
 	tests := []struct {
 		name           string
 		apiKey         string
-		clientIP       string
+		remoteAddr     string
+		forwardedFor   string
+		trustedProxies []string
 		config         *config.AdminAPIConfig
 		expectedStatus int
 	}{
@@ -58,9 +64,9 @@ func TestAPIKeyMiddleware(t *testing.T) {
 			expectedStatus: http.StatusForbidden,
 		},
 		{
-			name:     "正常系: IP制限あり（許可されたIP）",
-			apiKey:   "test-api-key",
-			clientIP: "127.0.0.1",
+			name:       "正常系: IP制限あり（許可されたIP）",
+			apiKey:     "test-api-key",
+			remoteAddr: "127.0.0.1:50000",
 			config: &config.AdminAPIConfig{
 				Enabled:    true,
 				APIKey:     "test-api-key",
@@ -69,9 +75,9 @@ func TestAPIKeyMiddleware(t *testing.T) {
 			expectedStatus: http.StatusOK,
 		},
 		{
-			name:     "異常系: IP制限あり（許可されていないIP）",
-			apiKey:   "test-api-key",
-			clientIP: "192.168.1.1",
+			name:       "異常系: IP制限あり（許可されていないIP）",
+			apiKey:     "test-api-key",
+			remoteAddr: "192.168.1.1:50000",
 			config: &config.AdminAPIConfig{
 				Enabled:    true,
 				APIKey:     "test-api-key",
@@ -79,6 +85,64 @@ func TestAPIKeyMiddleware(t *testing.T) {
 			},
 			expectedStatus: http.StatusForbidden,
 		},
+		{
+			name:       "正常系: IP制限あり（CIDRの範囲内）",
+			apiKey:     "test-api-key",
+			remoteAddr: "10.1.2.3:50000",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:       "異常系: IP制限あり（前方一致するだけのIP）",
+			apiKey:     "test-api-key",
+			remoteAddr: "1.2.3.45:50000",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"1.2.3.4/32"},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:       "正常系: IP制限あり（IPv6のCIDRの範囲内）",
+			apiKey:     "test-api-key",
+			remoteAddr: "[2001:db8::5]:50000",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"2001:db8::/32"},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:         "異常系: IP制限あり（信頼しない接続元のX-Forwarded-Forは無視する）",
+			apiKey:       "test-api-key",
+			remoteAddr:   "203.0.113.5:50000",
+			forwardedFor: "10.0.0.1",
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "正常系: IP制限あり（信頼するプロキシ経由）",
+			apiKey:         "test-api-key",
+			remoteAddr:     "192.0.2.10:443",
+			forwardedFor:   "10.1.2.3",
+			trustedProxies: []string{"192.0.2.10"},
+			config: &config.AdminAPIConfig{
+				Enabled:    true,
+				APIKey:     "test-api-key",
+				AllowedIPs: []string{"10.0.0.0/8"},
+			},
+			expectedStatus: http.StatusOK,
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,8 +151,19 @@ func TestAPIKeyMiddleware(t *testing.T) {
 			tracer := noop.NewTracerProvider().Tracer("test")
 			logger := otelinfra.NewLogger(tracer)
 
-			middlewareFunc := APIKeyMiddleware(tt.config, logger)
+			authenticator, err := apikey.NewAuthenticator(tt.config, nil)
+			require.NoError(t, err)
+
+			allowlist, err := ipaccess.NewAllowlist(tt.config.AllowedIPs)
+			require.NoError(t, err)
+			resolver, err := ipaccess.NewResolver(tt.trustedProxies)
+			require.NoError(t, err)
+
+			middlewareFunc := APIKeyMiddleware(tt.config, authenticator, allowlist, resolver, logger)
 			handler := middlewareFunc(func(c echo.Context) error {
+				// 認証したキーの名前が後続のハンドラーに渡される
+				assert.Equal(t, apikey.LegacyKeyName, c.Get("api_key_name"))
+				assert.Equal(t, apikey.LegacyKeyName, api_key.RequesterFromContext(c.Request().Context()))
 				return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
 			})
 
@@ -96,13 +171,16 @@ func TestAPIKeyMiddleware(t *testing.T) {
 			if tt.apiKey != "" {
 				req.Header.Set("X-API-Key", tt.apiKey)
 			}
-			if tt.clientIP != "" {
-				req.Header.Set("X-Real-IP", tt.clientIP)
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
 			}
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
 
-			err := handler(c)
+			err = handler(c)
 			if err != nil {
 				e.HTTPErrorHandler(err, c)
 			}
@@ -111,3 +189,62 @@ func TestAPIKeyMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []api_key.Scope
+		authenticated  bool
+		expectedStatus int
+	}{
+		{
+			name:           "正常系: 必要なスコープを持つ",
+			scopes:         []api_key.Scope{api_key.ScopeCurrencyRead, api_key.ScopeCurrencyGrant},
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "正常系: すべての操作を許可",
+			scopes:         []api_key.Scope{api_key.ScopeAll},
+			authenticated:  true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 必要なスコープを持たない",
+			scopes:         []api_key.Scope{api_key.ScopeCurrencyRead},
+			authenticated:  true,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "異常系: APIキー認証を経由していない",
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+
+			handler := RequireScope(api_key.ScopeCurrencyGrant, logger)(func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.authenticated {
+				key, err := api_key.NewAPIKey("game-server", api_key.HashKey("game-key"), tt.scopes)
+				require.NoError(t, err)
+				req = req.WithContext(api_key.NewContext(req.Context(), key))
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			require.NoError(t, handler(c))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, rec.Body.String(), "insufficient_scope")
+			}
+		})
+	}
+}
@@ -1,17 +1,25 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
+	"time"
 
+	"gem-server/internal/domain/auth_token"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/oidc"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
 // AuthMiddleware JWT認証ミドルウェア
-func AuthMiddleware(cfg *config.JWTConfig, logger *otelinfra.Logger) echo.MiddlewareFunc {
+// 署名はkeyringの鍵（kidで選択する公開鍵、または移行期間中のHS256のシークレット）で検証する
+// oidcVerifierが指定された場合は、外部のOIDCプロバイダーが発行したトークン（issで判別）も受け付ける
+// denylistが指定された場合は、失効させたトークン（jti）とユーザーごとの一括失効より前に発行したトークンを拒否する
+func AuthMiddleware(cfg *config.JWTConfig, keyring *jwtkeys.Keyring, oidcVerifier *oidc.Verifier, denylist auth_token.Denylist, logger *otelinfra.Logger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ctx := c.Request().Context()
@@ -38,45 +46,91 @@ func AuthMiddleware(cfg *config.JWTConfig, logger *otelinfra.Logger) echo.Middle
 
 			tokenString := parts[1]
 
-			// JWTトークンの検証
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				// 署名アルゴリズムの確認
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, jwt.ErrSignatureInvalid
+			var (
+				claims jwt.MapClaims
+				userID string
+				err    error
+			)
+			if oidcVerifier != nil && unverifiedIssuer(tokenString) == oidcVerifier.Issuer() {
+				// 外部のOIDCプロバイダーが発行したIDトークン
+				claims, userID, err = oidcVerifier.Verify(tokenString)
+				if errors.Is(err, oidc.ErrMissingUserID) {
+					logger.Warn(ctx, "Missing user_id in token claims", map[string]interface{}{
+						"issuer": oidcVerifier.Issuer(),
+					})
+					return c.JSON(401, ErrorResponse{
+						Error:   "unauthorized",
+						Message: "Missing user_id in token",
+					})
 				}
-				return []byte(cfg.Secret), nil
-			}, jwt.WithValidMethods([]string{"HS256"}), // アルゴリズムの明示的な指定
-				jwt.WithExpirationRequired(), // expクレームを必須に
-				jwt.WithIssuer(cfg.Issuer))   // issクレームの検証
-
-			if err != nil || !token.Valid {
-				logger.Warn(ctx, "Invalid token", map[string]interface{}{
-					"error": err.Error(),
-				})
-				return c.JSON(401, ErrorResponse{
-					Error:   "unauthorized",
-					Message: "Invalid or expired token",
-				})
-			}
+				if err != nil {
+					logger.Warn(ctx, "Invalid token", map[string]interface{}{
+						"issuer": oidcVerifier.Issuer(),
+						"error":  err.Error(),
+					})
+					return c.JSON(401, ErrorResponse{
+						Error:   "unauthorized",
+						Message: "Invalid or expired token",
+					})
+				}
+			} else {
+				// JWTトークンの検証
+				token, err := jwt.Parse(tokenString, keyring.Keyfunc,
+					jwt.WithValidMethods(keyring.ValidMethods()), // アルゴリズムの明示的な指定
+					jwt.WithExpirationRequired(),                 // expクレームを必須に
+					jwt.WithIssuer(cfg.Issuer))                   // issクレームの検証
 
-			// クレームからユーザーIDを取得
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				logger.Warn(ctx, "Invalid token claims", nil)
-				return c.JSON(401, ErrorResponse{
-					Error:   "unauthorized",
-					Message: "Invalid token claims",
-				})
+				if err != nil || !token.Valid {
+					logger.Warn(ctx, "Invalid token", map[string]interface{}{
+						"error": err.Error(),
+					})
+					return c.JSON(401, ErrorResponse{
+						Error:   "unauthorized",
+						Message: "Invalid or expired token",
+					})
+				}
+
+				// クレームからユーザーIDを取得
+				mapClaims, ok := token.Claims.(jwt.MapClaims)
+				if !ok {
+					logger.Warn(ctx, "Invalid token claims", nil)
+					return c.JSON(401, ErrorResponse{
+						Error:   "unauthorized",
+						Message: "Invalid token claims",
+					})
+				}
+
+				// ユーザーIDをコンテキストに設定
+				claims = mapClaims
+				userID, ok = claims["user_id"].(string)
+				if !ok {
+					logger.Warn(ctx, "Missing user_id in token claims", nil)
+					return c.JSON(401, ErrorResponse{
+						Error:   "unauthorized",
+						Message: "Missing user_id in token",
+					})
+				}
 			}
 
-			// ユーザーIDをコンテキストに設定
-			userID, ok := claims["user_id"].(string)
-			if !ok {
-				logger.Warn(ctx, "Missing user_id in token claims", nil)
-				return c.JSON(401, ErrorResponse{
-					Error:   "unauthorized",
-					Message: "Missing user_id in token",
-				})
+			// 失効したトークンの確認（確認できない場合は受け入れない）
+			if denylist != nil {
+				revoked, err := isRevoked(c, denylist, claims, userID)
+				if err != nil {
+					logger.Error(ctx, "Failed to check token revocation", err, nil)
+					return c.JSON(503, ErrorResponse{
+						Error:   "service_unavailable",
+						Message: "Token revocation check is unavailable",
+					})
+				}
+				if revoked {
+					logger.Warn(ctx, "Revoked token", map[string]interface{}{
+						"user_id": userID,
+					})
+					return c.JSON(401, ErrorResponse{
+						Error:   "unauthorized",
+						Message: "Token has been revoked",
+					})
+				}
 			}
 
 			// ユーザーIDをリクエストコンテキストに設定
@@ -87,3 +141,37 @@ func AuthMiddleware(cfg *config.JWTConfig, logger *otelinfra.Logger) echo.Middle
 		}
 	}
 }
+
+// isRevoked トークンが個別に失効しているか、ユーザーごとの一括失効より前に発行されたかを確認する
+// iatは秒単位のため、一括失効と同じ秒に発行したトークンも失効として扱う
+func isRevoked(c echo.Context, denylist auth_token.Denylist, claims jwt.MapClaims, userID string) (bool, error) {
+	ctx := c.Request().Context()
+
+	if jti, _ := claims["jti"].(string); jti != "" {
+		revoked, err := denylist.Contains(ctx, jti)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+
+	revokedAt, err := denylist.UserRevokedAt(ctx, userID)
+	if err != nil || revokedAt.IsZero() {
+		return false, err
+	}
+	issuedAt, err := claims.GetIssuedAt()
+	if err != nil || issuedAt == nil {
+		// 発行日時が不明なトークンは一括失効の対象とする
+		return true, nil
+	}
+	return !issuedAt.Time.After(revokedAt.Truncate(time.Second)), nil
+}
+
+// unverifiedIssuer 署名を検証せずにトークンのissを取得する（検証に使う鍵の選択にのみ使う）
+func unverifiedIssuer(tokenString string) string {
+	token, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return ""
+	}
+	iss, _ := token.Claims.GetIssuer()
+	return iss
+}
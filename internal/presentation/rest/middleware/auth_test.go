@@ -1,8 +1,18 @@
 package middleware
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,10 +22,22 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
 
+	"gem-server/internal/domain/auth_token"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/denylist"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/oidc"
 )
 
+// newTestKeyring JWT設定からテスト用のKeyringを作成
+func newTestKeyring(t *testing.T, cfg *config.JWTConfig) *jwtkeys.Keyring {
+	t.Helper()
+	keyring, err := jwtkeys.NewKeyring(cfg)
+	require.NoError(t, err)
+	return keyring
+}
+
 func TestAuthMiddleware_MissingAuthorizationHeader(t *testing.T) {
 	cfg := &config.JWTConfig{
 		Secret: "test-secret",
@@ -29,7 +51,7 @@ func TestAuthMiddleware_MissingAuthorizationHeader(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -53,7 +75,7 @@ func TestAuthMiddleware_InvalidAuthorizationHeaderFormat(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -77,7 +99,7 @@ func TestAuthMiddleware_InvalidToken(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -110,7 +132,7 @@ func TestAuthMiddleware_ValidToken(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		// ユーザーIDが設定されていることを確認
 		userID, ok := c.Get("user_id").(string)
@@ -147,7 +169,7 @@ func TestAuthMiddleware_MissingUserIDInClaims(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -180,7 +202,7 @@ func TestAuthMiddleware_InvalidUserIDType(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -213,7 +235,7 @@ func TestAuthMiddleware_WrongSecret(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -247,7 +269,7 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	e := echo.New()
 	c := e.NewContext(req, rec)
 
-	middleware := AuthMiddleware(cfg, logger)
+	middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -256,3 +278,337 @@ func TestAuthMiddleware_ExpiredToken(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusUnauthorized, rec.Code)
 }
+
+// unavailableDenylist 常にエラーを返すDenylist
+type unavailableDenylist struct{}
+
+func (unavailableDenylist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	return errors.New("denylist unavailable")
+}
+
+func (unavailableDenylist) Contains(ctx context.Context, jti string) (bool, error) {
+	return false, errors.New("denylist unavailable")
+}
+
+func (unavailableDenylist) RevokeUser(ctx context.Context, userID string, revokedAt, until time.Time) error {
+	return errors.New("denylist unavailable")
+}
+
+func (unavailableDenylist) UserRevokedAt(ctx context.Context, userID string) (time.Time, error) {
+	return time.Time{}, errors.New("denylist unavailable")
+}
+
+func TestAuthMiddleware_Denylist(t *testing.T) {
+	cfg := &config.JWTConfig{
+		Secret: "test-secret",
+		Issuer: "test-issuer",
+	}
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	ctx := context.Background()
+	now := time.Now()
+
+	tests := []struct {
+		name           string
+		issuedAt       time.Time
+		setupDenylist  func() auth_token.Denylist
+		expectedStatus int
+	}{
+		{
+			name:     "正常系: 失効していないトークン",
+			issuedAt: now,
+			setupDenylist: func() auth_token.Denylist {
+				d := denylist.NewMemoryDenylist()
+				require.NoError(t, d.Add(ctx, "other_jti", now.Add(time.Hour)))
+				return d
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "正常系: ユーザーの一括失効より後に発行したトークン",
+			issuedAt: now.Add(2 * time.Second),
+			setupDenylist: func() auth_token.Denylist {
+				d := denylist.NewMemoryDenylist()
+				require.NoError(t, d.RevokeUser(ctx, "user123", now, now.Add(time.Hour)))
+				return d
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:     "異常系: 個別に失効させたトークン",
+			issuedAt: now,
+			setupDenylist: func() auth_token.Denylist {
+				d := denylist.NewMemoryDenylist()
+				require.NoError(t, d.Add(ctx, "jti_123", now.Add(time.Hour)))
+				return d
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:     "異常系: ユーザーの一括失効より前に発行したトークン",
+			issuedAt: now.Add(-time.Minute),
+			setupDenylist: func() auth_token.Denylist {
+				d := denylist.NewMemoryDenylist()
+				require.NoError(t, d.RevokeUser(ctx, "user123", now, now.Add(time.Hour)))
+				return d
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:     "異常系: 失効の確認ができない",
+			issuedAt: now,
+			setupDenylist: func() auth_token.Denylist {
+				return unavailableDenylist{}
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"user_id": "user123",
+				"jti":     "jti_123",
+				"iss":     "test-issuer",
+				"iat":     tt.issuedAt.Unix(),
+				"exp":     now.Add(time.Hour).Unix(),
+			})
+			tokenString, err := token.SignedString([]byte(cfg.Secret))
+			require.NoError(t, err)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tokenString)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, tt.setupDenylist(), logger)
+			handler := middleware(func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})
+
+			err = handler(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_SigningKeys(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "key-1.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	claims := jwt.MapClaims{
+		"user_id": "user123",
+		"iss":     "test-issuer",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}
+
+	signWithKey := func(kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		token.Header["kid"] = kid
+		tokenString, err := token.SignedString(key)
+		require.NoError(t, err)
+		return tokenString
+	}
+	signWithSecret := func() string {
+		tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+		require.NoError(t, err)
+		return tokenString
+	}
+
+	tests := []struct {
+		name           string
+		acceptHS256    bool
+		token          string
+		expectedStatus int
+	}{
+		{
+			name:           "正常系: kidの公開鍵で検証する",
+			token:          signWithKey("key-1"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "正常系: 移行期間中はHS256のトークンを受け付ける",
+			acceptHS256:    true,
+			token:          signWithSecret(),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "異常系: 不明なkid",
+			token:          signWithKey("key-2"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "異常系: HS256を受け付けない設定",
+			token:          signWithSecret(),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.JWTConfig{
+				Secret:          "test-secret",
+				Issuer:          "test-issuer",
+				SigningKeyFiles: []string{path},
+				AcceptHS256:     tt.acceptHS256,
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), nil, nil, logger)
+			handler := middleware(func(c echo.Context) error {
+				return c.String(http.StatusOK, "ok")
+			})
+
+			err := handler(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_OIDC(t *testing.T) {
+	// OIDCプロバイダーのJWKSの代わり
+	public, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "OKP",
+				"crv": "Ed25519",
+				"kid": "provider-key-1",
+				"x":   base64.RawURLEncoding.EncodeToString(public),
+			}},
+		})
+	}))
+	defer provider.Close()
+
+	cfg := &config.JWTConfig{
+		Secret: "test-secret",
+		Issuer: "test-issuer",
+	}
+	verifier := oidc.NewVerifier(&config.OIDCConfig{
+		Enabled:      true,
+		Issuer:       "https://accounts.example.com",
+		JWKSURL:      provider.URL,
+		Audience:     "gem-client",
+		UserIDClaim:  "sub",
+		JWKSCacheTTL: time.Hour,
+	})
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+	ctx := context.Background()
+	now := time.Now()
+
+	idToken := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		token.Header["kid"] = "provider-key-1"
+		tokenString, err := token.SignedString(key)
+		require.NoError(t, err)
+		return tokenString
+	}
+	providerClaims := jwt.MapClaims{
+		"iss": "https://accounts.example.com",
+		"aud": "gem-client",
+		"sub": "player-42",
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+
+	tests := []struct {
+		name           string
+		token          string
+		setupDenylist  func() auth_token.Denylist
+		expectedStatus int
+		expectedUserID string
+	}{
+		{
+			name:           "正常系: OIDCプロバイダーのIDトークンのsubをuser_idとして使う",
+			token:          idToken(providerClaims),
+			expectedStatus: http.StatusOK,
+			expectedUserID: "player-42",
+		},
+		{
+			name: "正常系: 自身が発行したトークンも受け付ける",
+			token: func() string {
+				tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"user_id": "user123",
+					"iss":     "test-issuer",
+					"exp":     now.Add(time.Hour).Unix(),
+				}).SignedString([]byte(cfg.Secret))
+				require.NoError(t, err)
+				return tokenString
+			}(),
+			expectedStatus: http.StatusOK,
+			expectedUserID: "user123",
+		},
+		{
+			name: "異常系: subのないIDトークン",
+			token: idToken(jwt.MapClaims{
+				"iss": "https://accounts.example.com",
+				"aud": "gem-client",
+				"exp": now.Add(time.Hour).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "異常系: 別のクライアント向けのIDトークン",
+			token: idToken(jwt.MapClaims{
+				"iss": "https://accounts.example.com",
+				"aud": "other-client",
+				"sub": "player-42",
+				"exp": now.Add(time.Hour).Unix(),
+			}),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:  "異常系: ユーザーの一括失効より前に発行したIDトークン",
+			token: idToken(providerClaims),
+			setupDenylist: func() auth_token.Denylist {
+				d := denylist.NewMemoryDenylist()
+				require.NoError(t, d.RevokeUser(ctx, "player-42", now.Add(time.Second), now.Add(time.Hour)))
+				return d
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d auth_token.Denylist
+			if tt.setupDenylist != nil {
+				d = tt.setupDenylist()
+			}
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			middleware := AuthMiddleware(cfg, newTestKeyring(t, cfg), verifier, d, logger)
+			handler := middleware(func(c echo.Context) error {
+				return c.String(http.StatusOK, c.Get("user_id").(string))
+			})
+
+			err := handler(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, tt.expectedUserID, rec.Body.String())
+			}
+		})
+	}
+}
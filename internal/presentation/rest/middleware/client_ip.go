@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/netip"
+
+	"gem-server/internal/infrastructure/ipaccess"
+
+	"github.com/labstack/echo/v4"
+)
+
+// clientIP クライアントのIPアドレスを取得
+// X-Forwarded-For/X-Real-IPヘッダーは信頼するプロキシからの接続の場合のみ使用する
+func clientIP(c echo.Context, resolver *ipaccess.Resolver) (netip.Addr, bool) {
+	req := c.Request()
+	return resolver.ClientIP(req.RemoteAddr, req.Header.Values("X-Forwarded-For"), req.Header.Get("X-Real-IP"))
+}
@@ -3,11 +3,14 @@ package middleware
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
 
 	"github.com/labstack/echo/v4"
 
+	"gem-server/internal/domain/auth_token"
 	"gem-server/internal/domain/currency"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
@@ -61,6 +64,46 @@ func handleError(c echo.Context, err error, logger *otelinfra.Logger) error {
 		})
 	}
 
+	if errors.Is(err, currency.ErrInvalidExpiry) {
+		logger.Warn(ctx, "Invalid expiry", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_expiry",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, currency.ErrCurrencyNotTransferable) {
+		logger.Warn(ctx, "Currency not transferable", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "currency_not_transferable",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, currency.ErrSelfTransfer) {
+		logger.Warn(ctx, "Self transfer", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "self_transfer",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, currency.ErrRecipientNotFound) {
+		logger.Warn(ctx, "Recipient not found", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "recipient_not_found",
+			Message: err.Error(),
+		})
+	}
+
 	if errors.Is(err, currency.ErrCurrencyNotFound) {
 		logger.Warn(ctx, "Currency not found", map[string]interface{}{
 			"error": err.Error(),
@@ -81,6 +124,96 @@ func handleError(c echo.Context, err error, logger *otelinfra.Logger) error {
 		})
 	}
 
+	if errors.Is(err, transaction.ErrTransactionNotRefundable) {
+		logger.Warn(ctx, "Transaction not refundable", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "transaction_not_refundable",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrTransactionAlreadyRefunded) {
+		logger.Warn(ctx, "Transaction already refunded", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "transaction_already_refunded",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrDuplicateTransactionID) {
+		logger.Warn(ctx, "Duplicate transaction id", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "duplicate_transaction_id",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrReasonRequired) {
+		logger.Warn(ctx, "Reason required", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "reason_required",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrRequesterRequired) {
+		logger.Warn(ctx, "Requester required", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "requester_required",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrInvalidIdempotencyKey) {
+		logger.Warn(ctx, "Invalid idempotency key", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_idempotency_key",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrIdempotencyKeyConflict) {
+		logger.Warn(ctx, "Idempotency key conflict", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "idempotency_key_conflict",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrInvalidFilter) {
+		logger.Warn(ctx, "Invalid transaction filter", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_filter",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, transaction.ErrInvalidCursor) {
+		logger.Warn(ctx, "Invalid cursor", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_cursor",
+			Message: err.Error(),
+		})
+	}
+
 	if errors.Is(err, payment_request.ErrPaymentRequestNotFound) {
 		logger.Warn(ctx, "Payment request not found", map[string]interface{}{
 			"error": err.Error(),
@@ -161,6 +294,190 @@ func handleError(c echo.Context, err error, logger *otelinfra.Logger) error {
 		})
 	}
 
+	if errors.Is(err, redemption_code.ErrInvalidBatchSize) {
+		logger.Warn(ctx, "Invalid batch size", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_batch_size",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidBatchID) {
+		logger.Warn(ctx, "Invalid batch id", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_batch_id",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrBatchAlreadyExists) {
+		logger.Warn(ctx, "Code batch already exists", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "batch_already_exists",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidCodeSettings) {
+		logger.Warn(ctx, "Invalid code settings", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_code_settings",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidCodePattern) {
+		logger.Warn(ctx, "Invalid code pattern", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_code_pattern",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidRewards) {
+		logger.Warn(ctx, "Invalid rewards", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_rewards",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidFilter) {
+		logger.Warn(ctx, "Invalid code filter", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_filter",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidCodeUpdate) {
+		logger.Warn(ctx, "Invalid code update", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_code_update",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrInvalidEligibility) {
+		logger.Warn(ctx, "Invalid eligibility", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_eligibility",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrUserNotEligible) {
+		logger.Warn(ctx, "User not eligible for code", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "user_not_eligible",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrNewUsersOnly) {
+		logger.Warn(ctx, "Code is for new users only", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "new_users_only",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrCampaignLimitReached) {
+		logger.Warn(ctx, "Campaign redemption limit reached", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "campaign_limit_reached",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrCampaignExclusive) {
+		logger.Warn(ctx, "Campaign exclusive code already redeemed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "campaign_exclusive",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrBatchNotFound) {
+		logger.Warn(ctx, "Code batch not found", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "batch_not_found",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrExpirySweepInProgress) {
+		logger.Warn(ctx, "Code expiry sweep already running", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "expiry_sweep_in_progress",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, redemption_code.ErrRedemptionLockedOut) {
+		logger.Warn(ctx, "Redemption locked out", map[string]interface{}{
+			"error": err.Error(),
+		})
+		var lockoutErr *redemption_code.LockoutError
+		if errors.As(err, &lockoutErr) {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(time.Until(lockoutErr.Until))))
+		}
+		return c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "redemption_locked_out",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, auth_token.ErrInvalidRefreshToken) {
+		logger.Warn(ctx, "Invalid refresh token", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "invalid_refresh_token",
+			Message: err.Error(),
+		})
+	}
+
+	if errors.Is(err, auth_token.ErrInvalidToken) {
+		logger.Warn(ctx, "Invalid token", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_token",
+			Message: err.Error(),
+		})
+	}
+
 	// EchoのHTTPエラー
 	var httpErr *echo.HTTPError
 	if errors.As(err, &httpErr) {
@@ -2,15 +2,18 @@ package middleware
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
 
+	"gem-server/internal/domain/auth_token"
 	"gem-server/internal/domain/currency"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
@@ -113,6 +116,184 @@ func TestErrorHandlerMiddleware_TransactionNotFound(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rec.Code)
 }
 
+func TestErrorHandlerMiddleware_InvalidExpiry(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return currency.ErrInvalidExpiry
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_expiry")
+}
+
+func TestErrorHandlerMiddleware_CurrencyNotTransferable(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return currency.ErrCurrencyNotTransferable
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "currency_not_transferable")
+}
+
+func TestErrorHandlerMiddleware_RecipientNotFound(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return currency.ErrRecipientNotFound
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "recipient_not_found")
+}
+
+func TestErrorHandlerMiddleware_ReasonRequired(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return transaction.ErrReasonRequired
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "reason_required")
+}
+
+func TestErrorHandlerMiddleware_IdempotencyKeyConflict(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return transaction.ErrIdempotencyKeyConflict
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "idempotency_key_conflict")
+}
+
+func TestErrorHandlerMiddleware_InvalidFilter(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return fmt.Errorf("%w: unknown status", transaction.ErrInvalidFilter)
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_filter")
+}
+
+func TestErrorHandlerMiddleware_InvalidCursor(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return fmt.Errorf("%w: malformed cursor", transaction.ErrInvalidCursor)
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_cursor")
+}
+
+func TestErrorHandlerMiddleware_TransactionNotRefundable(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return transaction.ErrTransactionNotRefundable
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestErrorHandlerMiddleware_TransactionAlreadyRefunded(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return transaction.ErrTransactionAlreadyRefunded
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
 func TestErrorHandlerMiddleware_PaymentRequestNotFound(t *testing.T) {
 	tracer := noop.NewTracerProvider().Tracer("test")
 	logger := otelinfra.NewLogger(tracer)
@@ -227,6 +408,229 @@ func TestErrorHandlerMiddleware_UserAlreadyRedeemed(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestErrorHandlerMiddleware_InvalidCodePattern(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return fmt.Errorf("%w: length must be between 4 and 64", redemption_code.ErrInvalidCodePattern)
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_code_pattern")
+}
+
+func TestErrorHandlerMiddleware_InvalidRewards(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return fmt.Errorf("%w: duplicate currency type: free", redemption_code.ErrInvalidRewards)
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_rewards")
+}
+
+func TestErrorHandlerMiddleware_InvalidCodeUpdate(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPatch, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return fmt.Errorf("%w: cannot activate a code past valid_until", redemption_code.ErrInvalidCodeUpdate)
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_code_update")
+}
+
+func TestErrorHandlerMiddleware_Eligibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "引き換え条件が不正",
+			err:        fmt.Errorf("%w: campaign_id is required for campaign rules", redemption_code.ErrInvalidEligibility),
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid_eligibility",
+		},
+		{
+			name:       "対象外のユーザー",
+			err:        redemption_code.ErrUserNotEligible,
+			wantStatus: http.StatusForbidden,
+			wantError:  "user_not_eligible",
+		},
+		{
+			name:       "新規ユーザー限定",
+			err:        fmt.Errorf("%w: registered before 2024-04-01T00:00:00Z", redemption_code.ErrNewUsersOnly),
+			wantStatus: http.StatusForbidden,
+			wantError:  "new_users_only",
+		},
+		{
+			name:       "キャンペーン内の上限に到達",
+			err:        fmt.Errorf("%w: at most 1 redemptions per user", redemption_code.ErrCampaignLimitReached),
+			wantStatus: http.StatusForbidden,
+			wantError:  "campaign_limit_reached",
+		},
+		{
+			name:       "キャンペーン内の排他コード",
+			err:        redemption_code.ErrCampaignExclusive,
+			wantStatus: http.StatusForbidden,
+			wantError:  "campaign_exclusive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			middleware := ErrorHandlerMiddleware(logger)
+			handler := middleware(func(c echo.Context) error {
+				return tt.err
+			})
+
+			err := handler(c)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantError)
+		})
+	}
+}
+
+func TestErrorHandlerMiddleware_BatchNotFound(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return redemption_code.ErrBatchNotFound
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "batch_not_found")
+}
+
+func TestErrorHandlerMiddleware_RedemptionLockedOut(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return &redemption_code.LockoutError{Until: time.Now().Add(90 * time.Second)}
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "redemption_locked_out")
+	assert.Contains(t, []string{"89", "90"}, rec.Header().Get("Retry-After"))
+}
+
+func TestErrorHandlerMiddleware_ExpirySweepInProgress(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return redemption_code.ErrExpirySweepInProgress
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "expiry_sweep_in_progress")
+}
+
+func TestErrorHandlerMiddleware_InvalidRefreshToken(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return fmt.Errorf("%w: token has already been used", auth_token.ErrInvalidRefreshToken)
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_refresh_token")
+}
+
+func TestErrorHandlerMiddleware_InvalidToken(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("test")
+	logger := otelinfra.NewLogger(tracer)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := ErrorHandlerMiddleware(logger)
+	handler := middleware(func(c echo.Context) error {
+		return auth_token.ErrInvalidToken
+	})
+
+	err := handler(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid_token")
+}
+
 func TestErrorHandlerMiddleware_HTTPError(t *testing.T) {
 	tracer := noop.NewTracerProvider().Tracer("test")
 	logger := otelinfra.NewLogger(tracer)
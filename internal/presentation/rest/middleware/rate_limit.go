@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gem-server/internal/infrastructure/ipaccess"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitKeyFunc リクエストからレート制限のキーを取得する（空文字の場合は制限しない）
+type RateLimitKeyFunc func(c echo.Context) string
+
+// RateLimitMiddleware レート制限ミドルウェア
+// 制限を超えた場合は429とRetry-Afterヘッダーを返す。
+// Limiterが利用できない場合はリクエストを許可する
+func RateLimitMiddleware(limiter ratelimit.Limiter, keyFunc RateLimitKeyFunc, logger *otelinfra.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			key := keyFunc(c)
+			if key == "" {
+				return next(c)
+			}
+
+			res, err := limiter.Allow(ctx, key)
+			if err != nil {
+				logger.Warn(ctx, "Rate limiter unavailable", map[string]interface{}{
+					"error": err.Error(),
+				})
+				return next(c)
+			}
+
+			if !res.Allowed {
+				logger.Warn(ctx, "Rate limit exceeded", map[string]interface{}{
+					"path": c.Path(),
+				})
+				c.Response().Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(res.RetryAfter)))
+				return c.JSON(http.StatusTooManyRequests, ErrorResponse{
+					Error:   "rate_limit_exceeded",
+					Message: "Too many requests",
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RateLimitByIP クライアントのIPアドレスをキーにする（IPアドレスはresolverで求める）
+func RateLimitByIP(resolver *ipaccess.Resolver) RateLimitKeyFunc {
+	return func(c echo.Context) string {
+		ip, ok := clientIP(c, resolver)
+		if !ok {
+			return ""
+		}
+		return ip.String()
+	}
+}
+
+// RateLimitByUserID AuthMiddlewareが設定したユーザーIDをキーにする
+func RateLimitByUserID(c echo.Context) string {
+	userID, _ := c.Get("user_id").(string)
+	return userID
+}
+
+// RateLimitByAPIKey APIキーをキーにする（ハッシュ化して保持する）
+func RateLimitByAPIKey(c echo.Context) string {
+	apiKey := c.Request().Header.Get("X-API-Key")
+	if apiKey == "" {
+		return ""
+	}
+	return ratelimit.HashKey(apiKey)
+}
+
+// retryAfterSeconds Retry-Afterヘッダーの秒数（切り上げ、最小1秒）
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
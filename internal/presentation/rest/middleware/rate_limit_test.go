@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gem-server/internal/infrastructure/ipaccess"
+	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/ratelimit"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// stubLimiter 固定の結果を返すLimiter
+type stubLimiter struct {
+	result *ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (l *stubLimiter) Allow(ctx context.Context, key string) (*ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	return l.result, l.err
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	// httptest.NewRequestの接続元は192.0.2.1
+	direct, err := ipaccess.NewResolver(nil)
+	require.NoError(t, err)
+	viaProxy, err := ipaccess.NewResolver([]string{"192.0.2.0/24"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		limiter            *stubLimiter
+		keyFunc            RateLimitKeyFunc
+		setupRequest       func(req *http.Request, c echo.Context)
+		expectedStatus     int
+		expectedRetryAfter string
+		expectedKeys       []string
+	}{
+		{
+			name:           "正常系: 制限内",
+			limiter:        &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc:        RateLimitByIP(direct),
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"192.0.2.1"},
+		},
+		{
+			name:    "正常系: 信頼しない接続元の転送ヘッダーは無視する",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc: RateLimitByIP(direct),
+			setupRequest: func(req *http.Request, c echo.Context) {
+				req.Header.Set("X-Forwarded-For", "203.0.113.9")
+				req.Header.Set("X-Real-IP", "203.0.113.9")
+			},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"192.0.2.1"},
+		},
+		{
+			name:    "正常系: 信頼するプロキシ経由の場合は転送ヘッダーのIPアドレス",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc: RateLimitByIP(viaProxy),
+			setupRequest: func(req *http.Request, c echo.Context) {
+				req.Header.Set("X-Forwarded-For", "203.0.113.9")
+			},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"203.0.113.9"},
+		},
+		{
+			name:    "異常系: 制限超過",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: false, RetryAfter: 1500 * time.Millisecond}},
+			keyFunc: RateLimitByUserID,
+			setupRequest: func(req *http.Request, c echo.Context) {
+				c.Set("user_id", "user123")
+			},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "2",
+			expectedKeys:       []string{"user123"},
+		},
+		{
+			name:    "異常系: 制限超過（Retry-Afterは最小1秒）",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: false, RetryAfter: 10 * time.Millisecond}},
+			keyFunc: RateLimitByUserID,
+			setupRequest: func(req *http.Request, c echo.Context) {
+				c.Set("user_id", "user123")
+			},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedRetryAfter: "1",
+			expectedKeys:       []string{"user123"},
+		},
+		{
+			name:    "正常系: APIキーはハッシュ化してキーにする",
+			limiter: &stubLimiter{result: &ratelimit.Result{Allowed: true}},
+			keyFunc: RateLimitByAPIKey,
+			setupRequest: func(req *http.Request, c echo.Context) {
+				req.Header.Set("X-API-Key", "test-api-key")
+			},
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{ratelimit.HashKey("test-api-key")},
+		},
+		{
+			name:           "正常系: キーがない場合は制限しない",
+			limiter:        &stubLimiter{result: &ratelimit.Result{Allowed: false}},
+			keyFunc:        RateLimitByUserID,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "正常系: Limiterが利用できない場合は許可",
+			limiter:        &stubLimiter{err: errors.New("connection refused")},
+			keyFunc:        RateLimitByIP(direct),
+			expectedStatus: http.StatusOK,
+			expectedKeys:   []string{"192.0.2.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			tracer := noop.NewTracerProvider().Tracer("test")
+			logger := otelinfra.NewLogger(tracer)
+
+			middlewareFunc := RateLimitMiddleware(tt.limiter, tt.keyFunc, logger)
+			handler := middlewareFunc(func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.setupRequest != nil {
+				tt.setupRequest(req, c)
+			}
+
+			err := handler(c)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedRetryAfter, rec.Header().Get("Retry-After"))
+			assert.Equal(t, tt.expectedKeys, tt.limiter.keys)
+		})
+	}
+}
+
+func TestRateLimitMiddleware_MemoryLimiter(t *testing.T) {
+	e := echo.New()
+	logger := otelinfra.NewLogger(noop.NewTracerProvider().Tracer("test"))
+	limiter := ratelimit.NewMemoryLimiter(ratelimit.Limit{Rate: 1, Burst: 2})
+	resolver, err := ipaccess.NewResolver(nil)
+	require.NoError(t, err)
+
+	e.POST("/codes/redeem", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}, RateLimitMiddleware(limiter, RateLimitByIP(resolver), logger))
+
+	// 転送ヘッダーを変えても同じ接続元として制限する
+	statuses := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/codes/redeem", nil)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("203.0.113.%d", i))
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		statuses = append(statuses, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, statuses)
+}
@@ -8,8 +8,15 @@ import (
 	currencyapp "gem-server/internal/application/currency"
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/domain/auth_token"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/ipaccess"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/oidc"
+	"gem-server/internal/infrastructure/ratelimit"
 	"gem-server/internal/presentation/rest/handler"
 	restmiddleware "gem-server/internal/presentation/rest/middleware"
 
@@ -32,12 +39,27 @@ func NewRouter(
 	cfg *config.Config,
 	logger *otelinfra.Logger,
 	metrics *otelinfra.Metrics,
+	limiters *ratelimit.Limiters,
+	keyring *jwtkeys.Keyring,
+	oidcVerifier *oidc.Verifier,
+	denylist auth_token.Denylist,
+	authenticator *apikey.Authenticator,
 	authService *authapp.AuthApplicationService,
 	currencyService *currencyapp.CurrencyApplicationService,
 	paymentService *paymentapp.PaymentApplicationService,
 	redemptionService *redemptionapp.CodeRedemptionApplicationService,
 	historyService *historyapp.HistoryApplicationService,
 ) (*Router, error) {
+	// クライアントのIPアドレスの判定（転送ヘッダーは信頼するプロキシからの接続の場合のみ使用する）
+	resolver, err := ipaccess.NewResolver(cfg.Server.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	adminAllowlist, err := ipaccess.NewAllowlist(cfg.AdminAPI.AllowedIPs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid admin API allowed IPs: %w", err)
+	}
+
 	e := echo.New()
 
 	// Echoのデフォルトエラーハンドラーを無効化（カスタムエラーハンドラーを使用）
@@ -56,7 +78,7 @@ func NewRouter(
 	historyHandler := handler.NewHistoryHandler(historyService)
 
 	// ルーティングの設定
-	setupRoutes(e, cfg, logger, authHandler, currencyHandler, paymentHandler, redemptionHandler, historyHandler)
+	setupRoutes(e, cfg, logger, limiters, keyring, oidcVerifier, denylist, authenticator, adminAllowlist, resolver, authHandler, currencyHandler, paymentHandler, redemptionHandler, historyHandler)
 
 	// Swagger UI / ReDoc統合
 	SetupSwagger(e)
@@ -79,7 +101,7 @@ func setupMiddleware(e *echo.Echo, cfg *config.Config, logger *otelinfra.Logger,
 	// CORS設定
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"}, // 本番環境では適切に設定
-		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.OPTIONS},
+		AllowMethods: []string{echo.GET, echo.POST, echo.PUT, echo.PATCH, echo.DELETE, echo.OPTIONS},
 		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
 	}))
 
@@ -107,6 +129,13 @@ func setupRoutes(
 	e *echo.Echo,
 	cfg *config.Config,
 	logger *otelinfra.Logger,
+	limiters *ratelimit.Limiters,
+	keyring *jwtkeys.Keyring,
+	oidcVerifier *oidc.Verifier,
+	denylist auth_token.Denylist,
+	authenticator *apikey.Authenticator,
+	adminAllowlist *ipaccess.Allowlist,
+	resolver *ipaccess.Resolver,
 	authHandler *handler.AuthHandler,
 	currencyHandler *handler.CurrencyHandler,
 	paymentHandler *handler.PaymentHandler,
@@ -116,29 +145,66 @@ func setupRoutes(
 	// Payment Handler関連の静的ファイル配信
 	setupPaymentHandlerRoutes(e)
 
-	// API v1グループ
-	api := e.Group("/api/v1")
+	// レート制限（無効な識別子は制限しない）
+	var ipLimit, userLimit, apiKeyLimit []echo.MiddlewareFunc
+	if limiters != nil {
+		if limiters.IP != nil {
+			ipLimit = append(ipLimit, restmiddleware.RateLimitMiddleware(limiters.IP, restmiddleware.RateLimitByIP(resolver), logger))
+		}
+		if limiters.User != nil {
+			userLimit = append(userLimit, restmiddleware.RateLimitMiddleware(limiters.User, restmiddleware.RateLimitByUserID, logger))
+		}
+		if limiters.APIKey != nil {
+			apiKeyLimit = append(apiKeyLimit, restmiddleware.RateLimitMiddleware(limiters.APIKey, restmiddleware.RateLimitByAPIKey, logger))
+		}
+	}
 
-	// ユーザーAPI（JWT認証）
-	userAPI := api.Group("", restmiddleware.AuthMiddleware(&cfg.JWT, logger))
+	// API v1グループ（IPアドレスごとのレート制限は認証より前に適用）
+	api := e.Group("/api/v1", ipLimit...)
+
+	// トークン更新（リフレッシュトークンで認証するためJWT認証は不要）
+	api.POST("/auth/refresh", authHandler.RefreshToken)
+
+	// ユーザーAPI（JWT認証またはOIDCプロバイダーのIDトークン、ユーザーIDごとのレート制限）
+	userAPI := api.Group("", append([]echo.MiddlewareFunc{restmiddleware.AuthMiddleware(&cfg.JWT, keyring, oidcVerifier, denylist, logger)}, userLimit...)...)
 	userAPI.GET("/me/balance", currencyHandler.GetBalance)
 	userAPI.GET("/me/transactions", historyHandler.GetTransactionHistory)
+	userAPI.POST("/me/transfers", currencyHandler.TransferCurrency)
 	userAPI.POST("/payment/process", paymentHandler.ProcessPayment)
 	userAPI.POST("/codes/redeem", redemptionHandler.RedeemCode)
+	userAPI.GET("/me/redemptions", redemptionHandler.ListMyRedemptions)
 
-	// 管理API（APIキー認証）
-	adminAPI := api.Group("/admin", restmiddleware.APIKeyMiddleware(&cfg.AdminAPI, logger))
-	adminAPI.POST("/users/:user_id/issue_token", authHandler.GenerateToken)
-	adminAPI.POST("/users/:user_id/grant", currencyHandler.GrantCurrency)
-	adminAPI.POST("/users/:user_id/consume", currencyHandler.ConsumeCurrency)
-	adminAPI.GET("/users/:user_id/balance", currencyHandler.GetBalanceAdmin)
-	adminAPI.GET("/users/:user_id/transactions", historyHandler.GetTransactionHistoryAdmin)
+	// 管理API（APIキー認証、APIキーごとのレート制限、ルートごとに必要なスコープを確認）
+	adminAPI := api.Group("/admin", append([]echo.MiddlewareFunc{restmiddleware.APIKeyMiddleware(&cfg.AdminAPI, authenticator, adminAllowlist, resolver, logger)}, apiKeyLimit...)...)
+	scope := func(s api_key.Scope) echo.MiddlewareFunc {
+		return restmiddleware.RequireScope(s, logger)
+	}
+	adminAPI.POST("/users/:user_id/issue_token", authHandler.GenerateToken, scope(api_key.ScopeTokensIssue))
+	adminAPI.POST("/users/:user_id/revoke_tokens", authHandler.RevokeUserTokens, scope(api_key.ScopeTokensRevoke))
+	adminAPI.POST("/tokens/revoke", authHandler.RevokeToken, scope(api_key.ScopeTokensRevoke))
+	adminAPI.POST("/users/:user_id/grant", currencyHandler.GrantCurrency, scope(api_key.ScopeCurrencyGrant))
+	adminAPI.POST("/users/:user_id/consume", currencyHandler.ConsumeCurrency, scope(api_key.ScopeCurrencyConsume))
+	adminAPI.POST("/users/:user_id/compensate", currencyHandler.CompensateCurrency, scope(api_key.ScopeCurrencyCompensate))
+	adminAPI.GET("/users/:user_id/balance", currencyHandler.GetBalanceAdmin, scope(api_key.ScopeCurrencyRead))
+	adminAPI.GET("/users/:user_id/transactions", historyHandler.GetTransactionHistoryAdmin, scope(api_key.ScopeHistoryRead))
+	adminAPI.GET("/transactions/export", historyHandler.ExportTransactions, scope(api_key.ScopeHistoryRead))
+	adminAPI.POST("/transactions/:transaction_id/refund", currencyHandler.RefundTransaction, scope(api_key.ScopeCurrencyRefund))
 
 	// 引き換えコード管理API
-	adminAPI.POST("/codes", redemptionHandler.CreateCode)
-	adminAPI.DELETE("/codes/:code", redemptionHandler.DeleteCode)
-	adminAPI.GET("/codes/:code", redemptionHandler.GetCode)
-	adminAPI.GET("/codes", redemptionHandler.ListCodes)
+	adminAPI.POST("/codes", redemptionHandler.CreateCode, scope(api_key.ScopeCodesManage))
+	adminAPI.POST("/codes/expire_sweep", redemptionHandler.ExpireCodes, scope(api_key.ScopeCodesManage))
+	adminAPI.PATCH("/codes/:code", redemptionHandler.UpdateCode, scope(api_key.ScopeCodesManage))
+	adminAPI.DELETE("/codes/:code", redemptionHandler.DeleteCode, scope(api_key.ScopeCodesManage))
+	adminAPI.GET("/codes/:code", redemptionHandler.GetCode, scope(api_key.ScopeCodesManage))
+	adminAPI.GET("/codes/:code/redemptions", redemptionHandler.ListCodeRedemptions, scope(api_key.ScopeCodesManage))
+	adminAPI.GET("/codes/:code/stats", redemptionHandler.GetCodeStats, scope(api_key.ScopeCodesManage))
+	adminAPI.GET("/codes", redemptionHandler.ListCodes, scope(api_key.ScopeCodesManage))
+	adminAPI.POST("/code_batches", redemptionHandler.GenerateCodes, scope(api_key.ScopeCodesManage))
+	adminAPI.GET("/code_batches/:batch_id/export", redemptionHandler.ExportBatchCodes, scope(api_key.ScopeCodesManage))
+	adminAPI.DELETE("/users/:user_id/redemption_lockout", redemptionHandler.ClearRedemptionLockout, scope(api_key.ScopeCodesManage))
+
+	// アクセストークンの検証用公開鍵（認証不要）
+	e.GET("/.well-known/jwks.json", authHandler.GetJWKS)
 
 	// ヘルスチェックエンドポイント（認証不要）
 	e.GET("/health", func(c echo.Context) error {
@@ -2,10 +2,13 @@ package rest
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,13 +17,20 @@ import (
 	currencyapp "gem-server/internal/application/currency"
 	historyapp "gem-server/internal/application/history"
 	paymentapp "gem-server/internal/application/payment"
+	"gem-server/internal/domain/api_key"
+	"gem-server/internal/domain/auth_token"
 	"gem-server/internal/domain/currency"
+	"gem-server/internal/domain/idgen"
 	"gem-server/internal/domain/payment_request"
 	"gem-server/internal/domain/redemption_code"
 	"gem-server/internal/domain/service"
 	"gem-server/internal/domain/transaction"
+	"gem-server/internal/infrastructure/apikey"
 	"gem-server/internal/infrastructure/config"
+	"gem-server/internal/infrastructure/denylist"
+	"gem-server/internal/infrastructure/jwtkeys"
 	otelinfra "gem-server/internal/infrastructure/observability/otel"
+	"gem-server/internal/infrastructure/ratelimit"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
@@ -70,14 +80,32 @@ func (m *MockTransactionRepository) FindByTransactionID(ctx context.Context, tra
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionRepository) FindByUserID(ctx context.Context, userID string, limit, offset int) ([]*transaction.Transaction, error) {
-	args := m.Called(ctx, userID, limit, offset)
+func (m *MockTransactionRepository) FindByFilter(ctx context.Context, filter transaction.Filter, limit, offset int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) FindByFilterAfterCursor(ctx context.Context, filter transaction.Filter, cursor transaction.Cursor, limit int) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, filter, cursor, limit)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) StreamByFilter(ctx context.Context, filter transaction.Filter, fn func(*transaction.Transaction) error) error {
+	args := m.Called(ctx, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) CountByFilter(ctx context.Context, filter transaction.Filter) (int, error) {
+	args := m.Called(ctx, filter)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context, paymentRequestID string) (*transaction.Transaction, error) {
 	args := m.Called(ctx, paymentRequestID)
 	if args.Get(0) == nil {
@@ -86,6 +114,19 @@ func (m *MockTransactionRepository) FindByPaymentRequestID(ctx context.Context,
 	return args.Get(0).(*transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) FindByIdempotencyKey(ctx context.Context, userID string, idempotencyKey string) ([]*transaction.Transaction, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) ReserveIdempotencyKey(ctx context.Context, userID string, idempotencyKey string, requestHash string) error {
+	args := m.Called(ctx, userID, idempotencyKey, requestHash)
+	return args.Error(0)
+}
+
 // MockPaymentRequestRepository モックPaymentRequestリポジトリ
 type MockPaymentRequestRepository struct {
 	mock.Mock
@@ -109,15 +150,108 @@ func (m *MockPaymentRequestRepository) Update(ctx context.Context, pr *payment_r
 	return args.Error(0)
 }
 
+// MockLotRepository モックロットリポジトリ
+type MockLotRepository struct {
+	mock.Mock
+}
+
+func (m *MockLotRepository) Create(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) Save(ctx context.Context, lot *currency.Lot) error {
+	args := m.Called(ctx, lot)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindAvailableByUserIDAndType(ctx context.Context, userID string, currencyType currency.CurrencyType) ([]*currency.Lot, error) {
+	args := m.Called(ctx, userID, currencyType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) FindExpired(ctx context.Context, now time.Time, limit int) ([]*currency.Lot, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*currency.Lot), args.Error(1)
+}
+
+func (m *MockLotRepository) SaveConsumptions(ctx context.Context, transactionID string, consumptions []currency.LotConsumption) error {
+	args := m.Called(ctx, transactionID, consumptions)
+	return args.Error(0)
+}
+
+func (m *MockLotRepository) FindConsumptionsByTransactionID(ctx context.Context, transactionID string) ([]currency.LotConsumption, error) {
+	args := m.Called(ctx, transactionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]currency.LotConsumption), args.Error(1)
+}
+
+// newEmptyLotRepository 有効期限付きロットが存在しない状態のモックロットリポジトリを作成
+func newEmptyLotRepository() *MockLotRepository {
+	m := new(MockLotRepository)
+	m.On("FindAvailableByUserIDAndType", mock.Anything, mock.Anything, mock.Anything).Return([]*currency.Lot{}, nil).Maybe()
+	m.On("FindConsumptionsByTransactionID", mock.Anything, mock.Anything).Return([]currency.LotConsumption{}, nil).Maybe()
+	return m
+}
+
+// MockRefreshTokenRepository モックリフレッシュトークンリポジトリ
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Save(ctx context.Context, token *auth_token.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*auth_token.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*auth_token.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Update(ctx context.Context, token *auth_token.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, familyID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID string, revokedAt time.Time) (int, error) {
+	args := m.Called(ctx, userID, revokedAt)
+	return args.Int(0), args.Error(1)
+}
+
+// newRefreshTokenRepository リフレッシュトークンの保存と失効だけを受け付けるモックリフレッシュトークンリポジトリを作成
+func newRefreshTokenRepository() *MockRefreshTokenRepository {
+	m := new(MockRefreshTokenRepository)
+	m.On("Save", mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.On("RevokeByUserID", mock.Anything, mock.Anything, mock.Anything).Return(1, nil).Maybe()
+	return m
+}
+
 // MockTransactionManager モックトランザクションマネージャー
 type MockTransactionManager struct {
 	mock.Mock
 }
 
-func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(*sql.Tx) error) error {
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(context.Context) error) error {
 	args := m.Called(ctx, fn)
 	if fn != nil {
-		return fn(nil)
+		return fn(ctx)
 	}
 	return args.Error(0)
 }
@@ -140,16 +274,46 @@ func (m *MockRedemptionCodeRepository) Update(ctx context.Context, code *redempt
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) IncrementUses(ctx context.Context, code string) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) HasUserRedeemed(ctx context.Context, code string, userID string) (bool, error) {
 	args := m.Called(ctx, code, userID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRedemptionCodeRepository) CountUserCampaignRedemptions(ctx context.Context, campaignID string, userID string) (redemption_code.CampaignUsage, error) {
+	args := m.Called(ctx, campaignID, userID)
+	return args.Get(0).(redemption_code.CampaignUsage), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) LockUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindUserFirstSeenAt(ctx context.Context, userID string) (time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockRedemptionCodeRepository) SaveRedemption(ctx context.Context, redemption *redemption_code.CodeRedemption) error {
 	args := m.Called(ctx, redemption)
 	return args.Error(0)
 }
 
+func (m *MockRedemptionCodeRepository) UpdateSettings(ctx context.Context, code *redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, code)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) SaveAuditLog(ctx context.Context, log *redemption_code.CodeAuditLog) error {
+	args := m.Called(ctx, log)
+	return args.Error(0)
+}
+
 func (m *MockRedemptionCodeRepository) Create(ctx context.Context, code *redemption_code.RedemptionCode) error {
 	args := m.Called(ctx, code)
 	return args.Error(0)
@@ -160,17 +324,78 @@ func (m *MockRedemptionCodeRepository) Delete(ctx context.Context, code string)
 	return args.Error(0)
 }
 
-func (m *MockRedemptionCodeRepository) FindAll(ctx context.Context, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
-	args := m.Called(ctx, limit, offset)
+func (m *MockRedemptionCodeRepository) CreateBatch(ctx context.Context, codes []*redemption_code.RedemptionCode) error {
+	args := m.Called(ctx, codes)
+	return args.Error(0)
+}
+
+func (m *MockRedemptionCodeRepository) FindByBatchID(ctx context.Context, batchID string) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, batchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindByFilter(ctx context.Context, filter redemption_code.Filter, limit, offset int) ([]*redemption_code.RedemptionCode, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
 	return args.Get(0).([]*redemption_code.RedemptionCode), args.Int(1), args.Error(2)
 }
 
+func (m *MockRedemptionCodeRepository) FindRedemptionsByCode(ctx context.Context, code string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, code, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) FindRedemptionsByUserID(ctx context.Context, userID string, limit, offset int) ([]*redemption_code.CodeRedemption, int, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*redemption_code.CodeRedemption), args.Int(1), args.Error(2)
+}
+
+func (m *MockRedemptionCodeRepository) GetCodeStats(ctx context.Context, code string) (redemption_code.CodeStats, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(redemption_code.CodeStats), args.Error(1)
+}
+
+func (m *MockRedemptionCodeRepository) FindExpiredActive(ctx context.Context, now time.Time, limit int) ([]*redemption_code.RedemptionCode, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*redemption_code.RedemptionCode), args.Error(1)
+}
+
+// writeTestAPIKeysFile スコープを限定した管理APIキーの定義ファイルを作成
+// readonly-toolはスコープなし、support-toolは残高の参照のみ
+func writeTestAPIKeysFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "api_keys.json")
+	content := `[
+		{"name": "readonly-tool", "key_hash": "` + api_key.HashKey("readonly-key") + `", "scopes": []},
+		{"name": "support-tool", "key_hash": "` + api_key.HashKey("support-key") + `", "scopes": ["currency:read"]}
+	]`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
 // setupTestRouter テスト用のルーターをセットアップ
 func setupTestRouter(t *testing.T) (*Router, *MockCurrencyRepository, *MockTransactionRepository, *MockPaymentRequestRepository, *MockTransactionManager) {
 	t.Helper()
+	return setupTestRouterWithLimiters(t, nil)
+}
+
+// setupTestRouterWithLimiters レート制限付きのテスト用ルーターをセットアップ
+func setupTestRouterWithLimiters(t *testing.T, limiters *ratelimit.Limiters) (*Router, *MockCurrencyRepository, *MockTransactionRepository, *MockPaymentRequestRepository, *MockTransactionManager) {
+	t.Helper()
 
 	cfg := &config.Config{
 		JWT: config.JWTConfig{
@@ -179,8 +404,9 @@ func setupTestRouter(t *testing.T) (*Router, *MockCurrencyRepository, *MockTrans
 			Issuer:     "test-issuer",
 		},
 		AdminAPI: config.AdminAPIConfig{
-			Enabled: true,
-			APIKey:  "test-admin-api-key",
+			Enabled:  true,
+			APIKey:   "test-admin-api-key",
+			KeysFile: writeTestAPIKeysFile(t),
 		},
 	}
 
@@ -197,20 +423,39 @@ func setupTestRouter(t *testing.T) (*Router, *MockCurrencyRepository, *MockTrans
 
 	currencyService := service.NewCurrencyService(mockCurrencyRepo)
 
-	authService := authapp.NewAuthApplicationService(&cfg.JWT, logger)
+	keyring, err := jwtkeys.NewKeyring(&cfg.JWT)
+	require.NoError(t, err)
+	tokenDenylist := denylist.NewMemoryDenylist()
+	authenticator, err := apikey.NewAuthenticator(&cfg.AdminAPI, nil)
+	require.NoError(t, err)
+	authService := authapp.NewAuthApplicationService(
+		&cfg.JWT,
+		keyring,
+		newRefreshTokenRepository(),
+		mockTxManager,
+		tokenDenylist,
+		idgen.NewUUIDv7Generator(),
+		logger,
+	)
 	currencyAppService := currencyapp.NewCurrencyApplicationService(
 		mockCurrencyRepo,
 		mockTransactionRepo,
+		newEmptyLotRepository(),
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		nil,
 		currencyService,
+		nil,
 		logger,
 		metrics,
 	)
 	paymentAppService := paymentapp.NewPaymentApplicationService(
 		mockCurrencyRepo,
 		mockTransactionRepo,
+		newEmptyLotRepository(),
 		mockPaymentRequestRepo,
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
 		logger,
 		metrics,
 	)
@@ -219,6 +464,9 @@ func setupTestRouter(t *testing.T) (*Router, *MockCurrencyRepository, *MockTrans
 		mockTransactionRepo,
 		mockRedemptionCodeRepo,
 		mockTxManager,
+		idgen.NewUUIDv7Generator(),
+		nil,
+		nil,
 		logger,
 		metrics,
 	)
@@ -232,6 +480,11 @@ func setupTestRouter(t *testing.T) (*Router, *MockCurrencyRepository, *MockTrans
 		cfg,
 		logger,
 		metrics,
+		limiters,
+		keyring,
+		nil,
+		tokenDenylist,
+		authenticator,
 		authService,
 		currencyAppService,
 		paymentAppService,
@@ -272,6 +525,23 @@ func TestRouter_HealthCheck(t *testing.T) {
 	assert.Equal(t, "ok", response["status"])
 }
 
+func TestRouter_JWKSEndpoint(t *testing.T) {
+	router, _, _, _, _ := setupTestRouter(t)
+
+	// 認証なしで公開鍵一覧を取得できる
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	router.echo.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response map[string][]interface{}
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err)
+	assert.Contains(t, response, "keys")
+}
+
 func TestRouter_AuthTokenEndpoint(t *testing.T) {
 	router, _, _, _, _ := setupTestRouter(t)
 
@@ -347,6 +617,21 @@ func TestRouter_Middleware(t *testing.T) {
 	// 実際の実装に応じて確認
 }
 
+func TestRouter_CORSPreflight(t *testing.T) {
+	router, _, _, _, _ := setupTestRouter(t)
+
+	// 引き換えコードの更新（PATCH）をブラウザから呼び出せる
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/admin/codes/UPDATECODE", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://admin.example.com")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPatch)
+	rec := httptest.NewRecorder()
+
+	router.echo.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Contains(t, rec.Header().Get(echo.HeaderAccessControlAllowMethods), http.MethodPatch)
+}
+
 func TestRouter_SwaggerEndpoints(t *testing.T) {
 	router, _, _, _, _ := setupTestRouter(t)
 
@@ -511,6 +796,39 @@ func TestRouter_AuthenticatedEndpoints(t *testing.T) {
 	}
 }
 
+func TestRouter_RateLimit(t *testing.T) {
+	limiters := &ratelimit.Limiters{
+		IP:     ratelimit.NewMemoryLimiter(ratelimit.Limit{Rate: 0.001, Burst: 2}),
+		APIKey: ratelimit.NewMemoryLimiter(ratelimit.Limit{Rate: 0.001, Burst: 1}),
+	}
+	router, _, _, _, _ := setupTestRouterWithLimiters(t, limiters)
+
+	send := func(method, path, ip string, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = ip + ":50000"
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		router.echo.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// IPアドレスごとの制限は認証の失敗にも適用される
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/api/v1/me/balance", "192.0.2.1", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, "/api/v1/me/balance", "192.0.2.1", "").Code)
+	rec := send(http.MethodGet, "/api/v1/me/balance", "192.0.2.1", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	// ヘルスチェックは制限しない
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/health", "192.0.2.1", "").Code)
+
+	// APIキーごとの制限は別のIPアドレスからでも共有される
+	assert.Equal(t, http.StatusOK, send(http.MethodPost, "/api/v1/admin/users/user123/issue_token", "192.0.2.2", "test-admin-api-key").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "/api/v1/admin/users/user123/issue_token", "192.0.2.3", "test-admin-api-key").Code)
+}
+
 func TestRouter_StartShutdown(t *testing.T) {
 	router, _, _, _, _ := setupTestRouter(t)
 
@@ -568,3 +886,90 @@ func TestRouter_RouteRegistration(t *testing.T) {
 
 	assert.Greater(t, len(routes), 0, "ルートが登録されていることを確認")
 }
+
+func TestRouter_AdminAPIKeyScopes(t *testing.T) {
+	router, mockCurrencyRepo, _, _, _ := setupTestRouter(t)
+
+	t.Run("異常系: スコープのないキーはすべての管理APIで拒否される", func(t *testing.T) {
+		param := regexp.MustCompile(`:[a-z_]+`)
+		adminRoutes := 0
+		for _, route := range router.echo.Routes() {
+			// グループのミドルウェア用に登録されるワイルドカードのルートは除く
+			if !strings.HasPrefix(route.Path, "/api/v1/admin/") || strings.HasSuffix(route.Path, "*") {
+				continue
+			}
+			adminRoutes++
+
+			req := httptest.NewRequest(route.Method, param.ReplaceAllString(route.Path, "x"), strings.NewReader("{}"))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("X-API-Key", "readonly-key")
+			rec := httptest.NewRecorder()
+
+			router.echo.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusForbidden, rec.Code, "%s %s", route.Method, route.Path)
+			assert.Contains(t, rec.Body.String(), "insufficient_scope", "%s %s", route.Method, route.Path)
+		}
+		assert.Greater(t, adminRoutes, 0)
+	})
+
+	t.Run("正常系: 許可されたスコープの管理APIのみ呼び出せる", func(t *testing.T) {
+		mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypePaid).Return(currency.MustNewCurrency("user123", currency.CurrencyTypePaid, 1000, 1), nil)
+		mockCurrencyRepo.On("FindByUserIDAndType", mock.Anything, "user123", currency.CurrencyTypeFree).Return(currency.MustNewCurrency("user123", currency.CurrencyTypeFree, 500, 1), nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/users/user123/balance", nil)
+		req.Header.Set("X-API-Key", "support-key")
+		rec := httptest.NewRecorder()
+		router.echo.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/user123/grant", strings.NewReader(`{"currency_type":"free","amount":"100"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set("X-API-Key", "support-key")
+		rec = httptest.NewRecorder()
+		router.echo.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestRouter_TokenRevocation(t *testing.T) {
+	router, _, _, _, _ := setupTestRouter(t)
+
+	// 認証トークンを取得（管理API経由）
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/user123/issue_token", nil)
+	req.Header.Set("X-API-Key", "test-admin-api-key")
+	rec := httptest.NewRecorder()
+	router.echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var tokenResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tokenResp))
+	token := tokenResp["token"].(string)
+	assert.NotEmpty(t, tokenResp["refresh_token"])
+
+	// ユーザーのトークンを一括失効
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/admin/users/user123/revoke_tokens", nil)
+	req.Header.Set("X-API-Key", "test-admin-api-key")
+	rec = httptest.NewRecorder()
+	router.echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// 失効したトークンはユーザーAPIで拒否される
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/me/balance", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	rec = httptest.NewRecorder()
+	router.echo.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Token has been revoked")
+}
+
+func TestRouter_RefreshTokenEndpoint(t *testing.T) {
+	router, _, _, _, _ := setupTestRouter(t)
+
+	// JWT認証なしで呼び出せる（リフレッシュトークンがない場合は400）
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	router.echo.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,2 @@
+-- Drop currency_lots table
+DROP TABLE IF EXISTS currency_lots;
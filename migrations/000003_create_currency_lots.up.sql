@@ -0,0 +1,15 @@
+-- Create currency_lots table for time-limited currency
+CREATE TABLE currency_lots (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    lot_id VARCHAR(255) UNIQUE NOT NULL COMMENT '付与トランザクションID',
+    user_id VARCHAR(255) NOT NULL,
+    currency_type ENUM('paid', 'free') NOT NULL,
+    amount BIGINT NOT NULL COMMENT '付与時の数量',
+    remaining BIGINT NOT NULL COMMENT '未消費・未失効の数量',
+    expires_at TIMESTAMP NOT NULL COMMENT '有効期限',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    INDEX idx_user_currency_expires (user_id, currency_type, expires_at),
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
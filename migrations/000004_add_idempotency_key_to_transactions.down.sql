@@ -0,0 +1,5 @@
+-- Remove idempotency key columns from transactions table
+ALTER TABLE transactions
+DROP INDEX idx_user_idempotency_key,
+DROP COLUMN request_hash,
+DROP COLUMN idempotency_key;
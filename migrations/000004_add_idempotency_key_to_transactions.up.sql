@@ -0,0 +1,5 @@
+-- Add idempotency key columns to transactions table
+ALTER TABLE transactions
+ADD COLUMN idempotency_key VARCHAR(255) COMMENT 'クライアント指定の冪等性キー',
+ADD COLUMN request_hash CHAR(64) COMMENT '冪等性キーに紐づくリクエスト内容のSHA-256ハッシュ',
+ADD INDEX idx_user_idempotency_key (user_id, idempotency_key);
@@ -0,0 +1,3 @@
+-- Remove transfer transaction types
+ALTER TABLE transactions
+MODIFY COLUMN transaction_type ENUM('grant', 'consume', 'refund', 'expire', 'compensate') NOT NULL;
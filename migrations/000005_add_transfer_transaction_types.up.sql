@@ -0,0 +1,3 @@
+-- Add transfer transaction types
+ALTER TABLE transactions
+MODIFY COLUMN transaction_type ENUM('grant', 'consume', 'refund', 'expire', 'compensate', 'transfer_out', 'transfer_in') NOT NULL;
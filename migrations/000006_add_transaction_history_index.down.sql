@@ -0,0 +1,3 @@
+-- Remove composite index for filtered transaction history queries
+ALTER TABLE transactions
+DROP INDEX idx_user_created_at;
@@ -0,0 +1,3 @@
+-- Add composite index for filtered transaction history queries
+ALTER TABLE transactions
+ADD INDEX idx_user_created_at (user_id, created_at, id);
@@ -0,0 +1,4 @@
+-- Restore history index ordered by surrogate id
+ALTER TABLE transactions
+DROP INDEX idx_user_created_at_transaction_id,
+ADD INDEX idx_user_created_at (user_id, created_at, id);
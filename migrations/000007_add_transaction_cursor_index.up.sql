@@ -0,0 +1,4 @@
+-- Replace history index so that cursor pagination on (created_at, transaction_id) can use it
+ALTER TABLE transactions
+DROP INDEX idx_user_created_at,
+ADD INDEX idx_user_created_at_transaction_id (user_id, created_at, transaction_id);
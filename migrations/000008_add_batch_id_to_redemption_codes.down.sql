@@ -0,0 +1,4 @@
+-- Remove batch_id column from redemption_codes table
+ALTER TABLE redemption_codes
+DROP INDEX idx_batch_id,
+DROP COLUMN batch_id;
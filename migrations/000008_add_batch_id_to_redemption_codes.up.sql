@@ -0,0 +1,4 @@
+-- Add batch_id column to redemption_codes table for bulk generated codes
+ALTER TABLE redemption_codes
+ADD COLUMN batch_id VARCHAR(255) NULL COMMENT '一括生成したコードのバッチID' AFTER metadata,
+ADD INDEX idx_batch_id (batch_id);
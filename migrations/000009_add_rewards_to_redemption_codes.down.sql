@@ -0,0 +1,3 @@
+-- Remove rewards column from redemption_codes table
+ALTER TABLE redemption_codes
+DROP COLUMN rewards;
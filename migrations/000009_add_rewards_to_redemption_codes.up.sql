@@ -0,0 +1,3 @@
+-- Add rewards column to redemption_codes table for multi-currency reward bundles
+ALTER TABLE redemption_codes
+ADD COLUMN rewards JSON NULL COMMENT '複数の報酬のJSON配列（NULLの場合はcurrency_typeとamountの1件のみ）' AFTER amount;
@@ -0,0 +1,6 @@
+-- Remove campaign and eligibility rules from redemption_codes table
+ALTER TABLE redemption_codes
+DROP INDEX idx_campaign_id,
+DROP COLUMN eligibility,
+DROP COLUMN campaign_exclusive,
+DROP COLUMN campaign_id;
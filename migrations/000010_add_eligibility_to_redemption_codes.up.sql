@@ -0,0 +1,6 @@
+-- Add campaign and eligibility rules to redemption_codes table
+ALTER TABLE redemption_codes
+ADD COLUMN campaign_id VARCHAR(255) NULL COMMENT 'キャンペーンID（キャンペーン単位の引き換え条件に使用）' AFTER batch_id,
+ADD COLUMN campaign_exclusive BOOLEAN NOT NULL DEFAULT FALSE COMMENT '同じキャンペーンの排他コードとは併用できない' AFTER campaign_id,
+ADD COLUMN eligibility JSON NULL COMMENT '引き換え条件（対象ユーザー、新規ユーザー限定、キャンペーン内の上限）' AFTER campaign_exclusive,
+ADD INDEX idx_campaign_id (campaign_id);
@@ -0,0 +1,2 @@
+-- Drop redemption_code_audit_logs table
+DROP TABLE IF EXISTS redemption_code_audit_logs;
@@ -0,0 +1,12 @@
+-- Create redemption_code_audit_logs table for admin changes to redemption codes
+CREATE TABLE redemption_code_audit_logs (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    audit_id VARCHAR(255) UNIQUE NOT NULL,
+    code VARCHAR(255) NOT NULL COMMENT 'コード削除後も履歴を残すため外部キーは設定しない',
+    action VARCHAR(32) NOT NULL COMMENT '操作種別（update）',
+    changes JSON NOT NULL COMMENT '変更されたフィールドと変更前後の値',
+    reason TEXT NULL COMMENT '変更理由',
+    requester VARCHAR(255) NOT NULL COMMENT '変更を行ったオペレーターやツール名',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_code_created_at (code, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+-- Drop redemption history indexes
+ALTER TABLE code_redemptions
+DROP INDEX idx_code_redeemed_at,
+DROP INDEX idx_user_redeemed_at;
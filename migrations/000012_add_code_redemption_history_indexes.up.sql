@@ -0,0 +1,4 @@
+-- Add indexes for listing redemption history per code and per user, newest first
+ALTER TABLE code_redemptions
+ADD INDEX idx_code_redeemed_at (code, redeemed_at, id),
+ADD INDEX idx_user_redeemed_at (user_id, redeemed_at, id);
@@ -0,0 +1,14 @@
+-- Create refresh_tokens table for rotating refresh tokens
+CREATE TABLE refresh_tokens (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    token_id VARCHAR(255) UNIQUE NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    family_id VARCHAR(255) NOT NULL COMMENT 'ローテーションで引き継ぐ系列ID（最初に発行したトークンのID）',
+    token_hash CHAR(64) UNIQUE NOT NULL COMMENT 'トークンのSHA-256ハッシュ（平文は保存しない）',
+    expires_at TIMESTAMP NOT NULL COMMENT '有効期限',
+    revoked_at TIMESTAMP NULL COMMENT '失効日時（ローテーション済みを含む）',
+    replaced_by VARCHAR(255) NULL COMMENT 'ローテーション後のトークンID',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    INDEX idx_family_id (family_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+-- Drop admin_api_keys table
+DROP TABLE IF EXISTS admin_api_keys;
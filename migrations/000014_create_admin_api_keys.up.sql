@@ -0,0 +1,9 @@
+-- Create admin_api_keys table for named, scoped admin API keys
+CREATE TABLE admin_api_keys (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    name VARCHAR(100) UNIQUE NOT NULL COMMENT 'キーの名前（トランザクションのリクエスト元として記録される。失効後も再利用しない）',
+    key_hash CHAR(64) UNIQUE NOT NULL COMMENT 'キーのSHA-256ハッシュ（平文は保存しない）',
+    scopes VARCHAR(1024) NOT NULL DEFAULT '' COMMENT '許可するスコープ（カンマ区切り）',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP NULL COMMENT '失効日時'
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,17 @@
+-- Create idempotency_keys table to reserve each idempotency key once per user
+-- 優先順位制御の消費は同じキーで_free/_paidの2行を記録するため、transactionsではなく専用テーブルで一意にする
+CREATE TABLE idempotency_keys (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    user_id VARCHAR(255) NOT NULL,
+    idempotency_key VARCHAR(255) NOT NULL COMMENT 'クライアント指定の冪等性キー',
+    request_hash CHAR(64) NOT NULL COMMENT 'リクエスト内容のSHA-256ハッシュ',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_user_idempotency_key (user_id, idempotency_key)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- 記録済みの冪等性キーを移行
+INSERT IGNORE INTO idempotency_keys (user_id, idempotency_key, request_hash, created_at)
+SELECT user_id, idempotency_key, MIN(request_hash), MIN(created_at)
+FROM transactions
+WHERE idempotency_key IS NOT NULL AND request_hash IS NOT NULL
+GROUP BY user_id, idempotency_key;
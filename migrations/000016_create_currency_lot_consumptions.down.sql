@@ -0,0 +1,2 @@
+-- Drop currency_lot_consumptions table
+DROP TABLE IF EXISTS currency_lot_consumptions;
@@ -0,0 +1,11 @@
+-- Create currency_lot_consumptions table to record which lots each debit consumed
+-- 返金時に消費元のロットと同じ有効期限で残高を戻すために使う
+CREATE TABLE currency_lot_consumptions (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    transaction_id VARCHAR(255) NOT NULL COMMENT '消費したトランザクションID',
+    lot_id VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL COMMENT 'ロットから消費した数量',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (lot_id) REFERENCES currency_lots(lot_id) ON DELETE CASCADE,
+    INDEX idx_transaction_id (transaction_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;